@@ -7,7 +7,10 @@ require (
 	github.com/reeflective/readline v1.1.4
 	github.com/spf13/cobra v1.8.1
 	github.com/zalando/go-keyring v0.2.6
+	golang.org/x/crypto v0.48.0
+	golang.org/x/image v0.25.0
 	golang.org/x/term v0.40.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
@@ -21,7 +24,7 @@ require (
 	github.com/segmentio/encoding v0.5.3 // indirect
 	github.com/spf13/pflag v1.0.5 // indirect
 	github.com/yosida95/uritemplate/v3 v3.0.2 // indirect
-	golang.org/x/crypto v0.48.0 // indirect
 	golang.org/x/oauth2 v0.30.0 // indirect
 	golang.org/x/sys v0.41.0 // indirect
+	golang.org/x/text v0.34.0 // indirect
 )
@@ -0,0 +1,163 @@
+package asc
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+// AppInfo holds the app-level store information that is not tied to a version.
+// An app has a live app info and, while an update is prepared, an editable one.
+type AppInfo struct {
+	ID    string
+	State string
+}
+
+type appInfoAttributes struct {
+	State string `json:"state,omitempty"`
+}
+
+// ListAppInfos returns the app infos of an app.
+func (c *Client) ListAppInfos(ctx context.Context, appID string) ([]AppInfo, error) {
+	rs, err := list[appInfoAttributes](ctx, c, "/v1/apps/"+url.PathEscape(appID)+"/appInfos", nil, 0)
+	if err != nil {
+		return nil, err
+	}
+	infos := make([]AppInfo, 0, len(rs))
+	for _, r := range rs {
+		infos = append(infos, AppInfo{ID: r.ID, State: r.Attributes.State})
+	}
+	return infos, nil
+}
+
+// EditableAppInfo returns the app info that changes apply to: the one not yet
+// live, or the only one.
+func EditableAppInfo(infos []AppInfo) (AppInfo, bool) {
+	for _, info := range infos {
+		if info.State != "READY_FOR_DISTRIBUTION" && info.State != "READY_FOR_SALE" {
+			return info, true
+		}
+	}
+	if len(infos) > 0 {
+		return infos[0], true
+	}
+	return AppInfo{}, false
+}
+
+// AppInfoCategories returns the primary and secondary category IDs of an app
+// info, e.g. "PRODUCTIVITY". Unset categories are empty.
+func (c *Client) AppInfoCategories(ctx context.Context, appInfoID string) (string, string, error) {
+	base := "/v1/appInfos/" + url.PathEscape(appInfoID)
+	primary, err := get[struct{}](ctx, c, base+"/primaryCategory", nil)
+	if err != nil {
+		return "", "", err
+	}
+	secondary, err := get[struct{}](ctx, c, base+"/secondaryCategory", nil)
+	if err != nil {
+		return "", "", err
+	}
+	return primary.ID, secondary.ID, nil
+}
+
+// SetAppInfoCategories sets the primary and secondary categories of an app
+// info. An empty secondary category clears it.
+func (c *Client) SetAppInfoCategories(ctx context.Context, appInfoID, primary, secondary string) error {
+	rels := map[string]relationship{
+		"primaryCategory":   relate("appCategories", primary),
+		"secondaryCategory": {},
+	}
+	if secondary != "" {
+		rels["secondaryCategory"] = relate("appCategories", secondary)
+	}
+	_, err := update(ctx, c, "/v1/appInfos/"+url.PathEscape(appInfoID), resource[struct{}]{
+		Type:          "appInfos",
+		ID:            appInfoID,
+		Relationships: rels,
+	})
+	return err
+}
+
+// AppInfoLocalization is the per-locale app-level store information.
+type AppInfoLocalization struct {
+	ID     string
+	Locale string
+	AppInfoFields
+}
+
+// AppInfoFields are the editable text fields of an app info localization.
+// Empty fields are left unchanged on update.
+type AppInfoFields struct {
+	Name             string `json:"name,omitempty"`
+	Subtitle         string `json:"subtitle,omitempty"`
+	PrivacyPolicyURL string `json:"privacyPolicyUrl,omitempty"`
+}
+
+type appInfoLocalizationAttributes struct {
+	Locale string `json:"locale,omitempty"`
+	AppInfoFields
+}
+
+func appInfoLocalizationFrom(r resource[appInfoLocalizationAttributes]) AppInfoLocalization {
+	return AppInfoLocalization{ID: r.ID, Locale: r.Attributes.Locale, AppInfoFields: r.Attributes.AppInfoFields}
+}
+
+// ListAppInfoLocalizations returns every localization of an app info.
+func (c *Client) ListAppInfoLocalizations(ctx context.Context, appInfoID string) ([]AppInfoLocalization, error) {
+	rs, err := list[appInfoLocalizationAttributes](ctx, c, "/v1/appInfos/"+url.PathEscape(appInfoID)+"/appInfoLocalizations", nil, 0)
+	if err != nil {
+		return nil, err
+	}
+	locs := make([]AppInfoLocalization, 0, len(rs))
+	for _, r := range rs {
+		locs = append(locs, appInfoLocalizationFrom(r))
+	}
+	return locs, nil
+}
+
+// CreateAppInfoLocalization adds a locale to an app info.
+func (c *Client) CreateAppInfoLocalization(ctx context.Context, appInfoID, locale string, fields AppInfoFields) (AppInfoLocalization, error) {
+	r, err := create(ctx, c, "/v1/appInfoLocalizations", resource[appInfoLocalizationAttributes]{
+		Type:          "appInfoLocalizations",
+		Attributes:    appInfoLocalizationAttributes{Locale: locale, AppInfoFields: fields},
+		Relationships: map[string]relationship{"appInfo": relate("appInfos", appInfoID)},
+	})
+	if err != nil {
+		return AppInfoLocalization{}, err
+	}
+	return appInfoLocalizationFrom(r), nil
+}
+
+// UpdateAppInfoLocalization sets the non-empty fields of an app info localization.
+func (c *Client) UpdateAppInfoLocalization(ctx context.Context, id string, fields AppInfoFields) (AppInfoLocalization, error) {
+	r, err := update(ctx, c, "/v1/appInfoLocalizations/"+url.PathEscape(id), resource[appInfoLocalizationAttributes]{
+		Type:       "appInfoLocalizations",
+		ID:         id,
+		Attributes: appInfoLocalizationAttributes{AppInfoFields: fields},
+	})
+	if err != nil {
+		return AppInfoLocalization{}, err
+	}
+	return appInfoLocalizationFrom(r), nil
+}
+
+// AgeRatingDeclaration is an app info's answers to the age rating questionnaire,
+// keyed by API attribute, e.g. "violenceCartoonOrFantasy": "NONE" or "gambling": false.
+type AgeRatingDeclaration struct {
+	ID         string
+	Attributes map[string]any
+}
+
+// GetAgeRatingDeclaration returns the age rating declaration of an app info.
+func (c *Client) GetAgeRatingDeclaration(ctx context.Context, appInfoID string) (AgeRatingDeclaration, error) {
+	r, err := get[map[string]any](ctx, c, "/v1/appInfos/"+url.PathEscape(appInfoID)+"/ageRatingDeclaration", nil)
+	if err != nil {
+		return AgeRatingDeclaration{}, err
+	}
+	return AgeRatingDeclaration{ID: r.ID, Attributes: r.Attributes}, nil
+}
+
+// UpdateAgeRatingDeclaration sets the given questionnaire answers.
+func (c *Client) UpdateAgeRatingDeclaration(ctx context.Context, id string, attrs map[string]any) error {
+	return c.do(ctx, http.MethodPatch, "/v1/ageRatingDeclarations/"+url.PathEscape(id), nil,
+		requestDocument[resource[map[string]any]]{Data: resource[map[string]any]{Type: "ageRatingDeclarations", ID: id, Attributes: attrs}}, nil)
+}
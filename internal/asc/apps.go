@@ -0,0 +1,143 @@
+package asc
+
+import (
+	"context"
+	"errors"
+	"net/url"
+)
+
+// App is an app record in App Store Connect.
+type App struct {
+	ID            string
+	Name          string
+	BundleID      string
+	SKU           string
+	PrimaryLocale string
+}
+
+type appAttributes struct {
+	Name          string `json:"name,omitempty"`
+	BundleID      string `json:"bundleId,omitempty"`
+	SKU           string `json:"sku,omitempty"`
+	PrimaryLocale string `json:"primaryLocale,omitempty"`
+}
+
+func appFrom(r resource[appAttributes]) App {
+	return App{
+		ID:            r.ID,
+		Name:          r.Attributes.Name,
+		BundleID:      r.Attributes.BundleID,
+		SKU:           r.Attributes.SKU,
+		PrimaryLocale: r.Attributes.PrimaryLocale,
+	}
+}
+
+// ListApps returns every app in the account.
+func (c *Client) ListApps(ctx context.Context) ([]App, error) {
+	rs, err := list[appAttributes](ctx, c, "/v1/apps", url.Values{"limit": {"200"}}, 0)
+	if err != nil {
+		return nil, err
+	}
+	apps := make([]App, 0, len(rs))
+	for _, r := range rs {
+		apps = append(apps, appFrom(r))
+	}
+	return apps, nil
+}
+
+// GetApp returns the app with the given ID.
+func (c *Client) GetApp(ctx context.Context, id string) (App, error) {
+	r, err := get[appAttributes](ctx, c, "/v1/apps/"+url.PathEscape(id), nil)
+	if err != nil {
+		return App{}, err
+	}
+	return appFrom(r), nil
+}
+
+// FindAppByBundleID returns the app with the given bundle identifier, or
+// false when the account has none.
+func (c *Client) FindAppByBundleID(ctx context.Context, bundleID string) (App, bool, error) {
+	rs, err := list[appAttributes](ctx, c, "/v1/apps", url.Values{"filter[bundleId]": {bundleID}}, 0)
+	if err != nil {
+		return App{}, false, err
+	}
+	for _, r := range rs {
+		if r.Attributes.BundleID == bundleID {
+			return appFrom(r), true, nil
+		}
+	}
+	return App{}, false, nil
+}
+
+// BundleID is a registered bundle identifier.
+type BundleID struct {
+	ID         string
+	Identifier string
+	Name       string
+	Platform   string
+}
+
+type bundleIDAttributes struct {
+	Identifier string `json:"identifier,omitempty"`
+	Name       string `json:"name,omitempty"`
+	Platform   string `json:"platform,omitempty"`
+}
+
+func bundleIDFrom(r resource[bundleIDAttributes]) BundleID {
+	return BundleID{ID: r.ID, Identifier: r.Attributes.Identifier, Name: r.Attributes.Name, Platform: r.Attributes.Platform}
+}
+
+// FindBundleID returns the registered bundle ID resource for identifier, or
+// false when it is not registered.
+func (c *Client) FindBundleID(ctx context.Context, identifier string) (BundleID, bool, error) {
+	rs, err := list[bundleIDAttributes](ctx, c, "/v1/bundleIds", url.Values{"filter[identifier]": {identifier}}, 0)
+	if err != nil {
+		return BundleID{}, false, err
+	}
+	// The identifier filter matches prefixes, so compare exactly.
+	for _, r := range rs {
+		if r.Attributes.Identifier == identifier {
+			return bundleIDFrom(r), true, nil
+		}
+	}
+	return BundleID{}, false, nil
+}
+
+// CreateBundleID registers identifier for platform ("IOS", "MAC_OS" or "UNIVERSAL").
+func (c *Client) CreateBundleID(ctx context.Context, identifier, name, platform string) (BundleID, error) {
+	r, err := create(ctx, c, "/v1/bundleIds", resource[bundleIDAttributes]{
+		Type:       "bundleIds",
+		Attributes: bundleIDAttributes{Identifier: identifier, Name: name, Platform: platform},
+	})
+	if err != nil {
+		return BundleID{}, err
+	}
+	return bundleIDFrom(r), nil
+}
+
+// agreementsErrorCode is the 403 code App Store Connect returns for every
+// request while a required agreement is missing or expired.
+const agreementsErrorCode = "FORBIDDEN.REQUIRED_AGREEMENTS_MISSING_OR_EXPIRED"
+
+// CheckAgreements reports whether the account's required agreements are in
+// effect. The API has no agreements resource, so this makes a minimal request
+// and inspects the error it is refused with.
+func (c *Client) CheckAgreements(ctx context.Context) (bool, []Agreement, error) {
+	_, err := list[appAttributes](ctx, c, "/v1/apps", url.Values{"limit": {"1"}}, 1)
+	if err == nil {
+		return true, []Agreement{}, nil
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.HasCode(agreementsErrorCode) {
+		var agreements []Agreement
+		for _, d := range apiErr.Errors {
+			name := d.Detail
+			if name == "" {
+				name = d.Title
+			}
+			agreements = append(agreements, Agreement{Status: "MISSING_OR_EXPIRED", Type: name})
+		}
+		return false, agreements, nil
+	}
+	return false, nil, err
+}
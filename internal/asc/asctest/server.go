@@ -0,0 +1,620 @@
+// Package asctest provides an in-memory fake of the App Store Connect API for tests.
+package asctest
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/moasq/nanowave/internal/asc"
+)
+
+// maxPageSize caps collection pages; the real API allows 200.
+const maxPageSize = 50
+
+// Resource is a stored JSON:API resource.
+type Resource struct {
+	Type          string
+	ID            string
+	Attributes    map[string]any
+	Relationships map[string]Ref
+}
+
+// Ref identifies a related resource.
+type Ref struct {
+	Type string
+	ID   string
+}
+
+// Server is a fake App Store Connect API backed by an in-memory store. It
+// verifies each request's ES256 token against the key from Credential.
+type Server struct {
+	*httptest.Server
+
+	key  *ecdsa.PrivateKey
+	cred asc.Credential
+
+	mu                sync.Mutex
+	nextID            int
+	resources         map[string][]*Resource
+	uploads           map[string][]byte
+	links             map[[2]Ref]bool // to-many relationships, stored in both directions
+	requests          []string
+	agreementsExpired bool
+}
+
+// NewServer starts a fake API that is closed when the test ends.
+func NewServer(t testing.TB) *Server {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &Server{
+		key: key,
+		cred: asc.Credential{
+			KeyID:      "TESTKEY123",
+			IssuerID:   "00000000-0000-0000-0000-000000000000",
+			PrivateKey: string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})),
+		},
+		resources: map[string][]*Resource{},
+		uploads:   map[string][]byte{},
+		links:     map[[2]Ref]bool{},
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serve))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// Credential returns the API key requests must be signed with.
+func (s *Server) Credential() *asc.Credential {
+	cred := s.cred
+	return &cred
+}
+
+// APIClient returns a client for the fake signed with Credential.
+func (s *Server) APIClient(t testing.TB) *asc.Client {
+	t.Helper()
+	c, err := asc.NewClient(s.Credential(), asc.WithBaseURL(s.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+// Add stores a resource, assigning an ID when it has none, and returns the ID.
+func (s *Server) Add(r Resource) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.add(r).ID
+}
+
+func (s *Server) add(r Resource) *Resource {
+	if r.ID == "" {
+		s.nextID++
+		r.ID = strconv.Itoa(s.nextID)
+	}
+	if r.Attributes == nil {
+		r.Attributes = map[string]any{}
+	}
+	if r.Relationships == nil {
+		r.Relationships = map[string]Ref{}
+	}
+	s.resources[r.Type] = append(s.resources[r.Type], &r)
+	return &r
+}
+
+// Find returns the stored resource of the given type and ID.
+func (s *Server) Find(resourceType, id string) (Resource, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if r := s.find(resourceType, id); r != nil {
+		return *r, true
+	}
+	return Resource{}, false
+}
+
+func (s *Server) find(resourceType, id string) *Resource {
+	for _, r := range s.resources[resourceType] {
+		if r.ID == id {
+			return r
+		}
+	}
+	return nil
+}
+
+// All returns every stored resource of a type in insertion order.
+func (s *Server) All(resourceType string) []Resource {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var out []Resource
+	for _, r := range s.resources[resourceType] {
+		out = append(out, *r)
+	}
+	return out
+}
+
+// Link relates two resources through a to-many relationship, such as a beta
+// group and its testers. Links go both ways.
+func (s *Server) Link(a, b Ref) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.link(a, b)
+}
+
+func (s *Server) link(a, b Ref) {
+	s.links[[2]Ref{a, b}] = true
+	s.links[[2]Ref{b, a}] = true
+}
+
+func (s *Server) unlink(a, b Ref) {
+	delete(s.links, [2]Ref{a, b})
+	delete(s.links, [2]Ref{b, a})
+}
+
+// Linked returns the resources of a type linked to ref through a to-many relationship.
+func (s *Server) Linked(ref Ref, resourceType string) []Resource {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var out []Resource
+	for _, res := range s.resources[resourceType] {
+		if s.links[[2]Ref{ref, {Type: res.Type, ID: res.ID}}] {
+			out = append(out, *res)
+		}
+	}
+	return out
+}
+
+// Uploaded returns the bytes uploaded for an asset reservation.
+func (s *Server) Uploaded(id string) []byte {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.uploads[id]
+}
+
+// Requests returns the "METHOD /path" of every API request received.
+func (s *Server) Requests() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.requests...)
+}
+
+// SetAgreementsExpired makes every API request fail the way ASC does while a
+// required agreement is missing or expired.
+func (s *Server) SetAgreementsExpired(expired bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.agreementsExpired = expired
+}
+
+func (s *Server) serve(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Pre-signed asset uploads are authorized by their URL, not the token.
+	if id, ok := strings.CutPrefix(r.URL.Path, "/upload/"); ok && r.Method == http.MethodPut {
+		data, _ := io.ReadAll(r.Body)
+		s.uploads[id] = append(s.uploads[id], data...)
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	s.requests = append(s.requests, r.Method+" "+r.URL.Path)
+	if !s.authorized(r.Header.Get("Authorization")) {
+		writeError(w, http.StatusUnauthorized, "NOT_AUTHORIZED", "Authentication credentials are missing or invalid.")
+		return
+	}
+	if s.agreementsExpired {
+		writeError(w, http.StatusForbidden, "FORBIDDEN.REQUIRED_AGREEMENTS_MISSING_OR_EXPIRED",
+			"A required agreement is missing or has expired.")
+		return
+	}
+
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/v1/"), "/")
+	switch {
+	case r.Method == http.MethodGet && len(parts) == 1:
+		s.list(w, r, s.resources[parts[0]])
+	case r.Method == http.MethodGet && len(parts) == 2:
+		if res := s.find(parts[0], parts[1]); res != nil {
+			writeJSON(w, http.StatusOK, map[string]any{"data": encode(res)})
+		} else {
+			writeError(w, http.StatusNotFound, "NOT_FOUND", "The specified resource does not exist.")
+		}
+	case r.Method == http.MethodGet && len(parts) == 3 && !strings.HasSuffix(parts[2], "s"):
+		// A singular name is a to-one relationship; collections are plural.
+		s.related(w, parts[0], parts[1], parts[2])
+	case r.Method == http.MethodGet && len(parts) == 3:
+		parent := Ref{Type: parts[0], ID: parts[1]}
+		var children []*Resource
+		for _, res := range s.resources[parts[2]] {
+			if s.links[[2]Ref{parent, {Type: res.Type, ID: res.ID}}] {
+				children = append(children, res)
+				continue
+			}
+			for _, ref := range res.Relationships {
+				if ref == parent {
+					children = append(children, res)
+					break
+				}
+			}
+		}
+		s.list(w, r, children)
+	case r.Method == http.MethodPost && len(parts) == 1:
+		s.create(w, r, parts[0])
+	case r.Method == http.MethodPatch && len(parts) == 2:
+		s.update(w, r, parts[0], parts[1])
+	case r.Method == http.MethodPatch && len(parts) == 4 && parts[2] == "relationships":
+		s.relate(w, r, parts[0], parts[1], parts[3])
+	case (r.Method == http.MethodPost || r.Method == http.MethodDelete) && len(parts) == 4 && parts[2] == "relationships":
+		s.relateMany(w, r, parts[0], parts[1])
+	case r.Method == http.MethodDelete && len(parts) == 2:
+		s.remove(w, parts[0], parts[1])
+	default:
+		writeError(w, http.StatusNotFound, "NOT_FOUND", "The path provided does not match a defined resource type.")
+	}
+}
+
+func (s *Server) related(w http.ResponseWriter, resourceType, id, name string) {
+	parent := s.find(resourceType, id)
+	if parent == nil {
+		writeError(w, http.StatusNotFound, "NOT_FOUND", "The specified resource does not exist.")
+		return
+	}
+	ref, ok := parent.Relationships[name]
+	if !ok {
+		writeJSON(w, http.StatusOK, map[string]any{"data": nil})
+		return
+	}
+	res := s.find(ref.Type, ref.ID)
+	if res == nil {
+		// Reference data such as categories need not be seeded.
+		res = &Resource{Type: ref.Type, ID: ref.ID, Attributes: map[string]any{}}
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"data": encode(res)})
+}
+
+// authorized verifies an ES256 bearer token signed with the server's key.
+func (s *Server) authorized(header string) bool {
+	token, ok := strings.CutPrefix(header, "Bearer ")
+	if !ok {
+		return false
+	}
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return false
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil || len(sig) != 64 {
+		return false
+	}
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if !ecdsa.Verify(&s.key.PublicKey, digest[:], new(big.Int).SetBytes(sig[:32]), new(big.Int).SetBytes(sig[32:])) {
+		return false
+	}
+	var jwtHeader, claims map[string]any
+	if decodeSegment(parts[0], &jwtHeader) != nil || decodeSegment(parts[1], &claims) != nil {
+		return false
+	}
+	return jwtHeader["alg"] == "ES256" && jwtHeader["kid"] == s.cred.KeyID &&
+		claims["iss"] == s.cred.IssuerID && claims["aud"] == "appstoreconnect-v1"
+}
+
+func decodeSegment(seg string, out any) error {
+	data, err := base64.RawURLEncoding.DecodeString(seg)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, out)
+}
+
+// list writes a filtered, sorted page of resources. Filters match a
+// relationship ID or an attribute value against a comma-separated list.
+func (s *Server) list(w http.ResponseWriter, r *http.Request, all []*Resource) {
+	query := r.URL.Query()
+	var matched []*Resource
+	for _, res := range all {
+		if s.matches(res, query) {
+			matched = append(matched, res)
+		}
+	}
+	if field := query.Get("sort"); field != "" {
+		desc := strings.HasPrefix(field, "-")
+		field = strings.TrimPrefix(field, "-")
+		sort.SliceStable(matched, func(i, j int) bool {
+			a, b := fmt.Sprint(matched[i].Attributes[field]), fmt.Sprint(matched[j].Attributes[field])
+			if desc {
+				return a > b
+			}
+			return a < b
+		})
+	}
+
+	// Pages are smaller than the real API's so tests cross page boundaries.
+	limit := maxPageSize
+	if n, err := strconv.Atoi(query.Get("limit")); err == nil && n > 0 {
+		limit = min(n, maxPageSize)
+	}
+	offset, _ := strconv.Atoi(query.Get("cursor"))
+	if offset > len(matched) {
+		offset = len(matched)
+	}
+	end := min(offset+limit, len(matched))
+
+	data := make([]any, 0, end-offset)
+	for _, res := range matched[offset:end] {
+		data = append(data, encode(res))
+	}
+	links := map[string]string{"self": s.URL + r.URL.RequestURI()}
+	if end < len(matched) {
+		next := url.Values{}
+		for k, v := range query {
+			next[k] = v
+		}
+		next.Set("cursor", strconv.Itoa(end))
+		links["next"] = s.URL + r.URL.Path + "?" + next.Encode()
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"data": data, "links": links})
+}
+
+// matches reports whether a resource passes the query's filters. A dotted
+// filter such as filter[preReleaseVersion.version] reads the attribute of the
+// related resource.
+func (s *Server) matches(res *Resource, query url.Values) bool {
+	for key, values := range query {
+		name, ok := strings.CutPrefix(key, "filter[")
+		if !ok {
+			continue
+		}
+		name = strings.TrimSuffix(name, "]")
+		target := res
+		if rel, attr, ok := strings.Cut(name, "."); ok {
+			ref, linked := res.Relationships[rel]
+			if target = s.find(ref.Type, ref.ID); !linked || target == nil {
+				return false
+			}
+			name = attr
+		}
+		got := fmt.Sprint(target.Attributes[name])
+		if ref, ok := target.Relationships[name]; ok {
+			got = ref.ID
+		}
+		found := false
+		for _, want := range strings.Split(values[0], ",") {
+			if got == want {
+				found = true
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+type document struct {
+	Data struct {
+		Type          string         `json:"type"`
+		ID            string         `json:"id"`
+		Attributes    map[string]any `json:"attributes"`
+		Relationships map[string]struct {
+			Data json.RawMessage `json:"data"`
+		} `json:"relationships"`
+	} `json:"data"`
+}
+
+// relationshipData decodes a relationship's data: nil, one reference or, for
+// to-many relationships, a list.
+func relationshipData(raw json.RawMessage) (one *Ref, many []Ref, err error) {
+	raw = json.RawMessage(strings.TrimSpace(string(raw)))
+	switch {
+	case len(raw) == 0 || string(raw) == "null":
+		return nil, nil, nil
+	case raw[0] == '[':
+		err = json.Unmarshal(raw, &many)
+		return nil, many, err
+	default:
+		one = &Ref{}
+		err = json.Unmarshal(raw, one)
+		return one, nil, err
+	}
+}
+
+func (s *Server) create(w http.ResponseWriter, r *http.Request, resourceType string) {
+	var doc document
+	if err := json.NewDecoder(r.Body).Decode(&doc); err != nil || doc.Data.Type != resourceType {
+		writeError(w, http.StatusBadRequest, "PARAMETER_ERROR.INVALID", "The request entity is invalid.")
+		return
+	}
+	if resourceType == "bundleIds" {
+		for _, existing := range s.resources["bundleIds"] {
+			if existing.Attributes["identifier"] == doc.Data.Attributes["identifier"] {
+				writeError(w, http.StatusConflict, "ENTITY_ERROR.ATTRIBUTE.INVALID.DUPLICATE",
+					"An App ID with this Identifier already exists.")
+				return
+			}
+		}
+	}
+	res := Resource{Type: resourceType, Attributes: doc.Data.Attributes, Relationships: map[string]Ref{}}
+	var linked []Ref
+	for name, rel := range doc.Data.Relationships {
+		one, many, err := relationshipData(rel.Data)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "PARAMETER_ERROR.INVALID", "The request entity is invalid.")
+			return
+		}
+		if one != nil {
+			res.Relationships[name] = *one
+		}
+		linked = append(linked, many...)
+	}
+	created := s.add(res)
+	for _, ref := range linked {
+		s.link(Ref{Type: created.Type, ID: created.ID}, ref)
+	}
+	if resourceType == "appScreenshots" {
+		size, _ := created.Attributes["fileSize"].(float64)
+		created.Attributes["uploadOperations"] = []map[string]any{{
+			"method": http.MethodPut,
+			"url":    s.URL + "/upload/" + created.ID,
+			"offset": 0,
+			"length": int(size),
+			"requestHeaders": []map[string]string{
+				{"name": "Content-Type", "value": "image/png"},
+			},
+		}}
+	}
+	writeJSON(w, http.StatusCreated, map[string]any{"data": encode(created)})
+}
+
+func (s *Server) update(w http.ResponseWriter, r *http.Request, resourceType, id string) {
+	res := s.find(resourceType, id)
+	if res == nil {
+		writeError(w, http.StatusNotFound, "NOT_FOUND", "The specified resource does not exist.")
+		return
+	}
+	var doc document
+	if err := json.NewDecoder(r.Body).Decode(&doc); err != nil || doc.Data.ID != id {
+		writeError(w, http.StatusBadRequest, "PARAMETER_ERROR.INVALID", "The request entity is invalid.")
+		return
+	}
+	for k, v := range doc.Data.Attributes {
+		res.Attributes[k] = v
+	}
+	for name, rel := range doc.Data.Relationships {
+		if one, _, _ := relationshipData(rel.Data); one != nil {
+			res.Relationships[name] = *one
+		} else {
+			delete(res.Relationships, name)
+		}
+	}
+	if resourceType == "reviewSubmissions" && res.Attributes["submitted"] == true {
+		res.Attributes["state"] = "WAITING_FOR_REVIEW"
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"data": encode(res)})
+}
+
+func (s *Server) relate(w http.ResponseWriter, r *http.Request, resourceType, id, name string) {
+	res := s.find(resourceType, id)
+	if res == nil {
+		writeError(w, http.StatusNotFound, "NOT_FOUND", "The specified resource does not exist.")
+		return
+	}
+	var body struct {
+		Data *Ref `json:"data"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, "PARAMETER_ERROR.INVALID", "The request entity is invalid.")
+		return
+	}
+	if body.Data == nil {
+		delete(res.Relationships, name)
+	} else {
+		res.Relationships[name] = *body.Data
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// relateMany adds (POST) or removes (DELETE) members of a to-many
+// relationship. Every member must exist.
+func (s *Server) relateMany(w http.ResponseWriter, r *http.Request, resourceType, id string) {
+	if s.find(resourceType, id) == nil {
+		writeError(w, http.StatusNotFound, "NOT_FOUND", "The specified resource does not exist.")
+		return
+	}
+	var body struct {
+		Data []Ref `json:"data"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, "PARAMETER_ERROR.INVALID", "The request entity is invalid.")
+		return
+	}
+	if s.find(resourceType, id) == nil {
+		writeError(w, http.StatusNotFound, "NOT_FOUND", "The specified resource does not exist.")
+		return
+	}
+	for _, ref := range body.Data {
+		if s.find(ref.Type, ref.ID) == nil {
+			writeError(w, http.StatusNotFound, "NOT_FOUND", fmt.Sprintf("There is no resource of type '%s' with id '%s'.", ref.Type, ref.ID))
+			return
+		}
+	}
+	self := Ref{Type: resourceType, ID: id}
+	for _, ref := range body.Data {
+		if r.Method == http.MethodPost {
+			s.link(self, ref)
+		} else {
+			s.unlink(self, ref)
+		}
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) remove(w http.ResponseWriter, resourceType, id string) {
+	kept := s.resources[resourceType][:0]
+	found := false
+	for _, res := range s.resources[resourceType] {
+		if res.ID == id {
+			found = true
+			continue
+		}
+		kept = append(kept, res)
+	}
+	s.resources[resourceType] = kept
+	for pair := range s.links {
+		if pair[0] == (Ref{Type: resourceType, ID: id}) {
+			s.unlink(pair[0], pair[1])
+		}
+	}
+	if !found {
+		writeError(w, http.StatusNotFound, "NOT_FOUND", "The specified resource does not exist.")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func encode(res *Resource) map[string]any {
+	out := map[string]any{"type": res.Type, "id": res.ID, "attributes": res.Attributes}
+	if len(res.Relationships) > 0 {
+		rels := map[string]any{}
+		for name, ref := range res.Relationships {
+			rels[name] = map[string]any{"data": map[string]string{"type": ref.Type, "id": ref.ID}}
+		}
+		out["relationships"] = rels
+	}
+	return out
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, code, detail string) {
+	writeJSON(w, status, map[string]any{"errors": []map[string]string{{
+		"status": strconv.Itoa(status),
+		"code":   code,
+		"title":  http.StatusText(status),
+		"detail": detail,
+	}}})
+}
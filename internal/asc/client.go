@@ -0,0 +1,378 @@
+package asc
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// APIBaseURL is the App Store Connect REST API.
+const APIBaseURL = "https://api.appstoreconnect.apple.com"
+
+// tokenLifetime is how long a signed API token is valid; Apple allows at most 20 minutes.
+const tokenLifetime = 20 * time.Minute
+
+// Client is a typed App Store Connect API client authenticated with an API key.
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+	keyID      string
+	issuerID   string
+	key        *ecdsa.PrivateKey
+	logger     *log.Logger
+
+	mu          sync.Mutex
+	token       string
+	tokenExpiry time.Time
+}
+
+// ClientOption configures a Client.
+type ClientOption func(*Client)
+
+// WithBaseURL points the client at another API host, such as a local fake.
+func WithBaseURL(baseURL string) ClientOption {
+	return func(c *Client) { c.baseURL = strings.TrimSuffix(baseURL, "/") }
+}
+
+// WithHTTPClient sets the HTTP client requests are sent with.
+func WithHTTPClient(httpClient *http.Client) ClientOption {
+	return func(c *Client) { c.httpClient = httpClient }
+}
+
+// WithLogger logs every request's method, path and status to logger. Clients
+// log nothing by default.
+func WithLogger(logger *log.Logger) ClientOption {
+	return func(c *Client) { c.logger = logger }
+}
+
+// NewClient creates an API client signing requests with the credential's
+// P-256 private key.
+func NewClient(cred *Credential, opts ...ClientOption) (*Client, error) {
+	key, err := parsePrivateKey(cred.PrivateKey)
+	if err != nil {
+		return nil, err
+	}
+	c := &Client{
+		baseURL:    APIBaseURL,
+		httpClient: &http.Client{Timeout: 60 * time.Second},
+		keyID:      cred.KeyID,
+		issuerID:   cred.IssuerID,
+		key:        key,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c, nil
+}
+
+// parsePrivateKey decodes the PKCS#8 PEM of an App Store Connect .p8 key.
+func parsePrivateKey(pemData string) (*ecdsa.PrivateKey, error) {
+	block, _ := pem.Decode([]byte(pemData))
+	if block == nil {
+		return nil, fmt.Errorf("ASC private key is not PEM encoded")
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("parse ASC private key: %w", err)
+	}
+	key, ok := parsed.(*ecdsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("ASC private key is %T, want an ECDSA P-256 key", parsed)
+	}
+	return key, nil
+}
+
+// bearerToken returns a signed ES256 JWT, reusing it until shortly before it expires.
+func (c *Client) bearerToken() (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := time.Now()
+	if c.token != "" && now.Add(time.Minute).Before(c.tokenExpiry) {
+		return c.token, nil
+	}
+
+	expiry := now.Add(tokenLifetime)
+	header, _ := json.Marshal(map[string]string{"alg": "ES256", "kid": c.keyID, "typ": "JWT"})
+	claims, _ := json.Marshal(map[string]any{
+		"iss": c.issuerID,
+		"iat": now.Unix(),
+		"exp": expiry.Unix(),
+		"aud": "appstoreconnect-v1",
+	})
+	signingInput := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(claims)
+	digest := sha256.Sum256([]byte(signingInput))
+	r, s, err := ecdsa.Sign(rand.Reader, c.key, digest[:])
+	if err != nil {
+		return "", fmt.Errorf("sign ASC token: %w", err)
+	}
+	// JWS ES256 signatures are the fixed-width concatenation r || s.
+	sig := make([]byte, 64)
+	r.FillBytes(sig[:32])
+	s.FillBytes(sig[32:])
+
+	c.token = signingInput + "." + base64.RawURLEncoding.EncodeToString(sig)
+	c.tokenExpiry = expiry
+	return c.token, nil
+}
+
+// APIError is an error response from the App Store Connect API.
+type APIError struct {
+	StatusCode int
+	Errors     []APIErrorDetail
+}
+
+// APIErrorDetail is one entry of a JSON:API errors array.
+type APIErrorDetail struct {
+	Status string `json:"status"`
+	Code   string `json:"code"`
+	Title  string `json:"title"`
+	Detail string `json:"detail"`
+}
+
+func (e *APIError) Error() string {
+	if len(e.Errors) == 0 {
+		return fmt.Sprintf("App Store Connect API returned %d", e.StatusCode)
+	}
+	var parts []string
+	for _, d := range e.Errors {
+		text := d.Detail
+		if text == "" {
+			text = d.Title
+		}
+		parts = append(parts, fmt.Sprintf("%s: %s", d.Code, text))
+	}
+	return fmt.Sprintf("App Store Connect API returned %d: %s", e.StatusCode, strings.Join(parts, "; "))
+}
+
+// HasCode reports whether the error has an entry whose code is code or starts
+// with code followed by a dot, e.g. "FORBIDDEN" matches "FORBIDDEN.REQUIRED_AGREEMENTS_MISSING_OR_EXPIRED".
+func (e *APIError) HasCode(code string) bool {
+	for _, d := range e.Errors {
+		if d.Code == code || strings.HasPrefix(d.Code, code+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// IsNotFound reports whether err is a 404 from the API.
+func IsNotFound(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
+}
+
+// IsConflict reports whether err is a 409 from the API, e.g. a resource that already exists.
+func IsConflict(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusConflict
+}
+
+// resource is a JSON:API resource object with typed attributes.
+type resource[A any] struct {
+	Type          string                  `json:"type"`
+	ID            string                  `json:"id,omitempty"`
+	Attributes    A                       `json:"attributes,omitempty"`
+	Relationships map[string]relationship `json:"relationships,omitempty"`
+}
+
+// relationship is a to-one relationship, or a to-many one when Many is set.
+type relationship struct {
+	Data *linkage
+	Many []linkage
+}
+
+type linkage struct {
+	Type string `json:"type"`
+	ID   string `json:"id"`
+}
+
+func (r relationship) MarshalJSON() ([]byte, error) {
+	if r.Many != nil {
+		return json.Marshal(map[string][]linkage{"data": r.Many})
+	}
+	return json.Marshal(map[string]*linkage{"data": r.Data})
+}
+
+func (r *relationship) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Data json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	switch trimmed := bytes.TrimSpace(raw.Data); {
+	case len(trimmed) == 0 || string(trimmed) == "null":
+		return nil
+	case trimmed[0] == '[':
+		r.Many = []linkage{}
+		return json.Unmarshal(trimmed, &r.Many)
+	default:
+		r.Data = &linkage{}
+		return json.Unmarshal(trimmed, r.Data)
+	}
+}
+
+// relate returns a to-one relationship to the resource of the given type and ID.
+func relate(resourceType, id string) relationship {
+	return relationship{Data: &linkage{Type: resourceType, ID: id}}
+}
+
+// relateMany returns a to-many relationship to the resources of the given type and IDs.
+func relateMany(resourceType string, ids ...string) relationship {
+	r := relationship{Many: []linkage{}}
+	for _, id := range ids {
+		r.Many = append(r.Many, linkage{Type: resourceType, ID: id})
+	}
+	return r
+}
+
+// document is a JSON:API response document.
+type document[T any] struct {
+	Data  T `json:"data"`
+	Links struct {
+		Next string `json:"next"`
+	} `json:"links"`
+}
+
+// requestDocument is a JSON:API request document, which carries no links.
+type requestDocument[T any] struct {
+	Data T `json:"data"`
+}
+
+// get fetches one resource.
+func get[A any](ctx context.Context, c *Client, path string, query url.Values) (resource[A], error) {
+	var doc document[resource[A]]
+	err := c.do(ctx, http.MethodGet, path, query, nil, &doc)
+	return doc.Data, err
+}
+
+// list fetches a collection, following pagination links until limit resources
+// are read. A limit of 0 reads every page.
+func list[A any](ctx context.Context, c *Client, path string, query url.Values, limit int) ([]resource[A], error) {
+	var all []resource[A]
+	next := c.baseURL + path
+	if len(query) > 0 {
+		next += "?" + query.Encode()
+	}
+	for next != "" {
+		var doc document[[]resource[A]]
+		if err := c.doURL(ctx, http.MethodGet, next, nil, &doc); err != nil {
+			return all, err
+		}
+		all = append(all, doc.Data...)
+		if limit > 0 && len(all) >= limit {
+			return all[:limit], nil
+		}
+		next = doc.Links.Next
+		if next != "" && !c.isAPIURL(next) {
+			return all, fmt.Errorf("%s: next page link %q leaves the API host", path, next)
+		}
+	}
+	return all, nil
+}
+
+// isAPIURL reports whether u is on the client's API host, so a pagination
+// link can be followed without sending the token elsewhere.
+func (c *Client) isAPIURL(u string) bool {
+	target, err := url.Parse(u)
+	if err != nil {
+		return false
+	}
+	base, err := url.Parse(c.baseURL)
+	if err != nil {
+		return false
+	}
+	return target.Scheme == base.Scheme && target.Host == base.Host
+}
+
+// create posts a new resource and returns it as created.
+func create[A any](ctx context.Context, c *Client, path string, r resource[A]) (resource[A], error) {
+	var doc document[resource[A]]
+	err := c.do(ctx, http.MethodPost, path, nil, requestDocument[resource[A]]{Data: r}, &doc)
+	return doc.Data, err
+}
+
+// update patches a resource's attributes and returns it as updated.
+func update[A any](ctx context.Context, c *Client, path string, r resource[A]) (resource[A], error) {
+	var doc document[resource[A]]
+	err := c.do(ctx, http.MethodPatch, path, nil, requestDocument[resource[A]]{Data: r}, &doc)
+	return doc.Data, err
+}
+
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, body, out any) error {
+	u := c.baseURL + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	return c.doURL(ctx, method, u, body, out)
+}
+
+func (c *Client) doURL(ctx context.Context, method, u string, body, out any) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u, reader)
+	if err != nil {
+		return err
+	}
+	token, err := c.bearerToken()
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s %s: %w", method, req.URL.Path, err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if c.logger != nil {
+		c.logger.Printf("[asc] %s %s -> %d", method, req.URL.Path, resp.StatusCode)
+	}
+
+	if resp.StatusCode >= 400 {
+		apiErr := &APIError{StatusCode: resp.StatusCode}
+		var env struct {
+			Errors []APIErrorDetail `json:"errors"`
+		}
+		if json.Unmarshal(data, &env) == nil {
+			apiErr.Errors = env.Errors
+		}
+		return apiErr
+	}
+	if out == nil || len(data) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("parse %s %s response: %w", method, req.URL.Path, err)
+	}
+	return nil
+}
@@ -0,0 +1,285 @@
+package asc_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/moasq/nanowave/internal/asc"
+	"github.com/moasq/nanowave/internal/asc/asctest"
+)
+
+func TestClientSignsRequests(t *testing.T) {
+	srv := asctest.NewServer(t)
+	srv.Add(asctest.Resource{Type: "apps", ID: "app1", Attributes: map[string]any{"name": "Habits", "bundleId": "com.example.habits"}})
+
+	client := srv.APIClient(t)
+	app, err := client.GetApp(context.Background(), "app1")
+	if err != nil {
+		t.Fatalf("GetApp() error: %v", err)
+	}
+	if app.Name != "Habits" || app.BundleID != "com.example.habits" {
+		t.Errorf("GetApp() = %+v", app)
+	}
+
+	// A key the server does not know is rejected.
+	other := asctest.NewServer(t)
+	stranger, err := asc.NewClient(other.Credential(), asc.WithBaseURL(srv.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = stranger.GetApp(context.Background(), "app1")
+	var apiErr *asc.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != 401 || !apiErr.HasCode("NOT_AUTHORIZED") {
+		t.Errorf("GetApp() with a foreign key error = %v, want 401 NOT_AUTHORIZED", err)
+	}
+
+	if _, err := asc.NewClient(&asc.Credential{PrivateKey: "not a key"}); err == nil {
+		t.Error("NewClient() accepted a malformed private key")
+	}
+}
+
+func TestClientLogsOnlyWithLogger(t *testing.T) {
+	srv := asctest.NewServer(t)
+	srv.Add(asctest.Resource{Type: "apps", ID: "app1"})
+
+	var std bytes.Buffer
+	log.SetOutput(&std)
+	t.Cleanup(func() { log.SetOutput(os.Stderr) })
+	if _, err := srv.APIClient(t).GetApp(context.Background(), "app1"); err != nil {
+		t.Fatalf("GetApp() error: %v", err)
+	}
+	if std.Len() != 0 {
+		t.Errorf("client without a logger wrote %q", std.String())
+	}
+
+	var logged bytes.Buffer
+	client, err := asc.NewClient(srv.Credential(), asc.WithBaseURL(srv.URL), asc.WithLogger(log.New(&logged, "", 0)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.GetApp(context.Background(), "app1"); err != nil {
+		t.Fatalf("GetApp() error: %v", err)
+	}
+	if got := logged.String(); got != "[asc] GET /v1/apps/app1 -> 200\n" {
+		t.Errorf("logged %q, want the request line", got)
+	}
+}
+
+func TestListAppsFollowsPages(t *testing.T) {
+	srv := asctest.NewServer(t)
+	for i := 0; i < 120; i++ {
+		srv.Add(asctest.Resource{Type: "apps", Attributes: map[string]any{"name": "App", "bundleId": "com.example.app" + string(rune('a'+i%26))}})
+	}
+	srv.Add(asctest.Resource{Type: "apps", ID: "target", Attributes: map[string]any{"name": "Target", "bundleId": "com.example.target"}})
+
+	client := srv.APIClient(t)
+	// The fake serves at most 50 per page, so this crosses three pages.
+	apps, err := client.ListApps(context.Background())
+	if err != nil {
+		t.Fatalf("ListApps() error: %v", err)
+	}
+	if len(apps) != 121 {
+		t.Errorf("ListApps() returned %d apps, want 121", len(apps))
+	}
+
+	app, ok, err := client.FindAppByBundleID(context.Background(), "com.example.target")
+	if err != nil || !ok || app.ID != "target" {
+		t.Errorf("FindAppByBundleID() = %+v, %v, %v", app, ok, err)
+	}
+	if _, ok, _ := client.FindAppByBundleID(context.Background(), "com.example.missing"); ok {
+		t.Error("FindAppByBundleID() found an unregistered bundle ID")
+	}
+}
+
+func TestRequestDocumentsCarryNoLinks(t *testing.T) {
+	var body map[string]any
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		if err := json.Unmarshal(data, &body); err != nil {
+			t.Errorf("request body %s: %v", data, err)
+		}
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"data":{"type":"betaGroups","id":"g1","attributes":{"name":"Friends"}}}`))
+	}))
+	defer api.Close()
+
+	client, err := asc.NewClient(asctest.NewServer(t).Credential(), asc.WithBaseURL(api.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.CreateBetaGroup(context.Background(), "app1", "Friends", false); err != nil {
+		t.Fatalf("CreateBetaGroup() error: %v", err)
+	}
+	if _, ok := body["links"]; ok || body["data"] == nil {
+		t.Errorf("request document = %v, want data without links", body)
+	}
+}
+
+func TestListRefusesNextPageOnAnotherHost(t *testing.T) {
+	elsewhere := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("next page link followed to another host with Authorization %q", r.Header.Get("Authorization"))
+	}))
+	defer elsewhere.Close()
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"data":[{"type":"apps","id":"app1","attributes":{"name":"Habits"}}],"links":{"next":"` + elsewhere.URL + `/v1/apps?cursor=2"}}`))
+	}))
+	defer api.Close()
+
+	client, err := asc.NewClient(asctest.NewServer(t).Credential(), asc.WithBaseURL(api.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.ListApps(context.Background()); err == nil || !strings.Contains(err.Error(), "leaves the API host") {
+		t.Errorf("ListApps() error = %v, want the foreign next link refused", err)
+	}
+}
+
+func TestBundleIDs(t *testing.T) {
+	srv := asctest.NewServer(t)
+	client := srv.APIClient(t)
+	ctx := context.Background()
+
+	created, err := client.CreateBundleID(ctx, "com.example.habits", "Habits", "IOS")
+	if err != nil || created.ID == "" {
+		t.Fatalf("CreateBundleID() = %+v, %v", created, err)
+	}
+	if _, err := client.CreateBundleID(ctx, "com.example.habits", "Habits", "IOS"); !asc.IsConflict(err) {
+		t.Errorf("CreateBundleID() twice error = %v, want a conflict", err)
+	}
+	found, ok, err := client.FindBundleID(ctx, "com.example.habits")
+	if err != nil || !ok || found.ID != created.ID {
+		t.Errorf("FindBundleID() = %+v, %v, %v; want %s", found, ok, err, created.ID)
+	}
+}
+
+func TestCheckAgreements(t *testing.T) {
+	srv := asctest.NewServer(t)
+	client := srv.APIClient(t)
+	ctx := context.Background()
+
+	ok, agreements, err := client.CheckAgreements(ctx)
+	if err != nil || !ok || agreements == nil {
+		t.Errorf("CheckAgreements() = %v, %v, %v; want ok", ok, agreements, err)
+	}
+
+	srv.SetAgreementsExpired(true)
+	ok, agreements, err = client.CheckAgreements(ctx)
+	if err != nil || ok || len(agreements) != 1 || agreements[0].Status != "MISSING_OR_EXPIRED" {
+		t.Errorf("CheckAgreements() with expired agreements = %v, %+v, %v", ok, agreements, err)
+	}
+}
+
+func TestVersionsAndBuilds(t *testing.T) {
+	srv := asctest.NewServer(t)
+	srv.Add(asctest.Resource{Type: "apps", ID: "app1"})
+	srv.Add(asctest.Resource{Type: "builds", ID: "b1", Attributes: map[string]any{"version": "1", "processingState": "VALID", "uploadedDate": "2026-01-01T10:00:00Z"},
+		Relationships: map[string]asctest.Ref{"app": {Type: "apps", ID: "app1"}}})
+	srv.Add(asctest.Resource{Type: "builds", ID: "b2", Attributes: map[string]any{"version": "2", "processingState": "PROCESSING", "uploadedDate": "2026-02-01T10:00:00Z"},
+		Relationships: map[string]asctest.Ref{"app": {Type: "apps", ID: "app1"}}})
+	srv.Add(asctest.Resource{Type: "builds", ID: "other", Attributes: map[string]any{"version": "9", "uploadedDate": "2026-03-01T10:00:00Z"},
+		Relationships: map[string]asctest.Ref{"app": {Type: "apps", ID: "app2"}}})
+
+	client := srv.APIClient(t)
+	ctx := context.Background()
+
+	builds, err := client.ListBuilds(ctx, "app1", 1)
+	if err != nil || len(builds) != 1 || builds[0].ID != "b2" || builds[0].ProcessingState != "PROCESSING" {
+		t.Errorf("ListBuilds(limit 1) = %+v, %v; want the latest build b2", builds, err)
+	}
+
+	version, err := client.CreateAppStoreVersion(ctx, "app1", "IOS", "1.0.0")
+	if err != nil {
+		t.Fatalf("CreateAppStoreVersion() error: %v", err)
+	}
+	versions, err := client.ListAppStoreVersions(ctx, "app1", 5)
+	if err != nil || len(versions) != 1 || versions[0].VersionString != "1.0.0" {
+		t.Errorf("ListAppStoreVersions() = %+v, %v", versions, err)
+	}
+	if err := client.AttachBuild(ctx, version.ID, "b1"); err != nil {
+		t.Fatalf("AttachBuild() error: %v", err)
+	}
+	stored, _ := srv.Find("appStoreVersions", version.ID)
+	if stored.Relationships["build"].ID != "b1" {
+		t.Errorf("version build = %+v, want b1", stored.Relationships["build"])
+	}
+}
+
+func TestLocalizationsScreenshotsAndSubmission(t *testing.T) {
+	srv := asctest.NewServer(t)
+	srv.Add(asctest.Resource{Type: "apps", ID: "app1"})
+	srv.Add(asctest.Resource{Type: "appStoreVersions", ID: "v1", Attributes: map[string]any{"versionString": "1.0", "appStoreState": asc.VersionPrepareForSubmission},
+		Relationships: map[string]asctest.Ref{"app": {Type: "apps", ID: "app1"}}})
+
+	client := srv.APIClient(t)
+	ctx := context.Background()
+
+	loc, err := client.CreateVersionLocalization(ctx, "v1", "en-US", asc.LocalizationFields{Description: "Track habits", Keywords: "habits"})
+	if err != nil {
+		t.Fatalf("CreateVersionLocalization() error: %v", err)
+	}
+	if _, err := client.UpdateVersionLocalization(ctx, loc.ID, asc.LocalizationFields{WhatsNew: "First release"}); err != nil {
+		t.Fatalf("UpdateVersionLocalization() error: %v", err)
+	}
+	locs, err := client.ListVersionLocalizations(ctx, "v1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []asc.VersionLocalization{{ID: loc.ID, Locale: "en-US", LocalizationFields: asc.LocalizationFields{
+		Description: "Track habits", Keywords: "habits", WhatsNew: "First release",
+	}}}
+	if !reflect.DeepEqual(locs, want) {
+		t.Errorf("ListVersionLocalizations() = %+v, want %+v", locs, want)
+	}
+
+	set, err := client.CreateScreenshotSet(ctx, loc.ID, "APP_IPHONE_67")
+	if err != nil {
+		t.Fatalf("CreateScreenshotSet() error: %v", err)
+	}
+	sets, err := client.ListScreenshotSets(ctx, loc.ID)
+	if err != nil || len(sets) != 1 || sets[0].DisplayType != "APP_IPHONE_67" {
+		t.Errorf("ListScreenshotSets() = %+v, %v", sets, err)
+	}
+
+	png := filepath.Join(t.TempDir(), "01_home.png")
+	data := bytes.Repeat([]byte("png"), 100)
+	if err := os.WriteFile(png, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	shotID, err := client.UploadScreenshot(ctx, set.ID, png)
+	if err != nil {
+		t.Fatalf("UploadScreenshot() error: %v", err)
+	}
+	if !bytes.Equal(srv.Uploaded(shotID), data) {
+		t.Error("UploadScreenshot() did not upload the file contents")
+	}
+	shot, _ := srv.Find("appScreenshots", shotID)
+	sum := md5.Sum(data)
+	if shot.Attributes["uploaded"] != true || shot.Attributes["sourceFileChecksum"] != hex.EncodeToString(sum[:]) {
+		t.Errorf("screenshot was not committed: %+v", shot.Attributes)
+	}
+
+	sub, err := client.SubmitForReview(ctx, "app1", "IOS", "v1")
+	if err != nil {
+		t.Fatalf("SubmitForReview() error: %v", err)
+	}
+	if sub.State != "WAITING_FOR_REVIEW" {
+		t.Errorf("SubmitForReview() state = %s", sub.State)
+	}
+	items := srv.All("reviewSubmissionItems")
+	if len(items) != 1 || items[0].Relationships["appStoreVersion"].ID != "v1" || items[0].Relationships["reviewSubmission"].ID != sub.ID {
+		t.Errorf("review submission items = %+v", items)
+	}
+}
@@ -0,0 +1,130 @@
+package asc
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+)
+
+// ScreenshotSet holds a localization's screenshots for one display type.
+type ScreenshotSet struct {
+	ID string
+	// DisplayType is the screenshotDisplayType, e.g. "APP_IPHONE_67".
+	DisplayType string
+}
+
+type screenshotSetAttributes struct {
+	ScreenshotDisplayType string `json:"screenshotDisplayType"`
+}
+
+// ListScreenshotSets returns the screenshot sets of a version localization.
+func (c *Client) ListScreenshotSets(ctx context.Context, localizationID string) ([]ScreenshotSet, error) {
+	rs, err := list[screenshotSetAttributes](ctx, c, "/v1/appStoreVersionLocalizations/"+url.PathEscape(localizationID)+"/appScreenshotSets", nil, 0)
+	if err != nil {
+		return nil, err
+	}
+	sets := make([]ScreenshotSet, 0, len(rs))
+	for _, r := range rs {
+		sets = append(sets, ScreenshotSet{ID: r.ID, DisplayType: r.Attributes.ScreenshotDisplayType})
+	}
+	return sets, nil
+}
+
+// CreateScreenshotSet adds a screenshot set for displayType to a version localization.
+func (c *Client) CreateScreenshotSet(ctx context.Context, localizationID, displayType string) (ScreenshotSet, error) {
+	r, err := create(ctx, c, "/v1/appScreenshotSets", resource[screenshotSetAttributes]{
+		Type:          "appScreenshotSets",
+		Attributes:    screenshotSetAttributes{ScreenshotDisplayType: displayType},
+		Relationships: map[string]relationship{"appStoreVersionLocalization": relate("appStoreVersionLocalizations", localizationID)},
+	})
+	if err != nil {
+		return ScreenshotSet{}, err
+	}
+	return ScreenshotSet{ID: r.ID, DisplayType: r.Attributes.ScreenshotDisplayType}, nil
+}
+
+type uploadOperation struct {
+	Method         string `json:"method"`
+	URL            string `json:"url"`
+	Length         int    `json:"length"`
+	Offset         int    `json:"offset"`
+	RequestHeaders []struct {
+		Name  string `json:"name"`
+		Value string `json:"value"`
+	} `json:"requestHeaders"`
+}
+
+type screenshotAttributes struct {
+	FileName           string            `json:"fileName,omitempty"`
+	FileSize           int               `json:"fileSize,omitempty"`
+	SourceFileChecksum string            `json:"sourceFileChecksum,omitempty"`
+	Uploaded           bool              `json:"uploaded,omitempty"`
+	UploadOperations   []uploadOperation `json:"uploadOperations,omitempty"`
+}
+
+// UploadScreenshot uploads the PNG at path into a screenshot set: it reserves
+// the screenshot, sends each upload operation, then commits it with the file's
+// checksum. Returns the screenshot ID.
+func (c *Client) UploadScreenshot(ctx context.Context, setID, path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	reserved, err := create(ctx, c, "/v1/appScreenshots", resource[screenshotAttributes]{
+		Type:          "appScreenshots",
+		Attributes:    screenshotAttributes{FileName: filepath.Base(path), FileSize: len(data)},
+		Relationships: map[string]relationship{"appScreenshotSet": relate("appScreenshotSets", setID)},
+	})
+	if err != nil {
+		return "", fmt.Errorf("reserve screenshot %s: %w", filepath.Base(path), err)
+	}
+
+	for _, op := range reserved.Attributes.UploadOperations {
+		if err := c.sendUploadOperation(ctx, op, data); err != nil {
+			return "", fmt.Errorf("upload screenshot %s: %w", filepath.Base(path), err)
+		}
+	}
+
+	sum := md5.Sum(data)
+	_, err = update(ctx, c, "/v1/appScreenshots/"+url.PathEscape(reserved.ID), resource[screenshotAttributes]{
+		Type:       "appScreenshots",
+		ID:         reserved.ID,
+		Attributes: screenshotAttributes{Uploaded: true, SourceFileChecksum: hex.EncodeToString(sum[:])},
+	})
+	if err != nil {
+		return "", fmt.Errorf("commit screenshot %s: %w", filepath.Base(path), err)
+	}
+	return reserved.ID, nil
+}
+
+// sendUploadOperation sends one chunk of an asset to the pre-signed URL ASC
+// returned; these requests carry their own headers instead of the API token.
+func (c *Client) sendUploadOperation(ctx context.Context, op uploadOperation, data []byte) error {
+	if op.Offset < 0 || op.Length < 0 || op.Offset+op.Length > len(data) {
+		return fmt.Errorf("upload operation range %d+%d exceeds %d bytes", op.Offset, op.Length, len(data))
+	}
+	req, err := http.NewRequestWithContext(ctx, op.Method, op.URL, bytes.NewReader(data[op.Offset:op.Offset+op.Length]))
+	if err != nil {
+		return err
+	}
+	for _, h := range op.RequestHeaders {
+		req.Header.Set(h.Name, h.Value)
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("%s returned %d", op.Method, resp.StatusCode)
+	}
+	return nil
+}
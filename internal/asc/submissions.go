@@ -0,0 +1,57 @@
+package asc
+
+import (
+	"context"
+	"net/url"
+)
+
+// ReviewSubmission is a submission of app items for App Review.
+type ReviewSubmission struct {
+	ID       string
+	Platform string
+	State    string
+}
+
+type reviewSubmissionAttributes struct {
+	Platform  string `json:"platform,omitempty"`
+	State     string `json:"state,omitempty"`
+	Submitted bool   `json:"submitted,omitempty"`
+}
+
+func reviewSubmissionFrom(r resource[reviewSubmissionAttributes]) ReviewSubmission {
+	return ReviewSubmission{ID: r.ID, Platform: r.Attributes.Platform, State: r.Attributes.State}
+}
+
+// SubmitForReview submits an App Store version for App Review: it opens a
+// review submission, adds the version to it, and submits it.
+func (c *Client) SubmitForReview(ctx context.Context, appID, platform, versionID string) (ReviewSubmission, error) {
+	sub, err := create(ctx, c, "/v1/reviewSubmissions", resource[reviewSubmissionAttributes]{
+		Type:          "reviewSubmissions",
+		Attributes:    reviewSubmissionAttributes{Platform: platform},
+		Relationships: map[string]relationship{"app": relate("apps", appID)},
+	})
+	if err != nil {
+		return ReviewSubmission{}, err
+	}
+
+	_, err = create(ctx, c, "/v1/reviewSubmissionItems", resource[struct{}]{
+		Type: "reviewSubmissionItems",
+		Relationships: map[string]relationship{
+			"reviewSubmission": relate("reviewSubmissions", sub.ID),
+			"appStoreVersion":  relate("appStoreVersions", versionID),
+		},
+	})
+	if err != nil {
+		return reviewSubmissionFrom(sub), err
+	}
+
+	submitted, err := update(ctx, c, "/v1/reviewSubmissions/"+url.PathEscape(sub.ID), resource[reviewSubmissionAttributes]{
+		Type:       "reviewSubmissions",
+		ID:         sub.ID,
+		Attributes: reviewSubmissionAttributes{Submitted: true},
+	})
+	if err != nil {
+		return reviewSubmissionFrom(sub), err
+	}
+	return reviewSubmissionFrom(submitted), nil
+}
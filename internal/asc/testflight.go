@@ -0,0 +1,327 @@
+package asc
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// BetaGroup is a TestFlight group. Internal groups hold App Store Connect
+// users and see every build; external groups need builds assigned to them
+// and beta review.
+type BetaGroup struct {
+	ID                string
+	Name              string
+	Internal          bool
+	PublicLinkEnabled bool
+	PublicLink        string
+	CreatedDate       time.Time
+}
+
+type betaGroupAttributes struct {
+	Name              string    `json:"name,omitempty"`
+	IsInternalGroup   bool      `json:"isInternalGroup,omitempty"`
+	PublicLinkEnabled bool      `json:"publicLinkEnabled,omitempty"`
+	PublicLink        string    `json:"publicLink,omitempty"`
+	CreatedDate       time.Time `json:"createdDate,omitzero"`
+}
+
+func betaGroupFrom(r resource[betaGroupAttributes]) BetaGroup {
+	return BetaGroup{
+		ID:                r.ID,
+		Name:              r.Attributes.Name,
+		Internal:          r.Attributes.IsInternalGroup,
+		PublicLinkEnabled: r.Attributes.PublicLinkEnabled,
+		PublicLink:        r.Attributes.PublicLink,
+		CreatedDate:       r.Attributes.CreatedDate,
+	}
+}
+
+// ListBetaGroups returns every TestFlight group of the app.
+func (c *Client) ListBetaGroups(ctx context.Context, appID string) ([]BetaGroup, error) {
+	rs, err := list[betaGroupAttributes](ctx, c, "/v1/apps/"+url.PathEscape(appID)+"/betaGroups", nil, 0)
+	if err != nil {
+		return nil, err
+	}
+	groups := make([]BetaGroup, 0, len(rs))
+	for _, r := range rs {
+		groups = append(groups, betaGroupFrom(r))
+	}
+	return groups, nil
+}
+
+// CreateBetaGroup adds a TestFlight group to the app.
+func (c *Client) CreateBetaGroup(ctx context.Context, appID, name string, internal bool) (BetaGroup, error) {
+	r, err := create(ctx, c, "/v1/betaGroups", resource[betaGroupAttributes]{
+		Type:          "betaGroups",
+		Attributes:    betaGroupAttributes{Name: name, IsInternalGroup: internal},
+		Relationships: map[string]relationship{"app": relate("apps", appID)},
+	})
+	if err != nil {
+		return BetaGroup{}, err
+	}
+	return betaGroupFrom(r), nil
+}
+
+// BetaTester is a person invited to test builds through TestFlight.
+type BetaTester struct {
+	ID         string
+	Email      string
+	FirstName  string
+	LastName   string
+	InviteType string
+	State      string
+}
+
+type betaTesterAttributes struct {
+	Email      string `json:"email,omitempty"`
+	FirstName  string `json:"firstName,omitempty"`
+	LastName   string `json:"lastName,omitempty"`
+	InviteType string `json:"inviteType,omitempty"`
+	State      string `json:"state,omitempty"`
+}
+
+func betaTesterFrom(r resource[betaTesterAttributes]) BetaTester {
+	return BetaTester{
+		ID:         r.ID,
+		Email:      r.Attributes.Email,
+		FirstName:  r.Attributes.FirstName,
+		LastName:   r.Attributes.LastName,
+		InviteType: r.Attributes.InviteType,
+		State:      r.Attributes.State,
+	}
+}
+
+func betaTestersFrom(rs []resource[betaTesterAttributes]) []BetaTester {
+	testers := make([]BetaTester, 0, len(rs))
+	for _, r := range rs {
+		testers = append(testers, betaTesterFrom(r))
+	}
+	return testers
+}
+
+// FindBetaTester returns the tester with the given email, or false when the
+// account has none.
+func (c *Client) FindBetaTester(ctx context.Context, email string) (BetaTester, bool, error) {
+	rs, err := list[betaTesterAttributes](ctx, c, "/v1/betaTesters", url.Values{"filter[email]": {email}}, 1)
+	if err != nil || len(rs) == 0 {
+		return BetaTester{}, false, err
+	}
+	return betaTesterFrom(rs[0]), true, nil
+}
+
+// CreateBetaTester invites a new tester into the given groups.
+func (c *Client) CreateBetaTester(ctx context.Context, email, firstName, lastName string, groupIDs ...string) (BetaTester, error) {
+	r, err := create(ctx, c, "/v1/betaTesters", resource[betaTesterAttributes]{
+		Type:          "betaTesters",
+		Attributes:    betaTesterAttributes{Email: email, FirstName: firstName, LastName: lastName},
+		Relationships: map[string]relationship{"betaGroups": relateMany("betaGroups", groupIDs...)},
+	})
+	if err != nil {
+		return BetaTester{}, err
+	}
+	return betaTesterFrom(r), nil
+}
+
+// ListGroupTesters returns the testers in a TestFlight group.
+func (c *Client) ListGroupTesters(ctx context.Context, groupID string) ([]BetaTester, error) {
+	rs, err := list[betaTesterAttributes](ctx, c, "/v1/betaGroups/"+url.PathEscape(groupID)+"/betaTesters", nil, 0)
+	if err != nil {
+		return nil, err
+	}
+	return betaTestersFrom(rs), nil
+}
+
+// AddTestersToGroup adds existing testers to a TestFlight group.
+func (c *Client) AddTestersToGroup(ctx context.Context, groupID string, testerIDs ...string) error {
+	return c.do(ctx, http.MethodPost, "/v1/betaGroups/"+url.PathEscape(groupID)+"/relationships/betaTesters", nil, relateMany("betaTesters", testerIDs...), nil)
+}
+
+// RemoveTestersFromGroup removes testers from a TestFlight group. The testers
+// keep their access through any other group.
+func (c *Client) RemoveTestersFromGroup(ctx context.Context, groupID string, testerIDs ...string) error {
+	return c.do(ctx, http.MethodDelete, "/v1/betaGroups/"+url.PathEscape(groupID)+"/relationships/betaTesters", nil, relateMany("betaTesters", testerIDs...), nil)
+}
+
+// AddBuildsToGroup makes builds available to a TestFlight group.
+func (c *Client) AddBuildsToGroup(ctx context.Context, groupID string, buildIDs ...string) error {
+	return c.do(ctx, http.MethodPost, "/v1/betaGroups/"+url.PathEscape(groupID)+"/relationships/builds", nil, relateMany("builds", buildIDs...), nil)
+}
+
+// FindBuild returns the app's build with the given build number under the
+// marketing version, or false when none has been uploaded. Numbers can repeat
+// across marketing versions, so with an empty marketing version the most
+// recently uploaded build with the number is returned.
+func (c *Client) FindBuild(ctx context.Context, appID, marketingVersion, number string) (Build, bool, error) {
+	query := url.Values{"filter[app]": {appID}, "filter[version]": {number}, "sort": {"-uploadedDate"}}
+	if marketingVersion != "" {
+		query.Set("filter[preReleaseVersion.version]", marketingVersion)
+	}
+	rs, err := list[buildAttributes](ctx, c, "/v1/builds", query, 1)
+	if err != nil || len(rs) == 0 {
+		return Build{}, false, err
+	}
+	r := rs[0]
+	return Build{ID: r.ID, Version: r.Attributes.Version, ProcessingState: r.Attributes.ProcessingState, UploadedDate: r.Attributes.UploadedDate}, true, nil
+}
+
+// SubmitForBetaReview submits a build for TestFlight beta review, which it
+// must pass before external testers can install it. A build already
+// submitted is left as is.
+func (c *Client) SubmitForBetaReview(ctx context.Context, buildID string) error {
+	query := url.Values{"filter[build]": {buildID}}
+	rs, err := list[struct{}](ctx, c, "/v1/betaAppReviewSubmissions", query, 1)
+	if err != nil || len(rs) > 0 {
+		return err
+	}
+	_, err = create(ctx, c, "/v1/betaAppReviewSubmissions", resource[struct{}]{
+		Type:          "betaAppReviewSubmissions",
+		Relationships: map[string]relationship{"build": relate("builds", buildID)},
+	})
+	return err
+}
+
+// BetaBuildLocalization holds a build's "What to Test" notes for one locale.
+type BetaBuildLocalization struct {
+	ID       string
+	Locale   string
+	WhatsNew string
+}
+
+type betaBuildLocalizationAttributes struct {
+	Locale   string `json:"locale,omitempty"`
+	WhatsNew string `json:"whatsNew,omitempty"`
+}
+
+func betaBuildLocalizationFrom(r resource[betaBuildLocalizationAttributes]) BetaBuildLocalization {
+	return BetaBuildLocalization{ID: r.ID, Locale: r.Attributes.Locale, WhatsNew: r.Attributes.WhatsNew}
+}
+
+// ListBetaBuildLocalizations returns the "What to Test" notes of a build.
+func (c *Client) ListBetaBuildLocalizations(ctx context.Context, buildID string) ([]BetaBuildLocalization, error) {
+	rs, err := list[betaBuildLocalizationAttributes](ctx, c, "/v1/builds/"+url.PathEscape(buildID)+"/betaBuildLocalizations", nil, 0)
+	if err != nil {
+		return nil, err
+	}
+	locs := make([]BetaBuildLocalization, 0, len(rs))
+	for _, r := range rs {
+		locs = append(locs, betaBuildLocalizationFrom(r))
+	}
+	return locs, nil
+}
+
+// SetWhatToTest sets a build's "What to Test" notes for a locale, creating
+// the localization when the build has none for it yet.
+func (c *Client) SetWhatToTest(ctx context.Context, buildID, locale, text string) (BetaBuildLocalization, error) {
+	locs, err := c.ListBetaBuildLocalizations(ctx, buildID)
+	if err != nil {
+		return BetaBuildLocalization{}, err
+	}
+	for _, loc := range locs {
+		if loc.Locale != locale {
+			continue
+		}
+		r, err := update(ctx, c, "/v1/betaBuildLocalizations/"+url.PathEscape(loc.ID), resource[betaBuildLocalizationAttributes]{
+			Type:       "betaBuildLocalizations",
+			ID:         loc.ID,
+			Attributes: betaBuildLocalizationAttributes{WhatsNew: text},
+		})
+		if err != nil {
+			return BetaBuildLocalization{}, err
+		}
+		return betaBuildLocalizationFrom(r), nil
+	}
+	r, err := create(ctx, c, "/v1/betaBuildLocalizations", resource[betaBuildLocalizationAttributes]{
+		Type:          "betaBuildLocalizations",
+		Attributes:    betaBuildLocalizationAttributes{Locale: locale, WhatsNew: text},
+		Relationships: map[string]relationship{"build": relate("builds", buildID)},
+	})
+	if err != nil {
+		return BetaBuildLocalization{}, err
+	}
+	return betaBuildLocalizationFrom(r), nil
+}
+
+// Feedback is a tester's screenshot or crash submission from TestFlight.
+type Feedback struct {
+	ID          string
+	CreatedDate time.Time
+	Comment     string
+	Email       string
+	DeviceModel string
+	OSVersion   string
+	AppPlatform string
+	BuildID     string
+	Screenshots []FeedbackScreenshot
+}
+
+// FeedbackScreenshot is an image attached to screenshot feedback. Its URL
+// expires shortly after it is listed.
+type FeedbackScreenshot struct {
+	URL    string `json:"url"`
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+}
+
+type feedbackAttributes struct {
+	CreatedDate time.Time            `json:"createdDate"`
+	Comment     string               `json:"comment"`
+	Email       string               `json:"email"`
+	DeviceModel string               `json:"deviceModel"`
+	OSVersion   string               `json:"osVersion"`
+	AppPlatform string               `json:"appPlatform"`
+	Screenshots []FeedbackScreenshot `json:"screenshots"`
+}
+
+// ListFeedbackScreenshots returns the app's screenshot feedback, newest
+// first. A non-empty buildID limits it to that build.
+func (c *Client) ListFeedbackScreenshots(ctx context.Context, appID, buildID string) ([]Feedback, error) {
+	return c.listFeedback(ctx, appID, "betaFeedbackScreenshotSubmissions", buildID)
+}
+
+// ListFeedbackCrashes returns the app's crash feedback, newest first. A
+// non-empty buildID limits it to that build.
+func (c *Client) ListFeedbackCrashes(ctx context.Context, appID, buildID string) ([]Feedback, error) {
+	return c.listFeedback(ctx, appID, "betaFeedbackCrashSubmissions", buildID)
+}
+
+func (c *Client) listFeedback(ctx context.Context, appID, kind, buildID string) ([]Feedback, error) {
+	query := url.Values{"sort": {"-createdDate"}}
+	if buildID != "" {
+		query.Set("filter[build]", buildID)
+	}
+	rs, err := list[feedbackAttributes](ctx, c, "/v1/apps/"+url.PathEscape(appID)+"/"+kind, query, 0)
+	if err != nil {
+		return nil, err
+	}
+	feedback := make([]Feedback, 0, len(rs))
+	for _, r := range rs {
+		f := Feedback{
+			ID:          r.ID,
+			CreatedDate: r.Attributes.CreatedDate,
+			Comment:     r.Attributes.Comment,
+			Email:       r.Attributes.Email,
+			DeviceModel: r.Attributes.DeviceModel,
+			OSVersion:   r.Attributes.OSVersion,
+			AppPlatform: r.Attributes.AppPlatform,
+			Screenshots: r.Attributes.Screenshots,
+		}
+		if build := r.Relationships["build"].Data; build != nil {
+			f.BuildID = build.ID
+		}
+		feedback = append(feedback, f)
+	}
+	return feedback, nil
+}
+
+// CrashLog returns the crash log text of a crash submission.
+func (c *Client) CrashLog(ctx context.Context, submissionID string) (string, error) {
+	r, err := get[struct {
+		LogText string `json:"logText"`
+	}](ctx, c, "/v1/betaFeedbackCrashSubmissions/"+url.PathEscape(submissionID)+"/crashLog", nil)
+	if err != nil {
+		return "", err
+	}
+	return r.Attributes.LogText, nil
+}
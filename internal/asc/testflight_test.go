@@ -0,0 +1,186 @@
+package asc_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/moasq/nanowave/internal/asc"
+	"github.com/moasq/nanowave/internal/asc/asctest"
+)
+
+func TestBetaGroupsAndTesters(t *testing.T) {
+	srv := asctest.NewServer(t)
+	srv.Add(asctest.Resource{Type: "apps", ID: "app1"})
+	srv.Add(asctest.Resource{Type: "betaTesters", ID: "t1", Attributes: map[string]any{"email": "ada@example.com", "firstName": "Ada"}})
+
+	client := srv.APIClient(t)
+	ctx := context.Background()
+
+	group, err := client.CreateBetaGroup(ctx, "app1", "Friends", false)
+	if err != nil {
+		t.Fatalf("CreateBetaGroup() error: %v", err)
+	}
+	groups, err := client.ListBetaGroups(ctx, "app1")
+	if err != nil || len(groups) != 1 || groups[0].Name != "Friends" || groups[0].Internal {
+		t.Fatalf("ListBetaGroups() = %+v, %v; want the external Friends group", groups, err)
+	}
+
+	existing, ok, err := client.FindBetaTester(ctx, "ada@example.com")
+	if err != nil || !ok || existing.ID != "t1" {
+		t.Fatalf("FindBetaTester() = %+v, %v, %v; want t1", existing, ok, err)
+	}
+	if _, ok, _ := client.FindBetaTester(ctx, "nobody@example.com"); ok {
+		t.Error("FindBetaTester() found a tester that does not exist")
+	}
+	if err := client.AddTestersToGroup(ctx, group.ID, existing.ID); err != nil {
+		t.Fatalf("AddTestersToGroup() error: %v", err)
+	}
+	invited, err := client.CreateBetaTester(ctx, "grace@example.com", "Grace", "Hopper", group.ID)
+	if err != nil {
+		t.Fatalf("CreateBetaTester() error: %v", err)
+	}
+
+	testers, err := client.ListGroupTesters(ctx, group.ID)
+	if err != nil || len(testers) != 2 {
+		t.Fatalf("ListGroupTesters() = %+v, %v; want two testers", testers, err)
+	}
+
+	if err := client.RemoveTestersFromGroup(ctx, group.ID, existing.ID); err != nil {
+		t.Fatalf("RemoveTestersFromGroup() error: %v", err)
+	}
+	testers, _ = client.ListGroupTesters(ctx, group.ID)
+	if len(testers) != 1 || testers[0].ID != invited.ID {
+		t.Errorf("group testers after removal = %+v, want only %s", testers, invited.ID)
+	}
+	if _, ok := srv.Find("betaTesters", existing.ID); !ok {
+		t.Error("removing a tester from a group deleted the tester")
+	}
+
+	if err := client.AddTestersToGroup(ctx, group.ID, "missing"); !asc.IsNotFound(err) {
+		t.Errorf("AddTestersToGroup(missing) error = %v, want not found", err)
+	}
+}
+
+func TestAssignBuildAndWhatToTest(t *testing.T) {
+	srv := asctest.NewServer(t)
+	srv.Add(asctest.Resource{Type: "apps", ID: "app1"})
+	srv.Add(asctest.Resource{Type: "betaGroups", ID: "g1", Attributes: map[string]any{"name": "Friends"},
+		Relationships: map[string]asctest.Ref{"app": {Type: "apps", ID: "app1"}}})
+	for id, number := range map[string]string{"b1": "1", "b2": "2"} {
+		srv.Add(asctest.Resource{Type: "builds", ID: id, Attributes: map[string]any{"version": number, "processingState": "VALID"},
+			Relationships: map[string]asctest.Ref{"app": {Type: "apps", ID: "app1"}}})
+	}
+
+	client := srv.APIClient(t)
+	ctx := context.Background()
+
+	build, ok, err := client.FindBuild(ctx, "app1", "", "2")
+	if err != nil || !ok || build.ID != "b2" {
+		t.Fatalf("FindBuild(2) = %+v, %v, %v; want b2", build, ok, err)
+	}
+	if _, ok, _ := client.FindBuild(ctx, "app1", "", "9"); ok {
+		t.Error("FindBuild(9) found a build that was never uploaded")
+	}
+	if err := client.AddBuildsToGroup(ctx, "g1", build.ID); err != nil {
+		t.Fatalf("AddBuildsToGroup() error: %v", err)
+	}
+	if linked := srv.Linked(asctest.Ref{Type: "betaGroups", ID: "g1"}, "builds"); len(linked) != 1 || linked[0].ID != "b2" {
+		t.Errorf("group builds = %+v, want b2", linked)
+	}
+
+	if _, err := client.SetWhatToTest(ctx, "b2", "en-US", "Try the new streaks screen"); err != nil {
+		t.Fatalf("SetWhatToTest() error: %v", err)
+	}
+	if _, err := client.SetWhatToTest(ctx, "b2", "en-US", "Try streaks and reminders"); err != nil {
+		t.Fatalf("SetWhatToTest() update error: %v", err)
+	}
+	locs, err := client.ListBetaBuildLocalizations(ctx, "b2")
+	if err != nil || len(locs) != 1 || locs[0].WhatsNew != "Try streaks and reminders" {
+		t.Errorf("ListBetaBuildLocalizations() = %+v, %v; want one updated en-US note", locs, err)
+	}
+}
+
+func TestFindBuildAcrossMarketingVersions(t *testing.T) {
+	srv := asctest.NewServer(t)
+	srv.Add(asctest.Resource{Type: "apps", ID: "app1"})
+	app := asctest.Ref{Type: "apps", ID: "app1"}
+	uploaded := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	for i, version := range []string{"1.0", "1.1"} {
+		id := "v" + version
+		srv.Add(asctest.Resource{Type: "preReleaseVersions", ID: id, Attributes: map[string]any{"version": version}})
+		srv.Add(asctest.Resource{Type: "builds", ID: "b" + version,
+			Attributes:    map[string]any{"version": "3", "uploadedDate": uploaded.Add(time.Duration(i) * time.Hour)},
+			Relationships: map[string]asctest.Ref{"app": app, "preReleaseVersion": {Type: "preReleaseVersions", ID: id}}})
+	}
+
+	client := srv.APIClient(t)
+	ctx := context.Background()
+
+	if build, ok, err := client.FindBuild(ctx, "app1", "1.0", "3"); err != nil || !ok || build.ID != "b1.0" {
+		t.Errorf("FindBuild(1.0, 3) = %+v, %v, %v; want b1.0", build, ok, err)
+	}
+	if build, ok, err := client.FindBuild(ctx, "app1", "", "3"); err != nil || !ok || build.ID != "b1.1" {
+		t.Errorf("FindBuild(3) = %+v, %v, %v; want the newest upload b1.1", build, ok, err)
+	}
+	if _, ok, _ := client.FindBuild(ctx, "app1", "2.0", "3"); ok {
+		t.Error("FindBuild(2.0, 3) found a build of another marketing version")
+	}
+}
+
+func TestSubmitForBetaReview(t *testing.T) {
+	srv := asctest.NewServer(t)
+	srv.Add(asctest.Resource{Type: "builds", ID: "b1"})
+
+	client := srv.APIClient(t)
+	ctx := context.Background()
+
+	for range 2 {
+		if err := client.SubmitForBetaReview(ctx, "b1"); err != nil {
+			t.Fatalf("SubmitForBetaReview() error: %v", err)
+		}
+	}
+	subs := srv.All("betaAppReviewSubmissions")
+	if len(subs) != 1 || subs[0].Relationships["build"].ID != "b1" {
+		t.Errorf("beta review submissions = %+v, want one for b1", subs)
+	}
+}
+
+func TestFeedback(t *testing.T) {
+	srv := asctest.NewServer(t)
+	srv.Add(asctest.Resource{Type: "apps", ID: "app1"})
+	app := asctest.Ref{Type: "apps", ID: "app1"}
+	older := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	srv.Add(asctest.Resource{Type: "betaFeedbackScreenshotSubmissions", ID: "s1",
+		Attributes: map[string]any{"createdDate": older, "comment": "Button is cut off", "email": "ada@example.com",
+			"screenshots": []map[string]any{{"url": "https://example.com/s1.png", "width": 1179, "height": 2556}}},
+		Relationships: map[string]asctest.Ref{"app": app, "build": {Type: "builds", ID: "b1"}}})
+	srv.Add(asctest.Resource{Type: "betaFeedbackScreenshotSubmissions", ID: "s2",
+		Attributes:    map[string]any{"createdDate": older.Add(time.Hour), "comment": "Love it"},
+		Relationships: map[string]asctest.Ref{"app": app, "build": {Type: "builds", ID: "b2"}}})
+	srv.Add(asctest.Resource{Type: "betaCrashLogs", ID: "log1", Attributes: map[string]any{"logText": "Exception Type: EXC_CRASH"}})
+	srv.Add(asctest.Resource{Type: "betaFeedbackCrashSubmissions", ID: "c1",
+		Attributes:    map[string]any{"createdDate": older, "comment": "Crashed on launch", "deviceModel": "iPhone16,1"},
+		Relationships: map[string]asctest.Ref{"app": app, "build": {Type: "builds", ID: "b1"}, "crashLog": {Type: "betaCrashLogs", ID: "log1"}}})
+
+	client := srv.APIClient(t)
+	ctx := context.Background()
+
+	all, err := client.ListFeedbackScreenshots(ctx, "app1", "")
+	if err != nil || len(all) != 2 || all[0].ID != "s2" {
+		t.Fatalf("ListFeedbackScreenshots() = %+v, %v; want s2 then s1", all, err)
+	}
+	forBuild, err := client.ListFeedbackScreenshots(ctx, "app1", "b1")
+	if err != nil || len(forBuild) != 1 || forBuild[0].BuildID != "b1" || len(forBuild[0].Screenshots) != 1 || forBuild[0].Screenshots[0].Width != 1179 {
+		t.Errorf("ListFeedbackScreenshots(b1) = %+v, %v", forBuild, err)
+	}
+
+	crashes, err := client.ListFeedbackCrashes(ctx, "app1", "")
+	if err != nil || len(crashes) != 1 || crashes[0].DeviceModel != "iPhone16,1" {
+		t.Fatalf("ListFeedbackCrashes() = %+v, %v", crashes, err)
+	}
+	log, err := client.CrashLog(ctx, "c1")
+	if err != nil || log != "Exception Type: EXC_CRASH" {
+		t.Errorf("CrashLog() = %q, %v", log, err)
+	}
+}
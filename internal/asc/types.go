@@ -1,7 +1,5 @@
 package asc
 
-import "encoding/json"
-
 // Version state constants (finite API-defined set)
 const (
 	VersionPrepareForSubmission    = "PREPARE_FOR_SUBMISSION"
@@ -57,13 +55,19 @@ type PreflightResult struct {
 	LatestBuildVersion string // e.g. "42"
 	BuildState         string // e.g. "VALID", "PROCESSING"
 
+	// Local version state, from project_config.json
+	MarketingVersion string // e.g. "1.2"
+	BuildNumber      int    // build number the next upload uses
+	VersionClosed    bool   // MarketingVersion is released or below a released version; it must be bumped
+
 	// Flags
 	IconReady bool
 	HasAPIKey bool
 
 	// Submission readiness flags
 	HasSignIn    bool // project contains authentication/login code
-	CollectsData bool // project appears to collect user data (analytics, APIs, etc.)
+	CollectsData bool // privacy analysis found collected data types
+	DataCollected []string // one line per collected data type, e.g. "Email Address: App Functionality (linked)"
 }
 
 // Credential holds the App Store Connect API key credentials needed for xcodebuild authentication.
@@ -73,17 +77,8 @@ type Credential struct {
 	PrivateKey string `json:"private_key_pem"`
 }
 
-// Agreement is the typed JSON shape returned by `asc agreements list --output json`.
+// Agreement is a developer agreement and its status.
 type Agreement struct {
 	Status string `json:"status"`
 	Type   string `json:"type"`
 }
-
-// Envelope is the App Store Connect API response envelope.
-// `asc` CLI returns {"data": [...], "links": ..., "meta": ...}.
-type Envelope struct {
-	Data []struct {
-		ID         string          `json:"id"`
-		Attributes json.RawMessage `json:"attributes"`
-	} `json:"data"`
-}
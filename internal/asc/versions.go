@@ -0,0 +1,180 @@
+package asc
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+type versionAttributes struct {
+	Platform      string `json:"platform,omitempty"`
+	VersionString string `json:"versionString,omitempty"`
+	AppStoreState string `json:"appStoreState,omitempty"`
+}
+
+func versionFrom(r resource[versionAttributes]) VersionInfo {
+	return VersionInfo{ID: r.ID, VersionString: r.Attributes.VersionString, State: r.Attributes.AppStoreState}
+}
+
+// ListAppStoreVersions returns the app's most recent App Store versions, at
+// most limit of them (0 for all).
+func (c *Client) ListAppStoreVersions(ctx context.Context, appID string, limit int) ([]VersionInfo, error) {
+	query := url.Values{}
+	if limit > 0 {
+		query.Set("limit", strconv.Itoa(limit))
+	}
+	rs, err := list[versionAttributes](ctx, c, "/v1/apps/"+url.PathEscape(appID)+"/appStoreVersions", query, limit)
+	if err != nil {
+		return nil, err
+	}
+	versions := make([]VersionInfo, 0, len(rs))
+	for _, r := range rs {
+		versions = append(versions, versionFrom(r))
+	}
+	return versions, nil
+}
+
+// editablePriority ranks the version states whose metadata can still be
+// edited; lower is preferred.
+var editablePriority = map[string]int{
+	VersionPrepareForSubmission: 1,
+	VersionDeveloperRejected:    2,
+}
+
+// EditableVersion returns the version whose metadata changes apply to,
+// preferring one being prepared over a rejected one.
+func EditableVersion(versions []VersionInfo) (VersionInfo, bool) {
+	var best VersionInfo
+	bestPriority := 0
+	for _, v := range versions {
+		if pri, ok := editablePriority[v.State]; ok && (bestPriority == 0 || pri < bestPriority) {
+			best = v
+			bestPriority = pri
+		}
+	}
+	return best, bestPriority > 0
+}
+
+// CreateAppStoreVersion creates a new version of the app for platform ("IOS", "MAC_OS", ...).
+func (c *Client) CreateAppStoreVersion(ctx context.Context, appID, platform, versionString string) (VersionInfo, error) {
+	r, err := create(ctx, c, "/v1/appStoreVersions", resource[versionAttributes]{
+		Type:          "appStoreVersions",
+		Attributes:    versionAttributes{Platform: platform, VersionString: versionString},
+		Relationships: map[string]relationship{"app": relate("apps", appID)},
+	})
+	if err != nil {
+		return VersionInfo{}, err
+	}
+	return versionFrom(r), nil
+}
+
+// AttachBuild selects the build submitted with an App Store version.
+func (c *Client) AttachBuild(ctx context.Context, versionID, buildID string) error {
+	body := relationship{Data: &linkage{Type: "builds", ID: buildID}}
+	return c.do(ctx, http.MethodPatch, "/v1/appStoreVersions/"+url.PathEscape(versionID)+"/relationships/build", nil, body, nil)
+}
+
+// Build is an uploaded build.
+type Build struct {
+	ID              string
+	Version         string
+	ProcessingState string
+	UploadedDate    time.Time
+}
+
+type buildAttributes struct {
+	Version         string    `json:"version"`
+	ProcessingState string    `json:"processingState"`
+	UploadedDate    time.Time `json:"uploadedDate"`
+}
+
+// ListBuilds returns the app's builds, most recently uploaded first, at most
+// limit of them (0 for all).
+func (c *Client) ListBuilds(ctx context.Context, appID string, limit int) ([]Build, error) {
+	query := url.Values{"filter[app]": {appID}, "sort": {"-uploadedDate"}}
+	if limit > 0 {
+		query.Set("limit", strconv.Itoa(limit))
+	}
+	rs, err := list[buildAttributes](ctx, c, "/v1/builds", query, limit)
+	if err != nil {
+		return nil, err
+	}
+	builds := make([]Build, 0, len(rs))
+	for _, r := range rs {
+		builds = append(builds, Build{
+			ID:              r.ID,
+			Version:         r.Attributes.Version,
+			ProcessingState: r.Attributes.ProcessingState,
+			UploadedDate:    r.Attributes.UploadedDate,
+		})
+	}
+	return builds, nil
+}
+
+// VersionLocalization is the per-locale store metadata of an App Store version.
+type VersionLocalization struct {
+	ID     string
+	Locale string
+	LocalizationFields
+}
+
+// LocalizationFields are the editable text fields of a version localization.
+// Empty fields are left unchanged on update.
+type LocalizationFields struct {
+	Description     string `json:"description,omitempty"`
+	Keywords        string `json:"keywords,omitempty"`
+	WhatsNew        string `json:"whatsNew,omitempty"`
+	PromotionalText string `json:"promotionalText,omitempty"`
+	MarketingURL    string `json:"marketingUrl,omitempty"`
+	SupportURL      string `json:"supportUrl,omitempty"`
+}
+
+type localizationAttributes struct {
+	Locale string `json:"locale,omitempty"`
+	LocalizationFields
+}
+
+func localizationFrom(r resource[localizationAttributes]) VersionLocalization {
+	return VersionLocalization{ID: r.ID, Locale: r.Attributes.Locale, LocalizationFields: r.Attributes.LocalizationFields}
+}
+
+// ListVersionLocalizations returns every localization of an App Store version.
+func (c *Client) ListVersionLocalizations(ctx context.Context, versionID string) ([]VersionLocalization, error) {
+	rs, err := list[localizationAttributes](ctx, c, "/v1/appStoreVersions/"+url.PathEscape(versionID)+"/appStoreVersionLocalizations", nil, 0)
+	if err != nil {
+		return nil, err
+	}
+	locs := make([]VersionLocalization, 0, len(rs))
+	for _, r := range rs {
+		locs = append(locs, localizationFrom(r))
+	}
+	return locs, nil
+}
+
+// CreateVersionLocalization adds a locale to an App Store version.
+func (c *Client) CreateVersionLocalization(ctx context.Context, versionID, locale string, fields LocalizationFields) (VersionLocalization, error) {
+	r, err := create(ctx, c, "/v1/appStoreVersionLocalizations", resource[localizationAttributes]{
+		Type:          "appStoreVersionLocalizations",
+		Attributes:    localizationAttributes{Locale: locale, LocalizationFields: fields},
+		Relationships: map[string]relationship{"appStoreVersion": relate("appStoreVersions", versionID)},
+	})
+	if err != nil {
+		return VersionLocalization{}, err
+	}
+	return localizationFrom(r), nil
+}
+
+// UpdateVersionLocalization sets the non-empty fields of a localization.
+func (c *Client) UpdateVersionLocalization(ctx context.Context, id string, fields LocalizationFields) (VersionLocalization, error) {
+	r, err := update(ctx, c, "/v1/appStoreVersionLocalizations/"+url.PathEscape(id), resource[localizationAttributes]{
+		Type:       "appStoreVersionLocalizations",
+		ID:         id,
+		Attributes: localizationAttributes{LocalizationFields: fields},
+	})
+	if err != nil {
+		return VersionLocalization{}, err
+	}
+	return localizationFrom(r), nil
+}
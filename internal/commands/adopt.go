@@ -0,0 +1,103 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/moasq/nanowave/internal/config"
+	"github.com/moasq/nanowave/internal/orchestration"
+	"github.com/moasq/nanowave/internal/storage"
+	"github.com/moasq/nanowave/internal/terminal"
+	"github.com/spf13/cobra"
+)
+
+var adoptCmd = &cobra.Command{
+	Use:   "adopt <dir>",
+	Short: "Bring an existing XcodeGen or Xcode project under nanowave",
+	Long: `Adopt a project nanowave didn't create.
+
+Reads the project's project.yml (or its .xcodeproj, from which a project.yml is
+written), infers project_config.json, writes the Claude workspace scaffold and
+adds the project to the catalog so the edit pipeline can work on it.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return adoptRun(args[0])
+	},
+}
+
+func adoptRun(dir string) error {
+	projectDir, err := filepath.Abs(dir)
+	if err != nil {
+		return err
+	}
+	if info, err := os.Stat(projectDir); err != nil || !info.IsDir() {
+		return fmt.Errorf("%s is not a directory", dir)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+
+	result, err := orchestration.AdoptProject(projectDir)
+	if err != nil {
+		return err
+	}
+
+	catalogPath, err := cfg.LinkProject(projectDir)
+	if err != nil {
+		return err
+	}
+	cfg.SetProject(catalogPath)
+	if err := cfg.EnsureNanowaveDir(); err != nil {
+		return err
+	}
+	appName := result.AppName
+	if err := storage.NewProjectStore(cfg.NanowaveDir).Save(&storage.Project{
+		ID:           1,
+		Name:         &appName,
+		Status:       "active",
+		ProjectPath:  catalogPath,
+		BundleID:     result.BundleID,
+		Platform:     result.Platform,
+		Platforms:    result.Platforms,
+		DeviceFamily: result.DeviceFamily,
+	}); err != nil {
+		return err
+	}
+
+	terminal.Header("Adopted " + result.AppName)
+	terminal.Detail("Source", result.Source)
+	terminal.Detail("Bundle ID", result.BundleID)
+	platform := result.Platform
+	if len(result.Platforms) > 1 {
+		platform = strings.Join(result.Platforms, ", ")
+	} else if result.WatchProjectShape != "" {
+		platform += " (" + result.WatchProjectShape + ")"
+	}
+	terminal.Detail("Platform", platform)
+	if result.DeviceFamily != "" {
+		terminal.Detail("Devices", result.DeviceFamily)
+	}
+	for _, section := range []struct {
+		label string
+		items []string
+	}{
+		{"Extensions", result.Extensions},
+		{"Packages", result.Packages},
+		{"Permissions", result.Permissions},
+		{"Entitlements", result.Entitlements},
+	} {
+		if len(section.items) > 0 {
+			terminal.Detail(section.label, strings.Join(section.items, ", "))
+		}
+	}
+
+	if result.GeneratedSpec {
+		terminal.Warning(fmt.Sprintf("Wrote project.yml from %s. The next XcodeGen run regenerates the .xcodeproj from it — review project.yml and commit %s first.", result.Source, result.Source))
+	}
+	terminal.Success(fmt.Sprintf("%s is ready. Run `nanowave` to edit it.", result.AppName))
+	return nil
+}
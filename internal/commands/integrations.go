@@ -9,6 +9,7 @@ import (
 	"strings"
 
 	"github.com/moasq/nanowave/internal/integrations"
+	"github.com/moasq/nanowave/internal/integrations/plugin"
 	"github.com/moasq/nanowave/internal/integrations/providers"
 	"github.com/moasq/nanowave/internal/terminal"
 	"github.com/spf13/cobra"
@@ -18,6 +19,9 @@ import (
 func newCmdManager() *integrations.Manager {
 	r := integrations.NewRegistry()
 	providers.RegisterAll(r)
+	for _, err := range providers.RegisterPlugins(context.Background(), r, plugin.DefaultDir()) {
+		terminal.Warning(fmt.Sprintf("Skipping integration plugin: %v", err))
+	}
 	return integrations.NewManager(r, loadIntegrationStore())
 }
 
@@ -46,6 +50,12 @@ var integrationsSetupCmd = &cobra.Command{
 	},
 }
 
+// integrationsSetupLocal connects a locally running stack (`supabase start`) instead of a hosted project.
+var integrationsSetupLocal bool
+
+// integrationsSetupDir is the directory the provider CLI runs in for --local setup.
+var integrationsSetupDir string
+
 var integrationsStatusCmd = &cobra.Command{
 	Use:   "status",
 	Short: "Show configured integration details",
@@ -64,6 +74,9 @@ var integrationsRemoveCmd = &cobra.Command{
 }
 
 func init() {
+	integrationsSetupCmd.Flags().BoolVar(&integrationsSetupLocal, "local", false, "Connect a local stack started with `supabase start` (used by Debug builds)")
+	integrationsSetupCmd.Flags().StringVar(&integrationsSetupDir, "dir", "", "Directory containing supabase/config.toml for --local (default: current directory)")
+
 	integrationsCmd.AddCommand(integrationsListCmd)
 	integrationsCmd.AddCommand(integrationsSetupCmd)
 	integrationsCmd.AddCommand(integrationsStatusCmd)
@@ -141,6 +154,8 @@ func integrationsSetupRun(provider string) error {
 	return sc.Setup(context.Background(), integrations.SetupRequest{
 		Store:   m.Store(),
 		AppName: "my-app",
+		Local:   integrationsSetupLocal,
+		WorkDir: integrationsSetupDir,
 		PrintFn: terminalPrintFn,
 		PickFn:  terminalPickFn,
 	})
@@ -170,6 +185,9 @@ func integrationsStatusRun() error {
 		if s.ProjectURL != "" {
 			terminal.Detail("URL", s.ProjectURL)
 		}
+		if s.LocalURL != "" {
+			terminal.Detail("Local (Debug)", s.LocalURL)
+		}
 		mark := func(ok bool) string {
 			if ok {
 				return terminal.Green + "✓" + terminal.Reset
@@ -333,6 +351,9 @@ func RunIntegrationsInteractive() {
 			}
 			terminal.Detail("App", appLabel)
 			terminal.Detail("URL", cfg.ProjectURL)
+			if cfg.Local != nil {
+				terminal.Detail("Local (Debug)", cfg.Local.APIURL)
+			}
 			mark := func(ok bool) string {
 				if ok {
 					return terminal.Green + "✓" + terminal.Reset
@@ -367,13 +388,28 @@ func RunIntegrationsInteractive() {
 		}
 	} else {
 		// Not configured — show setup options
-		action := terminal.Pick(fmt.Sprintf("%s Setup", picked), []terminal.PickerOption{
+		setupOptions := []terminal.PickerOption{
 			{Label: "Set up automatically", Desc: fmt.Sprintf("Login + create project via %s CLI", picked)},
 			{Label: "Enter credentials manually", Desc: "Paste project URL and anon key"},
-			{Label: "Cancel"},
-		}, "")
+		}
+		if pid == integrations.ProviderSupabase {
+			setupOptions = append(setupOptions, terminal.PickerOption{Label: "Connect local stack", Desc: "Use the stack started with `supabase start` in this directory"})
+		}
+		setupOptions = append(setupOptions, terminal.PickerOption{Label: "Cancel"})
+		action := terminal.Pick(fmt.Sprintf("%s Setup", picked), setupOptions, "")
 
 		switch action {
+		case "Connect local stack":
+			if sc.CLIAvailable() {
+				_ = sc.Setup(context.Background(), integrations.SetupRequest{
+					Store:   store,
+					AppName: "my-app",
+					Local:   true,
+					PrintFn: terminalPrintFn,
+				})
+			} else {
+				terminal.Warning(fmt.Sprintf("%s CLI not found.", picked))
+			}
 		case "Set up automatically":
 			if sc.CLIAvailable() {
 				_ = sc.Setup(context.Background(), integrations.SetupRequest{
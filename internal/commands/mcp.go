@@ -1,6 +1,10 @@
 package commands
 
 import (
+	"log"
+	"os"
+
+	"github.com/moasq/nanowave/internal/packageregistry"
 	"github.com/moasq/nanowave/internal/revenuecatserver"
 	"github.com/moasq/nanowave/internal/supabaseserver"
 	"github.com/moasq/nanowave/internal/xcodegenserver"
@@ -18,7 +22,19 @@ var mcpXcodegenCmd = &cobra.Command{
 	Short: "Run the XcodeGen MCP server",
 	Long:  "Starts the XcodeGen MCP server over stdio. Used by Claude Code to manage Xcode project configuration via typed tool calls.",
 	RunE: func(cmd *cobra.Command, args []string) error {
-		return xcodegenserver.Run(cmd.Context())
+		dir, err := os.Getwd()
+		if err != nil {
+			return err
+		}
+		packages, skipped, err := packageregistry.Load(userNanowaveDir(), dir)
+		if err != nil {
+			return err
+		}
+		// stdout carries the MCP protocol, so manifest problems go to the log.
+		for _, err := range skipped {
+			log.Printf("skipping package registry entry: %v", err)
+		}
+		return xcodegenserver.Run(cmd.Context(), packages)
 	},
 }
 
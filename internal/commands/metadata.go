@@ -0,0 +1,130 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"unicode/utf8"
+
+	"github.com/moasq/nanowave/internal/orchestration"
+	"github.com/moasq/nanowave/internal/terminal"
+	"github.com/spf13/cobra"
+)
+
+var metadataCmd = &cobra.Command{
+	Use:   "metadata",
+	Short: "Manage App Store metadata as code",
+	Long: `Store metadata lives in the project's metadata directory, fastlane deliver
+style: metadata/<locale>/<field>.txt for name, subtitle, description,
+keywords, promotional_text, release_notes, support_url, marketing_url and
+privacy_url, plus primary_category.txt, secondary_category.txt and
+age_rating.json. Empty or missing files are left untouched in App Store
+Connect.`,
+}
+
+var metadataDiffCmd = &cobra.Command{
+	Use:   "diff",
+	Short: "Show how local metadata differs from App Store Connect",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		_, err := metadataPlan(cmd)
+		return err
+	},
+}
+
+var metadataPushCmd = &cobra.Command{
+	Use:   "push",
+	Short: "Validate local metadata and upload it to App Store Connect",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		plan, err := metadataPlan(cmd)
+		if err != nil || plan == nil {
+			return err
+		}
+		if len(plan.Problems) > 0 {
+			return fmt.Errorf("fix the metadata problems above before pushing")
+		}
+		if len(plan.Changes) == 0 {
+			return nil
+		}
+		if !metadataYes {
+			picked := terminal.Pick(fmt.Sprintf("Push %d change(s) to App Store Connect?", len(plan.Changes)), []terminal.PickerOption{
+				{Label: "Push", Desc: fmt.Sprintf("Update version %s", plan.Remote.VersionString)},
+				{Label: "Cancel", Desc: "Leave App Store Connect unchanged"},
+			}, "")
+			if picked != "Push" {
+				terminal.Info("Push cancelled.")
+				return nil
+			}
+		}
+		if err := plan.Push(cmd.Context()); err != nil {
+			terminal.Error(fmt.Sprintf("Push failed: %v", err))
+			return err
+		}
+		terminal.Success(fmt.Sprintf("Pushed %d change(s) to App Store Connect", len(plan.Changes)))
+		return nil
+	},
+}
+
+var (
+	// metadataDir is the project whose metadata is synced.
+	metadataDir string
+	// metadataYes skips the push confirmation.
+	metadataYes bool
+)
+
+func init() {
+	metadataCmd.PersistentFlags().StringVar(&metadataDir, "dir", "", "Project directory (default: current directory)")
+	metadataPushCmd.Flags().BoolVarP(&metadataYes, "yes", "y", false, "Push without asking for confirmation")
+	metadataCmd.AddCommand(metadataDiffCmd)
+	metadataCmd.AddCommand(metadataPushCmd)
+}
+
+// metadataPlan compares the project's metadata with App Store Connect and
+// prints the validation problems and changes.
+func metadataPlan(cmd *cobra.Command) (*orchestration.MetadataSync, error) {
+	dir := metadataDir
+	if dir == "" {
+		dir, _ = os.Getwd()
+	}
+	ctx, cancel := signal.NotifyContext(cmd.Context(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+
+	plan, err := orchestration.PlanMetadataSync(ctx, dir, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	terminal.Header(fmt.Sprintf("Metadata vs App Store Connect (version %s)", plan.Remote.VersionString))
+	for _, p := range plan.Problems {
+		terminal.Error(p.String())
+	}
+	if len(plan.Changes) == 0 {
+		terminal.Success("Metadata is in sync")
+		return plan, nil
+	}
+	for _, c := range plan.Changes {
+		label := c.Label()
+		if c.Locale != "" {
+			label = c.Locale + " " + label
+		}
+		terminal.Detail(label, fmt.Sprintf("%s → %s", metadataPreview(c.From), metadataPreview(c.To)))
+	}
+	terminal.Info(fmt.Sprintf("%d change(s) to push", len(plan.Changes)))
+	return plan, nil
+}
+
+// metadataPreview shortens a field value to one line for display.
+func metadataPreview(value string) string {
+	if value == "" {
+		return "(empty)"
+	}
+	line := strings.Join(strings.Fields(value), " ")
+	if utf8.RuneCountInString(line) > 60 {
+		line = string([]rune(line)[:57]) + "..."
+	}
+	if n := utf8.RuneCountInString(value); n > 60 {
+		line += fmt.Sprintf(" (%d chars)", n)
+	}
+	return line
+}
@@ -0,0 +1,268 @@
+package commands
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/moasq/nanowave/internal/packageregistry"
+	"github.com/moasq/nanowave/internal/projectconfig"
+	"github.com/moasq/nanowave/internal/terminal"
+	"github.com/spf13/cobra"
+)
+
+var packagesCmd = &cobra.Command{
+	Use:   "packages",
+	Short: "List, search and validate the SPM package registry and update locked packages",
+	Long: `The package registry is the embedded default merged with a user manifest
+(~/nanowave/packages.yaml) and a team manifest in the project
+(.nanowave/packages.yaml). Manifests may also be .yml or .json files.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return packagesListRun()
+	},
+}
+
+var packagesListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Show the registry grouped by category",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return packagesListRun()
+	},
+}
+
+var packagesSearchCmd = &cobra.Command{
+	Use:   "search <query>",
+	Short: "Find packages by key, name, category or description",
+	Args:  cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return packagesSearchRun(strings.Join(args, " "))
+	},
+}
+
+var packagesValidateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "Check the registry manifests and the declared products",
+	Long: `Validate loads every registry manifest and reports invalid entries.
+
+With --fixtures, each package's products are checked against the Package.swift
+in <fixtures>/<repository name>/ (or <fixtures>/<key>/) when one exists.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return packagesValidateRun()
+	},
+}
+
+var packagesUpdateCmd = &cobra.Command{
+	Use:   "update [package...]",
+	Short: "Show newer versions of locked packages and unlock them",
+	Long: `After the first successful build, nanowave locks every Swift package to the
+version recorded in Package.resolved, and project.yml pins that exact version.
+
+Update lists the newest release each locked package's requirement allows,
+asks for confirmation, then unlocks the packages so Xcode resolves them again.
+Without arguments every locked package is checked.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return packagesUpdateRun(cmd, args)
+	},
+}
+
+// packagesDir is the project whose team manifest is merged into the registry.
+var packagesDir string
+
+// packagesFixtures is the directory of Package.swift fixtures for validate.
+var packagesFixtures string
+
+// packagesYes skips the confirmation of update.
+var packagesYes bool
+
+func init() {
+	packagesCmd.PersistentFlags().StringVar(&packagesDir, "dir", "", "Project directory whose .nanowave/packages.yaml is merged (default: current directory)")
+	packagesValidateCmd.Flags().StringVar(&packagesFixtures, "fixtures", "", "Directory of <package>/Package.swift files to check declared products against")
+	packagesUpdateCmd.Flags().BoolVarP(&packagesYes, "yes", "y", false, "Unlock without asking for confirmation")
+
+	packagesCmd.AddCommand(packagesListCmd)
+	packagesCmd.AddCommand(packagesSearchCmd)
+	packagesCmd.AddCommand(packagesValidateCmd)
+	packagesCmd.AddCommand(packagesUpdateCmd)
+}
+
+// packagesProjectDir returns the --dir project, or the current directory.
+func packagesProjectDir() string {
+	if packagesDir != "" {
+		return packagesDir
+	}
+	dir, _ := os.Getwd()
+	return dir
+}
+
+// loadPackagesRegistry merges the user and team manifests into the registry
+// and returns the manifest problems.
+func loadPackagesRegistry() (*packageregistry.Registry, []error, error) {
+	return packageregistry.Load(userNanowaveDir(), packagesProjectDir())
+}
+
+// userNanowaveDir returns ~/nanowave, where the user package manifest lives.
+func userNanowaveDir() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, "nanowave")
+}
+
+func packagesListRun() error {
+	registry, skipped, err := loadPackagesRegistry()
+	if err != nil {
+		return err
+	}
+	for _, err := range skipped {
+		terminal.Warning(err.Error())
+	}
+	for _, cat := range registry.Categories() {
+		pkgs := registry.ByCategory(cat.Key)
+		if len(pkgs) == 0 {
+			continue
+		}
+		terminal.Header(cat.Label)
+		for _, pkg := range pkgs {
+			printPackage(pkg)
+		}
+	}
+	fmt.Println()
+	return nil
+}
+
+func packagesSearchRun(query string) error {
+	registry, skipped, err := loadPackagesRegistry()
+	if err != nil {
+		return err
+	}
+	for _, err := range skipped {
+		terminal.Warning(err.Error())
+	}
+	matches := registry.Search(query)
+	if len(matches) == 0 {
+		terminal.Info(fmt.Sprintf("No packages match %q.", query))
+		return nil
+	}
+	terminal.Header(fmt.Sprintf("Packages matching %q", query))
+	for i := range matches {
+		printPackage(&matches[i])
+	}
+	fmt.Println()
+	return nil
+}
+
+func packagesValidateRun() error {
+	registry, errs, err := loadPackagesRegistry()
+	if err != nil {
+		return err
+	}
+	for _, err := range errs {
+		terminal.Error(err.Error())
+	}
+
+	checked := 0
+	if packagesFixtures != "" {
+		for _, pkg := range registry.Packages() {
+			source, ok := readPackageFixture(packagesFixtures, pkg)
+			if !ok {
+				continue
+			}
+			checked++
+			if missing := packageregistry.MissingProducts(pkg, source); len(missing) > 0 {
+				err := fmt.Errorf("package %s declares products missing from its Package.swift: %s", pkg.Key, strings.Join(missing, ", "))
+				terminal.Error(err.Error())
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("package registry has %d problem(s)", len(errs))
+	}
+	msg := fmt.Sprintf("%d packages valid", len(registry.Packages()))
+	if packagesFixtures != "" {
+		msg += fmt.Sprintf(", %d checked against Package.swift fixtures", checked)
+	}
+	terminal.Success(msg)
+	return nil
+}
+
+func packagesUpdateRun(cmd *cobra.Command, names []string) error {
+	dir := packagesProjectDir()
+	if _, err := os.Stat(filepath.Join(dir, "project_config.json")); err != nil {
+		return fmt.Errorf("no project_config.json in %s; run update from a generated project or pass --dir", dir)
+	}
+
+	updates, err := projectconfig.CheckPackageUpdates(cmd.Context(), dir, names)
+	if err != nil {
+		return err
+	}
+	if len(updates) == 0 {
+		terminal.Info("No locked packages. Packages are locked after the first successful build.")
+		return nil
+	}
+
+	terminal.Header("Locked packages")
+	var unlock []string
+	for _, u := range updates {
+		switch {
+		case u.Changed():
+			fmt.Printf("  %s%s%s %s → %s%s%s\n", terminal.Bold, u.Name, terminal.Reset, u.Locked, terminal.Bold, u.Available, terminal.Reset)
+			unlock = append(unlock, u.Name)
+		case u.Available == "":
+			fmt.Printf("  %s%s%s %s %s(no release found within the requirement)%s\n", terminal.Bold, u.Name, terminal.Reset, u.Locked, terminal.Dim, terminal.Reset)
+		default:
+			fmt.Printf("  %s%s%s %s %s(up to date)%s\n", terminal.Bold, u.Name, terminal.Reset, u.Locked, terminal.Dim, terminal.Reset)
+		}
+	}
+	fmt.Println()
+	if len(unlock) == 0 {
+		terminal.Success("All locked packages are up to date")
+		return nil
+	}
+
+	if !packagesYes && !askConfirm(bufio.NewReader(os.Stdin), fmt.Sprintf("  Unlock %s?", strings.Join(unlock, ", "))) {
+		terminal.Info("Nothing changed.")
+		return nil
+	}
+
+	relocked, err := projectconfig.UnlockPackages(cmd.Context(), dir, unlock)
+	if err != nil {
+		return err
+	}
+	if len(relocked) > 0 {
+		terminal.Success(fmt.Sprintf("Resolved and locked %s", strings.Join(relocked, ", ")))
+		return nil
+	}
+	terminal.Success(fmt.Sprintf("Unlocked %s. The next successful build locks the new versions.", strings.Join(unlock, ", ")))
+	return nil
+}
+
+// readPackageFixture reads the Package.swift fixture of pkg, looked up by
+// repository name first and registry key second.
+func readPackageFixture(dir string, pkg packageregistry.Package) (string, bool) {
+	for _, name := range []string{pkg.RepoName, pkg.Key} {
+		data, err := os.ReadFile(filepath.Join(dir, name, "Package.swift"))
+		if err == nil {
+			return string(data), true
+		}
+	}
+	return "", false
+}
+
+func printPackage(pkg *packageregistry.Package) {
+	version := pkg.VersionSummary()
+	if len(pkg.Platforms) > 0 {
+		version += " · " + strings.Join(pkg.Platforms, ", ") + " only"
+	}
+	fmt.Printf("  %s%s%s %s(%s)%s\n", terminal.Bold, pkg.Name, terminal.Reset, terminal.Dim, version, terminal.Reset)
+	fmt.Printf("    %s\n", pkg.Description)
+	details := pkg.RepoURL
+	if pkg.Source != packageregistry.EmbeddedSource {
+		details += " · from " + pkg.Source
+	}
+	fmt.Printf("    %s%s%s\n", terminal.Dim, details, terminal.Reset)
+}
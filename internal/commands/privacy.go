@@ -0,0 +1,59 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/moasq/nanowave/internal/orchestration"
+	"github.com/moasq/nanowave/internal/privacy"
+	"github.com/moasq/nanowave/internal/terminal"
+	"github.com/spf13/cobra"
+)
+
+var privacyCmd = &cobra.Command{
+	Use:   "privacy",
+	Short: "Regenerate the privacy manifest and App Privacy answers",
+	Long: `Scans the project's Swift sources, permissions, packages and integrations
+for collected data and required-reason API usage, then writes
+<AppName>/PrivacyInfo.xcprivacy, the App Privacy questionnaire answers in
+privacy/app_privacy.json and the evidence behind each declaration in
+privacy/report.md.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dir := privacyDir
+		if dir == "" {
+			dir, _ = os.Getwd()
+		}
+		var ids []string
+		for _, ap := range newCmdManager().ResolveExisting(orchestration.ReadProjectAppName(dir)) {
+			ids = append(ids, string(ap.Provider.ID()))
+		}
+		report, err := orchestration.WritePrivacyManifest(dir, ids)
+		if err != nil {
+			return err
+		}
+
+		terminal.Header("Privacy manifest")
+		if len(report.CollectedData) == 0 {
+			terminal.Success("No collected data found")
+		}
+		for _, line := range report.Summary() {
+			terminal.Info(line)
+		}
+		for _, api := range report.AccessedAPIs {
+			terminal.Detail(strings.TrimPrefix(api.Category, "NSPrivacyAccessedAPICategory"), strings.Join(api.Reasons, ", "))
+		}
+		if report.Tracking {
+			terminal.Warning("Tracking detected — the app must request App Tracking Transparency permission")
+		}
+		terminal.Success(fmt.Sprintf("Wrote %s, %s and %s", privacy.ManifestFile, privacy.AppPrivacyFile, privacy.ReportFile))
+		return nil
+	},
+}
+
+// privacyDir is the project to analyze.
+var privacyDir string
+
+func init() {
+	privacyCmd.Flags().StringVar(&privacyDir, "dir", "", "Project directory (default: current directory)")
+}
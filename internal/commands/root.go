@@ -48,7 +48,15 @@ func init() {
 	rootCmd.AddCommand(mcpCmd)
 	rootCmd.AddCommand(usageCmd)
 	rootCmd.AddCommand(integrationsCmd)
+	rootCmd.AddCommand(secretsCmd)
 	rootCmd.AddCommand(publishCmd)
+	rootCmd.AddCommand(adoptCmd)
+	rootCmd.AddCommand(packagesCmd)
+	rootCmd.AddCommand(screenshotsCmd)
+	rootCmd.AddCommand(metadataCmd)
+	rootCmd.AddCommand(privacyCmd)
+	rootCmd.AddCommand(versionCmd)
+	rootCmd.AddCommand(testflightCmd)
 }
 
 // modelFlag holds the --model flag value.
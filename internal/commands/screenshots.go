@@ -0,0 +1,59 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/moasq/nanowave/internal/orchestration"
+	"github.com/moasq/nanowave/internal/screenshots"
+	"github.com/moasq/nanowave/internal/terminal"
+	"github.com/spf13/cobra"
+)
+
+var screenshotsCmd = &cobra.Command{
+	Use:   "screenshots",
+	Short: "Prepare App Store screenshots",
+}
+
+var screenshotsFrameCmd = &cobra.Command{
+	Use:   "frame",
+	Short: "Frame raw captures with a background, captions and a device bezel",
+	Long: `Frame composes the simulator captures in screenshots/auto into
+screenshots/nanowave at the exact App Store Connect size of each device type.
+
+The layout lives in screenshots/layout.json: the background gradient, the
+caption style, whether to draw a device bezel, and one entry per screen with
+its captions by locale. The default locale is written to screenshots/nanowave,
+every other captioned locale to screenshots/nanowave/<locale>. The directory is
+replaced on every run; screenshots/framed, for screenshots framed by hand, is
+left alone. The layout is created from the design palette on first use.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return screenshotsFrameRun()
+	},
+}
+
+// screenshotsDir is the project whose screenshots are framed.
+var screenshotsDir string
+
+func init() {
+	screenshotsCmd.PersistentFlags().StringVar(&screenshotsDir, "dir", "", "Project directory (default: current directory)")
+	screenshotsCmd.AddCommand(screenshotsFrameCmd)
+}
+
+func screenshotsFrameRun() error {
+	dir := screenshotsDir
+	if dir == "" {
+		dir, _ = os.Getwd()
+	}
+	written, err := orchestration.FrameScreenshots(dir)
+	if err != nil {
+		return err
+	}
+	if len(written) == 0 {
+		terminal.Info(fmt.Sprintf("No captures to frame. Add screens to %s or capture into screenshots/auto.", screenshots.LayoutFile))
+		return nil
+	}
+	terminal.Success(fmt.Sprintf("Framed %d screenshot(s) into %s", len(written), filepath.Join(dir, screenshots.FramedDir)))
+	return nil
+}
@@ -0,0 +1,195 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/moasq/nanowave/internal/integrations/secrets"
+	"github.com/moasq/nanowave/internal/terminal"
+	"github.com/spf13/cobra"
+)
+
+var secretsCmd = &cobra.Command{
+	Use:   "secrets",
+	Short: "Manage where integration credentials are stored",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return secretsListRun()
+	},
+}
+
+var secretsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Show the active secret backend and stored credentials",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return secretsListRun()
+	},
+}
+
+var secretsUseCmd = &cobra.Command{
+	Use:   "use [backend]",
+	Short: "Select the secret backend (auto, keychain, file, encrypted-file, env, 1password)",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return secretsUseRun(args[0])
+	},
+}
+
+var secretsMigrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Copy credentials from one secret backend to another",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return secretsMigrateRun()
+	},
+}
+
+// secretsMigrateFrom is the source backend; empty means the currently configured one.
+var secretsMigrateFrom string
+
+// secretsMigrateTo is the destination backend.
+var secretsMigrateTo string
+
+// secretsMigrateDelete removes migrated secrets from the source backend.
+var secretsMigrateDelete bool
+
+// secretsOPVault is the 1Password vault for the 1password backend.
+var secretsOPVault string
+
+func init() {
+	secretsUseCmd.Flags().StringVar(&secretsOPVault, "op-vault", "", "1Password vault for the 1password backend (default: nanowave)")
+
+	secretsMigrateCmd.Flags().StringVar(&secretsMigrateFrom, "from", "", "Source backend (default: the configured backend)")
+	secretsMigrateCmd.Flags().StringVar(&secretsMigrateTo, "to", "", "Destination backend (keychain, file, encrypted-file)")
+	secretsMigrateCmd.Flags().BoolVar(&secretsMigrateDelete, "delete-source", false, "Remove secrets from the source backend after copying")
+	_ = secretsMigrateCmd.MarkFlagRequired("to")
+
+	secretsCmd.AddCommand(secretsListCmd)
+	secretsCmd.AddCommand(secretsUseCmd)
+	secretsCmd.AddCommand(secretsMigrateCmd)
+}
+
+// backendLabel renders a backend name for display.
+func backendLabel(b secrets.Backend) string {
+	if b == secrets.BackendAuto {
+		return "auto (keychain, file fallback)"
+	}
+	return string(b)
+}
+
+func secretsListRun() error {
+	root := nanowaveRoot()
+	cfg, err := secrets.LoadConfig(root)
+	if err != nil {
+		return err
+	}
+	ss, err := secrets.Open(root, cfg.Backend, cfg)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println()
+	terminal.Header("Secrets")
+	terminal.Detail("Backend", backendLabel(cfg.Backend))
+
+	keys := loadIntegrationStore().SecretKeys()
+	if len(keys) == 0 {
+		terminal.Info("No stored credentials. Run: nanowave integrations setup supabase")
+		fmt.Println()
+		return nil
+	}
+	fmt.Println()
+	var readErr error
+	for _, key := range keys {
+		mark := terminal.Green + "✓" + terminal.Reset
+		if _, err := ss.Get(key); err != nil {
+			mark = terminal.Red + "✗" + terminal.Reset
+			if !errors.Is(err, secrets.ErrNotFound) && readErr == nil {
+				readErr = err
+			}
+		}
+		fmt.Printf("  %s %-40s %s%s%s\n", mark, key, terminal.Dim, secrets.Location(cfg.Backend, cfg, key), terminal.Reset)
+	}
+	fmt.Println()
+	if readErr != nil {
+		// Not a missing credential: the backend itself could not be read.
+		terminal.Warning(readErr.Error())
+		fmt.Println()
+	}
+	return nil
+}
+
+func secretsUseRun(name string) error {
+	backend, err := secrets.ParseBackend(name)
+	if err != nil {
+		return err
+	}
+	root := nanowaveRoot()
+	cfg, err := secrets.LoadConfig(root)
+	if err != nil {
+		return err
+	}
+	cfg.Backend = backend
+	if secretsOPVault != "" {
+		cfg.OnePasswordVault = secretsOPVault
+	}
+	if err := secrets.SaveConfig(root, cfg); err != nil {
+		return err
+	}
+	terminal.Success(fmt.Sprintf("Secret backend set to %s", backendLabel(backend)))
+	if backend == secrets.BackendEncryptedFile {
+		terminal.Info("Set NANOWAVE_SECRETS_PASSPHRASE (or NANOWAVE_SECRETS_KEY) before running nanowave")
+	}
+	if keys := loadIntegrationStore().SecretKeys(); len(keys) > 0 {
+		terminal.Info("Existing credentials were not moved. Run: nanowave secrets migrate --from <old> --to " + string(backend))
+	}
+	return nil
+}
+
+func secretsMigrateRun() error {
+	root := nanowaveRoot()
+	cfg, err := secrets.LoadConfig(root)
+	if err != nil {
+		return err
+	}
+
+	from := cfg.Backend
+	if secretsMigrateFrom != "" {
+		if from, err = secrets.ParseBackend(secretsMigrateFrom); err != nil {
+			return err
+		}
+	}
+	to, err := secrets.ParseBackend(secretsMigrateTo)
+	if err != nil {
+		return err
+	}
+	if from == to {
+		return fmt.Errorf("source and destination are both %s", backendLabel(from))
+	}
+
+	src, err := secrets.Open(root, from, cfg)
+	if err != nil {
+		return err
+	}
+	dst, err := secrets.Open(root, to, cfg)
+	if err != nil {
+		return err
+	}
+
+	keys := loadIntegrationStore().SecretKeys()
+	copied, missing, err := secrets.Migrate(src, dst, keys, secretsMigrateDelete)
+	if errors.Is(err, secrets.ErrReadOnly) {
+		return fmt.Errorf("%s is read-only — add the credentials there directly, then run: nanowave secrets use %s", to, to)
+	}
+	if err != nil {
+		return err
+	}
+	for _, key := range missing {
+		terminal.Warning(fmt.Sprintf("%s not found in %s (skipped)", key, backendLabel(from)))
+	}
+
+	cfg.Backend = to
+	if err := secrets.SaveConfig(root, cfg); err != nil {
+		return err
+	}
+	terminal.Success(fmt.Sprintf("Migrated %d secret(s) from %s to %s", copied, backendLabel(from), backendLabel(to)))
+	return nil
+}
@@ -0,0 +1,320 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/moasq/nanowave/internal/asc"
+	"github.com/moasq/nanowave/internal/orchestration"
+	"github.com/moasq/nanowave/internal/projectconfig"
+	"github.com/moasq/nanowave/internal/terminal"
+	"github.com/moasq/nanowave/internal/testflight"
+	"github.com/spf13/cobra"
+)
+
+var testflightCmd = &cobra.Command{
+	Use:   "testflight",
+	Short: "Manage TestFlight groups, testers, builds and feedback",
+	Long: `Works on the App Store Connect app the project was linked to by its first
+publish. Groups are named or given by ID. Internal groups hold App Store
+Connect users and receive every build; external groups only see the builds
+assigned to them, which assign submits for beta review.
+
+Tester CSV files have one tester per row: email, first name, last name. The
+names are optional, as is a header row (email,first_name,last_name).`,
+}
+
+var testflightGroupsCmd = &cobra.Command{
+	Use:   "groups",
+	Short: "List the app's TestFlight groups and their testers",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runTestflight(cmd, func(ctx context.Context, client *asc.Client, appID string) error {
+			groups, err := client.ListBetaGroups(ctx, appID)
+			if err != nil {
+				return err
+			}
+			terminal.Header("TestFlight groups")
+			if len(groups) == 0 {
+				terminal.Info("No groups yet; create one with `nanowave testflight groups create <name>`")
+				return nil
+			}
+			for _, g := range groups {
+				testers, err := client.ListGroupTesters(ctx, g.ID)
+				if err != nil {
+					return err
+				}
+				kind := "external"
+				if g.Internal {
+					kind = "internal"
+				}
+				desc := fmt.Sprintf("%s, %d tester(s)", kind, len(testers))
+				if g.PublicLinkEnabled && g.PublicLink != "" {
+					desc += ", " + g.PublicLink
+				}
+				terminal.Detail(g.Name, desc)
+			}
+			return nil
+		})
+	},
+}
+
+var testflightGroupsCreateCmd = &cobra.Command{
+	Use:   "create <name>",
+	Short: "Create a TestFlight group",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runTestflight(cmd, func(ctx context.Context, client *asc.Client, appID string) error {
+			groups, err := client.ListBetaGroups(ctx, appID)
+			if err != nil {
+				return err
+			}
+			if _, ok := testflight.FindGroup(groups, args[0]); ok {
+				return fmt.Errorf("group %q already exists", args[0])
+			}
+			group, err := client.CreateBetaGroup(ctx, appID, args[0], testflightInternal)
+			if err != nil {
+				return err
+			}
+			kind := "external"
+			if group.Internal {
+				kind = "internal"
+			}
+			terminal.Success(fmt.Sprintf("Created %s group %s", kind, group.Name))
+			return nil
+		})
+	},
+}
+
+var testflightTestersCmd = &cobra.Command{
+	Use:   "testers",
+	Short: "Add or remove the testers listed in a CSV file",
+}
+
+var testflightTestersAddCmd = &cobra.Command{
+	Use:   "add <group> <testers.csv>",
+	Short: "Add testers to a group, inviting new ones",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return changeTesters(cmd, args, testflight.AddTesters)
+	},
+}
+
+var testflightTestersRemoveCmd = &cobra.Command{
+	Use:   "remove <group> <testers.csv>",
+	Short: "Remove testers from a group",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return changeTesters(cmd, args, testflight.RemoveTesters)
+	},
+}
+
+var testflightAssignCmd = &cobra.Command{
+	Use:   "assign <group>",
+	Short: "Make a build available to a group (default: the project's build number)",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runTestflight(cmd, func(ctx context.Context, client *asc.Client, appID string) error {
+			groups, err := client.ListBetaGroups(ctx, appID)
+			if err != nil {
+				return err
+			}
+			group, ok := testflight.FindGroup(groups, args[0])
+			if !ok {
+				return fmt.Errorf("no TestFlight group %q", args[0])
+			}
+			build, err := testflightBuild(ctx, client, appID)
+			if err != nil {
+				return err
+			}
+			if build.ProcessingState != "" && build.ProcessingState != "VALID" {
+				terminal.Warning(fmt.Sprintf("Build %s is %s; testers get it once processing finishes", build.Version, build.ProcessingState))
+			}
+			if err := client.AddBuildsToGroup(ctx, group.ID, build.ID); err != nil {
+				return err
+			}
+			terminal.Success(fmt.Sprintf("Build %s assigned to %s", build.Version, group.Name))
+			if group.Internal {
+				return nil
+			}
+			if err := client.SubmitForBetaReview(ctx, build.ID); err != nil {
+				return fmt.Errorf("submit build %s for beta review: %w", build.Version, err)
+			}
+			terminal.Info("Build submitted for beta review; external testers are notified once it passes")
+			return nil
+		})
+	},
+}
+
+var testflightNotesCmd = &cobra.Command{
+	Use:   "notes <text>",
+	Short: `Set a build's "What to Test" notes (default: the project's build number)`,
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runTestflight(cmd, func(ctx context.Context, client *asc.Client, appID string) error {
+			build, err := testflightBuild(ctx, client, appID)
+			if err != nil {
+				return err
+			}
+			if _, err := client.SetWhatToTest(ctx, build.ID, testflightLocale, args[0]); err != nil {
+				return err
+			}
+			terminal.Success(fmt.Sprintf("What to Test for build %s (%s) updated", build.Version, testflightLocale))
+			return nil
+		})
+	},
+}
+
+var testflightFeedbackCmd = &cobra.Command{
+	Use:   "feedback",
+	Short: "Pull tester feedback and crash reports into " + testflight.FeedbackDir,
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runTestflight(cmd, func(ctx context.Context, client *asc.Client, appID string) error {
+			var buildID, number string
+			if testflightBuildNumber > 0 {
+				build, err := testflightBuild(ctx, client, appID)
+				if err != nil {
+					return err
+				}
+				buildID, number = build.ID, build.Version
+			}
+			feedback, err := testflight.PullFeedback(ctx, client, appID, buildID, number)
+			if err != nil {
+				return err
+			}
+			path, err := feedback.Write(ctx, testflightProjectDir(), &http.Client{Timeout: time.Minute})
+			if err != nil {
+				return err
+			}
+			terminal.Success(fmt.Sprintf("%d feedback and %d crash submission(s) saved to %s", len(feedback.Screenshots), len(feedback.Crashes), path))
+			return nil
+		})
+	},
+}
+
+var (
+	// testflightDir is the project whose App Store Connect app is managed.
+	testflightDir string
+	// testflightInternal creates an internal group instead of an external one.
+	testflightInternal bool
+	// testflightBuildNumber selects a build other than the project's current one.
+	testflightBuildNumber int
+	// testflightLocale is the locale of "What to Test" notes.
+	testflightLocale string
+)
+
+func init() {
+	testflightCmd.PersistentFlags().StringVar(&testflightDir, "dir", "", "Project directory (default: current directory)")
+	testflightGroupsCreateCmd.Flags().BoolVar(&testflightInternal, "internal", false, "Create an internal group of App Store Connect users")
+	for _, c := range []*cobra.Command{testflightAssignCmd, testflightNotesCmd, testflightFeedbackCmd} {
+		c.Flags().IntVar(&testflightBuildNumber, "build", 0, "Build number")
+	}
+	testflightNotesCmd.Flags().StringVar(&testflightLocale, "locale", "en-US", "Locale of the notes")
+
+	testflightGroupsCmd.AddCommand(testflightGroupsCreateCmd)
+	testflightTestersCmd.AddCommand(testflightTestersAddCmd)
+	testflightTestersCmd.AddCommand(testflightTestersRemoveCmd)
+	testflightCmd.AddCommand(testflightGroupsCmd)
+	testflightCmd.AddCommand(testflightTestersCmd)
+	testflightCmd.AddCommand(testflightAssignCmd)
+	testflightCmd.AddCommand(testflightNotesCmd)
+	testflightCmd.AddCommand(testflightFeedbackCmd)
+}
+
+func testflightProjectDir() string {
+	if testflightDir != "" {
+		return testflightDir
+	}
+	dir, _ := os.Getwd()
+	return dir
+}
+
+// runTestflight calls fn with a client for the project's linked app.
+func runTestflight(cmd *cobra.Command, fn func(ctx context.Context, client *asc.Client, appID string) error) error {
+	ctx, cancel := signal.NotifyContext(cmd.Context(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+
+	client, appID, err := orchestration.LinkedASCApp(testflightProjectDir())
+	if err != nil {
+		return err
+	}
+	return fn(ctx, client, appID)
+}
+
+// testflightBuild returns the build selected by --build, or the uploaded
+// build matching the project's current marketing version and build number.
+// A number given by --build may belong to an earlier marketing version, so
+// its most recent upload is used.
+func testflightBuild(ctx context.Context, client *asc.Client, appID string) (asc.Build, error) {
+	var marketing string
+	number := testflightBuildNumber
+	if number == 0 {
+		version, current, err := projectconfig.ProjectVersion(testflightProjectDir())
+		if err != nil {
+			return asc.Build{}, err
+		}
+		marketing, number = version, current
+	}
+	build, ok, err := client.FindBuild(ctx, appID, marketing, strconv.Itoa(number))
+	if err != nil {
+		return asc.Build{}, err
+	}
+	if !ok {
+		return asc.Build{}, fmt.Errorf("build %d has not been uploaded to App Store Connect; pass --build or run `nanowave publish`", number)
+	}
+	return build, nil
+}
+
+// changeTesters applies a tester change to the group and CSV file in args.
+func changeTesters(cmd *cobra.Command, args []string, change func(context.Context, *asc.Client, string, []testflight.Tester) (*testflight.Change, error)) error {
+	f, err := os.Open(args[1])
+	if err != nil {
+		return err
+	}
+	testers, err := testflight.ParseTesters(f)
+	f.Close()
+	if err != nil {
+		return fmt.Errorf("%s: %w", args[1], err)
+	}
+	if len(testers) == 0 {
+		return fmt.Errorf("%s lists no testers", args[1])
+	}
+
+	return runTestflight(cmd, func(ctx context.Context, client *asc.Client, appID string) error {
+		groups, err := client.ListBetaGroups(ctx, appID)
+		if err != nil {
+			return err
+		}
+		group, ok := testflight.FindGroup(groups, args[0])
+		if !ok {
+			return fmt.Errorf("no TestFlight group %q", args[0])
+		}
+		result, err := change(ctx, client, group.ID, testers)
+		if result != nil {
+			for _, email := range result.Invited {
+				terminal.Detail(email, "invited")
+			}
+			for _, email := range result.Added {
+				terminal.Detail(email, "added")
+			}
+			for _, email := range result.Removed {
+				terminal.Detail(email, "removed")
+			}
+			for _, email := range result.Unknown {
+				terminal.Warning(fmt.Sprintf("%s is not a tester of this account", email))
+			}
+		}
+		if err != nil {
+			return err
+		}
+		terminal.Success(fmt.Sprintf("Group %s updated", group.Name))
+		return nil
+	})
+}
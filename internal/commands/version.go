@@ -0,0 +1,121 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/moasq/nanowave/internal/projectconfig"
+	"github.com/moasq/nanowave/internal/storage"
+	"github.com/moasq/nanowave/internal/terminal"
+	"github.com/moasq/nanowave/internal/versioning"
+	"github.com/spf13/cobra"
+)
+
+var versionCmd = &cobra.Command{
+	Use:   "version",
+	Short: "Show the app's version, build number and release history",
+	Long: `The app's marketing version (MARKETING_VERSION) and build number
+(CURRENT_PROJECT_VERSION) live in project_config.json and apply to every
+target. Publishing raises the build number above the builds already in App
+Store Connect and records each release with the commits since the last one.
+nanowave's own version is printed by ` + "`nanowave --version`" + `.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dir := versionProjectDir()
+		marketing, build, err := projectconfig.ProjectVersion(dir)
+		if err != nil {
+			return err
+		}
+		terminal.Header(fmt.Sprintf("Version %s (build %d)", marketing, build))
+
+		project, err := storage.NewProjectStore(filepath.Join(dir, ".nanowave")).Load()
+		if err != nil {
+			return err
+		}
+		if project == nil || len(project.Releases) == 0 {
+			terminal.Info("No releases published yet")
+			return nil
+		}
+		for i := len(project.Releases) - 1; i >= 0; i-- {
+			r := project.Releases[i]
+			terminal.Detail(fmt.Sprintf("%s (%d)", r.Version, r.BuildNumber), r.CreatedAt.Format("2006-01-02"))
+			for _, entry := range r.Changelog {
+				fmt.Printf("      - %s\n", entry)
+			}
+		}
+		return nil
+	},
+}
+
+var versionBumpCmd = &cobra.Command{
+	Use:       "bump <major|minor|patch>",
+	Short:     "Increment the marketing version",
+	Args:      cobra.ExactArgs(1),
+	ValidArgs: []string{versioning.Major, versioning.Minor, versioning.Patch},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dir := versionProjectDir()
+		marketing, build, err := projectconfig.ProjectVersion(dir)
+		if err != nil {
+			return err
+		}
+		next, err := versioning.Bump(marketing, strings.ToLower(args[0]))
+		if err != nil {
+			return err
+		}
+		if err := projectconfig.SetProjectVersion(dir, next, build); err != nil {
+			return err
+		}
+		terminal.Success(fmt.Sprintf("Version %s → %s (build %d)", marketing, next, build))
+		return nil
+	},
+}
+
+var versionSetCmd = &cobra.Command{
+	Use:   "set [marketing-version]",
+	Short: "Set the marketing version and/or build number",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) == 0 && versionBuild == 0 {
+			return fmt.Errorf("give a marketing version, --build, or both")
+		}
+		dir := versionProjectDir()
+		marketing, build, err := projectconfig.ProjectVersion(dir)
+		if err != nil {
+			return err
+		}
+		if len(args) == 1 {
+			marketing = args[0]
+		}
+		if versionBuild > 0 {
+			build = versionBuild
+		}
+		if err := projectconfig.SetProjectVersion(dir, marketing, build); err != nil {
+			return err
+		}
+		terminal.Success(fmt.Sprintf("Version %s (build %d)", marketing, build))
+		return nil
+	},
+}
+
+var (
+	// versionDir is the project whose version is managed.
+	versionDir string
+	// versionBuild is the build number `version set` writes.
+	versionBuild int
+)
+
+func init() {
+	versionCmd.PersistentFlags().StringVar(&versionDir, "dir", "", "Project directory (default: current directory)")
+	versionSetCmd.Flags().IntVar(&versionBuild, "build", 0, "Build number (CURRENT_PROJECT_VERSION)")
+	versionCmd.AddCommand(versionBumpCmd)
+	versionCmd.AddCommand(versionSetCmd)
+}
+
+func versionProjectDir() string {
+	if versionDir != "" {
+		return versionDir
+	}
+	dir, _ := os.Getwd()
+	return dir
+}
@@ -74,6 +74,7 @@ func (c *Config) SetProject(projectPath string) {
 }
 
 // ListProjects scans the catalog for valid projects (dirs with .nanowave/project.json).
+// Symlinked entries (adopted projects living outside the catalog) are followed.
 func (c *Config) ListProjects() []ProjectInfo {
 	catalogRoot := c.CatalogRoot()
 
@@ -85,7 +86,12 @@ func (c *Config) ListProjects() []ProjectInfo {
 	var projects []ProjectInfo
 	for _, entry := range entries {
 		if !entry.IsDir() {
-			continue
+			if entry.Type()&os.ModeSymlink == 0 {
+				continue
+			}
+			if target, err := os.Stat(filepath.Join(catalogRoot, entry.Name())); err != nil || !target.IsDir() {
+				continue
+			}
 		}
 		projDir := filepath.Join(catalogRoot, entry.Name())
 		projectJSON := filepath.Join(projDir, ".nanowave", "project.json")
@@ -108,6 +114,34 @@ func (c *Config) ListProjects() []ProjectInfo {
 	return projects
 }
 
+// LinkProject adds a project that lives outside the catalog by symlinking it
+// into the catalog root. It returns the catalog path of the project.
+func (c *Config) LinkProject(projectPath string) (string, error) {
+	abs, err := filepath.Abs(projectPath)
+	if err != nil {
+		return "", err
+	}
+	catalogRoot := c.CatalogRoot()
+	if filepath.Dir(abs) == catalogRoot {
+		return abs, nil
+	}
+	if err := os.MkdirAll(catalogRoot, 0o755); err != nil {
+		return "", fmt.Errorf("failed to create project catalog: %w", err)
+	}
+
+	link := filepath.Join(catalogRoot, filepath.Base(abs))
+	if existing, err := os.Readlink(link); err == nil && existing == abs {
+		return link, nil
+	}
+	if _, err := os.Lstat(link); err == nil {
+		return "", fmt.Errorf("a project named %q is already in the catalog", filepath.Base(abs))
+	}
+	if err := os.Symlink(abs, link); err != nil {
+		return "", fmt.Errorf("failed to link project into the catalog: %w", err)
+	}
+	return link, nil
+}
+
 // CatalogRoot returns the project catalog root (~/nanowave/projects/).
 // This is the original ProjectDir before SetProject() is called.
 func (c *Config) CatalogRoot() string {
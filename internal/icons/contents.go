@@ -9,11 +9,16 @@ import (
 	"strings"
 )
 
-// UpdateContentsJSON generates all required icon sizes from the source image
-// using macOS sips, then writes a complete Contents.json referencing every size.
-func UpdateContentsJSON(appIconDir, iconFilename, platform string) error {
+// UpdateContentsJSON generates every icon the platform needs from the source
+// image and writes the asset's Contents.json. iOS and watchOS get the
+// single-size format when deploymentTarget allows it (empty means nanowave's
+// own target) and one PNG per size otherwise; macOS always gets every size.
+// Dark and tinted variants already in a single-size iOS appiconset are kept.
+// On tvOS and visionOS the appiconset is replaced by a layered AppIcon brand
+// asset or image stack next to it.
+func UpdateContentsJSON(appIconDir, iconFilename, platform, deploymentTarget string) error {
 	sourcePath := filepath.Join(appIconDir, iconFilename)
-	log.Printf("[asc][icon] UpdateContentsJSON: dir=%s source=%s platform=%s", appIconDir, iconFilename, platform)
+	log.Printf("[asc][icon] UpdateContentsJSON: dir=%s source=%s platform=%s deployment=%s", appIconDir, iconFilename, platform, deploymentTarget)
 
 	// Verify source exists and get its dimensions
 	srcInfo, srcErr := os.Stat(sourcePath)
@@ -23,12 +28,42 @@ func UpdateContentsJSON(appIconDir, iconFilename, platform string) error {
 	}
 	log.Printf("[asc][icon] source icon: %s (%d bytes)", sourcePath, srcInfo.Size())
 
+	platform = strings.ToLower(platform)
+	switch platform {
+	case "tvos", "visionos":
+		return writeLayeredIcon(appIconDir, sourcePath, platform)
+	case "":
+		platform = "ios"
+	}
+
+	var specs []Spec
+	switch {
+	case SupportsSingleSize(platform, deploymentTarget):
+		specs = []Spec{singleSizeSpec(platform)}
+	case platform == "ios":
+		specs = IOSSpecs()
+	case platform == "watchos":
+		specs = WatchOSSpecs()
+	case platform == "macos":
+		specs = MacOSSpecs()
+	default:
+		return fmt.Errorf("unsupported icon platform %q", platform)
+	}
+	log.Printf("[asc][icon] generating %d icon sizes for %s", len(specs), platform)
+
+	type appearance struct {
+		Appearance string `json:"appearance"`
+		Value      string `json:"value"`
+	}
 	type imageEntry struct {
-		Filename string `json:"filename,omitempty"`
-		Idiom    string `json:"idiom"`
-		Platform string `json:"platform,omitempty"`
-		Size     string `json:"size"`
-		Scale    string `json:"scale,omitempty"`
+		Appearances []appearance `json:"appearances,omitempty"`
+		Filename    string       `json:"filename,omitempty"`
+		Idiom       string       `json:"idiom"`
+		Platform    string       `json:"platform,omitempty"`
+		Role        string       `json:"role,omitempty"`
+		Subtype     string       `json:"subtype,omitempty"`
+		Size        string       `json:"size"`
+		Scale       string       `json:"scale,omitempty"`
 	}
 	type contentsJSON struct {
 		Images []imageEntry `json:"images"`
@@ -39,61 +74,35 @@ func UpdateContentsJSON(appIconDir, iconFilename, platform string) error {
 	}
 
 	var entries []imageEntry
-
-	switch strings.ToLower(platform) {
-	case "ios", "":
-		specs := IOSSpecs()
-		log.Printf("[asc][icon] generating %d icon sizes for iOS", len(specs))
-		for _, spec := range specs {
-			destPath := filepath.Join(appIconDir, spec.Filename)
-			// Copy source for 1024, resize for others
-			if spec.Pixels == 1024 {
-				if err := CopyFile(sourcePath, destPath); err != nil {
-					return fmt.Errorf("failed to copy icon for %s: %w", spec.Size, err)
-				}
-				log.Printf("[asc][icon]   copied: %s (1024x1024)", spec.Filename)
-			} else {
-				if err := Resize(sourcePath, destPath, spec.Pixels); err != nil {
-					return fmt.Errorf("failed to resize icon to %dx%d: %w", spec.Pixels, spec.Pixels, err)
-				}
-				log.Printf("[asc][icon]   resized: %s (%dx%d)", spec.Filename, spec.Pixels, spec.Pixels)
+	for _, spec := range specs {
+		destPath := filepath.Join(appIconDir, spec.Filename)
+		if err := Resize(sourcePath, destPath, spec.Pixels); err != nil {
+			return fmt.Errorf("failed to resize icon to %dx%d: %w", spec.Pixels, spec.Pixels, err)
+		}
+		log.Printf("[asc][icon]   resized: %s (%dx%d)", spec.Filename, spec.Pixels, spec.Pixels)
+		entries = append(entries, imageEntry{
+			Filename: spec.Filename,
+			Idiom:    spec.Idiom,
+			Platform: spec.Platform,
+			Role:     spec.Role,
+			Subtype:  spec.Subtype,
+			Size:     spec.Size,
+			Scale:    spec.Scale,
+		})
+	}
+	if platform == "ios" && len(specs) == 1 {
+		for _, v := range appearanceVariants {
+			if _, err := os.Stat(filepath.Join(appIconDir, v.filename)); err != nil {
+				continue
 			}
 			entries = append(entries, imageEntry{
-				Filename: spec.Filename,
-				Idiom:    spec.Idiom,
-				Size:     spec.Size,
-				Scale:    spec.Scale,
+				Appearances: []appearance{{Appearance: "luminosity", Value: v.luminosity}},
+				Filename:    v.filename,
+				Idiom:       specs[0].Idiom,
+				Platform:    specs[0].Platform,
+				Size:        specs[0].Size,
 			})
 		}
-	case "watchos":
-		// watchOS: universal 1024x1024 entry is sufficient
-		entries = append(entries, imageEntry{
-			Filename: iconFilename,
-			Idiom:    "universal",
-			Platform: "watchos",
-			Size:     "1024x1024",
-		})
-	case "tvos":
-		entries = append(entries, imageEntry{
-			Filename: iconFilename,
-			Idiom:    "tv",
-			Platform: "tvos",
-			Size:     "1280x768",
-		})
-	case "macos":
-		entries = append(entries, imageEntry{
-			Filename: iconFilename,
-			Idiom:    "mac",
-			Size:     "1024x1024",
-			Scale:    "1x",
-		})
-	case "visionos":
-		entries = append(entries, imageEntry{
-			Filename: iconFilename,
-			Idiom:    "universal",
-			Platform: "xros",
-			Size:     "1024x1024",
-		})
 	}
 
 	contents := contentsJSON{
@@ -119,3 +128,31 @@ func UpdateContentsJSON(appIconDir, iconFilename, platform string) error {
 	log.Printf("[asc][icon] Contents.json written successfully to %s (%d bytes)", outPath, len(data))
 	return nil
 }
+
+// writeLayeredIcon renders the tvOS brand asset or visionOS image stack from
+// the source and removes the appiconset it replaces. The source is decoded
+// first, so appIconDir may be the layered asset itself.
+func writeLayeredIcon(appIconDir, sourcePath, platform string) error {
+	src, err := decodeImage(sourcePath)
+	if err != nil {
+		return err
+	}
+	name, write := tvOSIconAsset, writeBrandAssets
+	if platform == "visionos" {
+		name, write = visionOSIconAsset, writeSolidImageStack
+	}
+	dir := filepath.Join(filepath.Dir(appIconDir), name)
+	if err := os.RemoveAll(dir); err != nil {
+		return err
+	}
+	if err := write(dir, src); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	if filepath.Clean(appIconDir) != dir {
+		if err := os.RemoveAll(appIconDir); err != nil {
+			return err
+		}
+	}
+	log.Printf("[asc][icon] %s written to %s", name, dir)
+	return nil
+}
@@ -0,0 +1,180 @@
+package icons
+
+import (
+	"encoding/json"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeTestIcon writes a PNG with an opaque gradient, or with a transparent
+// corner when alpha is set.
+func writeTestIcon(t *testing.T, path string, width, height int, alpha bool) {
+	t.Helper()
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			c := color.NRGBA{R: uint8(x * 255 / width), G: uint8(y * 255 / height), B: 128, A: 255}
+			if alpha && x < width/4 && y < height/4 {
+				c.A = 0
+			}
+			img.Set(x, y, c)
+		}
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if err := png.Encode(f, img); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func readContents(t *testing.T, dir string) map[string]any {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(dir, "Contents.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var contents map[string]any
+	if err := json.Unmarshal(data, &contents); err != nil {
+		t.Fatal(err)
+	}
+	return contents
+}
+
+func imageSize(t *testing.T, path string) (int, int) {
+	t.Helper()
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	cfg, err := png.DecodeConfig(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cfg.Width, cfg.Height
+}
+
+func TestUpdateContentsJSONSizes(t *testing.T) {
+	tests := []struct {
+		platform         string
+		deploymentTarget string
+		want             []Spec
+	}{
+		{platform: "ios", want: []Spec{singleSizeSpec("ios")}},
+		{platform: "ios", deploymentTarget: "11.0", want: IOSSpecs()},
+		{platform: "watchos", deploymentTarget: "26.0", want: []Spec{singleSizeSpec("watchos")}},
+		{platform: "watchos", deploymentTarget: "6.2", want: WatchOSSpecs()},
+		{platform: "macos", want: MacOSSpecs()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.platform+tt.deploymentTarget, func(t *testing.T) {
+			dir := filepath.Join(t.TempDir(), "AppIcon.appiconset")
+			if err := os.MkdirAll(dir, 0o755); err != nil {
+				t.Fatal(err)
+			}
+			writeTestIcon(t, filepath.Join(dir, "AppIcon.png"), 1024, 1024, false)
+
+			if err := UpdateContentsJSON(dir, "AppIcon.png", tt.platform, tt.deploymentTarget); err != nil {
+				t.Fatalf("UpdateContentsJSON() error: %v", err)
+			}
+			images := readContents(t, dir)["images"].([]any)
+			if len(images) != len(tt.want) {
+				t.Fatalf("Contents.json has %d images, want %d", len(images), len(tt.want))
+			}
+			for i, spec := range tt.want {
+				entry := images[i].(map[string]any)
+				if entry["filename"] != spec.Filename || entry["size"] != spec.Size || entry["idiom"] != spec.Idiom {
+					t.Errorf("images[%d] = %v, want %+v", i, entry, spec)
+				}
+				if w, h := imageSize(t, filepath.Join(dir, spec.Filename)); w != spec.Pixels || h != spec.Pixels {
+					t.Errorf("%s is %dx%d, want %d", spec.Filename, w, h, spec.Pixels)
+				}
+			}
+		})
+	}
+}
+
+func TestUpdateContentsJSONLayered(t *testing.T) {
+	for _, tt := range []struct {
+		platform string
+		asset    string
+		layer    string
+		width    int
+		height   int
+	}{
+		{platform: "tvos", asset: tvOSIconAsset, layer: "App Icon - App Store.imagestack/Back.imagestacklayer/Content.imageset/Content.png", width: 1280, height: 768},
+		{platform: "visionos", asset: visionOSIconAsset, layer: "Back.solidimagestacklayer/Content.imageset/Content@2x.png", width: 1024, height: 1024},
+	} {
+		t.Run(tt.platform, func(t *testing.T) {
+			catalog := t.TempDir()
+			appIconDir := filepath.Join(catalog, "AppIcon.appiconset")
+			if err := os.MkdirAll(appIconDir, 0o755); err != nil {
+				t.Fatal(err)
+			}
+			writeTestIcon(t, filepath.Join(appIconDir, "AppIcon.png"), 1024, 1024, false)
+
+			if err := UpdateContentsJSON(appIconDir, "AppIcon.png", tt.platform, ""); err != nil {
+				t.Fatalf("UpdateContentsJSON() error: %v", err)
+			}
+			if _, err := os.Stat(appIconDir); !os.IsNotExist(err) {
+				t.Error("the appiconset should be replaced by the layered asset")
+			}
+			found, err := FindAppIconDir(catalog)
+			if err != nil || filepath.Base(found) != tt.asset {
+				t.Fatalf("FindAppIconDir() = %q, %v; want %s", found, err, tt.asset)
+			}
+			if w, h := imageSize(t, filepath.Join(found, tt.layer)); w != tt.width || h != tt.height {
+				t.Errorf("%s is %dx%d, want %dx%d", tt.layer, w, h, tt.width, tt.height)
+			}
+
+			// Regenerating from a layer of the asset itself keeps a complete asset.
+			src := FindSourceIcon(found)
+			rel, _ := filepath.Rel(found, src)
+			if err := UpdateContentsJSON(found, rel, tt.platform, ""); err != nil {
+				t.Fatalf("UpdateContentsJSON(layered) error: %v", err)
+			}
+			if !HasExisting(found) {
+				t.Error("regenerated layered asset has no images")
+			}
+		})
+	}
+}
+
+func TestValidateAppStoreIcon(t *testing.T) {
+	dir := t.TempDir()
+	valid := filepath.Join(dir, "valid.png")
+	writeTestIcon(t, valid, 1024, 1024, false)
+	if problems := ValidateAppStoreIcon(valid); len(problems) > 0 {
+		t.Errorf("ValidateAppStoreIcon(valid) = %v, want none", problems)
+	}
+
+	resized := filepath.Join(dir, "resized.png")
+	if err := Resize(valid, resized, 1024); err != nil {
+		t.Fatal(err)
+	}
+	if problems := ValidateAppStoreIcon(resized); len(problems) > 0 {
+		t.Errorf("resizing an opaque icon must not add an alpha channel: %v", problems)
+	}
+
+	for name, icon := range map[string]struct {
+		width, height int
+		alpha         bool
+	}{
+		"alpha.png": {1024, 1024, true},
+		"small.png": {512, 512, false},
+		"wide.png":  {1024, 768, false},
+	} {
+		path := filepath.Join(dir, name)
+		writeTestIcon(t, path, icon.width, icon.height, icon.alpha)
+		if problems := ValidateAppStoreIcon(path); len(problems) == 0 {
+			t.Errorf("ValidateAppStoreIcon(%s) reported no problems", name)
+		}
+	}
+}
@@ -5,17 +5,22 @@ import (
 	"log"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 )
 
-// FindAppIconDir locates the AppIcon.appiconset directory in a project.
+// appIconAssets are the asset names an app icon may live in: the appiconset
+// the scaffold writes, or the layered tvOS and visionOS assets replacing it.
+var appIconAssets = []string{"AppIcon.appiconset", tvOSIconAsset, visionOSIconAsset}
+
+// FindAppIconDir locates the AppIcon asset directory in a project.
 func FindAppIconDir(projectDir string) (string, error) {
 	var found string
 	err := filepath.WalkDir(projectDir, func(path string, d os.DirEntry, err error) error {
 		if err != nil {
 			return nil
 		}
-		if d.IsDir() && d.Name() == "AppIcon.appiconset" {
+		if d.IsDir() && slices.Contains(appIconAssets, d.Name()) {
 			found = path
 			return filepath.SkipAll
 		}
@@ -33,27 +38,30 @@ func FindAppIconDir(projectDir string) (string, error) {
 	return found, nil
 }
 
-// FindSourceIcon returns the path to the largest PNG/JPG in the appiconset directory,
-// which is used as the source for generating all required icon sizes.
+// IsLayered reports whether the app icon directory is a layered tvOS brand
+// asset or visionOS image stack rather than an appiconset.
+func IsLayered(appIconDir string) bool {
+	return !strings.HasSuffix(appIconDir, ".appiconset")
+}
+
+// FindSourceIcon returns the path to the largest PNG/JPG in the app icon directory,
+// which is used as the source for generating all required icon sizes. Dark and
+// tinted appearance variants are never the source.
 func FindSourceIcon(appIconDir string) string {
-	entries, err := os.ReadDir(appIconDir)
-	if err != nil {
-		log.Printf("[asc][icon] FindSourceIcon: cannot read dir %s: %v", appIconDir, err)
-		return ""
-	}
 	var best string
 	var bestSize int64
-	for _, e := range entries {
-		name := strings.ToLower(e.Name())
-		if strings.HasSuffix(name, ".png") || strings.HasSuffix(name, ".jpg") || strings.HasSuffix(name, ".jpeg") {
-			info, _ := e.Info()
-			if info != nil {
-				log.Printf("[asc][icon] FindSourceIcon: candidate %s (%d bytes)", e.Name(), info.Size())
-				if info.Size() > bestSize {
-					bestSize = info.Size()
-					best = filepath.Join(appIconDir, e.Name())
-				}
-			}
+	for _, path := range iconImages(appIconDir) {
+		if isAppearanceVariant(path) {
+			continue
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+		log.Printf("[asc][icon] FindSourceIcon: candidate %s (%d bytes)", filepath.Base(path), info.Size())
+		if info.Size() > bestSize {
+			bestSize = info.Size()
+			best = path
 		}
 	}
 	if best != "" {
@@ -64,20 +72,42 @@ func FindSourceIcon(appIconDir string) string {
 	return best
 }
 
-// HasExisting checks if AppIcon.appiconset has an actual image file.
+// isAppearanceVariant reports whether path is a dark or tinted icon variant.
+func isAppearanceVariant(path string) bool {
+	for _, v := range appearanceVariants {
+		if filepath.Base(path) == v.filename {
+			return true
+		}
+	}
+	return false
+}
+
+// HasExisting checks if the app icon directory has an actual image file.
 func HasExisting(appIconDir string) bool {
-	entries, err := os.ReadDir(appIconDir)
-	if err != nil {
-		return false
+	for _, path := range iconImages(appIconDir) {
+		if info, err := os.Stat(path); err == nil && info.Size() > 0 {
+			return true
+		}
 	}
-	for _, e := range entries {
-		name := strings.ToLower(e.Name())
+	return false
+}
+
+// iconImages returns the PNG and JPEG files in the app icon directory,
+// including the layers of layered assets.
+func iconImages(appIconDir string) []string {
+	var images []string
+	err := filepath.WalkDir(appIconDir, func(path string, d os.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return nil
+		}
+		name := strings.ToLower(d.Name())
 		if strings.HasSuffix(name, ".png") || strings.HasSuffix(name, ".jpg") || strings.HasSuffix(name, ".jpeg") {
-			info, _ := e.Info()
-			if info != nil && info.Size() > 0 {
-				return true
-			}
+			images = append(images, path)
 		}
+		return nil
+	})
+	if err != nil {
+		log.Printf("[asc][icon] cannot read dir %s: %v", appIconDir, err)
 	}
-	return false
+	return images
 }
@@ -0,0 +1,145 @@
+package icons
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"log"
+	"os"
+	"path/filepath"
+
+	"golang.org/x/image/draw"
+	"golang.org/x/image/vector"
+)
+
+// Appearance variants of an iOS app icon, shown on iOS 18 and later next to the
+// default icon in the single-size appiconset.
+const (
+	darkIconFilename   = "Icon-1024-dark.png"
+	tintedIconFilename = "Icon-1024-tinted.png"
+)
+
+// appearanceVariants maps each appearance variant file to its luminosity value.
+var appearanceVariants = []struct{ filename, luminosity string }{
+	{darkIconFilename, "dark"},
+	{tintedIconFilename, "tinted"},
+}
+
+// glyphScale is the share of the icon's width the glyph's box takes up.
+const glyphScale = 0.54
+
+// IconStyle describes a generated app icon.
+type IconStyle struct {
+	AppName string
+	// Glyph names one of Glyphs(); empty draws a monogram of AppName.
+	Glyph   string
+	Primary color.RGBA
+	Accent  color.RGBA
+}
+
+// GenerateIcon renders an app icon from style into appIconDir and generates
+// every size the platform needs from it with UpdateContentsJSON. iOS icons in
+// the single-size format also get dark and tinted variants.
+func GenerateIcon(appIconDir, platform, deploymentTarget string, style IconStyle) error {
+	glyph, err := styleGlyph(style)
+	if err != nil {
+		return err
+	}
+	log.Printf("[asc][icon] generating %s icon for %q in %s", glyph.Name, style.AppName, appIconDir)
+
+	const source = "AppIcon.png"
+	if err := encodePNG(filepath.Join(appIconDir, source), renderIcon(glyph, style, "")); err != nil {
+		return err
+	}
+	RemoveAppearanceVariants(appIconDir)
+	if platform == "ios" && SupportsSingleSize(platform, deploymentTarget) {
+		for _, v := range appearanceVariants {
+			if err := encodePNG(filepath.Join(appIconDir, v.filename), renderIcon(glyph, style, v.luminosity)); err != nil {
+				return err
+			}
+		}
+	}
+	return UpdateContentsJSON(appIconDir, source, platform, deploymentTarget)
+}
+
+// RemoveAppearanceVariants deletes the dark and tinted variants of a previous
+// generated icon, so they don't outlive the icon they belong to.
+func RemoveAppearanceVariants(appIconDir string) {
+	for _, v := range appearanceVariants {
+		_ = os.Remove(filepath.Join(appIconDir, v.filename))
+	}
+}
+
+// styleGlyph resolves the glyph a style draws.
+func styleGlyph(style IconStyle) (Glyph, error) {
+	if style.Glyph == "" {
+		return monogram(style.AppName)
+	}
+	glyph, ok := LookupGlyph(style.Glyph)
+	if !ok {
+		return Glyph{}, fmt.Errorf("unknown icon glyph %q", style.Glyph)
+	}
+	return glyph, nil
+}
+
+// renderIcon draws a 1024px icon for a luminosity appearance. The default icon
+// is the glyph on an opaque primary-to-accent gradient; the dark one is the
+// gradient glyph on a transparent background the system fills; the tinted one
+// is a white glyph on black that the system tints.
+func renderIcon(glyph Glyph, style IconStyle, luminosity string) *image.RGBA {
+	const size = AppStoreIconPixels
+	dst := image.NewRGBA(image.Rect(0, 0, size, size))
+	background := gradient{from: style.Primary, to: style.Accent, size: size}
+
+	var fill image.Image
+	switch luminosity {
+	case "dark":
+		fill = gradient{from: lighten(style.Primary), to: lighten(style.Accent), size: size}
+	case "tinted":
+		draw.Draw(dst, dst.Bounds(), image.Black, image.Point{}, draw.Src)
+		fill = image.White
+	default:
+		draw.Draw(dst, dst.Bounds(), background, image.Point{}, draw.Src)
+		fill = image.NewUniform(contrastColor(background.At(size/2, size/2)))
+	}
+
+	z := vector.NewRasterizer(size, size)
+	box := float64(size) * glyphScale
+	offset := (float64(size) - box) / 2
+	glyph.draw(&pen{z: z, x: offset, y: offset, size: box})
+	z.Draw(dst, dst.Bounds(), fill, image.Point{})
+	return dst
+}
+
+// gradient is a diagonal linear gradient across a size×size square.
+type gradient struct {
+	from, to color.RGBA
+	size     int
+}
+
+func (g gradient) ColorModel() color.Model { return color.RGBAModel }
+func (g gradient) Bounds() image.Rectangle { return image.Rect(0, 0, g.size, g.size) }
+
+func (g gradient) At(x, y int) color.Color {
+	t := float64(x+y) / float64(2*(g.size-1))
+	t = min(max(t, 0), 1)
+	mix := func(a, b uint8) uint8 { return uint8(float64(a) + (float64(b)-float64(a))*t + 0.5) }
+	return color.RGBA{R: mix(g.from.R, g.to.R), G: mix(g.from.G, g.to.G), B: mix(g.from.B, g.to.B), A: 255}
+}
+
+// contrastColor returns white for dark backgrounds and near-black for light
+// ones, using the NTSC brightness formula.
+func contrastColor(c color.Color) color.RGBA {
+	r, g, b, _ := c.RGBA()
+	if (r>>8*299+g>>8*587+b>>8*114)/1000 < 160 {
+		return color.RGBA{R: 255, G: 255, B: 255, A: 255}
+	}
+	return color.RGBA{R: 28, G: 28, B: 30, A: 255}
+}
+
+// lighten mixes a color halfway to white, keeping it legible on the dark
+// background the system draws behind dark icons.
+func lighten(c color.RGBA) color.RGBA {
+	up := func(v uint8) uint8 { return v + (255-v)/2 }
+	return color.RGBA{R: up(c.R), G: up(c.G), B: up(c.B), A: 255}
+}
@@ -0,0 +1,85 @@
+package icons
+
+import (
+	"image/color"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var testStyle = IconStyle{
+	AppName: "habit grid",
+	Primary: color.RGBA{R: 59, G: 130, B: 246, A: 255},
+	Accent:  color.RGBA{R: 139, G: 92, B: 246, A: 255},
+}
+
+func TestGenerateIconAppearanceVariants(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "AppIcon.appiconset")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := GenerateIcon(dir, "ios", "", testStyle); err != nil {
+		t.Fatalf("GenerateIcon() error: %v", err)
+	}
+
+	if problems := ValidateAppStoreIcon(filepath.Join(dir, "Icon-1024.png")); len(problems) > 0 {
+		t.Errorf("generated icon is not a valid App Store icon: %v", problems)
+	}
+	if src := FindSourceIcon(dir); filepath.Base(src) == darkIconFilename || filepath.Base(src) == tintedIconFilename {
+		t.Errorf("FindSourceIcon() = %s, want the default icon", src)
+	}
+
+	images := readContents(t, dir)["images"].([]any)
+	if len(images) != 3 {
+		t.Fatalf("Contents.json has %d images, want default, dark and tinted", len(images))
+	}
+	for i, want := range []string{"", "dark", "tinted"} {
+		entry := images[i].(map[string]any)
+		var got string
+		if appearances, ok := entry["appearances"].([]any); ok {
+			got = appearances[0].(map[string]any)["value"].(string)
+		}
+		if got != want {
+			t.Errorf("images[%d] luminosity = %q, want %q", i, got, want)
+		}
+	}
+
+	// A legacy deployment target has no single-size icon to hang variants on.
+	if err := GenerateIcon(dir, "ios", "11.0", testStyle); err != nil {
+		t.Fatalf("GenerateIcon(11.0) error: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, darkIconFilename)); !os.IsNotExist(err) {
+		t.Error("dark variant should be removed for a legacy deployment target")
+	}
+	if images := readContents(t, dir)["images"].([]any); len(images) != len(IOSSpecs()) {
+		t.Errorf("Contents.json has %d images, want %d", len(images), len(IOSSpecs()))
+	}
+}
+
+func TestGenerateIconGlyphs(t *testing.T) {
+	for _, glyph := range append(Glyphs(), Glyph{}) {
+		style := testStyle
+		style.Glyph = glyph.Name
+		g, err := styleGlyph(style)
+		if err != nil {
+			t.Fatalf("styleGlyph(%q) error: %v", glyph.Name, err)
+		}
+		img := renderIcon(g, style, "tinted")
+		// The center of every glyph is inked; the corners never are.
+		if r, _, _, _ := img.At(512, 600).RGBA(); r == 0 && glyph.Name != "checkmark" && glyph.Name != "" {
+			t.Errorf("%s glyph left the icon center empty", glyph.Name)
+		}
+		if r, _, _, _ := img.At(8, 8).RGBA(); r != 0 {
+			t.Errorf("%s glyph reached the icon corner", glyph.Name)
+		}
+	}
+
+	style := testStyle
+	style.Glyph = "unicorn"
+	if err := GenerateIcon(t.TempDir(), "ios", "", style); err == nil {
+		t.Error("GenerateIcon() with an unknown glyph should fail")
+	}
+	if g, err := monogram("42 days"); err != nil || g.Name != "4" {
+		t.Errorf("monogram(42 days) = %q, %v", g.Name, err)
+	}
+}
@@ -0,0 +1,223 @@
+package icons
+
+import (
+	"fmt"
+	"math"
+	"unicode"
+
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/sfnt"
+	"golang.org/x/image/math/fixed"
+	"golang.org/x/image/vector"
+)
+
+// Glyph is a vector symbol a generated icon can show, drawn in a unit square
+// with y pointing down.
+type Glyph struct {
+	Name   string
+	Symbol string // the SF Symbol it resembles
+	draw   func(p *pen)
+}
+
+// Glyphs returns the symbols available to generated icons.
+func Glyphs() []Glyph {
+	return []Glyph{
+		{Name: "star", Symbol: "star.fill", draw: drawStar},
+		{Name: "heart", Symbol: "heart.fill", draw: drawHeart},
+		{Name: "bolt", Symbol: "bolt.fill", draw: func(p *pen) {
+			p.polygon(0.62, 0, 0.14, 0.58, 0.46, 0.58, 0.36, 1, 0.86, 0.4, 0.54, 0.4)
+		}},
+		{Name: "leaf", Symbol: "leaf.fill", draw: func(p *pen) {
+			p.move(0.08, 0.92)
+			p.cube(0.02, 0.4, 0.4, 0.06, 0.94, 0.06)
+			p.cube(0.94, 0.6, 0.6, 0.98, 0.08, 0.92)
+			p.close()
+		}},
+		{Name: "drop", Symbol: "drop.fill", draw: func(p *pen) {
+			p.move(0.5, 0)
+			p.cube(0.62, 0.2, 0.86, 0.42, 0.86, 0.64)
+			p.cube(0.86, 0.84, 0.7, 1, 0.5, 1)
+			p.cube(0.3, 1, 0.14, 0.84, 0.14, 0.64)
+			p.cube(0.14, 0.42, 0.38, 0.2, 0.5, 0)
+			p.close()
+		}},
+		{Name: "bubble", Symbol: "bubble.left.fill", draw: func(p *pen) {
+			p.roundedRect(0, 0.08, 1, 0.76, 0.2)
+			p.polygon(0.18, 0.7, 0.42, 0.72, 0.12, 0.96)
+		}},
+		{Name: "checkmark", Symbol: "checkmark", draw: func(p *pen) {
+			p.polygon(0.04, 0.54, 0.18, 0.4, 0.38, 0.6, 0.82, 0.16, 0.96, 0.3, 0.38, 0.88)
+		}},
+		{Name: "sparkle", Symbol: "sparkle", draw: func(p *pen) {
+			p.move(0.5, 0)
+			p.quad(0.56, 0.44, 1, 0.5)
+			p.quad(0.56, 0.56, 0.5, 1)
+			p.quad(0.44, 0.56, 0, 0.5)
+			p.quad(0.44, 0.44, 0.5, 0)
+			p.close()
+		}},
+		{Name: "house", Symbol: "house.fill", draw: func(p *pen) {
+			p.polygon(0.5, 0.02, 1, 0.46, 0.86, 0.46, 0.86, 0.96, 0.6, 0.96, 0.6, 0.66,
+				0.4, 0.66, 0.4, 0.96, 0.14, 0.96, 0.14, 0.46, 0, 0.46)
+		}},
+		{Name: "bookmark", Symbol: "bookmark.fill", draw: func(p *pen) {
+			p.polygon(0.18, 0, 0.82, 0, 0.82, 1, 0.5, 0.74, 0.18, 1)
+		}},
+	}
+}
+
+// LookupGlyph returns the glyph with the given name.
+func LookupGlyph(name string) (Glyph, bool) {
+	for _, g := range Glyphs() {
+		if g.Name == name {
+			return g, true
+		}
+	}
+	return Glyph{}, false
+}
+
+func drawStar(p *pen) {
+	var pts []float64
+	for i := 0; i < 10; i++ {
+		r := 0.5
+		if i%2 == 1 {
+			r = 0.2
+		}
+		angle := -math.Pi/2 + float64(i)*math.Pi/5
+		pts = append(pts, 0.5+r*math.Cos(angle), 0.53+r*math.Sin(angle))
+	}
+	p.polygon(pts...)
+}
+
+func drawHeart(p *pen) {
+	p.move(0.5, 0.92)
+	p.cube(0.15, 0.68, 0, 0.45, 0, 0.3)
+	p.cube(0, 0.12, 0.14, 0.02, 0.28, 0.02)
+	p.cube(0.4, 0.02, 0.47, 0.1, 0.5, 0.18)
+	p.cube(0.53, 0.1, 0.6, 0.02, 0.72, 0.02)
+	p.cube(0.86, 0.02, 1, 0.12, 1, 0.3)
+	p.cube(1, 0.45, 0.85, 0.68, 0.5, 0.92)
+	p.close()
+}
+
+// monogram returns a glyph drawing the first letter of name in Go Bold,
+// fitted to the unit square.
+func monogram(name string) (Glyph, error) {
+	letter := 'A'
+	for _, r := range name {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			letter = unicode.ToUpper(r)
+			break
+		}
+	}
+	f, err := sfnt.Parse(gobold.TTF)
+	if err != nil {
+		return Glyph{}, err
+	}
+	var buf sfnt.Buffer
+	idx, err := f.GlyphIndex(&buf, letter)
+	if err != nil || idx == 0 {
+		return Glyph{}, fmt.Errorf("no glyph for %q", letter)
+	}
+	segments, err := f.LoadGlyph(&buf, idx, fixed.I(1000), nil)
+	if err != nil {
+		return Glyph{}, err
+	}
+
+	minX, minY, maxX, maxY := math.Inf(1), math.Inf(1), math.Inf(-1), math.Inf(-1)
+	for _, seg := range segments {
+		for _, pt := range seg.Args[:segmentArgs(seg.Op)] {
+			x, y := float64(pt.X)/64, float64(pt.Y)/64
+			minX, maxX = math.Min(minX, x), math.Max(maxX, x)
+			minY, maxY = math.Min(minY, y), math.Max(maxY, y)
+		}
+	}
+	size := math.Max(maxX-minX, maxY-minY)
+	offX, offY := (size-(maxX-minX))/2, (size-(maxY-minY))/2
+	unit := func(pt fixed.Point26_6) (float64, float64) {
+		return (float64(pt.X)/64 - minX + offX) / size, (float64(pt.Y)/64 - minY + offY) / size
+	}
+
+	return Glyph{Name: string(letter), draw: func(p *pen) {
+		for i, seg := range segments {
+			x0, y0 := unit(seg.Args[0])
+			x1, y1 := unit(seg.Args[1])
+			x2, y2 := unit(seg.Args[2])
+			switch seg.Op {
+			case sfnt.SegmentOpMoveTo:
+				if i > 0 {
+					p.close()
+				}
+				p.move(x0, y0)
+			case sfnt.SegmentOpLineTo:
+				p.line(x0, y0)
+			case sfnt.SegmentOpQuadTo:
+				p.quad(x0, y0, x1, y1)
+			case sfnt.SegmentOpCubeTo:
+				p.cube(x0, y0, x1, y1, x2, y2)
+			}
+		}
+		p.close()
+	}}, nil
+}
+
+// segmentArgs is the number of points a font path segment uses.
+func segmentArgs(op sfnt.SegmentOp) int {
+	switch op {
+	case sfnt.SegmentOpQuadTo:
+		return 2
+	case sfnt.SegmentOpCubeTo:
+		return 3
+	}
+	return 1
+}
+
+// pen draws unit-square paths into a square box of a rasterizer.
+type pen struct {
+	z          *vector.Rasterizer
+	x, y, size float64
+}
+
+func (p *pen) at(x, y float64) (float32, float32) {
+	return float32(p.x + x*p.size), float32(p.y + y*p.size)
+}
+
+func (p *pen) move(x, y float64) { p.z.MoveTo(p.at(x, y)) }
+func (p *pen) line(x, y float64) { p.z.LineTo(p.at(x, y)) }
+func (p *pen) close()            { p.z.ClosePath() }
+
+func (p *pen) quad(bx, by, cx, cy float64) {
+	x1, y1 := p.at(bx, by)
+	x2, y2 := p.at(cx, cy)
+	p.z.QuadTo(x1, y1, x2, y2)
+}
+
+func (p *pen) cube(bx, by, cx, cy, dx, dy float64) {
+	x1, y1 := p.at(bx, by)
+	x2, y2 := p.at(cx, cy)
+	x3, y3 := p.at(dx, dy)
+	p.z.CubeTo(x1, y1, x2, y2, x3, y3)
+}
+
+// polygon draws a closed polygon through x, y pairs.
+func (p *pen) polygon(pts ...float64) {
+	p.move(pts[0], pts[1])
+	for i := 2; i+1 < len(pts); i += 2 {
+		p.line(pts[i], pts[i+1])
+	}
+	p.close()
+}
+
+// roundedRect draws a clockwise rectangle with corners of radius r.
+func (p *pen) roundedRect(x0, y0, x1, y1, r float64) {
+	p.move(x0+r, y0)
+	p.line(x1-r, y0)
+	p.quad(x1, y0, x1, y0+r)
+	p.line(x1, y1-r)
+	p.quad(x1, y1, x1-r, y1)
+	p.line(x0+r, y1)
+	p.quad(x0, y1, x0, y1-r)
+	p.line(x0, y0+r)
+	p.quad(x0, y0, x0+r, y0)
+	p.close()
+}
@@ -0,0 +1,137 @@
+package icons
+
+import (
+	"encoding/json"
+	"fmt"
+	"image"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"golang.org/x/image/draw"
+)
+
+// Layered app icon asset names. They replace AppIcon.appiconset on tvOS and
+// visionOS and keep the AppIcon name the build settings refer to.
+const (
+	tvOSIconAsset     = "AppIcon.brandassets"
+	visionOSIconAsset = "AppIcon.solidimagestack"
+)
+
+// assetInfo is the info block of every asset catalog Contents.json.
+var assetInfo = map[string]any{"version": 1, "author": "xcode"}
+
+// writeContents writes an asset catalog Contents.json into dir.
+func writeContents(dir string, contents map[string]any) error {
+	contents["info"] = assetInfo
+	data, err := json.MarshalIndent(contents, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, "Contents.json"), append(data, '\n'), 0o644)
+}
+
+// writeBrandAssets writes a tvOS brand asset into dir: layered app icons whose
+// Back layer fills the frame with the source and whose Front layer holds the
+// whole source, plus flattened top shelf images.
+func writeBrandAssets(dir string, src image.Image) error {
+	var assets []map[string]any
+	for _, asset := range TVOSBrandAssets() {
+		assets = append(assets, map[string]any{
+			"filename": asset.Filename,
+			"idiom":    "tv",
+			"role":     asset.Role,
+			"size":     fmt.Sprintf("%dx%d", asset.Width, asset.Height),
+		})
+		assetDir := filepath.Join(dir, asset.Filename)
+		if !asset.Layered {
+			err := writeImageSet(assetDir, "tv", asset.Scales, func(scale int) image.Image {
+				w, h := asset.Width*scale, asset.Height*scale
+				return flatten(cover(src, w, h), contain(src, w, h))
+			})
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		layers := []map[string]any{{"filename": "Front.imagestacklayer"}, {"filename": "Back.imagestacklayer"}}
+		if err := writeContents(assetDir, map[string]any{"layers": layers}); err != nil {
+			return err
+		}
+		for _, layer := range []string{"Front", "Back"} {
+			layerDir := filepath.Join(assetDir, layer+".imagestacklayer")
+			if err := writeContents(layerDir, map[string]any{}); err != nil {
+				return err
+			}
+			err := writeImageSet(filepath.Join(layerDir, "Content.imageset"), "tv", asset.Scales, func(scale int) image.Image {
+				w, h := asset.Width*scale, asset.Height*scale
+				if layer == "Back" {
+					return cover(src, w, h)
+				}
+				return contain(src, w, h)
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return writeContents(dir, map[string]any{"assets": assets})
+}
+
+// writeSolidImageStack writes a visionOS app icon into dir: the source fills
+// the opaque Back layer and the Front layer is left transparent for artwork
+// that should float above it.
+func writeSolidImageStack(dir string, src image.Image) error {
+	var layers []map[string]any
+	for _, layer := range VisionOSLayers() {
+		layers = append(layers, map[string]any{"filename": layer + ".solidimagestacklayer"})
+		layerDir := filepath.Join(dir, layer+".solidimagestacklayer")
+		if err := writeContents(layerDir, map[string]any{}); err != nil {
+			return err
+		}
+		err := writeImageSet(filepath.Join(layerDir, "Content.imageset"), "vision", []int{2}, func(int) image.Image {
+			if layer == "Back" {
+				return flatten(scale(src, 1024, 1024))
+			}
+			return transparent(1024, 1024)
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return writeContents(dir, map[string]any{"layers": layers})
+}
+
+// writeImageSet writes an image set with one PNG per scale, rendered by render.
+func writeImageSet(dir, idiom string, scales []int, render func(scale int) image.Image) error {
+	name := strings.ReplaceAll(strings.TrimSuffix(filepath.Base(dir), filepath.Ext(dir)), " ", "")
+	var images []map[string]any
+	for _, s := range scales {
+		filename := name + ".png"
+		if s > 1 {
+			filename = name + "@" + strconv.Itoa(s) + "x.png"
+		}
+		if err := encodePNG(filepath.Join(dir, filename), render(s)); err != nil {
+			return err
+		}
+		images = append(images, map[string]any{"filename": filename, "idiom": idiom, "scale": strconv.Itoa(s) + "x"})
+	}
+	return writeContents(dir, map[string]any{"images": images})
+}
+
+// flatten composites the layers back to front onto an opaque black canvas, so
+// the result has no transparency.
+func flatten(layers ...*image.RGBA) *image.RGBA {
+	b := layers[0].Bounds()
+	dst := image.NewRGBA(b)
+	draw.Draw(dst, b, image.Black, image.Point{}, draw.Src)
+	for _, layer := range layers {
+		draw.Draw(dst, b, layer, b.Min, draw.Over)
+	}
+	return dst
+}
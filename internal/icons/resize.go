@@ -2,30 +2,105 @@ package icons
 
 import (
 	"fmt"
+	"image"
+	_ "image/jpeg" // JPEG sources
+	"image/png"
 	"io"
 	"log"
 	"os"
-	"os/exec"
 	"path/filepath"
+
+	"golang.org/x/image/draw"
 )
 
-// Resize uses macOS sips to resize a PNG to the given pixel dimensions.
+// Resize scales an icon to pixels×pixels and writes it to dst as a PNG.
 func Resize(src, dst string, pixels int) error {
-	// Strip xattrs that can cause sips to reject the file (e.g. com.apple.provenance)
-	_ = exec.Command("xattr", "-c", src).Run()
-	cmd := exec.Command("sips", "-z", fmt.Sprintf("%d", pixels), fmt.Sprintf("%d", pixels), src, "--out", dst)
-	if out, err := cmd.CombinedOutput(); err != nil {
-		log.Printf("[asc][icon] sips FAILED for %dx%d → %s: %s: %v", pixels, pixels, filepath.Base(dst), string(out), err)
-		return fmt.Errorf("sips failed: %s: %w", string(out), err)
-	}
-	// Verify the output file exists and has content
-	info, statErr := os.Stat(dst)
-	if statErr != nil || info.Size() == 0 {
-		log.Printf("[asc][icon] sips WARNING: output file %s missing or empty after resize", filepath.Base(dst))
+	img, err := decodeImage(src)
+	if err != nil {
+		return err
+	}
+	if err := encodePNG(dst, scale(img, pixels, pixels)); err != nil {
+		log.Printf("[asc][icon] resize FAILED for %dx%d → %s: %v", pixels, pixels, filepath.Base(dst), err)
+		return err
 	}
 	return nil
 }
 
+// decodeImage reads a PNG or JPEG file.
+func decodeImage(path string) (image.Image, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	img, _, err := image.Decode(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode %s: %w", filepath.Base(path), err)
+	}
+	return img, nil
+}
+
+// encodePNG writes img to path as a PNG, creating the parent directory. Fully
+// opaque images are written without an alpha channel.
+func encodePNG(path string, img image.Image) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := png.Encode(f, img); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to encode %s: %w", filepath.Base(path), err)
+	}
+	return f.Close()
+}
+
+// scale resamples img to width×height with a Catmull-Rom filter, which keeps
+// edges sharp when downscaling a 1024px icon to 20px.
+func scale(img image.Image, width, height int) *image.RGBA {
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, img.Bounds(), draw.Src, nil)
+	return dst
+}
+
+// cover scales img to fill width×height, cropping the overflow around the center.
+func cover(img image.Image, width, height int) *image.RGBA {
+	b := img.Bounds()
+	crop := b
+	if b.Dx()*height > b.Dy()*width {
+		w := b.Dy() * width / height
+		crop.Min.X = b.Min.X + (b.Dx()-w)/2
+		crop.Max.X = crop.Min.X + w
+	} else {
+		h := b.Dx() * height / width
+		crop.Min.Y = b.Min.Y + (b.Dy()-h)/2
+		crop.Max.Y = crop.Min.Y + h
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, crop, draw.Src, nil)
+	return dst
+}
+
+// contain scales img to fit inside width×height, centered on a transparent canvas.
+func contain(img image.Image, width, height int) *image.RGBA {
+	b := img.Bounds()
+	w, h := width, b.Dy()*width/b.Dx()
+	if h > height {
+		w, h = b.Dx()*height/b.Dy(), height
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	x, y := (width-w)/2, (height-h)/2
+	draw.CatmullRom.Scale(dst, image.Rect(x, y, x+w, y+h), img, b, draw.Src, nil)
+	return dst
+}
+
+// transparent returns an empty width×height layer.
+func transparent(width, height int) *image.RGBA {
+	return image.NewRGBA(image.Rect(0, 0, width, height))
+}
+
 // CopyFile copies src to dst.
 func CopyFile(src, dst string) error {
 	if src == dst {
@@ -1,5 +1,10 @@
 package icons
 
+import (
+	"strconv"
+	"strings"
+)
+
 // Spec defines a required icon size entry.
 type Spec struct {
 	Idiom    string
@@ -7,6 +12,9 @@ type Spec struct {
 	Scale    string // "1x", "2x", "3x"
 	Pixels   int    // actual pixel dimension (size * scale)
 	Filename string // generated filename
+	Platform string // Contents.json platform, e.g. "ios" for single-size entries
+	Role     string // watchOS icon role, e.g. "appLauncher"
+	Subtype  string // watchOS case size, e.g. "40mm"
 }
 
 // IOSSpecs returns all icon sizes required for iOS App Store submission.
@@ -42,3 +50,117 @@ func IOSSpecs() []Spec {
 		{Idiom: "ios-marketing", Size: "1024x1024", Scale: "1x", Pixels: 1024, Filename: "Icon-1024.png"},
 	}
 }
+
+// MacOSSpecs returns the icon sizes of a macOS app icon. macOS has no
+// single-size format, so every size is always generated.
+func MacOSSpecs() []Spec {
+	var specs []Spec
+	for _, points := range []int{16, 32, 128, 256, 512} {
+		size := strconv.Itoa(points) + "x" + strconv.Itoa(points)
+		specs = append(specs,
+			Spec{Idiom: "mac", Size: size, Scale: "1x", Pixels: points, Filename: "icon_" + size + ".png"},
+			Spec{Idiom: "mac", Size: size, Scale: "2x", Pixels: points * 2, Filename: "icon_" + size + "@2x.png"},
+		)
+	}
+	return specs
+}
+
+// WatchOSSpecs returns the per-case icon sizes of a watchOS app icon, used when
+// the deployment target predates the single-size format.
+func WatchOSSpecs() []Spec {
+	return []Spec{
+		// Notification Center
+		{Idiom: "watch", Size: "24x24", Scale: "2x", Pixels: 48, Filename: "Icon-24@2x.png", Role: "notificationCenter", Subtype: "38mm"},
+		{Idiom: "watch", Size: "27.5x27.5", Scale: "2x", Pixels: 55, Filename: "Icon-27.5@2x.png", Role: "notificationCenter", Subtype: "42mm"},
+		{Idiom: "watch", Size: "33x33", Scale: "2x", Pixels: 66, Filename: "Icon-33@2x.png", Role: "notificationCenter", Subtype: "45mm"},
+		// Companion Settings
+		{Idiom: "watch", Size: "29x29", Scale: "2x", Pixels: 58, Filename: "Icon-29@2x.png", Role: "companionSettings"},
+		{Idiom: "watch", Size: "29x29", Scale: "3x", Pixels: 87, Filename: "Icon-29@3x.png", Role: "companionSettings"},
+		// Home Screen
+		{Idiom: "watch", Size: "40x40", Scale: "2x", Pixels: 80, Filename: "Icon-40@2x.png", Role: "appLauncher", Subtype: "38mm"},
+		{Idiom: "watch", Size: "44x44", Scale: "2x", Pixels: 88, Filename: "Icon-44@2x.png", Role: "appLauncher", Subtype: "40mm"},
+		{Idiom: "watch", Size: "46x46", Scale: "2x", Pixels: 92, Filename: "Icon-46@2x.png", Role: "appLauncher", Subtype: "41mm"},
+		{Idiom: "watch", Size: "50x50", Scale: "2x", Pixels: 100, Filename: "Icon-50@2x.png", Role: "appLauncher", Subtype: "44mm"},
+		{Idiom: "watch", Size: "51x51", Scale: "2x", Pixels: 102, Filename: "Icon-51@2x.png", Role: "appLauncher", Subtype: "45mm"},
+		{Idiom: "watch", Size: "54x54", Scale: "2x", Pixels: 108, Filename: "Icon-54@2x.png", Role: "appLauncher", Subtype: "49mm"},
+		// Short Look
+		{Idiom: "watch", Size: "86x86", Scale: "2x", Pixels: 172, Filename: "Icon-86@2x.png", Role: "quickLook", Subtype: "38mm"},
+		{Idiom: "watch", Size: "98x98", Scale: "2x", Pixels: 196, Filename: "Icon-98@2x.png", Role: "quickLook", Subtype: "42mm"},
+		{Idiom: "watch", Size: "108x108", Scale: "2x", Pixels: 216, Filename: "Icon-108@2x.png", Role: "quickLook", Subtype: "44mm"},
+		{Idiom: "watch", Size: "117x117", Scale: "2x", Pixels: 234, Filename: "Icon-117@2x.png", Role: "quickLook", Subtype: "45mm"},
+		{Idiom: "watch", Size: "129x129", Scale: "2x", Pixels: 258, Filename: "Icon-129@2x.png", Role: "quickLook", Subtype: "49mm"},
+		// App Store
+		{Idiom: "watch-marketing", Size: "1024x1024", Scale: "1x", Pixels: 1024, Filename: "Icon-1024.png"},
+	}
+}
+
+// singleSizeSpec returns the one 1024px entry Xcode derives every size from.
+func singleSizeSpec(platform string) Spec {
+	return Spec{Idiom: "universal", Platform: platform, Size: "1024x1024", Pixels: 1024, Filename: "Icon-1024.png"}
+}
+
+// singleSizeMinimum is the oldest deployment target per platform whose app
+// icon may be a single 1024px image. macOS always needs every size.
+var singleSizeMinimum = map[string]string{
+	"ios":     "12.0",
+	"watchos": "7.0",
+}
+
+// SupportsSingleSize reports whether an app icon for the platform and
+// deployment target may use the single-size format. An empty deployment
+// target means nanowave's own, which always does.
+func SupportsSingleSize(platform, deploymentTarget string) bool {
+	minimum, ok := singleSizeMinimum[platform]
+	if !ok {
+		return false
+	}
+	if deploymentTarget == "" {
+		return true
+	}
+	return compareOSVersions(deploymentTarget, minimum) >= 0
+}
+
+// compareOSVersions compares dotted OS versions such as 17.0 and 12.
+func compareOSVersions(a, b string) int {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < max(len(as), len(bs)); i++ {
+		var x, y int
+		if i < len(as) {
+			x, _ = strconv.Atoi(as[i])
+		}
+		if i < len(bs) {
+			y, _ = strconv.Atoi(bs[i])
+		}
+		if x != y {
+			return x - y
+		}
+	}
+	return 0
+}
+
+// BrandAsset is one entry of a tvOS "App Icon & Top Shelf Image" brand asset.
+type BrandAsset struct {
+	Filename string // e.g. "App Icon.imagestack"
+	Role     string
+	Width    int // point size
+	Height   int
+	Scales   []int
+	// Layered assets are image stacks with a Front and a Back layer; the others are image sets.
+	Layered bool
+}
+
+// TVOSBrandAssets returns the app icons and top shelf images of a tvOS app.
+func TVOSBrandAssets() []BrandAsset {
+	return []BrandAsset{
+		{Filename: "App Icon - App Store.imagestack", Role: "primary-app-icon", Width: 1280, Height: 768, Scales: []int{1}, Layered: true},
+		{Filename: "App Icon.imagestack", Role: "primary-app-icon", Width: 400, Height: 240, Scales: []int{1, 2}, Layered: true},
+		{Filename: "Top Shelf Image Wide.imageset", Role: "top-shelf-image-wide", Width: 2320, Height: 720, Scales: []int{1, 2}},
+		{Filename: "Top Shelf Image.imageset", Role: "top-shelf-image", Width: 1920, Height: 720, Scales: []int{1, 2}},
+	}
+}
+
+// VisionOSLayers lists the layers of a visionOS app icon, front to back. Each
+// is a 1024px image; the Back layer must be opaque.
+func VisionOSLayers() []string {
+	return []string{"Front", "Back"}
+}
@@ -2,6 +2,7 @@ package icons
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"log"
@@ -73,7 +74,7 @@ const iconUploadPage = `<!DOCTYPE html>
 <body>
 <div class="container">
   <h1>App Icon</h1>
-  <p class="subtitle">1024x1024 PNG required for App Store</p>
+  <p class="subtitle">1024x1024 PNG without transparency, required for App Store</p>
   <div class="drop-zone" id="dropZone">
     <img class="icon-preview" id="preview">
     <div class="drop-text">
@@ -166,8 +167,9 @@ function setStatus(msg, isError) {
 
 // RunUploadServer starts a temporary local HTTP server for icon upload.
 // Opens the browser, waits for upload or skip, then shuts down.
+// Uploads that fail App Store icon validation are rejected.
 // Returns true if an icon was set.
-func RunUploadServer(ctx context.Context, appIconDir, platform string) bool {
+func RunUploadServer(ctx context.Context, appIconDir, platform, deploymentTarget string) bool {
 	done := make(chan bool, 1)
 
 	mux := http.NewServeMux()
@@ -212,8 +214,16 @@ func RunUploadServer(ctx context.Context, appIconDir, platform string) bool {
 		}
 		out.Close()
 
+		if problems := ValidateAppStoreIcon(destPath); len(problems) > 0 {
+			os.Remove(destPath)
+			msg, _ := json.Marshal(problems[0].Error())
+			w.Write([]byte(fmt.Sprintf(`{"ok":false,"error":%s}`, msg)))
+			return
+		}
+
 		// Update Contents.json
-		if err := UpdateContentsJSON(appIconDir, iconFilename, platform); err != nil {
+		RemoveAppearanceVariants(appIconDir)
+		if err := UpdateContentsJSON(appIconDir, iconFilename, platform, deploymentTarget); err != nil {
 			log.Printf("[asc] icon Contents.json update failed: %v", err)
 			w.Write([]byte(fmt.Sprintf(`{"ok":false,"error":"icon saved but Contents.json update failed: %s"}`, err.Error())))
 			return
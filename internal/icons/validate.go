@@ -0,0 +1,173 @@
+package icons
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/color"
+	"io"
+	"os"
+	"strings"
+)
+
+// AppStoreIconPixels is the size of the App Store marketing icon.
+const AppStoreIconPixels = 1024
+
+// ValidateAppStoreIcon checks an App Store icon the way App Store Connect does:
+// a square 1024x1024 RGB image without an alpha channel, in the sRGB or
+// Display P3 color space. It returns every problem found.
+func ValidateAppStoreIcon(path string) []error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return []error{err}
+	}
+	cfg, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return []error{fmt.Errorf("not a PNG or JPEG image: %w", err)}
+	}
+
+	var problems []error
+	if cfg.Width != cfg.Height {
+		problems = append(problems, fmt.Errorf("icon is %dx%d, it must be square", cfg.Width, cfg.Height))
+	} else if cfg.Width != AppStoreIconPixels {
+		problems = append(problems, fmt.Errorf("icon is %dx%d, it must be %dx%d", cfg.Width, cfg.Height, AppStoreIconPixels, AppStoreIconPixels))
+	}
+	switch cfg.ColorModel {
+	case color.GrayModel, color.Gray16Model:
+		problems = append(problems, fmt.Errorf("icon is grayscale, it must be RGB"))
+	case color.CMYKModel:
+		problems = append(problems, fmt.Errorf("icon is CMYK, it must be RGB"))
+	}
+
+	if format == "png" {
+		info, err := readPNGInfo(data)
+		if err != nil {
+			return append(problems, err)
+		}
+		if info.alpha {
+			problems = append(problems, fmt.Errorf("icon has an alpha channel, App Store icons must be opaque without one"))
+		}
+		if info.profile != "" && !isSupportedProfile(info.profile) {
+			problems = append(problems, fmt.Errorf("icon uses the %q color profile, it must be sRGB or Display P3", info.profile))
+		}
+	}
+	return problems
+}
+
+// pngInfo is what ValidateAppStoreIcon needs from a PNG's chunks.
+type pngInfo struct {
+	alpha   bool   // color type with alpha, or a tRNS transparency chunk
+	profile string // embedded ICC profile description, "" when none
+}
+
+// readPNGInfo walks the chunks of a PNG file.
+func readPNGInfo(data []byte) (pngInfo, error) {
+	var info pngInfo
+	const signature = "\x89PNG\r\n\x1a\n"
+	if !bytes.HasPrefix(data, []byte(signature)) {
+		return info, fmt.Errorf("not a PNG file")
+	}
+	rest := data[len(signature):]
+	for len(rest) >= 12 {
+		length := binary.BigEndian.Uint32(rest[:4])
+		kind := string(rest[4:8])
+		if uint64(length)+12 > uint64(len(rest)) {
+			return info, fmt.Errorf("truncated PNG chunk %s", kind)
+		}
+		body := rest[8 : 8+length]
+		switch kind {
+		case "IHDR":
+			if len(body) >= 10 {
+				colorType := body[9]
+				info.alpha = info.alpha || colorType == 4 || colorType == 6
+			}
+		case "tRNS":
+			info.alpha = true
+		case "iCCP":
+			info.profile = iccProfileName(body)
+		case "IEND":
+			return info, nil
+		}
+		rest = rest[12+length:]
+	}
+	return info, nil
+}
+
+// iccProfileName returns the description of an iCCP chunk's ICC profile, or
+// the chunk's profile name when the description cannot be read.
+func iccProfileName(chunk []byte) string {
+	nul := bytes.IndexByte(chunk, 0)
+	if nul < 0 || nul+2 > len(chunk) {
+		return ""
+	}
+	name := string(chunk[:nul])
+	r, err := zlib.NewReader(bytes.NewReader(chunk[nul+2:]))
+	if err != nil {
+		return name
+	}
+	profile, err := io.ReadAll(r)
+	if err != nil {
+		return name
+	}
+	if desc := iccDescription(profile); desc != "" {
+		return desc
+	}
+	return name
+}
+
+// iccDescription reads the 'desc' tag of an ICC profile, in either the v2
+// textDescriptionType or the v4 multiLocalizedUnicodeType encoding.
+func iccDescription(profile []byte) string {
+	if len(profile) < 132 {
+		return ""
+	}
+	count := int(binary.BigEndian.Uint32(profile[128:132]))
+	for i := 0; i < count; i++ {
+		entry := 132 + i*12
+		if entry+12 > len(profile) {
+			return ""
+		}
+		if string(profile[entry:entry+4]) != "desc" {
+			continue
+		}
+		offset := int(binary.BigEndian.Uint32(profile[entry+4:]))
+		size := int(binary.BigEndian.Uint32(profile[entry+8:]))
+		if offset < 0 || size < 12 || offset+size > len(profile) {
+			return ""
+		}
+		tag := profile[offset : offset+size]
+		switch string(tag[:4]) {
+		case "desc":
+			n := int(binary.BigEndian.Uint32(tag[8:12]))
+			if 12+n > len(tag) {
+				return ""
+			}
+			return strings.TrimRight(string(tag[12:12+n]), "\x00")
+		case "mluc":
+			if len(tag) < 28 {
+				return ""
+			}
+			n := int(binary.BigEndian.Uint32(tag[20:24]))
+			start := int(binary.BigEndian.Uint32(tag[24:28]))
+			if start+n > len(tag) {
+				return ""
+			}
+			var runes []rune
+			for j := start; j+1 < start+n; j += 2 {
+				runes = append(runes, rune(binary.BigEndian.Uint16(tag[j:])))
+			}
+			return string(runes)
+		}
+		return ""
+	}
+	return ""
+}
+
+// isSupportedProfile reports whether an ICC profile description names an
+// sRGB or Display P3 color space.
+func isSupportedProfile(name string) bool {
+	lower := strings.ToLower(name)
+	return strings.Contains(lower, "srgb") || strings.Contains(lower, "p3")
+}
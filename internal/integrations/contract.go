@@ -62,6 +62,13 @@ type ProvisionCapable interface {
 	Provision(ctx context.Context, req ProvisionRequest) (*ProvisionResult, error)
 }
 
+// PlannerCapable providers describe when the planner should select them.
+// Built-in providers are documented in the planner skill; plugins use this instead.
+type PlannerCapable interface {
+	// PlannerHint returns a one-line description of when to add the provider to `integrations`.
+	PlannerHint() string
+}
+
 // --- Request/Response types ---
 
 // SetupRequest holds parameters for the Setup flow.
@@ -69,6 +76,8 @@ type SetupRequest struct {
 	Store      *IntegrationStore
 	AppName    string
 	Manual     bool
+	Local      bool   // connect a locally running stack instead of a hosted project
+	WorkDir    string // directory the provider CLI runs in for local setup (empty = cwd)
 	ReadLineFn func(label string) string
 	PrintFn    func(level, msg string)
 	PickFn     func(title string, options []string) string
@@ -80,6 +89,7 @@ type ProviderStatus struct {
 	ProjectURL  string
 	HasAnonKey  bool
 	HasPAT      bool
+	LocalURL    string // local stack API URL, if one is configured
 	ValidatedAt string
 }
 
@@ -91,6 +101,7 @@ type PromptRequest struct {
 	Store              *IntegrationStore
 	BackendProvisioned bool
 	MonetizationPlan   *MonetizationPlan // product/subscription plan from planner
+	BundleID           string            // app bundle identifier (e.g. com.example.myapp)
 }
 
 // PromptContribution is the output of a provider's prompt generation.
@@ -134,6 +145,8 @@ type ProvisionRequest struct {
 	NeedsMonetization bool              // in-app purchases / subscriptions
 	MonetizationType  string             // "subscription", "consumable", "hybrid"
 	MonetizationPlan  *MonetizationPlan  // product definitions from planner
+	Local             *LocalStack        // local stack to provision instead of the hosted project
+	Providers         []ProviderID       // every provider active in this build, filled in by Manager.Provision
 }
 
 // ProvisionResult holds the outcome of backend provisioning.
@@ -46,6 +46,15 @@ func (m *Manager) Resolve(ctx context.Context, appName string, planned []string,
 			// No config — try auto-setup if provider supports it
 			if sc, ok := p.(SetupCapable); ok {
 				cfg = ui.PromptSetup(ctx, sc, p, m.store, appName)
+			} else {
+				// Providers without setup (storekit-native, credential-free plugins) need
+				// no credentials, so there is nothing to prompt for. Record an empty config
+				// so ResolveExisting picks them up again for edits. Providers with setup,
+				// which includes every credentialed integration, never reach this branch.
+				cfg = &IntegrationConfig{Provider: id}
+				if err := m.store.SetProvider(*cfg, appName); err != nil {
+					ui.Warning(fmt.Sprintf("Could not record %s for %s: %v", id, appName, err))
+				}
 			}
 			if cfg == nil {
 				ui.Info("Continuing without backend — using placeholder config")
@@ -140,6 +149,9 @@ func (m *Manager) PromptContributions(ctx context.Context, req PromptRequest, ac
 // Provision runs backend provisioning for all active providers that support it.
 func (m *Manager) Provision(ctx context.Context, req ProvisionRequest, active []ActiveProvider) (*ProvisionResult, error) {
 	combined := &ProvisionResult{}
+	for _, a := range active {
+		req.Providers = append(req.Providers, a.Provider.ID())
+	}
 	for _, a := range active {
 		pc, ok := a.Provider.(ProvisionCapable)
 		if !ok {
@@ -150,6 +162,7 @@ func (m *Manager) Provision(ctx context.Context, req ProvisionRequest, active []
 		r.PAT = a.Config.PAT
 		r.ProjectURL = a.Config.ProjectURL
 		r.ProjectRef = a.Config.ProjectRef
+		r.Local = a.Config.Local
 		result, err := pc.Provision(ctx, r)
 		if err != nil {
 			return nil, fmt.Errorf("provision for %s: %w", a.Provider.ID(), err)
@@ -184,6 +197,20 @@ func (m *Manager) ResolveExisting(appName string) []ActiveProvider {
 	return active
 }
 
+// PlannerHints returns "- `id`: hint" lines for registered providers that describe
+// themselves to the planner (plugins). Empty when there are none.
+func (m *Manager) PlannerHints() []string {
+	var hints []string
+	for _, p := range m.registry.All() {
+		pc, ok := p.(PlannerCapable)
+		if !ok || pc.PlannerHint() == "" {
+			continue
+		}
+		hints = append(hints, fmt.Sprintf("- `%s` (%s): %s", p.ID(), p.Meta().Name, pc.PlannerHint()))
+	}
+	return hints
+}
+
 // AllProviders returns all registered providers.
 func (m *Manager) AllProviders() []Provider {
 	return m.registry.All()
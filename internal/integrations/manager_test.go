@@ -156,3 +156,79 @@ func must(p Provider, ok bool) Provider {
 	}
 	return p
 }
+
+func TestManager_Resolve_CredentialFreeProviderRecorded(t *testing.T) {
+	r := NewRegistry()
+	r.Register(&mockProvider{id: "storekit-native", meta: ProviderMeta{Name: "StoreKit Native"}})
+	store := NewIntegrationStore(t.TempDir())
+	m := NewManager(r, store)
+
+	active, err := m.Resolve(context.Background(), "test-app", []string{"storekit-native"}, &mockSetupUI{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(active) != 1 {
+		t.Fatalf("expected 1 active provider, got %d", len(active))
+	}
+
+	// Recorded so edits find it without re-planning.
+	if existing := m.ResolveExisting("test-app"); len(existing) != 1 {
+		t.Errorf("expected provider to be restored by ResolveExisting, got %d", len(existing))
+	}
+}
+
+// mockSetupProvider is a provider with credentials, set up through SetupUI.
+type mockSetupProvider struct {
+	mockProvider
+}
+
+func (m *mockSetupProvider) Setup(context.Context, SetupRequest) error { return nil }
+func (m *mockSetupProvider) Remove(context.Context, *IntegrationStore, string) error {
+	return nil
+}
+func (m *mockSetupProvider) Status(context.Context, *IntegrationStore, string) (ProviderStatus, error) {
+	return ProviderStatus{}, nil
+}
+func (m *mockSetupProvider) CLIAvailable() bool { return true }
+
+func TestManager_Resolve_OnlyCredentialFreeProvidersRecorded(t *testing.T) {
+	r := NewRegistry()
+	r.Register(&mockSetupProvider{mockProvider{id: "supabase"}})
+	r.Register(&mockProvider{id: "acme-analytics"}) // a plugin without setup
+	store := NewIntegrationStore(t.TempDir())
+	m := NewManager(r, store)
+
+	// Setup is declined: the credentialed provider is skipped and nothing is stored for it.
+	active, err := m.Resolve(context.Background(), "test-app", []string{"supabase", "acme-analytics"}, &mockSetupUI{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(active) != 1 || active[0].Provider.ID() != "acme-analytics" {
+		t.Fatalf("expected only the credential-free provider to be active, got %+v", active)
+	}
+	if cfg, _ := store.GetProvider("supabase", "test-app"); cfg != nil {
+		t.Errorf("declined setup recorded a config: %+v", cfg)
+	}
+	if cfg, _ := store.GetProvider("acme-analytics", "test-app"); cfg == nil {
+		t.Error("expected the credential-free provider to be recorded")
+	}
+}
+
+type mockPlannerProvider struct {
+	mockProvider
+	hint string
+}
+
+func (m *mockPlannerProvider) PlannerHint() string { return m.hint }
+
+func TestManager_PlannerHints(t *testing.T) {
+	r := NewRegistry()
+	r.Register(&mockProvider{id: "supabase", meta: ProviderMeta{Name: "Supabase"}})
+	r.Register(&mockPlannerProvider{mockProvider: mockProvider{id: "acme", meta: ProviderMeta{Name: "Acme"}}, hint: "apps with analytics"})
+	m := NewManager(r, NewIntegrationStore(t.TempDir()))
+
+	hints := m.PlannerHints()
+	if len(hints) != 1 || hints[0] != "- `acme` (Acme): apps with analytics" {
+		t.Errorf("unexpected hints: %v", hints)
+	}
+}
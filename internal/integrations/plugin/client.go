@@ -0,0 +1,152 @@
+package plugin
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+)
+
+// maxMessageSize bounds a single JSON-RPC line from a plugin (prompt blocks can be large).
+const maxMessageSize = 16 << 20
+
+// hostUI answers the ui.* requests a plugin sends during setup. Nil disables them.
+type hostUI struct {
+	print    func(level, msg string)
+	readLine func(label string) string
+	pick     func(title string, options []string) string
+}
+
+// call runs the plugin executable once, sends a single request, and decodes the
+// matching response into result. Requests from the plugin are served by ui.
+func call(ctx context.Context, path, method string, params, result any, ui *hostUI) error {
+	rawParams, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
+	id := int64(1)
+	req, err := json.Marshal(rpcMessage{JSONRPC: "2.0", ID: &id, Method: method, Params: rawParams})
+	if err != nil {
+		return err
+	}
+
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, path)
+	cmd.Stderr = &stderr
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("start plugin %s: %w", path, err)
+	}
+
+	resp, convErr := converse(stdin, stdout, req, id, ui)
+	stdin.Close()
+	waitErr := cmd.Wait()
+
+	if convErr != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return fmt.Errorf("plugin %s %s: %w (%s)", path, method, convErr, msg)
+		}
+		if waitErr != nil {
+			return fmt.Errorf("plugin %s %s: %w (%v)", path, method, convErr, waitErr)
+		}
+		return fmt.Errorf("plugin %s %s: %w", path, method, convErr)
+	}
+	if resp.Error != nil {
+		return fmt.Errorf("plugin %s %s: %w", path, method, resp.Error)
+	}
+	if result == nil || len(resp.Result) == 0 || string(resp.Result) == "null" {
+		return nil
+	}
+	if err := json.Unmarshal(resp.Result, result); err != nil {
+		return fmt.Errorf("plugin %s %s: invalid result: %w", path, method, err)
+	}
+	return nil
+}
+
+// converse writes the request and reads messages until the response with id arrives,
+// answering any host requests the plugin makes along the way.
+func converse(w io.Writer, r io.Reader, req []byte, id int64, ui *hostUI) (*rpcMessage, error) {
+	if _, err := w.Write(append(req, '\n')); err != nil {
+		return nil, fmt.Errorf("write request: %w", err)
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxMessageSize)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var msg rpcMessage
+		if err := json.Unmarshal(line, &msg); err != nil {
+			return nil, fmt.Errorf("invalid message from plugin: %w", err)
+		}
+
+		if msg.Method != "" {
+			// Request or notification from the plugin.
+			result, rpcErr := serveHost(msg, ui)
+			if msg.ID == nil {
+				continue
+			}
+			reply := rpcMessage{JSONRPC: "2.0", ID: msg.ID, Error: rpcErr}
+			if rpcErr == nil {
+				reply.Result, _ = json.Marshal(result)
+			}
+			data, _ := json.Marshal(reply)
+			if _, err := w.Write(append(data, '\n')); err != nil {
+				return nil, fmt.Errorf("write reply: %w", err)
+			}
+			continue
+		}
+
+		if msg.ID != nil && *msg.ID == id {
+			return &msg, nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read response: %w", err)
+	}
+	return nil, fmt.Errorf("plugin exited without responding")
+}
+
+// serveHost handles a ui.* request from the plugin.
+func serveHost(msg rpcMessage, ui *hostUI) (any, *rpcError) {
+	if ui == nil {
+		return nil, &rpcError{Code: codeMethodNotFound, Message: msg.Method + " is only available during setup"}
+	}
+	switch msg.Method {
+	case methodUIPrint:
+		var p uiPrintParams
+		_ = json.Unmarshal(msg.Params, &p)
+		if ui.print != nil {
+			ui.print(p.Level, p.Message)
+		}
+		return nil, nil
+	case methodUIReadLine:
+		var p uiReadLineParams
+		_ = json.Unmarshal(msg.Params, &p)
+		if ui.readLine == nil {
+			return "", nil
+		}
+		return ui.readLine(p.Label), nil
+	case methodUIPick:
+		var p uiPickParams
+		_ = json.Unmarshal(msg.Params, &p)
+		if ui.pick == nil {
+			return "", nil
+		}
+		return ui.pick(p.Title, p.Options), nil
+	}
+	return nil, &rpcError{Code: codeMethodNotFound, Message: "unknown host method: " + msg.Method}
+}
@@ -0,0 +1,250 @@
+package plugin
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/moasq/nanowave/internal/integrations"
+	"github.com/moasq/nanowave/internal/integrations/secrets"
+)
+
+// TestHelperPlugin is not a real test: it is the fake plugin executable, re-entered
+// through the wrapper script that writeTestPlugin installs.
+func TestHelperPlugin(t *testing.T) {
+	if os.Getenv("NANOWAVE_TEST_PLUGIN") != "1" {
+		t.Skip("helper process")
+	}
+	runFakePlugin(os.Stdin, os.Stdout)
+	os.Exit(0)
+}
+
+func runFakePlugin(in *os.File, out *os.File) {
+	reader := bufio.NewReader(in)
+	send := func(msg rpcMessage) {
+		msg.JSONRPC = "2.0"
+		data, _ := json.Marshal(msg)
+		fmt.Fprintln(out, string(data))
+	}
+	readMsg := func() rpcMessage {
+		line, _ := reader.ReadBytes('\n')
+		var msg rpcMessage
+		_ = json.Unmarshal(line, &msg)
+		return msg
+	}
+	reply := func(id *int64, v any) {
+		data, _ := json.Marshal(v)
+		send(rpcMessage{ID: id, Result: data})
+	}
+
+	req := readMsg()
+	switch req.Method {
+	case methodMeta:
+		reply(req.ID, metaResult{
+			ProtocolVersion: 1,
+			ID:              "acme-analytics",
+			Name:            "Acme Analytics",
+			Description:     "Internal analytics backend",
+			Capabilities:    []string{capSetup, capPrompt, capMCP, capProvision},
+			MCPTools:        []string{"mcp__acme__track"},
+			PlannerHint:     "apps that need product analytics",
+		})
+	case methodSetup:
+		var p setupParams
+		_ = json.Unmarshal(req.Params, &p)
+		id := int64(100)
+		params, _ := json.Marshal(uiReadLineParams{Label: "API key"})
+		send(rpcMessage{ID: &id, Method: methodUIReadLine, Params: params})
+		var key string
+		_ = json.Unmarshal(readMsg().Result, &key)
+		reply(req.ID, setupResult{Config: wireConfig{ProjectURL: "https://acme.test/" + p.AppName, PAT: key}})
+	case methodPrompt:
+		var p promptParams
+		_ = json.Unmarshal(req.Params, &p)
+		pat := ""
+		if p.Config != nil {
+			pat = p.Config.PAT
+		}
+		reply(req.ID, promptResult{SystemBlock: fmt.Sprintf("<acme app=%q models=%d pat=%q>", p.AppName, len(p.Models), pat)})
+	case methodMCPServer:
+		reply(req.ID, mcpServerResult{Command: "acme-mcp", Args: []string{"--stdio"}})
+	case methodProvision:
+		send(rpcMessage{ID: req.ID, Error: &rpcError{Code: -32000, Message: "quota exceeded"}})
+	default:
+		send(rpcMessage{ID: req.ID, Error: &rpcError{Code: codeMethodNotFound, Message: "unknown method"}})
+	}
+}
+
+// writeTestPlugin installs a wrapper script in dir that runs this test binary as the fake plugin.
+func writeTestPlugin(t *testing.T, dir string) string {
+	t.Helper()
+	exe, err := os.Executable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "acme")
+	script := fmt.Sprintf("#!/bin/sh\nNANOWAVE_TEST_PLUGIN=1 exec %q -test.run='^TestHelperPlugin$'\n", exe)
+	if err := os.WriteFile(path, []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+type memSecrets map[string]string
+
+func (m memSecrets) Get(key string) (string, error) {
+	if v, ok := m[key]; ok {
+		return v, nil
+	}
+	return "", secrets.ErrNotFound
+}
+func (m memSecrets) Set(key, value string) error { m[key] = value; return nil }
+func (m memSecrets) Delete(key string) error     { delete(m, key); return nil }
+
+func TestDiscover(t *testing.T) {
+	dir := t.TempDir()
+	writeTestPlugin(t, dir)
+	os.WriteFile(filepath.Join(dir, "README.md"), []byte("not a plugin"), 0o644)
+
+	found, errs := Discover(context.Background(), dir)
+	if len(errs) != 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	if len(found) != 1 {
+		t.Fatalf("expected 1 plugin, got %d", len(found))
+	}
+	p := found[0]
+	if p.ID() != "acme-analytics" || p.Meta().Name != "Acme Analytics" {
+		t.Errorf("unexpected meta: %s %+v", p.ID(), p.Meta())
+	}
+	if _, ok := p.(integrations.SetupCapable); !ok {
+		t.Error("plugin declaring setup should be SetupCapable")
+	}
+	if tools := p.(integrations.MCPCapable).MCPTools(); len(tools) != 1 || tools[0] != "mcp__acme__track" {
+		t.Errorf("unexpected MCP tools: %v", tools)
+	}
+
+	if found, errs := Discover(context.Background(), filepath.Join(dir, "missing")); found != nil || errs != nil {
+		t.Errorf("missing dir should be empty, got %v, %v", found, errs)
+	}
+}
+
+func TestDiscover_CachesMeta(t *testing.T) {
+	dir := t.TempDir()
+	path := writeTestPlugin(t, dir)
+	if found, errs := Discover(context.Background(), dir); len(found) != 1 || len(errs) != 0 {
+		t.Fatalf("Discover() = %v, %v; want the plugin", found, errs)
+	}
+
+	// Swap in a plugin that cannot answer, keeping size and modification time:
+	// the cached meta is used without starting it.
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	broken := "#!/bin/sh\nexit 1\n"
+	broken += strings.Repeat("#", int(info.Size())-len(broken))
+	if err := os.WriteFile(path, []byte(broken), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(path, info.ModTime(), info.ModTime()); err != nil {
+		t.Fatal(err)
+	}
+	found, errs := Discover(context.Background(), dir)
+	if len(found) != 1 || len(errs) != 0 || found[0].ID() != "acme-analytics" {
+		t.Fatalf("Discover() with unchanged plugin = %v, %v; want the cached plugin", found, errs)
+	}
+
+	// A replaced executable is asked again, and its failure is remembered too.
+	later := info.ModTime().Add(time.Minute)
+	if err := os.Chtimes(path, later, later); err != nil {
+		t.Fatal(err)
+	}
+	for range 2 {
+		if found, errs := Discover(context.Background(), dir); len(found) != 0 || len(errs) != 1 {
+			t.Errorf("Discover() with replaced plugin = %v, %v; want one error", found, errs)
+		}
+	}
+}
+
+func TestPluginProvider_Calls(t *testing.T) {
+	p, err := Load(context.Background(), writeTestPlugin(t, t.TempDir()))
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	store := integrations.NewIntegrationStoreWithSecrets(t.TempDir(), memSecrets{})
+
+	// Setup round-trips a ui.read_line request through the host.
+	var asked string
+	err = p.(integrations.SetupCapable).Setup(context.Background(), integrations.SetupRequest{
+		Store:      store,
+		AppName:    "MyApp",
+		ReadLineFn: func(label string) string { asked = label; return "key-123" },
+		PrintFn:    func(string, string) {},
+	})
+	if err != nil {
+		t.Fatalf("Setup: %v", err)
+	}
+	if asked != "API key" {
+		t.Errorf("expected ui.read_line prompt, got %q", asked)
+	}
+	cfg, _ := store.GetProvider("acme-analytics", "MyApp")
+	if cfg == nil || cfg.ProjectURL != "https://acme.test/MyApp" || cfg.PAT != "key-123" {
+		t.Fatalf("unexpected stored config: %+v", cfg)
+	}
+
+	contrib, err := p.(integrations.PromptCapable).PromptContribution(context.Background(), integrations.PromptRequest{
+		AppName: "MyApp",
+		Store:   store,
+		Models:  []integrations.ModelRef{{Name: "Event"}},
+	})
+	if err != nil {
+		t.Fatalf("PromptContribution: %v", err)
+	}
+	if contrib.SystemBlock != `<acme app="MyApp" models=1 pat="key-123">` {
+		t.Errorf("unexpected system block: %s", contrib.SystemBlock)
+	}
+
+	mcp, err := p.(integrations.MCPCapable).MCPServer(context.Background(), integrations.MCPRequest{})
+	if err != nil {
+		t.Fatalf("MCPServer: %v", err)
+	}
+	if mcp.Name != "acme-analytics" || mcp.Command != "acme-mcp" {
+		t.Errorf("unexpected MCP config: %+v", mcp)
+	}
+
+	_, err = p.(integrations.ProvisionCapable).Provision(context.Background(), integrations.ProvisionRequest{AppName: "MyApp"})
+	if err == nil || !strings.Contains(err.Error(), "quota exceeded") {
+		t.Errorf("expected plugin error to surface, got %v", err)
+	}
+}
+
+func TestRegister_RejectsConflicts(t *testing.T) {
+	p, err := Load(context.Background(), writeTestPlugin(t, t.TempDir()))
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	r := integrations.NewRegistry()
+	if errs := Register(r, []integrations.Provider{p}); len(errs) != 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	if integrations.LookupIntegration("acme-analytics") == nil {
+		t.Error("plugin should be listed as an integration")
+	}
+	if errs := Register(r, []integrations.Provider{p}); len(errs) != 1 {
+		t.Errorf("expected a conflict error for duplicate ID, got %v", errs)
+	}
+}
+
+func TestConverse_HostMethodsOutsideSetup(t *testing.T) {
+	_, rpcErr := serveHost(rpcMessage{Method: methodUIReadLine}, nil)
+	if rpcErr == nil || rpcErr.Code != codeMethodNotFound {
+		t.Errorf("expected method-not-found outside setup, got %v", rpcErr)
+	}
+}
@@ -0,0 +1,221 @@
+// Package plugin loads integration providers from external executables.
+//
+// A plugin is any executable in ~/nanowave/plugins. For every call nanowave starts
+// the executable, writes one JSON-RPC 2.0 request as a single line on stdin, and
+// reads newline-delimited JSON-RPC messages from stdout until the matching response
+// arrives. Stderr is captured and reported on failure.
+//
+// Methods the host calls (params/results are the wire types below):
+//
+//	meta                 → metaResult        (required; describes the provider and its capabilities)
+//	setup                → setupResult       (capability "setup")
+//	prompt_contribution  → promptResult      (capability "prompt")
+//	mcp_server           → mcpServerResult   (capability "mcp")
+//	provision            → provisionResult   (capability "provision")
+//
+// While handling setup, a plugin may send requests back to the host on stdout to
+// interact with the user; the host answers on stdin:
+//
+//	ui.print      {"level": "info", "message": "..."}    → null
+//	ui.read_line  {"label": "API key"}                    → "..."
+//	ui.pick       {"title": "...", "options": ["a","b"]}  → "a"
+//
+// Capabilities map onto the integrations capability interfaces, so plugin
+// providers go through the same Manager code paths as built-in ones.
+package plugin
+
+import "encoding/json"
+
+// Protocol methods implemented by plugins.
+const (
+	methodMeta      = "meta"
+	methodSetup     = "setup"
+	methodPrompt    = "prompt_contribution"
+	methodMCPServer = "mcp_server"
+	methodProvision = "provision"
+)
+
+// Host methods plugins may call during setup.
+const (
+	methodUIPrint    = "ui.print"
+	methodUIReadLine = "ui.read_line"
+	methodUIPick     = "ui.pick"
+)
+
+// Capability names declared in metaResult.Capabilities.
+const (
+	capSetup     = "setup"
+	capPrompt    = "prompt"
+	capMCP       = "mcp"
+	capProvision = "provision"
+)
+
+// protocolVersion is the version this host speaks; plugins declaring a newer one are rejected.
+const protocolVersion = 1
+
+// rpcMessage is a JSON-RPC 2.0 request, response, or notification.
+type rpcMessage struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      *int64          `json:"id,omitempty"`
+	Method  string          `json:"method,omitempty"`
+	Params  json.RawMessage `json:"params,omitempty"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+// rpcError is a JSON-RPC 2.0 error object.
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *rpcError) Error() string { return e.Message }
+
+// JSON-RPC error codes used by the host.
+const (
+	codeMethodNotFound = -32601
+)
+
+// --- meta ---
+
+type metaResult struct {
+	ProtocolVersion int      `json:"protocol_version"`
+	ID              string   `json:"id"`
+	Name            string   `json:"name"`
+	Description     string   `json:"description,omitempty"`
+	SPMPackage      string   `json:"spm_package,omitempty"`
+	DocsMCPPkg      string   `json:"docs_mcp_package,omitempty"`
+	Capabilities    []string `json:"capabilities"`
+	// Requires lists CLI tools setup depends on; CLIAvailable checks they are on PATH.
+	Requires []string `json:"requires,omitempty"`
+	// MCPTools and AgentTools are the allowlists for the plugin's MCP server.
+	MCPTools   []string `json:"mcp_tools,omitempty"`
+	AgentTools []string `json:"agent_tools,omitempty"`
+	// PlannerHint tells the planner when to add this provider to `integrations`.
+	PlannerHint string `json:"planner_hint,omitempty"`
+}
+
+// --- shared ---
+
+// wireConfig is an app's stored configuration, with secrets resolved.
+type wireConfig struct {
+	ProjectURL string `json:"project_url,omitempty"`
+	ProjectRef string `json:"project_ref,omitempty"`
+	AnonKey    string `json:"anon_key,omitempty"`
+	PAT        string `json:"pat,omitempty"`
+}
+
+type wireModel struct {
+	Name       string         `json:"name"`
+	Storage    string         `json:"storage,omitempty"`
+	Properties []wireProperty `json:"properties,omitempty"`
+}
+
+type wireProperty struct {
+	Name         string `json:"name"`
+	Type         string `json:"type"`
+	DefaultValue string `json:"default_value,omitempty"`
+}
+
+type wireMonetizationPlan struct {
+	Model       string                    `json:"model"`
+	Products    []wireMonetizationProduct `json:"products,omitempty"`
+	Entitlement string                    `json:"entitlement,omitempty"`
+	FreeCredits int                       `json:"free_credits,omitempty"`
+}
+
+type wireMonetizationProduct struct {
+	Identifier  string `json:"identifier"`
+	Type        string `json:"type"`
+	DisplayName string `json:"display_name,omitempty"`
+	Price       string `json:"price,omitempty"`
+	Credits     int    `json:"credits,omitempty"`
+	Duration    string `json:"duration,omitempty"`
+}
+
+// --- setup ---
+
+type setupParams struct {
+	AppName  string      `json:"app_name"`
+	Manual   bool        `json:"manual,omitempty"`
+	Local    bool        `json:"local,omitempty"`
+	WorkDir  string      `json:"work_dir,omitempty"`
+	Existing *wireConfig `json:"existing,omitempty"`
+}
+
+type setupResult struct {
+	Config wireConfig `json:"config"`
+}
+
+type uiPrintParams struct {
+	Level   string `json:"level"`
+	Message string `json:"message"`
+}
+
+type uiReadLineParams struct {
+	Label string `json:"label"`
+}
+
+type uiPickParams struct {
+	Title   string   `json:"title"`
+	Options []string `json:"options"`
+}
+
+// --- prompt_contribution ---
+
+type promptParams struct {
+	AppName            string                `json:"app_name"`
+	BundleID           string                `json:"bundle_id,omitempty"`
+	Models             []wireModel           `json:"models,omitempty"`
+	AuthMethods        []string              `json:"auth_methods,omitempty"`
+	BackendProvisioned bool                  `json:"backend_provisioned"`
+	MonetizationPlan   *wireMonetizationPlan `json:"monetization_plan,omitempty"`
+	Config             *wireConfig           `json:"config,omitempty"`
+}
+
+type promptResult struct {
+	SystemBlock        string `json:"system_block"`
+	UserBlock          string `json:"user_block"`
+	BackendProvisioned bool   `json:"backend_provisioned"`
+}
+
+// --- mcp_server ---
+
+type mcpServerParams struct {
+	PAT        string `json:"pat,omitempty"`
+	ProjectURL string `json:"project_url,omitempty"`
+	ProjectRef string `json:"project_ref,omitempty"`
+}
+
+type mcpServerResult struct {
+	Name    string            `json:"name"`
+	Command string            `json:"command"`
+	Args    []string          `json:"args,omitempty"`
+	Env     map[string]string `json:"env,omitempty"`
+}
+
+// --- provision ---
+
+type provisionParams struct {
+	PAT               string                `json:"pat,omitempty"`
+	ProjectURL        string                `json:"project_url,omitempty"`
+	ProjectRef        string                `json:"project_ref,omitempty"`
+	AppName           string                `json:"app_name"`
+	BundleID          string                `json:"bundle_id,omitempty"`
+	Models            []wireModel           `json:"models,omitempty"`
+	AuthMethods       []string              `json:"auth_methods,omitempty"`
+	NeedsAuth         bool                  `json:"needs_auth"`
+	NeedsDB           bool                  `json:"needs_db"`
+	NeedsStorage      bool                  `json:"needs_storage"`
+	NeedsRealtime     bool                  `json:"needs_realtime"`
+	NeedsMonetization bool                  `json:"needs_monetization"`
+	MonetizationType  string                `json:"monetization_type,omitempty"`
+	MonetizationPlan  *wireMonetizationPlan `json:"monetization_plan,omitempty"`
+}
+
+type provisionResult struct {
+	BackendProvisioned bool     `json:"backend_provisioned"`
+	NeedsAppleSignIn   bool     `json:"needs_apple_sign_in"`
+	TablesCreated      []string `json:"tables_created,omitempty"`
+	Warnings           []string `json:"warnings,omitempty"`
+}
@@ -0,0 +1,427 @@
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/moasq/nanowave/internal/integrations"
+)
+
+// metaTimeout bounds the meta call made while discovering plugins.
+const metaTimeout = 10 * time.Second
+
+// DefaultDir returns the plugin directory, ~/nanowave/plugins.
+func DefaultDir() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, "nanowave", "plugins")
+}
+
+// pluginProvider adapts a plugin executable to the integrations capability interfaces.
+// Methods for capabilities the plugin did not declare return empty results, which the
+// Manager already skips; setup is the exception and lives on setupPluginProvider so
+// that credential-free plugins are not type-asserted as SetupCapable.
+type pluginProvider struct {
+	path string
+	meta metaResult
+}
+
+// setupPluginProvider is a pluginProvider that declared the "setup" capability.
+type setupPluginProvider struct {
+	*pluginProvider
+}
+
+// Load starts the plugin at path, asks for its metadata, and returns the provider.
+func Load(ctx context.Context, path string) (integrations.Provider, error) {
+	meta, err := fetchMeta(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	return newProvider(path, meta), nil
+}
+
+// fetchMeta runs the plugin's meta call and validates the result.
+func fetchMeta(ctx context.Context, path string) (metaResult, error) {
+	ctx, cancel := context.WithTimeout(ctx, metaTimeout)
+	defer cancel()
+
+	var meta metaResult
+	if err := call(ctx, path, methodMeta, struct {
+		ProtocolVersion int `json:"protocol_version"`
+	}{protocolVersion}, &meta, nil); err != nil {
+		return metaResult{}, err
+	}
+	if meta.ID == "" {
+		return metaResult{}, fmt.Errorf("plugin %s: meta is missing id", path)
+	}
+	if meta.ProtocolVersion > protocolVersion {
+		return metaResult{}, fmt.Errorf("plugin %s: protocol version %d is newer than supported (%d)", path, meta.ProtocolVersion, protocolVersion)
+	}
+	if meta.Name == "" {
+		meta.Name = meta.ID
+	}
+	return meta, nil
+}
+
+func newProvider(path string, meta metaResult) integrations.Provider {
+	p := &pluginProvider{path: path, meta: meta}
+	if p.has(capSetup) {
+		return &setupPluginProvider{p}
+	}
+	return p
+}
+
+// Discover loads every executable in dir as a plugin. A missing directory yields
+// no providers. Plugins that fail to load are reported in errs and skipped.
+// Meta results and failures are cached in dir by executable size and modification
+// time, so a plugin only starts here again after it is replaced.
+func Discover(ctx context.Context, dir string) (providers []integrations.Provider, errs []error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, []error{err}
+	}
+
+	infos := map[string]os.FileInfo{}
+	var names []string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil || info.Mode()&0o111 == 0 {
+			continue // not executable (README, manifests, ...)
+		}
+		infos[entry.Name()] = info
+		names = append(names, entry.Name())
+	}
+	sort.Strings(names)
+
+	cache := readMetaCache(dir)
+	fresh := make(map[string]metaCacheEntry, len(names))
+	changed := len(cache) != len(names)
+	for _, name := range names {
+		path := filepath.Join(dir, name)
+		info := infos[name]
+		entry, ok := cache[name]
+		if !ok || entry.Size != info.Size() || !entry.ModTime.Equal(info.ModTime()) {
+			meta, err := fetchMeta(ctx, path)
+			if ctx.Err() != nil {
+				// Interrupted, not the plugin's fault: try again next time.
+				errs = append(errs, err)
+				continue
+			}
+			entry = metaCacheEntry{Size: info.Size(), ModTime: info.ModTime()}
+			if err != nil {
+				entry.Error = err.Error()
+			} else {
+				entry.Meta = &meta
+			}
+			changed = true
+		}
+		fresh[name] = entry
+
+		if entry.Meta == nil {
+			errs = append(errs, errors.New(entry.Error))
+			continue
+		}
+		providers = append(providers, newProvider(path, *entry.Meta))
+	}
+	if changed {
+		writeMetaCache(dir, fresh)
+	}
+	return providers, errs
+}
+
+// metaCacheFile holds the meta results of the plugins in a plugin directory.
+const metaCacheFile = ".meta-cache.json"
+
+// metaCacheEntry is the meta result, or the failure, of one plugin executable.
+type metaCacheEntry struct {
+	Size    int64       `json:"size"`
+	ModTime time.Time   `json:"mod_time"`
+	Meta    *metaResult `json:"meta,omitempty"`
+	Error   string      `json:"error,omitempty"`
+}
+
+// readMetaCache returns the cached meta results by executable name. A missing or
+// unreadable cache is empty.
+func readMetaCache(dir string) map[string]metaCacheEntry {
+	data, err := os.ReadFile(filepath.Join(dir, metaCacheFile))
+	if err != nil {
+		return nil
+	}
+	var cache map[string]metaCacheEntry
+	if json.Unmarshal(data, &cache) != nil {
+		return nil
+	}
+	return cache
+}
+
+// writeMetaCache saves the meta results. Failing to save only costs the next
+// Discover another meta call per plugin.
+func writeMetaCache(dir string, cache map[string]metaCacheEntry) {
+	data, err := json.MarshalIndent(cache, "", "  ")
+	if err != nil {
+		return
+	}
+	_ = os.WriteFile(filepath.Join(dir, metaCacheFile), data, 0o644)
+}
+
+// Register adds discovered plugin providers to the registry and the curated
+// integration list. Plugins whose ID is already registered are rejected.
+func Register(r *integrations.Registry, providers []integrations.Provider) []error {
+	var errs []error
+	for _, p := range providers {
+		if _, exists := r.Get(p.ID()); exists {
+			errs = append(errs, fmt.Errorf("plugin provider %q conflicts with an existing provider", p.ID()))
+			continue
+		}
+		r.Register(p)
+		m := p.Meta()
+		integrations.RegisterIntegration(&integrations.CuratedIntegration{
+			ID:          p.ID(),
+			Name:        m.Name,
+			Description: m.Description,
+			SPMPackage:  m.SPMPackage,
+			MCPCommand:  m.MCPCommand,
+			MCPArgs:     m.MCPArgs,
+			DocsMCPPkg:  m.DocsMCPPkg,
+		})
+	}
+	return errs
+}
+
+func (p *pluginProvider) has(capability string) bool {
+	return slices.Contains(p.meta.Capabilities, capability)
+}
+
+// ID returns the provider ID declared by the plugin.
+func (p *pluginProvider) ID() integrations.ProviderID {
+	return integrations.ProviderID(p.meta.ID)
+}
+
+// Meta returns display metadata declared by the plugin.
+func (p *pluginProvider) Meta() integrations.ProviderMeta {
+	return integrations.ProviderMeta{
+		Name:        p.meta.Name,
+		Description: p.meta.Description,
+		SPMPackage:  p.meta.SPMPackage,
+		DocsMCPPkg:  p.meta.DocsMCPPkg,
+	}
+}
+
+// PlannerHint tells the planner when to select this provider.
+func (p *pluginProvider) PlannerHint() string {
+	return p.meta.PlannerHint
+}
+
+// PromptContribution asks the plugin for its build prompt content.
+func (p *pluginProvider) PromptContribution(ctx context.Context, req integrations.PromptRequest) (*integrations.PromptContribution, error) {
+	if !p.has(capPrompt) {
+		return nil, nil
+	}
+	params := promptParams{
+		AppName:            req.AppName,
+		BundleID:           req.BundleID,
+		Models:             toWireModels(req.Models),
+		AuthMethods:        req.AuthMethods,
+		BackendProvisioned: req.BackendProvisioned,
+		MonetizationPlan:   toWirePlan(req.MonetizationPlan),
+	}
+	if req.Store != nil {
+		if cfg, _ := req.Store.GetProvider(p.ID(), req.AppName); cfg != nil {
+			params.Config = toWireConfig(cfg)
+		}
+	}
+	var res promptResult
+	if err := call(ctx, p.path, methodPrompt, params, &res, nil); err != nil {
+		return nil, err
+	}
+	return &integrations.PromptContribution{
+		SystemBlock:        res.SystemBlock,
+		UserBlock:          res.UserBlock,
+		BackendProvisioned: res.BackendProvisioned || req.BackendProvisioned,
+	}, nil
+}
+
+// MCPServer asks the plugin for its MCP server entry.
+func (p *pluginProvider) MCPServer(ctx context.Context, req integrations.MCPRequest) (*integrations.MCPServerConfig, error) {
+	if !p.has(capMCP) {
+		return nil, nil
+	}
+	var res mcpServerResult
+	if err := call(ctx, p.path, methodMCPServer, mcpServerParams{
+		PAT:        req.PAT,
+		ProjectURL: req.ProjectURL,
+		ProjectRef: req.ProjectRef,
+	}, &res, nil); err != nil {
+		return nil, err
+	}
+	if res.Command == "" {
+		return nil, nil
+	}
+	if res.Name == "" {
+		res.Name = p.meta.ID
+	}
+	return &integrations.MCPServerConfig{Name: res.Name, Command: res.Command, Args: res.Args, Env: res.Env}, nil
+}
+
+// MCPTools returns the MCP tool allowlist declared in meta.
+func (p *pluginProvider) MCPTools() []string {
+	if !p.has(capMCP) {
+		return nil
+	}
+	return p.meta.MCPTools
+}
+
+// AgentTools returns the agentic build allowlist declared in meta.
+func (p *pluginProvider) AgentTools() []string {
+	if !p.has(capMCP) {
+		return nil
+	}
+	return p.meta.AgentTools
+}
+
+// Provision asks the plugin to create backend resources.
+func (p *pluginProvider) Provision(ctx context.Context, req integrations.ProvisionRequest) (*integrations.ProvisionResult, error) {
+	if !p.has(capProvision) {
+		return nil, nil
+	}
+	var res provisionResult
+	if err := call(ctx, p.path, methodProvision, provisionParams{
+		PAT:               req.PAT,
+		ProjectURL:        req.ProjectURL,
+		ProjectRef:        req.ProjectRef,
+		AppName:           req.AppName,
+		BundleID:          req.BundleID,
+		Models:            toWireModels(req.Models),
+		AuthMethods:       req.AuthMethods,
+		NeedsAuth:         req.NeedsAuth,
+		NeedsDB:           req.NeedsDB,
+		NeedsStorage:      req.NeedsStorage,
+		NeedsRealtime:     req.NeedsRealtime,
+		NeedsMonetization: req.NeedsMonetization,
+		MonetizationType:  req.MonetizationType,
+		MonetizationPlan:  toWirePlan(req.MonetizationPlan),
+	}, &res, nil); err != nil {
+		return nil, err
+	}
+	return &integrations.ProvisionResult{
+		BackendProvisioned: res.BackendProvisioned,
+		NeedsAppleSignIn:   res.NeedsAppleSignIn,
+		TablesCreated:      res.TablesCreated,
+		Warnings:           res.Warnings,
+	}, nil
+}
+
+// Setup runs the plugin's setup flow and stores the config it returns.
+func (p *setupPluginProvider) Setup(ctx context.Context, req integrations.SetupRequest) error {
+	params := setupParams{
+		AppName: req.AppName,
+		Manual:  req.Manual,
+		Local:   req.Local,
+		WorkDir: req.WorkDir,
+	}
+	if req.Store != nil {
+		if cfg, _ := req.Store.GetProvider(p.ID(), req.AppName); cfg != nil {
+			params.Existing = toWireConfig(cfg)
+		}
+	}
+	ui := &hostUI{print: req.PrintFn, readLine: req.ReadLineFn, pick: req.PickFn}
+
+	var res setupResult
+	if err := call(ctx, p.path, methodSetup, params, &res, ui); err != nil {
+		return err
+	}
+	return req.Store.SetProvider(integrations.IntegrationConfig{
+		Provider:   p.ID(),
+		ProjectURL: res.Config.ProjectURL,
+		ProjectRef: res.Config.ProjectRef,
+		AnonKey:    res.Config.AnonKey,
+		PAT:        res.Config.PAT,
+	}, req.AppName)
+}
+
+// Remove deletes the stored config (and its secrets) for an app.
+func (p *setupPluginProvider) Remove(_ context.Context, store *integrations.IntegrationStore, appName string) error {
+	return store.RemoveProvider(p.ID(), appName)
+}
+
+// Status returns the stored integration status for an app.
+func (p *setupPluginProvider) Status(_ context.Context, store *integrations.IntegrationStore, appName string) (integrations.ProviderStatus, error) {
+	cfg, err := store.GetProvider(p.ID(), appName)
+	if err != nil {
+		return integrations.ProviderStatus{}, err
+	}
+	if cfg == nil {
+		return integrations.ProviderStatus{Configured: false}, nil
+	}
+	return integrations.ProviderStatus{
+		Configured: true,
+		ProjectURL: cfg.ProjectURL,
+		HasAnonKey: cfg.AnonKey != "",
+		HasPAT:     cfg.PAT != "",
+	}, nil
+}
+
+// CLIAvailable reports whether every tool the plugin requires is on PATH.
+func (p *setupPluginProvider) CLIAvailable() bool {
+	for _, tool := range p.meta.Requires {
+		if _, err := exec.LookPath(tool); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+func toWireConfig(cfg *integrations.IntegrationConfig) *wireConfig {
+	return &wireConfig{
+		ProjectURL: cfg.ProjectURL,
+		ProjectRef: cfg.ProjectRef,
+		AnonKey:    cfg.AnonKey,
+		PAT:        cfg.PAT,
+	}
+}
+
+func toWireModels(models []integrations.ModelRef) []wireModel {
+	var out []wireModel
+	for _, m := range models {
+		wm := wireModel{Name: m.Name, Storage: m.Storage}
+		for _, prop := range m.Properties {
+			wm.Properties = append(wm.Properties, wireProperty{Name: prop.Name, Type: prop.Type, DefaultValue: prop.DefaultValue})
+		}
+		out = append(out, wm)
+	}
+	return out
+}
+
+func toWirePlan(plan *integrations.MonetizationPlan) *wireMonetizationPlan {
+	if plan == nil {
+		return nil
+	}
+	wp := &wireMonetizationPlan{Model: plan.Model, Entitlement: plan.Entitlement, FreeCredits: plan.FreeCredits}
+	for _, prod := range plan.Products {
+		wp.Products = append(wp.Products, wireMonetizationProduct{
+			Identifier:  prod.Identifier,
+			Type:        prod.Type,
+			DisplayName: prod.DisplayName,
+			Price:       prod.Price,
+			Credits:     prod.Credits,
+			Duration:    prod.Duration,
+		})
+	}
+	return wp
+}
@@ -4,8 +4,12 @@
 package providers
 
 import (
+	"context"
+
 	"github.com/moasq/nanowave/internal/integrations"
+	"github.com/moasq/nanowave/internal/integrations/plugin"
 	"github.com/moasq/nanowave/internal/integrations/providers/revenuecat"
+	"github.com/moasq/nanowave/internal/integrations/providers/storekit"
 	"github.com/moasq/nanowave/internal/integrations/providers/supabase"
 )
 
@@ -15,5 +19,14 @@ import (
 func RegisterAll(r *integrations.Registry) {
 	r.Register(supabase.New())
 	r.Register(revenuecat.New())
+	r.Register(storekit.New())
 	// r.Register(appstoreconnect.New())  // future
 }
+
+// RegisterPlugins discovers external provider plugins in dir (see package plugin)
+// and registers them after the built-in providers. Plugins that fail to load or
+// collide with a built-in ID are skipped and reported in the returned errors.
+func RegisterPlugins(ctx context.Context, r *integrations.Registry, dir string) []error {
+	found, errs := plugin.Discover(ctx, dir)
+	return append(errs, plugin.Register(r, found)...)
+}
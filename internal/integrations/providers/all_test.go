@@ -28,9 +28,14 @@ func TestRegisterAll(t *testing.T) {
 		t.Errorf("got ID %q, want %q", p2.ID(), integrations.ProviderRevenueCat)
 	}
 
-	// Should have exactly 2 providers
+	// Should have StoreKit native registered
+	if _, ok := r.Get(integrations.ProviderStoreKitNative); !ok {
+		t.Fatal("expected StoreKit native to be registered")
+	}
+
+	// Should have exactly 3 providers
 	all := r.All()
-	if len(all) != 2 {
-		t.Errorf("expected 2 providers, got %d", len(all))
+	if len(all) != 3 {
+		t.Errorf("expected 3 providers, got %d", len(all))
 	}
 }
@@ -0,0 +1,163 @@
+package storekit
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/moasq/nanowave/internal/integrations"
+)
+
+// verifyFunctionSlug is the Edge Function name the app invokes for verification.
+const verifyFunctionSlug = "verify-transaction"
+
+// appStoreSecrets are the Edge Function secrets the user must set before
+// server-side verification works (App Store Connect → Users and Access → Integrations → In-App Purchase).
+var appStoreSecrets = []string{"APPSTORE_ISSUER_ID", "APPSTORE_KEY_ID", "APPSTORE_PRIVATE_KEY"}
+
+// purchasesTableSQL creates the table the Edge Function records verified transactions in.
+// Clients may only read their own rows; writes happen with the service role inside the function.
+const purchasesTableSQL = `CREATE TABLE IF NOT EXISTS public.purchases (
+  transaction_id TEXT PRIMARY KEY,
+  original_transaction_id TEXT NOT NULL,
+  user_id UUID REFERENCES auth.users(id) ON DELETE SET NULL,
+  product_id TEXT NOT NULL,
+  environment TEXT NOT NULL,
+  expires_at TIMESTAMPTZ,
+  revoked BOOLEAN NOT NULL DEFAULT false,
+  credits INTEGER NOT NULL DEFAULT 0,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+ALTER TABLE public.purchases ENABLE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS "purchases_select" ON public.purchases;
+CREATE POLICY "purchases_select" ON public.purchases FOR SELECT USING (auth.uid() = user_id);
+`
+
+// verifyTransactionSource returns the TypeScript source of the verify-transaction Edge Function.
+// The function looks the transaction up with the App Store Server API (so the client's claim
+// is never trusted), checks the bundle ID, and records the result in public.purchases. Only the
+// signed-in user whose ID is the transaction's appAccountToken may record it, and never over
+// another user's row for the same original transaction.
+func verifyTransactionSource(bundleID string, plan *integrations.MonetizationPlan) string {
+	return fmt.Sprintf(verifyTransactionTemplate, bundleID, creditsTable(plan))
+}
+
+// creditsTable renders the product → credits map for consumable products as a JSON object.
+func creditsTable(plan *integrations.MonetizationPlan) string {
+	credits := make(map[string]int)
+	if plan != nil {
+		for _, p := range plan.Products {
+			if p.Credits > 0 {
+				credits[p.Identifier] = p.Credits
+			}
+		}
+	}
+	data, _ := json.Marshal(credits) // encoding/json sorts map keys — stable output
+	return string(data)
+}
+
+const verifyTransactionTemplate = `// verify-transaction — generated by nanowave (storekit-native provider).
+// Verifies a StoreKit 2 transaction with the App Store Server API and records it.
+import { createClient } from "npm:@supabase/supabase-js@2";
+import { decodeJwt, importPKCS8, SignJWT } from "npm:jose@5";
+
+const BUNDLE_ID = Deno.env.get("APPSTORE_BUNDLE_ID") ?? "%s";
+const CREDITS: Record<string, number> = %s;
+const APPLE_HOSTS = [
+  "https://api.storekit.itunes.apple.com",
+  "https://api.storekit-sandbox.itunes.apple.com",
+];
+
+function json(body: unknown, status = 200): Response {
+  return new Response(JSON.stringify(body), {
+    status,
+    headers: { "Content-Type": "application/json" },
+  });
+}
+
+async function appStoreToken(): Promise<string> {
+  const issuer = Deno.env.get("APPSTORE_ISSUER_ID");
+  const keyId = Deno.env.get("APPSTORE_KEY_ID");
+  const privateKey = Deno.env.get("APPSTORE_PRIVATE_KEY");
+  if (!issuer || !keyId || !privateKey) {
+    throw new Error("App Store Server API credentials are not configured");
+  }
+  const key = await importPKCS8(privateKey.replaceAll("\\n", "\n"), "ES256");
+  return await new SignJWT({ bid: BUNDLE_ID })
+    .setProtectedHeader({ alg: "ES256", kid: keyId, typ: "JWT" })
+    .setIssuer(issuer)
+    .setIssuedAt()
+    .setExpirationTime("5m")
+    .setAudience("appstoreconnect-v1")
+    .sign(key);
+}
+
+async function lookupTransaction(transactionId: string): Promise<Record<string, unknown>> {
+  const token = await appStoreToken();
+  for (const host of APPLE_HOSTS) {
+    const res = await fetch(host + "/inApps/v1/transactions/" + transactionId, {
+      headers: { Authorization: "Bearer " + token },
+    });
+    if (res.status === 404) continue; // not in this environment — try sandbox
+    if (!res.ok) throw new Error("App Store Server API returned " + res.status);
+    const body = await res.json();
+    return decodeJwt(body.signedTransactionInfo);
+  }
+  throw new Error("transaction not found");
+}
+
+Deno.serve(async (req) => {
+  if (req.method !== "POST") return json({ error: "method not allowed" }, 405);
+
+  const supabase = createClient(
+    Deno.env.get("SUPABASE_URL")!,
+    Deno.env.get("SUPABASE_SERVICE_ROLE_KEY")!,
+  );
+  const jwt = (req.headers.get("Authorization") ?? "").replace("Bearer ", "");
+  const { data: { user } } = await supabase.auth.getUser(jwt);
+  if (!user) return json({ error: "sign in required" }, 401);
+
+  const { transaction_id } = await req.json();
+  if (!transaction_id) return json({ error: "transaction_id is required" }, 400);
+
+  try {
+    const tx = await lookupTransaction(String(transaction_id));
+    if (tx.bundleId !== BUNDLE_ID) return json({ error: "bundle ID mismatch" }, 400);
+    // The app purchases with appAccountToken set to the user's ID, so a
+    // transaction can only be claimed by the account that made it.
+    if (String(tx.appAccountToken ?? "").toLowerCase() !== user.id.toLowerCase()) {
+      return json({ error: "transaction belongs to another account" }, 403);
+    }
+    const { data: owners, error: ownerError } = await supabase.from("purchases")
+      .select("user_id")
+      .eq("original_transaction_id", String(tx.originalTransactionId))
+      .neq("user_id", user.id)
+      .limit(1);
+    if (ownerError) return json({ error: ownerError.message }, 500);
+    if (owners && owners.length > 0) {
+      return json({ error: "transaction belongs to another account" }, 403);
+    }
+
+    const productId = String(tx.productId);
+    const expiresAt = typeof tx.expiresDate === "number" ? new Date(tx.expiresDate).toISOString() : null;
+    const revoked = Boolean(tx.revocationDate);
+    const active = !revoked && (expiresAt === null || new Date(expiresAt) > new Date());
+    const credits = CREDITS[productId] ?? 0;
+
+    const { error } = await supabase.from("purchases").upsert({
+      transaction_id: String(tx.transactionId),
+      original_transaction_id: String(tx.originalTransactionId),
+      user_id: user.id,
+      product_id: productId,
+      environment: String(tx.environment),
+      expires_at: expiresAt,
+      revoked,
+      credits,
+    }, { onConflict: "transaction_id" });
+    if (error) return json({ error: error.message }, 500);
+
+    return json({ active, product_id: productId, expires_at: expiresAt, credits });
+  } catch (err) {
+    return json({ error: String(err) }, 502);
+  }
+});
+`
@@ -0,0 +1,286 @@
+package storekit
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/moasq/nanowave/internal/integrations"
+)
+
+// PromptContribution generates the StoreKit native prompt content: AppConfig constants,
+// the StoreManager purchase manager, and — when the app has a Supabase MCP connection —
+// the verify-transaction Edge Function to deploy.
+func (s *storekitProvider) PromptContribution(_ context.Context, req integrations.PromptRequest) (*integrations.PromptContribution, error) {
+	var supabaseCfg *integrations.IntegrationConfig
+	if req.Store != nil {
+		supabaseCfg, _ = req.Store.GetProvider(integrations.ProviderSupabase, req.AppName)
+	}
+	serverVerify := supabaseCfg != nil && supabaseCfg.PAT != ""
+
+	entitlementID := "premium"
+	if req.MonetizationPlan != nil && req.MonetizationPlan.Entitlement != "" {
+		entitlementID = req.MonetizationPlan.Entitlement
+	}
+
+	var system strings.Builder
+	system.WriteString("\n<storekit-config>\n")
+	system.WriteString("Monetization uses StoreKit 2 directly. Do NOT add RevenueCat or any other purchases SDK.\n")
+	system.WriteString("A .storekit configuration with every product below is already in the app folder for local testing.\n\n")
+
+	// AppConfig constants
+	system.WriteString("## AppConfig.swift (REQUIRED — add these constants)\n\n")
+	system.WriteString("```swift\n")
+	system.WriteString("import Foundation\n\n")
+	system.WriteString("enum AppConfig {\n")
+	fmt.Fprintf(&system, "    static let entitlementID = %q\n", entitlementID)
+	if serverVerify {
+		fmt.Fprintf(&system, "    static let verifyTransactionFunction = %q\n", verifyFunctionSlug)
+	}
+	system.WriteString("\n    enum ProductID {\n")
+	var constNames []string
+	if req.MonetizationPlan != nil {
+		for _, p := range req.MonetizationPlan.Products {
+			constName := productIdentifierToConstName(p.Identifier)
+			constNames = append(constNames, constName)
+			fmt.Fprintf(&system, "        static let %s = %q\n", constName, p.Identifier)
+		}
+	}
+	fmt.Fprintf(&system, "        static let all: [String] = [%s]\n", strings.Join(constNames, ", "))
+	system.WriteString("    }\n")
+	system.WriteString("}\n")
+	system.WriteString("```\n\n")
+
+	// Products table
+	if req.MonetizationPlan != nil && len(req.MonetizationPlan.Products) > 0 {
+		system.WriteString("## Products\n\n")
+		system.WriteString("| Identifier | Type | Display Name | Duration | Credits |\n")
+		system.WriteString("|---|---|---|---|---|\n")
+		for _, p := range req.MonetizationPlan.Products {
+			dur := "-"
+			if p.Duration != "" {
+				dur = p.Duration
+			}
+			fmt.Fprintf(&system, "| %s | %s | %s | %s | %d |\n", p.Identifier, p.Type, p.DisplayName, dur, p.Credits)
+		}
+		system.WriteString("\n")
+	}
+
+	system.WriteString("## REQUIRED: StoreManager (Services/Subscription/StoreManager.swift)\n\n")
+	system.WriteString("StoreManager is the ONLY type that touches StoreKit. Create it exactly like this:\n\n")
+	system.WriteString(storeManagerSource(serverVerify))
+	system.WriteString("\n")
+
+	system.WriteString(`## Paywall Rules
+
+- PaywallView uses StoreManager.shared directly — no PaywallViewModel
+- Iterate manager.products ([Product] from StoreKit) — NEVER custom plan enums or hardcoded "$X.XX" strings
+- Prices from product.displayPrice, names from product.displayName, descriptions from product.description
+- Present the paywall with .fullScreenCover, never .sheet
+- Close button immediately visible; Restore Purchases visible without scrolling
+- Full billed amount is the most prominent price (minimum 16pt font); no fake urgency
+- Terms of Service and Privacy Policy as tappable in-app links
+
+`)
+
+	if serverVerify {
+		system.WriteString("## Server-side verification (Supabase Edge Function)\n\n")
+		system.WriteString("Before writing Swift code:\n")
+		system.WriteString("1. Create the purchases table with mcp__supabase__execute_sql:\n")
+		system.WriteString("```sql\n")
+		system.WriteString(purchasesTableSQL)
+		system.WriteString("```\n")
+		fmt.Fprintf(&system, "2. Deploy the function with mcp__supabase__deploy_edge_function: name %q, verify_jwt true, files [{\"name\": \"index.ts\", \"content\": <source below>}].\n", verifyFunctionSlug)
+		system.WriteString("```typescript\n")
+		system.WriteString(verifyTransactionSource(req.BundleID, req.MonetizationPlan))
+		system.WriteString("```\n")
+		fmt.Fprintf(&system, "3. Do NOT set %s yourself — the user adds their In-App Purchase key as Edge Function secrets.\n", strings.Join(appStoreSecrets, ", "))
+		system.WriteString("Until they do, the function returns an error and StoreManager keeps the on-device verified result.\n\n")
+	} else {
+		system.WriteString("No Supabase MCP connection — StoreManager relies on StoreKit's on-device verification only.\n\n")
+	}
+
+	system.WriteString("</storekit-config>\n")
+
+	userBlock := `STOREKIT NATIVE MONETIZATION:
+Use StoreKit 2 only (no RevenueCat). Follow the StoreManager pattern in <storekit-config> exactly.
+Prices MUST come from Product.displayPrice — NEVER create custom plan enums with price strings.
+`
+	if serverVerify {
+		userBlock += fmt.Sprintf("Create the purchases table and deploy the %q Edge Function BEFORE writing Swift code.\n", verifyFunctionSlug)
+	}
+	userBlock += "\n"
+
+	return &integrations.PromptContribution{
+		SystemBlock:        system.String(),
+		UserBlock:          userBlock,
+		BackendProvisioned: req.BackendProvisioned,
+	}, nil
+}
+
+// storeManagerSource returns the StoreManager Swift source the builder must create.
+// With serverVerify, every verified transaction is also sent to the Edge Function.
+func storeManagerSource(serverVerify bool) string {
+	var b strings.Builder
+	b.WriteString("```swift\n")
+	b.WriteString("import Foundation\nimport StoreKit\n")
+	if serverVerify {
+		b.WriteString("import Supabase\n")
+	}
+	b.WriteString(`
+@Observable
+@MainActor
+final class StoreManager {
+    static let shared = StoreManager()
+
+    var isPremium = false
+    var products: [Product] = []          // <-- StoreKit Product objects, NOT custom plan models
+    var selectedProduct: Product?
+    var purchasedProductIDs: Set<String> = []
+    var isLoading = false
+    var errorMessage: String?
+
+    private var updatesTask: Task<Void, Never>?
+
+    private init() {}
+
+    /// Call once from App.init() — listens for renewals, refunds, and Ask to Buy approvals.
+    func configure() {
+        updatesTask = Task { await listenForTransactions() }
+        Task { await refreshEntitlements() }
+    }
+
+    func loadProducts() async {
+        isLoading = true
+        defer { isLoading = false }
+        do {
+            products = try await Product.products(for: AppConfig.ProductID.all)
+                .sorted { $0.price < $1.price }
+            if selectedProduct == nil {
+                selectedProduct = products.first
+            }
+        } catch {
+            errorMessage = "Could not load plans. Please try again."
+        }
+    }
+
+    func purchase(_ product: Product) async {      // <-- takes Product, NOT a custom plan enum
+        isLoading = true
+        defer { isLoading = false }
+        do {
+`)
+	if serverVerify {
+		b.WriteString("            switch try await product.purchase(options: purchaseOptions()) {\n")
+	} else {
+		b.WriteString("            switch try await product.purchase() {\n")
+	}
+	b.WriteString(`            case .success(let verification):
+                try await handle(verification)
+            case .userCancelled, .pending:
+                break
+            @unknown default:
+                break
+            }
+        } catch {
+            errorMessage = error.localizedDescription
+        }
+    }
+
+    func restore() async {
+        isLoading = true
+        defer { isLoading = false }
+        do {
+            try await AppStore.sync()
+            await refreshEntitlements()
+            if !isPremium {
+                errorMessage = "No active purchases found."
+            }
+        } catch {
+            errorMessage = error.localizedDescription
+        }
+    }
+
+    private func refreshEntitlements() async {
+        var active: Set<String> = []
+        for await result in Transaction.currentEntitlements {
+            if case .verified(let transaction) = result, transaction.revocationDate == nil {
+                active.insert(transaction.productID)
+            }
+        }
+        purchasedProductIDs = active
+        isPremium = !active.isEmpty
+    }
+
+    private func listenForTransactions() async {
+        for await result in Transaction.updates {
+            try? await handle(result)
+        }
+    }
+
+    private func handle(_ result: VerificationResult<Transaction>) async throws {
+        guard case .verified(let transaction) = result else {
+            throw StoreError.failedVerification
+        }
+`)
+	if serverVerify {
+		b.WriteString("        await verifyOnServer(transaction)\n")
+	}
+	b.WriteString(`        await transaction.finish()
+        await refreshEntitlements()
+    }
+`)
+	if serverVerify {
+		b.WriteString(`
+    /// Ties the purchase to the signed-in user. The Edge Function only records
+    /// transactions whose appAccountToken is the caller's user ID.
+    private func purchaseOptions() -> Set<Product.PurchaseOption> {
+        guard let userID = SupabaseService.shared.client.auth.currentUser?.id else { return [] }
+        return [.appAccountToken(userID)]
+    }
+
+    /// Records the transaction server-side. The Edge Function re-fetches it from Apple,
+    /// so failures here never block the on-device verified purchase.
+    private func verifyOnServer(_ transaction: Transaction) async {
+        struct VerifyRequest: Encodable { let transaction_id: String }
+        struct VerifyResponse: Decodable { let active: Bool }
+        do {
+            let _: VerifyResponse = try await SupabaseService.shared.client.functions.invoke(
+                AppConfig.verifyTransactionFunction,
+                options: FunctionInvokeOptions(body: VerifyRequest(transaction_id: String(transaction.id)))
+            )
+        } catch {
+            #if DEBUG
+            print("[StoreManager] server verification failed: \(error)")
+            #endif
+        }
+    }
+`)
+	}
+	b.WriteString(`}
+
+enum StoreError: LocalizedError {
+    case failedVerification
+
+    var errorDescription: String? {
+        "The purchase could not be verified."
+    }
+}
+`)
+	b.WriteString("```\n")
+	return b.String()
+}
+
+// productIdentifierToConstName converts "premium_monthly" to "premiumMonthly".
+func productIdentifierToConstName(identifier string) string {
+	parts := strings.FieldsFunc(identifier, func(r rune) bool { return r == '_' || r == '.' || r == '-' })
+	if len(parts) <= 1 {
+		return identifier
+	}
+	var b strings.Builder
+	b.WriteString(parts[0])
+	for _, part := range parts[1:] {
+		b.WriteString(strings.ToUpper(part[:1]))
+		b.WriteString(part[1:])
+	}
+	return b.String()
+}
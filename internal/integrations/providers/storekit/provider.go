@@ -0,0 +1,37 @@
+// Package storekit implements the "storekit-native" monetization provider:
+// StoreKit 2 purchases with no third-party SDK, verified server-side by a
+// Supabase Edge Function that the builder deploys through the supabase MCP tools.
+// Pattern: same as revenuecat — consumes the planner's MonetizationPlan.
+package storekit
+
+import (
+	"github.com/moasq/nanowave/internal/integrations"
+)
+
+// storekitProvider implements integrations.Provider plus prompt and provision capabilities.
+// It has no credentials of its own, so it is not SetupCapable; server-side
+// verification reuses the app's Supabase integration when one is configured.
+type storekitProvider struct{}
+
+// New creates a new StoreKit native provider.
+func New() integrations.Provider {
+	return &storekitProvider{}
+}
+
+func (s *storekitProvider) ID() integrations.ProviderID {
+	return integrations.ProviderStoreKitNative
+}
+
+func (s *storekitProvider) Meta() integrations.ProviderMeta {
+	return integrations.ProviderMeta{
+		Name:        "StoreKit Native",
+		Description: "StoreKit 2 purchases with Edge Function receipt validation (no SDK)",
+	}
+}
+
+// Compile-time interface checks.
+var (
+	_ integrations.Provider         = (*storekitProvider)(nil)
+	_ integrations.PromptCapable    = (*storekitProvider)(nil)
+	_ integrations.ProvisionCapable = (*storekitProvider)(nil)
+)
@@ -0,0 +1,171 @@
+package storekit
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/moasq/nanowave/internal/integrations"
+)
+
+func testPlan() *integrations.MonetizationPlan {
+	return &integrations.MonetizationPlan{
+		Model:       "hybrid",
+		Entitlement: "pro",
+		Products: []integrations.MonetizationProduct{
+			{Identifier: "pro_monthly", Type: "subscription", DisplayName: "Monthly", Duration: "P1M"},
+			{Identifier: "credits_10", Type: "consumable", DisplayName: "10 Credits", Credits: 10},
+		},
+	}
+}
+
+// storeWithSupabase returns a store where TestApp has a Supabase config with the given PAT.
+func storeWithSupabase(t *testing.T, pat string) *integrations.IntegrationStore {
+	t.Helper()
+	dir := t.TempDir()
+	storeData := map[string]any{
+		"providers": map[string]any{
+			"supabase": map[string]any{
+				"TestApp": map[string]any{
+					"provider":    "supabase",
+					"project_url": "https://test.supabase.co",
+					"project_ref": "test",
+					"anon_key":    "test-key",
+					"pat":         pat,
+				},
+			},
+		},
+	}
+	data, _ := json.MarshalIndent(storeData, "", "  ")
+	os.WriteFile(filepath.Join(dir, "integrations.json"), data, 0o644)
+	store := integrations.NewIntegrationStore(dir)
+	if err := store.Load(); err != nil {
+		t.Fatalf("store.Load: %v", err)
+	}
+	return store
+}
+
+func TestProvider_ID(t *testing.T) {
+	p := New()
+	if p.ID() != integrations.ProviderStoreKitNative {
+		t.Errorf("got ID %q, want %q", p.ID(), integrations.ProviderStoreKitNative)
+	}
+	if p.Meta().SPMPackage != "" {
+		t.Errorf("StoreKit native must not pull in an SPM package, got %q", p.Meta().SPMPackage)
+	}
+	if _, ok := p.(integrations.SetupCapable); ok {
+		t.Error("StoreKit native has no credentials and should not be SetupCapable")
+	}
+}
+
+func TestProvider_PromptContribution_ServerVerification(t *testing.T) {
+	pc := New().(integrations.PromptCapable)
+	contrib, err := pc.PromptContribution(context.Background(), integrations.PromptRequest{
+		AppName:          "TestApp",
+		BundleID:         "com.example.testapp",
+		Store:            storeWithSupabase(t, "sbp_test"),
+		MonetizationPlan: testPlan(),
+	})
+	if err != nil {
+		t.Fatalf("PromptContribution error: %v", err)
+	}
+	for _, want := range []string{
+		"<storekit-config>",
+		"static let proMonthly = \"pro_monthly\"",
+		"static let all: [String] = [proMonthly, credits10]",
+		"Transaction.updates",
+		"mcp__supabase__deploy_edge_function",
+		"CREATE TABLE IF NOT EXISTS public.purchases",
+		`?? "com.example.testapp"`,
+		`const CREDITS: Record<string, number> = {"credits_10":10};`,
+		"functions.invoke",
+	} {
+		if !strings.Contains(contrib.SystemBlock, want) {
+			t.Errorf("expected %q in system block", want)
+		}
+	}
+	if strings.Contains(contrib.SystemBlock, "import RevenueCat") {
+		t.Error("StoreKit native prompt must not reference the RevenueCat SDK")
+	}
+	if !strings.Contains(contrib.UserBlock, "verify-transaction") {
+		t.Error("expected user block to require deploying the Edge Function")
+	}
+}
+
+func TestVerifyTransactionSource_BindsTransactionToCaller(t *testing.T) {
+	src := verifyTransactionSource("com.example.testapp", testPlan())
+	for _, want := range []string{
+		`if (!user) return json({ error: "sign in required" }, 401);`,
+		`String(tx.appAccountToken ?? "").toLowerCase() !== user.id.toLowerCase()`,
+		`.eq("original_transaction_id", String(tx.originalTransactionId))`,
+		`.neq("user_id", user.id)`,
+		"user_id: user.id,",
+	} {
+		if !strings.Contains(src, want) {
+			t.Errorf("expected %q in the Edge Function", want)
+		}
+	}
+	// The ownership checks must run before the row is written.
+	if strings.Index(src, "appAccountToken") > strings.Index(src, ".upsert(") || strings.Index(src, `.neq("user_id"`) > strings.Index(src, ".upsert(") {
+		t.Error("ownership checks must come before the upsert")
+	}
+
+	manager := storeManagerSource(true)
+	if !strings.Contains(manager, "product.purchase(options: purchaseOptions())") || !strings.Contains(manager, ".appAccountToken(userID)") {
+		t.Error("server-verified purchases must set appAccountToken to the user's ID")
+	}
+	if strings.Contains(storeManagerSource(false), "appAccountToken") {
+		t.Error("on-device purchases have no user to tie the transaction to")
+	}
+}
+
+func TestProvider_PromptContribution_OnDeviceOnly(t *testing.T) {
+	pc := New().(integrations.PromptCapable)
+	contrib, err := pc.PromptContribution(context.Background(), integrations.PromptRequest{
+		AppName:          "TestApp",
+		Store:            integrations.NewIntegrationStore(t.TempDir()),
+		MonetizationPlan: testPlan(),
+	})
+	if err != nil {
+		t.Fatalf("PromptContribution error: %v", err)
+	}
+	if strings.Contains(contrib.SystemBlock, "import Supabase") || strings.Contains(contrib.SystemBlock, "deploy_edge_function") {
+		t.Error("without Supabase MCP the prompt should not reference server verification")
+	}
+	if !strings.Contains(contrib.SystemBlock, "on-device verification") {
+		t.Error("expected on-device verification note")
+	}
+}
+
+func TestProvider_Provision(t *testing.T) {
+	p := New().(integrations.ProvisionCapable)
+
+	result, err := p.Provision(context.Background(), integrations.ProvisionRequest{})
+	if err != nil || len(result.Warnings) != 0 {
+		t.Fatalf("expected no-op without monetization, got %+v, %v", result, err)
+	}
+
+	result, err = p.Provision(context.Background(), integrations.ProvisionRequest{
+		NeedsMonetization: true,
+		MonetizationPlan:  testPlan(),
+		Providers:         []integrations.ProviderID{integrations.ProviderStoreKitNative},
+	})
+	if err != nil || len(result.Warnings) != 0 {
+		t.Fatalf("expected no secrets reminder without Supabase, got %+v, %v", result, err)
+	}
+
+	result, err = p.Provision(context.Background(), integrations.ProvisionRequest{
+		NeedsMonetization: true,
+		MonetizationPlan:  testPlan(),
+		Providers:         []integrations.ProviderID{integrations.ProviderSupabase, integrations.ProviderStoreKitNative},
+	})
+	if err != nil {
+		t.Fatalf("Provision error: %v", err)
+	}
+	if len(result.Warnings) != 1 || !strings.Contains(result.Warnings[0], "APPSTORE_PRIVATE_KEY") {
+		t.Errorf("expected secrets reminder, got %v", result.Warnings)
+	}
+}
@@ -0,0 +1,26 @@
+package storekit
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/moasq/nanowave/internal/integrations"
+)
+
+// Provision has no remote resources to create — products live in App Store Connect and the
+// Edge Function is deployed by the builder through the supabase MCP tools. It reports the
+// manual step left to the user: adding their In-App Purchase key as Edge Function secrets.
+// Without Supabase there is no Edge Function and nothing to report.
+func (s *storekitProvider) Provision(_ context.Context, req integrations.ProvisionRequest) (*integrations.ProvisionResult, error) {
+	if !req.NeedsMonetization || req.MonetizationPlan == nil || !slices.Contains(req.Providers, integrations.ProviderSupabase) {
+		return &integrations.ProvisionResult{}, nil
+	}
+	return &integrations.ProvisionResult{
+		Warnings: []string{fmt.Sprintf(
+			"StoreKit native: set %s with `supabase secrets set` so %s can validate purchases",
+			strings.Join(appStoreSecrets, ", "), verifyFunctionSlug,
+		)},
+	}, nil
+}
@@ -17,6 +17,10 @@ var supabaseMCPTools = []string{
 	"mcp__supabase__get_logs",
 	"mcp__supabase__configure_auth_providers",
 	"mcp__supabase__get_auth_config",
+	"mcp__supabase__deploy_edge_function",
+	"mcp__supabase__list_edge_functions",
+	"mcp__supabase__get_edge_function",
+	"mcp__supabase__delete_edge_function",
 }
 
 // supabaseAgentTools are the agentic build tools (same as MCP tools for Supabase).
@@ -30,6 +34,10 @@ var supabaseAgentTools = []string{
 	"mcp__supabase__get_logs",
 	"mcp__supabase__configure_auth_providers",
 	"mcp__supabase__get_auth_config",
+	"mcp__supabase__deploy_edge_function",
+	"mcp__supabase__list_edge_functions",
+	"mcp__supabase__get_edge_function",
+	"mcp__supabase__delete_edge_function",
 }
 
 // MCPServer returns the MCP server configuration for Supabase.
@@ -17,7 +17,9 @@ func (s *supabaseProvider) PromptContribution(_ context.Context, req integration
 	system.WriteString("\n<integration-config>\n")
 
 	// Credentials
-	if cfg != nil && cfg.ProjectURL != "" {
+	if cfg != nil && cfg.Local != nil {
+		writeLocalCredentials(&system, cfg)
+	} else if cfg != nil && cfg.ProjectURL != "" {
 		fmt.Fprintf(&system, "Supabase Project URL: %s\n", cfg.ProjectURL)
 		fmt.Fprintf(&system, "Supabase Anon Key: %s\n", cfg.AnonKey)
 		system.WriteString("Store these in Config/AppConfig.swift as static constants.\n\n")
@@ -27,8 +29,13 @@ func (s *supabaseProvider) PromptContribution(_ context.Context, req integration
 		system.WriteString("Store these in Config/AppConfig.swift as static constants. The user will replace the placeholders.\n\n")
 	}
 
-	// Backend setup instructions (only if MCP available via PAT)
-	hasMCP := cfg != nil && cfg.PAT != ""
+	// Backend setup instructions (only if MCP available via PAT). The MCP tools act on
+	// the hosted project, so apps on a local stack keep their schema where it was provisioned.
+	hasMCP := cfg != nil && cfg.PAT != "" && cfg.Local == nil
+	if cfg != nil && cfg.PAT != "" && cfg.Local != nil {
+		system.WriteString("The Supabase MCP tools act on the hosted project, NOT the local stack. ")
+		system.WriteString("Do NOT create tables, policies, or buckets with mcp__supabase__execute_sql — the schema lives on the local stack, provisioned by nanowave.\n\n")
+	}
 	if hasMCP {
 		// Auth provider status
 		if len(req.AuthMethods) > 0 {
@@ -86,6 +93,8 @@ func (s *supabaseProvider) PromptContribution(_ context.Context, req integration
 		system.WriteString("### Step 6: STOP and verify before writing Swift code\n")
 		system.WriteString("Call `mcp__supabase__list_tables` NOW. Only proceed to Swift code after confirming tables exist.\n\n")
 		system.WriteString("</backend-setup>\n\n")
+
+		writeEdgeFunctionGuidance(&system)
 	}
 
 	system.WriteString("Models use Codable (NOT @Model) — Supabase is the persistence layer.\n")
@@ -93,7 +102,14 @@ func (s *supabaseProvider) PromptContribution(_ context.Context, req integration
 
 	// User message block (backend-first instructions)
 	var userBlock string
-	if hasMCP {
+	if cfg != nil && cfg.Local != nil && req.BackendProvisioned {
+		userBlock = `
+SUPABASE BACKEND (local stack, already provisioned by nanowave):
+Tables, RLS policies, and storage buckets have been created on the local ` + "`supabase start`" + ` stack.
+Debug builds talk to the local stack; proceed directly to writing Swift code.
+
+`
+	} else if hasMCP {
 		if req.BackendProvisioned {
 			userBlock = `
 SUPABASE BACKEND (already provisioned by nanowave):
@@ -125,6 +141,52 @@ DO NOT skip this. The app CANNOT function without a backend schema.
 	}, nil
 }
 
+// writeEdgeFunctionGuidance tells the builder to put privileged logic in Edge Functions
+// instead of the client, and how to deploy them through the MCP tools.
+func writeEdgeFunctionGuidance(system *strings.Builder) {
+	system.WriteString(`## Server-side logic: Edge Functions
+
+Anything that needs a secret or must not be trusted to the client goes in an Edge Function, NOT in Swift:
+- Calls to third-party APIs with keys (AI providers, email, payments) — the app calls the function, the function calls the API
+- Webhook receivers (Stripe, App Store Server Notifications) — deploy with verify_jwt false and check the sender's signature
+- Sending push notifications, and any write that must bypass RLS (service role stays in the function)
+
+Workflow:
+1. mcp__supabase__list_edge_functions — reuse an existing function instead of creating a duplicate
+2. mcp__supabase__get_edge_function — read the deployed source before changing an existing function
+3. mcp__supabase__deploy_edge_function — name (lowercase-hyphenated), files [{"name": "index.ts", "content": ...}]; keep verify_jwt true for app calls
+4. Call it from Swift with SupabaseService.shared.client.functions.invoke("<name>", options: FunctionInvokeOptions(body: ...))
+
+NEVER put API keys or service_role keys in AppConfig.swift or any Swift file. If a function needs a secret the user has not provided,
+read it with Deno.env.get("NAME") and list the secret names the user must set in your final summary.
+
+`)
+}
+
+// writeLocalCredentials writes the credentials section for an app connected to a
+// local `supabase start` stack. Debug builds point at the local API; Release builds
+// use the hosted project when one is configured, otherwise placeholders.
+func writeLocalCredentials(system *strings.Builder, cfg *integrations.IntegrationConfig) {
+	releaseURL, releaseKey := cfg.ProjectURL, cfg.AnonKey
+	if releaseURL == "" {
+		releaseURL, releaseKey = "https://YOUR_PROJECT_REF.supabase.co", "YOUR_ANON_KEY"
+	}
+	fmt.Fprintf(system, "Supabase Local API URL (Debug): %s\n", cfg.Local.APIURL)
+	fmt.Fprintf(system, "Supabase Local Anon Key (Debug): %s\n", cfg.Local.AnonKey)
+	fmt.Fprintf(system, "Supabase Project URL (Release): %s\n", releaseURL)
+	fmt.Fprintf(system, "Supabase Anon Key (Release): %s\n", releaseKey)
+	system.WriteString("Store these in Config/AppConfig.swift as static constants, switching on the build configuration:\n")
+	system.WriteString("```swift\nenum AppConfig {\n#if DEBUG\n")
+	fmt.Fprintf(system, "    static let supabaseURL = URL(string: %q)!\n", cfg.Local.APIURL)
+	fmt.Fprintf(system, "    static let supabaseAnonKey = %q\n", cfg.Local.AnonKey)
+	system.WriteString("#else\n")
+	fmt.Fprintf(system, "    static let supabaseURL = URL(string: %q)!\n", releaseURL)
+	fmt.Fprintf(system, "    static let supabaseAnonKey = %q\n", releaseKey)
+	system.WriteString("#endif\n}\n```\n")
+	system.WriteString("The local stack is plain HTTP on the host machine — the Simulator reaches it directly. ")
+	system.WriteString("Keep the loopback IP address as-is (do NOT rewrite it to localhost); IP addresses are exempt from App Transport Security.\n\n")
+}
+
 // --- Helper functions (mirrored from orchestration/build_prompts.go) ---
 
 // modelRefCamelToSnake converts camelCase/PascalCase to snake_case.
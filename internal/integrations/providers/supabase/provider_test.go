@@ -3,8 +3,11 @@ package supabase
 import (
 	"context"
 	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"testing"
 
@@ -45,6 +48,11 @@ func TestProvider_MCPTools(t *testing.T) {
 			t.Errorf("tool %q doesn't have expected prefix", tool)
 		}
 	}
+	for _, want := range []string{"deploy_edge_function", "list_edge_functions", "get_edge_function", "delete_edge_function"} {
+		if !slices.Contains(tools, "mcp__supabase__"+want) {
+			t.Errorf("expected %s in MCP tools", want)
+		}
+	}
 }
 
 func TestProvider_AgentTools(t *testing.T) {
@@ -133,4 +141,134 @@ func TestProvider_PromptContribution(t *testing.T) {
 	if !strings.Contains(contrib.SystemBlock, "posts") {
 		t.Error("expected table name 'posts' in system block")
 	}
+	if !strings.Contains(contrib.SystemBlock, "mcp__supabase__deploy_edge_function") {
+		t.Error("expected Edge Function guidance when the MCP server is connected")
+	}
+}
+
+func TestProvider_ProvisionLocal(t *testing.T) {
+	var queries []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/pg/query" {
+			t.Errorf("unexpected path %q", r.URL.Path)
+		}
+		if r.Header.Get("apikey") != "local-service" {
+			t.Errorf("expected service role key in apikey header")
+		}
+		var body map[string]string
+		json.NewDecoder(r.Body).Decode(&body)
+		queries = append(queries, body["query"])
+		w.Write([]byte("[]"))
+	}))
+	defer srv.Close()
+
+	p := New().(integrations.ProvisionCapable)
+	result, err := p.Provision(context.Background(), integrations.ProvisionRequest{
+		AppName: "TestApp",
+		Models: []integrations.ModelRef{
+			{Name: "Post", Properties: []integrations.PropertyRef{{Name: "id", Type: "UUID"}}},
+		},
+		NeedsDB: true,
+		Local: &integrations.LocalStack{
+			APIURL:         srv.URL,
+			AnonKey:        "local-anon",
+			ServiceRoleKey: "local-service",
+		},
+	})
+	if err != nil {
+		t.Fatalf("Provision error: %v", err)
+	}
+	if !result.BackendProvisioned {
+		t.Errorf("expected backend provisioned, warnings: %v", result.Warnings)
+	}
+	if len(queries) == 0 || !strings.Contains(queries[0], "CREATE TABLE IF NOT EXISTS public.posts") {
+		t.Errorf("expected create table SQL against local stack, got %v", queries)
+	}
+}
+
+func TestProvider_PromptContributionLocal(t *testing.T) {
+	tmpDir := t.TempDir()
+	storeData := map[string]any{
+		"providers": map[string]any{
+			"supabase": map[string]any{
+				"TestApp": map[string]any{
+					"provider":    "supabase",
+					"project_url": "https://test.supabase.co",
+					"anon_key":    "hosted-key",
+					"local": map[string]any{
+						"api_url":  "http://127.0.0.1:54321",
+						"anon_key": "local-anon",
+					},
+				},
+			},
+		},
+	}
+	data, _ := json.MarshalIndent(storeData, "", "  ")
+	os.WriteFile(filepath.Join(tmpDir, "integrations.json"), data, 0o644)
+
+	store := integrations.NewIntegrationStore(tmpDir)
+	if err := store.Load(); err != nil {
+		t.Fatalf("store.Load: %v", err)
+	}
+
+	pc := New().(integrations.PromptCapable)
+	contrib, err := pc.PromptContribution(context.Background(), integrations.PromptRequest{
+		AppName: "TestApp",
+		Store:   store,
+	})
+	if err != nil {
+		t.Fatalf("PromptContribution error: %v", err)
+	}
+	for _, want := range []string{"#if DEBUG", "http://127.0.0.1:54321", "local-anon", "https://test.supabase.co", "hosted-key"} {
+		if !strings.Contains(contrib.SystemBlock, want) {
+			t.Errorf("expected %q in system block", want)
+		}
+	}
+}
+
+func TestProvider_PromptContributionLocalWithPAT(t *testing.T) {
+	tmpDir := t.TempDir()
+	storeData := map[string]any{
+		"providers": map[string]any{
+			"supabase": map[string]any{
+				"TestApp": map[string]any{
+					"provider":    "supabase",
+					"project_url": "https://test.supabase.co",
+					"anon_key":    "hosted-key",
+					"pat":         "test-pat",
+					"local": map[string]any{
+						"api_url":  "http://127.0.0.1:54321",
+						"anon_key": "local-anon",
+					},
+				},
+			},
+		},
+	}
+	data, _ := json.MarshalIndent(storeData, "", "  ")
+	os.WriteFile(filepath.Join(tmpDir, "integrations.json"), data, 0o644)
+
+	store := integrations.NewIntegrationStore(tmpDir)
+	if err := store.Load(); err != nil {
+		t.Fatalf("store.Load: %v", err)
+	}
+
+	pc := New().(integrations.PromptCapable)
+	contrib, err := pc.PromptContribution(context.Background(), integrations.PromptRequest{
+		AppName:            "TestApp",
+		Models:             []integrations.ModelRef{{Name: "Post", Properties: []integrations.PropertyRef{{Name: "id", Type: "UUID"}}}},
+		Store:              store,
+		BackendProvisioned: true,
+	})
+	if err != nil {
+		t.Fatalf("PromptContribution error: %v", err)
+	}
+	if strings.Contains(contrib.SystemBlock, "<backend-setup>") || strings.Contains(contrib.UserBlock, "mcp__supabase__") {
+		t.Error("a local stack must not get the hosted backend-first instructions")
+	}
+	if !strings.Contains(contrib.SystemBlock, "act on the hosted project, NOT the local stack") {
+		t.Error("expected a warning that the MCP tools target the hosted project")
+	}
+	if !strings.Contains(contrib.UserBlock, "local stack, already provisioned by nanowave") {
+		t.Errorf("expected the local stack user block, got %q", contrib.UserBlock)
+	}
 }
@@ -14,7 +14,22 @@ import (
 
 // Provision auto-provisions Supabase backend resources: auth, tables, RLS, storage, realtime.
 // Delegates to the same Supabase Management API calls that were in pipeline.go.
+// When a local stack is configured, SQL runs against it instead of the hosted project.
 func (s *supabaseProvider) Provision(_ context.Context, req integrations.ProvisionRequest) (*integrations.ProvisionResult, error) {
+	if req.Local != nil && req.Local.ServiceRoleKey != "" {
+		result := &integrations.ProvisionResult{}
+		// Local auth providers live in supabase/config.toml, not the Management API.
+		if req.NeedsAuth {
+			for _, m := range req.AuthMethods {
+				if m == "apple" {
+					result.NeedsAppleSignIn = true
+				}
+			}
+			result.Warnings = append(result.Warnings, "Local stack: enable auth providers in supabase/config.toml ([auth.external.*]) and restart with `supabase start`")
+		}
+		client := &localClient{apiURL: req.Local.APIURL, serviceRoleKey: req.Local.ServiceRoleKey}
+		return provisionSQL(client, req, result), nil
+	}
 	if req.PAT == "" {
 		return &integrations.ProvisionResult{}, nil
 	}
@@ -39,6 +54,12 @@ func (s *supabaseProvider) Provision(_ context.Context, req integrations.Provisi
 		}
 	}
 
+	return provisionSQL(client, req, result), nil
+}
+
+// provisionSQL creates tables, RLS policies, storage buckets, and realtime publications
+// through any SQL executor (hosted Management API or local stack).
+func provisionSQL(client sqlExecutor, req integrations.ProvisionRequest, result *integrations.ProvisionResult) *integrations.ProvisionResult {
 	// 2. Create tables from models
 	if req.NeedsDB && len(req.Models) > 0 {
 		sql := generateCreateTablesSQL(req.Models)
@@ -86,7 +107,12 @@ func (s *supabaseProvider) Provision(_ context.Context, req integrations.Provisi
 		}
 	}
 
-	return result, nil
+	return result
+}
+
+// sqlExecutor runs SQL against a Supabase database.
+type sqlExecutor interface {
+	executeSQL(query string) error
 }
 
 // --- Supabase Management API client (moved from pipeline.go) ---
@@ -146,6 +172,41 @@ func (c *apiClient) updateAuthConfig(config map[string]any) error {
 	return nil
 }
 
+// --- Local stack client ---
+
+// localClient runs SQL against a local `supabase start` stack through the
+// postgres-meta endpoint exposed by the local API gateway. It only needs HTTP,
+// so it works the same on macOS and Linux without psql installed.
+type localClient struct {
+	apiURL         string
+	serviceRoleKey string
+}
+
+func (c *localClient) executeSQL(query string) error {
+	data, err := json.Marshal(map[string]string{"query": query})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, strings.TrimRight(c.apiURL, "/")+"/pg/query", bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("apikey", c.serviceRoleKey)
+	req.Header.Set("Authorization", "Bearer "+c.serviceRoleKey)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("local SQL execution returned %d: %s", resp.StatusCode, string(body))
+	}
+	return nil
+}
+
 // --- Auth configuration ---
 
 func configureAuth(c *apiClient, bundleID string, authMethods []string) error {
@@ -7,8 +7,11 @@ import (
 	"github.com/moasq/nanowave/internal/integrations"
 )
 
-// Setup delegates to the existing integrations.SetupSupabase/SetupSupabaseManual functions.
+// Setup delegates to the existing integrations.SetupSupabase/SetupSupabaseManual/SetupSupabaseLocal functions.
 func (s *supabaseProvider) Setup(_ context.Context, req integrations.SetupRequest) error {
+	if req.Local {
+		return integrations.SetupSupabaseLocal(req.Store, req.AppName, req.WorkDir, req.PrintFn)
+	}
 	if req.Manual {
 		return integrations.SetupSupabaseManual(req.Store, req.AppName, req.ReadLineFn, req.PrintFn)
 	}
@@ -29,12 +32,16 @@ func (s *supabaseProvider) Status(_ context.Context, store *integrations.Integra
 	if cfg == nil {
 		return integrations.ProviderStatus{Configured: false}, nil
 	}
-	return integrations.ProviderStatus{
+	status := integrations.ProviderStatus{
 		Configured: true,
 		ProjectURL: cfg.ProjectURL,
 		HasAnonKey: cfg.AnonKey != "",
 		HasPAT:     cfg.PAT != "",
-	}, nil
+	}
+	if cfg.Local != nil {
+		status.LocalURL = cfg.Local.APIURL
+	}
+	return status, nil
 }
 
 // CLIAvailable checks if the Supabase CLI is installed.
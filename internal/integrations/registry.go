@@ -30,6 +30,24 @@ var integrationRegistry = map[ProviderID]*CuratedIntegration{
 		MCPCommand:  "nanowave",
 		MCPArgs:     []string{"mcp", "revenuecat"},
 	},
+	ProviderStoreKitNative: {
+		ID:          ProviderStoreKitNative,
+		Name:        "StoreKit Native",
+		Description: "StoreKit 2 purchases with Edge Function receipt validation (no SDK)",
+	},
+}
+
+// pluginIntegrations lists integrations registered at runtime by plugins, in registration order.
+var pluginIntegrations []*CuratedIntegration
+
+// RegisterIntegration adds a runtime-discovered integration (see package plugin)
+// so it shows up in status listings alongside the built-in providers.
+func RegisterIntegration(ci *CuratedIntegration) {
+	if _, exists := integrationRegistry[ci.ID]; exists {
+		return
+	}
+	integrationRegistry[ci.ID] = ci
+	pluginIntegrations = append(pluginIntegrations, ci)
 }
 
 // LookupIntegration returns the curated integration for a provider ID, or nil.
@@ -37,10 +55,13 @@ func LookupIntegration(id ProviderID) *CuratedIntegration {
 	return integrationRegistry[id]
 }
 
-// AllIntegrations returns all available integrations in a stable order.
+// AllIntegrations returns all available integrations in a stable order:
+// built-in providers first, then plugins.
 func AllIntegrations() []*CuratedIntegration {
-	return []*CuratedIntegration{
+	all := []*CuratedIntegration{
 		integrationRegistry[ProviderSupabase],
 		integrationRegistry[ProviderRevenueCat],
+		integrationRegistry[ProviderStoreKitNative],
 	}
+	return append(all, pluginIntegrations...)
 }
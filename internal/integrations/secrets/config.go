@@ -0,0 +1,122 @@
+package secrets
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Backend names a SecretStore implementation.
+type Backend string
+
+const (
+	// BackendAuto uses the OS keychain when available, falling back to the plain file store.
+	BackendAuto Backend = ""
+	// BackendKeychain always uses the OS keychain.
+	BackendKeychain Backend = "keychain"
+	// BackendFile stores secrets in a 0600 JSON file.
+	BackendFile Backend = "file"
+	// BackendEncryptedFile stores secrets AES-GCM encrypted with a passphrase-derived key.
+	BackendEncryptedFile Backend = "encrypted-file"
+	// BackendEnv reads secrets from NANOWAVE_SECRET_* environment variables (read-only).
+	BackendEnv Backend = "env"
+	// BackendOnePassword reads secrets with `op read` (read-only).
+	BackendOnePassword Backend = "1password"
+)
+
+// Backends lists every selectable backend, in display order.
+var Backends = []Backend{BackendKeychain, BackendFile, BackendEncryptedFile, BackendEnv, BackendOnePassword}
+
+// configFile holds the backend selection, next to the secrets themselves.
+const configFile = "secrets_config.json"
+
+// backendEnvVar overrides the configured backend — handy on CI where no config file exists.
+const backendEnvVar = "NANOWAVE_SECRETS_BACKEND"
+
+// Config selects and configures the secret backend.
+type Config struct {
+	Backend Backend `json:"backend,omitempty"`
+	// OnePasswordVault is the vault `op read` looks in (default "nanowave").
+	OnePasswordVault string `json:"op_vault,omitempty"`
+}
+
+// LoadConfig reads the backend config from dir. A missing file yields the zero Config.
+// NANOWAVE_SECRETS_BACKEND, when set, overrides the configured backend.
+func LoadConfig(dir string) (Config, error) {
+	var cfg Config
+	raw, err := os.ReadFile(filepath.Join(dir, configFile))
+	if err != nil && !os.IsNotExist(err) {
+		return cfg, err
+	}
+	if err == nil {
+		if err := json.Unmarshal(raw, &cfg); err != nil {
+			return cfg, fmt.Errorf("parse %s: %w", configFile, err)
+		}
+	}
+	if v := strings.TrimSpace(os.Getenv(backendEnvVar)); v != "" {
+		cfg.Backend = Backend(v)
+	}
+	return cfg, nil
+}
+
+// SaveConfig writes the backend config to dir.
+func SaveConfig(dir string, cfg Config) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	raw, err := json.MarshalIndent(cfg, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, configFile), raw, secretsFileMode)
+}
+
+// ParseBackend validates a backend name given on the command line or in config.
+func ParseBackend(name string) (Backend, error) {
+	b := Backend(strings.ToLower(strings.TrimSpace(name)))
+	if b == "auto" {
+		return BackendAuto, nil
+	}
+	for _, known := range Backends {
+		if b == known {
+			return b, nil
+		}
+	}
+	return "", fmt.Errorf("unknown secret backend %q (valid: auto, keychain, file, encrypted-file, env, 1password)", name)
+}
+
+// Open returns the SecretStore for a specific backend rooted at dir.
+func Open(dir string, backend Backend, cfg Config) (SecretStore, error) {
+	switch backend {
+	case BackendAuto:
+		return newAutoStore(dir), nil
+	case BackendKeychain:
+		return newKeychainStore(), nil
+	case BackendFile:
+		return newFileStore(dir), nil
+	case BackendEncryptedFile:
+		return newEncryptedFileStore(dir), nil
+	case BackendEnv:
+		return newEnvStore(), nil
+	case BackendOnePassword:
+		return newOnePasswordStore(cfg.OnePasswordVault), nil
+	}
+	return nil, fmt.Errorf("unknown secret backend %q", backend)
+}
+
+// Location describes where a backend keeps a secret key, for display.
+func Location(backend Backend, cfg Config, key string) string {
+	switch backend {
+	case BackendEnv:
+		return "$" + EnvVarName(key)
+	case BackendOnePassword:
+		return newOnePasswordStore(cfg.OnePasswordVault).Reference(key)
+	case BackendFile:
+		return secretsFile + " → " + key
+	case BackendEncryptedFile:
+		return encryptedSecretsFile + " → " + key
+	}
+	return key
+}
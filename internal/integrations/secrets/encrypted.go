@@ -0,0 +1,235 @@
+package secrets
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+const encryptedSecretsFile = "secrets.enc.json"
+
+// Environment variables that supply the encryption key. A raw key takes precedence.
+const (
+	passphraseEnvVar = "NANOWAVE_SECRETS_PASSPHRASE"
+	rawKeyEnvVar     = "NANOWAVE_SECRETS_KEY" // base64-encoded 32-byte AES key
+)
+
+// scrypt parameters for deriving the AES-256 key from a passphrase.
+// Variables so tests can use cheaper settings.
+var (
+	scryptN = 1 << 15
+	scryptR = 8
+	scryptP = 1
+)
+
+// encryptedEnvelope is the on-disk format: the JSON secrets map sealed with AES-256-GCM.
+type encryptedEnvelope struct {
+	Version    int    `json:"version"`
+	Salt       string `json:"salt,omitempty"` // scrypt salt; empty when a raw key is used
+	Nonce      string `json:"nonce"`
+	Ciphertext string `json:"ciphertext"`
+}
+
+// encryptedFileStore keeps secrets in an AES-GCM encrypted file, for hosts without a
+// keychain where the plain file store is not acceptable (shared CI runners, containers).
+// The key comes from NANOWAVE_SECRETS_KEY or is derived from NANOWAVE_SECRETS_PASSPHRASE.
+type encryptedFileStore struct {
+	mu   sync.Mutex
+	path string
+
+	// getenv is os.Getenv, replaceable in tests.
+	getenv func(string) string
+	// keyCache avoids re-running scrypt on every access; keyed by salt.
+	keyCache map[string][]byte
+}
+
+func newEncryptedFileStore(dir string) *encryptedFileStore {
+	return &encryptedFileStore{
+		path:     filepath.Join(dir, encryptedSecretsFile),
+		getenv:   os.Getenv,
+		keyCache: make(map[string][]byte),
+	}
+}
+
+func (e *encryptedFileStore) Get(key string) (string, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	data, _, err := e.load()
+	if err != nil {
+		return "", err
+	}
+	val, ok := data[key]
+	if !ok {
+		return "", ErrNotFound
+	}
+	return val, nil
+}
+
+func (e *encryptedFileStore) Set(key, value string) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	data, salt, err := e.load()
+	if err != nil {
+		return err
+	}
+	data[key] = value
+	return e.save(data, salt)
+}
+
+func (e *encryptedFileStore) Delete(key string) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	data, salt, err := e.load()
+	if err != nil {
+		return err
+	}
+	if _, ok := data[key]; !ok {
+		return nil
+	}
+	delete(data, key)
+	return e.save(data, salt)
+}
+
+// load decrypts the secrets file. Unlike the plain file store, a file that fails to
+// decrypt is an error — starting fresh would silently discard every stored secret.
+func (e *encryptedFileStore) load() (map[string]string, []byte, error) {
+	raw, err := os.ReadFile(e.path)
+	if os.IsNotExist(err) {
+		return make(map[string]string), nil, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var env encryptedEnvelope
+	if err := json.Unmarshal(raw, &env); err != nil {
+		return nil, nil, fmt.Errorf("parse %s: %w", encryptedSecretsFile, err)
+	}
+	salt, err := base64.StdEncoding.DecodeString(env.Salt)
+	if err != nil {
+		return nil, nil, fmt.Errorf("parse %s: invalid salt", encryptedSecretsFile)
+	}
+	nonce, err := base64.StdEncoding.DecodeString(env.Nonce)
+	if err != nil {
+		return nil, nil, fmt.Errorf("parse %s: invalid nonce", encryptedSecretsFile)
+	}
+	ciphertext, err := base64.StdEncoding.DecodeString(env.Ciphertext)
+	if err != nil {
+		return nil, nil, fmt.Errorf("parse %s: invalid ciphertext", encryptedSecretsFile)
+	}
+
+	gcm, err := e.cipher(salt)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(nonce) != gcm.NonceSize() {
+		return nil, nil, fmt.Errorf("parse %s: invalid nonce", encryptedSecretsFile)
+	}
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		source := passphraseEnvVar
+		if e.getenv(rawKeyEnvVar) != "" {
+			source = rawKeyEnvVar
+		}
+		return nil, nil, fmt.Errorf("%w: %s does not match %s, or the file is corrupted", ErrDecrypt, source, encryptedSecretsFile)
+	}
+
+	data := make(map[string]string)
+	if err := json.Unmarshal(plaintext, &data); err != nil {
+		return nil, nil, fmt.Errorf("parse decrypted secrets: %w", err)
+	}
+	return data, salt, nil
+}
+
+// save seals data with a fresh nonce. The salt is kept across writes so the
+// derived key stays cached; a new file gets a new random salt.
+func (e *encryptedFileStore) save(data map[string]string, salt []byte) error {
+	if salt == nil && e.getenv(rawKeyEnvVar) == "" {
+		salt = make([]byte, 16)
+		if _, err := rand.Read(salt); err != nil {
+			return err
+		}
+	}
+	gcm, err := e.cipher(salt)
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return err
+	}
+	plaintext, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	env := encryptedEnvelope{
+		Version:    1,
+		Salt:       base64.StdEncoding.EncodeToString(salt),
+		Nonce:      base64.StdEncoding.EncodeToString(nonce),
+		Ciphertext: base64.StdEncoding.EncodeToString(gcm.Seal(nil, nonce, plaintext, nil)),
+	}
+	raw, err := json.MarshalIndent(env, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(e.path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(e.path, raw, secretsFileMode)
+}
+
+// cipher returns the AES-256-GCM AEAD for the configured key.
+func (e *encryptedFileStore) cipher(salt []byte) (cipher.AEAD, error) {
+	key, err := e.key(salt)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// key returns the raw key from NANOWAVE_SECRETS_KEY, or derives one from the passphrase with scrypt.
+func (e *encryptedFileStore) key(salt []byte) ([]byte, error) {
+	if raw := e.getenv(rawKeyEnvVar); raw != "" {
+		key, err := base64.StdEncoding.DecodeString(raw)
+		if err != nil || len(key) != 32 {
+			return nil, fmt.Errorf("%s must be a base64-encoded 32-byte key", rawKeyEnvVar)
+		}
+		if len(salt) > 0 {
+			return nil, fmt.Errorf("%w: %s was written with a passphrase; unset %s and set %s", ErrDecrypt, encryptedSecretsFile, rawKeyEnvVar, passphraseEnvVar)
+		}
+		return key, nil
+	}
+	passphrase := e.getenv(passphraseEnvVar)
+	if passphrase == "" {
+		return nil, fmt.Errorf("encrypted secret store needs %s or %s", passphraseEnvVar, rawKeyEnvVar)
+	}
+	if len(salt) == 0 {
+		return nil, fmt.Errorf("%w: %s was written with a raw key; set %s", ErrDecrypt, encryptedSecretsFile, rawKeyEnvVar)
+	}
+	cacheKey := passphrase + "\x00" + string(salt)
+	if key, ok := e.keyCache[cacheKey]; ok {
+		return key, nil
+	}
+	key, err := scrypt.Key([]byte(passphrase), salt, scryptN, scryptR, scryptP, 32)
+	if err != nil {
+		return nil, err
+	}
+	e.keyCache[cacheKey] = key
+	return key, nil
+}
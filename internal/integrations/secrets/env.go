@@ -0,0 +1,57 @@
+package secrets
+
+import (
+	"os"
+	"strings"
+)
+
+// envPrefix starts every environment variable the env backend reads.
+const envPrefix = "NANOWAVE_SECRET_"
+
+// envStore resolves secrets from environment variables, for CI where credentials
+// arrive as injected env vars. It is read-only: Set and Delete return a *ReadOnlyError.
+type envStore struct {
+	// lookupEnv is os.LookupEnv, replaceable in tests.
+	lookupEnv func(string) (string, bool)
+}
+
+func newEnvStore() *envStore {
+	return &envStore{lookupEnv: os.LookupEnv}
+}
+
+func (e *envStore) Get(key string) (string, error) {
+	val, ok := e.lookupEnv(EnvVarName(key))
+	if !ok || val == "" {
+		return "", ErrNotFound
+	}
+	return val, nil
+}
+
+func (e *envStore) Set(key, value string) error { return e.readOnly(key) }
+
+func (e *envStore) Delete(key string) error { return e.readOnly(key) }
+
+func (e *envStore) readOnly(key string) error {
+	return &ReadOnlyError{Key: key, Location: "the environment variable " + EnvVarName(key)}
+}
+
+// EnvVarName returns the environment variable the env backend reads for a secret key.
+// "supabase/My App/pat" becomes NANOWAVE_SECRET_SUPABASE_MY_APP_PAT: each segment is
+// upper-cased and anything other than letters and digits becomes an underscore.
+func EnvVarName(key string) string {
+	var b strings.Builder
+	b.WriteString(envPrefix)
+	for i, part := range strings.Split(key, "/") {
+		if i > 0 {
+			b.WriteByte('_')
+		}
+		for _, r := range strings.ToUpper(part) {
+			if (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+				b.WriteRune(r)
+			} else {
+				b.WriteByte('_')
+			}
+		}
+	}
+	return b.String()
+}
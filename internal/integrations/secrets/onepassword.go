@@ -0,0 +1,80 @@
+package secrets
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// defaultOnePasswordVault is used when Config.OnePasswordVault is empty.
+const defaultOnePasswordVault = "nanowave"
+
+// onePasswordStore resolves secrets with the 1Password CLI (`op read`).
+// It is read-only: credentials are managed in 1Password itself.
+//
+// A key "provider/app/field" maps to the reference op://<vault>/<provider>-<app>/<field>,
+// so each app's provider credentials live in one 1Password item.
+type onePasswordStore struct {
+	vault string
+	// read runs `op read <ref>`, replaceable in tests.
+	read func(ref string) (string, error)
+}
+
+func newOnePasswordStore(vault string) *onePasswordStore {
+	if vault == "" {
+		vault = defaultOnePasswordVault
+	}
+	return &onePasswordStore{vault: vault, read: opRead}
+}
+
+func (o *onePasswordStore) Get(key string) (string, error) {
+	val, err := o.read(o.Reference(key))
+	if err != nil {
+		return "", err
+	}
+	val = strings.TrimRight(val, "\r\n")
+	if val == "" {
+		return "", ErrNotFound
+	}
+	return val, nil
+}
+
+func (o *onePasswordStore) Set(key, value string) error { return o.readOnly(key) }
+
+func (o *onePasswordStore) Delete(key string) error { return o.readOnly(key) }
+
+func (o *onePasswordStore) readOnly(key string) error {
+	return &ReadOnlyError{Key: key, Location: "the 1Password field " + o.Reference(key)}
+}
+
+// Reference returns the op:// secret reference for a secret key.
+func (o *onePasswordStore) Reference(key string) string {
+	parts := strings.SplitN(key, "/", 3)
+	if len(parts) != 3 {
+		return fmt.Sprintf("op://%s/%s/credential", o.vault, key)
+	}
+	return fmt.Sprintf("op://%s/%s-%s/%s", o.vault, parts[0], parts[1], parts[2])
+}
+
+// opRead runs `op read --no-newline <ref>`. Missing items and fields map to ErrNotFound.
+func opRead(ref string) (string, error) {
+	if _, err := exec.LookPath("op"); err != nil {
+		return "", fmt.Errorf("1Password CLI (op) not found: %w", err)
+	}
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command("op", "read", "--no-newline", ref)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if strings.Contains(msg, "isn't an item") || strings.Contains(msg, "isn't a field") {
+			return "", ErrNotFound
+		}
+		if msg == "" {
+			msg = err.Error()
+		}
+		return "", fmt.Errorf("op read %s: %s", ref, msg)
+	}
+	return stdout.String(), nil
+}
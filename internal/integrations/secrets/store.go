@@ -1,12 +1,18 @@
 // Package secrets provides secure storage for sensitive credentials like PATs.
 // It uses the OS keychain (macOS Keychain, Linux Secret Service) when available,
 // with a file-based fallback for environments without a keychain (CI, containers).
+// Other backends — an encrypted file, NANOWAVE_SECRET_* environment variables and
+// the 1Password CLI — can be selected in secrets_config.json (see Config).
 //
 // Pattern: zalando/go-keyring — the industry standard for Go keychain access.
 // Used by GitHub CLI, Docker credential helpers. Has MockInit() for testing.
 package secrets
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+	"os"
+)
 
 // serviceName is the keychain service identifier for all nanowave secrets.
 const serviceName = "nanowave"
@@ -30,9 +36,47 @@ func SecretKey(provider, appName, field string) string {
 	return provider + "/" + appName + "/" + field
 }
 
-// New returns the best available SecretStore for the current environment.
-// It tries the OS keychain first, falling back to a file-based store.
+// ErrReadOnly is returned by backends that can only resolve secrets (env, 1Password).
+// Their Set and Delete return a *ReadOnlyError, which matches it with errors.Is.
+var ErrReadOnly = fmt.Errorf("secret backend is read-only")
+
+// ReadOnlyError reports a write to a read-only backend and where the user has to
+// create the secret instead.
+type ReadOnlyError struct {
+	Key string
+	// Location is the environment variable or 1Password field the backend reads Key from.
+	Location string
+}
+
+func (e *ReadOnlyError) Error() string {
+	return fmt.Sprintf("secret backend is read-only: create %s holding %s, then try again", e.Location, e.Key)
+}
+
+// Is reports whether target is ErrReadOnly.
+func (e *ReadOnlyError) Is(target error) bool { return target == ErrReadOnly }
+
+// ErrDecrypt is returned when the encrypted-file backend cannot decrypt its file
+// with the configured passphrase or key.
+var ErrDecrypt = errors.New("cannot decrypt secrets")
+
+// New returns the SecretStore selected by the config in dir (see LoadConfig).
+// Without a configured backend it tries the OS keychain first, falling back to a file-based store.
 func New(dir string) SecretStore {
+	cfg, err := LoadConfig(dir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: %v — using default secret storage\n", err)
+		return newAutoStore(dir)
+	}
+	ss, err := Open(dir, cfg.Backend, cfg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: %v — using default secret storage\n", err)
+		return newAutoStore(dir)
+	}
+	return ss
+}
+
+// newAutoStore probes the OS keychain and falls back to the file store when it is unavailable.
+func newAutoStore(dir string) SecretStore {
 	ks := newKeychainStore()
 	// Probe: try a set+get+delete cycle to verify keychain availability.
 	probeKey := "__nanowave_probe__"
@@ -42,3 +86,27 @@ func New(dir string) SecretStore {
 	_ = ks.Delete(probeKey)
 	return ks
 }
+
+// Migrate copies the given keys from one store to another. Keys missing from the
+// source are skipped and returned so callers can report them. With deleteSource,
+// each copied secret is removed from the source afterwards (best-effort).
+func Migrate(from, to SecretStore, keys []string, deleteSource bool) (copied int, missing []string, err error) {
+	for _, key := range keys {
+		val, err := from.Get(key)
+		if errors.Is(err, ErrNotFound) {
+			missing = append(missing, key)
+			continue
+		}
+		if err != nil {
+			return copied, missing, fmt.Errorf("read %s: %w", key, err)
+		}
+		if err := to.Set(key, val); err != nil {
+			return copied, missing, fmt.Errorf("write %s: %w", key, err)
+		}
+		copied++
+		if deleteSource {
+			_ = from.Delete(key)
+		}
+	}
+	return copied, missing, nil
+}
@@ -1,8 +1,12 @@
 package secrets
 
 import (
+	"encoding/base64"
+	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/zalando/go-keyring"
@@ -149,3 +153,208 @@ func TestNew_FallsBackToFileStore(t *testing.T) {
 	}
 	_ = s.Delete(key)
 }
+
+func testEncryptedStore(t *testing.T, dir, passphrase string) *encryptedFileStore {
+	t.Helper()
+	scryptN = 1 << 10 // keep tests fast
+	s := newEncryptedFileStore(dir)
+	s.getenv = func(name string) string {
+		if name == passphraseEnvVar {
+			return passphrase
+		}
+		return ""
+	}
+	return s
+}
+
+func TestEncryptedFileStore_RoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	key := SecretKey("supabase", "TestApp", "pat")
+
+	s := testEncryptedStore(t, dir, "correct horse")
+	if _, err := s.Get(key); err != ErrNotFound {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+	if err := s.Set(key, "sbp_enc123"); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+
+	raw, err := os.ReadFile(filepath.Join(dir, encryptedSecretsFile))
+	if err != nil {
+		t.Fatalf("read encrypted file: %v", err)
+	}
+	if strings.Contains(string(raw), "sbp_enc123") || strings.Contains(string(raw), "supabase/TestApp") {
+		t.Error("encrypted file must not contain plaintext keys or values")
+	}
+
+	// A fresh instance with the same passphrase reads it back.
+	val, err := testEncryptedStore(t, dir, "correct horse").Get(key)
+	if err != nil || val != "sbp_enc123" {
+		t.Fatalf("got %q, %v; want sbp_enc123", val, err)
+	}
+
+	// A wrong passphrase is an error, not an empty store.
+	if _, err := testEncryptedStore(t, dir, "wrong").Get(key); !errors.Is(err, ErrDecrypt) {
+		t.Fatalf("expected decrypt error with wrong passphrase, got %v", err)
+	}
+
+	// So is a raw key for a file written with a passphrase.
+	rawKey := testEncryptedStore(t, dir, "")
+	rawKey.getenv = func(name string) string {
+		if name == rawKeyEnvVar {
+			return base64.StdEncoding.EncodeToString(make([]byte, 32))
+		}
+		return ""
+	}
+	if _, err := rawKey.Get(key); !errors.Is(err, ErrDecrypt) || !strings.Contains(err.Error(), "written with a passphrase") {
+		t.Fatalf("expected decrypt error for a raw key, got %v", err)
+	}
+
+	if err := s.Delete(key); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if _, err := s.Get(key); err != ErrNotFound {
+		t.Fatalf("expected ErrNotFound after delete, got %v", err)
+	}
+}
+
+func TestEncryptedFileStore_NoPassphrase(t *testing.T) {
+	s := testEncryptedStore(t, t.TempDir(), "")
+	if err := s.Set("a/b/c", "v"); err == nil {
+		t.Fatal("expected error without passphrase or key")
+	}
+}
+
+func TestEnvStore(t *testing.T) {
+	s := newEnvStore()
+	s.lookupEnv = func(name string) (string, bool) {
+		if name == "NANOWAVE_SECRET_SUPABASE_MY_APP_PAT" {
+			return "sbp_env", true
+		}
+		return "", false
+	}
+
+	val, err := s.Get(SecretKey("supabase", "My-App", "pat"))
+	if err != nil || val != "sbp_env" {
+		t.Fatalf("got %q, %v; want sbp_env", val, err)
+	}
+	if _, err := s.Get(SecretKey("supabase", "Other", "pat")); err != ErrNotFound {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+	err = s.Set("a/b/c", "v")
+	if !errors.Is(err, ErrReadOnly) || !strings.Contains(err.Error(), "NANOWAVE_SECRET_A_B_C") {
+		t.Fatalf("expected ErrReadOnly naming the variable, got %v", err)
+	}
+}
+
+func TestEnvVarName(t *testing.T) {
+	got := EnvVarName(SecretKey("supabase", "My App", "local_service_role_key"))
+	want := "NANOWAVE_SECRET_SUPABASE_MY_APP_LOCAL_SERVICE_ROLE_KEY"
+	if got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestOnePasswordStore(t *testing.T) {
+	s := newOnePasswordStore("")
+	var gotRef string
+	s.read = func(ref string) (string, error) {
+		gotRef = ref
+		return "sbp_op\n", nil
+	}
+
+	val, err := s.Get(SecretKey("supabase", "MyApp", "pat"))
+	if err != nil || val != "sbp_op" {
+		t.Fatalf("got %q, %v; want sbp_op", val, err)
+	}
+	if gotRef != "op://nanowave/supabase-MyApp/pat" {
+		t.Errorf("got reference %q", gotRef)
+	}
+	err = s.Set("a/b/c", "v")
+	var readOnly *ReadOnlyError
+	if !errors.As(err, &readOnly) || readOnly.Location != "the 1Password field op://nanowave/a-b/c" {
+		t.Fatalf("expected ReadOnlyError naming the 1Password field, got %v", err)
+	}
+	if err := s.Delete("a/b/c"); !errors.Is(err, ErrReadOnly) {
+		t.Fatalf("expected ErrReadOnly, got %v", err)
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	dir := t.TempDir()
+	cfg, err := LoadConfig(dir)
+	if err != nil || cfg.Backend != BackendAuto {
+		t.Fatalf("missing config: got %+v, %v", cfg, err)
+	}
+
+	if err := SaveConfig(dir, Config{Backend: BackendOnePassword, OnePasswordVault: "CI"}); err != nil {
+		t.Fatalf("SaveConfig failed: %v", err)
+	}
+	cfg, err = LoadConfig(dir)
+	if err != nil || cfg.Backend != BackendOnePassword || cfg.OnePasswordVault != "CI" {
+		t.Fatalf("got %+v, %v", cfg, err)
+	}
+
+	t.Setenv(backendEnvVar, "env")
+	cfg, _ = LoadConfig(dir)
+	if cfg.Backend != BackendEnv {
+		t.Errorf("env override: got %q, want env", cfg.Backend)
+	}
+	if _, ok := New(dir).(*envStore); !ok {
+		t.Error("New should honor the configured backend")
+	}
+}
+
+func TestParseBackend(t *testing.T) {
+	if b, err := ParseBackend("Encrypted-File"); err != nil || b != BackendEncryptedFile {
+		t.Errorf("got %q, %v", b, err)
+	}
+	if b, err := ParseBackend("auto"); err != nil || b != BackendAuto {
+		t.Errorf("got %q, %v", b, err)
+	}
+	if _, err := ParseBackend("vault"); err == nil {
+		t.Error("expected error for unknown backend")
+	}
+}
+
+func TestMigrate(t *testing.T) {
+	from := newFileStore(t.TempDir())
+	to := testEncryptedStore(t, t.TempDir(), "pass")
+	_ = from.Set("supabase/A/pat", "one")
+	_ = from.Set("supabase/B/pat", "two")
+
+	copied, missing, err := Migrate(from, to, []string{"supabase/A/pat", "supabase/B/pat", "supabase/C/pat"}, true)
+	if err != nil {
+		t.Fatalf("Migrate failed: %v", err)
+	}
+	if copied != 2 || len(missing) != 1 || missing[0] != "supabase/C/pat" {
+		t.Fatalf("got copied=%d missing=%v", copied, missing)
+	}
+	if val, _ := to.Get("supabase/B/pat"); val != "two" {
+		t.Errorf("destination: got %q, want two", val)
+	}
+	if _, err := from.Get("supabase/A/pat"); err != ErrNotFound {
+		t.Errorf("source should be cleared with deleteSource, got %v", err)
+	}
+
+	if _, _, err := Migrate(to, newEnvStore(), []string{"supabase/B/pat"}, false); !errors.Is(err, ErrReadOnly) {
+		t.Errorf("expected ErrReadOnly migrating into a read-only backend, got %v", err)
+	}
+
+	// Backends may wrap ErrNotFound.
+	wrapped := wrapNotFound{from}
+	if _, missing, err := Migrate(wrapped, to, []string{"supabase/C/pat"}, false); err != nil || len(missing) != 1 {
+		t.Errorf("wrapped not found: got missing=%v, %v", missing, err)
+	}
+}
+
+// wrapNotFound wraps the errors of a SecretStore's Get.
+type wrapNotFound struct{ SecretStore }
+
+func (w wrapNotFound) Get(key string) (string, error) {
+	val, err := w.SecretStore.Get(key)
+	if err != nil {
+		return "", fmt.Errorf("lookup %s: %w", key, err)
+	}
+	return val, nil
+}
@@ -2,9 +2,11 @@ package integrations
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -197,19 +199,36 @@ func (s *IntegrationStore) GetProvider(id ProviderID, appName string) (*Integrat
 	}
 	cp := *cfg
 
-	// Resolve secret reference
-	if strings.HasPrefix(cp.PAT, secretRefPrefix) {
-		key := strings.TrimPrefix(cp.PAT, secretRefPrefix)
-		val, err := s.secrets.Get(key)
-		if err == nil {
-			cp.PAT = val
-		} else {
-			// Secret not found — clear PAT so callers know it's missing
-			cp.PAT = ""
-		}
+	// Resolve secret references. A secret that cannot be read (as opposed to one
+	// that is missing) is reported alongside the config, with the field left empty.
+	var patErr, localErr error
+	cp.PAT, patErr = s.resolveSecretLocked(cp.PAT)
+	if cfg.Local != nil {
+		local := *cfg.Local
+		local.ServiceRoleKey, localErr = s.resolveSecretLocked(local.ServiceRoleKey)
+		cp.Local = &local
 	}
 
-	return &cp, nil
+	return &cp, errors.Join(patErr, localErr)
+}
+
+// resolveSecretLocked returns the secret behind a "secret:<key>" reference.
+// Raw values are returned unchanged; missing secrets resolve to "" so callers
+// know the credential is unavailable. Other failures, such as an encrypted file
+// that does not decrypt, are returned. Caller must already hold mu.
+func (s *IntegrationStore) resolveSecretLocked(value string) (string, error) {
+	if !strings.HasPrefix(value, secretRefPrefix) {
+		return value, nil
+	}
+	key := strings.TrimPrefix(value, secretRefPrefix)
+	val, err := s.secrets.Get(key)
+	if errors.Is(err, secrets.ErrNotFound) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("read %s: %w", key, err)
+	}
+	return val, nil
 }
 
 // SetProvider stores or updates a provider config for a specific app.
@@ -220,11 +239,22 @@ func (s *IntegrationStore) SetProvider(cfg IntegrationConfig, appName string) er
 
 	// Store PAT in secret store if it's a raw value (not already a reference)
 	if cfg.PAT != "" && !strings.HasPrefix(cfg.PAT, secretRefPrefix) {
-		key := secrets.SecretKey(string(cfg.Provider), appName, "pat")
-		if err := s.secrets.Set(key, cfg.PAT); err != nil {
-			return fmt.Errorf("failed to store credentials securely: %w", err)
+		ref, err := s.storeSecretLocked(secrets.SecretKey(string(cfg.Provider), appName, "pat"), cfg.PAT)
+		if err != nil {
+			return err
 		}
-		cfg.PAT = secretRefPrefix + key
+		cfg.PAT = ref
+	}
+	if cfg.Local != nil {
+		local := *cfg.Local
+		if local.ServiceRoleKey != "" && !strings.HasPrefix(local.ServiceRoleKey, secretRefPrefix) {
+			ref, err := s.storeSecretLocked(secrets.SecretKey(string(cfg.Provider), appName, "local_service_role_key"), local.ServiceRoleKey)
+			if err != nil {
+				return err
+			}
+			local.ServiceRoleKey = ref
+		}
+		cfg.Local = &local
 	}
 
 	if s.data.Providers[cfg.Provider] == nil {
@@ -234,6 +264,22 @@ func (s *IntegrationStore) SetProvider(cfg IntegrationConfig, appName string) er
 	return s.saveLocked()
 }
 
+// storeSecretLocked saves a credential in the secret store and returns the reference
+// that replaces it. Read-only backends (env, 1Password) cannot take the value; their
+// *secrets.ReadOnlyError names where to create it, and once created with the same
+// value it is referenced as is. Caller must already hold mu.
+func (s *IntegrationStore) storeSecretLocked(key, value string) (string, error) {
+	if err := s.secrets.Set(key, value); err != nil {
+		if !errors.Is(err, secrets.ErrReadOnly) {
+			return "", fmt.Errorf("failed to store credentials securely: %w", err)
+		}
+		if got, getErr := s.secrets.Get(key); getErr != nil || got != value {
+			return "", err
+		}
+	}
+	return secretRefPrefix + key, nil
+}
+
 // RemoveProvider deletes a provider config for a specific app.
 // Also removes the corresponding secret from the secret store.
 func (s *IntegrationStore) RemoveProvider(id ProviderID, appName string) error {
@@ -242,9 +288,13 @@ func (s *IntegrationStore) RemoveProvider(id ProviderID, appName string) error {
 
 	// Clean up secret if present
 	if apps, ok := s.data.Providers[id]; ok {
-		if cfg, ok := apps[appName]; ok && strings.HasPrefix(cfg.PAT, secretRefPrefix) {
-			key := strings.TrimPrefix(cfg.PAT, secretRefPrefix)
-			_ = s.secrets.Delete(key)
+		if cfg, ok := apps[appName]; ok {
+			if strings.HasPrefix(cfg.PAT, secretRefPrefix) {
+				_ = s.secrets.Delete(strings.TrimPrefix(cfg.PAT, secretRefPrefix))
+			}
+			if cfg.Local != nil && strings.HasPrefix(cfg.Local.ServiceRoleKey, secretRefPrefix) {
+				_ = s.secrets.Delete(strings.TrimPrefix(cfg.Local.ServiceRoleKey, secretRefPrefix))
+			}
 		}
 	}
 
@@ -260,6 +310,30 @@ func (s *IntegrationStore) RemoveProvider(id ProviderID, appName string) error {
 	return s.saveLocked()
 }
 
+// SecretKeys returns the secret store keys referenced by every stored config, sorted.
+// Used by `nanowave secrets` to list and migrate credentials between backends.
+func (s *IntegrationStore) SecretKeys() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var keys []string
+	for _, apps := range s.data.Providers {
+		for _, cfg := range apps {
+			refs := []string{cfg.PAT}
+			if cfg.Local != nil {
+				refs = append(refs, cfg.Local.ServiceRoleKey)
+			}
+			for _, ref := range refs {
+				if strings.HasPrefix(ref, secretRefPrefix) {
+					keys = append(keys, strings.TrimPrefix(ref, secretRefPrefix))
+				}
+			}
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
 // AllAppNames returns all configured app names for a given provider.
 func (s *IntegrationStore) AllAppNames(id ProviderID) []string {
 	s.mu.Lock()
@@ -300,9 +374,18 @@ func (s *IntegrationStore) AllStatuses() []IntegrationStatus {
 				ProjectURL:  cfg.ProjectURL,
 				HasAnonKey:  cfg.AnonKey != "",
 				HasPAT:      cfg.PAT != "",
+				LocalURL:    localAPIURL(cfg),
 				ValidatedAt: time.Now().Format(time.RFC3339),
 			})
 		}
 	}
 	return statuses
 }
+
+// localAPIURL returns the local stack API URL for a config, or "" if none.
+func localAPIURL(cfg *IntegrationConfig) string {
+	if cfg.Local == nil {
+		return ""
+	}
+	return cfg.Local.APIURL
+}
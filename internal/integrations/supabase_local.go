@@ -0,0 +1,126 @@
+package integrations
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// supabaseLocalStatus mirrors the fields of `supabase status --output json`.
+// Older CLI versions only support `--output env`, which uses the same keys.
+type supabaseLocalStatus struct {
+	APIURL         string `json:"API_URL"`
+	DBURL          string `json:"DB_URL"`
+	StudioURL      string `json:"STUDIO_URL"`
+	AnonKey        string `json:"ANON_KEY"`
+	ServiceRoleKey string `json:"SERVICE_ROLE_KEY"`
+}
+
+// SetupSupabaseLocal connects an app to a local Supabase stack started with `supabase start`.
+// It reads the API URL and anon/service_role keys from `supabase status` in workDir
+// (the directory containing supabase/config.toml; empty means the current directory).
+//
+// Any existing hosted config for the app is kept: Release builds keep using the hosted
+// project while Debug builds and provisioning use the local stack.
+func SetupSupabaseLocal(
+	store *IntegrationStore,
+	appName string,
+	workDir string,
+	printFn func(level, msg string),
+) error {
+	printFn("info", "Reading local Supabase stack from `supabase status`...")
+
+	local, err := readSupabaseLocalStatus(workDir)
+	if err != nil {
+		printFn("info", "Start the local stack first with: supabase start")
+		return err
+	}
+
+	if err := validateSupabaseConnection(local.APIURL, local.AnonKey); err != nil {
+		printFn("warning", fmt.Sprintf("Validation warning: %v (storing config anyway)", err))
+	}
+
+	cfg := IntegrationConfig{Provider: ProviderSupabase}
+	if existing, _ := store.GetProvider(ProviderSupabase, appName); existing != nil {
+		cfg = *existing
+	}
+	cfg.Local = local
+
+	if err := store.SetProvider(cfg, appName); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+
+	printFn("success", "Local Supabase stack connected")
+	printFn("detail", fmt.Sprintf("API URL: %s", local.APIURL))
+	if local.StudioURL != "" {
+		printFn("detail", fmt.Sprintf("Studio: %s", local.StudioURL))
+	}
+	if cfg.ProjectURL != "" {
+		printFn("detail", fmt.Sprintf("Release builds keep using %s", cfg.ProjectURL))
+	}
+	return nil
+}
+
+// readSupabaseLocalStatus runs `supabase status` and parses the local stack details.
+// JSON output is tried first; the env format is used as a fallback for older CLIs.
+func readSupabaseLocalStatus(workDir string) (*LocalStack, error) {
+	var lastErr error
+	for _, format := range []string{"json", "env"} {
+		cmd := exec.Command("supabase", "status", "--output", format)
+		cmd.Dir = workDir
+		out, err := cmd.Output()
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		local, err := parseSupabaseStatus(out)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		return local, nil
+	}
+	return nil, fmt.Errorf("local Supabase stack is not running: %w", lastErr)
+}
+
+// parseSupabaseStatus parses `supabase status` output in either JSON or env format.
+func parseSupabaseStatus(data []byte) (*LocalStack, error) {
+	var st supabaseLocalStatus
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) > 0 && trimmed[0] == '{' {
+		if err := json.Unmarshal(trimmed, &st); err != nil {
+			return nil, fmt.Errorf("parse supabase status: %w", err)
+		}
+	} else {
+		values := make(map[string]string)
+		scanner := bufio.NewScanner(bytes.NewReader(trimmed))
+		for scanner.Scan() {
+			key, value, ok := strings.Cut(strings.TrimSpace(scanner.Text()), "=")
+			if !ok {
+				continue
+			}
+			values[key] = strings.Trim(value, `"'`)
+		}
+		st = supabaseLocalStatus{
+			APIURL:         values["API_URL"],
+			DBURL:          values["DB_URL"],
+			StudioURL:      values["STUDIO_URL"],
+			AnonKey:        values["ANON_KEY"],
+			ServiceRoleKey: values["SERVICE_ROLE_KEY"],
+		}
+	}
+
+	if st.APIURL == "" || st.AnonKey == "" {
+		return nil, fmt.Errorf("supabase status output is missing API_URL or ANON_KEY")
+	}
+	return &LocalStack{
+		APIURL:         strings.TrimRight(st.APIURL, "/"),
+		DBURL:          st.DBURL,
+		StudioURL:      st.StudioURL,
+		AnonKey:        st.AnonKey,
+		ServiceRoleKey: st.ServiceRoleKey,
+	}, nil
+}
@@ -11,9 +11,19 @@ func XcodeGen() Server {
 			"mcp__xcodegen__add_extension",
 			"mcp__xcodegen__add_entitlement",
 			"mcp__xcodegen__add_localization",
+			"mcp__xcodegen__add_url_scheme",
 			"mcp__xcodegen__set_build_setting",
 			"mcp__xcodegen__get_project_config",
 			"mcp__xcodegen__add_package",
+			"mcp__xcodegen__remove_permission",
+			"mcp__xcodegen__remove_extension",
+			"mcp__xcodegen__remove_entitlement",
+			"mcp__xcodegen__remove_package",
+			"mcp__xcodegen__update_package_version",
+			"mcp__xcodegen__remove_localization",
+			"mcp__xcodegen__unset_build_setting",
+			"mcp__xcodegen__add_configuration",
+			"mcp__xcodegen__set_configuration_value",
 			"mcp__xcodegen__regenerate_project",
 		},
 	}
@@ -0,0 +1,71 @@
+package metadata
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Change is a field whose local value differs from App Store Connect.
+type Change struct {
+	Locale string // empty for app-level fields
+	// Field is a Field.File for localized fields, or the app-level file name.
+	Field string
+	// Key is the age rating attribute when Field is AgeRatingFile.
+	Key  string
+	From string
+	To   string
+}
+
+// Label is the human-readable name of the changed field.
+func (c Change) Label() string {
+	switch c.Field {
+	case PrimaryCategoryFile:
+		return "Primary Category"
+	case SecondaryCategoryFile:
+		return "Secondary Category"
+	case AgeRatingFile:
+		return "Age Rating: " + c.Key
+	}
+	if f, ok := LookupField(c.Field); ok {
+		return f.Label
+	}
+	return c.Field
+}
+
+// Diff returns the changes that pushing local would make to remote. Only
+// fields set locally are compared, so unmanaged fields never show up.
+func Diff(local, remote *Metadata) []Change {
+	var changes []Change
+	if local.PrimaryCategory != "" && local.PrimaryCategory != remote.PrimaryCategory {
+		changes = append(changes, Change{Field: PrimaryCategoryFile, From: remote.PrimaryCategory, To: local.PrimaryCategory})
+	}
+	if local.SecondaryCategory != "" && local.SecondaryCategory != remote.SecondaryCategory {
+		changes = append(changes, Change{Field: SecondaryCategoryFile, From: remote.SecondaryCategory, To: local.SecondaryCategory})
+	}
+
+	keys := make([]string, 0, len(local.AgeRating))
+	for key := range local.AgeRating {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		to := fmt.Sprint(local.AgeRating[key])
+		from := ""
+		if v, ok := remote.AgeRating[key]; ok && v != nil {
+			from = fmt.Sprint(v)
+		}
+		if to != from {
+			changes = append(changes, Change{Field: AgeRatingFile, Key: key, From: from, To: to})
+		}
+	}
+
+	for _, locale := range local.LocaleNames() {
+		loc, remoteLoc := local.Locales[locale], remote.Locales[locale]
+		for _, f := range Fields {
+			if to := loc[f.File]; to != "" && to != remoteLoc[f.File] {
+				changes = append(changes, Change{Locale: locale, Field: f.File, From: remoteLoc[f.File], To: to})
+			}
+		}
+	}
+	return changes
+}
@@ -0,0 +1,248 @@
+package metadata
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// Dir is the project's store metadata directory, relative to the project
+// directory. It follows the fastlane deliver layout: one subdirectory per
+// locale holding a text file per field, plus app-level files at the top.
+const Dir = "metadata"
+
+// App-level files in Dir.
+const (
+	PrimaryCategoryFile   = "primary_category.txt"
+	SecondaryCategoryFile = "secondary_category.txt"
+	AgeRatingFile         = "age_rating.json"
+)
+
+// Field is a localized store field stored as <locale>/<File>.txt.
+type Field struct {
+	File  string
+	Label string
+	// Limit is the maximum length in characters; 0 means the field is a URL.
+	Limit int
+	// AppInfo fields belong to the app rather than to a version.
+	AppInfo bool
+}
+
+// Fields are the localized store fields, in the order they are shown.
+var Fields = []Field{
+	{File: "name", Label: "Name", Limit: 30, AppInfo: true},
+	{File: "subtitle", Label: "Subtitle", Limit: 30, AppInfo: true},
+	{File: "description", Label: "Description", Limit: 4000},
+	{File: "keywords", Label: "Keywords", Limit: 100},
+	{File: "promotional_text", Label: "Promotional Text", Limit: 170},
+	{File: "release_notes", Label: "What's New", Limit: 4000},
+	{File: "support_url", Label: "Support URL"},
+	{File: "marketing_url", Label: "Marketing URL"},
+	{File: "privacy_url", Label: "Privacy Policy URL", AppInfo: true},
+}
+
+// Categories are the App Store category IDs.
+var Categories = []string{
+	"BOOKS", "BUSINESS", "DEVELOPER_TOOLS", "EDUCATION", "ENTERTAINMENT",
+	"FINANCE", "FOOD_AND_DRINK", "GAMES", "GRAPHICS_AND_DESIGN",
+	"HEALTH_AND_FITNESS", "LIFESTYLE", "MAGAZINES_AND_NEWSPAPERS", "MEDICAL",
+	"MUSIC", "NAVIGATION", "NEWS", "PHOTO_AND_VIDEO", "PRODUCTIVITY",
+	"REFERENCE", "SHOPPING", "SOCIAL_NETWORKING", "SPORTS", "STICKERS",
+	"TRAVEL", "UTILITIES", "WEATHER",
+}
+
+// Locale holds one locale's field values keyed by Field.File. Empty or
+// absent fields are not managed and are left as they are in App Store Connect.
+type Locale map[string]string
+
+// Metadata is the store metadata of an app.
+type Metadata struct {
+	PrimaryCategory   string
+	SecondaryCategory string
+	// AgeRating holds age rating questionnaire answers keyed by API attribute.
+	AgeRating map[string]any
+	Locales   map[string]Locale
+}
+
+// DefaultAgeRating answers every age rating question with "none" or "no",
+// which rates an app 4+.
+func DefaultAgeRating() map[string]any {
+	rating := map[string]any{
+		"gambling":              false,
+		"unrestrictedWebAccess": false,
+	}
+	for _, key := range []string{
+		"alcoholTobaccoOrDrugUseOrReferences", "contests", "gamblingSimulated",
+		"horrorOrFearThemes", "matureOrSuggestiveThemes", "medicalOrTreatmentInformation",
+		"profanityOrCrudeHumor", "sexualContentGraphicAndNudity", "sexualContentOrNudity",
+		"violenceCartoonOrFantasy", "violenceRealistic", "violenceRealisticProlongedGraphicOrSadistic",
+	} {
+		rating[key] = "NONE"
+	}
+	return rating
+}
+
+var localePattern = regexp.MustCompile(`^[a-z]{2,3}(-[A-Za-z]{2,4})*$`)
+
+// Load reads the project's metadata directory. It returns nil without an
+// error when the project has none.
+func Load(projectDir string) (*Metadata, error) {
+	root := filepath.Join(projectDir, Dir)
+	entries, err := os.ReadDir(root)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	m := &Metadata{Locales: map[string]Locale{}}
+	m.PrimaryCategory = readField(filepath.Join(root, PrimaryCategoryFile))
+	m.SecondaryCategory = readField(filepath.Join(root, SecondaryCategoryFile))
+	if data, err := os.ReadFile(filepath.Join(root, AgeRatingFile)); err == nil {
+		if err := json.Unmarshal(data, &m.AgeRating); err != nil {
+			return nil, fmt.Errorf("parse %s: %w", filepath.Join(Dir, AgeRatingFile), err)
+		}
+	}
+
+	for _, e := range entries {
+		if !e.IsDir() || !localePattern.MatchString(e.Name()) {
+			continue
+		}
+		loc := Locale{}
+		for _, f := range Fields {
+			if v := readField(filepath.Join(root, e.Name(), f.File+".txt")); v != "" {
+				loc[f.File] = v
+			}
+		}
+		m.Locales[e.Name()] = loc
+	}
+	return m, nil
+}
+
+func readField(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// Save writes the metadata into the project's metadata directory. Fields are
+// written one file each so they diff cleanly under version control.
+func Save(projectDir string, m *Metadata) error {
+	root := filepath.Join(projectDir, Dir)
+	if err := os.MkdirAll(root, 0o755); err != nil {
+		return err
+	}
+	files := map[string]string{
+		PrimaryCategoryFile:   m.PrimaryCategory,
+		SecondaryCategoryFile: m.SecondaryCategory,
+	}
+	for locale, loc := range m.Locales {
+		if err := os.MkdirAll(filepath.Join(root, locale), 0o755); err != nil {
+			return err
+		}
+		for field, value := range loc {
+			files[filepath.Join(locale, field+".txt")] = value
+		}
+	}
+	for name, value := range files {
+		if value == "" {
+			continue
+		}
+		if err := os.WriteFile(filepath.Join(root, name), []byte(value+"\n"), 0o644); err != nil {
+			return err
+		}
+	}
+	if m.AgeRating != nil {
+		data, err := json.MarshalIndent(m.AgeRating, "", "  ")
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(filepath.Join(root, AgeRatingFile), append(data, '\n'), 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Problem is a metadata value App Store Connect would reject.
+type Problem struct {
+	Locale  string // empty for app-level fields
+	Field   string
+	Message string
+}
+
+func (p Problem) String() string {
+	if p.Locale == "" {
+		return fmt.Sprintf("%s: %s", p.Field, p.Message)
+	}
+	return fmt.Sprintf("%s %s: %s", p.Locale, p.Field, p.Message)
+}
+
+// Validate checks the metadata against App Store Connect's limits: field
+// lengths, URL formats and category IDs.
+func (m *Metadata) Validate() []Problem {
+	var problems []Problem
+	known := map[string]bool{}
+	for _, c := range Categories {
+		known[c] = true
+	}
+	if m.PrimaryCategory != "" && !known[m.PrimaryCategory] {
+		problems = append(problems, Problem{Field: "Primary Category", Message: fmt.Sprintf("unknown category %q", m.PrimaryCategory)})
+	}
+	if m.SecondaryCategory != "" {
+		if !known[m.SecondaryCategory] {
+			problems = append(problems, Problem{Field: "Secondary Category", Message: fmt.Sprintf("unknown category %q", m.SecondaryCategory)})
+		} else if m.SecondaryCategory == m.PrimaryCategory {
+			problems = append(problems, Problem{Field: "Secondary Category", Message: "must differ from the primary category"})
+		}
+	}
+
+	for _, locale := range m.LocaleNames() {
+		loc := m.Locales[locale]
+		for _, f := range Fields {
+			value := loc[f.File]
+			if value == "" {
+				continue
+			}
+			if f.Limit == 0 {
+				if u, err := url.Parse(value); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+					problems = append(problems, Problem{Locale: locale, Field: f.Label, Message: fmt.Sprintf("%q is not an http(s) URL", value)})
+				}
+				continue
+			}
+			if n := utf8.RuneCountInString(value); n > f.Limit {
+				problems = append(problems, Problem{Locale: locale, Field: f.Label, Message: fmt.Sprintf("%d characters, limit is %d", n, f.Limit)})
+			}
+		}
+	}
+	return problems
+}
+
+// LocaleNames returns the metadata's locales in sorted order.
+func (m *Metadata) LocaleNames() []string {
+	names := make([]string, 0, len(m.Locales))
+	for name := range m.Locales {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// LookupField returns the localized field stored in file.
+func LookupField(file string) (Field, bool) {
+	for _, f := range Fields {
+		if f.File == file {
+			return f, true
+		}
+	}
+	return Field{}, false
+}
@@ -0,0 +1,69 @@
+package metadata
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSaveLoadRoundTrip(t *testing.T) {
+	projectDir := t.TempDir()
+	m := &Metadata{
+		PrimaryCategory: "HEALTH_AND_FITNESS",
+		AgeRating:       DefaultAgeRating(),
+		Locales: map[string]Locale{
+			"en-US": {"name": "Habits", "description": "Track every habit.\n\nBuild streaks.", "support_url": "https://example.com/support"},
+			"de-DE": {"name": "Gewohnheiten"},
+		},
+	}
+	if err := Save(projectDir, m); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(projectDir, Dir, "en-US", "description.txt")); err != nil {
+		t.Errorf("description not written per field: %v", err)
+	}
+	// Stray directories are not locales.
+	os.MkdirAll(filepath.Join(projectDir, Dir, "screenshots"), 0o755)
+
+	loaded, err := Load(projectDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(loaded.Locales, m.Locales) || loaded.PrimaryCategory != m.PrimaryCategory || len(loaded.AgeRating) != len(m.AgeRating) {
+		t.Errorf("Load() = %+v, want %+v", loaded, m)
+	}
+
+	if m, err := Load(t.TempDir()); m != nil || err != nil {
+		t.Errorf("Load() without a metadata directory = %v, %v", m, err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	m := &Metadata{
+		PrimaryCategory:   "PRODUCTIVITY",
+		SecondaryCategory: "PRODUCTIVITY",
+		Locales: map[string]Locale{
+			"en-US": {
+				"name":        "A name well beyond the thirty character limit",
+				"keywords":    strings.Repeat("habit,", 17),
+				"subtitle":    "Ünïcödé counts as characters!", // 29 characters, more bytes
+				"support_url": "example.com/support",
+			},
+		},
+	}
+	var got []string
+	for _, p := range m.Validate() {
+		got = append(got, p.String())
+	}
+	want := []string{
+		"Secondary Category: must differ from the primary category",
+		"en-US Name: 45 characters, limit is 30",
+		"en-US Keywords: 102 characters, limit is 100",
+		`en-US Support URL: "example.com/support" is not an http(s) URL`,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Validate() = %q, want %q", got, want)
+	}
+}
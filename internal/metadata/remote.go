@@ -0,0 +1,202 @@
+package metadata
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/moasq/nanowave/internal/asc"
+)
+
+// Remote is an app's metadata in App Store Connect, with the IDs of the
+// resources a push updates.
+type Remote struct {
+	Metadata
+	AppID         string
+	VersionID     string
+	VersionString string
+	AppInfoID     string
+
+	ageRatingID   string
+	versionLocIDs map[string]string
+	infoLocIDs    map[string]string
+}
+
+// Fetch reads the metadata of the app's editable App Store version and app info.
+func Fetch(ctx context.Context, client *asc.Client, appID string) (*Remote, error) {
+	versions, err := client.ListAppStoreVersions(ctx, appID, 10)
+	if err != nil {
+		return nil, fmt.Errorf("list versions: %w", err)
+	}
+	version, ok := asc.EditableVersion(versions)
+	if !ok {
+		return nil, fmt.Errorf("app %s has no editable App Store version; create one in App Store Connect first", appID)
+	}
+	infos, err := client.ListAppInfos(ctx, appID)
+	if err != nil {
+		return nil, fmt.Errorf("list app infos: %w", err)
+	}
+	info, ok := asc.EditableAppInfo(infos)
+	if !ok {
+		return nil, fmt.Errorf("app %s has no app info", appID)
+	}
+
+	r := &Remote{
+		Metadata:      Metadata{Locales: map[string]Locale{}},
+		AppID:         appID,
+		VersionID:     version.ID,
+		VersionString: version.VersionString,
+		AppInfoID:     info.ID,
+		versionLocIDs: map[string]string{},
+		infoLocIDs:    map[string]string{},
+	}
+
+	versionLocs, err := client.ListVersionLocalizations(ctx, version.ID)
+	if err != nil {
+		return nil, fmt.Errorf("list version localizations: %w", err)
+	}
+	for _, l := range versionLocs {
+		r.versionLocIDs[l.Locale] = l.ID
+		r.locale(l.Locale).setVersionFields(l.LocalizationFields)
+	}
+	infoLocs, err := client.ListAppInfoLocalizations(ctx, info.ID)
+	if err != nil {
+		return nil, fmt.Errorf("list app info localizations: %w", err)
+	}
+	for _, l := range infoLocs {
+		r.infoLocIDs[l.Locale] = l.ID
+		r.locale(l.Locale).setInfoFields(l.AppInfoFields)
+	}
+
+	if r.PrimaryCategory, r.SecondaryCategory, err = client.AppInfoCategories(ctx, info.ID); err != nil {
+		return nil, fmt.Errorf("read categories: %w", err)
+	}
+	rating, err := client.GetAgeRatingDeclaration(ctx, info.ID)
+	if err != nil {
+		return nil, fmt.Errorf("read age rating: %w", err)
+	}
+	r.ageRatingID, r.AgeRating = rating.ID, rating.Attributes
+	return r, nil
+}
+
+func (r *Remote) locale(name string) Locale {
+	loc, ok := r.Locales[name]
+	if !ok {
+		loc = Locale{}
+		r.Locales[name] = loc
+	}
+	return loc
+}
+
+// Push applies changes, as returned by Diff(local, &r.Metadata), to App Store
+// Connect. Locales missing remotely are created.
+func (r *Remote) Push(ctx context.Context, client *asc.Client, local *Metadata, changes []Change) error {
+	versionLocales, infoLocales := map[string]bool{}, map[string]bool{}
+	categories := false
+	ageRating := map[string]any{}
+	for _, c := range changes {
+		switch c.Field {
+		case PrimaryCategoryFile, SecondaryCategoryFile:
+			categories = true
+		case AgeRatingFile:
+			ageRating[c.Key] = local.AgeRating[c.Key]
+		default:
+			if f, ok := LookupField(c.Field); ok && f.AppInfo {
+				infoLocales[c.Locale] = true
+			} else {
+				versionLocales[c.Locale] = true
+			}
+		}
+	}
+
+	for _, locale := range local.LocaleNames() {
+		loc := local.Locales[locale]
+		if versionLocales[locale] {
+			fields := loc.versionFields()
+			var err error
+			if id, ok := r.versionLocIDs[locale]; ok {
+				_, err = client.UpdateVersionLocalization(ctx, id, fields)
+			} else {
+				var created asc.VersionLocalization
+				created, err = client.CreateVersionLocalization(ctx, r.VersionID, locale, fields)
+				r.versionLocIDs[locale] = created.ID
+			}
+			if err != nil {
+				return fmt.Errorf("%s version metadata: %w", locale, err)
+			}
+		}
+		if infoLocales[locale] {
+			fields := loc.infoFields()
+			var err error
+			if id, ok := r.infoLocIDs[locale]; ok {
+				_, err = client.UpdateAppInfoLocalization(ctx, id, fields)
+			} else {
+				var created asc.AppInfoLocalization
+				created, err = client.CreateAppInfoLocalization(ctx, r.AppInfoID, locale, fields)
+				r.infoLocIDs[locale] = created.ID
+			}
+			if err != nil {
+				return fmt.Errorf("%s app info: %w", locale, err)
+			}
+		}
+	}
+
+	if categories {
+		primary, secondary := local.PrimaryCategory, local.SecondaryCategory
+		if primary == "" {
+			primary = r.PrimaryCategory
+		}
+		if secondary == "" {
+			secondary = r.SecondaryCategory
+		}
+		if err := client.SetAppInfoCategories(ctx, r.AppInfoID, primary, secondary); err != nil {
+			return fmt.Errorf("categories: %w", err)
+		}
+	}
+	if len(ageRating) > 0 {
+		if r.ageRatingID == "" {
+			return fmt.Errorf("age rating: app info %s has no age rating declaration", r.AppInfoID)
+		}
+		if err := client.UpdateAgeRatingDeclaration(ctx, r.ageRatingID, ageRating); err != nil {
+			return fmt.Errorf("age rating: %w", err)
+		}
+	}
+	return nil
+}
+
+func (l Locale) versionFields() asc.LocalizationFields {
+	return asc.LocalizationFields{
+		Description:     l["description"],
+		Keywords:        l["keywords"],
+		WhatsNew:        l["release_notes"],
+		PromotionalText: l["promotional_text"],
+		MarketingURL:    l["marketing_url"],
+		SupportURL:      l["support_url"],
+	}
+}
+
+func (l Locale) setVersionFields(f asc.LocalizationFields) {
+	l.set("description", f.Description)
+	l.set("keywords", f.Keywords)
+	l.set("release_notes", f.WhatsNew)
+	l.set("promotional_text", f.PromotionalText)
+	l.set("marketing_url", f.MarketingURL)
+	l.set("support_url", f.SupportURL)
+}
+
+func (l Locale) infoFields() asc.AppInfoFields {
+	return asc.AppInfoFields{Name: l["name"], Subtitle: l["subtitle"], PrivacyPolicyURL: l["privacy_url"]}
+}
+
+func (l Locale) setInfoFields(f asc.AppInfoFields) {
+	l.set("name", f.Name)
+	l.set("subtitle", f.Subtitle)
+	l.set("privacy_url", f.PrivacyPolicyURL)
+}
+
+// set stores a remote value trimmed the way local files are read.
+func (l Locale) set(field, value string) {
+	if value = strings.TrimSpace(value); value != "" {
+		l[field] = value
+	}
+}
@@ -0,0 +1,73 @@
+package metadata
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/moasq/nanowave/internal/asc"
+	"github.com/moasq/nanowave/internal/asc/asctest"
+)
+
+func TestFetchDiffPush(t *testing.T) {
+	srv := asctest.NewServer(t)
+	app := asctest.Ref{Type: "apps", ID: "app1"}
+	srv.Add(asctest.Resource{Type: "appStoreVersions", ID: "v1", Attributes: map[string]any{"versionString": "1.0", "appStoreState": asc.VersionPrepareForSubmission},
+		Relationships: map[string]asctest.Ref{"app": app}})
+	srv.Add(asctest.Resource{Type: "ageRatingDeclarations", ID: "rating1", Attributes: map[string]any{"gambling": false}})
+	srv.Add(asctest.Resource{Type: "appInfos", ID: "info1", Attributes: map[string]any{"state": "PREPARE_FOR_SUBMISSION"},
+		Relationships: map[string]asctest.Ref{"app": app, "ageRatingDeclaration": {Type: "ageRatingDeclarations", ID: "rating1"}}})
+	srv.Add(asctest.Resource{Type: "appStoreVersionLocalizations", ID: "vl1", Attributes: map[string]any{"locale": "en-US", "description": "Old description\n", "keywords": "habits"},
+		Relationships: map[string]asctest.Ref{"appStoreVersion": {Type: "appStoreVersions", ID: "v1"}}})
+	srv.Add(asctest.Resource{Type: "appInfoLocalizations", ID: "il1", Attributes: map[string]any{"locale": "en-US", "name": "Habits"},
+		Relationships: map[string]asctest.Ref{"appInfo": {Type: "appInfos", ID: "info1"}}})
+
+	client := srv.APIClient(t)
+	ctx := context.Background()
+
+	remote, err := Fetch(ctx, client, "app1")
+	if err != nil {
+		t.Fatalf("Fetch() error: %v", err)
+	}
+	if remote.VersionID != "v1" || remote.AppInfoID != "info1" || remote.Locales["en-US"]["description"] != "Old description" {
+		t.Fatalf("Fetch() = %+v", remote)
+	}
+
+	local := &Metadata{
+		PrimaryCategory: "HEALTH_AND_FITNESS",
+		AgeRating:       map[string]any{"gambling": false, "violenceRealistic": "NONE"},
+		Locales: map[string]Locale{
+			"en-US": {"name": "Habits", "description": "New description", "keywords": "habits"},
+			"de-DE": {"name": "Gewohnheiten", "description": "Neue Beschreibung"},
+		},
+	}
+	changes := Diff(local, &remote.Metadata)
+	var labels []string
+	for _, c := range changes {
+		labels = append(labels, c.Locale+" "+c.Label())
+	}
+	want := []string{
+		" Primary Category",
+		" Age Rating: violenceRealistic",
+		"de-DE Name",
+		"de-DE Description",
+		"en-US Description",
+	}
+	if !reflect.DeepEqual(labels, want) {
+		t.Fatalf("Diff() = %q, want %q", labels, want)
+	}
+
+	if err := remote.Push(ctx, client, local, changes); err != nil {
+		t.Fatalf("Push() error: %v", err)
+	}
+	after, err := Fetch(ctx, client, "app1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if left := Diff(local, &after.Metadata); len(left) != 0 {
+		t.Errorf("Diff() after Push() = %+v, want none", left)
+	}
+	if rating, _ := srv.Find("ageRatingDeclarations", "rating1"); rating.Attributes["violenceRealistic"] != "NONE" {
+		t.Errorf("age rating = %+v", rating.Attributes)
+	}
+}
@@ -0,0 +1,828 @@
+package orchestration
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/moasq/nanowave/internal/mcpregistry"
+	"github.com/moasq/nanowave/internal/pbxproj"
+	"github.com/moasq/nanowave/internal/xcodegen"
+)
+
+// AdoptResult describes a project brought under nanowave management by AdoptProject.
+type AdoptResult struct {
+	AppName           string
+	BundleID          string
+	Platform          string
+	Platforms         []string
+	WatchProjectShape string
+	DeviceFamily      string
+	// Source is the file the configuration was inferred from (project.yml or X.xcodeproj).
+	Source string
+	// GeneratedSpec is true when project.yml was written from an .xcodeproj.
+	GeneratedSpec bool
+	Extensions    []string
+	Packages      []string
+	Permissions   []string
+	Entitlements  []string
+}
+
+// AdoptProject brings an existing Xcode project that nanowave did not create
+// under management. It reads project.yml (or, failing that, the single
+// .xcodeproj, from which it writes a project.yml), infers project_config.json
+// and writes the Claude workspace scaffold. The project.yml stays the source of
+// truth: the xcodegen MCP tools edit it in place rather than regenerating it.
+func AdoptProject(projectDir string) (*AdoptResult, error) {
+	if _, err := os.Stat(filepath.Join(projectDir, "project_config.json")); err == nil {
+		return nil, fmt.Errorf("%s is already managed by nanowave (project_config.json exists)", projectDir)
+	}
+
+	result := &AdoptResult{Source: "project.yml"}
+	specPath := filepath.Join(projectDir, "project.yml")
+	spec, err := xcodegen.Load(specPath)
+	if os.IsNotExist(unwrapAll(err)) {
+		xcodeproj, findErr := findXcodeproj(projectDir)
+		if findErr != nil {
+			return nil, findErr
+		}
+		data, readErr := os.ReadFile(filepath.Join(projectDir, xcodeproj, "project.pbxproj"))
+		if readErr != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", xcodeproj, readErr)
+		}
+		file, parseErr := pbxproj.Parse(data)
+		if parseErr != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", xcodeproj, parseErr)
+		}
+		spec = specFromPBXProj(projectDir, strings.TrimSuffix(xcodeproj, ".xcodeproj"), file)
+		if err := spec.Save(specPath); err != nil {
+			return nil, err
+		}
+		result.Source = xcodeproj
+		result.GeneratedSpec = true
+	} else if err != nil {
+		return nil, err
+	}
+
+	cfg, err := configFromSpec(projectDir, spec)
+	if err != nil {
+		return nil, err
+	}
+	if err := saveProjectConfigFile(projectDir, cfg); err != nil {
+		return nil, err
+	}
+	if err := writeAdoptedWorkspace(projectDir, cfg); err != nil {
+		return nil, err
+	}
+
+	result.AppName = cfg.AppName
+	result.BundleID = cfg.BundleID
+	result.Platform = cfg.Platform
+	result.Platforms = cfg.Platforms
+	result.WatchProjectShape = cfg.WatchProjectShape
+	result.DeviceFamily = cfg.DeviceFamily
+	for _, ext := range cfg.Extensions {
+		result.Extensions = append(result.Extensions, fmt.Sprintf("%s (%s)", ext.Name, ext.Kind))
+	}
+	for _, pkg := range cfg.Packages {
+		result.Packages = append(result.Packages, pkg.Name)
+	}
+	for _, perm := range cfg.Permissions {
+		result.Permissions = append(result.Permissions, perm.Key)
+	}
+	for _, ent := range cfg.Entitlements {
+		result.Entitlements = append(result.Entitlements, ent.Key)
+	}
+	return result, nil
+}
+
+// unwrapAll returns the innermost wrapped error.
+func unwrapAll(err error) error {
+	for {
+		inner, ok := err.(interface{ Unwrap() error })
+		if !ok || inner.Unwrap() == nil {
+			return err
+		}
+		err = inner.Unwrap()
+	}
+}
+
+// findXcodeproj returns the name of the only .xcodeproj bundle in projectDir.
+func findXcodeproj(projectDir string) (string, error) {
+	entries, err := os.ReadDir(projectDir)
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", projectDir, err)
+	}
+	var found []string
+	for _, entry := range entries {
+		if entry.IsDir() && strings.HasSuffix(entry.Name(), ".xcodeproj") {
+			found = append(found, entry.Name())
+		}
+	}
+	switch len(found) {
+	case 0:
+		return "", fmt.Errorf("no project.yml or .xcodeproj found in %s", projectDir)
+	case 1:
+		return found[0], nil
+	default:
+		return "", fmt.Errorf("multiple .xcodeproj bundles in %s (%s); add a project.yml to pick one", projectDir, strings.Join(found, ", "))
+	}
+}
+
+// writeAdoptedWorkspace writes the Claude scaffold (memory, rules, skills,
+// commands, agents, scripts and MCP config) into an adopted project. Unlike a
+// new build it leaves the project's own Makefile and CI workflows alone.
+func writeAdoptedWorkspace(projectDir string, cfg *projectConfigFile) error {
+	reg := mcpregistry.New()
+	mcpregistry.RegisterAll(reg)
+
+	platform := cfg.Platform
+	shape := cfg.WatchProjectShape
+	if len(cfg.Platforms) > 1 {
+		platform = PlatformIOS
+		shape = ""
+	}
+
+	if err := setupWorkspace(projectDir); err != nil {
+		return fmt.Errorf("workspace setup failed: %w", err)
+	}
+	if err := writeInitialCLAUDEMD(projectDir, cfg.AppName, cfg.Platform, cfg.DeviceFamily); err != nil {
+		return fmt.Errorf("failed to write CLAUDE.md: %w", err)
+	}
+	if err := writeCoreRules(projectDir, cfg.Platform, nil, nil); err != nil {
+		return fmt.Errorf("failed to write core rules: %w", err)
+	}
+	if len(cfg.Platforms) > 1 {
+		if err := writeAlwaysSkills(projectDir, cfg.Platforms[0], cfg.Platforms[1:]...); err != nil {
+			return fmt.Errorf("failed to write always skills: %w", err)
+		}
+	} else if err := writeAlwaysSkills(projectDir, cfg.Platform); err != nil {
+		return fmt.Errorf("failed to write always skills: %w", err)
+	}
+
+	steps := []func() error{
+		func() error { return writeSkillCatalog(projectDir) },
+		func() error { return writeClaudeCommandsWithShape(projectDir, cfg.AppName, platform, shape) },
+		func() error { return writeClaudeAgents(projectDir) },
+		func() error { return writeClaudeScriptsWithShape(projectDir, cfg.AppName, platform, shape) },
+		func() error { return writeClaudeWorkflowDocsWithShape(projectDir, cfg.AppName, platform, shape) },
+		func() error { return writeMCPConfig(projectDir, reg, nil) },
+		func() error { return writeSettingsShared(projectDir, reg, nil) },
+		func() error { return writeSettingsLocal(projectDir) },
+	}
+	for _, step := range steps {
+		if err := step(); err != nil {
+			return fmt.Errorf("failed to write Claude project scaffold: %w", err)
+		}
+	}
+	return nil
+}
+
+// configFromSpec infers project_config.json from an XcodeGen spec.
+func configFromSpec(projectDir string, spec *xcodegen.Spec) (*projectConfigFile, error) {
+	mainName, main, err := spec.Target("")
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := &projectConfigFile{AppName: mainName, Source: "project.yml"}
+	cfg.BundleID = specSetting(main, "PRODUCT_BUNDLE_IDENTIFIER")
+	if cfg.BundleID == "" || strings.Contains(cfg.BundleID, "$(") {
+		cfg.BundleID = strings.ToLower(mainName)
+		if prefix := spec.Options.BundleIDPrefix; prefix != "" {
+			cfg.BundleID = prefix + "." + cfg.BundleID
+		}
+	}
+
+	if version := specSetting(main, "MARKETING_VERSION"); version != "" && !strings.Contains(version, "$(") {
+		cfg.MarketingVersion = version
+	}
+	if build, err := strconv.Atoi(specSetting(main, "CURRENT_PROJECT_VERSION")); err == nil && build > 0 {
+		cfg.BuildNumber = build
+	}
+
+	// Platforms of the application targets, main target first.
+	var platforms []string
+	hasWatchApp := false
+	for _, name := range append([]string{mainName}, spec.Targets.Keys()...) {
+		target, _ := spec.Targets.Get(name)
+		if target == nil || !strings.HasPrefix(target.Type, "application") {
+			continue
+		}
+		if strings.HasPrefix(target.Type, "application.watchapp2") {
+			hasWatchApp = true
+		}
+		if platform := xcodegen.PlatformFromXcodeGen(target.Platform); platform != "" && !slices.Contains(platforms, platform) {
+			platforms = append(platforms, platform)
+		}
+	}
+	switch {
+	case main.Type == "application.watchapp2-container" || (hasWatchApp && len(platforms) == 1):
+		cfg.Platform = PlatformWatchOS
+		cfg.WatchProjectShape = WatchShapeStandalone
+	case len(platforms) == 2 && platforms[0] == PlatformIOS && slices.Contains(platforms, PlatformWatchOS):
+		cfg.Platform = PlatformWatchOS
+		cfg.WatchProjectShape = WatchShapePaired
+	case len(platforms) > 1:
+		cfg.Platform = platforms[0]
+		cfg.Platforms = platforms
+	case len(platforms) == 1:
+		cfg.Platform = platforms[0]
+	default:
+		cfg.Platform = PlatformIOS
+	}
+	if cfg.Platform == PlatformIOS {
+		switch specSetting(main, "TARGETED_DEVICE_FAMILY") {
+		case "2":
+			cfg.DeviceFamily = "ipad"
+		case "1,2":
+			cfg.DeviceFamily = "universal"
+		default:
+			cfg.DeviceFamily = "iphone"
+		}
+	}
+
+	// Permissions: INFOPLIST_KEY_*UsageDescription settings and usage keys of the Info.plist.
+	info := targetInfoPlist(projectDir, main)
+	if main.Settings != nil {
+		for _, key := range main.Settings.Base.Keys() {
+			if strings.HasPrefix(key, "INFOPLIST_KEY_") && strings.HasSuffix(key, "UsageDescription") {
+				info[strings.TrimPrefix(key, "INFOPLIST_KEY_")] = specSetting(main, key)
+			}
+		}
+	}
+	for _, key := range sortedMapKeys(info) {
+		if !strings.HasSuffix(key, "UsageDescription") {
+			continue
+		}
+		desc, _ := info[key].(string)
+		cfg.Permissions = append(cfg.Permissions, configPermission{
+			Key:         key,
+			Description: desc,
+			Framework:   permissionFramework(key),
+		})
+	}
+
+	for _, lang := range spec.Options.KnownRegions {
+		if lang != "Base" {
+			cfg.Localizations = append(cfg.Localizations, lang)
+		}
+	}
+
+	for _, name := range spec.Targets.Keys() {
+		target, _ := spec.Targets.Get(name)
+		if target == nil {
+			continue
+		}
+		entitlementTarget := name
+		if name == mainName {
+			entitlementTarget = ""
+		}
+		ents := targetEntitlements(projectDir, target)
+		for _, key := range sortedMapKeys(ents) {
+			cfg.Entitlements = append(cfg.Entitlements, configEntitlement{Key: key, Value: ents[key], Target: entitlementTarget})
+		}
+
+		kind := extensionKind(target, targetInfoPlist(projectDir, target))
+		if kind == "" {
+			continue
+		}
+		ext := configExtension{Kind: kind, Name: name}
+		if platform := xcodegen.PlatformFromXcodeGen(target.Platform); len(cfg.Platforms) > 1 && platform != PlatformIOS {
+			ext.Platform = platform
+		}
+		cfg.Extensions = append(cfg.Extensions, ext)
+	}
+
+	for _, name := range spec.Packages.Keys() {
+		pkg, _ := spec.Packages.Get(name)
+		if pkg == nil || pkg.URL == "" {
+			continue // local packages have no registry entry
+		}
+		dep := configPackage{Name: name, URL: pkg.URL, MinVersion: pkg.From}
+		if v, ok := pkg.Extra["exactVersion"]; ok && dep.MinVersion == "" {
+			dep.ExactVersion = fmt.Sprint(v)
+		} else if v, ok := pkg.Extra["maxVersion"]; ok && dep.MinVersion == "" {
+			dep.MaxVersion = fmt.Sprint(v)
+		}
+		if dep.MinVersion == "" && dep.ExactVersion == "" {
+			for _, key := range []string{"version", "majorVersion", "minorVersion", "minVersion"} {
+				if v, ok := pkg.Extra[key]; ok {
+					dep.MinVersion = fmt.Sprint(v)
+					break
+				}
+			}
+		}
+		for _, d := range main.Dependencies {
+			if d.Package == name && d.Product != "" {
+				dep.Products = append(dep.Products, d.Product)
+			}
+		}
+		cfg.Packages = append(cfg.Packages, dep)
+	}
+
+	return cfg, nil
+}
+
+// specSetting returns a base build setting of a target as a string.
+func specSetting(target *xcodegen.Target, key string) string {
+	if target.Settings == nil {
+		return ""
+	}
+	v, ok := target.Settings.Base.Get(key)
+	if !ok || v == nil {
+		return ""
+	}
+	return fmt.Sprint(v)
+}
+
+// targetInfoPlist returns the Info.plist properties of a target: the spec's
+// info properties merged over the INFOPLIST_FILE / info.path file on disk.
+func targetInfoPlist(projectDir string, target *xcodegen.Target) map[string]any {
+	props := make(map[string]any)
+	file := specSetting(target, "INFOPLIST_FILE")
+	if target.Info != nil && target.Info.Path != "" {
+		file = target.Info.Path
+	}
+	if file != "" {
+		if m, err := readPlistFile(filepath.Join(projectDir, file)); err == nil {
+			props = m
+		}
+	}
+	if target.Info != nil {
+		for k, v := range target.Info.Properties {
+			props[k] = v
+		}
+	}
+	return props
+}
+
+// targetEntitlements returns a target's entitlements: the spec's properties
+// merged over the CODE_SIGN_ENTITLEMENTS / entitlements.path file on disk.
+func targetEntitlements(projectDir string, target *xcodegen.Target) map[string]any {
+	ents := make(map[string]any)
+	file := specSetting(target, "CODE_SIGN_ENTITLEMENTS")
+	if target.Entitlements != nil && target.Entitlements.Path != "" {
+		file = target.Entitlements.Path
+	}
+	if file != "" {
+		if m, err := readPlistFile(filepath.Join(projectDir, file)); err == nil {
+			ents = m
+		}
+	}
+	if target.Entitlements != nil {
+		for k, v := range target.Entitlements.Properties {
+			ents[k] = v
+		}
+	}
+	return ents
+}
+
+// extensionKind maps an extension target to a nanowave extension kind, or ""
+// for targets that are not extensions.
+func extensionKind(target *xcodegen.Target, info map[string]any) string {
+	switch target.Type {
+	case "application.on-demand-install-capable", "app-clip":
+		return "app_clip"
+	case "app-extension", "extensionkit-extension", "tv-app-extension", "watchkit2-extension":
+	default:
+		return ""
+	}
+	if target.Type == "watchkit2-extension" {
+		return "" // intrinsic watch app extension, part of the watch app itself
+	}
+
+	var point string
+	if ext, ok := info["NSExtension"].(map[string]any); ok {
+		point, _ = ext["NSExtensionPointIdentifier"].(string)
+	}
+	if ext, ok := info["EXAppExtensionAttributes"].(map[string]any); ok {
+		point, _ = ext["EXExtensionPointIdentifier"].(string)
+	}
+	switch point {
+	case "com.apple.widgetkit-extension":
+		return "widget"
+	case "com.apple.share-services":
+		return "share"
+	case "com.apple.usernotifications.service":
+		return "notification_service"
+	case "com.apple.usernotifications.content-extension":
+		return "notification_content"
+	case "com.apple.appintents-extension":
+		return "app_intents"
+	case "com.apple.spotlight.index":
+		return "spotlight"
+	case "com.apple.Safari.web-extension":
+		return "safari"
+	}
+	return "extension"
+}
+
+// permissionFramework returns the framework usually behind an Info.plist usage key.
+func permissionFramework(key string) string {
+	switch key {
+	case "NSCameraUsageDescription", "NSMicrophoneUsageDescription":
+		return "AVFoundation"
+	case "NSPhotoLibraryUsageDescription", "NSPhotoLibraryAddUsageDescription":
+		return "Photos"
+	case "NSLocationWhenInUseUsageDescription", "NSLocationAlwaysAndWhenInUseUsageDescription":
+		return "CoreLocation"
+	case "NSContactsUsageDescription":
+		return "Contacts"
+	case "NSCalendarsUsageDescription", "NSCalendarsFullAccessUsageDescription", "NSRemindersUsageDescription", "NSRemindersFullAccessUsageDescription":
+		return "EventKit"
+	case "NSHealthShareUsageDescription", "NSHealthUpdateUsageDescription":
+		return "HealthKit"
+	case "NSMotionUsageDescription":
+		return "CoreMotion"
+	case "NSSpeechRecognitionUsageDescription":
+		return "Speech"
+	case "NSBluetoothAlwaysUsageDescription":
+		return "CoreBluetooth"
+	case "NSFaceIDUsageDescription":
+		return "LocalAuthentication"
+	case "NSUserTrackingUsageDescription":
+		return "AppTrackingTransparency"
+	}
+	return ""
+}
+
+func sortedMapKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// specFromPBXProj builds an XcodeGen spec describing an Xcode project, so the
+// project can be regenerated with XcodeGen and edited through project.yml.
+func specFromPBXProj(projectDir, name string, f *pbxproj.File) *xcodegen.Spec {
+	root := f.Root()
+	spec := &xcodegen.Spec{Name: name}
+	spec.Options.CreateIntermediateGroups = true
+	for _, lang := range root.Strings("knownRegions") {
+		if lang != "Base" {
+			spec.Options.KnownRegions = append(spec.Options.KnownRegions, lang)
+		}
+	}
+
+	// Packages, keyed by the reference object so product dependencies can find their package name.
+	packageNames := make(map[string]string)
+	for _, id := range root.Strings("packageReferences") {
+		ref := f.Objects[id]
+		switch ref.Isa() {
+		case "XCRemoteSwiftPackageReference":
+			url := ref.String("repositoryURL")
+			pkgName := strings.TrimSuffix(path.Base(url), ".git")
+			pkg := &xcodegen.SwiftPackage{URL: url}
+			setPackageRequirement(pkg, ref.Map("requirement"))
+			spec.Packages.Set(pkgName, pkg)
+			packageNames[id] = pkgName
+		case "XCLocalSwiftPackageReference":
+			rel := ref.String("relativePath")
+			pkgName := path.Base(rel)
+			spec.Packages.Set(pkgName, &xcodegen.SwiftPackage{Path: rel})
+			packageNames[id] = pkgName
+		}
+	}
+
+	// Top-level groups by name, for targets that predate synchronized folders.
+	groupPaths := make(map[string]string)
+	for _, child := range f.Refs(f.Ref(root, "mainGroup"), "children") {
+		p := child.String("path")
+		if n := child.String("name"); n != "" {
+			groupPaths[n] = p
+		}
+		if p != "" {
+			groupPaths[path.Base(p)] = p
+		}
+	}
+
+	projectSettings := buildSettings(f, f.Ref(root, "buildConfigurationList"))
+	targetNames := make(map[string]string) // object ID → target name
+	for _, id := range root.Strings("targets") {
+		targetNames[id] = f.Objects[id].String("name")
+	}
+
+	var bundleIDs []string
+	for _, id := range root.Strings("targets") {
+		obj := f.Objects[id]
+		if obj.Isa() != "PBXNativeTarget" {
+			continue
+		}
+		targetName := obj.String("name")
+		settings := buildSettings(f, f.Ref(obj, "buildConfigurationList"))
+		sdk := settings.value("SDKROOT")
+		if sdk == "" {
+			sdk = projectSettings.value("SDKROOT")
+		}
+
+		target := &xcodegen.Target{
+			Type:     strings.TrimPrefix(obj.String("productType"), "com.apple.product-type."),
+			Platform: sdkPlatform(sdk),
+		}
+
+		for _, group := range f.Refs(obj, "fileSystemSynchronizedGroups") {
+			target.Sources = append(target.Sources, xcodegen.Source{Path: group.String("path"), Type: "syncedFolder"})
+		}
+		if len(target.Sources) == 0 {
+			if p, ok := groupPaths[targetName]; ok && p != "" {
+				target.Sources = []xcodegen.Source{{Path: p}}
+			} else if info, err := os.Stat(filepath.Join(projectDir, targetName)); err == nil && info.IsDir() {
+				target.Sources = []xcodegen.Source{{Path: targetName}}
+			}
+		}
+
+		target.Settings = settings.spec()
+		if deployment := deploymentTargetSetting(target.Platform); deployment != "" {
+			if v := settings.value(deployment); v != "" {
+				spec.Options.DeploymentTarget.Set(target.Platform, v)
+			} else if v := projectSettings.value(deployment); v != "" {
+				spec.Options.DeploymentTarget.Set(target.Platform, v)
+			}
+		}
+		if bundleID := settings.value("PRODUCT_BUNDLE_IDENTIFIER"); bundleID != "" && !strings.Contains(bundleID, "$(") {
+			bundleIDs = append(bundleIDs, bundleID)
+		}
+
+		for _, dep := range f.Refs(obj, "dependencies") {
+			if depName := targetNames[dep.String("target")]; depName != "" {
+				target.Dependencies = append(target.Dependencies, xcodegen.Dependency{Target: depName})
+			}
+		}
+		for _, product := range f.Refs(obj, "packageProductDependencies") {
+			pkgName := packageNames[product.String("package")]
+			if pkgName == "" {
+				continue
+			}
+			dep := xcodegen.Dependency{Package: pkgName}
+			if productName := product.String("productName"); productName != pkgName {
+				dep.Product = productName
+			}
+			target.Dependencies = append(target.Dependencies, dep)
+		}
+
+		spec.Targets.Set(targetName, target)
+	}
+
+	if len(bundleIDs) > 0 {
+		if i := strings.LastIndex(bundleIDs[0], "."); i > 0 {
+			spec.Options.BundleIDPrefix = bundleIDs[0][:i]
+		}
+	}
+
+	// One scheme for the app target, building everything it depends on.
+	if mainName := spec.MainTargetName(); mainName != "" {
+		scheme := &xcodegen.Scheme{Run: &xcodegen.SchemeRun{Executable: mainName}}
+		scheme.Build.Targets.Set(mainName, "all")
+		main, _ := spec.Targets.Get(mainName)
+		for _, dep := range main.Dependencies {
+			if dep.Target != "" {
+				scheme.Build.Targets.Set(dep.Target, "all")
+			}
+		}
+		spec.Schemes.Set(mainName, scheme)
+	}
+	return spec
+}
+
+// setPackageRequirement translates an Xcode package requirement into XcodeGen keys.
+func setPackageRequirement(pkg *xcodegen.SwiftPackage, req map[string]any) {
+	str := func(key string) string {
+		s, _ := req[key].(string)
+		return s
+	}
+	extra := func(key, value string) {
+		if pkg.Extra == nil {
+			pkg.Extra = make(map[string]any)
+		}
+		pkg.Extra[key] = value
+	}
+	switch str("kind") {
+	case "upToNextMajorVersion":
+		pkg.From = str("minimumVersion")
+	case "upToNextMinorVersion":
+		extra("minorVersion", str("minimumVersion"))
+	case "exactVersion":
+		extra("exactVersion", str("version"))
+	case "branch":
+		extra("branch", str("branch"))
+	case "revision":
+		extra("revision", str("revision"))
+	case "versionRange":
+		extra("minVersion", str("minimumVersion"))
+		extra("maxVersion", str("maximumVersion"))
+	}
+}
+
+// sdkPlatform maps SDKROOT to the XcodeGen platform value.
+func sdkPlatform(sdk string) string {
+	switch sdk {
+	case "macosx":
+		return "macOS"
+	case "watchos":
+		return "watchOS"
+	case "appletvos":
+		return "tvOS"
+	case "xros":
+		return "visionOS"
+	default:
+		return "iOS"
+	}
+}
+
+// deploymentTargetSetting returns the deployment target build setting for an XcodeGen platform.
+func deploymentTargetSetting(platform string) string {
+	switch platform {
+	case "iOS":
+		return "IPHONEOS_DEPLOYMENT_TARGET"
+	case "macOS":
+		return "MACOSX_DEPLOYMENT_TARGET"
+	case "watchOS":
+		return "WATCHOS_DEPLOYMENT_TARGET"
+	case "tvOS":
+		return "TVOS_DEPLOYMENT_TARGET"
+	case "visionOS":
+		return "XROS_DEPLOYMENT_TARGET"
+	}
+	return ""
+}
+
+// configSettings holds the build settings of each configuration of a target, in order.
+type configSettings struct {
+	names    []string
+	settings []map[string]any
+}
+
+// buildSettings collects the build configurations of an XCConfigurationList.
+func buildSettings(f *pbxproj.File, list pbxproj.Object) configSettings {
+	var cs configSettings
+	for _, conf := range f.Refs(list, "buildConfigurations") {
+		cs.names = append(cs.names, conf.String("name"))
+		cs.settings = append(cs.settings, conf.Map("buildSettings"))
+	}
+	return cs
+}
+
+// value returns a setting from the first configuration that defines it.
+func (cs configSettings) value(key string) string {
+	for _, s := range cs.settings {
+		if v, ok := s[key].(string); ok {
+			return v
+		}
+	}
+	return ""
+}
+
+// spec splits the settings into base (identical in every configuration) and
+// per-configuration overrides.
+func (cs configSettings) spec() *xcodegen.Settings {
+	out := &xcodegen.Settings{}
+	keys := make(map[string]bool)
+	for _, s := range cs.settings {
+		for k := range s {
+			keys[k] = true
+		}
+	}
+	sorted := make([]string, 0, len(keys))
+	for k := range keys {
+		sorted = append(sorted, k)
+	}
+	slices.Sort(sorted)
+
+	for _, key := range sorted {
+		first, shared := cs.settings[0][key], true
+		for _, s := range cs.settings[1:] {
+			if v, ok := s[key]; !ok || fmt.Sprint(v) != fmt.Sprint(first) {
+				shared = false
+				break
+			}
+		}
+		if shared {
+			out.Base.Set(key, first)
+			continue
+		}
+		for i, s := range cs.settings {
+			v, ok := s[key]
+			if !ok {
+				continue
+			}
+			if out.Configs == nil {
+				out.Configs = make(map[string]xcodegen.OrderedMap[any])
+			}
+			conf := out.Configs[cs.names[i]]
+			conf.Set(key, v)
+			out.Configs[cs.names[i]] = conf
+		}
+	}
+	if out.Base.Len() == 0 && len(out.Configs) == 0 {
+		return nil
+	}
+	return out
+}
+
+// readPlistFile reads an XML property list whose root is a dictionary.
+func readPlistFile(path string) (map[string]any, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	dec := xml.NewDecoder(f)
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		if start, ok := tok.(xml.StartElement); ok && start.Name.Local != "plist" {
+			v, err := plistValue(dec, start)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", path, err)
+			}
+			m, ok := v.(map[string]any)
+			if !ok {
+				return nil, fmt.Errorf("%s: root is not a dictionary", path)
+			}
+			return m, nil
+		}
+	}
+}
+
+// plistValue decodes the plist element opened by start.
+func plistValue(dec *xml.Decoder, start xml.StartElement) (any, error) {
+	switch start.Name.Local {
+	case "dict":
+		m := make(map[string]any)
+		var key string
+		for {
+			tok, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			switch t := tok.(type) {
+			case xml.StartElement:
+				if t.Name.Local == "key" {
+					if err := dec.DecodeElement(&key, &t); err != nil {
+						return nil, err
+					}
+					continue
+				}
+				v, err := plistValue(dec, t)
+				if err != nil {
+					return nil, err
+				}
+				m[key] = v
+			case xml.EndElement:
+				return m, nil
+			}
+		}
+	case "array":
+		list := []any{}
+		for {
+			tok, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			switch t := tok.(type) {
+			case xml.StartElement:
+				v, err := plistValue(dec, t)
+				if err != nil {
+					return nil, err
+				}
+				list = append(list, v)
+			case xml.EndElement:
+				return list, nil
+			}
+		}
+	case "true", "false":
+		if err := dec.Skip(); err != nil {
+			return nil, err
+		}
+		return start.Name.Local == "true", nil
+	}
+
+	var text string
+	if err := dec.DecodeElement(&text, &start); err != nil && err != io.EOF {
+		return nil, err
+	}
+	switch start.Name.Local {
+	case "integer":
+		if n, err := strconv.Atoi(strings.TrimSpace(text)); err == nil {
+			return n, nil
+		}
+	case "real":
+		if n, err := strconv.ParseFloat(strings.TrimSpace(text), 64); err == nil {
+			return n, nil
+		}
+	}
+	return text, nil
+}
@@ -0,0 +1,171 @@
+package orchestration
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// copyTree copies the src fixture directory into dst.
+func copyTree(t *testing.T, src, dst string) {
+	t.Helper()
+	err := filepath.Walk(src, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, _ := filepath.Rel(src, path)
+		target := filepath.Join(dst, rel)
+		if info.IsDir() {
+			return os.MkdirAll(target, 0o755)
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(target, data, 0o644)
+	})
+	if err != nil {
+		t.Fatalf("copy fixture: %v", err)
+	}
+}
+
+func readAdoptedConfig(t *testing.T, projectDir string) projectConfigFile {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(projectDir, "project_config.json"))
+	if err != nil {
+		t.Fatalf("read project_config.json: %v", err)
+	}
+	var cfg projectConfigFile
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		t.Fatalf("parse project_config.json: %v", err)
+	}
+	return cfg
+}
+
+func TestAdoptProjectFromXcodeproj(t *testing.T) {
+	projectDir := t.TempDir()
+	copyTree(t, filepath.Join("testdata", "adopt"), projectDir)
+
+	result, err := AdoptProject(projectDir)
+	if err != nil {
+		t.Fatalf("AdoptProject: %v", err)
+	}
+	if result.Source != "Habits.xcodeproj" || !result.GeneratedSpec {
+		t.Errorf("Source = %q, GeneratedSpec = %v; want Habits.xcodeproj, true", result.Source, result.GeneratedSpec)
+	}
+
+	cfg := readAdoptedConfig(t, projectDir)
+	if cfg.AppName != "Habits" || cfg.BundleID != "com.acme.habits" {
+		t.Errorf("app = %s (%s), want Habits (com.acme.habits)", cfg.AppName, cfg.BundleID)
+	}
+	if cfg.Platform != PlatformIOS || cfg.DeviceFamily != "universal" || cfg.Source != "project.yml" {
+		t.Errorf("platform = %s/%s source %q, want ios/universal source project.yml", cfg.Platform, cfg.DeviceFamily, cfg.Source)
+	}
+	if len(cfg.Permissions) != 1 || cfg.Permissions[0].Key != "NSHealthShareUsageDescription" || cfg.Permissions[0].Framework != "HealthKit" {
+		t.Errorf("permissions = %+v, want NSHealthShareUsageDescription (HealthKit)", cfg.Permissions)
+	}
+	if len(cfg.Extensions) != 1 || cfg.Extensions[0].Kind != "widget" || cfg.Extensions[0].Name != "HabitsWidget" {
+		t.Errorf("extensions = %+v, want HabitsWidget widget", cfg.Extensions)
+	}
+	if len(cfg.Packages) != 1 || cfg.Packages[0].Name != "lottie-spm" || cfg.Packages[0].MinVersion != "4.5.0" ||
+		len(cfg.Packages[0].Products) != 1 || cfg.Packages[0].Products[0] != "Lottie" {
+		t.Errorf("packages = %+v, want lottie-spm 4.5.0 [Lottie]", cfg.Packages)
+	}
+	keys := map[string]bool{}
+	for _, ent := range cfg.Entitlements {
+		keys[ent.Key] = true
+	}
+	if !keys["com.apple.developer.healthkit"] || !keys["com.apple.security.application-groups"] {
+		t.Errorf("entitlements = %+v, want healthkit and app groups", cfg.Entitlements)
+	}
+	if len(cfg.Localizations) != 2 || cfg.Localizations[0] != "en" || cfg.Localizations[1] != "fr" {
+		t.Errorf("localizations = %v, want [en fr]", cfg.Localizations)
+	}
+
+	spec, err := os.ReadFile(filepath.Join(projectDir, "project.yml"))
+	if err != nil {
+		t.Fatalf("read project.yml: %v", err)
+	}
+	for _, want := range []string{
+		"bundleIdPrefix: com.acme",
+		"iOS: \"18.0\"",
+		"lottie-spm:\n    url: https://github.com/airbnb/lottie-spm.git\n    from: 4.5.0",
+		"- path: Habits\n        type: syncedFolder",
+		"CODE_SIGN_ENTITLEMENTS: Habits/Habits.entitlements",
+		"configs:\n        Debug:\n          SWIFT_ACTIVE_COMPILATION_CONDITIONS:",
+		"- target: HabitsWidget",
+		"- package: lottie-spm\n        product: Lottie",
+		"HabitsWidget:\n    type: app-extension",
+	} {
+		if !strings.Contains(string(spec), want) {
+			t.Errorf("project.yml missing %q:\n%s", want, spec)
+		}
+	}
+
+	for _, path := range []string{".claude/CLAUDE.md", ".mcp.json", ".claude/settings.json", ".claude/commands"} {
+		if _, err := os.Stat(filepath.Join(projectDir, path)); err != nil {
+			t.Errorf("scaffold missing %s: %v", path, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(projectDir, "Makefile")); err == nil {
+		t.Error("adopt should not write a Makefile")
+	}
+
+	if _, err := AdoptProject(projectDir); err == nil {
+		t.Error("adopting an already managed project should fail")
+	}
+}
+
+func TestAdoptProjectFromProjectYML(t *testing.T) {
+	projectDir := t.TempDir()
+	spec := `name: Trails
+options:
+  bundleIdPrefix: com.example
+targets:
+  Trails:
+    type: application
+    platform: macOS
+    sources: [Trails]
+    info:
+      path: Trails/Info.plist
+      properties:
+        NSCameraUsageDescription: Scan trail markers
+  TrailsTests:
+    type: bundle.unit-test
+    platform: macOS
+`
+	if err := os.WriteFile(filepath.Join(projectDir, "project.yml"), []byte(spec), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := AdoptProject(projectDir)
+	if err != nil {
+		t.Fatalf("AdoptProject: %v", err)
+	}
+	if result.GeneratedSpec {
+		t.Error("an existing project.yml should not be rewritten")
+	}
+	data, _ := os.ReadFile(filepath.Join(projectDir, "project.yml"))
+	if string(data) != spec {
+		t.Errorf("project.yml changed:\n%s", data)
+	}
+
+	cfg := readAdoptedConfig(t, projectDir)
+	if cfg.AppName != "Trails" || cfg.BundleID != "com.example.trails" || cfg.Platform != PlatformMacOS {
+		t.Errorf("config = %s %s %s, want Trails com.example.trails macos", cfg.AppName, cfg.BundleID, cfg.Platform)
+	}
+	if cfg.DeviceFamily != "" || len(cfg.Extensions) != 0 {
+		t.Errorf("device family %q, extensions %+v; want none", cfg.DeviceFamily, cfg.Extensions)
+	}
+	if len(cfg.Permissions) != 1 || cfg.Permissions[0].Framework != "AVFoundation" {
+		t.Errorf("permissions = %+v, want camera (AVFoundation)", cfg.Permissions)
+	}
+}
+
+func TestAdoptProjectWithoutProject(t *testing.T) {
+	if _, err := AdoptProject(t.TempDir()); err == nil || !strings.Contains(err.Error(), "no project.yml or .xcodeproj") {
+		t.Errorf("AdoptProject error = %v, want no project found", err)
+	}
+}
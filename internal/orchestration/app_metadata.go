@@ -0,0 +1,238 @@
+package orchestration
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/moasq/nanowave/internal/asc"
+	"github.com/moasq/nanowave/internal/metadata"
+)
+
+// metadataSourceLocale is the locale generated metadata is written in; other
+// locales are translated from it.
+const metadataSourceLocale = "en-US"
+
+// categoryKeywords guesses an App Store category from words in the app's
+// analysis. The first category with a matching word wins.
+var categoryKeywords = []struct {
+	category string
+	words    []string
+}{
+	{"HEALTH_AND_FITNESS", []string{"habit", "workout", "fitness", "health", "meditation", "sleep", "water", "step", "calorie"}},
+	{"FINANCE", []string{"budget", "expense", "finance", "money", "invoice", "savings", "spending"}},
+	{"FOOD_AND_DRINK", []string{"recipe", "meal", "food", "cooking", "drink", "grocery"}},
+	{"EDUCATION", []string{"learn", "study", "flashcard", "quiz", "lesson", "course"}},
+	{"TRAVEL", []string{"travel", "trip", "itinerary", "flight", "packing"}},
+	{"WEATHER", []string{"weather", "forecast"}},
+	{"MUSIC", []string{"music", "song", "playlist", "metronome", "tuner"}},
+	{"PHOTO_AND_VIDEO", []string{"photo", "video", "camera"}},
+	{"GAMES", []string{"game", "puzzle", "score"}},
+	{"PRODUCTIVITY", []string{"task", "todo", "note", "planner", "reminder", "journal", "productivity"}},
+}
+
+// metadataFor derives first-draft store metadata from the analysis, in the
+// source locale. URLs are left for the developer since nanowave cannot know them.
+func metadataFor(appName string, analysis *AnalysisResult) *metadata.Metadata {
+	if analysis == nil {
+		return nil
+	}
+	loc := metadata.Locale{
+		"name":             truncateWords(appName, 30),
+		"subtitle":         truncateWords(firstSentence(analysis.Description), 30),
+		"promotional_text": truncateWords(analysis.Description, 170),
+	}
+
+	var desc strings.Builder
+	desc.WriteString(strings.TrimSpace(analysis.Description))
+	if len(analysis.Features) > 0 {
+		desc.WriteString("\n\nFeatures:\n")
+		for _, f := range analysis.Features {
+			fmt.Fprintf(&desc, "• %s — %s\n", f.Name, strings.TrimSpace(f.Description))
+		}
+	}
+	loc["description"] = truncateWords(desc.String(), 4000)
+	loc["keywords"] = keywordsFor(appName, analysis)
+
+	return &metadata.Metadata{
+		PrimaryCategory: categoryFor(analysis),
+		AgeRating:       metadata.DefaultAgeRating(),
+		Locales:         map[string]metadata.Locale{metadataSourceLocale: loc},
+	}
+}
+
+// writeMetadata stores generated metadata with the project. An existing
+// metadata directory is kept, since it is edited by hand afterwards.
+func writeMetadata(projectDir, appName string, analysis *AnalysisResult) error {
+	if existing, err := metadata.Load(projectDir); existing != nil || err != nil {
+		return err
+	}
+	m := metadataFor(appName, analysis)
+	if m == nil {
+		return nil
+	}
+	return metadata.Save(projectDir, m)
+}
+
+func categoryFor(analysis *AnalysisResult) string {
+	var text strings.Builder
+	text.WriteString(analysis.Description)
+	for _, f := range analysis.Features {
+		text.WriteString(" " + f.Name + " " + f.Description)
+	}
+	lower := strings.ToLower(text.String())
+	for _, c := range categoryKeywords {
+		for _, w := range c.words {
+			if strings.Contains(lower, w) {
+				return c.category
+			}
+		}
+	}
+	return "UTILITIES"
+}
+
+// keywordsFor joins distinct feature words into a comma-separated keyword
+// list within the 100 character limit. The app name is left out since the
+// App Store already indexes it.
+func keywordsFor(appName string, analysis *AnalysisResult) string {
+	seen := map[string]bool{}
+	for _, w := range strings.Fields(strings.ToLower(appName)) {
+		seen[w] = true
+	}
+	var keywords []string
+	length := 0
+	for _, f := range analysis.Features {
+		for _, w := range strings.FieldsFunc(strings.ToLower(f.Name), func(r rune) bool {
+			return r == ' ' || r == '/' || r == '&' || r == ','
+		}) {
+			if utf8.RuneCountInString(w) < 3 || seen[w] {
+				continue
+			}
+			n := utf8.RuneCountInString(w)
+			if len(keywords) > 0 {
+				n++ // comma
+			}
+			if length+n > 100 {
+				return strings.Join(keywords, ",")
+			}
+			seen[w] = true
+			keywords = append(keywords, w)
+			length += n
+		}
+	}
+	return strings.Join(keywords, ",")
+}
+
+func firstSentence(s string) string {
+	s = strings.TrimSpace(s)
+	if i := strings.IndexAny(s, ".!?\n"); i >= 0 {
+		return s[:i]
+	}
+	return s
+}
+
+// truncateWords shortens s to at most limit characters, cutting at a word
+// boundary.
+func truncateWords(s string, limit int) string {
+	s = strings.TrimSpace(s)
+	if utf8.RuneCountInString(s) <= limit {
+		return s
+	}
+	cut := string([]rune(s)[:limit])
+	if i := strings.LastIndexAny(cut, " \n"); i > 0 {
+		cut = cut[:i]
+	}
+	return strings.TrimRight(cut, " ,;:—-")
+}
+
+// MetadataSync is the difference between a project's metadata directory and
+// its app in App Store Connect.
+type MetadataSync struct {
+	Local    *metadata.Metadata
+	Remote   *metadata.Remote
+	Changes  []metadata.Change
+	Problems []metadata.Problem
+
+	client *asc.Client
+}
+
+// PlanMetadataSync validates the project's metadata and compares it with the
+// linked App Store Connect app. client may be nil to use the stored API key.
+func PlanMetadataSync(ctx context.Context, projectDir string, client *asc.Client) (*MetadataSync, error) {
+	local, err := metadata.Load(projectDir)
+	if err != nil {
+		return nil, err
+	}
+	if local == nil {
+		return nil, fmt.Errorf("no %s directory in %s", metadata.Dir, projectDir)
+	}
+	client, appID, err := linkedASCApp(projectDir, client)
+	if err != nil {
+		return nil, err
+	}
+
+	remote, err := metadata.Fetch(ctx, client, appID)
+	if err != nil {
+		return nil, err
+	}
+	return &MetadataSync{
+		Local:    local,
+		Remote:   remote,
+		Changes:  metadata.Diff(local, &remote.Metadata),
+		Problems: local.Validate(),
+		client:   client,
+	}, nil
+}
+
+// Push uploads the planned changes. Metadata with validation problems is
+// refused rather than partially uploaded.
+func (s *MetadataSync) Push(ctx context.Context) error {
+	if len(s.Problems) > 0 {
+		return fmt.Errorf("metadata has %d validation problem(s)", len(s.Problems))
+	}
+	return s.Remote.Push(ctx, s.client, s.Local, s.Changes)
+}
+
+// LinkedASCApp returns an App Store Connect client using the stored API key
+// and the ID of the app the project is linked to.
+func LinkedASCApp(projectDir string) (*asc.Client, string, error) {
+	return linkedASCApp(projectDir, nil)
+}
+
+// linkedASCApp is LinkedASCApp with a client that, when non-nil, is used
+// instead of the stored API key.
+func linkedASCApp(projectDir string, client *asc.Client) (*asc.Client, string, error) {
+	appID := readASCAppID(projectDir)
+	if appID == "" {
+		return nil, "", fmt.Errorf("project is not linked to an App Store Connect app; run `nanowave publish` once to link it")
+	}
+	if client == nil {
+		cred, err := asc.LoadCredential()
+		if err != nil {
+			return nil, "", err
+		}
+		if client, err = asc.NewClient(cred); err != nil {
+			return nil, "", err
+		}
+	}
+	return client, appID, nil
+}
+
+// readASCAppID returns the App Store Connect app ID saved in project_config.json.
+func readASCAppID(projectDir string) string {
+	data, err := os.ReadFile(filepath.Join(projectDir, "project_config.json"))
+	if err != nil {
+		return ""
+	}
+	var cfg struct {
+		ASCAppID string `json:"asc_app_id"`
+	}
+	if json.Unmarshal(data, &cfg) != nil {
+		return ""
+	}
+	return cfg.ASCAppID
+}
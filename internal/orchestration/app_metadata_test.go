@@ -0,0 +1,55 @@
+package orchestration
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/moasq/nanowave/internal/metadata"
+)
+
+func TestMetadataForFitsLimits(t *testing.T) {
+	analysis := &AnalysisResult{
+		Description: "A calm habit tracker that helps you build routines that stick. " + strings.Repeat("Stay consistent every day. ", 10),
+		Features: []Feature{
+			{Name: "Daily Check-ins", Description: "Mark habits done with one tap."},
+			{Name: "Streak Calendar", Description: "See your streaks at a glance."},
+			{Name: "Reminders & Notifications", Description: "Gentle nudges at the right time."},
+		},
+	}
+	m := metadataFor("Habit Flow", analysis)
+	if problems := m.Validate(); len(problems) > 0 {
+		t.Errorf("generated metadata has problems: %v", problems)
+	}
+	loc := m.Locales[metadataSourceLocale]
+	if loc["name"] != "Habit Flow" || loc["subtitle"] != "A calm habit tracker that" {
+		t.Errorf("name/subtitle = %q/%q", loc["name"], loc["subtitle"])
+	}
+	if loc["keywords"] != "daily,check-ins,streak,calendar,reminders,notifications" {
+		t.Errorf("keywords = %q", loc["keywords"])
+	}
+	if !strings.Contains(loc["description"], "• Streak Calendar — See your streaks at a glance.") {
+		t.Errorf("description lacks features:\n%s", loc["description"])
+	}
+	if m.PrimaryCategory != "HEALTH_AND_FITNESS" {
+		t.Errorf("PrimaryCategory = %s", m.PrimaryCategory)
+	}
+}
+
+func TestWriteMetadataKeepsExisting(t *testing.T) {
+	dir := t.TempDir()
+	edited := filepath.Join(dir, metadata.Dir, "en-US", "name.txt")
+	os.MkdirAll(filepath.Dir(edited), 0o755)
+	os.WriteFile(edited, []byte("My Name\n"), 0o644)
+
+	if err := writeMetadata(dir, "Generated", &AnalysisResult{Description: "Generated app."}); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(edited); string(data) != "My Name\n" {
+		t.Errorf("writeMetadata overwrote the edited name: %q", data)
+	}
+	if _, err := os.Stat(filepath.Join(dir, metadata.Dir, metadata.AgeRatingFile)); !os.IsNotExist(err) {
+		t.Error("writeMetadata added files to an existing metadata directory")
+	}
+}
@@ -0,0 +1,126 @@
+package orchestration
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/moasq/nanowave/internal/asc"
+	"github.com/moasq/nanowave/internal/projectconfig"
+	"github.com/moasq/nanowave/internal/storage"
+	"github.com/moasq/nanowave/internal/terminal"
+	"github.com/moasq/nanowave/internal/versioning"
+)
+
+// uploadedBuildsChecked is how many of the most recent App Store Connect
+// builds the next build number is chosen above. Build numbers only grow, so
+// the newest uploads hold the highest.
+const uploadedBuildsChecked = 50
+
+// closedVersionStates are App Store version states whose version number no
+// longer accepts new builds. Uploads need a marketing version above the
+// highest of them.
+var closedVersionStates = []string{
+	asc.VersionReadyForSale,
+	asc.VersionPendingDeveloperRelease,
+	asc.VersionProcessingForAppStore,
+}
+
+// prepareVersion reads the project's marketing version and build number into
+// preflight and raises the build number above every build already uploaded
+// for the app, regenerating the Xcode project when it changes. It reports
+// whether the build number changed.
+func (p *Pipeline) prepareVersion(ctx context.Context, projectDir string, preflight *asc.PreflightResult) (bool, error) {
+	marketing, build, err := projectconfig.ProjectVersion(projectDir)
+	if err != nil {
+		return false, err
+	}
+	preflight.MarketingVersion, preflight.BuildNumber = marketing, build
+	for _, v := range preflight.AllVersions {
+		if slices.Contains(closedVersionStates, v.State) && versioning.Compare(marketing, v.VersionString) <= 0 {
+			preflight.VersionClosed = true
+		}
+	}
+	if preflight.AppID == "" {
+		return false, nil
+	}
+
+	client, err := p.ascClient()
+	if err != nil {
+		return false, err
+	}
+	builds, err := client.ListBuilds(ctx, preflight.AppID, uploadedBuildsChecked)
+	if err != nil {
+		return false, err
+	}
+	var uploaded []string
+	for _, b := range builds {
+		uploaded = append(uploaded, b.Version)
+	}
+	next := versioning.NextBuild(build, uploaded)
+	if next == build {
+		return false, nil
+	}
+	log.Printf("[asc] build number %d already uploaded, using %d", build, next)
+	if err := projectconfig.SetProjectVersion(projectDir, marketing, next); err != nil {
+		return false, fmt.Errorf("set build number %d: %w", next, err)
+	}
+	preflight.BuildNumber = next
+	return true, nil
+}
+
+// recordRelease adds the build to the project's release history once App Store
+// Connect has it, with the commits since the previous release as its changelog.
+// Sessions that did not upload the project's build record nothing.
+func (p *Pipeline) recordRelease(ctx context.Context, projectDir string, preflight *asc.PreflightResult) {
+	if preflight.AppID == "" || preflight.BuildNumber == 0 {
+		return
+	}
+	client, err := p.ascClient()
+	if err != nil {
+		return
+	}
+	builds, err := client.ListBuilds(ctx, preflight.AppID, 10)
+	if err != nil {
+		log.Printf("[asc] release check failed: %v", err)
+		return
+	}
+	buildNumber := strconv.Itoa(preflight.BuildNumber)
+	if !slices.ContainsFunc(builds, func(b asc.Build) bool { return b.Version == buildNumber }) {
+		return
+	}
+
+	store := storage.NewProjectStore(filepath.Join(projectDir, ".nanowave"))
+	project, err := store.Load()
+	if err != nil || project == nil {
+		return
+	}
+	for _, r := range project.Releases {
+		if r.Version == preflight.MarketingVersion && r.BuildNumber == preflight.BuildNumber {
+			return
+		}
+	}
+
+	release := storage.Release{
+		Version:     preflight.MarketingVersion,
+		BuildNumber: preflight.BuildNumber,
+		AppID:       preflight.AppID,
+		CreatedAt:   time.Now(),
+	}
+	var since string
+	if last := project.LastRelease(); last != nil {
+		since = last.Commit
+	}
+	if release.Commit, release.Changelog, err = versioning.Changelog(ctx, projectDir, since); err != nil {
+		log.Printf("[asc] changelog: %v", err)
+	}
+	if err := store.AddRelease(ctx, release); err != nil {
+		log.Printf("[asc] record release: %v", err)
+		return
+	}
+	terminal.Success(fmt.Sprintf("Recorded release %s (%d) with %d change(s)", release.Version, release.BuildNumber, len(release.Changelog)))
+}
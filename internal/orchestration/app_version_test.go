@@ -0,0 +1,103 @@
+package orchestration
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/moasq/nanowave/internal/asc"
+	"github.com/moasq/nanowave/internal/asc/asctest"
+	"github.com/moasq/nanowave/internal/storage"
+)
+
+func TestPrepareVersionDetectsReleasedVersion(t *testing.T) {
+	srv := asctest.NewServer(t)
+	app := asctest.Ref{Type: "apps", ID: "app1"}
+	srv.Add(asctest.Resource{Type: "builds", ID: "b3", Attributes: map[string]any{"version": "3", "uploadedDate": "2026-05-01T09:00:00Z"},
+		Relationships: map[string]asctest.Ref{"app": app}})
+
+	projectDir := t.TempDir()
+	os.WriteFile(filepath.Join(projectDir, "project_config.json"), []byte(`{"app_name": "Habits", "marketing_version": "1.0", "build_number": 4}`), 0o644)
+
+	p := &Pipeline{ascAPI: srv.APIClient(t)}
+	preflight := &asc.PreflightResult{AppID: "app1", AllVersions: []asc.VersionInfo{{ID: "v1", VersionString: "1.0", State: asc.VersionReadyForSale}}}
+	bumped, err := p.prepareVersion(context.Background(), projectDir, preflight)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bumped || preflight.MarketingVersion != "1.0" || preflight.BuildNumber != 4 {
+		t.Errorf("prepareVersion() = %v, %s (%d); want 1.0 (4) unchanged", bumped, preflight.MarketingVersion, preflight.BuildNumber)
+	}
+	if !preflight.VersionClosed {
+		t.Error("released version 1.0 not reported as closed")
+	}
+}
+
+func TestPrepareVersionDetectsVersionBelowRelease(t *testing.T) {
+	projectDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(projectDir, "project_config.json"), []byte(`{"app_name": "Habits", "marketing_version": "1.2", "build_number": 4}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	p := &Pipeline{}
+	preflight := &asc.PreflightResult{AllVersions: []asc.VersionInfo{
+		{ID: "v2", VersionString: "1.10", State: asc.VersionReadyForSale},
+		{ID: "v3", VersionString: "2.0", State: asc.VersionPrepareForSubmission},
+	}}
+	if _, err := p.prepareVersion(context.Background(), projectDir, preflight); err != nil {
+		t.Fatal(err)
+	}
+	if !preflight.VersionClosed {
+		t.Error("version 1.2, below the released 1.10, not reported as closed")
+	}
+}
+
+func TestRecordReleaseOnceUploaded(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	projectDir := t.TempDir()
+	git := func(args ...string) {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = projectDir
+		cmd.Env = append(os.Environ(), "GIT_AUTHOR_NAME=t", "GIT_AUTHOR_EMAIL=t@example.com", "GIT_COMMITTER_NAME=t", "GIT_COMMITTER_EMAIL=t@example.com")
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	git("init", "-q")
+	git("commit", "-q", "--allow-empty", "-m", "Initial build: Habits")
+	git("commit", "-q", "--allow-empty", "-m", "Add streak counter")
+
+	store := storage.NewProjectStore(filepath.Join(projectDir, ".nanowave"))
+	if _, err := store.Create("Habits"); err != nil {
+		t.Fatal(err)
+	}
+
+	srv := asctest.NewServer(t)
+	app := asctest.Ref{Type: "apps", ID: "app1"}
+	p := &Pipeline{ascAPI: srv.APIClient(t)}
+	preflight := &asc.PreflightResult{AppID: "app1", MarketingVersion: "1.1", BuildNumber: 5}
+
+	// The session did not upload build 5: nothing is recorded.
+	p.recordRelease(context.Background(), projectDir, preflight)
+	if project, _ := store.Load(); len(project.Releases) != 0 {
+		t.Fatalf("releases = %+v before the upload", project.Releases)
+	}
+
+	srv.Add(asctest.Resource{Type: "builds", ID: "b5", Attributes: map[string]any{"version": "5", "uploadedDate": "2026-05-01T09:00:00Z"},
+		Relationships: map[string]asctest.Ref{"app": app}})
+	p.recordRelease(context.Background(), projectDir, preflight)
+	p.recordRelease(context.Background(), projectDir, preflight)
+
+	project, _ := store.Load()
+	if len(project.Releases) != 1 {
+		t.Fatalf("releases = %+v, want one", project.Releases)
+	}
+	r := project.Releases[0]
+	if r.Version != "1.1" || r.BuildNumber != 5 || r.Commit == "" || len(r.Changelog) != 2 || r.Changelog[0] != "Add streak counter" {
+		t.Errorf("release = %+v", r)
+	}
+}
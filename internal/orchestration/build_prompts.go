@@ -4,9 +4,11 @@ import (
 	"context"
 	"fmt"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/moasq/nanowave/internal/integrations"
+	"github.com/moasq/nanowave/internal/packageregistry"
 	"github.com/moasq/nanowave/internal/terminal"
 )
 
@@ -28,6 +30,9 @@ func appendBuildPlanFileEntry(b *strings.Builder, f FilePlan) {
 	if f.Platform != "" {
 		platformTag = fmt.Sprintf(" [%s]", f.Platform)
 	}
+	if f.Package != "" {
+		platformTag += fmt.Sprintf(" [package %s]", f.Package)
+	}
 	fmt.Fprintf(b, "- %s (%s)%s: %s\n  Components: %s\n  Data access: %s\n",
 		f.Path, f.TypeName, platformTag, f.Purpose, f.Components, f.DataAccess)
 }
@@ -35,6 +40,10 @@ func appendBuildPlanFileEntry(b *strings.Builder, f FilePlan) {
 // buildPrompts constructs the system and user prompts for the build phase.
 func (p *Pipeline) buildPrompts(_ string, appName string, _ string, analysis *AnalysisResult, plan *PlannerResult, backendProvisioned bool, ac ActionContext) (string, string, error) {
 	destination := canonicalBuildDestinationForShape(plan.GetPlatform(), plan.GetWatchProjectShape())
+	packages, err := p.packageRegistry()
+	if err != nil {
+		return "", "", err
+	}
 
 	// Load all relevant coder skills — Claude picks the right approach
 	skillNames := []string{"builder", "editor", "fixer"}
@@ -110,13 +119,36 @@ func (p *Pipeline) buildPrompts(_ string, appName string, _ string, analysis *An
 		}
 	}
 
+	if len(plan.LocalPackages) > 0 {
+		appendPrompt.WriteString("\n### Local Packages\n")
+		appendPrompt.WriteString("Files tagged [package X] go in X's source root; their paths are relative to it. Only `public` declarations are visible to the app and to dependent packages.\n")
+		for _, pkg := range plan.LocalPackages {
+			appendPrompt.WriteString(fmt.Sprintf("- %s: %s\n  Source path: %s/\n", pkg.Name, pkg.Purpose, localPackageSourceDir(pkg.Name)))
+			if len(pkg.DependsOn) > 0 {
+				appendPrompt.WriteString(fmt.Sprintf("  Depends on: %s\n", strings.Join(pkg.DependsOn, ", ")))
+			}
+		}
+	}
+
+	if testFiles := plan.TestFiles(appName); len(testFiles) > 0 {
+		appendPrompt.WriteString("\n### Tests\n")
+		appendPrompt.WriteString("Paths are relative to the project root. Each folder is its own test target, already in the app scheme's test action.\n")
+		for _, f := range testFiles {
+			appendBuildPlanFileEntry(&appendPrompt, f)
+		}
+	}
+
 	if len(plan.Localizations) > 0 {
 		appendPrompt.WriteString(fmt.Sprintf("\n### Localizations: %s\n", strings.Join(plan.Localizations, ", ")))
 	}
 
 	// SPM package instructions
 	appendPrompt.WriteString("\n### SPM Packages\n")
-	appendBuildSPMSection(&appendPrompt, plan.Packages, appName)
+	appendBuildSPMSection(&appendPrompt, plan.Packages, appName, packages)
+
+	if cp := capturePlanFor(appName, analysis, plan); cp != nil {
+		appendCapturePlanSection(&appendPrompt, cp)
+	}
 
 	appendPrompt.WriteString("\n</build-plan>\n")
 
@@ -155,6 +187,7 @@ func (p *Pipeline) buildPrompts(_ string, appName string, _ string, analysis *An
 			Store:              p.manager.Store(),
 			BackendProvisioned: backendProvisioned,
 			MonetizationPlan:   monetizationPlanToRef(plan.MonetizationPlan),
+			BundleID:           fmt.Sprintf("%s.%s", bundleIDPrefix(), strings.ToLower(appName)),
 		}
 		contributions, err := p.manager.PromptContributions(promptCtx, promptReq, p.activeProviders)
 		if err != nil {
@@ -260,9 +293,30 @@ IMPORTANT:
 			backendFirstBlock, appName, appName, appName, destination, appName)
 	}
 
+	userMsg += testInstructions(appName, plan, destination)
+
 	return appendPrompt.String(), userMsg, nil
 }
 
+// testInstructions returns the build-phase instructions for the planned test
+// files, or "" when the plan has none.
+func testInstructions(appName string, plan *PlannerResult, destination string) string {
+	var dirs []string
+	for _, name := range testTargetNames(appName, plan) {
+		dirs = append(dirs, name+"/")
+	}
+	if len(dirs) == 0 {
+		return ""
+	}
+	return fmt.Sprintf(`
+
+TESTS:
+1. Write the planned test files (### Tests in the plan) under %s, after the code they cover.
+2. Once the app builds, compile the tests with: xcodebuild -project %s.xcodeproj -scheme %s -destination '%s' -quiet build-for-testing
+3. Fix test compile errors against the real app types. Never delete a planned test to make the build pass.`,
+		strings.Join(dirs, " and "), appName, appName, destination)
+}
+
 // completionPrompts builds targeted prompts for unresolved planned files.
 func (p *Pipeline) completionPrompts(appName string, projectDir string, plan *PlannerResult, report *FileCompletionReport) (string, string, error) {
 	destination := canonicalBuildDestinationForShape(plan.GetPlatform(), plan.GetWatchProjectShape())
@@ -276,8 +330,9 @@ func (p *Pipeline) completionPrompts(appName string, projectDir string, plan *Pl
 	appendPrompt.WriteString("Only complete the unresolved planned files listed in the user message.\n")
 	appendPrompt.WriteString("Do not mark work done until every listed file exists, contains its expected type, and the build succeeds.\n")
 
-	plannedByPath := make(map[string]FilePlan, len(plan.Files))
-	for _, f := range plan.Files {
+	testFiles := plan.TestFiles(appName)
+	plannedByPath := make(map[string]FilePlan, len(plan.Files)+len(testFiles))
+	for _, f := range slices.Concat(plan.Files, testFiles) {
 		plannedByPath[f.Path] = f
 	}
 
@@ -295,6 +350,9 @@ func (p *Pipeline) completionPrompts(appName string, projectDir string, plan *Pl
 		if filePlan.Platform != "" {
 			fileList.WriteString(fmt.Sprintf("  Platform: %s\n", filePlan.Platform))
 		}
+		if filePlan.Package != "" {
+			fileList.WriteString(fmt.Sprintf("  Local package: %s (declarations used outside it must be public)\n", filePlan.Package))
+		}
 		if filePlan.TypeName != "" {
 			fileList.WriteString(fmt.Sprintf("  Expected type: %s\n", filePlan.TypeName))
 		}
@@ -347,12 +405,12 @@ Required process:
 // appendBuildSPMSection writes the SPM package instructions into the build prompt.
 // It resolves planned packages against the curated registry for exact details,
 // and falls back to internet-search instructions for unrecognized packages.
-func appendBuildSPMSection(b *strings.Builder, packages []PackagePlan, appName string) {
-	var resolved []*CuratedPackage
+func appendBuildSPMSection(b *strings.Builder, packages []PackagePlan, appName string, registry *packageregistry.Registry) {
+	var resolved []*packageregistry.Package
 	var unresolved []PackagePlan
 
 	for _, pkg := range packages {
-		if curated := LookupPackageByName(pkg.Name); curated != nil {
+		if curated := registry.LookupByName(pkg.Name); curated != nil {
 			resolved = append(resolved, curated)
 		} else {
 			unresolved = append(unresolved, pkg)
@@ -366,7 +424,10 @@ func appendBuildSPMSection(b *strings.Builder, packages []PackagePlan, appName s
 			b.WriteString(fmt.Sprintf("**%s** — %s\n", pkg.Name, pkg.Description))
 			b.WriteString(fmt.Sprintf("- Repository: %s\n", pkg.RepoURL))
 			b.WriteString(fmt.Sprintf("- XcodeGen package key: `%s`\n", pkg.RepoName))
-			b.WriteString(fmt.Sprintf("- Minimum version: `\"%s\"`\n", pkg.MinVersion))
+			b.WriteString(fmt.Sprintf("- Version: %s\n", pkg.VersionSummary()))
+			if len(pkg.Platforms) > 0 {
+				b.WriteString(fmt.Sprintf("- Platforms: %s only\n", strings.Join(pkg.Platforms, ", ")))
+			}
 			if len(pkg.Products) == 1 {
 				b.WriteString(fmt.Sprintf("- Import: `import %s`\n", pkg.Products[0]))
 			} else {
@@ -381,7 +442,9 @@ func appendBuildSPMSection(b *strings.Builder, packages []PackagePlan, appName s
 			b.WriteString("packages:\n")
 			b.WriteString(fmt.Sprintf("  %s:\n", pkg.RepoName))
 			b.WriteString(fmt.Sprintf("    url: %s\n", pkg.RepoURL))
-			b.WriteString(fmt.Sprintf("    from: \"%s\"\n", pkg.MinVersion))
+			for _, kv := range pkg.VersionRequirement() {
+				b.WriteString(fmt.Sprintf("    %s: \"%s\"\n", kv[0], kv[1]))
+			}
 			b.WriteString("targets:\n")
 			b.WriteString(fmt.Sprintf("  %s:\n", appName))
 			b.WriteString("    dependencies:\n")
@@ -427,7 +490,7 @@ The packages: section goes at the top level. Each key is the repository name (la
 
 Format rules:
 - The packages: key is the repository name (last URL path component), not the product name.
-- Use from: for minimum version (semver). Do not use minVersion:, version:, or exactVersion:.
+- Use from: for minimum version (semver), unless a package above shows an exactVersion: or minVersion:/maxVersion: pin — keep that pin as shown.
 - Every dependency must include package:. Add product: when the product name differs from the package key, or use products: for multiple products.
 - Version strings must be quoted (from: "4.5.0").
 
@@ -588,7 +588,7 @@ func TestWriteCoreRulesPhotoGalleryEnrichment(t *testing.T) {
 		{Name: "WaterfallGrid", Reason: "Pinterest-style staggered grid layout"},
 	}
 
-	if err := writeCoreRules(projectDir, PlatformIOS, packages); err != nil {
+	if err := writeCoreRules(projectDir, PlatformIOS, packages, defaultPackages(t)); err != nil {
 		t.Fatalf("writeCoreRules() error: %v", err)
 	}
 
@@ -618,7 +618,7 @@ func TestWriteCoreRulesMixedResolvedAndUnresolved(t *testing.T) {
 		{Name: "UnknownLib", Reason: "Some custom functionality"},
 	}
 
-	if err := writeCoreRules(projectDir, PlatformIOS, packages); err != nil {
+	if err := writeCoreRules(projectDir, PlatformIOS, packages, defaultPackages(t)); err != nil {
 		t.Fatalf("writeCoreRules() error: %v", err)
 	}
 
@@ -678,3 +678,95 @@ func TestAppendBuildPlanFileEntry(t *testing.T) {
 		})
 	}
 }
+
+func TestAppendBuildSPMSectionResolved(t *testing.T) {
+	packages := []PackagePlan{
+		{Name: "Kingfisher", Reason: "disk-cached image loading"},
+	}
+	var b strings.Builder
+	appendBuildSPMSection(&b, packages, "TestApp", defaultPackages(t))
+	output := b.String()
+
+	// Should contain registry details
+	if !strings.Contains(output, "https://github.com/onevcat/Kingfisher") {
+		t.Error("resolved package should include repo URL")
+	}
+	if !strings.Contains(output, "import Kingfisher") {
+		t.Error("resolved package should include import statement")
+	}
+	if !strings.Contains(output, "from:") {
+		t.Error("resolved package should include version")
+	}
+	// Should NOT contain "search the internet"
+	if strings.Contains(output, "Search the internet") {
+		t.Error("resolved package should not have search instructions")
+	}
+}
+
+func TestAppendBuildSPMSectionUnresolved(t *testing.T) {
+	packages := []PackagePlan{
+		{Name: "SomeUnknownLib", Reason: "does something special"},
+	}
+	var b strings.Builder
+	appendBuildSPMSection(&b, packages, "TestApp", defaultPackages(t))
+	output := b.String()
+
+	if !strings.Contains(output, "SomeUnknownLib") {
+		t.Error("unresolved package should include name")
+	}
+	if !strings.Contains(output, "WebSearch") {
+		t.Error("unresolved package should have search instructions")
+	}
+}
+
+func TestAppendBuildSPMSectionMixed(t *testing.T) {
+	packages := []PackagePlan{
+		{Name: "Lottie", Reason: "After Effects animations"},
+		{Name: "MyCustomLib", Reason: "custom functionality"},
+	}
+	var b strings.Builder
+	appendBuildSPMSection(&b, packages, "TestApp", defaultPackages(t))
+	output := b.String()
+
+	// Should have both resolved and unresolved sections
+	if !strings.Contains(output, "lottie-spm") {
+		t.Error("resolved Lottie should include repo name lottie-spm")
+	}
+	if !strings.Contains(output, "MyCustomLib") {
+		t.Error("unresolved package should be listed")
+	}
+}
+
+func TestAppendBuildSPMSectionMultiProduct(t *testing.T) {
+	packages := []PackagePlan{
+		{Name: "Nuke", Reason: "image pipeline"},
+	}
+	var b strings.Builder
+	appendBuildSPMSection(&b, packages, "TestApp", defaultPackages(t))
+	output := b.String()
+
+	if !strings.Contains(output, "import Nuke") {
+		t.Error("multi-product package should list Nuke import")
+	}
+	if !strings.Contains(output, "import NukeUI") {
+		t.Error("multi-product package should list NukeUI import")
+	}
+	if !strings.Contains(output, "products:") {
+		t.Error("multi-product package should use products: in YAML")
+	}
+}
+
+func TestAppendBuildSPMSectionEmpty(t *testing.T) {
+	var b strings.Builder
+	appendBuildSPMSection(&b, nil, "TestApp", defaultPackages(t))
+	output := b.String()
+
+	// Should still have the format reference
+	if !strings.Contains(output, "XcodeGen project.yml format") {
+		t.Error("empty packages should still include format reference")
+	}
+	// Should not have approved or unresolved sections
+	if strings.Contains(output, "approved for this project") {
+		t.Error("empty packages should not have approved section")
+	}
+}
@@ -0,0 +1,100 @@
+package orchestration
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/moasq/nanowave/internal/screenshots"
+)
+
+// maxCaptureScreens is the most screenshots App Store Connect takes per device.
+const maxCaptureScreens = 10
+
+// capturePlanFor derives the screens automatic screenshot capture visits from
+// the analyzed features: the launch screen, then one deep link per feature.
+// It returns nil for apps without an iOS target, which capture can't drive.
+func capturePlanFor(appName string, analysis *AnalysisResult, plan *PlannerResult) *screenshots.CapturePlan {
+	if analysis == nil || !slices.Contains(plan.GetPlatforms(), PlatformIOS) {
+		return nil
+	}
+	scheme := urlSchemeFor(appName)
+	cp := &screenshots.CapturePlan{
+		URLScheme:       scheme,
+		Driver:          screenshots.DriverDeepLink,
+		LaunchArguments: []string{"-" + screenshots.SampleDataArgument, "YES"},
+		StatusBar:       screenshots.DefaultStatusBar(),
+		Screens:         []screenshots.CaptureScreen{{Name: "home"}},
+	}
+	seen := map[string]bool{"home": true}
+	for _, f := range analysis.Features {
+		if len(cp.Screens) == maxCaptureScreens {
+			break
+		}
+		name := screenSlug(f.Name)
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		cp.Screens = append(cp.Screens, screenshots.CaptureScreen{
+			Name:     name,
+			Feature:  f.Name,
+			DeepLink: scheme + "://" + name,
+		})
+	}
+	return cp
+}
+
+// writeCapturePlan stores the derived capture plan with the project. An
+// existing plan is kept, since users edit it to pick their screens.
+func writeCapturePlan(projectDir, appName string, analysis *AnalysisResult, plan *PlannerResult) error {
+	cp := capturePlanFor(appName, analysis, plan)
+	if cp == nil {
+		return nil
+	}
+	if existing, err := screenshots.LoadCapturePlan(projectDir); existing != nil || err != nil {
+		return err
+	}
+	return screenshots.SaveCapturePlan(projectDir, cp)
+}
+
+// urlSchemeFor returns the deep link scheme of an app: its name in lowercase
+// letters and digits.
+func urlSchemeFor(appName string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(appName) {
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			b.WriteRune(r)
+		}
+	}
+	scheme := b.String()
+	if scheme == "" || !unicode.IsLetter(rune(scheme[0])) {
+		scheme = "app" + scheme
+	}
+	return scheme
+}
+
+// screenSlug turns a feature name into a lowercase, hyphenated screen name.
+func screenSlug(name string) string {
+	words := strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return r >= unicode.MaxASCII || !(unicode.IsLetter(r) || unicode.IsDigit(r))
+	})
+	return strings.Join(words, "-")
+}
+
+// appendCapturePlanSection tells the builder how screenshot capture drives the
+// app, so the routes and sample data exist when it runs.
+func appendCapturePlanSection(b *strings.Builder, cp *screenshots.CapturePlan) {
+	b.WriteString("\n### Screenshot Capture\n")
+	fmt.Fprintf(b, "App Store screenshots are captured by opening deep links on the simulator (%s).\n", screenshots.CapturePlanFile)
+	fmt.Fprintf(b, "- Register the URL scheme with add_url_scheme(scheme: %q) and handle links with .onOpenURL on the root view.\n", cp.URLScheme)
+	b.WriteString("- Route each link to its screen, selecting the tab or pushing the view, with realistic content showing:\n")
+	for _, s := range cp.Screens {
+		if s.DeepLink != "" {
+			fmt.Fprintf(b, "  - %s → %s\n", s.DeepLink, s.Feature)
+		}
+	}
+	fmt.Fprintf(b, "- When launched with -%s YES (UserDefaults.standard.bool(forKey: %q)), seed in-memory sample data and skip onboarding, sign-in prompts and paywalls.\n",
+		screenshots.SampleDataArgument, screenshots.SampleDataArgument)
+}
@@ -0,0 +1,55 @@
+package orchestration
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/moasq/nanowave/internal/screenshots"
+)
+
+func TestCapturePlanFor(t *testing.T) {
+	analysis := &AnalysisResult{Features: []Feature{
+		{Name: "Daily Journal"},
+		{Name: "Mood Trends & Charts"},
+		{Name: "daily journal"},
+		{Name: "Home"},
+	}}
+	cp := capturePlanFor("2Do", analysis, &PlannerResult{})
+	if cp == nil {
+		t.Fatal("capturePlanFor returned nil for an iOS app")
+	}
+	if cp.URLScheme != "app2do" {
+		t.Errorf("URLScheme = %q, want app2do", cp.URLScheme)
+	}
+	var links []string
+	for _, s := range cp.Screens {
+		links = append(links, s.Name+"="+s.DeepLink)
+	}
+	want := "home= daily-journal=app2do://daily-journal mood-trends-charts=app2do://mood-trends-charts"
+	if got := strings.Join(links, " "); got != want {
+		t.Errorf("screens = %s, want %s", got, want)
+	}
+
+	if cp := capturePlanFor("Flix", analysis, &PlannerResult{Platform: PlatformTvOS}); cp != nil {
+		t.Errorf("capturePlanFor(tvOS) = %+v, want nil", cp)
+	}
+}
+
+func TestWriteCapturePlanKeepsExisting(t *testing.T) {
+	dir := t.TempDir()
+	edited := &screenshots.CapturePlan{URLScheme: "notes", Screens: []screenshots.CaptureScreen{{Name: "editor"}}}
+	if err := screenshots.SaveCapturePlan(dir, edited); err != nil {
+		t.Fatal(err)
+	}
+	analysis := &AnalysisResult{Features: []Feature{{Name: "Search"}}}
+	if err := writeCapturePlan(dir, "Notes", analysis, &PlannerResult{}); err != nil {
+		t.Fatalf("writeCapturePlan: %v", err)
+	}
+	got, err := screenshots.LoadCapturePlan(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Screens) != 1 || got.Screens[0].Name != "editor" {
+		t.Errorf("writeCapturePlan replaced an edited plan: %+v", got.Screens)
+	}
+}
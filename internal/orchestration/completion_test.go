@@ -202,3 +202,83 @@ func TestShouldRetryCompletion(t *testing.T) {
 		}
 	})
 }
+
+func TestVerifyPlannedFilesIncludesTests(t *testing.T) {
+	projectDir := t.TempDir()
+	plan := &PlannerResult{
+		Files: []FilePlan{{Path: "Models/Meal.swift", TypeName: "Meal"}},
+		Tests: &TestPlan{
+			Unit: []FilePlan{{Path: "MealViewModelTests.swift", TypeName: "MealViewModelTests"}},
+			UI:   []FilePlan{{Path: "MyAppUITests/MyAppUITests.swift", TypeName: "MyAppUITests"}},
+		},
+	}
+	write := func(rel, content string) {
+		path := filepath.Join(projectDir, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("MyApp/Models/Meal.swift", "struct Meal {}\n")
+	write("MyAppTests/MealViewModelTests.swift", "import Testing\n\nstruct MealViewModelTests {}\n")
+
+	report, err := verifyPlannedFiles(projectDir, "MyApp", plan)
+	if err != nil {
+		t.Fatalf("verifyPlannedFiles() returned error: %v", err)
+	}
+	if report.TotalPlanned != 3 || report.ValidCount != 2 || report.Complete {
+		t.Fatalf("unexpected totals: %+v", report)
+	}
+	if len(report.Missing) != 1 || report.Missing[0].PlannedPath != "MyAppUITests/MyAppUITests.swift" {
+		t.Fatalf("expected the UI test file to be missing, got %+v", report.Missing)
+	}
+
+	plan.Platform = PlatformWatchOS
+	report, err = verifyPlannedFiles(projectDir, "MyApp", plan)
+	if err != nil {
+		t.Fatalf("verifyPlannedFiles() returned error: %v", err)
+	}
+	if report.TotalPlanned != 2 || !report.Complete {
+		t.Fatalf("watchOS plans have no UI tests to verify, got %+v", report)
+	}
+}
+
+func TestVerifyPlannedFilesResolvesLocalPackages(t *testing.T) {
+	projectDir := t.TempDir()
+	plan := &PlannerResult{
+		LocalPackages: []LocalPackagePlan{{Name: "Models"}},
+		Files: []FilePlan{
+			{Path: "Habit.swift", TypeName: "Habit", Package: "Models"},
+			{Path: "Packages/Models/Sources/Models/Streak.swift", TypeName: "Streak", Package: "Models"},
+			{Path: "App/HabitsApp.swift", TypeName: "HabitsApp"},
+		},
+	}
+	for rel, content := range map[string]string{
+		"Packages/Models/Sources/Models/Habit.swift":  "public struct Habit {}\n",
+		"Packages/Models/Sources/Models/Streak.swift": "public struct Streak {}\n",
+		"Habits/App/HabitsApp.swift":                  "@main struct HabitsApp {}\n",
+	} {
+		path := filepath.Join(projectDir, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	report, err := verifyPlannedFiles(projectDir, "Habits", plan)
+	if err != nil {
+		t.Fatalf("verifyPlannedFiles() returned error: %v", err)
+	}
+	if !report.Complete {
+		t.Fatalf("expected package files to resolve under their source root, got %+v", report)
+	}
+
+	missing := resolvePlannedFilePathWithPlatform(projectDir, "Habits", FilePlan{Path: "Features/Streaks.swift", Package: "Models"}, false)
+	if want := filepath.Join(projectDir, "Packages", "Models", "Sources", "Models", "Features", "Streaks.swift"); missing != want {
+		t.Errorf("missing package file resolved to %s, want %s", missing, want)
+	}
+}
@@ -117,6 +117,9 @@ func parsePlan(result string) (*PlannerResult, error) {
 	if len(plan.Files) == 0 {
 		return nil, fmt.Errorf("plan has no files")
 	}
+	if err := orderLocalPackages(plan); err != nil {
+		return nil, err
+	}
 
 	// Validate Platforms entries
 	if len(plan.Platforms) > 0 {
@@ -235,6 +238,11 @@ func normalizePlannerResult(plan *PlannerResult) {
 		plan.DeviceFamily = ""
 	}
 
+	// Plans with tests load the testing skill.
+	if (plan.HasUnitTests() || plan.HasUITests()) && !plan.HasRuleKey("testing") {
+		plan.RuleKeys = append(plan.RuleKeys, "testing")
+	}
+
 	// Normalize packages: ensure non-nil, drop empty names, deduplicate.
 	if plan.Packages == nil {
 		plan.Packages = []PackagePlan{}
@@ -252,6 +260,8 @@ func normalizePlannerResult(plan *PlannerResult) {
 		}
 		plan.Packages = filtered
 	}
+
+	normalizeLocalPackages(plan)
 }
 
 // normalizePlannerPlatform gracefully handles unrecognized platform values
@@ -2,6 +2,9 @@ package orchestration
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"image/color"
 	"log"
 	"os"
 	"path/filepath"
@@ -9,6 +12,7 @@ import (
 
 	"github.com/moasq/nanowave/internal/icons"
 	"github.com/moasq/nanowave/internal/terminal"
+	"github.com/moasq/nanowave/internal/xcodegen"
 )
 
 // checkAppIcon checks whether the project already has a valid app icon
@@ -28,39 +32,60 @@ func (p *Pipeline) checkAppIcon(projectDir, platform string) (found bool, sizeCo
 	}
 
 	log.Printf("[asc][icon] icon already exists in %s", appIconDir)
-	if src := icons.FindSourceIcon(appIconDir); src != "" {
+	if icons.IsLayered(appIconDir) {
+		log.Printf("[asc][icon] layered icon already generated in %s", appIconDir)
+	} else if src := icons.FindSourceIcon(appIconDir); src != "" {
 		log.Printf("[asc][icon] source icon for resizing: %s", src)
-		if err := icons.UpdateContentsJSON(appIconDir, filepath.Base(src), platform); err != nil {
+		for _, problem := range icons.ValidateAppStoreIcon(src) {
+			terminal.Warning("App icon: " + problem.Error())
+		}
+		if err := icons.UpdateContentsJSON(appIconDir, filepath.Base(src), platform, iconDeploymentTarget(projectDir, platform)); err != nil {
 			log.Printf("[asc][icon] FAILED to generate icon sizes: %v", err)
 		}
+		if layered, err := icons.FindAppIconDir(projectDir); err == nil {
+			appIconDir = layered
+		}
 	} else {
 		log.Printf("[asc][icon] WARNING: no source icon found for resizing in %s", appIconDir)
 	}
 
-	entries, _ := os.ReadDir(appIconDir)
 	count := 0
-	for _, e := range entries {
-		if strings.HasSuffix(strings.ToLower(e.Name()), ".png") {
+	_ = filepath.WalkDir(appIconDir, func(path string, d os.DirEntry, err error) error {
+		if err == nil && !d.IsDir() && strings.HasSuffix(strings.ToLower(d.Name()), ".png") {
 			count++
 		}
-	}
+		return nil
+	})
 	log.Printf("[asc][icon] icon check complete: %d PNG files", count)
 	return true, count
 }
 
-// offerIconUpload opens a local browser page where the user can drag-and-drop
-// an icon. Called only when checkAppIcon returns found=false.
+// iconDeploymentTarget returns the deployment target project.yml declares for
+// the platform, or "" when it declares none.
+func iconDeploymentTarget(projectDir, platform string) string {
+	spec, err := xcodegen.Load(filepath.Join(projectDir, "project.yml"))
+	if err != nil {
+		return ""
+	}
+	target, _ := spec.Options.DeploymentTarget.Get(PlatformDeploymentTargetKey(platform))
+	return target
+}
+
+// offerIconUpload lets the user generate an icon from the design palette or
+// opens a local browser page where they can drag-and-drop one.
+// Called only when checkAppIcon returns found=false.
 // Returns true if icon was set.
 func (p *Pipeline) offerIconUpload(ctx context.Context, projectDir, platform string) bool {
 	log.Printf("[asc] no icon found, offering upload UI")
 	terminal.Warning("No app icon found.")
 
 	picked := terminal.Pick("App icon", []terminal.PickerOption{
+		{Label: "Generate icon", Desc: "Draw an icon from the app name and design palette"},
 		{Label: "Upload icon", Desc: "Open browser to drag-and-drop your icon"},
 		{Label: "Skip", Desc: "Continue without an icon"},
 	}, "")
 
-	if picked != "Upload icon" {
+	if picked != "Generate icon" && picked != "Upload icon" {
 		return false
 	}
 
@@ -71,6 +96,73 @@ func (p *Pipeline) offerIconUpload(ctx context.Context, projectDir, platform str
 		return false
 	}
 
+	if picked == "Generate icon" {
+		return generateAppIcon(projectDir, appIconDir, platform)
+	}
+
 	// Start local server for icon upload
-	return icons.RunUploadServer(ctx, appIconDir, platform)
+	return icons.RunUploadServer(ctx, appIconDir, platform, iconDeploymentTarget(projectDir, platform))
+}
+
+// Palette colors used for generated icons when the project has no saved design
+// or its colors don't parse.
+const (
+	defaultIconPrimary = "#0A84FF"
+	defaultIconAccent  = "#5E5CE6"
+)
+
+// generateAppIcon renders an icon with a glyph the user picks and generates
+// every platform size from it. Returns true if the icon was set.
+func generateAppIcon(projectDir, appIconDir, platform string) bool {
+	options := []terminal.PickerOption{{Label: "Monogram", Desc: "First letter of the app name"}}
+	for _, g := range icons.Glyphs() {
+		options = append(options, terminal.PickerOption{Label: g.Name, Desc: "Like the " + g.Symbol + " symbol"})
+	}
+	glyph := terminal.Pick("Icon glyph", options, "Monogram")
+	if glyph == "" {
+		return false
+	}
+
+	style := iconStyle(projectDir)
+	if glyph != "Monogram" {
+		style.Glyph = glyph
+	}
+	if err := icons.GenerateIcon(appIconDir, platform, iconDeploymentTarget(projectDir, platform), style); err != nil {
+		log.Printf("[asc][icon] generation failed: %v", err)
+		terminal.Error(fmt.Sprintf("Could not generate app icon: %v", err))
+		return false
+	}
+	terminal.Success("App icon generated")
+	return true
+}
+
+// iconStyle builds a generated icon's style from the app name in
+// project_config.json and the primary and accent colors of the saved design.
+func iconStyle(projectDir string) icons.IconStyle {
+	style := icons.IconStyle{AppName: filepath.Base(projectDir)}
+	if data, err := os.ReadFile(filepath.Join(projectDir, "project_config.json")); err == nil {
+		var cfg struct {
+			AppName string `json:"app_name"`
+		}
+		if json.Unmarshal(data, &cfg) == nil && cfg.AppName != "" {
+			style.AppName = cfg.AppName
+		}
+	}
+
+	primary, accent := defaultIconPrimary, defaultIconAccent
+	if design := readDesignFile(projectDir); design != nil {
+		primary, accent = design.Palette.Primary, design.Palette.Accent
+	}
+	style.Primary = iconColor(primary, defaultIconPrimary)
+	style.Accent = iconColor(accent, defaultIconAccent)
+	return style
+}
+
+// iconColor parses a hex color, falling back to fallback when it is invalid.
+func iconColor(hex, fallback string) color.RGBA {
+	r, g, b, ok := parseHexColor(hex)
+	if !ok {
+		r, g, b, _ = parseHexColor(fallback)
+	}
+	return color.RGBA{R: r, G: g, B: b, A: 255}
 }
@@ -0,0 +1,190 @@
+package orchestration
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/moasq/nanowave/internal/terminal"
+	"github.com/moasq/nanowave/internal/xcodegen"
+)
+
+// localPackagesDir holds the planner's local Swift packages, relative to the project.
+const localPackagesDir = "Packages"
+
+// localPackageDir returns the project-relative directory of a local package.
+func localPackageDir(name string) string {
+	return localPackagesDir + "/" + name
+}
+
+// localPackageSourceDir returns the project-relative source root of a local
+// package's single target, which planned file paths are relative to.
+func localPackageSourceDir(name string) string {
+	return localPackageDir(name) + "/Sources/" + name
+}
+
+// normalizeLocalPackages sanitizes local package names, drops empty and
+// duplicate packages, and removes dependency edges that point at packages the
+// plan does not declare. Dependencies and file assignments may name a package
+// as declared, sanitized or canonical; files naming no declared package move
+// into the app target with a warning.
+func normalizeLocalPackages(plan *PlannerResult) {
+	renamed := make(map[string]string)
+	packages := plan.LocalPackages[:0]
+	for _, pkg := range plan.LocalPackages {
+		name := sanitizeToPascalCase(pkg.Name)
+		if name == "" {
+			continue
+		}
+		// A duplicate merges into the first package with its canonical name.
+		if _, dup := renamed[name]; !dup {
+			renamed[name] = name
+			packages = append(packages, pkg)
+			packages[len(packages)-1].Name = name
+		}
+		renamed[pkg.Name] = name
+	}
+	plan.LocalPackages = packages
+	resolve := func(ref string) string {
+		if name, ok := renamed[ref]; ok {
+			return name
+		}
+		return renamed[sanitizeToPascalCase(ref)]
+	}
+
+	for i := range plan.LocalPackages {
+		pkg := &plan.LocalPackages[i]
+		var deps []string
+		for _, dep := range pkg.DependsOn {
+			dep = resolve(dep)
+			if dep != "" && dep != pkg.Name && !slices.Contains(deps, dep) {
+				deps = append(deps, dep)
+			}
+		}
+		pkg.DependsOn = deps
+	}
+
+	for i := range plan.Files {
+		f := &plan.Files[i]
+		if f.Package == "" {
+			continue
+		}
+		name := resolve(f.Package)
+		if name == "" {
+			terminal.Warning(fmt.Sprintf("%s names undeclared local package %s; adding it to the app target", f.Path, f.Package))
+		}
+		f.Package = name
+	}
+}
+
+// orderLocalPackages sorts the local packages so each comes after the packages
+// it depends on, keeping the planner's order otherwise. SwiftPM rejects
+// dependency cycles, so a plan with one is an error.
+func orderLocalPackages(plan *PlannerResult) error {
+	ordered := make([]LocalPackagePlan, 0, len(plan.LocalPackages))
+	placed := make(map[string]bool)
+	for len(ordered) < len(plan.LocalPackages) {
+		progressed := false
+		for _, pkg := range plan.LocalPackages {
+			if placed[pkg.Name] {
+				continue
+			}
+			if slices.ContainsFunc(pkg.DependsOn, func(dep string) bool { return !placed[dep] }) {
+				continue
+			}
+			ordered = append(ordered, pkg)
+			placed[pkg.Name] = true
+			progressed = true
+		}
+		if !progressed {
+			var cycle []string
+			for _, pkg := range plan.LocalPackages {
+				if !placed[pkg.Name] {
+					cycle = append(cycle, pkg.Name)
+				}
+			}
+			return fmt.Errorf("local packages %s depend on each other in a cycle", strings.Join(cycle, ", "))
+		}
+	}
+	plan.LocalPackages = ordered
+	return nil
+}
+
+// localPackageModels returns the local packages as XcodeGen packages, each
+// linked into the main app target.
+func localPackageModels(plan *PlannerResult) []xcodegen.Package {
+	var packages []xcodegen.Package
+	for _, pkg := range plan.LocalPackages {
+		packages = append(packages, xcodegen.Package{Name: pkg.Name, Path: localPackageDir(pkg.Name)})
+	}
+	return packages
+}
+
+// swiftPMPlatforms maps platform identifiers to Package.swift platform entries.
+var swiftPMPlatforms = map[string]string{
+	PlatformIOS:      ".iOS(.v26)",
+	PlatformMacOS:    ".macOS(.v26)",
+	PlatformWatchOS:  ".watchOS(.v26)",
+	PlatformTvOS:     ".tvOS(.v26)",
+	PlatformVisionOS: ".visionOS(.v26)",
+}
+
+// packageManifest renders the Package.swift of a local package: one library
+// product and target named after the package, depending on its local siblings.
+func packageManifest(pkg LocalPackagePlan, platforms []string) string {
+	var b strings.Builder
+	b.WriteString("// swift-tools-version: 6.2\n")
+	b.WriteString("import PackageDescription\n\n")
+	b.WriteString("let package = Package(\n")
+	fmt.Fprintf(&b, "    name: %q,\n", pkg.Name)
+
+	var entries []string
+	for _, platform := range platforms {
+		if entry, ok := swiftPMPlatforms[platform]; ok && !slices.Contains(entries, entry) {
+			entries = append(entries, entry)
+		}
+	}
+	fmt.Fprintf(&b, "    platforms: [%s],\n", strings.Join(entries, ", "))
+	fmt.Fprintf(&b, "    products: [\n        .library(name: %q, targets: [%q]),\n    ],\n", pkg.Name, pkg.Name)
+
+	if len(pkg.DependsOn) > 0 {
+		b.WriteString("    dependencies: [\n")
+		for _, dep := range pkg.DependsOn {
+			fmt.Fprintf(&b, "        .package(path: \"../%s\"),\n", dep)
+		}
+		b.WriteString("    ],\n")
+	}
+
+	b.WriteString("    targets: [\n")
+	fmt.Fprintf(&b, "        .target(\n            name: %q", pkg.Name)
+	if len(pkg.DependsOn) > 0 {
+		var deps []string
+		for _, dep := range pkg.DependsOn {
+			deps = append(deps, fmt.Sprintf("%q", dep))
+		}
+		fmt.Fprintf(&b, ",\n            dependencies: [%s]", strings.Join(deps, ", "))
+	}
+	b.WriteString("\n        ),\n    ]\n)\n")
+	return b.String()
+}
+
+// writeLocalPackages writes the Package.swift of each planned local package.
+// scaffoldSourceDirs creates the matching source roots.
+func writeLocalPackages(projectDir string, plan *PlannerResult) error {
+	if plan == nil {
+		return nil
+	}
+	for _, pkg := range plan.LocalPackages {
+		dir := filepath.Join(projectDir, filepath.FromSlash(localPackageDir(pkg.Name)))
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return fmt.Errorf("failed to create directory %s: %w", dir, err)
+		}
+		manifestPath := filepath.Join(dir, "Package.swift")
+		if err := os.WriteFile(manifestPath, []byte(packageManifest(pkg, plan.GetPlatforms())), 0o644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", manifestPath, err)
+		}
+	}
+	return nil
+}
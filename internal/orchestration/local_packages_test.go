@@ -0,0 +1,112 @@
+package orchestration
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestNormalizeLocalPackages(t *testing.T) {
+	plan := &PlannerResult{
+		LocalPackages: []LocalPackagePlan{
+			{Name: "models"},
+			{Name: "Models"},
+			{Name: " "},
+			{Name: "Design System", DependsOn: []string{"models", "Design System", "Unknown", "HabitsFeature"}},
+			{Name: "HabitsFeature", DependsOn: []string{"Models", "models"}},
+		},
+		Files: []FilePlan{
+			{Path: "Habit.swift", Package: "models"},
+			{Path: "Theme.swift", Package: "Design System"},
+			{Path: "Colors.swift", Package: "DesignSystem"},
+			{Path: "Streak.swift", Package: "habits feature"},
+			{Path: "Ghost.swift", Package: "Ghost"},
+		},
+	}
+
+	normalizePlannerResult(plan)
+	if err := orderLocalPackages(plan); err != nil {
+		t.Fatal(err)
+	}
+
+	var names []string
+	for _, pkg := range plan.LocalPackages {
+		names = append(names, pkg.Name)
+	}
+	if want := []string{"Models", "HabitsFeature", "DesignSystem"}; !slices.Equal(names, want) {
+		t.Fatalf("packages = %v, want %v with dependencies first", names, want)
+	}
+	if deps := plan.LocalPackages[1].DependsOn; !slices.Equal(deps, []string{"Models"}) {
+		t.Errorf("HabitsFeature depends on %v, want [Models]", deps)
+	}
+	if deps := plan.LocalPackages[2].DependsOn; !slices.Equal(deps, []string{"Models", "HabitsFeature"}) {
+		t.Errorf("DesignSystem depends on %v, want [Models HabitsFeature] without self or unknown packages", deps)
+	}
+	for i, want := range []string{"Models", "DesignSystem", "DesignSystem", "HabitsFeature", ""} {
+		if got := plan.Files[i].Package; got != want {
+			t.Errorf("Files[%d].Package = %q, want %q", i, got, want)
+		}
+	}
+}
+
+func TestOrderLocalPackagesRejectsCycles(t *testing.T) {
+	plan := &PlannerResult{
+		LocalPackages: []LocalPackagePlan{
+			{Name: "Models"},
+			{Name: "Sync", DependsOn: []string{"Models", "Storage"}},
+			{Name: "Storage", DependsOn: []string{"Sync"}},
+		},
+	}
+	err := orderLocalPackages(plan)
+	if err == nil || !strings.Contains(err.Error(), "Sync, Storage") {
+		t.Errorf("orderLocalPackages() = %v, want a cycle error naming Sync and Storage", err)
+	}
+}
+
+func TestPackageManifest(t *testing.T) {
+	manifest := packageManifest(LocalPackagePlan{Name: "HabitsFeature", DependsOn: []string{"Models", "DesignSystem"}}, []string{PlatformIOS, PlatformWatchOS})
+
+	for _, want := range []string{
+		"// swift-tools-version: 6.2\n",
+		`name: "HabitsFeature",`,
+		"platforms: [.iOS(.v26), .watchOS(.v26)],",
+		`.library(name: "HabitsFeature", targets: ["HabitsFeature"]),`,
+		`.package(path: "../Models"),`,
+		`.package(path: "../DesignSystem"),`,
+		`dependencies: ["Models", "DesignSystem"]`,
+	} {
+		if !strings.Contains(manifest, want) {
+			t.Errorf("Package.swift missing %q:\n%s", want, manifest)
+		}
+	}
+
+	if leaf := packageManifest(LocalPackagePlan{Name: "Models"}, []string{PlatformIOS}); strings.Contains(leaf, "dependencies") {
+		t.Errorf("package without dependencies should not declare any:\n%s", leaf)
+	}
+}
+
+func TestScaffoldLocalPackages(t *testing.T) {
+	projectDir := t.TempDir()
+	plan := &PlannerResult{
+		Platform:      PlatformIOS,
+		LocalPackages: []LocalPackagePlan{{Name: "Models"}},
+	}
+
+	if err := scaffoldSourceDirs(projectDir, "Habits", plan); err != nil {
+		t.Fatalf("scaffoldSourceDirs() error: %v", err)
+	}
+	if err := writeLocalPackages(projectDir, plan); err != nil {
+		t.Fatalf("writeLocalPackages() error: %v", err)
+	}
+
+	for _, rel := range []string{
+		"Packages/Models/Package.swift",
+		"Packages/Models/Sources/Models/Placeholder.swift",
+	} {
+		if _, err := os.Stat(filepath.Join(projectDir, filepath.FromSlash(rel))); err != nil {
+			t.Errorf("expected %s: %v", rel, err)
+		}
+	}
+}
@@ -115,3 +115,33 @@ func TestParsePlan_AcceptsCanonicalWatchShape(t *testing.T) {
 		t.Fatalf("depends_on len = %d, want 1", len(plan.Files[0].DependsOn))
 	}
 }
+
+func TestParsePlanWithTestsLoadsTestingSkill(t *testing.T) {
+	input := `{
+  "platform": "ios",
+  "files": [
+    {"path": "Features/Notes/NoteListViewModel.swift", "type_name": "NoteListViewModel", "purpose": "list state", "components": "NoteListViewModel", "data_access": "none", "depends_on": []}
+  ],
+  "rule_keys": ["haptics"],
+  "tests": {
+    "unit": [
+      {"path": "NoteListViewModelTests.swift", "type_name": "NoteListViewModelTests", "purpose": "list state", "components": "@Test load()", "data_access": "none", "depends_on": ["Features/Notes/NoteListViewModel.swift"]}
+    ]
+  },
+  "build_order": ["Features/Notes/NoteListViewModel.swift"]
+}`
+	plan, err := parsePlan(input)
+	if err != nil {
+		t.Fatalf("parsePlan() error: %v", err)
+	}
+	if !plan.HasUnitTests() || plan.HasUITests() {
+		t.Fatalf("HasUnitTests = %v, HasUITests = %v; want true, false", plan.HasUnitTests(), plan.HasUITests())
+	}
+	if !plan.HasRuleKey("testing") {
+		t.Errorf("rule_keys = %v, want testing added", plan.RuleKeys)
+	}
+	files := plan.TestFiles("Notes")
+	if len(files) != 1 || files[0].Path != "NotesTests/NoteListViewModelTests.swift" {
+		t.Errorf("TestFiles = %+v, want NotesTests/NoteListViewModelTests.swift", files)
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"io/fs"
 	"sort"
 	"strings"
+
+	"github.com/moasq/nanowave/internal/packageregistry"
 )
 
 func loadPhaseSkillContent(skillName string) (string, error) {
@@ -165,6 +167,41 @@ func composePlannerSystemPrompt(intent *IntentDecision, platform string) (string
 	return b.String(), nil
 }
 
+// appendPluginIntegrations adds the integration plugins installed on this machine to the
+// planner prompt, so their IDs can appear in `integrations` next to the built-in providers.
+func appendPluginIntegrations(systemPrompt string, hints []string) string {
+	if len(hints) == 0 {
+		return systemPrompt
+	}
+	var b strings.Builder
+	b.WriteString(systemPrompt)
+	appendPromptSection(&b, "Plugin Integrations", "Additional providers installed as plugins. Add a provider's ID to `integrations` when the app matches its description:\n"+strings.Join(hints, "\n"))
+	return b.String()
+}
+
+// appendManifestPackages lists the packages added or overridden by user and
+// team registry manifests, which the planner's curated table does not know.
+func appendManifestPackages(systemPrompt string, packages []packageregistry.Package) string {
+	var lines []string
+	for _, pkg := range packages {
+		if pkg.Source == packageregistry.EmbeddedSource {
+			continue
+		}
+		line := fmt.Sprintf("- %s (%s): %s", pkg.Name, pkg.Category, pkg.Description)
+		if len(pkg.Platforms) > 0 {
+			line += fmt.Sprintf(" [%s only]", strings.Join(pkg.Platforms, ", "))
+		}
+		lines = append(lines, line)
+	}
+	if len(lines) == 0 {
+		return systemPrompt
+	}
+	var b strings.Builder
+	b.WriteString(systemPrompt)
+	appendPromptSection(&b, "Team Packages", "Packages approved by this user's or team's package registry, in addition to the curated table. Prefer them when they fit, and never suggest one for a platform it does not support:\n"+strings.Join(lines, "\n"))
+	return b.String()
+}
+
 func composeCoderAppendPrompt(phaseSkillName, platform string) (string, error) {
 	phaseSkill, err := loadPhaseSkillContent(phaseSkillName)
 	if err != nil {
@@ -8,10 +8,13 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/moasq/nanowave/internal/asc"
 	"github.com/moasq/nanowave/internal/claude"
 	"github.com/moasq/nanowave/internal/config"
 	"github.com/moasq/nanowave/internal/integrations"
 	"github.com/moasq/nanowave/internal/mcpregistry"
+	"github.com/moasq/nanowave/internal/packageregistry"
+	"github.com/moasq/nanowave/internal/projectconfig"
 	"github.com/moasq/nanowave/internal/terminal"
 )
 
@@ -112,6 +115,8 @@ type Pipeline struct {
 	registry        *mcpregistry.Registry          // internal MCP server registry (apple-docs, xcodegen)
 	activeProviders []integrations.ActiveProvider   // resolved providers for current build (transient)
 	onStreamEvent   func(claude.StreamEvent)       // optional hook for web UI streaming (nil = CLI-only)
+	ascAPI          *asc.Client                    // App Store Connect API client (nil until first use)
+	packages        *packageregistry.Registry      // SPM package registry (nil until first use)
 }
 
 // SetManager sets the integration manager for provider-based integrations.
@@ -125,6 +130,28 @@ func (p *Pipeline) SetStreamHook(hook func(claude.StreamEvent)) {
 	p.onStreamEvent = hook
 }
 
+// packageRegistry returns the SPM package registry: the embedded default merged
+// with the user and team manifests, loaded on first use. Invalid manifest
+// entries are skipped with a warning.
+func (p *Pipeline) packageRegistry() (*packageregistry.Registry, error) {
+	if p.packages != nil {
+		return p.packages, nil
+	}
+	var nanowaveRoot, projectDir string
+	if p.config != nil {
+		nanowaveRoot, projectDir = p.config.NanowaveRoot, p.config.ProjectDir
+	}
+	registry, skipped, err := packageregistry.Load(nanowaveRoot, projectDir)
+	if err != nil {
+		return nil, err
+	}
+	for _, err := range skipped {
+		terminal.Warning(fmt.Sprintf("Skipping package registry entry: %v", err))
+	}
+	p.packages = registry
+	return registry, nil
+}
+
 // makeStreamCallback wraps the terminal progress callback and the optional web hook.
 func (p *Pipeline) makeStreamCallback(progress *terminal.ProgressDisplay) func(claude.StreamEvent) {
 	termCb := newProgressCallback(progress)
@@ -300,6 +327,12 @@ func (p *Pipeline) Action(ctx context.Context, prompt string, ac ActionContext,
 		if err := p.scaffoldProject(projectDir, appName, plan, provState.needsAppleSignIn); err != nil {
 			return nil, err
 		}
+		if err := writeCapturePlan(projectDir, appName, analysis, plan); err != nil {
+			terminal.Warning(fmt.Sprintf("Could not write screenshot capture plan: %v", err))
+		}
+		if err := writeMetadata(projectDir, appName, analysis); err != nil {
+			terminal.Warning(fmt.Sprintf("Could not write store metadata: %v", err))
+		}
 	}
 	backendProvisioned := provState.backendProvisioned
 
@@ -315,7 +348,7 @@ func (p *Pipeline) Action(ctx context.Context, prompt string, ac ActionContext,
 		totalCacheCreate  int
 	)
 
-	progress := terminal.NewProgressDisplay("build", len(plan.Files))
+	progress := terminal.NewProgressDisplay("build", len(plan.Files)+len(plan.TestFiles(appName)))
 	progress.Start()
 
 	prevValidCount := 0
@@ -385,6 +418,19 @@ func (p *Pipeline) Action(ctx context.Context, prompt string, ac ActionContext,
 	progress.StopWithSuccess(fmt.Sprintf("Build complete — %d files", report.ValidCount))
 	terminal.Detail("Cost", fmt.Sprintf("$%.4f (total across %d passes)", totalCostUSD, completionPasses))
 
+	// Lock the package versions the build resolved so regenerating the
+	// project keeps them (nanowave packages update unlocks them).
+	if locked, err := projectconfig.LockResolvedPackages(projectDir); err != nil {
+		terminal.Warning(fmt.Sprintf("Could not lock package versions: %v", err))
+	} else if len(locked) > 0 {
+		terminal.Detail("Locked", strings.Join(locked, ", "))
+	}
+
+	// Declare what the generated code collects before the project is committed.
+	if _, err := p.writePrivacyManifest(projectDir); err != nil {
+		terminal.Warning(fmt.Sprintf("Could not write privacy manifest: %v", err))
+	}
+
 	// Phase 5: Finalize (git init + commit — new builds only)
 	if !isEdit {
 		p.finalize(ctx, projectDir, appName)
@@ -406,7 +452,7 @@ func (p *Pipeline) Action(ctx context.Context, prompt string, ac ActionContext,
 		WatchProjectShape: plan.GetWatchProjectShape(),
 		Features:          analysis.Features,
 		FileCount:         len(plan.Files),
-		PlannedFiles:      len(plan.Files),
+		PlannedFiles:      report.TotalPlanned,
 		CompletedFiles:    report.ValidCount,
 		CompletionPasses:  completionPasses,
 		SessionID:         sessionID,
@@ -13,6 +13,7 @@ import (
 	"github.com/moasq/nanowave/internal/appleauth"
 	"github.com/moasq/nanowave/internal/asc"
 	"github.com/moasq/nanowave/internal/claude"
+	"github.com/moasq/nanowave/internal/metadata"
 	"github.com/moasq/nanowave/internal/screenshots"
 	"github.com/moasq/nanowave/internal/terminal"
 	"golang.org/x/term"
@@ -197,7 +198,20 @@ func (p *Pipeline) ASCFull(ctx context.Context, prompt, projectDir, sessionID st
 		cl.CompleteItem(terminal.ChecklistSkipped, "No builds uploaded")
 	}
 
-	// 7. Icon
+	// 7. Version and build number
+	cl.StartItem("Checking version and build number")
+	if bumped, err := p.prepareVersion(ctx, projectDir, preflight); err != nil {
+		log.Printf("[asc] version check: %v", err)
+		cl.CompleteItem(terminal.ChecklistWarning, "Version check skipped")
+	} else if preflight.VersionClosed {
+		cl.CompleteItem(terminal.ChecklistWarning, fmt.Sprintf("Version %s is not above the released versions — run `nanowave version bump` first", preflight.MarketingVersion))
+	} else if bumped {
+		cl.CompleteItem(terminal.ChecklistSuccess, fmt.Sprintf("Version %s, build number raised to %d", preflight.MarketingVersion, preflight.BuildNumber))
+	} else {
+		cl.CompleteItem(terminal.ChecklistSuccess, fmt.Sprintf("Version %s (build %d)", preflight.MarketingVersion, preflight.BuildNumber))
+	}
+
+	// 8. Icon
 	platform := "ios"
 	if configData, err := os.ReadFile(filepath.Join(projectDir, "project_config.json")); err == nil {
 		var cfg struct {
@@ -218,10 +232,10 @@ func (p *Pipeline) ASCFull(ctx context.Context, prompt, projectDir, sessionID st
 		p.offerIconUpload(ctx, projectDir, platform)
 	}
 
-	// 8. Screenshots
+	// 9. Screenshots
 	cl.StartItem("Checking screenshots")
-	deviceFamily := readDeviceFamily(projectDir)
-	ssFound, ssCount, ssDir, ssFulfilled, ssMissing := p.checkScreenshots(projectDir, deviceFamily)
+	reqs := screenshots.RequirementsFor(platform, readDeviceFamily(projectDir))
+	ssFound, ssCount, ssDir, ssFulfilled, ssMissing := p.checkScreenshots(projectDir, reqs)
 	var captureResult *screenshots.CaptureResult
 
 	if ssFound && len(ssMissing) == 0 {
@@ -232,25 +246,38 @@ func (p *Pipeline) ASCFull(ctx context.Context, prompt, projectDir, sessionID st
 		preflight.ScreenshotDir = ssDir
 		preflight.DeviceTypes = ssFulfilled
 		cl.CompleteItem(terminal.ChecklistWarning, fmt.Sprintf("Screenshots incomplete — missing: %s", strings.Join(ssMissing, ", ")))
-		dir, cr := p.offerScreenshotOptions(ctx, projectDir, deviceFamily)
+		dir, cr := p.offerScreenshotOptions(ctx, projectDir, reqs)
 		if dir != "" {
 			preflight.ScreenshotDir = dir
 			captureResult = cr
-			reqs := screenshots.RequirementsForFamily(deviceFamily)
-			preflight.DeviceTypes, _ = screenshots.ValidateScreenshots(dir, reqs)
+			preflight.DeviceTypes, _, _ = screenshots.ValidateScreenshots(dir, reqs)
 		}
 	} else {
 		cl.CompleteItem(terminal.ChecklistWarning, "No screenshots")
-		dir, cr := p.offerScreenshotOptions(ctx, projectDir, deviceFamily)
+		dir, cr := p.offerScreenshotOptions(ctx, projectDir, reqs)
 		if dir != "" {
 			preflight.ScreenshotDir = dir
 			captureResult = cr
-			reqs := screenshots.RequirementsForFamily(deviceFamily)
-			preflight.DeviceTypes, _ = screenshots.ValidateScreenshots(dir, reqs)
+			preflight.DeviceTypes, _, _ = screenshots.ValidateScreenshots(dir, reqs)
+		}
+	}
+
+	// 10. Privacy manifest
+	cl.StartItem("Generating privacy manifest")
+	if report, err := p.writePrivacyManifest(projectDir); err != nil {
+		log.Printf("[asc] privacy manifest: %v", err)
+		cl.CompleteItem(terminal.ChecklistWarning, "Privacy manifest skipped")
+	} else {
+		preflight.CollectsData = len(report.CollectedData) > 0
+		preflight.DataCollected = report.Summary()
+		if preflight.CollectsData {
+			cl.CompleteItem(terminal.ChecklistWarning, fmt.Sprintf("Privacy manifest written — %d data type(s) to declare", len(report.CollectedData)))
+		} else {
+			cl.CompleteItem(terminal.ChecklistSuccess, "Privacy manifest written — no data collected")
 		}
 	}
 
-	// 9. Xcode project
+	// 11. Xcode project
 	cl.StartItem("Regenerating Xcode project")
 	if err := p.regenerateXcodeProject(ctx, projectDir); err != nil {
 		cl.CompleteItem(terminal.ChecklistWarning, "Xcode project regeneration skipped")
@@ -258,18 +285,13 @@ func (p *Pipeline) ASCFull(ctx context.Context, prompt, projectDir, sessionID st
 		cl.CompleteItem(terminal.ChecklistSuccess, "Xcode project regenerated")
 	}
 
-	// 10. Project pattern detection
-	cl.StartItem("Analyzing project patterns")
-	preflight.HasSignIn, preflight.CollectsData = detectProjectPatterns(projectDir)
-	switch {
-	case preflight.HasSignIn && preflight.CollectsData:
-		cl.CompleteItem(terminal.ChecklistWarning, "Sign-in and data collection detected")
-	case preflight.HasSignIn:
+	// 12. Sign-in detection
+	cl.StartItem("Checking for sign-in")
+	preflight.HasSignIn = detectSignIn(projectDir)
+	if preflight.HasSignIn {
 		cl.CompleteItem(terminal.ChecklistWarning, "Sign-in detected — review credentials needed")
-	case preflight.CollectsData:
-		cl.CompleteItem(terminal.ChecklistWarning, "Data collection detected — privacy declaration needed")
-	default:
-		cl.CompleteItem(terminal.ChecklistSuccess, "No sign-in or data collection detected")
+	} else {
+		cl.CompleteItem(terminal.ChecklistSuccess, "No sign-in detected")
 	}
 
 	// Load API credentials silently (no checklist item — not a meaningful gate)
@@ -309,13 +331,20 @@ Use these flags with xcodebuild: -authenticationKeyPath %s -authenticationKeyID
 		systemPrompt += "\n## Simulator Screenshots\n\n"
 		systemPrompt += "Simulators are already booted with the app installed and launched. "
 		systemPrompt += "You can capture additional screenshots on different app screens using AXe.\n"
-		systemPrompt += "Only the initial launch screen has been captured so far. "
-		systemPrompt += "Before submitting, analyze the app's source code to identify key screens "
-		systemPrompt += "(ignore settings/preferences), navigate to them with AXe, and capture more screenshots.\n\n"
+		if plan, _ := screenshots.LoadCapturePlan(projectDir); plan != nil {
+			systemPrompt += fmt.Sprintf("The screens listed in %s have been captured. ", screenshots.CapturePlanFile)
+			systemPrompt += "Check the captures; if a key screen is missing or shows no content, navigate to it with AXe and capture it.\n\n"
+		} else {
+			systemPrompt += "Only the initial launch screen has been captured so far. "
+			systemPrompt += "Before submitting, analyze the app's source code to identify key screens "
+			systemPrompt += "(ignore settings/preferences), navigate to them with AXe, and capture more screenshots.\n\n"
+		}
 		for dt, udid := range captureResult.SimUDIDs {
 			systemPrompt += fmt.Sprintf("- %s simulator UDID: %s\n", dt, udid)
 		}
-		systemPrompt += fmt.Sprintf("\nScreenshot output dir: %s\n", preflight.ScreenshotDir)
+		systemPrompt += fmt.Sprintf("\nScreenshot output dir: %s\n", captureResult.Dir)
+		systemPrompt += fmt.Sprintf("Raw captures are framed with captions from %s into %s. ", screenshots.LayoutFile, screenshots.FramedDir)
+		systemPrompt += "After capturing, add a short marketing caption per screen to the layout and run `nanowave screenshots frame`, then upload the framed screenshots.\n"
 		systemPrompt += "Use: `axe describe-ui --udid <UDID>` to see current screen elements.\n"
 		systemPrompt += "Use: `axe tap --id <element_id> --udid <UDID>` to navigate.\n"
 		systemPrompt += "Use: `xcrun simctl io <UDID> screenshot <path>.png` to capture.\n"
@@ -479,6 +508,7 @@ Use these flags with xcodebuild: -authenticationKeyPath %s -authenticationKeyID
 	}
 
 	showCost(resp)
+	p.recordRelease(ctx, projectDir, preflight)
 
 	return &asc.Result{
 		Summary:      resp.Result,
@@ -665,27 +695,47 @@ func (p *Pipeline) regenerateXcodeProject(ctx context.Context, projectDir string
 	return nil
 }
 
+// ascClient returns the App Store Connect API client for the stored API key,
+// creating it on first use.
+func (p *Pipeline) ascClient() (*asc.Client, error) {
+	if p.ascAPI != nil {
+		return p.ascAPI, nil
+	}
+	cred, err := asc.LoadCredential()
+	if err != nil {
+		return nil, err
+	}
+	// Request logs land in publish.log, where the pipeline redirects the standard logger.
+	client, err := asc.NewClient(cred, asc.WithLogger(log.Default()))
+	if err != nil {
+		return nil, err
+	}
+	p.ascAPI = client
+	return client, nil
+}
+
 // checkASCAuth verifies authentication by making a real API call.
-// If `asc apps list --output json` succeeds and returns a valid ASC envelope,
-// the user has working credentials. No string matching on CLI output.
+// If the stored API key can list apps, the user has working credentials.
+// An agreements error still proves the key itself is valid.
 func (p *Pipeline) checkASCAuth(ctx context.Context) bool {
-	cmd := exec.CommandContext(ctx, "asc", "apps", "list", "--output", "json")
-	output, err := cmd.CombinedOutput()
+	client, err := p.ascClient()
 	if err != nil {
-		log.Printf("[asc] auth check failed: asc apps list returned error: %v", err)
+		log.Printf("[asc] auth check failed: %v", err)
 		return false
 	}
-	// Valid auth returns an ASC envelope with a "data" array
-	var env asc.Envelope
-	ok := json.Unmarshal(output, &env) == nil && env.Data != nil
-	log.Printf("[asc] auth check: valid=%v apps=%d", ok, len(env.Data))
-	return ok
+	if _, _, err := client.CheckAgreements(ctx); err != nil {
+		log.Printf("[asc] auth check failed: %v", err)
+		return false
+	}
+	log.Printf("[asc] auth check: valid")
+	return true
 }
 
 // setupASCAuth guides the user through App Store Connect authentication.
 // Primary flow: Apple ID + password + OTP (fully automated API key creation).
 // Fallback: manual API key entry. Returns true on success.
 func (p *Pipeline) setupASCAuth(ctx context.Context) bool {
+	p.ascAPI = nil // a new API key may be stored below
 	log.Printf("[asc] starting guided auth setup")
 	terminal.Info("You need to authenticate with App Store Connect to continue.")
 	fmt.Println()
@@ -1056,23 +1106,12 @@ func (p *Pipeline) gatherASCContext(ctx context.Context, projectDir string, cl *
 	if savedAppID != "" {
 		log.Printf("[asc] using saved asc_app_id=%s", savedAppID)
 
-		cmd := exec.CommandContext(ctx, "asc", "apps", "get", "--id", savedAppID, "--output", "json")
-		output, err := cmd.CombinedOutput()
-		if err == nil {
-			var resp struct {
-				Data struct {
-					ID         string `json:"id"`
-					Attributes struct {
-						Name     string `json:"name"`
-						BundleID string `json:"bundleId"`
-					} `json:"attributes"`
-				} `json:"data"`
-			}
-			if json.Unmarshal(output, &resp) == nil && resp.Data.ID != "" {
-				result.AppID = resp.Data.ID
-				result.AppName = resp.Data.Attributes.Name
-				result.BundleID = resp.Data.Attributes.BundleID
-				log.Printf("[asc] verified saved app: id=%s name=%q bundleID=%s", resp.Data.ID, resp.Data.Attributes.Name, resp.Data.Attributes.BundleID)
+		if client, err := p.ascClient(); err == nil {
+			if app, err := client.GetApp(ctx, savedAppID); err == nil && app.ID != "" {
+				result.AppID = app.ID
+				result.AppName = app.Name
+				result.BundleID = app.BundleID
+				log.Printf("[asc] verified saved app: id=%s name=%q bundleID=%s", app.ID, app.Name, app.BundleID)
 				return result
 			}
 		}
@@ -1083,92 +1122,73 @@ func (p *Pipeline) gatherASCContext(ctx context.Context, projectDir string, cl *
 	if result.BundleID != "" {
 		log.Printf("[asc] attempting app match by bundleID=%s", result.BundleID)
 
-		cmd := exec.CommandContext(ctx, "asc", "apps", "list", "--output", "json")
-		output, err := cmd.CombinedOutput()
+		client, err := p.ascClient()
 		if err != nil {
 			log.Printf("[asc] app list failed: %v", err)
 			return result
 		}
-
-		type ascAppAttrs struct {
-			Name     string `json:"name"`
-			BundleID string `json:"bundleId"`
-		}
-		type ascApp struct {
-			ID       string
-			Name     string
-			BundleID string
+		apps, err := client.ListApps(ctx)
+		if err != nil {
+			log.Printf("[asc] app list failed: %v", err)
+			return result
 		}
+		log.Printf("[asc] found %d apps in ASC account", len(apps))
 
-		var env asc.Envelope
-		if json.Unmarshal(output, &env) == nil {
-			apps := make([]ascApp, 0, len(env.Data))
-			for _, d := range env.Data {
-				var attrs ascAppAttrs
-				if json.Unmarshal(d.Attributes, &attrs) == nil {
-					apps = append(apps, ascApp{ID: d.ID, Name: attrs.Name, BundleID: attrs.BundleID})
-				}
-			}
-			log.Printf("[asc] found %d apps in ASC account", len(apps))
-
-			for _, app := range apps {
-				if app.BundleID == result.BundleID {
-					result.AppID = app.ID
-					result.AppName = app.Name
-					log.Printf("[asc] matched app: id=%s name=%q bundleID=%s", app.ID, app.Name, app.BundleID)
-					asc.SaveAppID(projectDir, app.ID)
-					return result
-				}
+		for _, app := range apps {
+			if app.BundleID == result.BundleID {
+				result.AppID = app.ID
+				result.AppName = app.Name
+				log.Printf("[asc] matched app: id=%s name=%q bundleID=%s", app.ID, app.Name, app.BundleID)
+				asc.SaveAppID(projectDir, app.ID)
+				return result
 			}
+		}
 
-			// No match — offer picker
-			log.Printf("[asc] no app matched bundleID=%s, offering picker with %d options", result.BundleID, len(apps))
+		// No match — offer picker
+		log.Printf("[asc] no app matched bundleID=%s, offering picker with %d options", result.BundleID, len(apps))
 
-			if len(apps) > 0 {
-				// Stop the checklist spinner before showing interactive picker
-				cl.CompleteItem(terminal.ChecklistSkipped, "App not found — select below")
+		if len(apps) > 0 {
+			// Stop the checklist spinner before showing interactive picker
+			cl.CompleteItem(terminal.ChecklistSkipped, "App not found — select below")
 
-				// Step 1: Ask create or use existing
-				picked := terminal.Pick("Select your app", []terminal.PickerOption{
-					{Label: "Create a new app", Desc: fmt.Sprintf("Register %s in App Store Connect", result.BundleID)},
-					{Label: "Use an existing app", Desc: fmt.Sprintf("Choose from %d apps in your account", len(apps))},
-				}, "")
+			// Step 1: Ask create or use existing
+			picked := terminal.Pick("Select your app", []terminal.PickerOption{
+				{Label: "Create a new app", Desc: fmt.Sprintf("Register %s in App Store Connect", result.BundleID)},
+				{Label: "Use an existing app", Desc: fmt.Sprintf("Choose from %d apps in your account", len(apps))},
+			}, "")
 
-				if picked == "" {
-					return result
-				}
-				if picked == "Create a new app" {
-					p.createASCApp(ctx, result, projectDir)
-					if result.AppID != "" {
-						asc.SaveAppID(projectDir, result.AppID)
-					}
-					return result
+			if picked == "" {
+				return result
+			}
+			if picked == "Create a new app" {
+				p.createASCApp(ctx, result, projectDir)
+				if result.AppID != "" {
+					asc.SaveAppID(projectDir, result.AppID)
 				}
+				return result
+			}
 
-				// Step 2: Show existing apps list
-				appOptions := make([]terminal.PickerOption, 0, len(apps))
-				for _, app := range apps {
-					appOptions = append(appOptions, terminal.PickerOption{
-						Label: app.Name,
-						Desc:  app.BundleID,
-					})
-				}
+			// Step 2: Show existing apps list
+			appOptions := make([]terminal.PickerOption, 0, len(apps))
+			for _, app := range apps {
+				appOptions = append(appOptions, terminal.PickerOption{
+					Label: app.Name,
+					Desc:  app.BundleID,
+				})
+			}
 
-				picked = terminal.Pick("Select an existing app", appOptions, "")
-				if picked == "" {
+			picked = terminal.Pick("Select an existing app", appOptions, "")
+			if picked == "" {
+				return result
+			}
+			for _, app := range apps {
+				if app.Name == picked {
+					result.AppID = app.ID
+					result.BundleID = app.BundleID
+					asc.SaveAppID(projectDir, app.ID)
 					return result
 				}
-				for _, app := range apps {
-					if app.Name == picked {
-						result.AppID = app.ID
-						result.BundleID = app.BundleID
-						asc.SaveAppID(projectDir, app.ID)
-						return result
-					}
-				}
 			}
-		} else {
-			log.Printf("[asc] could not parse app list response")
 		}
 	}
 
@@ -1284,140 +1304,99 @@ func (p *Pipeline) ensureBundleID(ctx context.Context, bundleID, appName string)
 	spinner := terminal.NewSpinner(fmt.Sprintf("Registering bundle ID %s...", bundleID))
 	spinner.Start()
 
-	cmd := exec.CommandContext(ctx, "asc", "bundle-ids", "create",
-		"--identifier", bundleID,
-		"--name", appName,
-		"--platform", "IOS",
-		"--output", "json",
-	)
-	output, err := cmd.CombinedOutput()
+	client, err := p.ascClient()
+	if err != nil {
+		log.Printf("[asc] no API client for bundle ID registration: %v", err)
+		spinner.StopWithMessage(fmt.Sprintf("  %s%s✗%s Bundle ID registration unavailable", terminal.Bold, terminal.Red, terminal.Reset))
+		terminal.Error("App Store Connect API key not found.")
+		return ""
+	}
+
+	created, err := client.CreateBundleID(ctx, bundleID, appName, "IOS")
 	if err == nil {
-		var env struct {
-			Data struct {
-				ID string `json:"id"`
-			} `json:"data"`
-		}
-		if json.Unmarshal(output, &env) == nil && env.Data.ID != "" {
-			log.Printf("[asc] bundle ID registered: %s resourceID=%s", bundleID, env.Data.ID)
-			spinner.StopWithMessage(fmt.Sprintf("  %s%s✓%s Bundle ID registered: %s", terminal.Bold, terminal.Green, terminal.Reset, bundleID))
-			return env.Data.ID
-		}
+		log.Printf("[asc] bundle ID registered: %s resourceID=%s", bundleID, created.ID)
+		spinner.StopWithMessage(fmt.Sprintf("  %s%s✓%s Bundle ID registered: %s", terminal.Bold, terminal.Green, terminal.Reset, bundleID))
+		return created.ID
 	}
 
 	// Bundle ID may already exist — look it up
-	log.Printf("[asc] bundle-ids create failed, looking up existing: %v", err)
+	log.Printf("[asc] bundle ID create failed, looking up existing: %v", err)
 	spinner.StopWithMessage(fmt.Sprintf("  %s%s—%s Checking existing bundle IDs...", terminal.Bold, terminal.Yellow, terminal.Reset))
 
-	lookupCmd := exec.CommandContext(ctx, "asc", "bundle-ids", "list", "--output", "json")
-	lookupOut, lookupErr := lookupCmd.CombinedOutput()
+	existing, found, lookupErr := client.FindBundleID(ctx, bundleID)
 	if lookupErr != nil {
+		log.Printf("[asc] bundle ID lookup failed: %v", lookupErr)
 		terminal.Error("Failed to list bundle IDs.")
 		return ""
 	}
-
-	resourceID := asc.FindBundleIDResource(lookupOut, bundleID)
-	if resourceID != "" {
-		log.Printf("[asc] found existing bundle ID resource: %s", resourceID)
+	if found {
+		log.Printf("[asc] found existing bundle ID resource: %s", existing.ID)
 		terminal.Success(fmt.Sprintf("Bundle ID %s already registered", bundleID))
-		return resourceID
+		return existing.ID
 	}
 
 	terminal.Error(fmt.Sprintf("Bundle ID %s could not be registered or found.", bundleID))
 	return ""
 }
 
-// checkAgreements checks whether all developer agreements are in ACTIVE state.
+// checkAgreements checks whether the account's required agreements are in effect.
+// Returns a nil slice when the check could not be made.
 func (p *Pipeline) checkAgreements(ctx context.Context) (bool, []asc.Agreement) {
-	cmd := exec.CommandContext(ctx, "asc", "agreements", "list", "--output", "json")
-	output, err := cmd.CombinedOutput()
+	client, err := p.ascClient()
 	if err != nil {
 		log.Printf("[asc] agreements check failed: %v", err)
 		return true, nil // skip gracefully
 	}
-	return asc.ParseAgreements(output)
+	ok, agreements, err := client.CheckAgreements(ctx)
+	if err != nil {
+		log.Printf("[asc] agreements check failed: %v", err)
+		return true, nil // skip gracefully
+	}
+	return ok, agreements
 }
 
 // checkVersionState finds an editable App Store version for the given app.
 // Returns (versionID, versionString, state, allVersions) or empty strings if none found.
 func (p *Pipeline) checkVersionState(ctx context.Context, appID string) (string, string, string, []asc.VersionInfo) {
-	cmd := exec.CommandContext(ctx, "asc", "versions", "list", "--app", appID, "--limit", "5", "--output", "json")
-	output, err := cmd.CombinedOutput()
+	client, err := p.ascClient()
 	if err != nil {
 		log.Printf("[asc] versions list failed: %v", err)
 		return "", "", "", nil
 	}
-
-	var env asc.Envelope
-	if json.Unmarshal(output, &env) != nil || len(env.Data) == 0 {
+	allVersions, err := client.ListAppStoreVersions(ctx, appID, 5)
+	if err != nil {
+		log.Printf("[asc] versions list failed: %v", err)
 		return "", "", "", nil
 	}
-
-	type versionAttrs struct {
-		VersionString string `json:"versionString"`
-		AppStoreState string `json:"appStoreState"`
-	}
-
-	// Priority order for editable states
-	editablePriority := map[string]int{
-		asc.VersionPrepareForSubmission: 1,
-		asc.VersionDeveloperRejected:    2,
-	}
-
-	var bestID, bestVersion, bestState string
-	bestPriority := 999
-	var allVersions []asc.VersionInfo
-
-	for _, d := range env.Data {
-		var attrs versionAttrs
-		if json.Unmarshal(d.Attributes, &attrs) != nil {
-			continue
-		}
-
-		allVersions = append(allVersions, asc.VersionInfo{
-			ID:            d.ID,
-			VersionString: attrs.VersionString,
-			State:         attrs.AppStoreState,
-		})
-
-		if pri, ok := editablePriority[attrs.AppStoreState]; ok && pri < bestPriority {
-			bestID = d.ID
-			bestVersion = attrs.VersionString
-			bestState = attrs.AppStoreState
-			bestPriority = pri
-		}
+	if len(allVersions) == 0 {
+		return "", "", "", nil
 	}
 
-	log.Printf("[asc] version state: id=%s version=%s state=%s allVersions=%d", bestID, bestVersion, bestState, len(allVersions))
-	return bestID, bestVersion, bestState, allVersions
+	best, _ := asc.EditableVersion(allVersions)
+	log.Printf("[asc] version state: id=%s version=%s state=%s allVersions=%d", best.ID, best.VersionString, best.State, len(allVersions))
+	return best.ID, best.VersionString, best.State, allVersions
 }
 
 // checkLatestBuild finds the most recent build for the given app.
 // Returns (buildID, buildVersion, processingState) or empty strings if none found.
 func (p *Pipeline) checkLatestBuild(ctx context.Context, appID string) (string, string, string) {
-	cmd := exec.CommandContext(ctx, "asc", "builds", "list", "--app", appID, "--sort", "-uploadedDate", "--limit", "1", "--output", "json")
-	output, err := cmd.CombinedOutput()
+	client, err := p.ascClient()
 	if err != nil {
 		log.Printf("[asc] builds list failed: %v", err)
 		return "", "", ""
 	}
-
-	var env asc.Envelope
-	if json.Unmarshal(output, &env) != nil || len(env.Data) == 0 {
+	builds, err := client.ListBuilds(ctx, appID, 1)
+	if err != nil {
+		log.Printf("[asc] builds list failed: %v", err)
 		return "", "", ""
 	}
-
-	type buildAttrs struct {
-		Version         string `json:"version"`
-		ProcessingState string `json:"processingState"`
-	}
-
-	var attrs buildAttrs
-	if json.Unmarshal(env.Data[0].Attributes, &attrs) != nil {
+	if len(builds) == 0 {
 		return "", "", ""
 	}
 
-	log.Printf("[asc] latest build: id=%s version=%s state=%s", env.Data[0].ID, attrs.Version, attrs.ProcessingState)
-	return env.Data[0].ID, attrs.Version, attrs.ProcessingState
+	latest := builds[0]
+	log.Printf("[asc] latest build: id=%s version=%s state=%s", latest.ID, latest.Version, latest.ProcessingState)
+	return latest.ID, latest.Version, latest.ProcessingState
 }
 
 // ComposeASCSystemPrompt builds the system prompt for ASC operations
@@ -1516,6 +1495,15 @@ When adding beta testers, **default to external testing** — it works for any e
 			sb.WriteString("- Latest build: none — build and upload required\n")
 		}
 
+		// Local version, managed by nanowave
+		if preflight.MarketingVersion != "" {
+			sb.WriteString(fmt.Sprintf("- Project version: %s (build %d) — already set in project_config.json and the Xcode project. Archive and upload as-is; do NOT edit MARKETING_VERSION or CURRENT_PROJECT_VERSION, and create the App Store version with this version string.\n",
+				preflight.MarketingVersion, preflight.BuildNumber))
+			if preflight.VersionClosed {
+				sb.WriteString(fmt.Sprintf("- **Version %s is not above the released versions** — it cannot take new builds. Tell the user to run `nanowave version bump minor` (or major/patch) and stop.\n", preflight.MarketingVersion))
+			}
+		}
+
 		// Icon + Screenshots + API key
 		if preflight.IconReady {
 			sb.WriteString("- App icon: ready\n")
@@ -1542,9 +1530,12 @@ When adding beta testers, **default to external testing** — it works for any e
 			sb.WriteString("- Project sign-in: not detected — App Review credentials likely not needed\n")
 		}
 		if preflight.CollectsData {
-			sb.WriteString("- Data collection: detected — App Privacy nutrition label must declare collected data types\n")
+			sb.WriteString("- Data collection: App Privacy must declare these data types (evidence in privacy/report.md, answers in privacy/app_privacy.json):\n")
+			for _, line := range preflight.DataCollected {
+				sb.WriteString(fmt.Sprintf("  - %s\n", line))
+			}
 		} else {
-			sb.WriteString("- Data collection: not detected — App Privacy should declare 'does not collect data'\n")
+			sb.WriteString("- Data collection: none found — App Privacy should declare 'does not collect data' (see privacy/report.md)\n")
 		}
 	} else {
 		// Fallback: read project config directly
@@ -1568,6 +1559,13 @@ When adding beta testers, **default to external testing** — it works for any e
 		}
 	}
 
+	if local, err := metadata.Load(projectDir); err == nil && local != nil {
+		sb.WriteString("\n## Store Metadata\n\n")
+		sb.WriteString(fmt.Sprintf("Store metadata is versioned in the project's %s/ directory (locales: %s). ", metadata.Dir, strings.Join(local.LocaleNames(), ", ")))
+		sb.WriteString("Do NOT write description, keywords, promotional text, what's new, URLs, categories or age rating ad hoc. ")
+		sb.WriteString("Edit the files there, then run `nanowave metadata diff` to review and `nanowave metadata push` to upload; push validates character limits first.\n")
+	}
+
 	// Load skills — official ASC CLI skills from github.com/rudrankriyam/app-store-connect-cli-skills
 	// plus nanowave-specific skills (asset-management, asc-publish)
 	ascSkills := []struct {
@@ -1621,7 +1619,7 @@ When adding beta testers, **default to external testing** — it works for any e
 
 You MUST resolve ALL of these BEFORE showing the submission preview. Do NOT just "remind" the user — actively resolve each one:
 
-1. **App Privacy Nutrition Label** — Ask the user if they have configured it. If not, show them the exact steps and wait for confirmation before proceeding. Use an OPTIONS block. Do NOT submit without confirmation.
+1. **App Privacy Nutrition Label** — Ask the user if they have configured it. If not, walk them through the answers in privacy/app_privacy.json (they match the generated PrivacyInfo.xcprivacy) and wait for confirmation before proceeding. Use an OPTIONS block. Do NOT submit without confirmation.
 2. **Support URL** — Check the current support URL. If it is a placeholder (e.g. google.com, example.com) or missing, ask the user to provide a real URL using an OPTIONS block. Update it via ` + "`asc localizations update`" + ` before submitting.
 3. **Privacy Policy URL** — If the app collects data or has sign-in, verify a real privacy policy URL is set. Ask the user if missing.
 4. **Screenshots** — Must have at least the mandatory device types uploaded. Do NOT submit without screenshots.
@@ -1639,27 +1637,19 @@ Before submitting a build for Beta App Review, you MUST:
 	return sb.String()
 }
 
-// detectProjectPatterns scans Swift files in the project directory for known
-// sign-in and data collection patterns. These are finite sets of framework/API
-// identifiers in generated code — not user input string matching.
-func detectProjectPatterns(projectDir string) (hasSignIn bool, collectsData bool) {
-	// Known sign-in patterns (finite set of framework identifiers)
+// detectSignIn scans Swift files in the project directory for known sign-in
+// patterns. These are a finite set of framework/API identifiers in generated
+// code — not user input string matching. Data collection is inferred by the
+// privacy analyzer instead.
+func detectSignIn(projectDir string) bool {
 	signInPatterns := []string{
 		"SignInView", "LoginView", "AuthView", "SignUpView",
 		"supabase.auth", ".signIn", ".signUp", ".signOut",
 		"FirebaseAuth", "Auth.auth()",
 		"ASAuthorizationAppleIDProvider",
 	}
-	// Known data collection patterns (finite set of SDK/framework names)
-	dataPatterns := []string{
-		"Analytics", "Firebase", "Amplitude", "Mixpanel",
-		"Sentry", "Crashlytics", "BugSnag",
-		"CLLocationManager", "CoreLocation",
-		"AVCaptureSession",
-		"CNContactStore",
-		"HealthKit", "HKHealthStore",
-	}
 
+	hasSignIn := false
 	err := filepath.Walk(projectDir, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			return nil // skip unreadable paths
@@ -1679,33 +1669,18 @@ func detectProjectPatterns(projectDir string) (hasSignIn bool, collectsData bool
 		if readErr != nil {
 			return nil
 		}
-		content := string(data)
-		if !hasSignIn {
-			for _, p := range signInPatterns {
-				if strings.Contains(content, p) {
-					hasSignIn = true
-					break
-				}
-			}
-		}
-		if !collectsData {
-			for _, p := range dataPatterns {
-				if strings.Contains(content, p) {
-					collectsData = true
-					break
-				}
+		for _, p := range signInPatterns {
+			if strings.Contains(string(data), p) {
+				hasSignIn = true
+				return filepath.SkipAll
 			}
 		}
-		// Stop walking early if both found
-		if hasSignIn && collectsData {
-			return filepath.SkipAll
-		}
 		return nil
 	})
 	if err != nil {
-		log.Printf("[asc] detectProjectPatterns walk error: %v", err)
+		log.Printf("[asc] detectSignIn walk error: %v", err)
 	}
-	return hasSignIn, collectsData
+	return hasSignIn
 }
 
 // lastLines returns the last n lines of a string.
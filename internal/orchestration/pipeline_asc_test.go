@@ -0,0 +1,53 @@
+package orchestration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/moasq/nanowave/internal/asc"
+	"github.com/moasq/nanowave/internal/asc/asctest"
+)
+
+func TestASCPreflightChecksUseAPI(t *testing.T) {
+	srv := asctest.NewServer(t)
+	app := asctest.Ref{Type: "apps", ID: "app1"}
+	srv.Add(asctest.Resource{Type: "apps", ID: "app1", Attributes: map[string]any{"name": "Habits", "bundleId": "com.example.habits"}})
+	srv.Add(asctest.Resource{Type: "appStoreVersions", ID: "v1", Attributes: map[string]any{"versionString": "1.0", "appStoreState": asc.VersionReadyForSale},
+		Relationships: map[string]asctest.Ref{"app": app}})
+	srv.Add(asctest.Resource{Type: "appStoreVersions", ID: "v2", Attributes: map[string]any{"versionString": "1.1", "appStoreState": asc.VersionDeveloperRejected},
+		Relationships: map[string]asctest.Ref{"app": app}})
+	srv.Add(asctest.Resource{Type: "appStoreVersions", ID: "v3", Attributes: map[string]any{"versionString": "1.2", "appStoreState": asc.VersionPrepareForSubmission},
+		Relationships: map[string]asctest.Ref{"app": app}})
+	srv.Add(asctest.Resource{Type: "builds", ID: "b7", Attributes: map[string]any{"version": "7", "processingState": "VALID", "uploadedDate": "2026-05-01T09:00:00Z"},
+		Relationships: map[string]asctest.Ref{"app": app}})
+	srv.Add(asctest.Resource{Type: "builds", ID: "b8", Attributes: map[string]any{"version": "8", "processingState": "PROCESSING", "uploadedDate": "2026-05-02T09:00:00Z"},
+		Relationships: map[string]asctest.Ref{"app": app}})
+
+	p := &Pipeline{ascAPI: srv.APIClient(t)}
+	ctx := context.Background()
+
+	if !p.checkASCAuth(ctx) {
+		t.Error("checkASCAuth() = false with a valid key")
+	}
+	if ok, agreements := p.checkAgreements(ctx); !ok || agreements == nil {
+		t.Errorf("checkAgreements() = %v, %v; want active", ok, agreements)
+	}
+
+	id, version, state, all := p.checkVersionState(ctx, "app1")
+	if id != "v3" || version != "1.2" || state != asc.VersionPrepareForSubmission || len(all) != 3 {
+		t.Errorf("checkVersionState() = %s, %s, %s, %d versions; want v3 in PREPARE_FOR_SUBMISSION", id, version, state, len(all))
+	}
+
+	buildID, buildVersion, buildState := p.checkLatestBuild(ctx, "app1")
+	if buildID != "b8" || buildVersion != "8" || buildState != "PROCESSING" {
+		t.Errorf("checkLatestBuild() = %s, %s, %s; want the latest build b8", buildID, buildVersion, buildState)
+	}
+
+	srv.SetAgreementsExpired(true)
+	if ok, agreements := p.checkAgreements(ctx); ok || len(agreements) == 0 {
+		t.Errorf("checkAgreements() = %v, %v; want agreements needing attention", ok, agreements)
+	}
+	if !p.checkASCAuth(ctx) {
+		t.Error("checkASCAuth() = false for a valid key blocked only by agreements")
+	}
+}
@@ -379,7 +379,11 @@ func (u *pipelineSetupUI) ValidateExisting(ctx context.Context, sc integrations.
 	terminal.Detail("Config details", fmt.Sprintf("URL=%s, has_anon_key=%t, has_PAT=%t",
 		cfg.ProjectURL, cfg.AnonKey != "", cfg.PAT != ""))
 
-	if cfg.PAT == "" {
+	if cfg.Local != nil {
+		terminal.Detail("Local stack", fmt.Sprintf("%s (Debug builds + provisioning)", cfg.Local.APIURL))
+	}
+
+	if cfg.PAT == "" && cfg.Local == nil {
 		terminal.Warning(fmt.Sprintf("%s PAT is missing — MCP tools will not work. Re-running setup...", p.Meta().Name))
 		if sc.CLIAvailable() {
 			err := sc.Setup(context.Background(), integrations.SetupRequest{
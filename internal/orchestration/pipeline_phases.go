@@ -76,6 +76,14 @@ func (p *Pipeline) plan(ctx context.Context, analysis *AnalysisResult, intent *I
 	if err != nil {
 		return nil, err
 	}
+	if p.manager != nil {
+		systemPrompt = appendPluginIntegrations(systemPrompt, p.manager.PlannerHints())
+	}
+	packages, err := p.packageRegistry()
+	if err != nil {
+		return nil, err
+	}
+	systemPrompt = appendManifestPackages(systemPrompt, packages.Packages())
 
 	// Marshal the analysis as the user message
 	analysisJSON, err := json.MarshalIndent(analysis, "", "  ")
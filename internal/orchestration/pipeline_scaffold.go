@@ -9,11 +9,20 @@ import (
 // scaffoldProject writes project_config.json, project.yml, asset catalogs,
 // source directory stubs, .gitignore, and runs XcodeGen.
 func (p *Pipeline) scaffoldProject(projectDir, appName string, plan *PlannerResult, needsAppleSignIn bool) error {
+	packages, err := p.packageRegistry()
+	if err != nil {
+		return err
+	}
+
 	// Write project_config.json first (source of truth for XcodeGen MCP server).
-	if err := writeProjectConfig(projectDir, plan, appName); err != nil {
+	if err := writeProjectConfig(projectDir, plan, appName, packages); err != nil {
 		return fmt.Errorf("failed to write project_config.json: %w", err)
 	}
 
+	if err := writeDesignFile(projectDir, plan); err != nil {
+		return fmt.Errorf("failed to write design: %w", err)
+	}
+
 	// Auto-add Apple Sign-In entitlement when apple auth is detected.
 	if needsAppleSignIn {
 		if err := addAutoEntitlement(projectDir, "com.apple.developer.applesignin", []any{"Default"}, ""); err != nil {
@@ -26,7 +35,7 @@ func (p *Pipeline) scaffoldProject(projectDir, appName string, plan *PlannerResu
 	// Read back entitlements from project_config.json so project.yml includes them.
 	mainEntitlements := readConfigEntitlements(projectDir, "")
 
-	if err := writeProjectYML(projectDir, plan, appName, mainEntitlements); err != nil {
+	if err := writeProjectYML(projectDir, plan, appName, mainEntitlements, packages); err != nil {
 		return fmt.Errorf("failed to write project.yml: %w", err)
 	}
 
@@ -42,6 +51,10 @@ func (p *Pipeline) scaffoldProject(projectDir, appName string, plan *PlannerResu
 		return fmt.Errorf("failed to scaffold source dirs: %w", err)
 	}
 
+	if err := writeLocalPackages(projectDir, plan); err != nil {
+		return fmt.Errorf("failed to write local packages: %w", err)
+	}
+
 	if err := runXcodeGen(projectDir); err != nil {
 		return fmt.Errorf("failed to run xcodegen: %w", err)
 	}
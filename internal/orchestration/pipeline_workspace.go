@@ -21,7 +21,11 @@ func (p *Pipeline) setupBuildWorkspace(projectDir, appName string, plan *Planner
 		return fmt.Errorf("failed to enrich CLAUDE.md: %w", err)
 	}
 
-	if err := writeCoreRules(projectDir, plan.GetPlatform(), plan.Packages); err != nil {
+	packages, err := p.packageRegistry()
+	if err != nil {
+		return err
+	}
+	if err := writeCoreRules(projectDir, plan.GetPlatform(), plan.Packages, packages); err != nil {
 		return fmt.Errorf("failed to write core rules: %w", err)
 	}
 
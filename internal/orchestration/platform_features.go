@@ -62,6 +62,9 @@ var tvOSUnsupportedExtensionKinds = map[string]bool{
 	"safari":               true,
 	"app_clip":             true,
 	"widget":               true,
+	"control_widget":       true,
+	"spotlight":            true,
+	"notification_content": true,
 }
 
 // visionOSUnsupportedRuleKeys lists rule_keys that are not supported on visionOS.
@@ -88,6 +91,8 @@ var visionOSUnsupportedExtensionKinds = map[string]bool{
 	"notification_service": true,
 	"safari":               true,
 	"app_clip":             true,
+	"control_widget":       true,
+	"notification_content": true,
 }
 
 // watchOSUnsupportedExtensionKinds lists extension kinds not available on watchOS.
@@ -97,6 +102,8 @@ var watchOSUnsupportedExtensionKinds = map[string]bool{
 	"notification_service": true,
 	"safari":               true,
 	"app_clip":             true,
+	"spotlight":            true,
+	"notification_content": true,
 }
 
 // macOSUnsupportedRuleKeys lists rule_keys that are not supported on macOS.
@@ -115,9 +122,10 @@ var macOSConditionalRuleKeys = map[string]string{
 
 // macOSUnsupportedExtensionKinds lists extension kinds not available on macOS.
 var macOSUnsupportedExtensionKinds = map[string]bool{
-	"live_activity": true,
-	"app_clip":      true,
-	"safari":        true,
+	"live_activity":        true,
+	"app_clip":             true,
+	"safari":               true,
+	"notification_content": true,
 }
 
 // ValidatePlatform checks that the platform string is a known value.
@@ -326,19 +334,19 @@ func ValidateExtensionsForPlatform(platform string, extensions []ExtensionPlan)
 	case IsWatchOS(platform):
 		unsupportedKinds = watchOSUnsupportedExtensionKinds
 		platformName = "watchOS"
-		supportedNote = "only widget is supported"
+		supportedNote = "widget, control_widget, and app_intents are supported"
 	case IsTvOS(platform):
 		unsupportedKinds = tvOSUnsupportedExtensionKinds
 		platformName = "tvOS"
-		supportedNote = "only tv-top-shelf is supported"
+		supportedNote = "only tv-top-shelf and app_intents are supported"
 	case IsVisionOS(platform):
 		unsupportedKinds = visionOSUnsupportedExtensionKinds
 		platformName = "visionOS"
-		supportedNote = "only widget is supported"
+		supportedNote = "widget, app_intents, and spotlight are supported"
 	case IsMacOS(platform):
 		unsupportedKinds = macOSUnsupportedExtensionKinds
 		platformName = "macOS"
-		supportedNote = "widget, share, and notification_service are supported"
+		supportedNote = "widget, control_widget, app_intents, spotlight, share, and notification_service are supported"
 	default:
 		return nil
 	}
@@ -1,6 +1,7 @@
 package orchestration
 
 import (
+	"slices"
 	"strings"
 	"testing"
 )
@@ -585,3 +586,26 @@ func TestPlatformXcodegenValueMacOS(t *testing.T) {
 		t.Fatalf("PlatformXcodegenValue(macos) = %q, want %q", got, "macOS")
 	}
 }
+
+func TestValidateExtensionsForPlatformSystemExtensionKinds(t *testing.T) {
+	supported := map[string][]string{
+		"ios":      {"app_intents", "control_widget", "spotlight", "notification_content"},
+		"macos":    {"app_intents", "control_widget", "spotlight"},
+		"watchos":  {"app_intents", "control_widget"},
+		"visionos": {"app_intents", "spotlight"},
+		"tvos":     {"app_intents"},
+	}
+	all := []string{"app_intents", "control_widget", "spotlight", "notification_content"}
+	for platform, kinds := range supported {
+		for _, kind := range all {
+			err := ValidateExtensionsForPlatform(platform, []ExtensionPlan{{Kind: kind}})
+			want := slices.Contains(kinds, kind)
+			if want && err != nil {
+				t.Errorf("%s should support extension kind %q, got: %v", platform, kind, err)
+			}
+			if !want && err == nil {
+				t.Errorf("%s should reject extension kind %q", platform, kind)
+			}
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package orchestration
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/moasq/nanowave/internal/privacy"
+)
+
+// WritePrivacyManifest analyzes the project and writes PrivacyInfo.xcprivacy
+// into the app target, with the App Privacy answers and the evidence report
+// under privacy/. integrationIDs are the app's configured integrations.
+func WritePrivacyManifest(projectDir string, integrationIDs []string) (*privacy.Report, error) {
+	in := privacy.Inputs{Integrations: integrationIDs}
+	if data, err := os.ReadFile(filepath.Join(projectDir, "project_config.json")); err == nil {
+		var cfg projectConfigFile
+		if json.Unmarshal(data, &cfg) == nil {
+			for _, perm := range cfg.Permissions {
+				in.Permissions = append(in.Permissions, perm.Key)
+			}
+			for _, pkg := range cfg.Packages {
+				in.Packages = append(in.Packages, pkg.Name)
+			}
+		}
+	}
+	report, err := privacy.Analyze(projectDir, in)
+	if err != nil {
+		return nil, fmt.Errorf("analyze privacy: %w", err)
+	}
+	if _, err := report.Write(projectDir, ReadProjectAppName(projectDir)); err != nil {
+		return nil, fmt.Errorf("write privacy manifest: %w", err)
+	}
+	return report, nil
+}
+
+// writePrivacyManifest runs WritePrivacyManifest with the integrations
+// configured for the project's app.
+func (p *Pipeline) writePrivacyManifest(projectDir string) (*privacy.Report, error) {
+	var ids []string
+	if p.manager != nil {
+		for _, ap := range p.manager.ResolveExisting(ReadProjectAppName(projectDir)) {
+			ids = append(ids, string(ap.Provider.ID()))
+		}
+	}
+	return WritePrivacyManifest(projectDir, ids)
+}
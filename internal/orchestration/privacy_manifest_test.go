@@ -0,0 +1,42 @@
+package orchestration
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/moasq/nanowave/internal/privacy"
+)
+
+func TestWritePrivacyManifestUsesProjectConfig(t *testing.T) {
+	projectDir := t.TempDir()
+	config := `{"app_name": "Habits", "bundle_id": "com.example.habits",
+  "permissions": [{"key": "NSCameraUsageDescription", "description": "Scan receipts", "framework": "AVFoundation"}],
+  "packages": [{"name": "sentry-cocoa"}]}`
+	if err := os.WriteFile(filepath.Join(projectDir, "project_config.json"), []byte(config), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(projectDir, "Habits"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(projectDir, "Habits", "App.swift"), []byte("import SwiftUI\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := WritePrivacyManifest(projectDir, []string{"supabase"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := strings.Join(report.Summary(), "\n")
+	for _, want := range []string{"Photos or Videos: App Functionality", "Crash Data: App Functionality"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("summary missing %q:\n%s", want, got)
+		}
+	}
+	for _, file := range []string{filepath.Join("Habits", privacy.ManifestFile), privacy.AppPrivacyFile, privacy.ReportFile} {
+		if _, err := os.Stat(filepath.Join(projectDir, file)); err != nil {
+			t.Errorf("%s not written: %v", file, err)
+		}
+	}
+}
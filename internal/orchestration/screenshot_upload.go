@@ -6,6 +6,7 @@ import (
 	"log"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/moasq/nanowave/internal/screenshots"
@@ -14,7 +15,8 @@ import (
 
 // checkScreenshots checks whether the project has existing screenshots and validates them.
 // Returns whether screenshots were found, the count, the directory path, and validation results.
-func (p *Pipeline) checkScreenshots(projectDir, deviceFamily string) (found bool, count int, dir string, fulfilled []string, missing []string) {
+// Locales missing a required type are reported in missing as "TYPE (locale)".
+func (p *Pipeline) checkScreenshots(projectDir string, reqs screenshots.ScreenshotRequirements) (found bool, count int, dir string, fulfilled []string, missing []string) {
 	dir = screenshots.FindScreenshotDir(projectDir)
 	if dir == "" {
 		return false, 0, "", nil, nil
@@ -25,23 +27,35 @@ func (p *Pipeline) checkScreenshots(projectDir, deviceFamily string) (found bool
 	}
 	log.Printf("[asc][screenshots] found %d screenshots in %s", len(list), dir)
 
-	reqs := screenshots.RequirementsForFamily(deviceFamily)
-	fulfilled, missing = screenshots.ValidateScreenshots(dir, reqs)
+	fulfilled, missing, localeGaps := screenshots.ValidateScreenshots(dir, reqs)
+	locales := make([]string, 0, len(localeGaps))
+	for locale := range localeGaps {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+	for _, locale := range locales {
+		for _, deviceType := range localeGaps[locale] {
+			missing = append(missing, fmt.Sprintf("%s (%s)", deviceType, locale))
+		}
+	}
 	return true, len(list), dir, fulfilled, missing
 }
 
-// offerScreenshotOptions shows a 3-option picker for screenshot acquisition.
+// offerScreenshotOptions shows a picker for screenshot acquisition. Automatic
+// capture is offered for iOS apps only.
 // Returns the screenshot directory path and optional capture result.
-func (p *Pipeline) offerScreenshotOptions(ctx context.Context, projectDir, deviceFamily string) (dir string, captureResult *screenshots.CaptureResult) {
-	log.Printf("[asc] offering screenshot options (deviceFamily=%s)", deviceFamily)
+func (p *Pipeline) offerScreenshotOptions(ctx context.Context, projectDir string, reqs screenshots.ScreenshotRequirements) (dir string, captureResult *screenshots.CaptureResult) {
+	log.Printf("[asc] offering screenshot options (platform=%s, deviceFamily=%s)", reqs.Platform, reqs.DeviceFamily)
 
-	picked := terminal.Pick("App screenshots", []terminal.PickerOption{
-		{Label: "Automatic", Desc: "Build and capture from simulator (recommended)"},
-		{Label: "Custom", Desc: "Upload your own screenshots in browser"},
-		{Label: "Skip", Desc: "Continue without screenshots"},
-	}, "")
-
-	reqs := screenshots.RequirementsForFamily(deviceFamily)
+	var options []terminal.PickerOption
+	if reqs.Platform == "ios" {
+		options = append(options, terminal.PickerOption{Label: "Automatic", Desc: "Build and capture from simulator (recommended)"})
+	}
+	options = append(options,
+		terminal.PickerOption{Label: "Custom", Desc: "Upload your own screenshots in browser"},
+		terminal.PickerOption{Label: "Skip", Desc: "Continue without screenshots"},
+	)
+	picked := terminal.Pick("App screenshots", options, "")
 
 	switch picked {
 	case "Automatic":
@@ -95,6 +109,12 @@ func (p *Pipeline) offerScreenshotOptions(ctx context.Context, projectDir, devic
 			}
 		}
 
+		if framed, err := FrameScreenshots(projectDir); err != nil {
+			log.Printf("[asc] framing failed: %v", err)
+			terminal.Warning(fmt.Sprintf("Could not frame screenshots: %v", err))
+		} else if len(framed) > 0 && confirmFramedUpload(projectDir, len(framed)) {
+			return filepath.Join(projectDir, screenshots.FramedDir), cr
+		}
 		return cr.Dir, cr
 
 	case "Custom":
@@ -105,6 +125,19 @@ func (p *Pipeline) offerScreenshotOptions(ctx context.Context, projectDir, devic
 	}
 }
 
+// confirmFramedUpload asks whether to upload the framed screenshots or the raw
+// captures. Framing runs with the layout's defaults, so captions are empty until
+// the user writes them. Cancelling keeps the raw captures.
+func confirmFramedUpload(projectDir string, count int) bool {
+	terminal.Success(fmt.Sprintf("Framed %d screenshot(s) into %s", count, filepath.Join(projectDir, screenshots.FramedDir)))
+	terminal.Info(fmt.Sprintf("Captions are empty until you write them in %s; run `nanowave screenshots frame` after editing it", screenshots.LayoutFile))
+	picked := terminal.Pick("Screenshots to upload", []terminal.PickerOption{
+		{Label: "Framed", Desc: "Upload the framed screenshots"},
+		{Label: "Raw captures", Desc: "Upload the simulator captures as they are"},
+	}, "")
+	return picked == "Framed"
+}
+
 func (p *Pipeline) offerCustomUpload(ctx context.Context, projectDir string, reqs screenshots.ScreenshotRequirements) (string, *screenshots.CaptureResult) {
 	uploadDir := filepath.Join(projectDir, "screenshots", "upload")
 	if err := os.MkdirAll(uploadDir, 0o755); err != nil {
@@ -118,3 +151,41 @@ func (p *Pipeline) offerCustomUpload(ctx context.Context, projectDir string, req
 	}
 	return "", nil
 }
+
+// FrameScreenshots composes the project's raw captures into
+// screenshots/nanowave using screenshots/layout.json. The layout is created on
+// first use with a background from the design palette, and captures it doesn't
+// frame yet are added to it. Returns the written files.
+func FrameScreenshots(projectDir string) ([]string, error) {
+	layout, err := screenshots.LoadLayout(projectDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var sources []string
+	for _, s := range screenshots.ListScreenshots(filepath.Join(projectDir, "screenshots", "auto")) {
+		sources = append(sources, filepath.ToSlash(filepath.Join("screenshots", "auto", s.Filename)))
+	}
+	switch {
+	case layout == nil:
+		if len(sources) == 0 {
+			return nil, nil
+		}
+		from, to := defaultIconPrimary, defaultIconAccent
+		if design := readDesignFile(projectDir); design != nil {
+			from, to = design.Palette.Primary, design.Palette.Accent
+		}
+		layout = screenshots.DefaultLayout(sources, from, to)
+		if err := screenshots.SaveLayout(projectDir, layout); err != nil {
+			return nil, err
+		}
+	case layout.AddScreens(sources):
+		if err := screenshots.SaveLayout(projectDir, layout); err != nil {
+			return nil, err
+		}
+	}
+	if len(layout.Screens) == 0 {
+		return nil, nil
+	}
+	return screenshots.Frame(projectDir, layout)
+}
@@ -202,6 +202,8 @@ func writeClaudeMemoryFiles(projectDir, appName, platform, deviceFamily string,
 	xg.WriteString("## Required Workflow\n")
 	xg.WriteString("- Use xcodegen MCP tools for project configuration changes\n")
 	xg.WriteString("- Preferred tools: `add_permission`, `add_extension`, `add_entitlement`, `add_localization`, `add_package`, `set_build_setting`, `get_project_config`, `regenerate_project`\n")
+	xg.WriteString("- To undo a change: `remove_permission`, `remove_extension`, `remove_entitlement`, `remove_package`, `update_package_version`, `remove_localization`, `unset_build_setting` — never hand-edit `project_config.json`\n")
+	xg.WriteString("- Per-environment values (Staging/Beta builds, backend URLs, display names, icons): `add_configuration`, `set_configuration_value` — never hardcode environment URLs in Swift\n")
 	xg.WriteString("- Do not manually edit `.xcodeproj`\n")
 	xg.WriteString("- Avoid manual `project.yml` edits unless explicitly doing emergency recovery, then run `regenerate_project`\n")
 	xg.WriteString("\n## Files\n")
@@ -334,6 +336,14 @@ func writeClaudeMemoryFiles(projectDir, appName, platform, deviceFamily string,
 				fmt.Fprintf(&planDoc, "- `%s`\n", p)
 			}
 		}
+		planDoc.WriteString("\n## Tests\n")
+		if testFiles := plan.TestFiles(appName); len(testFiles) == 0 {
+			planDoc.WriteString("- None\n")
+		} else {
+			for _, f := range testFiles {
+				fmt.Fprintf(&planDoc, "- `%s` (%s)\n", f.Path, f.TypeName)
+			}
+		}
 		planDoc.WriteString("\n## Permissions\n")
 		if len(plan.Permissions) == 0 {
 			planDoc.WriteString("- None\n")
@@ -582,7 +592,17 @@ tools:
   - mcp__xcodegen__add_extension
   - mcp__xcodegen__add_entitlement
   - mcp__xcodegen__add_localization
+  - mcp__xcodegen__add_url_scheme
   - mcp__xcodegen__set_build_setting
+  - mcp__xcodegen__remove_permission
+  - mcp__xcodegen__remove_extension
+  - mcp__xcodegen__remove_entitlement
+  - mcp__xcodegen__remove_package
+  - mcp__xcodegen__update_package_version
+  - mcp__xcodegen__remove_localization
+  - mcp__xcodegen__unset_build_setting
+  - mcp__xcodegen__add_configuration
+  - mcp__xcodegen__set_configuration_value
   - mcp__xcodegen__regenerate_project
 model: sonnet
 ---
@@ -708,7 +728,7 @@ Keep Apple docs MCP plus web fallback as the default recovery path.
 func writeProjectMakefileWithShape(projectDir, appName, platform, watchProjectShape string) error {
 	buildCmd := canonicalBuildCommandForShape(appName, platform, watchProjectShape)
 	destination := canonicalBuildDestinationForShape(platform, watchProjectShape)
-	content := fmt.Sprintf(".PHONY: build test claude-check mcp-health skills-validate\n\nbuild:\n\t%s\n\nmcp-health:\n\t./scripts/claude/mcp-health.sh\n\nskills-validate:\n\t./scripts/claude/validate-skills.sh\n\nclaude-check:\n\t-./scripts/claude/mcp-health.sh\n\tplutil -lint .mcp.json >/dev/null\n\tplutil -lint .claude/settings.json >/dev/null\n\t./scripts/claude/validate-skills.sh\n\t./scripts/claude/check-no-placeholders.sh\n\t./scripts/claude/check-previews.sh\n\t./scripts/claude/check-swift-structure.sh\n\t./scripts/claude/check-a11y-dynamic-type.sh\n\t./scripts/claude/check-a11y-icon-buttons.sh\n\t./scripts/claude/check-project-config-edits.sh --scan || true\n\t./scripts/claude/run-build-check.sh\n\ntest:\n\t@if [ -d Tests ]; then \\\n\t\techo \"Tests directory found; running xcodebuild test\"; \\\n\t\txcodebuild -project %s.xcodeproj -scheme %s -destination '%s' -quiet test; \\\n\telse \\\n\t\techo \"No Tests directory present; skipping tests\"; \\\n\tfi\n", buildCmd, appName, appName, destination)
+	content := fmt.Sprintf(".PHONY: build test claude-check mcp-health skills-validate\n\nbuild:\n\t%s\n\nmcp-health:\n\t./scripts/claude/mcp-health.sh\n\nskills-validate:\n\t./scripts/claude/validate-skills.sh\n\nclaude-check:\n\t-./scripts/claude/mcp-health.sh\n\tplutil -lint .mcp.json >/dev/null\n\tplutil -lint .claude/settings.json >/dev/null\n\t./scripts/claude/validate-skills.sh\n\t./scripts/claude/check-no-placeholders.sh\n\t./scripts/claude/check-previews.sh\n\t./scripts/claude/check-swift-structure.sh\n\t./scripts/claude/check-a11y-dynamic-type.sh\n\t./scripts/claude/check-a11y-icon-buttons.sh\n\t./scripts/claude/check-project-config-edits.sh --scan || true\n\t./scripts/claude/run-build-check.sh\n\ntest:\n\t@if [ -d %sTests ] || [ -d %sUITests ]; then \\\n\t\techo \"Test targets found; running xcodebuild test\"; \\\n\t\txcodebuild -project %s.xcodeproj -scheme %s -destination '%s' -quiet test; \\\n\telse \\\n\t\techo \"No test targets present; skipping tests\"; \\\n\tfi\n", buildCmd, appName, appName, appName, appName, destination)
 	return writeTextFile(filepath.Join(projectDir, "Makefile"), content, 0o644)
 }
 
@@ -750,12 +770,12 @@ jobs:
 
       - name: Optional tests (if present)
         run: |
-          if [ -d Tests ]; then
+          if [ -d %sTests ] || [ -d %sUITests ]; then
             xcodebuild -project %s.xcodeproj -scheme %s -destination '%s' -quiet test
           else
-            echo "No Tests directory present; skipping tests"
+            echo "No test targets present; skipping tests"
           fi
-`, appName, appName, destination)
+`, appName, appName, appName, appName, destination)
 	return writeTextFile(filepath.Join(projectDir, ".github", "workflows", "claude-quality.yml"), content, 0o644)
 }
 
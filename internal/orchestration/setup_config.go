@@ -6,6 +6,9 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+
+	"github.com/moasq/nanowave/internal/packageregistry"
+	"github.com/moasq/nanowave/internal/xcodegen"
 )
 
 // writeSettingsLocal writes local (non-committed) Claude Code settings for machine-specific overrides.
@@ -184,51 +187,99 @@ func writeAssetCatalog(projectDir, appName, platform string) error {
 	return os.WriteFile(filepath.Join(accentColorDir, "Contents.json"), []byte(accentColorContents), 0o644)
 }
 
+// projectConfigFile is the on-disk shape of project_config.json, mirrored by the
+// xcodegen MCP server's ProjectConfig.
+type projectConfigFile struct {
+	AppName               string              `json:"app_name"`
+	BundleID              string              `json:"bundle_id"`
+	Platform              string              `json:"platform,omitempty"`
+	Platforms             []string            `json:"platforms,omitempty"`
+	WatchProjectShape     string              `json:"watch_project_shape,omitempty"`
+	DeviceFamily          string              `json:"device_family,omitempty"`
+	Permissions           []configPermission  `json:"permissions,omitempty"`
+	Extensions            []configExtension   `json:"extensions,omitempty"`
+	Localizations         []string            `json:"localizations,omitempty"`
+	Entitlements          []configEntitlement `json:"entitlements,omitempty"`
+	BuildSettings         map[string]string   `json:"build_settings,omitempty"`
+	Packages              []configPackage     `json:"packages,omitempty"`
+	InterfaceStyle        string              `json:"interface_style,omitempty"`
+	StoreKitConfiguration string              `json:"storekit_configuration,omitempty"`
+	UnitTests             bool                `json:"unit_tests,omitempty"`
+	UITests               bool                `json:"ui_tests,omitempty"`
+	URLSchemes            []string            `json:"url_schemes,omitempty"`
+	MarketingVersion      string              `json:"marketing_version,omitempty"`
+	BuildNumber           int                 `json:"build_number,omitempty"`
+	// Source is "project.yml" for adopted projects, whose hand-written project.yml
+	// stays the source of truth and is edited in place.
+	Source string `json:"source,omitempty"`
+}
+
+type configPermission struct {
+	Key         string `json:"key"`
+	Description string `json:"description"`
+	Framework   string `json:"framework"`
+}
+
+type configExtension struct {
+	Kind         string            `json:"kind"`
+	Name         string            `json:"name"`
+	Purpose      string            `json:"purpose"`
+	Platform     string            `json:"platform,omitempty"`
+	InfoPlist    map[string]any    `json:"info_plist,omitempty"`
+	Entitlements map[string]any    `json:"entitlements,omitempty"`
+	Settings     map[string]string `json:"settings,omitempty"`
+}
+
+type configEntitlement struct {
+	Key    string `json:"key"`
+	Value  any    `json:"value"`
+	Target string `json:"target,omitempty"`
+}
+
+type configPackage struct {
+	Name         string   `json:"name"`
+	URL          string   `json:"url,omitempty"`
+	MinVersion   string   `json:"min_version,omitempty"`
+	ExactVersion string   `json:"exact_version,omitempty"`
+	MaxVersion   string   `json:"max_version,omitempty"`
+	Path         string   `json:"path,omitempty"`
+	Products     []string `json:"products,omitempty"`
+}
+
 // writeProjectConfig writes project_config.json from the PlannerResult.
 // This is the source of truth that the xcodegen MCP server reads/writes.
-func writeProjectConfig(projectDir string, plan *PlannerResult, appName string) error {
-	bundleID := fmt.Sprintf("%s.%s", bundleIDPrefix(), strings.ToLower(appName))
-
-	type permission struct {
-		Key         string `json:"key"`
-		Description string `json:"description"`
-		Framework   string `json:"framework"`
-	}
-	type extensionPlan struct {
-		Kind         string            `json:"kind"`
-		Name         string            `json:"name"`
-		Purpose      string            `json:"purpose"`
-		InfoPlist    map[string]any    `json:"info_plist,omitempty"`
-		Entitlements map[string]any    `json:"entitlements,omitempty"`
-		Settings     map[string]string `json:"settings,omitempty"`
-	}
-	type projectConfig struct {
-		AppName           string            `json:"app_name"`
-		BundleID          string            `json:"bundle_id"`
-		Platform          string            `json:"platform,omitempty"`
-		Platforms         []string          `json:"platforms,omitempty"`
-		WatchProjectShape string            `json:"watch_project_shape,omitempty"`
-		DeviceFamily      string            `json:"device_family,omitempty"`
-		Permissions       []permission      `json:"permissions,omitempty"`
-		Extensions        []extensionPlan   `json:"extensions,omitempty"`
-		Localizations     []string          `json:"localizations,omitempty"`
-		BuildSettings     map[string]string `json:"build_settings,omitempty"`
-	}
-
-	cfg := projectConfig{
-		AppName:           appName,
-		BundleID:          bundleID,
-		Platform:          plan.GetPlatform(),
-		WatchProjectShape: plan.GetWatchProjectShape(),
-		DeviceFamily:      plan.GetDeviceFamily(),
-	}
-	if plan.IsMultiPlatform() {
-		cfg.Platforms = plan.GetPlatforms()
+func writeProjectConfig(projectDir string, plan *PlannerResult, appName string, registry *packageregistry.Registry) error {
+	model := projectModel(appName, plan, nil, registry)
+
+	cfg := projectConfigFile{
+		AppName:               appName,
+		BundleID:              model.BundleID,
+		Platform:              plan.GetPlatform(),
+		Platforms:             model.Platforms,
+		WatchProjectShape:     plan.GetWatchProjectShape(),
+		DeviceFamily:          plan.GetDeviceFamily(),
+		InterfaceStyle:        model.InterfaceStyle,
+		StoreKitConfiguration: model.StoreKitConfiguration,
+		UnitTests:             model.UnitTests,
+		UITests:               model.UITests,
+		MarketingVersion:      "1.0",
+		BuildNumber:           1,
+	}
+	for _, pkg := range model.Packages {
+		cfg.Packages = append(cfg.Packages, configPackage{
+			Name:         pkg.Name,
+			URL:          pkg.URL,
+			MinVersion:   pkg.MinVersion,
+			ExactVersion: pkg.ExactVersion,
+			MaxVersion:   pkg.MaxVersion,
+			Path:         pkg.Path,
+			Products:     pkg.Products,
+		})
 	}
 
 	if plan != nil {
 		for _, p := range plan.Permissions {
-			cfg.Permissions = append(cfg.Permissions, permission{
+			cfg.Permissions = append(cfg.Permissions, configPermission{
 				Key:         p.Key,
 				Description: p.Description,
 				Framework:   p.Framework,
@@ -236,10 +287,11 @@ func writeProjectConfig(projectDir string, plan *PlannerResult, appName string)
 		}
 		for _, ext := range plan.Extensions {
 			name := extensionTargetName(ext, appName)
-			cfg.Extensions = append(cfg.Extensions, extensionPlan{
+			cfg.Extensions = append(cfg.Extensions, configExtension{
 				Kind:         ext.Kind,
 				Name:         name,
 				Purpose:      ext.Purpose,
+				Platform:     ext.Platform,
 				InfoPlist:    ext.InfoPlist,
 				Entitlements: ext.Entitlements,
 				Settings:     ext.Settings,
@@ -248,6 +300,45 @@ func writeProjectConfig(projectDir string, plan *PlannerResult, appName string)
 		cfg.Localizations = plan.Localizations
 	}
 
+	return saveProjectConfigFile(projectDir, &cfg)
+}
+
+// designFilePath is where the plan's design system is kept for steps that run
+// after the build, such as generating an app icon from its palette.
+const designFilePath = ".nanowave/design.json"
+
+// writeDesignFile saves the plan's design system to .nanowave/design.json.
+func writeDesignFile(projectDir string, plan *PlannerResult) error {
+	if plan == nil {
+		return nil
+	}
+	data, err := json.MarshalIndent(plan.Design, "", "  ")
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(projectDir, designFilePath)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// readDesignFile loads the design system saved by writeDesignFile, or nil when
+// the project has none.
+func readDesignFile(projectDir string) *DesignSystem {
+	data, err := os.ReadFile(filepath.Join(projectDir, designFilePath))
+	if err != nil {
+		return nil
+	}
+	var design DesignSystem
+	if json.Unmarshal(data, &design) != nil {
+		return nil
+	}
+	return &design
+}
+
+// saveProjectConfigFile writes project_config.json to the project directory.
+func saveProjectConfigFile(projectDir string, cfg *projectConfigFile) error {
 	data, err := json.MarshalIndent(cfg, "", "  ")
 	if err != nil {
 		return fmt.Errorf("failed to marshal project config: %w", err)
@@ -332,11 +423,24 @@ func readConfigEntitlements(projectDir, target string) map[string]any {
 
 // writeProjectYML writes the XcodeGen project.yml, including extension targets if present.
 // entitlements is a map of main-app-target entitlements to embed in project.yml properties.
-func writeProjectYML(projectDir string, plan *PlannerResult, appName string, entitlements map[string]any) error {
-	yml := generateProjectYAML(appName, plan, entitlements)
+func writeProjectYML(projectDir string, plan *PlannerResult, appName string, entitlements map[string]any, registry *packageregistry.Registry) error {
+	yml := generateProjectYAML(appName, plan, entitlements, registry)
 	return os.WriteFile(filepath.Join(projectDir, "project.yml"), []byte(yml), 0o644)
 }
 
+// testTargetNames returns the test bundle targets of the plan, which are also
+// their source directories.
+func testTargetNames(appName string, plan *PlannerResult) []string {
+	var names []string
+	if plan.HasUnitTests() {
+		names = append(names, xcodegen.UnitTestTargetName(appName))
+	}
+	if plan.HasUITests() {
+		names = append(names, xcodegen.UITestTargetName(appName))
+	}
+	return names
+}
+
 // scaffoldSourceDirs creates the directory structure that XcodeGen expects before generating
 // the .xcodeproj. This ensures all source paths referenced in project.yml actually exist.
 func scaffoldSourceDirs(projectDir, appName string, plan *PlannerResult) error {
@@ -376,6 +480,16 @@ func scaffoldSourceDirs(projectDir, appName string, plan *PlannerResult) error {
 		for _, lang := range plan.Localizations {
 			dirs = append(dirs, filepath.Join(projectDir, appName, lang+".lproj"))
 		}
+
+		// {AppName}Tests/ and {AppName}UITests/ for planned tests
+		for _, name := range testTargetNames(appName, plan) {
+			dirs = append(dirs, filepath.Join(projectDir, name))
+		}
+
+		// Packages/{Name}/Sources/{Name}/ per local package
+		for _, pkg := range plan.LocalPackages {
+			dirs = append(dirs, filepath.Join(projectDir, filepath.FromSlash(localPackageSourceDir(pkg.Name))))
+		}
 	}
 
 	for _, d := range dirs {
@@ -413,6 +527,12 @@ func scaffoldSourceDirs(projectDir, appName string, plan *PlannerResult) error {
 			name := extensionTargetName(ext, appName)
 			placeholders = append(placeholders, placeholderEntry{filepath.Join(projectDir, "Targets", name), "Placeholder.swift"})
 		}
+		for _, name := range testTargetNames(appName, plan) {
+			placeholders = append(placeholders, placeholderEntry{filepath.Join(projectDir, name), "Placeholder.swift"})
+		}
+		for _, pkg := range plan.LocalPackages {
+			placeholders = append(placeholders, placeholderEntry{filepath.Join(projectDir, filepath.FromSlash(localPackageSourceDir(pkg.Name))), "Placeholder.swift"})
+		}
 	}
 
 	placeholderContent := []byte("// Placeholder — replaced by generated code\nimport Foundation\n")
@@ -449,6 +569,12 @@ func cleanupScaffoldPlaceholders(projectDir, appName string, plan *PlannerResult
 			name := extensionTargetName(ext, appName)
 			candidates = append(candidates, filepath.Join(projectDir, "Targets", name, "Placeholder.swift"))
 		}
+		for _, name := range testTargetNames(appName, plan) {
+			candidates = append(candidates, filepath.Join(projectDir, name, "Placeholder.swift"))
+		}
+		for _, pkg := range plan.LocalPackages {
+			candidates = append(candidates, filepath.Join(projectDir, filepath.FromSlash(localPackageSourceDir(pkg.Name)), "Placeholder.swift"))
+		}
 	}
 	for _, p := range candidates {
 		if _, err := os.Stat(p); err != nil {
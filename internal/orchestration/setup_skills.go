@@ -7,6 +7,8 @@ import (
 	"path/filepath"
 	"sort"
 	"strings"
+
+	"github.com/moasq/nanowave/internal/packageregistry"
 )
 
 // conditionalCategories lists embedded directories searched for conditional skill keys.
@@ -14,8 +16,9 @@ var conditionalCategories = []string{"features", "ui", "extensions"}
 
 // writeCoreRules copies skills/core/*.md to projectDir/.claude/rules/ (always loaded eagerly).
 // Platform-specific content in swift-conventions.md is adapted to the target platform.
-// Planner-approved packages are injected into forbidden-patterns.md.
-func writeCoreRules(projectDir, platform string, packages []PackagePlan) error {
+// Planner-approved packages are injected into forbidden-patterns.md, with their
+// details from registry.
+func writeCoreRules(projectDir, platform string, packages []PackagePlan, registry *packageregistry.Registry) error {
 	rulesDir := filepath.Join(projectDir, ".claude", "rules")
 
 	entries, err := fs.ReadDir(skillsFS, "skills/core")
@@ -55,11 +58,11 @@ func writeCoreRules(projectDir, platform string, packages []PackagePlan) error {
 				sb.WriteString("The planner approved the following packages. Integrate each one:\n\n")
 				for _, pkg := range packages {
 					// Enrich with registry details when available
-					if curated := LookupPackageByName(pkg.Name); curated != nil {
+					if curated := registry.LookupByName(pkg.Name); curated != nil {
 						sb.WriteString(fmt.Sprintf("- **%s** — %s\n", curated.Name, pkg.Reason))
 						sb.WriteString(fmt.Sprintf("  - URL: %s\n", curated.RepoURL))
 						sb.WriteString(fmt.Sprintf("  - XcodeGen key: `%s`\n", curated.RepoName))
-						sb.WriteString(fmt.Sprintf("  - Version: `from: \"%s\"`\n", curated.MinVersion))
+						sb.WriteString(fmt.Sprintf("  - Version: %s\n", curated.VersionSummary()))
 						sb.WriteString(fmt.Sprintf("  - Import: `%s`\n", strings.Join(curated.Products, "`, `")))
 					} else {
 						sb.WriteString(fmt.Sprintf("- **%s** — %s\n", pkg.Name, pkg.Reason))
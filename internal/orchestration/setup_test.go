@@ -17,7 +17,7 @@ func TestWriteCoreRules(t *testing.T) {
 		t.Fatalf("failed to create rules dir: %v", err)
 	}
 
-	if err := writeCoreRules(projectDir, PlatformIOS, nil); err != nil {
+	if err := writeCoreRules(projectDir, PlatformIOS, nil, defaultPackages(t)); err != nil {
 		t.Fatalf("writeCoreRules() error: %v", err)
 	}
 
@@ -48,7 +48,7 @@ func TestWriteCoreRulesMacOS(t *testing.T) {
 		t.Fatalf("failed to create rules dir: %v", err)
 	}
 
-	if err := writeCoreRules(projectDir, PlatformMacOS, nil); err != nil {
+	if err := writeCoreRules(projectDir, PlatformMacOS, nil, defaultPackages(t)); err != nil {
 		t.Fatalf("writeCoreRules(macOS) error: %v", err)
 	}
 
@@ -79,7 +79,7 @@ func TestWriteCoreRulesWithPackages(t *testing.T) {
 		{Name: "Lottie", Reason: "Complex vector animations not achievable with native SwiftUI"},
 	}
 
-	if err := writeCoreRules(projectDir, PlatformIOS, packages); err != nil {
+	if err := writeCoreRules(projectDir, PlatformIOS, packages, defaultPackages(t)); err != nil {
 		t.Fatalf("writeCoreRules(with packages) error: %v", err)
 	}
 
@@ -110,7 +110,7 @@ func TestWriteCoreRulesNoPackages(t *testing.T) {
 		t.Fatalf("failed to create rules dir: %v", err)
 	}
 
-	if err := writeCoreRules(projectDir, PlatformIOS, nil); err != nil {
+	if err := writeCoreRules(projectDir, PlatformIOS, nil, defaultPackages(t)); err != nil {
 		t.Fatalf("writeCoreRules(no packages) error: %v", err)
 	}
 
@@ -1220,6 +1220,33 @@ func TestWriteAlwaysSkillsVisionOS(t *testing.T) {
 	}
 }
 
+func TestIconStyleFromDesignFile(t *testing.T) {
+	projectDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(projectDir, "project_config.json"), []byte(`{"app_name":"HabitGrid"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	style := iconStyle(projectDir)
+	if style.AppName != "HabitGrid" {
+		t.Errorf("AppName = %q, want HabitGrid", style.AppName)
+	}
+	if want := iconColor(defaultIconPrimary, ""); style.Primary != want {
+		t.Errorf("Primary without a design = %v, want default %v", style.Primary, want)
+	}
+
+	plan := &PlannerResult{Design: DesignSystem{Palette: Palette{Primary: "#FF0000", Accent: "#00F"}}}
+	if err := writeDesignFile(projectDir, plan); err != nil {
+		t.Fatalf("writeDesignFile() error: %v", err)
+	}
+	style = iconStyle(projectDir)
+	if style.Primary.R != 255 || style.Primary.G != 0 || style.Primary.B != 0 {
+		t.Errorf("Primary = %v, want #FF0000", style.Primary)
+	}
+	if want := iconColor(defaultIconAccent, ""); style.Accent != want {
+		t.Errorf("Accent with an invalid hex = %v, want default %v", style.Accent, want)
+	}
+}
+
 func runClaudeScript(t *testing.T, projectDir, scriptName string, args ...string) (int, string) {
 	t.Helper()
 	cmdArgs := []string{filepath.Join(projectDir, "scripts", "claude", scriptName)}
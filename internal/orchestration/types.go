@@ -4,6 +4,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"strings"
+
+	"github.com/moasq/nanowave/internal/xcodegen"
 )
 
 // BuildResult is the final output of a successful Build pipeline.
@@ -89,11 +91,20 @@ type PlannerResult struct {
 	Localizations     []string        `json:"localizations"`
 	RuleKeys          []string        `json:"rule_keys"`
 	Packages          []PackagePlan   `json:"packages"`
+	LocalPackages     []LocalPackagePlan `json:"local_packages,omitempty"`
 	Integrations      []string          `json:"integrations,omitempty"`
 	MonetizationPlan  *MonetizationPlan `json:"monetization_plan,omitempty"`
+	Tests             *TestPlan         `json:"tests,omitempty"`
 	BuildOrder        []string          `json:"build_order"`
 }
 
+// TestPlan lists the planned test files. Paths are relative to the test
+// target's directory ({AppName}Tests/ or {AppName}UITests/).
+type TestPlan struct {
+	Unit []FilePlan `json:"unit"`
+	UI   []FilePlan `json:"ui"`
+}
+
 // GetDeviceFamily returns the device family, defaulting to "iphone" for iOS.
 // Non-iOS platforms (macOS, tvOS, visionOS, watchOS) return "" when unset.
 func (p *PlannerResult) GetDeviceFamily() string {
@@ -149,6 +160,37 @@ func (p *PlannerResult) GetWatchProjectShape() string {
 	return p.WatchProjectShape
 }
 
+// HasUnitTests returns true if the plan includes unit test files.
+func (p *PlannerResult) HasUnitTests() bool {
+	return p != nil && p.Tests != nil && len(p.Tests.Unit) > 0
+}
+
+// HasUITests returns true if the plan includes UI test files. watchOS apps get no UI test target.
+func (p *PlannerResult) HasUITests() bool {
+	return p != nil && p.Tests != nil && len(p.Tests.UI) > 0 && !(IsWatchOS(p.GetPlatform()) && !p.IsMultiPlatform())
+}
+
+// TestFiles returns the planned test files with paths relative to the project
+// directory, prefixed with their test target's directory.
+func (p *PlannerResult) TestFiles(appName string) []FilePlan {
+	var files []FilePlan
+	add := func(dir string, planned []FilePlan) {
+		for _, f := range planned {
+			if !strings.HasPrefix(f.Path, dir+"/") {
+				f.Path = dir + "/" + strings.TrimPrefix(f.Path, "/")
+			}
+			files = append(files, f)
+		}
+	}
+	if p.HasUnitTests() {
+		add(xcodegen.UnitTestTargetName(appName), p.Tests.Unit)
+	}
+	if p.HasUITests() {
+		add(xcodegen.UITestTargetName(appName), p.Tests.UI)
+	}
+	return files
+}
+
 // HasRuleKey returns true if the plan includes the given rule key.
 func (p *PlannerResult) HasRuleKey(key string) bool {
 	if p == nil {
@@ -195,10 +237,13 @@ type Palette struct {
 
 // FilePlan describes a single file in the build plan.
 type FilePlan struct {
-	Path       string   `json:"path"`
-	TypeName   string   `json:"type_name"`
-	Purpose    string   `json:"purpose"`
-	Platform   string   `json:"platform,omitempty"`
+	Path     string `json:"path"`
+	TypeName string `json:"type_name"`
+	Purpose  string `json:"purpose"`
+	Platform string `json:"platform,omitempty"`
+	// Package is the local package the file belongs to; Path is then relative
+	// to the package's source root (Packages/{Package}/Sources/{Package}/).
+	Package    string   `json:"package,omitempty"`
 	Components string   `json:"components"`
 	DataAccess string   `json:"data_access"`
 	DependsOn  []string `json:"depends_on"`
@@ -214,6 +259,7 @@ func (f *FilePlan) UnmarshalJSON(data []byte) error {
 		TypeAlias       string          `json:"type"`
 		Purpose         string          `json:"purpose"`
 		Platform        string          `json:"platform"`
+		Package         string          `json:"package"`
 		Components      json.RawMessage `json:"components"`
 		DataAccess      string          `json:"data_access"`
 		DataAccessCamel string          `json:"dataAccess"`
@@ -230,6 +276,7 @@ func (f *FilePlan) UnmarshalJSON(data []byte) error {
 	f.TypeName = firstNonEmpty(raw.TypeName, raw.TypeNameCamel, raw.TypeAlias)
 	f.Purpose = raw.Purpose
 	f.Platform = raw.Platform
+	f.Package = raw.Package
 	f.DataAccess = firstNonEmpty(raw.DataAccess, raw.DataAccessCamel)
 
 	components, err := decodeStringOrStringArray(raw.Components)
@@ -360,6 +407,16 @@ type Permission struct {
 	Framework   string `json:"framework"`
 }
 
+// LocalPackagePlan describes a local Swift package the planner splits the app
+// into (Models, Networking, DesignSystem, a feature module, ...). It lives in
+// Packages/{Name}/ and builds one library product named after it.
+type LocalPackagePlan struct {
+	Name    string `json:"name"`
+	Purpose string `json:"purpose"`
+	// DependsOn lists the other local packages this one imports.
+	DependsOn []string `json:"depends_on,omitempty"`
+}
+
 // PackagePlan describes an SPM package suggested by the planner.
 type PackagePlan struct {
 	Name   string `json:"name"`
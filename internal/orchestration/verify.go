@@ -25,27 +25,41 @@ func shouldRetryCompletion(report *FileCompletionReport, pass, maxPasses int) (b
 	return true, nil
 }
 
-// verifyPlannedFiles checks whether all planned files exist and satisfy minimal validity requirements.
+// verifyPlannedFiles checks whether all planned files, test files included,
+// exist and satisfy minimal validity requirements.
 func verifyPlannedFiles(projectDir, appName string, plan *PlannerResult) (*FileCompletionReport, error) {
 	if plan == nil {
 		return nil, fmt.Errorf("cannot verify file completion without a build plan")
 	}
 
+	testFiles := plan.TestFiles(appName)
 	report := &FileCompletionReport{
-		TotalPlanned: len(plan.Files),
+		TotalPlanned: len(plan.Files) + len(testFiles),
 	}
-	if len(plan.Files) == 0 {
+	if report.TotalPlanned == 0 {
 		report.Complete = true
 		return report, nil
 	}
 
 	isMulti := plan.IsMultiPlatform()
+	statuses := make([]PlannedFileStatus, 0, report.TotalPlanned)
 	for _, planned := range plan.Files {
-		status := PlannedFileStatus{
+		statuses = append(statuses, PlannedFileStatus{
 			PlannedPath:  planned.Path,
 			ResolvedPath: resolvePlannedFilePathWithPlatform(projectDir, appName, planned, isMulti),
 			ExpectedType: planned.TypeName,
-		}
+		})
+	}
+	// Test file paths already start with their test target's directory.
+	for _, planned := range testFiles {
+		statuses = append(statuses, PlannedFileStatus{
+			PlannedPath:  planned.Path,
+			ResolvedPath: filepath.Join(projectDir, filepath.Clean(filepath.FromSlash(planned.Path))),
+			ExpectedType: planned.TypeName,
+		})
+	}
+
+	for _, status := range statuses {
 
 		info, err := os.Stat(status.ResolvedPath)
 		if err != nil {
@@ -118,6 +132,9 @@ func resolvePlannedFilePath(projectDir, appName, plannedPath string) string {
 	if _, err := os.Stat(direct); err == nil {
 		return direct
 	}
+	if strings.HasPrefix(filepath.ToSlash(cleanPath), localPackagesDir+"/") {
+		return direct
+	}
 
 	return filepath.Join(projectDir, appName, cleanPath)
 }
@@ -135,6 +152,14 @@ func resolvePlannedFilePathWithPlatform(projectDir, appName string, planned File
 		return direct
 	}
 
+	// Files of a local package live under the package's source root.
+	if strings.HasPrefix(filepath.ToSlash(cleanPath), localPackagesDir+"/") {
+		return direct
+	}
+	if planned.Package != "" {
+		return filepath.Join(projectDir, filepath.FromSlash(localPackageSourceDir(planned.Package)), cleanPath)
+	}
+
 	// For multi-platform, use AI-assigned platform to determine base directory
 	if isMultiPlatform && planned.Platform != "" {
 		suffix := PlatformSourceDirSuffix(planned.Platform)
@@ -1,8 +1,12 @@
 package orchestration
 
 import (
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+
+	"github.com/moasq/nanowave/internal/packageregistry"
 )
 
 func TestGenerateProjectYAMLiOS(t *testing.T) {
@@ -11,7 +15,7 @@ func TestGenerateProjectYAMLiOS(t *testing.T) {
 		DeviceFamily: "iphone",
 	}
 
-	yml := generateProjectYAML("TestApp", plan, nil)
+	yml := generateProjectYAML("TestApp", plan, nil, defaultPackages(t))
 
 	checks := []struct {
 		desc string
@@ -46,7 +50,7 @@ func TestGenerateProjectYAMLiPad(t *testing.T) {
 		DeviceFamily: "ipad",
 	}
 
-	yml := generateProjectYAML("TabletApp", plan, nil)
+	yml := generateProjectYAML("TabletApp", plan, nil, defaultPackages(t))
 
 	if !strings.Contains(yml, "TARGETED_DEVICE_FAMILY: \"2\"") {
 		t.Error("iPad YAML should have TARGETED_DEVICE_FAMILY 2")
@@ -62,9 +66,9 @@ func TestGenerateProjectYAMLUniversal(t *testing.T) {
 		DeviceFamily: "universal",
 	}
 
-	yml := generateProjectYAML("UniApp", plan, nil)
+	yml := generateProjectYAML("UniApp", plan, nil, defaultPackages(t))
 
-	if !strings.Contains(yml, "TARGETED_DEVICE_FAMILY: \"1,2\"") {
+	if !strings.Contains(yml, "TARGETED_DEVICE_FAMILY: 1,2") {
 		t.Error("Universal YAML should have TARGETED_DEVICE_FAMILY 1,2")
 	}
 	if !strings.Contains(yml, "device: iPhone") || !strings.Contains(yml, "device: iPad") {
@@ -78,7 +82,7 @@ func TestGenerateProjectYAMLWatchOnly(t *testing.T) {
 		WatchProjectShape: "watch_only",
 	}
 
-	yml := generateProjectYAML("WatchApp", plan, nil)
+	yml := generateProjectYAML("WatchApp", plan, nil, defaultPackages(t))
 
 	checks := []struct {
 		desc string
@@ -124,7 +128,7 @@ func TestGenerateProjectYAMLPaired(t *testing.T) {
 		WatchProjectShape: "paired_ios_watch",
 	}
 
-	yml := generateProjectYAML("PairedApp", plan, nil)
+	yml := generateProjectYAML("PairedApp", plan, nil, defaultPackages(t))
 
 	checks := []struct {
 		desc string
@@ -147,7 +151,7 @@ func TestGenerateProjectYAMLPaired(t *testing.T) {
 		{"watch app depends on watch extension", "target: PairedAppWatchExtension"},
 		{"has watchkit extension point", "NSExtensionPointIdentifier: com.apple.watchkit"},
 		{"has WKAppBundleIdentifier", "WKAppBundleIdentifier: " + bundleIDPrefix() + ".pairedapp.watchkitapp"},
-		{"watch app excludes swift", "\"**/*.swift\""},
+		{"watch app excludes swift", "'**/*.swift'"},
 	}
 
 	for _, c := range checks {
@@ -168,7 +172,7 @@ func TestGenerateProjectYAMLWatchOnlyDoesNotRequireCompanionBundleIdentifier(t *
 		WatchProjectShape: "watch_only",
 	}
 
-	yml := generateProjectYAML("SoloWatch", plan, nil)
+	yml := generateProjectYAML("SoloWatch", plan, nil, defaultPackages(t))
 
 	if strings.Contains(yml, "WKCompanionAppBundleIdentifier") {
 		t.Error("watch_only YAML should not contain WKCompanionAppBundleIdentifier")
@@ -190,7 +194,7 @@ func TestGenerateProjectYAMLWatchOnlyWithWidget(t *testing.T) {
 		},
 	}
 
-	yml := generateProjectYAML("WatchWidgetApp", plan, nil)
+	yml := generateProjectYAML("WatchWidgetApp", plan, nil, defaultPackages(t))
 
 	// Extension target should be present
 	if !strings.Contains(yml, "WatchWidgetAppWidget:") {
@@ -218,7 +222,7 @@ func TestGenerateProjectYAMLPairedWithWidget(t *testing.T) {
 		},
 	}
 
-	yml := generateProjectYAML("PairedWidgetApp", plan, nil)
+	yml := generateProjectYAML("PairedWidgetApp", plan, nil, defaultPackages(t))
 
 	// Should have 4 targets: iOS app, watch app, watch runtime extension, widget extension
 	if !strings.Contains(yml, "PairedWidgetApp:") {
@@ -246,7 +250,7 @@ func TestGenerateProjectYAMLPairedWatchHasInstallableTargetGraph(t *testing.T) {
 		WatchProjectShape: "paired_ios_watch",
 	}
 
-	yml := generateProjectYAML("TapCounter", plan, nil)
+	yml := generateProjectYAML("TapCounter", plan, nil, defaultPackages(t))
 
 	markers := []string{
 		"TapCounterWatch:",
@@ -270,7 +274,7 @@ func TestGenerateProjectYAMLTvOS(t *testing.T) {
 		Platform: "tvos",
 	}
 
-	yml := generateProjectYAML("TVApp", plan, nil)
+	yml := generateProjectYAML("TVApp", plan, nil, defaultPackages(t))
 
 	checks := []struct {
 		desc string
@@ -313,7 +317,7 @@ func TestGenerateMultiPlatformProjectYAML(t *testing.T) {
 		WatchProjectShape: "paired_ios_watch",
 	}
 
-	yml := generateProjectYAML("FocusFlow", plan, nil)
+	yml := generateProjectYAML("FocusFlow", plan, nil, defaultPackages(t))
 
 	checks := []struct {
 		desc string
@@ -347,7 +351,7 @@ func TestGenerateMultiPlatformYAMLiOSWatchOnly(t *testing.T) {
 		WatchProjectShape: "paired_ios_watch",
 	}
 
-	yml := generateProjectYAML("MyApp", plan, nil)
+	yml := generateProjectYAML("MyApp", plan, nil, defaultPackages(t))
 
 	// Should have iOS + watchOS but NOT tvOS
 	if !strings.Contains(yml, "iOS: \"26.0\"") {
@@ -376,7 +380,7 @@ func TestGenerateProjectYAMLEmptyExtensionKindSafeBundleID(t *testing.T) {
 		},
 	}
 
-	yml := generateProjectYAML("FocusFlow", plan, nil)
+	yml := generateProjectYAML("FocusFlow", plan, nil, defaultPackages(t))
 
 	// Bundle ID should use lowercase target name, NOT have a trailing dot
 	if strings.Contains(yml, "PRODUCT_BUNDLE_IDENTIFIER: "+bundleIDPrefix()+".focusflow.\n") {
@@ -392,7 +396,7 @@ func TestGenerateProjectYAMLVisionOS(t *testing.T) {
 		Platform: "visionos",
 	}
 
-	yml := generateProjectYAML("VisionApp", plan, nil)
+	yml := generateProjectYAML("VisionApp", plan, nil, defaultPackages(t))
 
 	checks := []struct {
 		desc string
@@ -434,7 +438,7 @@ func TestGenerateMultiPlatformProjectYAMLWithVisionOS(t *testing.T) {
 		DeviceFamily: "iphone",
 	}
 
-	yml := generateProjectYAML("SpatialApp", plan, nil)
+	yml := generateProjectYAML("SpatialApp", plan, nil, defaultPackages(t))
 
 	checks := []struct {
 		desc string
@@ -463,7 +467,7 @@ func TestGenerateProjectYAMLMacOS(t *testing.T) {
 		Platform: "macos",
 	}
 
-	yml := generateProjectYAML("MacApp", plan, nil)
+	yml := generateProjectYAML("MacApp", plan, nil, defaultPackages(t))
 
 	checks := []struct {
 		desc string
@@ -474,7 +478,7 @@ func TestGenerateProjectYAMLMacOS(t *testing.T) {
 		{"has macOS platform", "platform: macOS"},
 		{"has supportedDestinations", "supportedDestinations:"},
 		{"has macOS destination", "- macOS"},
-		{"has COMBINE_HIDPI_IMAGES", "COMBINE_HIDPI_IMAGES: YES"},
+		{"has COMBINE_HIDPI_IMAGES", "COMBINE_HIDPI_IMAGES: \"YES\""},
 	}
 
 	for _, c := range checks {
@@ -512,7 +516,7 @@ func TestGenerateMultiPlatformProjectYAMLWithMacOS(t *testing.T) {
 		DeviceFamily: "iphone",
 	}
 
-	yml := generateProjectYAML("ProdApp", plan, nil)
+	yml := generateProjectYAML("ProdApp", plan, nil, defaultPackages(t))
 
 	checks := []struct {
 		desc string
@@ -553,7 +557,7 @@ func TestGenerateMultiPlatformYAMLiOSMacOSNoDependencies(t *testing.T) {
 		DeviceFamily: "iphone",
 	}
 
-	yml := generateProjectYAML("RecipeBook", plan, nil)
+	yml := generateProjectYAML("RecipeBook", plan, nil, defaultPackages(t))
 
 	// iOS target without watch or extensions should NOT have an empty dependencies key
 	iosIdx := strings.Index(yml, "  RecipeBook:")
@@ -570,7 +574,7 @@ func TestGenerateMultiPlatformYAMLiOSMacOSNoDependencies(t *testing.T) {
 func TestGenerateProjectYAMLDefaultsToIOS(t *testing.T) {
 	plan := &PlannerResult{}
 
-	yml := generateProjectYAML("DefaultApp", plan, nil)
+	yml := generateProjectYAML("DefaultApp", plan, nil, defaultPackages(t))
 
 	if !strings.Contains(yml, "iOS: \"26.0\"") {
 		t.Error("default should produce iOS YAML")
@@ -582,7 +586,7 @@ func TestGenerateProjectYAMLDefaultsToIOS(t *testing.T) {
 
 func TestAppearanceLockIOSLightByDefault(t *testing.T) {
 	plan := &PlannerResult{Platform: "ios", DeviceFamily: "iphone"}
-	yml := generateProjectYAML("App", plan, nil)
+	yml := generateProjectYAML("App", plan, nil, defaultPackages(t))
 	if !strings.Contains(yml, "INFOPLIST_KEY_UIUserInterfaceStyle: Light") {
 		t.Error("iOS YAML without dark-mode rule should contain UIUserInterfaceStyle: Light")
 	}
@@ -590,7 +594,7 @@ func TestAppearanceLockIOSLightByDefault(t *testing.T) {
 
 func TestAppearanceLockIOSOmittedWithDarkMode(t *testing.T) {
 	plan := &PlannerResult{Platform: "ios", DeviceFamily: "iphone", RuleKeys: []string{"dark-mode"}}
-	yml := generateProjectYAML("App", plan, nil)
+	yml := generateProjectYAML("App", plan, nil, defaultPackages(t))
 	if strings.Contains(yml, "UIUserInterfaceStyle") {
 		t.Error("iOS YAML with dark-mode rule should NOT contain UIUserInterfaceStyle")
 	}
@@ -598,7 +602,7 @@ func TestAppearanceLockIOSOmittedWithDarkMode(t *testing.T) {
 
 func TestAppearanceLockMacOSNoLock(t *testing.T) {
 	plan := &PlannerResult{Platform: "macos"}
-	yml := generateProjectYAML("App", plan, nil)
+	yml := generateProjectYAML("App", plan, nil, defaultPackages(t))
 	if strings.Contains(yml, "NSRequiresAquaSystemAppearance") {
 		t.Error("macOS YAML should NEVER contain NSRequiresAquaSystemAppearance — macOS follows system appearance")
 	}
@@ -606,7 +610,7 @@ func TestAppearanceLockMacOSNoLock(t *testing.T) {
 
 func TestAppearanceLockVisionOSNoLock(t *testing.T) {
 	plan := &PlannerResult{Platform: "visionos"}
-	yml := generateProjectYAML("App", plan, nil)
+	yml := generateProjectYAML("App", plan, nil, defaultPackages(t))
 	if strings.Contains(yml, "UIUserInterfaceStyle") {
 		t.Error("visionOS YAML should NOT contain UIUserInterfaceStyle — glass auto-adapts")
 	}
@@ -621,7 +625,7 @@ func TestAppearanceLockMultiPlatform(t *testing.T) {
 		Platforms:    []string{"ios", "macos", "tvos"},
 		DeviceFamily: "iphone",
 	}
-	yml := generateProjectYAML("App", plan, nil)
+	yml := generateProjectYAML("App", plan, nil, defaultPackages(t))
 	if !strings.Contains(yml, "INFOPLIST_KEY_UIUserInterfaceStyle: Light") {
 		t.Error("multi-platform YAML should lock iOS appearance")
 	}
@@ -635,7 +639,7 @@ func TestAppearanceLockMultiPlatformVisionOSExcluded(t *testing.T) {
 		Platform:  "ios",
 		Platforms: []string{"ios", "visionos"},
 	}
-	yml := generateProjectYAML("App", plan, nil)
+	yml := generateProjectYAML("App", plan, nil, defaultPackages(t))
 	// Count occurrences of UIUserInterfaceStyle — should only appear for iOS, not visionOS
 	count := strings.Count(yml, "INFOPLIST_KEY_UIUserInterfaceStyle: Light")
 	if count != 1 {
@@ -649,7 +653,7 @@ func TestAppearanceLockIOSDarkPalette(t *testing.T) {
 		DeviceFamily: "iphone",
 		Design:       DesignSystem{Palette: Palette{Background: "#1A1A2E"}},
 	}
-	yml := generateProjectYAML("App", plan, nil)
+	yml := generateProjectYAML("App", plan, nil, defaultPackages(t))
 	if !strings.Contains(yml, "INFOPLIST_KEY_UIUserInterfaceStyle: Dark") {
 		t.Error("iOS YAML with dark palette should lock to Dark, not Light")
 	}
@@ -664,7 +668,7 @@ func TestAppearanceLockIOSLightPalette(t *testing.T) {
 		DeviceFamily: "iphone",
 		Design:       DesignSystem{Palette: Palette{Background: "#F5F5F5"}},
 	}
-	yml := generateProjectYAML("App", plan, nil)
+	yml := generateProjectYAML("App", plan, nil, defaultPackages(t))
 	if !strings.Contains(yml, "INFOPLIST_KEY_UIUserInterfaceStyle: Light") {
 		t.Error("iOS YAML with light palette should lock to Light")
 	}
@@ -675,7 +679,7 @@ func TestAppearanceLockTvOSDarkPalette(t *testing.T) {
 		Platform: "tvos",
 		Design:   DesignSystem{Palette: Palette{Background: "#0D0D0D"}},
 	}
-	yml := generateProjectYAML("TVApp", plan, nil)
+	yml := generateProjectYAML("TVApp", plan, nil, defaultPackages(t))
 	if !strings.Contains(yml, "INFOPLIST_KEY_UIUserInterfaceStyle: Dark") {
 		t.Error("tvOS YAML with dark palette should lock to Dark")
 	}
@@ -688,7 +692,7 @@ func TestAppearanceLockMultiPlatformDarkPalette(t *testing.T) {
 		DeviceFamily: "iphone",
 		Design:       DesignSystem{Palette: Palette{Background: "#1A1A2E"}},
 	}
-	yml := generateProjectYAML("App", plan, nil)
+	yml := generateProjectYAML("App", plan, nil, defaultPackages(t))
 
 	// iOS should lock to Dark
 	if !strings.Contains(yml, "INFOPLIST_KEY_UIUserInterfaceStyle: Dark") {
@@ -773,7 +777,7 @@ func TestGenerateProjectYAMLWithPackages(t *testing.T) {
 		},
 	}
 
-	yml := generateProjectYAML("ImageApp", plan, nil)
+	yml := generateProjectYAML("ImageApp", plan, nil, defaultPackages(t))
 
 	checks := []struct {
 		desc string
@@ -782,10 +786,10 @@ func TestGenerateProjectYAMLWithPackages(t *testing.T) {
 		{"has packages section", "packages:"},
 		{"has Kingfisher package", "Kingfisher:"},
 		{"has Kingfisher URL", "url: https://github.com/onevcat/Kingfisher"},
-		{"has Kingfisher version", "from: \"8.1.0\""},
+		{"has Kingfisher version", "from: 8.1.0"},
 		{"has lottie-spm package", "lottie-spm:"},
 		{"has lottie-spm URL", "url: https://github.com/airbnb/lottie-spm"},
-		{"has lottie-spm version", "from: \"4.5.0\""},
+		{"has lottie-spm version", "from: 4.5.0"},
 		{"has Kingfisher dependency", "- package: Kingfisher"},
 		{"has lottie-spm dependency", "- package: lottie-spm"},
 		{"has Lottie product", "product: Lottie"},
@@ -815,7 +819,7 @@ func TestGenerateProjectYAMLMultiProduct(t *testing.T) {
 		},
 	}
 
-	yml := generateProjectYAML("NukeApp", plan, nil)
+	yml := generateProjectYAML("NukeApp", plan, nil, defaultPackages(t))
 
 	// Nuke has Products: ["Nuke", "NukeUI"] — both should appear as dependencies
 	if !strings.Contains(yml, "- package: Nuke") {
@@ -841,7 +845,7 @@ func TestGenerateProjectYAMLNoPackages(t *testing.T) {
 		DeviceFamily: "iphone",
 	}
 
-	yml := generateProjectYAML("SimpleApp", plan, nil)
+	yml := generateProjectYAML("SimpleApp", plan, nil, defaultPackages(t))
 
 	if strings.Contains(yml, "packages:") {
 		t.Error("YAML without packages should not contain packages: section")
@@ -860,7 +864,7 @@ func TestGenerateProjectYAMLMixedPackagesAndExtensions(t *testing.T) {
 		},
 	}
 
-	yml := generateProjectYAML("MixedApp", plan, nil)
+	yml := generateProjectYAML("MixedApp", plan, nil, defaultPackages(t))
 
 	// Should have both package and extension dependencies
 	if !strings.Contains(yml, "- package: Kingfisher") {
@@ -895,7 +899,7 @@ func TestGenerateProjectYAMLUnresolvedPackageSkipped(t *testing.T) {
 		},
 	}
 
-	yml := generateProjectYAML("SkipApp", plan, nil)
+	yml := generateProjectYAML("SkipApp", plan, nil, defaultPackages(t))
 
 	// Known package should be present
 	if !strings.Contains(yml, "Kingfisher:") {
@@ -907,3 +911,84 @@ func TestGenerateProjectYAMLUnresolvedPackageSkipped(t *testing.T) {
 		t.Error("unresolved package should not appear in project.yml")
 	}
 }
+
+func TestGenerateProjectYAMLWithTests(t *testing.T) {
+	plan := &PlannerResult{
+		Platform:     "ios",
+		DeviceFamily: "iphone",
+		Tests: &TestPlan{
+			Unit: []FilePlan{{Path: "NoteListViewModelTests.swift", TypeName: "NoteListViewModelTests"}},
+			UI:   []FilePlan{{Path: "NotesUITests.swift", TypeName: "NotesUITests"}},
+		},
+	}
+
+	yml := generateProjectYAML("Notes", plan, nil, defaultPackages(t))
+
+	for _, want := range []string{
+		"NotesTests:\n    type: bundle.unit-test",
+		"NotesUITests:\n    type: bundle.ui-testing",
+		"- target: Notes",
+		"test:\n      gatherCoverageData: true\n      targets:\n        - NotesTests\n        - NotesUITests",
+	} {
+		if !strings.Contains(yml, want) {
+			t.Errorf("project.yml missing %q:\n%s", want, yml)
+		}
+	}
+
+	if yml := generateProjectYAML("Notes", &PlannerResult{Platform: "ios"}, nil, defaultPackages(t)); strings.Contains(yml, "bundle.unit-test") {
+		t.Error("plans without tests should not get test targets")
+	}
+}
+
+func TestGenerateProjectYAMLWithLocalPackages(t *testing.T) {
+	plan := &PlannerResult{
+		Platform: "ios",
+		LocalPackages: []LocalPackagePlan{
+			{Name: "Models"},
+			{Name: "HabitsFeature", DependsOn: []string{"Models"}},
+		},
+	}
+
+	yml := generateProjectYAML("Habits", plan, nil, defaultPackages(t))
+
+	for _, want := range []string{
+		"Models:\n    path: Packages/Models",
+		"HabitsFeature:\n    path: Packages/HabitsFeature",
+		"- package: Models",
+		"- package: HabitsFeature",
+	} {
+		if !strings.Contains(yml, want) {
+			t.Errorf("project.yml missing %q:\n%s", want, yml)
+		}
+	}
+}
+
+func TestResolvePackagesSkipsUnsupportedPlatforms(t *testing.T) {
+	projectDir := t.TempDir()
+	manifest := filepath.Join(projectDir, ".nanowave", "packages.yaml")
+	if err := os.MkdirAll(filepath.Dir(manifest), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(manifest, []byte("packages:\n  - key: kingfisher\n    platforms: [ios]\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	registry, skipped, err := packageregistry.Load("", projectDir)
+	if err != nil || len(skipped) > 0 {
+		t.Fatalf("Load() = %v, %v", skipped, err)
+	}
+
+	got := resolvePackages([]PackagePlan{{Name: "Kingfisher"}, {Name: "Lottie"}}, []string{PlatformIOS, PlatformMacOS}, registry)
+	if len(got) != 1 || got[0].Key != "lottie" {
+		t.Errorf("resolvePackages() = %v, want only Lottie on iOS and macOS", got)
+	}
+}
+
+// defaultPackages returns the embedded package registry.
+func defaultPackages(t *testing.T) *packageregistry.Registry {
+	t.Helper()
+	registry, err := packageregistry.Default()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return registry
+}
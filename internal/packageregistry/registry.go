@@ -0,0 +1,551 @@
+// Package packageregistry holds the curated SPM packages nanowave offers the
+// planner and the build: an embedded default merged with user and team
+// manifests.
+package packageregistry
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Package describes a pre-validated SPM package in the registry.
+type Package struct {
+	Key          string   // lookup key, e.g. "kingfisher"
+	Name         string   // display name, e.g. "Kingfisher"
+	Category     string   // category key matching Category.Key
+	Description  string   // what it enables beyond native frameworks
+	RepoURL      string   // full GitHub URL
+	RepoName     string   // last path component of URL (XcodeGen packages: key)
+	Products     []string // SPM product names from Package.swift (import + product: in XcodeGen)
+	MinVersion   string   // minimum version to use with from:
+	ExactVersion string   // pins exactly this version instead of from:
+	MaxVersion   string   // exclusive upper bound; with MinVersion pins a range instead of from:
+	Platforms    []string // platforms the package supports; empty means all
+	Source       string   // manifest the entry was last defined in ("embedded" for the default)
+}
+
+// VersionRequirement returns the XcodeGen version keys and values for the
+// package, in order: from:, exactVersion:, or minVersion: with maxVersion:.
+func (p *Package) VersionRequirement() [][2]string {
+	switch {
+	case p.ExactVersion != "":
+		return [][2]string{{"exactVersion", p.ExactVersion}}
+	case p.MaxVersion != "":
+		return [][2]string{{"minVersion", p.MinVersion}, {"maxVersion", p.MaxVersion}}
+	default:
+		return [][2]string{{"from", p.MinVersion}}
+	}
+}
+
+// VersionSummary describes the version requirement in one line, e.g. "from 8.1.0".
+func (p *Package) VersionSummary() string {
+	switch {
+	case p.ExactVersion != "":
+		return "exactly " + p.ExactVersion
+	case p.MaxVersion != "":
+		return fmt.Sprintf("%s..<%s", p.MinVersion, p.MaxVersion)
+	default:
+		return "from " + p.MinVersion
+	}
+}
+
+// SupportsPlatforms reports whether the package supports every given platform.
+func (p *Package) SupportsPlatforms(platforms []string) bool {
+	if len(p.Platforms) == 0 {
+		return true
+	}
+	for _, platform := range platforms {
+		if !slices.Contains(p.Platforms, platform) {
+			return false
+		}
+	}
+	return true
+}
+
+// Category groups packages under a human-readable label.
+type Category struct {
+	Key         string // e.g. "images"
+	Label       string // e.g. "Image Loading & Caching"
+	Description string // when to use this category
+}
+
+// EmbeddedSource is the Source of packages from the embedded registry.
+const EmbeddedSource = "embedded"
+
+// embeddedManifest is the default registry manifest compiled into the binary.
+//
+//go:embed registry.yaml
+var embeddedManifest []byte
+
+// registryManifest is the on-disk format of a package registry manifest,
+// written as YAML or JSON.
+type registryManifest struct {
+	Categories []registryManifestCategory `yaml:"categories" json:"categories"`
+	Packages   []registryManifestPackage  `yaml:"packages" json:"packages"`
+}
+
+type registryManifestCategory struct {
+	Key         string `yaml:"key" json:"key"`
+	Label       string `yaml:"label" json:"label"`
+	Description string `yaml:"description" json:"description"`
+}
+
+type registryManifestPackage struct {
+	Key          string   `yaml:"key" json:"key"`
+	Name         string   `yaml:"name" json:"name"`
+	Category     string   `yaml:"category" json:"category"`
+	Description  string   `yaml:"description" json:"description"`
+	URL          string   `yaml:"url" json:"url"`
+	Products     []string `yaml:"products" json:"products"`
+	MinVersion   string   `yaml:"min_version" json:"min_version"`
+	ExactVersion string   `yaml:"exact_version" json:"exact_version"`
+	MaxVersion   string   `yaml:"max_version" json:"max_version"`
+	Platforms    []string `yaml:"platforms" json:"platforms"`
+}
+
+// packageManifestNames are the file names a registry manifest is read from,
+// in order of preference.
+var packageManifestNames = []string{"packages.yaml", "packages.yml", "packages.json"}
+
+// ManifestPaths returns the registry manifests merged over the embedded
+// registry, in merge order: the user manifest in nanowaveRoot (~/nanowave),
+// then the team manifest in the project's .nanowave directory. Only existing
+// files are returned.
+func ManifestPaths(nanowaveRoot, projectDir string) []string {
+	var paths []string
+	for _, dir := range []string{nanowaveRoot, filepath.Join(projectDir, ".nanowave")} {
+		if dir == "" || dir == ".nanowave" {
+			continue
+		}
+		for _, name := range packageManifestNames {
+			path := filepath.Join(dir, name)
+			if _, err := os.Stat(path); err == nil {
+				paths = append(paths, path)
+				break
+			}
+		}
+	}
+	return paths
+}
+
+// parseManifest decodes a manifest; .json files are read as JSON,
+// everything else as YAML.
+func parseManifest(path string, data []byte) (registryManifest, error) {
+	var m registryManifest
+	var err error
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		err = json.Unmarshal(data, &m)
+	} else {
+		err = yaml.Unmarshal(data, &m)
+	}
+	if err != nil {
+		return m, fmt.Errorf("%s: %w", path, err)
+	}
+	return m, nil
+}
+
+// Registry is a merged set of categories and packages.
+type Registry struct {
+	categories []Category
+	packages   []Package
+}
+
+// merge applies a manifest on top of the registry. Entries whose key already
+// exists override only the fields they set; new keys add categories and
+// packages. An entry that fails validation after merging is skipped and
+// reported, leaving any previous definition in place.
+func (r *Registry) merge(m registryManifest, source string) []error {
+	var errs []error
+	for _, mc := range m.Categories {
+		if mc.Key == "" {
+			errs = append(errs, fmt.Errorf("%s: category without a key", source))
+			continue
+		}
+		i := slices.IndexFunc(r.categories, func(c Category) bool { return c.Key == mc.Key })
+		if i < 0 {
+			if mc.Label == "" {
+				errs = append(errs, fmt.Errorf("%s: category %s has no label", source, mc.Key))
+				continue
+			}
+			r.categories = append(r.categories, Category{Key: mc.Key, Label: mc.Label, Description: mc.Description})
+			continue
+		}
+		if mc.Label != "" {
+			r.categories[i].Label = mc.Label
+		}
+		if mc.Description != "" {
+			r.categories[i].Description = mc.Description
+		}
+	}
+
+	for _, mp := range m.Packages {
+		if mp.Key == "" {
+			errs = append(errs, fmt.Errorf("%s: package without a key", source))
+			continue
+		}
+		i := slices.IndexFunc(r.packages, func(p Package) bool { return p.Key == mp.Key })
+		var pkg Package
+		if i >= 0 {
+			pkg = r.packages[i]
+			pkg.Products = slices.Clone(pkg.Products)
+			pkg.Platforms = slices.Clone(pkg.Platforms)
+		}
+		overlayManifestPackage(&pkg, mp)
+		pkg.Source = source
+		if problems := r.validate(pkg); len(problems) > 0 {
+			errs = append(errs, fmt.Errorf("%s: package %s: %s", source, mp.Key, strings.Join(problems, "; ")))
+			continue
+		}
+		if i >= 0 {
+			r.packages[i] = pkg
+		} else {
+			r.packages = append(r.packages, pkg)
+		}
+	}
+	return errs
+}
+
+// overlayManifestPackage copies the fields a manifest entry sets onto pkg.
+// Setting exact_version drops a range pin, and setting min_version or
+// max_version drops an exact pin, so a later manifest can switch pin styles.
+func overlayManifestPackage(pkg *Package, mp registryManifestPackage) {
+	set := func(dst *string, v string) {
+		if v != "" {
+			*dst = v
+		}
+	}
+	pkg.Key = mp.Key
+	set(&pkg.Name, mp.Name)
+	set(&pkg.Category, mp.Category)
+	set(&pkg.Description, mp.Description)
+	if mp.URL != "" {
+		pkg.RepoURL = strings.TrimSuffix(strings.TrimSuffix(mp.URL, "/"), ".git")
+		pkg.RepoName = pkg.RepoURL[strings.LastIndex(pkg.RepoURL, "/")+1:]
+	}
+	if len(mp.Products) > 0 {
+		pkg.Products = mp.Products
+	}
+	if mp.ExactVersion != "" {
+		pkg.ExactVersion = mp.ExactVersion
+		pkg.MaxVersion = ""
+	}
+	if mp.MinVersion != "" || mp.MaxVersion != "" {
+		pkg.ExactVersion = ""
+		set(&pkg.MinVersion, mp.MinVersion)
+		set(&pkg.MaxVersion, mp.MaxVersion)
+	}
+	if len(mp.Platforms) > 0 {
+		pkg.Platforms = mp.Platforms
+	}
+}
+
+// platforms are the platform identifiers a package may be limited to.
+var platforms = []string{"ios", "macos", "watchos", "tvos", "visionos"}
+
+// registryVersionPattern matches the versions SwiftPM accepts in a requirement.
+var registryVersionPattern = regexp.MustCompile(`^\d+\.\d+\.\d+(-[0-9A-Za-z.-]+)?$`)
+
+// validate returns the problems of a merged package entry.
+func (r *Registry) validate(pkg Package) []string {
+	var problems []string
+	if pkg.Name == "" {
+		problems = append(problems, "name is required")
+	}
+	if !slices.ContainsFunc(r.categories, func(c Category) bool { return c.Key == pkg.Category }) {
+		problems = append(problems, fmt.Sprintf("unknown category %q", pkg.Category))
+	}
+	if !strings.HasPrefix(pkg.RepoURL, "https://") || pkg.RepoName == "" {
+		problems = append(problems, "url must be an https repository URL")
+	}
+	if len(pkg.Products) == 0 {
+		problems = append(problems, "at least one product is required")
+	}
+	for field, version := range map[string]string{"min_version": pkg.MinVersion, "exact_version": pkg.ExactVersion, "max_version": pkg.MaxVersion} {
+		if version != "" && !registryVersionPattern.MatchString(version) {
+			problems = append(problems, fmt.Sprintf("%s %q is not a semantic version", field, version))
+		}
+	}
+	if pkg.MinVersion == "" && pkg.ExactVersion == "" {
+		problems = append(problems, "min_version or exact_version is required")
+	}
+	if pkg.MaxVersion != "" && pkg.MinVersion != "" && compareSemver(pkg.MaxVersion, pkg.MinVersion) <= 0 {
+		problems = append(problems, fmt.Sprintf("max_version %s must be above min_version %s", pkg.MaxVersion, pkg.MinVersion))
+	}
+	for _, platform := range pkg.Platforms {
+		if !slices.Contains(platforms, platform) {
+			problems = append(problems, fmt.Sprintf("unknown platform %q", platform))
+		}
+	}
+	slices.Sort(problems)
+	return problems
+}
+
+// compareSemver compares the numeric major.minor.patch parts of two versions.
+func compareSemver(a, b string) int {
+	pa := strings.SplitN(strings.SplitN(a, "-", 2)[0], ".", 3)
+	pb := strings.SplitN(strings.SplitN(b, "-", 2)[0], ".", 3)
+	for i := range 3 {
+		var x, y int
+		if i < len(pa) {
+			x, _ = strconv.Atoi(pa[i])
+		}
+		if i < len(pb) {
+			y, _ = strconv.Atoi(pb[i])
+		}
+		if x != y {
+			return x - y
+		}
+	}
+	return 0
+}
+
+// Requirement is a version requirement requested for a package: exactly
+// ExactVersion, or MinVersion up to MaxVersion, or MinVersion up to the next
+// major version when MaxVersion is empty.
+type Requirement struct {
+	MinVersion   string
+	ExactVersion string
+	MaxVersion   string
+}
+
+// String describes the requirement in one line, e.g. "from 8.1.0".
+func (q Requirement) String() string {
+	p := Package{MinVersion: q.MinVersion, ExactVersion: q.ExactVersion, MaxVersion: q.MaxVersion}
+	return p.VersionSummary()
+}
+
+// Validate reports a malformed requirement: versions SwiftPM does not accept,
+// an exact version combined with a range, or an empty or inverted range.
+func (q Requirement) Validate() error {
+	for field, version := range map[string]string{"min_version": q.MinVersion, "exact_version": q.ExactVersion, "max_version": q.MaxVersion} {
+		if version != "" && !registryVersionPattern.MatchString(version) {
+			return fmt.Errorf("%s %q is not a semantic version like 1.2.3", field, version)
+		}
+	}
+	switch {
+	case q.ExactVersion != "" && (q.MinVersion != "" || q.MaxVersion != ""):
+		return fmt.Errorf("exact_version cannot be combined with min_version or max_version")
+	case q.ExactVersion == "" && q.MinVersion == "":
+		return fmt.Errorf("min_version or exact_version is required")
+	case q.MaxVersion != "" && compareSemver(q.MaxVersion, q.MinVersion) <= 0:
+		return fmt.Errorf("max_version %s must be above min_version %s", q.MaxVersion, q.MinVersion)
+	}
+	return nil
+}
+
+// bounds returns the lowest and highest version the requirement admits, and
+// whether the highest is admitted itself or is an exclusive bound.
+func (q Requirement) bounds() (lower, upper string, inclusive bool) {
+	switch {
+	case q.ExactVersion != "":
+		return q.ExactVersion, q.ExactVersion, true
+	case q.MaxVersion != "":
+		return q.MinVersion, q.MaxVersion, false
+	default:
+		major, _ := strconv.Atoi(strings.SplitN(q.MinVersion, ".", 2)[0])
+		return q.MinVersion, fmt.Sprintf("%d.0.0", major+1), false
+	}
+}
+
+// Requirement returns the version requirement the registry approves for the package.
+func (p *Package) Requirement() Requirement {
+	if p.ExactVersion != "" {
+		return Requirement{ExactVersion: p.ExactVersion}
+	}
+	return Requirement{MinVersion: p.MinVersion, MaxVersion: p.MaxVersion}
+}
+
+// Admits returns an error when q is invalid or admits versions outside the
+// requirement the registry approves for the package.
+func (p *Package) Admits(q Requirement) error {
+	if err := q.Validate(); err != nil {
+		return err
+	}
+	lower, upper, inclusive := q.bounds()
+	approvedLower, approvedUpper, approvedInclusive := p.Requirement().bounds()
+	admitted := compareSemver(lower, approvedLower) >= 0
+	switch {
+	case approvedInclusive:
+		admitted = admitted && inclusive && compareSemver(upper, approvedUpper) == 0
+	case inclusive:
+		admitted = admitted && compareSemver(upper, approvedUpper) < 0
+	default:
+		admitted = admitted && compareSemver(upper, approvedUpper) <= 0
+	}
+	if !admitted {
+		return fmt.Errorf("the package registry approves %s %s; %s is outside it", p.Name, p.VersionSummary(), q)
+	}
+	return nil
+}
+
+// Load returns the embedded registry merged with the user manifest in
+// nanowaveRoot (~/nanowave) and the team manifest in projectDir (see
+// ManifestPaths). Problems in those manifests are returned as skipped; the
+// affected entries are left out and the rest still load. The error is set only
+// when the embedded registry itself is invalid.
+func Load(nanowaveRoot, projectDir string) (*Registry, []error, error) {
+	return load(ManifestPaths(nanowaveRoot, projectDir))
+}
+
+// Default returns the embedded registry without any manifests merged.
+func Default() (*Registry, error) {
+	r, _, err := load(nil)
+	return r, err
+}
+
+// load builds a registry from the embedded manifest and the given manifest
+// files, in order. Unreadable manifests and invalid entries are skipped and
+// reported.
+func load(paths []string) (*Registry, []error, error) {
+	r := &Registry{}
+	m, err := parseManifest("registry.yaml", embeddedManifest)
+	if err != nil {
+		return nil, nil, fmt.Errorf("embedded package registry: %w", err)
+	}
+	if errs := r.merge(m, EmbeddedSource); len(errs) > 0 {
+		return nil, nil, fmt.Errorf("embedded package registry: %w", errors.Join(errs...))
+	}
+
+	var skipped []error
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			skipped = append(skipped, err)
+			continue
+		}
+		m, err := parseManifest(path, data)
+		if err != nil {
+			skipped = append(skipped, err)
+			continue
+		}
+		skipped = append(skipped, r.merge(m, path)...)
+	}
+	return r, skipped, nil
+}
+
+// Lookup returns the package with the given key, or nil if not found.
+func (r *Registry) Lookup(key string) *Package {
+	for i := range r.packages {
+		if r.packages[i].Key == key {
+			return &r.packages[i]
+		}
+	}
+	return nil
+}
+
+// LookupByName finds a package by its display name or key (case-insensitive).
+// This is used when the planner outputs a name like "Kingfisher" instead of the key "kingfisher".
+func (r *Registry) LookupByName(name string) *Package {
+	for i := range r.packages {
+		if equalFoldASCII(r.packages[i].Name, name) || equalFoldASCII(r.packages[i].Key, name) {
+			return &r.packages[i]
+		}
+	}
+	return nil
+}
+
+// LookupByURL finds a package by its repository URL, with or without a .git
+// suffix or trailing slash.
+func (r *Registry) LookupByURL(url string) *Package {
+	url = strings.TrimSuffix(strings.TrimSuffix(url, "/"), ".git")
+	for i := range r.packages {
+		if strings.EqualFold(r.packages[i].RepoURL, url) {
+			return &r.packages[i]
+		}
+	}
+	return nil
+}
+
+// ByCategory returns all packages in a category.
+func (r *Registry) ByCategory(category string) []*Package {
+	var result []*Package
+	for i := range r.packages {
+		if r.packages[i].Category == category {
+			result = append(result, &r.packages[i])
+		}
+	}
+	return result
+}
+
+// Categories returns the category definitions in display order.
+func (r *Registry) Categories() []Category {
+	return slices.Clone(r.categories)
+}
+
+// Packages returns a copy of the full package list.
+func (r *Registry) Packages() []Package {
+	return slices.Clone(r.packages)
+}
+
+// Search returns the packages whose key, name, category or description
+// contains query, case-insensitively.
+func (r *Registry) Search(query string) []Package {
+	query = strings.ToLower(strings.TrimSpace(query))
+	var result []Package
+	for _, pkg := range r.packages {
+		for _, field := range []string{pkg.Key, pkg.Name, pkg.Category, pkg.Description} {
+			if strings.Contains(strings.ToLower(field), query) {
+				result = append(result, pkg)
+				break
+			}
+		}
+	}
+	return result
+}
+
+// packageSwiftLibraryPattern matches library products declared in a Package.swift.
+var packageSwiftLibraryPattern = regexp.MustCompile(`\.library\(\s*name:\s*"([^"]+)"`)
+
+// PackageSwiftProducts returns the library products declared in Package.swift source.
+func PackageSwiftProducts(source string) []string {
+	var products []string
+	for _, m := range packageSwiftLibraryPattern.FindAllStringSubmatch(source, -1) {
+		if !slices.Contains(products, m[1]) {
+			products = append(products, m[1])
+		}
+	}
+	return products
+}
+
+// MissingProducts returns the products pkg declares that the given
+// Package.swift source does not.
+func MissingProducts(pkg Package, packageSwift string) []string {
+	declared := PackageSwiftProducts(packageSwift)
+	var missing []string
+	for _, product := range pkg.Products {
+		if !slices.Contains(declared, product) {
+			missing = append(missing, product)
+		}
+	}
+	return missing
+}
+
+// equalFoldASCII is a simple ASCII case-insensitive comparison.
+func equalFoldASCII(a, b string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range len(a) {
+		ca, cb := a[i], b[i]
+		if ca >= 'A' && ca <= 'Z' {
+			ca += 'a' - 'A'
+		}
+		if cb >= 'A' && cb <= 'Z' {
+			cb += 'a' - 'A'
+		}
+		if ca != cb {
+			return false
+		}
+	}
+	return true
+}
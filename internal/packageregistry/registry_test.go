@@ -0,0 +1,434 @@
+package packageregistry
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestLookup(t *testing.T) {
+	r := defaultRegistry(t)
+	tests := []struct {
+		key      string
+		wantName string
+		wantNil  bool
+	}{
+		// Images
+		{"kingfisher", "Kingfisher", false},
+		{"nuke", "Nuke", false},
+		{"sdwebimage-swiftui", "SDWebImageSwiftUI", false},
+		// GIF / SVG
+		{"gifu", "Gifu", false},
+		{"svgview", "SVGView", false},
+		// Image editing
+		{"brightroom", "Brightroom", false},
+		{"cropviewcontroller", "CropViewController", false},
+		// Audio
+		{"dswaveformimage", "DSWaveformImage", false},
+		{"audiokit", "AudioKit", false},
+		// Animations & Effects
+		{"lottie", "Lottie", false},
+		{"confetti", "ConfettiSwiftUI", false},
+		{"pow", "Pow", false},
+		{"vortex", "Vortex", false},
+		// Shimmer & Loading
+		{"shimmer", "Shimmer", false},
+		{"activity-indicator", "ActivityIndicatorView", false},
+		// UI Components
+		{"popupview", "PopupView", false},
+		{"alerttoast", "AlertToast", false},
+		{"whatsnewkit", "WhatsNewKit", false},
+		{"concentric-onboarding", "ConcentricOnboarding", false},
+		{"horizoncalendar", "HorizonCalendar", false},
+		{"exyte-chat", "ExyteChat", false},
+		// Text & Content
+		{"markdown-ui", "MarkdownUI", false},
+		{"richtextkit", "RichTextKit", false},
+		// Layouts
+		{"swiftui-flow", "SwiftUI-Flow", false},
+		{"waterfallgrid", "WaterfallGrid", false},
+		// Scanning & Codes
+		{"efqrcode", "EFQRCode", false},
+		// Syntax Highlighting
+		{"highlightr", "Highlightr", false},
+		// Keychain
+		{"keychainswift", "KeychainSwift", false},
+		{"valet", "Valet", false},
+		// Not found
+		{"nonexistent", "", true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.key, func(t *testing.T) {
+			pkg := r.Lookup(tc.key)
+			if tc.wantNil {
+				if pkg != nil {
+					t.Errorf("Lookup(%q) = %v, want nil", tc.key, pkg)
+				}
+				return
+			}
+			if pkg == nil {
+				t.Fatalf("Lookup(%q) = nil, want %q", tc.key, tc.wantName)
+			}
+			if pkg.Name != tc.wantName {
+				t.Errorf("Lookup(%q).Name = %q, want %q", tc.key, pkg.Name, tc.wantName)
+			}
+		})
+	}
+}
+
+func TestLookupByName(t *testing.T) {
+	r := defaultRegistry(t)
+	tests := []struct {
+		name     string
+		wantKey  string
+		wantNil  bool
+	}{
+		// Case-insensitive matches
+		{"Kingfisher", "kingfisher", false},
+		{"kingfisher", "kingfisher", false},
+		{"KINGFISHER", "kingfisher", false},
+		// All packages by display name
+		{"Nuke", "nuke", false},
+		{"SDWebImageSwiftUI", "sdwebimage-swiftui", false},
+		{"Gifu", "gifu", false},
+		{"SVGView", "svgview", false},
+		{"Brightroom", "brightroom", false},
+		{"CropViewController", "cropviewcontroller", false},
+		{"DSWaveformImage", "dswaveformimage", false},
+		{"AudioKit", "audiokit", false},
+		{"Lottie", "lottie", false},
+		{"ConfettiSwiftUI", "confetti", false},
+		{"Pow", "pow", false},
+		{"Vortex", "vortex", false},
+		{"Shimmer", "shimmer", false},
+		{"ActivityIndicatorView", "activity-indicator", false},
+		{"PopupView", "popupview", false},
+		{"AlertToast", "alerttoast", false},
+		{"WhatsNewKit", "whatsnewkit", false},
+		{"ConcentricOnboarding", "concentric-onboarding", false},
+		{"HorizonCalendar", "horizoncalendar", false},
+		{"ExyteChat", "exyte-chat", false},
+		{"MarkdownUI", "markdown-ui", false},
+		{"RichTextKit", "richtextkit", false},
+		{"SwiftUI-Flow", "swiftui-flow", false},
+		{"WaterfallGrid", "waterfallgrid", false},
+		{"EFQRCode", "efqrcode", false},
+		{"Highlightr", "highlightr", false},
+		{"KeychainSwift", "keychainswift", false},
+		{"Valet", "valet", false},
+		// Not found
+		{"UnknownPackage", "", true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			pkg := r.LookupByName(tc.name)
+			if tc.wantNil {
+				if pkg != nil {
+					t.Errorf("LookupByName(%q) = %v, want nil", tc.name, pkg)
+				}
+				return
+			}
+			if pkg == nil {
+				t.Fatalf("LookupByName(%q) = nil, want key %q", tc.name, tc.wantKey)
+			}
+			if pkg.Key != tc.wantKey {
+				t.Errorf("LookupByName(%q).Key = %q, want %q", tc.name, pkg.Key, tc.wantKey)
+			}
+		})
+	}
+}
+
+func TestByCategory(t *testing.T) {
+	r := defaultRegistry(t)
+	images := r.ByCategory("images")
+	if len(images) < 2 {
+		t.Errorf("ByCategory(\"images\") returned %d packages, want at least 2", len(images))
+	}
+
+	effects := r.ByCategory("effects")
+	if len(effects) < 2 {
+		t.Errorf("ByCategory(\"effects\") returned %d packages, want at least 2", len(effects))
+	}
+
+	keychain := r.ByCategory("keychain")
+	if len(keychain) < 2 {
+		t.Errorf("ByCategory(\"keychain\") returned %d packages, want at least 2", len(keychain))
+	}
+
+	toasts := r.ByCategory("toasts")
+	if len(toasts) < 2 {
+		t.Errorf("ByCategory(\"toasts\") returned %d packages, want at least 2", len(toasts))
+	}
+
+	onboarding := r.ByCategory("onboarding")
+	if len(onboarding) < 2 {
+		t.Errorf("ByCategory(\"onboarding\") returned %d packages, want at least 2", len(onboarding))
+	}
+
+	flowLayout := r.ByCategory("flow-layout")
+	if len(flowLayout) < 1 {
+		t.Errorf("ByCategory(\"flow-layout\") returned %d packages, want at least 1", len(flowLayout))
+	}
+
+	waterfallGrid := r.ByCategory("waterfall-grid")
+	if len(waterfallGrid) < 1 {
+		t.Errorf("ByCategory(\"waterfall-grid\") returned %d packages, want at least 1", len(waterfallGrid))
+	}
+
+	syntaxHighlighting := r.ByCategory("syntax-highlighting")
+	if len(syntaxHighlighting) < 1 {
+		t.Errorf("ByCategory(\"syntax-highlighting\") returned %d packages, want at least 1", len(syntaxHighlighting))
+	}
+
+	empty := r.ByCategory("nonexistent")
+	if len(empty) != 0 {
+		t.Errorf("ByCategory(\"nonexistent\") returned %d packages, want 0", len(empty))
+	}
+}
+
+func TestPackagesCount(t *testing.T) {
+	all := defaultRegistry(t).Packages()
+	if len(all) < 27 {
+		t.Errorf("Packages() returned %d packages, want at least 27", len(all))
+	}
+
+	// Verify no duplicate keys
+	keys := make(map[string]bool)
+	for _, pkg := range all {
+		if keys[pkg.Key] {
+			t.Errorf("duplicate package key %q", pkg.Key)
+		}
+		keys[pkg.Key] = true
+	}
+}
+
+func TestCategories(t *testing.T) {
+	cats := defaultRegistry(t).Categories()
+	if len(cats) < 15 {
+		t.Errorf("Categories() returned %d categories, want at least 15", len(cats))
+	}
+
+	keys := make(map[string]bool)
+	for _, cat := range cats {
+		if cat.Key == "" {
+			t.Error("category has empty key")
+		}
+		if cat.Label == "" {
+			t.Errorf("category %q has empty label", cat.Key)
+		}
+		if keys[cat.Key] {
+			t.Errorf("duplicate category key %q", cat.Key)
+		}
+		keys[cat.Key] = true
+	}
+}
+
+func TestPackageFieldsNotEmpty(t *testing.T) {
+	r := defaultRegistry(t)
+	for _, pkg := range r.Packages() {
+		t.Run(pkg.Key, func(t *testing.T) {
+			if pkg.Key == "" {
+				t.Error("empty Key")
+			}
+			if pkg.Name == "" {
+				t.Error("empty Name")
+			}
+			if pkg.Category == "" {
+				t.Error("empty Category")
+			}
+			if pkg.Description == "" {
+				t.Error("empty Description")
+			}
+			if pkg.RepoURL == "" {
+				t.Error("empty RepoURL")
+			}
+			if !strings.HasPrefix(pkg.RepoURL, "https://github.com/") {
+				t.Errorf("RepoURL %q does not start with https://github.com/", pkg.RepoURL)
+			}
+			if pkg.RepoName == "" {
+				t.Error("empty RepoName")
+			}
+			if len(pkg.Products) == 0 {
+				t.Error("empty Products")
+			}
+			if pkg.MinVersion == "" {
+				t.Error("empty MinVersion")
+			}
+			// Verify category exists
+			found := false
+			for _, cat := range r.Categories() {
+				if cat.Key == pkg.Category {
+					found = true
+					break
+				}
+			}
+			if !found {
+				t.Errorf("package category %q not in Categories()", pkg.Category)
+			}
+		})
+	}
+}
+
+func TestLoadMergesManifests(t *testing.T) {
+	userDir := t.TempDir()
+	projectDir := t.TempDir()
+	writeFile := func(path, content string) {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeFile(filepath.Join(userDir, "packages.yaml"), `
+packages:
+  - key: kingfisher
+    min_version: 8.2.0
+    max_version: 9.0.0
+  - key: broken
+    name: Broken
+    category: images
+    url: http://example.com/broken
+    products: [Broken]
+    min_version: "1.0"
+`)
+	writeFile(filepath.Join(projectDir, ".nanowave", "packages.json"), `{
+  "categories": [{"key": "collections", "label": "Collections"}],
+  "packages": [
+    {"key": "kingfisher", "exact_version": "8.3.2", "platforms": ["ios"]},
+    {"key": "swift-collections", "name": "Collections", "category": "collections",
+     "description": "Deques and ordered sets", "url": "https://github.com/apple/swift-collections.git",
+     "products": ["Collections"], "min_version": "1.1.0"}
+  ]
+}`)
+
+	r, skipped, err := Load(userDir, projectDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(skipped) != 1 || !strings.Contains(skipped[0].Error(), "package broken") {
+		t.Fatalf("Load skipped = %v, want one for the broken package", skipped)
+	}
+	if r.Lookup("broken") != nil {
+		t.Error("invalid manifest entries should be skipped")
+	}
+
+	kf := r.Lookup("kingfisher")
+	if kf.ExactVersion != "8.3.2" || kf.MaxVersion != "" || kf.Name != "Kingfisher" || kf.RepoURL != "https://github.com/onevcat/Kingfisher" {
+		t.Errorf("kingfisher override = %+v, want the exact pin over the embedded entry", kf)
+	}
+	if got := kf.VersionRequirement(); len(got) != 1 || got[0] != [2]string{"exactVersion", "8.3.2"} {
+		t.Errorf("VersionRequirement() = %v", got)
+	}
+	if !strings.HasSuffix(kf.Source, "packages.json") {
+		t.Errorf("Source = %q, want the team manifest", kf.Source)
+	}
+
+	collections := r.LookupByName("Collections")
+	if collections == nil || collections.RepoName != "swift-collections" || len(r.ByCategory("collections")) != 1 {
+		t.Fatalf("team package not added: %+v", collections)
+	}
+
+	if r.LookupByURL("https://github.com/apple/swift-collections/") != collections {
+		t.Error("LookupByURL should match the URL without its .git suffix")
+	}
+
+	if kf := defaultRegistry(t).Lookup("kingfisher"); kf.ExactVersion != "" || kf.Source != EmbeddedSource {
+		t.Errorf("the default registry should keep the embedded entry, got %+v", kf)
+	}
+}
+
+func TestVersionRange(t *testing.T) {
+	pkg := Package{MinVersion: "8.2.0", MaxVersion: "9.0.0"}
+	if got := pkg.VersionRequirement(); len(got) != 2 || got[0] != [2]string{"minVersion", "8.2.0"} || got[1] != [2]string{"maxVersion", "9.0.0"} {
+		t.Errorf("VersionRequirement() = %v", got)
+	}
+	if got := pkg.VersionSummary(); got != "8.2.0..<9.0.0" {
+		t.Errorf("VersionSummary() = %q", got)
+	}
+
+	r := &Registry{categories: []Category{{Key: "images", Label: "Images"}}}
+	errs := r.merge(registryManifest{Packages: []registryManifestPackage{
+		{Key: "inverted", Name: "Inverted", Category: "images", URL: "https://github.com/a/b", Products: []string{"B"}, MinVersion: "2.0.0", MaxVersion: "1.5.0"},
+	}}, "test")
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "must be above") {
+		t.Errorf("merge errors = %v, want an inverted range error", errs)
+	}
+}
+
+func TestMissingProducts(t *testing.T) {
+	source, err := os.ReadFile(filepath.Join("testdata", "packages", "Nuke", "Package.swift"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := PackageSwiftProducts(string(source)); !slices.Equal(got, []string{"Nuke", "NukeUI", "NukeExtensions"}) {
+		t.Errorf("PackageSwiftProducts() = %v", got)
+	}
+
+	nuke := *defaultRegistry(t).Lookup("nuke")
+	if missing := MissingProducts(nuke, string(source)); len(missing) != 0 {
+		t.Errorf("registry Nuke products missing from Package.swift: %v", missing)
+	}
+	nuke.Products = append(nuke.Products, "NukeVideo")
+	if missing := MissingProducts(nuke, string(source)); !slices.Equal(missing, []string{"NukeVideo"}) {
+		t.Errorf("MissingProducts() = %v, want [NukeVideo]", missing)
+	}
+}
+
+func TestAdmits(t *testing.T) {
+	from := Package{Name: "Kingfisher", MinVersion: "8.1.0"}
+	ranged := Package{Name: "Lottie", MinVersion: "4.4.0", MaxVersion: "4.6.0"}
+	exact := Package{Name: "Pow", ExactVersion: "1.0.5"}
+
+	tests := []struct {
+		name    string
+		pkg     Package
+		req     Requirement
+		wantErr string
+	}{
+		{"from within the major version", from, Requirement{MinVersion: "8.2.0"}, ""},
+		{"from below the floor", from, Requirement{MinVersion: "7.12.0"}, "outside"},
+		{"from the next major version", from, Requirement{MinVersion: "9.0.0"}, "outside"},
+		{"exact within from", from, Requirement{ExactVersion: "8.3.1"}, ""},
+		{"range within from", from, Requirement{MinVersion: "8.1.0", MaxVersion: "9.0.0"}, ""},
+		{"range within range", ranged, Requirement{MinVersion: "4.4.2", MaxVersion: "4.5.0"}, ""},
+		{"range past the maximum", ranged, Requirement{MinVersion: "4.4.0", MaxVersion: "4.7.0"}, "outside"},
+		{"from past a range", ranged, Requirement{MinVersion: "4.4.0"}, "outside"},
+		{"exact at the exclusive maximum", ranged, Requirement{ExactVersion: "4.6.0"}, "outside"},
+		{"same exact version", exact, Requirement{ExactVersion: "1.0.5"}, ""},
+		{"other exact version", exact, Requirement{ExactVersion: "1.0.6"}, "outside"},
+		{"range over an exact pin", exact, Requirement{MinVersion: "1.0.5", MaxVersion: "1.0.6"}, "outside"},
+		{"no version", from, Requirement{}, "min_version or exact_version is required"},
+		{"exact with a range", from, Requirement{MinVersion: "8.1.0", ExactVersion: "8.2.0"}, "cannot be combined"},
+		{"inverted range", from, Requirement{MinVersion: "8.3.0", MaxVersion: "8.2.0"}, "must be above"},
+		{"not semantic", from, Requirement{MinVersion: "8.2"}, "not a semantic version"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.pkg.Admits(tc.req)
+			if tc.wantErr == "" {
+				if err != nil {
+					t.Errorf("Admits(%+v) = %v, want nil", tc.req, err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Errorf("Admits(%+v) = %v, want an error containing %q", tc.req, err, tc.wantErr)
+			}
+		})
+	}
+}
+
+// defaultRegistry returns the embedded registry.
+func defaultRegistry(t *testing.T) *Registry {
+	t.Helper()
+	r, err := Default()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
@@ -0,0 +1,23 @@
+// swift-tools-version:5.9
+import PackageDescription
+
+let package = Package(
+    name: "Nuke",
+    platforms: [
+        .iOS(.v13),
+        .tvOS(.v13),
+        .macOS(.v10_15),
+        .watchOS(.v6),
+        .visionOS(.v1)
+    ],
+    products: [
+        .library(name: "Nuke", targets: ["Nuke"]),
+        .library(name: "NukeUI", targets: ["NukeUI"]),
+        .library(name: "NukeExtensions", targets: ["NukeExtensions"])
+    ],
+    targets: [
+        .target(name: "Nuke"),
+        .target(name: "NukeUI", dependencies: ["Nuke"]),
+        .target(name: "NukeExtensions", dependencies: ["Nuke"])
+    ]
+)
@@ -0,0 +1,261 @@
+// Package pbxproj parses Xcode project.pbxproj files (old-style ASCII property
+// lists) into their object graph, enough to read targets, build settings and
+// Swift package references.
+package pbxproj
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Object is a node of the project object graph, keyed by its property names.
+// Values are strings, []any or map[string]any.
+type Object map[string]any
+
+// File is a parsed project.pbxproj.
+type File struct {
+	RootObject string
+	Objects    map[string]Object
+}
+
+// Parse decodes project.pbxproj content.
+func Parse(data []byte) (*File, error) {
+	// The "// !$*UTF8*$!" header is skipped as a comment.
+	p := &parser{src: string(data)}
+	value, err := p.value()
+	if err != nil {
+		return nil, err
+	}
+	root, ok := value.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("pbxproj: top level is not a dictionary")
+	}
+
+	f := &File{Objects: make(map[string]Object)}
+	f.RootObject, _ = root["rootObject"].(string)
+	objects, _ := root["objects"].(map[string]any)
+	for id, raw := range objects {
+		if obj, ok := raw.(map[string]any); ok {
+			f.Objects[id] = Object(obj)
+		}
+	}
+	if _, ok := f.Objects[f.RootObject]; !ok {
+		return nil, fmt.Errorf("pbxproj: root object %q not found", f.RootObject)
+	}
+	return f, nil
+}
+
+// Root returns the PBXProject object.
+func (f *File) Root() Object {
+	return f.Objects[f.RootObject]
+}
+
+// Ref resolves the object ID stored under key of obj.
+func (f *File) Ref(obj Object, key string) Object {
+	return f.Objects[obj.String(key)]
+}
+
+// Refs resolves the list of object IDs stored under key of obj, skipping dangling IDs.
+func (f *File) Refs(obj Object, key string) []Object {
+	var out []Object
+	for _, id := range obj.Strings(key) {
+		if o, ok := f.Objects[id]; ok {
+			out = append(out, o)
+		}
+	}
+	return out
+}
+
+// Isa returns the object's class name, e.g. PBXNativeTarget.
+func (o Object) Isa() string {
+	return o.String("isa")
+}
+
+// String returns a string property, or "" when absent.
+func (o Object) String(key string) string {
+	s, _ := o[key].(string)
+	return s
+}
+
+// Strings returns a list property as strings.
+func (o Object) Strings(key string) []string {
+	list, _ := o[key].([]any)
+	out := make([]string, 0, len(list))
+	for _, v := range list {
+		if s, ok := v.(string); ok {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// Map returns a dictionary property, or nil when absent.
+func (o Object) Map(key string) map[string]any {
+	m, _ := o[key].(map[string]any)
+	return m
+}
+
+type parser struct {
+	src string
+	pos int
+}
+
+func (p *parser) errorf(format string, args ...any) error {
+	line := 1 + strings.Count(p.src[:p.pos], "\n")
+	return fmt.Errorf("pbxproj: line %d: %s", line, fmt.Sprintf(format, args...))
+}
+
+// skipSpace skips whitespace and comments.
+func (p *parser) skipSpace() {
+	for p.pos < len(p.src) {
+		rest := p.src[p.pos:]
+		switch {
+		case rest[0] == ' ' || rest[0] == '\t' || rest[0] == '\n' || rest[0] == '\r':
+			p.pos++
+		case strings.HasPrefix(rest, "//"):
+			end := strings.IndexByte(rest, '\n')
+			if end < 0 {
+				p.pos = len(p.src)
+			} else {
+				p.pos += end + 1
+			}
+		case strings.HasPrefix(rest, "/*"):
+			end := strings.Index(rest[2:], "*/")
+			if end < 0 {
+				p.pos = len(p.src)
+			} else {
+				p.pos += end + 4
+			}
+		default:
+			return
+		}
+	}
+}
+
+func (p *parser) expect(c byte) error {
+	p.skipSpace()
+	if p.pos >= len(p.src) || p.src[p.pos] != c {
+		return p.errorf("expected %q", c)
+	}
+	p.pos++
+	return nil
+}
+
+func (p *parser) value() (any, error) {
+	p.skipSpace()
+	if p.pos >= len(p.src) {
+		return nil, p.errorf("unexpected end of file")
+	}
+	switch p.src[p.pos] {
+	case '{':
+		return p.dict()
+	case '(':
+		return p.array()
+	case '"':
+		return p.quoted()
+	default:
+		return p.bare()
+	}
+}
+
+func (p *parser) dict() (map[string]any, error) {
+	p.pos++ // {
+	m := make(map[string]any)
+	for {
+		p.skipSpace()
+		if p.pos < len(p.src) && p.src[p.pos] == '}' {
+			p.pos++
+			return m, nil
+		}
+		key, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+		k, ok := key.(string)
+		if !ok {
+			return nil, p.errorf("dictionary key is not a string")
+		}
+		if err := p.expect('='); err != nil {
+			return nil, err
+		}
+		v, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(';'); err != nil {
+			return nil, err
+		}
+		m[k] = v
+	}
+}
+
+func (p *parser) array() ([]any, error) {
+	p.pos++ // (
+	list := []any{}
+	for {
+		p.skipSpace()
+		if p.pos < len(p.src) && p.src[p.pos] == ')' {
+			p.pos++
+			return list, nil
+		}
+		v, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, v)
+		p.skipSpace()
+		if p.pos < len(p.src) && p.src[p.pos] == ',' {
+			p.pos++
+		}
+	}
+}
+
+func (p *parser) quoted() (string, error) {
+	p.pos++ // opening quote
+	var b strings.Builder
+	for p.pos < len(p.src) {
+		c := p.src[p.pos]
+		switch c {
+		case '"':
+			p.pos++
+			return b.String(), nil
+		case '\\':
+			if p.pos+1 >= len(p.src) {
+				return "", p.errorf("unterminated escape")
+			}
+			p.pos++
+			switch e := p.src[p.pos]; e {
+			case 'n':
+				b.WriteByte('\n')
+			case 't':
+				b.WriteByte('\t')
+			default:
+				b.WriteByte(e)
+			}
+		default:
+			b.WriteByte(c)
+		}
+		p.pos++
+	}
+	return "", p.errorf("unterminated string")
+}
+
+func (p *parser) bare() (string, error) {
+	start := p.pos
+	for p.pos < len(p.src) && isBareChar(p.src[p.pos]) {
+		p.pos++
+	}
+	if p.pos == start {
+		return "", p.errorf("unexpected character %q", p.src[p.pos])
+	}
+	return p.src[start:p.pos], nil
+}
+
+// isBareChar reports whether c may appear in an unquoted string.
+func isBareChar(c byte) bool {
+	switch {
+	case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		return true
+	}
+	return strings.IndexByte("_$/:.-+", c) >= 0
+}
@@ -0,0 +1,91 @@
+package pbxproj
+
+import (
+	"strings"
+	"testing"
+)
+
+const sample = `// !$*UTF8*$!
+{
+	archiveVersion = 1;
+	objects = {
+
+/* Begin PBXNativeTarget section */
+		AA01 /* Notes */ = {
+			isa = PBXNativeTarget;
+			buildConfigurationList = AA02 /* Build configuration list for PBXNativeTarget "Notes" */;
+			name = Notes;
+			productType = "com.apple.product-type.application";
+		};
+/* End PBXNativeTarget section */
+		AA02 = {
+			isa = XCConfigurationList;
+			buildConfigurations = (
+				AA03 /* Debug */,
+				AA09 /* dangling */,
+			);
+		};
+		AA03 /* Debug */ = {
+			isa = XCBuildConfiguration;
+			buildSettings = {
+				INFOPLIST_KEY_NSCameraUsageDescription = "Scan \"receipts\"";
+				PRODUCT_BUNDLE_IDENTIFIER = com.example.notes;
+				SWIFT_VERSION = 6.0;
+			};
+			name = Debug;
+		};
+		AA00 /* Project object */ = {
+			isa = PBXProject;
+			knownRegions = (en, Base, );
+			targets = (AA01 /* Notes */);
+		};
+	};
+	rootObject = AA00 /* Project object */;
+}
+`
+
+func TestParse(t *testing.T) {
+	f, err := Parse([]byte(sample))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+
+	root := f.Root()
+	if root.Isa() != "PBXProject" {
+		t.Fatalf("Root().Isa() = %q, want PBXProject", root.Isa())
+	}
+	if got := root.Strings("knownRegions"); len(got) != 2 || got[0] != "en" || got[1] != "Base" {
+		t.Errorf("knownRegions = %v, want [en Base]", got)
+	}
+
+	targets := f.Refs(root, "targets")
+	if len(targets) != 1 || targets[0].String("name") != "Notes" {
+		t.Fatalf("targets = %v, want [Notes]", targets)
+	}
+	configs := f.Refs(f.Ref(targets[0], "buildConfigurationList"), "buildConfigurations")
+	if len(configs) != 1 {
+		t.Fatalf("buildConfigurations = %d, want 1 (dangling IDs skipped)", len(configs))
+	}
+	settings := configs[0].Map("buildSettings")
+	if got := settings["INFOPLIST_KEY_NSCameraUsageDescription"]; got != `Scan "receipts"` {
+		t.Errorf("quoted setting = %q", got)
+	}
+	if got := settings["SWIFT_VERSION"]; got != "6.0" {
+		t.Errorf("SWIFT_VERSION = %q, want 6.0", got)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for name, src := range map[string]string{
+		"unterminated string": `{ a = "b; }`,
+		"missing semicolon":   "{\n a = b\n}",
+		"missing root":        `{ objects = {}; rootObject = X; }`,
+		"not a dictionary":    `( a, b )`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			if _, err := Parse([]byte(src)); err == nil || !strings.HasPrefix(err.Error(), "pbxproj:") {
+				t.Errorf("Parse error = %v, want a pbxproj error", err)
+			}
+		})
+	}
+}
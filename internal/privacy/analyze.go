@@ -0,0 +1,306 @@
+package privacy
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Inputs are what the analyzer knows about a project besides its sources.
+type Inputs struct {
+	// Permissions are the Info.plist usage description keys the app declares.
+	Permissions []string
+	// Packages are the names of the SPM packages the app depends on.
+	Packages []string
+	// Integrations are the IDs of the app's active integrations, e.g. "supabase".
+	Integrations []string
+}
+
+// Evidence is one observation a declaration is based on.
+type Evidence struct {
+	// Source is where it was seen: "Habits/Store.swift:12", "permission
+	// NSCameraUsageDescription", "package firebase-ios-sdk" or "integration supabase".
+	Source string
+	Match  string
+}
+
+func (e Evidence) String() string {
+	if e.Match == "" {
+		return e.Source
+	}
+	return fmt.Sprintf("%s (%s)", e.Source, e.Match)
+}
+
+// CollectedData is a data type the app collects.
+type CollectedData struct {
+	DataType
+	Linked   bool
+	Tracking bool
+	Purposes []string
+	Evidence []Evidence
+}
+
+// AccessedAPI is a required-reason API category the app uses.
+type AccessedAPI struct {
+	Category string
+	Reasons  []string
+	Evidence []Evidence
+}
+
+// Report is what the app collects and which required-reason APIs it uses,
+// with the evidence behind each declaration.
+type Report struct {
+	Tracking         bool
+	TrackingEvidence []Evidence
+	// OffDevice is evidence that the app sends data off the device.
+	OffDevice     []Evidence
+	CollectedData []CollectedData
+	AccessedAPIs  []AccessedAPI
+}
+
+// Analyze scans the Swift sources under projectDir together with the
+// project's permissions, packages and integrations.
+func Analyze(projectDir string, in Inputs) (*Report, error) {
+	hits, err := scanSources(projectDir)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &Report{}
+	r.OffDevice = append(hits.find(networkPatterns), integrationEvidence(in.Integrations, backendIntegrations)...)
+	r.TrackingEvidence = append(hits.find(trackingPatterns), permissionEvidence(in.Permissions, []string{"NSUserTrackingUsageDescription"})...)
+
+	collected := map[string]*CollectedData{}
+	var order []string
+	for _, rule := range dataRules {
+		evidence := hits.find(rule.patterns)
+		if len(rule.sameFile) > 0 {
+			evidence = hits.inFilesOf(evidence, rule.sameFile)
+		}
+		evidence = append(evidence, permissionEvidence(in.Permissions, rule.permissions)...)
+		evidence = append(evidence, packageEvidence(in.Packages, rule.packages)...)
+		evidence = append(evidence, integrationEvidence(in.Integrations, rule.integrations)...)
+		if len(evidence) == 0 || (rule.onDevice && len(r.OffDevice) == 0) {
+			continue
+		}
+		cd, ok := collected[rule.dataType]
+		if !ok {
+			cd = &CollectedData{DataType: dataTypes[rule.dataType]}
+			collected[rule.dataType] = cd
+			order = append(order, rule.dataType)
+		}
+		cd.Linked = cd.Linked || rule.linked
+		cd.Tracking = cd.Tracking || rule.tracking
+		if !contains(cd.Purposes, rule.purpose) {
+			cd.Purposes = append(cd.Purposes, rule.purpose)
+		}
+		cd.Evidence = append(cd.Evidence, evidence...)
+	}
+
+	// Account-scoped data is stored against the user's account when there is one.
+	_, hasAccounts := collected["UserID"]
+	for _, rule := range dataRules {
+		if cd, ok := collected[rule.dataType]; ok && rule.accountScoped && hasAccounts {
+			cd.Linked = true
+		}
+	}
+	for _, key := range order {
+		cd := collected[key]
+		r.CollectedData = append(r.CollectedData, *cd)
+		if cd.Tracking {
+			r.Tracking = true
+		}
+	}
+	if len(r.TrackingEvidence) > 0 {
+		r.Tracking = true
+	}
+
+	apis := map[string]*AccessedAPI{}
+	var apiOrder []string
+	for _, rule := range apiRules {
+		evidence := hits.find(rule.patterns)
+		if len(evidence) == 0 {
+			continue
+		}
+		api, ok := apis[rule.category]
+		if !ok {
+			api = &AccessedAPI{Category: rule.category}
+			apis[rule.category] = api
+			apiOrder = append(apiOrder, rule.category)
+		}
+		api.Reasons = append(api.Reasons, rule.reason)
+		api.Evidence = append(api.Evidence, evidence...)
+	}
+	for _, category := range apiOrder {
+		r.AccessedAPIs = append(r.AccessedAPIs, *apis[category])
+	}
+	return r, nil
+}
+
+// sourceHits maps each pattern seen in the sources to where it was seen.
+type sourceHits map[string][]Evidence
+
+func (h sourceHits) find(patterns []string) []Evidence {
+	var out []Evidence
+	for _, p := range patterns {
+		out = append(out, h[p]...)
+	}
+	return out
+}
+
+// inFilesOf keeps the evidence found in files where one of patterns was seen.
+func (h sourceHits) inFilesOf(evidence []Evidence, patterns []string) []Evidence {
+	files := map[string]bool{}
+	for _, e := range h.find(patterns) {
+		files[e.file()] = true
+	}
+	var out []Evidence
+	for _, e := range evidence {
+		if files[e.file()] {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+// file returns the source file of code evidence.
+func (e Evidence) file() string {
+	if i := strings.LastIndex(e.Source, ":"); i >= 0 {
+		return e.Source[:i]
+	}
+	return e.Source
+}
+
+// allPatterns is every source pattern a rule looks for.
+func allPatterns() []string {
+	seen := map[string]bool{}
+	var out []string
+	add := func(ps []string) {
+		for _, p := range ps {
+			if !seen[p] {
+				seen[p] = true
+				out = append(out, p)
+			}
+		}
+	}
+	for _, r := range dataRules {
+		add(r.patterns)
+		add(r.sameFile)
+	}
+	for _, r := range apiRules {
+		add(r.patterns)
+	}
+	add(networkPatterns)
+	add(trackingPatterns)
+	return out
+}
+
+// scanSources records the first line each pattern appears on in every
+// shipping Swift file. Test targets, build output and comment lines are skipped.
+func scanSources(projectDir string) (sourceHits, error) {
+	patterns := allPatterns()
+	hits := sourceHits{}
+	err := filepath.WalkDir(projectDir, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return nil // skip unreadable paths
+		}
+		if d.IsDir() {
+			base := d.Name()
+			if path != projectDir && (strings.HasPrefix(base, ".") || base == "build" || base == "DerivedData" || strings.HasSuffix(base, "Tests")) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(path, ".swift") {
+			return nil
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			return nil
+		}
+		defer f.Close()
+		rel, _ := filepath.Rel(projectDir, path)
+		seen := map[string]bool{}
+		scanner := bufio.NewScanner(f)
+		scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+		for line := 1; scanner.Scan(); line++ {
+			text := strings.TrimSpace(scanner.Text())
+			if strings.HasPrefix(text, "//") || strings.HasPrefix(text, "*") || strings.HasPrefix(text, "/*") {
+				continue
+			}
+			for _, p := range patterns {
+				if !seen[p] && containsPattern(text, p) {
+					seen[p] = true
+					hits[p] = append(hits[p], Evidence{Source: fmt.Sprintf("%s:%d", filepath.ToSlash(rel), line), Match: p})
+				}
+			}
+		}
+		return nil
+	})
+	return hits, err
+}
+
+// containsPattern reports whether text contains p where an identifier starts,
+// so fstatfs( is not taken for statfs(.
+func containsPattern(text, p string) bool {
+	for i := 0; ; {
+		j := strings.Index(text[i:], p)
+		if j < 0 {
+			return false
+		}
+		at := i + j
+		if at == 0 || !isIdentByte(p[0]) || !isIdentByte(text[at-1]) {
+			return true
+		}
+		i = at + 1
+	}
+}
+
+func isIdentByte(c byte) bool {
+	return c == '_' || '0' <= c && c <= '9' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z'
+}
+
+func permissionEvidence(declared, wanted []string) []Evidence {
+	var out []Evidence
+	for _, key := range wanted {
+		if contains(declared, key) {
+			out = append(out, Evidence{Source: "permission " + key})
+		}
+	}
+	return out
+}
+
+func packageEvidence(declared, wanted []string) []Evidence {
+	var out []Evidence
+	for _, name := range declared {
+		lower := strings.ToLower(name)
+		for _, w := range wanted {
+			if strings.Contains(lower, w) {
+				out = append(out, Evidence{Source: "package " + name})
+				break
+			}
+		}
+	}
+	return out
+}
+
+func integrationEvidence(active, wanted []string) []Evidence {
+	var out []Evidence
+	for _, id := range active {
+		if contains(wanted, id) {
+			out = append(out, Evidence{Source: "integration " + id})
+		}
+	}
+	return out
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
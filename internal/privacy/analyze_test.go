@@ -0,0 +1,291 @@
+package privacy
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeSource(t *testing.T, projectDir, rel, content string) {
+	t.Helper()
+	path := filepath.Join(projectDir, rel)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func findData(r *Report, key string) *CollectedData {
+	for i := range r.CollectedData {
+		if r.CollectedData[i].Key == "NSPrivacyCollectedDataType"+key {
+			return &r.CollectedData[i]
+		}
+	}
+	return nil
+}
+
+func TestAnalyzeOfflineApp(t *testing.T) {
+	projectDir := t.TempDir()
+	writeSource(t, projectDir, "Habits/Settings.swift", `import SwiftUI
+
+struct SettingsView: View {
+    @AppStorage("reminders") var reminders = true
+    // let photos = PhotosPicker(...)
+    var body: some View { PhotosPicker("Pick", selection: $item) }
+}
+`)
+	writeSource(t, projectDir, "HabitsTests/StoreTests.swift", "let uptime = ProcessInfo.processInfo.systemUptime\n")
+	writeSource(t, projectDir, "build/Generated.swift", "let session = URLSession.shared\n")
+
+	r, err := Analyze(projectDir, Inputs{Permissions: []string{"NSPhotoLibraryUsageDescription"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Photos never leave the device, so nothing is collected.
+	if len(r.CollectedData) != 0 || len(r.OffDevice) != 0 {
+		t.Errorf("CollectedData = %+v, OffDevice = %v, want none", r.CollectedData, r.OffDevice)
+	}
+	if len(r.AccessedAPIs) != 1 || r.AccessedAPIs[0].Category != "NSPrivacyAccessedAPICategoryUserDefaults" {
+		t.Fatalf("AccessedAPIs = %+v, want only UserDefaults (tests and build output are skipped)", r.AccessedAPIs)
+	}
+	if got := r.AccessedAPIs[0].Evidence[0].String(); got != "Habits/Settings.swift:4 (@AppStorage)" {
+		t.Errorf("evidence = %q", got)
+	}
+	if answers := r.AppPrivacy(); answers.CollectsData || len(answers.DataTypes) != 0 {
+		t.Errorf("AppPrivacy() = %+v, want no data collected", answers)
+	}
+}
+
+func TestAnalyzeBackedApp(t *testing.T) {
+	projectDir := t.TempDir()
+	writeSource(t, projectDir, "Habits/AuthService.swift", `import Supabase
+
+final class AuthService {
+    func signIn(email: String, password: String) async throws {
+        try await supabase.auth.signIn(email: email, password: password)
+    }
+}
+`)
+	writeSource(t, projectDir, "Habits/Camera.swift", "let picker = UIImagePickerController()\n")
+	writeSource(t, projectDir, "Habits/Shared.swift", "let defaults = UserDefaults(suiteName: \"group.habits\")\n")
+
+	r, err := Analyze(projectDir, Inputs{
+		Permissions:  []string{"NSCameraUsageDescription"},
+		Packages:     []string{"supabase-swift", "sentry-cocoa"},
+		Integrations: []string{"supabase"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	email := findData(r, "EmailAddress")
+	if email == nil {
+		t.Fatalf("email not collected: %+v", r.CollectedData)
+	}
+	if email.Linked {
+		t.Error("email linked without a user ID")
+	}
+	if photos := findData(r, "PhotosorVideos"); photos == nil || len(photos.Evidence) != 2 {
+		t.Errorf("photos = %+v, want code and permission evidence", photos)
+	}
+	if crash := findData(r, "CrashData"); crash == nil || crash.Evidence[0].Source != "package sentry-cocoa" {
+		t.Errorf("crash data = %+v", crash)
+	}
+	if r.Tracking {
+		t.Error("tracking declared without tracking evidence")
+	}
+
+	var reasons []string
+	for _, api := range r.AccessedAPIs {
+		reasons = append(reasons, api.Reasons...)
+	}
+	if strings.Join(reasons, ",") != "CA92.1,1C8F.1" {
+		t.Errorf("reasons = %v", reasons)
+	}
+
+	manifest := string(r.Manifest())
+	for _, want := range []string{
+		"<key>NSPrivacyTracking</key>\n\t<false/>",
+		"<string>NSPrivacyCollectedDataTypeEmailAddress</string>",
+		"<string>NSPrivacyCollectedDataTypePurposeAppFunctionality</string>",
+		"<string>1C8F.1</string>",
+	} {
+		if !strings.Contains(manifest, want) {
+			t.Errorf("manifest missing %q:\n%s", want, manifest)
+		}
+	}
+}
+
+func TestAnalyzeAccountsLinkData(t *testing.T) {
+	projectDir := t.TempDir()
+	writeSource(t, projectDir, "Habits/Auth.swift", `let session = try await supabase.auth.session
+try await supabase.auth.signIn(email: email, password: password)
+`)
+	r, err := Analyze(projectDir, Inputs{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if email := findData(r, "EmailAddress"); email == nil || !email.Linked {
+		t.Errorf("email = %+v, want linked to the user's account", email)
+	}
+}
+
+func TestAnalyzeTracking(t *testing.T) {
+	projectDir := t.TempDir()
+	writeSource(t, projectDir, "Ads/Ads.swift", "GADMobileAds.shared.start()\n")
+	r, err := Analyze(projectDir, Inputs{Permissions: []string{"NSUserTrackingUsageDescription"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !r.Tracking {
+		t.Error("tracking not declared")
+	}
+	ads := findData(r, "AdvertisingData")
+	if ads == nil || !ads.Tracking || ads.Purposes[0] != PurposeThirdPartyAdvertising {
+		t.Errorf("advertising data = %+v", ads)
+	}
+}
+
+func TestWrite(t *testing.T) {
+	projectDir := t.TempDir()
+	writeSource(t, projectDir, "Habits/Sync.swift", "let task = URLSession.shared.dataTask(with: url)\nlet x = HKHealthStore()\n")
+	r, err := Analyze(projectDir, Inputs{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	manifestPath, err := r.Write(projectDir, "Habits")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if manifestPath != filepath.Join(projectDir, "Habits", ManifestFile) {
+		t.Errorf("manifest path = %s", manifestPath)
+	}
+
+	data, err := os.ReadFile(filepath.Join(projectDir, AppPrivacyFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var answers AppPrivacy
+	if err := json.Unmarshal(data, &answers); err != nil {
+		t.Fatal(err)
+	}
+	if !answers.CollectsData || len(answers.DataTypes) != 1 || answers.DataTypes[0].DataType != "Health" || answers.DataTypes[0].Purposes[0] != "App Functionality" {
+		t.Errorf("answers = %+v", answers)
+	}
+
+	report, err := os.ReadFile(filepath.Join(projectDir, ReportFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"### Health (Health & Fitness)", "- Habits/Sync.swift:2 (HKHealthStore)", "- Habits/Sync.swift:1 (URLSession)"} {
+		if !strings.Contains(string(report), want) {
+			t.Errorf("report missing %q:\n%s", want, report)
+		}
+	}
+}
+
+func TestAnalyzeMatchesWholeCalls(t *testing.T) {
+	projectDir := t.TempDir()
+	writeSource(t, projectDir, "Notes/Disk.swift", "let free = fstatfs(fd, &stats)\n")
+	writeSource(t, projectDir, "Notes/Feed.swift", `let notes: [Note] = try await supabase
+    .from("notes")
+    .select()
+    .execute()
+    .value
+`)
+	writeSource(t, projectDir, "Notes/Editor.swift", `try await supabase
+    .from("notes")
+    .insert(note)
+    .execute()
+`)
+	r, err := Analyze(projectDir, Inputs{Integrations: []string{"supabase"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, api := range r.AccessedAPIs {
+		for _, e := range api.Evidence {
+			if e.Match == "statfs(" {
+				t.Errorf("statfs( matched inside fstatfs( at %s", e.Source)
+			}
+		}
+	}
+	content := findData(r, "OtherUserContent")
+	if content == nil || len(content.Evidence) != 1 || content.Evidence[0].String() != "Notes/Editor.swift:3 (.insert()" {
+		t.Errorf("other user content = %+v, want only the insert", content)
+	}
+}
+
+func TestWriteMergesExistingManifest(t *testing.T) {
+	projectDir := t.TempDir()
+	writeSource(t, projectDir, "Habits/Store.swift", "let defaults = UserDefaults.standard\nlet up = ProcessInfo.processInfo.systemUptime\n")
+	writeSource(t, projectDir, "Habits/"+ManifestFile, `<?xml version="1.0" encoding="UTF-8"?>
+<plist version="1.0">
+<dict>
+	<key>NSPrivacyTracking</key>
+	<false/>
+	<key>NSPrivacyTrackingDomains</key>
+	<array>
+		<string>metrics.example.com</string>
+	</array>
+	<key>NSPrivacyCollectedDataTypes</key>
+	<array>
+		<dict>
+			<key>NSPrivacyCollectedDataType</key>
+			<string>NSPrivacyCollectedDataTypeCoarseLocation</string>
+			<key>NSPrivacyCollectedDataTypeLinked</key>
+			<false/>
+			<key>NSPrivacyCollectedDataTypeTracking</key>
+			<false/>
+			<key>NSPrivacyCollectedDataTypePurposes</key>
+			<array>
+				<string>NSPrivacyCollectedDataTypePurposeAppFunctionality</string>
+			</array>
+		</dict>
+	</array>
+	<key>NSPrivacyAccessedAPITypes</key>
+	<array>
+		<dict>
+			<key>NSPrivacyAccessedAPIType</key>
+			<string>NSPrivacyAccessedAPICategoryUserDefaults</string>
+			<key>NSPrivacyAccessedAPITypeReasons</key>
+			<array>
+				<string>C56D.1</string>
+			</array>
+		</dict>
+	</array>
+</dict>
+</plist>
+`)
+	r, err := Analyze(projectDir, Inputs{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	manifestPath, err := r.Write(projectDir, "Habits")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(manifestPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := parseManifest(data)
+	if err != nil {
+		t.Fatalf("parseManifest() error: %v\n%s", err, data)
+	}
+	if len(got.TrackingDomains) != 1 || got.TrackingDomains[0] != "metrics.example.com" {
+		t.Errorf("tracking domains = %v, want the hand-written domain kept", got.TrackingDomains)
+	}
+	if len(got.CollectedData) != 1 || got.CollectedData[0].Key != "NSPrivacyCollectedDataTypeCoarseLocation" {
+		t.Errorf("collected data = %+v, want the hand-written type kept", got.CollectedData)
+	}
+	reasons := map[string]string{}
+	for _, api := range got.AccessedAPIs {
+		reasons[api.Category] = strings.Join(api.Reasons, ",")
+	}
+	if reasons["NSPrivacyAccessedAPICategoryUserDefaults"] != "C56D.1,CA92.1" || reasons["NSPrivacyAccessedAPICategorySystemBootTime"] != "35F9.1" {
+		t.Errorf("reasons = %v, want the hand-written reason kept and the found ones added", reasons)
+	}
+}
@@ -0,0 +1,269 @@
+package privacy
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// manifest is the content of a PrivacyInfo.xcprivacy file.
+type manifest struct {
+	Tracking        bool
+	TrackingDomains []string
+	CollectedData   []manifestDataType
+	AccessedAPIs    []manifestAPI
+}
+
+type manifestDataType struct {
+	Key      string
+	Linked   bool
+	Tracking bool
+	Purposes []string
+}
+
+type manifestAPI struct {
+	Category string
+	Reasons  []string
+}
+
+// manifest returns the declarations the report makes.
+func (r *Report) manifest() manifest {
+	m := manifest{Tracking: r.Tracking}
+	for _, cd := range r.CollectedData {
+		m.CollectedData = append(m.CollectedData, manifestDataType{Key: cd.Key, Linked: cd.Linked, Tracking: cd.Tracking, Purposes: cd.Purposes})
+	}
+	for _, api := range r.AccessedAPIs {
+		m.AccessedAPIs = append(m.AccessedAPIs, manifestAPI{Category: api.Category, Reasons: api.Reasons})
+	}
+	return m
+}
+
+// merge adds what generated declares and m lacks. Declarations already in m,
+// such as hand-written tracking domains or a data type's linkage, are kept as
+// written: the analyzer only adds data types, API categories and reasons.
+func (m *manifest) merge(generated manifest) {
+	m.Tracking = m.Tracking || generated.Tracking
+	for _, cd := range generated.CollectedData {
+		found := false
+		for _, existing := range m.CollectedData {
+			found = found || existing.Key == cd.Key
+		}
+		if !found {
+			m.CollectedData = append(m.CollectedData, cd)
+		}
+	}
+	for _, api := range generated.AccessedAPIs {
+		i := 0
+		for i < len(m.AccessedAPIs) && m.AccessedAPIs[i].Category != api.Category {
+			i++
+		}
+		if i == len(m.AccessedAPIs) {
+			m.AccessedAPIs = append(m.AccessedAPIs, api)
+			continue
+		}
+		for _, reason := range api.Reasons {
+			if !contains(m.AccessedAPIs[i].Reasons, reason) {
+				m.AccessedAPIs[i].Reasons = append(m.AccessedAPIs[i].Reasons, reason)
+			}
+		}
+	}
+}
+
+// render writes the manifest as a property list.
+func (m manifest) render() []byte {
+	var b bytes.Buffer
+	b.WriteString(`<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+`)
+	b.WriteString("\t<key>NSPrivacyTracking</key>\n")
+	fmt.Fprintf(&b, "\t<%t/>\n", m.Tracking)
+	b.WriteString("\t<key>NSPrivacyTrackingDomains</key>\n")
+	if len(m.TrackingDomains) == 0 {
+		b.WriteString("\t<array/>\n")
+	} else {
+		b.WriteString("\t<array>\n")
+		for _, domain := range m.TrackingDomains {
+			b.WriteString("\t\t<string>")
+			xml.EscapeText(&b, []byte(domain))
+			b.WriteString("</string>\n")
+		}
+		b.WriteString("\t</array>\n")
+	}
+
+	b.WriteString("\t<key>NSPrivacyCollectedDataTypes</key>\n")
+	if len(m.CollectedData) == 0 {
+		b.WriteString("\t<array/>\n")
+	} else {
+		b.WriteString("\t<array>\n")
+		for _, cd := range m.CollectedData {
+			b.WriteString("\t\t<dict>\n")
+			plistString(&b, "NSPrivacyCollectedDataType", cd.Key)
+			fmt.Fprintf(&b, "\t\t\t<key>NSPrivacyCollectedDataTypeLinked</key>\n\t\t\t<%t/>\n", cd.Linked)
+			fmt.Fprintf(&b, "\t\t\t<key>NSPrivacyCollectedDataTypeTracking</key>\n\t\t\t<%t/>\n", cd.Tracking)
+			plistStrings(&b, "NSPrivacyCollectedDataTypePurposes", cd.Purposes)
+			b.WriteString("\t\t</dict>\n")
+		}
+		b.WriteString("\t</array>\n")
+	}
+
+	b.WriteString("\t<key>NSPrivacyAccessedAPITypes</key>\n")
+	if len(m.AccessedAPIs) == 0 {
+		b.WriteString("\t<array/>\n")
+	} else {
+		b.WriteString("\t<array>\n")
+		for _, api := range m.AccessedAPIs {
+			b.WriteString("\t\t<dict>\n")
+			plistString(&b, "NSPrivacyAccessedAPIType", api.Category)
+			plistStrings(&b, "NSPrivacyAccessedAPITypeReasons", api.Reasons)
+			b.WriteString("\t\t</dict>\n")
+		}
+		b.WriteString("\t</array>\n")
+	}
+	b.WriteString("</dict>\n</plist>\n")
+	return b.Bytes()
+}
+
+func plistString(b *bytes.Buffer, key, value string) {
+	fmt.Fprintf(b, "\t\t\t<key>%s</key>\n\t\t\t<string>", key)
+	xml.EscapeText(b, []byte(value))
+	b.WriteString("</string>\n")
+}
+
+func plistStrings(b *bytes.Buffer, key string, values []string) {
+	fmt.Fprintf(b, "\t\t\t<key>%s</key>\n\t\t\t<array>\n", key)
+	for _, v := range values {
+		b.WriteString("\t\t\t\t<string>")
+		xml.EscapeText(b, []byte(v))
+		b.WriteString("</string>\n")
+	}
+	b.WriteString("\t\t\t</array>\n")
+}
+
+// parseManifest reads the declarations of an XML PrivacyInfo.xcprivacy.
+func parseManifest(data []byte) (manifest, error) {
+	root, err := decodePlist(data)
+	if err != nil {
+		return manifest{}, err
+	}
+	dict, ok := root.(map[string]any)
+	if !ok {
+		return manifest{}, fmt.Errorf("privacy manifest is not a dictionary")
+	}
+	m := manifest{TrackingDomains: stringList(dict["NSPrivacyTrackingDomains"])}
+	m.Tracking, _ = dict["NSPrivacyTracking"].(bool)
+	for _, item := range list(dict["NSPrivacyCollectedDataTypes"]) {
+		entry, _ := item.(map[string]any)
+		cd := manifestDataType{Purposes: stringList(entry["NSPrivacyCollectedDataTypePurposes"])}
+		cd.Key, _ = entry["NSPrivacyCollectedDataType"].(string)
+		cd.Linked, _ = entry["NSPrivacyCollectedDataTypeLinked"].(bool)
+		cd.Tracking, _ = entry["NSPrivacyCollectedDataTypeTracking"].(bool)
+		if cd.Key != "" {
+			m.CollectedData = append(m.CollectedData, cd)
+		}
+	}
+	for _, item := range list(dict["NSPrivacyAccessedAPITypes"]) {
+		entry, _ := item.(map[string]any)
+		api := manifestAPI{Reasons: stringList(entry["NSPrivacyAccessedAPITypeReasons"])}
+		api.Category, _ = entry["NSPrivacyAccessedAPIType"].(string)
+		if api.Category != "" {
+			m.AccessedAPIs = append(m.AccessedAPIs, api)
+		}
+	}
+	return m, nil
+}
+
+func list(v any) []any {
+	items, _ := v.([]any)
+	return items
+}
+
+func stringList(v any) []string {
+	var out []string
+	for _, item := range list(v) {
+		if s, ok := item.(string); ok {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// decodePlist decodes an XML property list into maps, slices, strings and
+// bools. Numbers, dates and data are kept as their text.
+func decodePlist(data []byte) (any, error) {
+	d := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil, fmt.Errorf("property list has no value")
+			}
+			return nil, err
+		}
+		if start, ok := tok.(xml.StartElement); ok && start.Name.Local != "plist" {
+			return decodePlistValue(d, start)
+		}
+	}
+}
+
+func decodePlistValue(d *xml.Decoder, start xml.StartElement) (any, error) {
+	switch start.Name.Local {
+	case "true", "false":
+		return start.Name.Local == "true", d.Skip()
+	case "dict":
+		dict := map[string]any{}
+		key := ""
+		for {
+			tok, err := d.Token()
+			if err != nil {
+				return nil, err
+			}
+			switch t := tok.(type) {
+			case xml.EndElement:
+				return dict, nil
+			case xml.StartElement:
+				if t.Name.Local == "key" {
+					var k string
+					if err := d.DecodeElement(&k, &t); err != nil {
+						return nil, err
+					}
+					key = strings.TrimSpace(k)
+					continue
+				}
+				v, err := decodePlistValue(d, t)
+				if err != nil {
+					return nil, err
+				}
+				dict[key] = v
+			}
+		}
+	case "array":
+		items := []any{}
+		for {
+			tok, err := d.Token()
+			if err != nil {
+				return nil, err
+			}
+			switch t := tok.(type) {
+			case xml.EndElement:
+				return items, nil
+			case xml.StartElement:
+				v, err := decodePlistValue(d, t)
+				if err != nil {
+					return nil, err
+				}
+				items = append(items, v)
+			}
+		}
+	default:
+		var text string
+		if err := d.DecodeElement(&text, &start); err != nil {
+			return nil, err
+		}
+		return text, nil
+	}
+}
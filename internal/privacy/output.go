@@ -0,0 +1,194 @@
+package privacy
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ManifestFile is the privacy manifest's name inside the app's source directory.
+const ManifestFile = "PrivacyInfo.xcprivacy"
+
+// Project files written next to the manifest, relative to the project directory.
+const (
+	// AppPrivacyFile holds the App Privacy questionnaire answers for App Store Connect.
+	AppPrivacyFile = "privacy/app_privacy.json"
+	// ReportFile explains the evidence behind every declaration.
+	ReportFile = "privacy/report.md"
+)
+
+// Manifest renders the report as a PrivacyInfo.xcprivacy property list.
+func (r *Report) Manifest() []byte {
+	return r.manifest().render()
+}
+
+// AppPrivacy is the answer set for the App Privacy questionnaire in App Store Connect.
+type AppPrivacy struct {
+	CollectsData bool                 `json:"collects_data"`
+	Tracking     bool                 `json:"tracking"`
+	DataTypes    []AppPrivacyDataType `json:"data_types"`
+}
+
+// AppPrivacyDataType answers the questionnaire for one collected data type.
+type AppPrivacyDataType struct {
+	Category        string   `json:"category"`
+	DataType        string   `json:"data_type"`
+	Purposes        []string `json:"purposes"`
+	LinkedToUser    bool     `json:"linked_to_user"`
+	UsedForTracking bool     `json:"used_for_tracking"`
+}
+
+// AppPrivacy returns the questionnaire answers matching the manifest.
+func (r *Report) AppPrivacy() AppPrivacy {
+	answers := AppPrivacy{CollectsData: len(r.CollectedData) > 0, Tracking: r.Tracking, DataTypes: []AppPrivacyDataType{}}
+	for _, cd := range r.CollectedData {
+		var purposes []string
+		for _, p := range cd.Purposes {
+			purposes = append(purposes, purposeLabels[p])
+		}
+		answers.DataTypes = append(answers.DataTypes, AppPrivacyDataType{
+			Category:        cd.Category,
+			DataType:        cd.Label,
+			Purposes:        purposes,
+			LinkedToUser:    cd.Linked,
+			UsedForTracking: cd.Tracking,
+		})
+	}
+	return answers
+}
+
+// Summary returns one line per collected data type, e.g.
+// "Email Address: App Functionality (linked)".
+func (r *Report) Summary() []string {
+	var lines []string
+	for _, d := range r.AppPrivacy().DataTypes {
+		var flags []string
+		if d.LinkedToUser {
+			flags = append(flags, "linked")
+		}
+		if d.UsedForTracking {
+			flags = append(flags, "tracking")
+		}
+		line := fmt.Sprintf("%s: %s", d.DataType, strings.Join(d.Purposes, ", "))
+		if len(flags) > 0 {
+			line += " (" + strings.Join(flags, ", ") + ")"
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// maxReportEvidence caps the evidence listed per declaration in the report.
+const maxReportEvidence = 5
+
+// Markdown renders the evidence report.
+func (r *Report) Markdown() string {
+	var b strings.Builder
+	b.WriteString("# Privacy report\n\n")
+	b.WriteString("Generated by nanowave from the app's sources, permissions, packages and integrations.\n")
+	b.WriteString("Review it before submitting: the analyzer only sees what the code shows.\n")
+
+	b.WriteString("\n## Data sent off the device\n\n")
+	if len(r.OffDevice) == 0 {
+		b.WriteString("No networking or backend found, so on-device data such as photos or location is not declared as collected.\n")
+	} else {
+		writeEvidence(&b, r.OffDevice)
+	}
+
+	b.WriteString("\n## Collected data\n\n")
+	if len(r.CollectedData) == 0 {
+		b.WriteString("None. Answer \"No, we do not collect data from this app\" in App Privacy.\n")
+	}
+	for _, cd := range r.CollectedData {
+		fmt.Fprintf(&b, "### %s (%s)\n\n", cd.Label, cd.Category)
+		var purposes []string
+		for _, p := range cd.Purposes {
+			purposes = append(purposes, purposeLabels[p])
+		}
+		fmt.Fprintf(&b, "Purposes: %s. Linked to the user: %s. Used for tracking: %s.\n\n", strings.Join(purposes, ", "), yesNo(cd.Linked), yesNo(cd.Tracking))
+		writeEvidence(&b, cd.Evidence)
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n## Required-reason APIs\n\n")
+	if len(r.AccessedAPIs) == 0 {
+		b.WriteString("None found.\n")
+	}
+	for _, api := range r.AccessedAPIs {
+		fmt.Fprintf(&b, "### %s\n\n", strings.TrimPrefix(api.Category, "NSPrivacyAccessedAPICategory"))
+		for _, reason := range api.Reasons {
+			for _, rule := range apiRules {
+				if rule.category == api.Category && rule.reason == reason {
+					fmt.Fprintf(&b, "Reason %s: %s.\n", reason, rule.label)
+				}
+			}
+		}
+		b.WriteString("\n")
+		writeEvidence(&b, api.Evidence)
+		b.WriteString("\n")
+	}
+
+	if len(r.TrackingEvidence) > 0 {
+		b.WriteString("\n## Tracking\n\n")
+		writeEvidence(&b, r.TrackingEvidence)
+	}
+	return b.String()
+}
+
+func writeEvidence(b *strings.Builder, evidence []Evidence) {
+	for i, e := range evidence {
+		if i == maxReportEvidence {
+			fmt.Fprintf(b, "- and %d more\n", len(evidence)-i)
+			break
+		}
+		fmt.Fprintf(b, "- %s\n", e)
+	}
+}
+
+func yesNo(v bool) string {
+	if v {
+		return "yes"
+	}
+	return "no"
+}
+
+// Write stores the manifest in the app's source directory and the
+// questionnaire answers and evidence report under privacy/. An existing
+// manifest is merged rather than replaced, so hand-written declarations such
+// as tracking domains survive. The manifest path is returned.
+func (r *Report) Write(projectDir, appName string) (string, error) {
+	manifestPath := filepath.Join(projectDir, appName, ManifestFile)
+	if err := os.MkdirAll(filepath.Dir(manifestPath), 0o755); err != nil {
+		return "", err
+	}
+	m := r.manifest()
+	if existing, err := os.ReadFile(manifestPath); err == nil {
+		parsed, err := parseManifest(existing)
+		if err != nil {
+			return "", fmt.Errorf("%s: %w", ManifestFile, err)
+		}
+		parsed.merge(m)
+		m = parsed
+	} else if !os.IsNotExist(err) {
+		return "", err
+	}
+	if err := os.WriteFile(manifestPath, m.render(), 0o644); err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(filepath.Join(projectDir, filepath.Dir(AppPrivacyFile)), 0o755); err != nil {
+		return "", err
+	}
+	answers, err := json.MarshalIndent(r.AppPrivacy(), "", "  ")
+	if err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(filepath.Join(projectDir, AppPrivacyFile), append(answers, '\n'), 0o644); err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(filepath.Join(projectDir, ReportFile), []byte(r.Markdown()), 0o644); err != nil {
+		return "", err
+	}
+	return manifestPath, nil
+}
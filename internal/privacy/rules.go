@@ -0,0 +1,155 @@
+package privacy
+
+// Purposes of collected data, as NSPrivacyCollectedDataTypePurpose values.
+const (
+	PurposeAppFunctionality      = "NSPrivacyCollectedDataTypePurposeAppFunctionality"
+	PurposeAnalytics             = "NSPrivacyCollectedDataTypePurposeAnalytics"
+	PurposeThirdPartyAdvertising = "NSPrivacyCollectedDataTypePurposeThirdPartyAdvertising"
+)
+
+// purposeLabels are the App Privacy questionnaire names of the purposes.
+var purposeLabels = map[string]string{
+	PurposeAppFunctionality:      "App Functionality",
+	PurposeAnalytics:             "Analytics",
+	PurposeThirdPartyAdvertising: "Third-Party Advertising",
+}
+
+// DataType is a collected data type, as declared in the privacy manifest and
+// answered in the App Privacy questionnaire.
+type DataType struct {
+	// Key is the NSPrivacyCollectedDataType value.
+	Key string
+	// Category and Label name the type in the App Privacy questionnaire.
+	Category string
+	Label    string
+}
+
+// dataTypes is the table of data types the analyzer can infer.
+var dataTypes = map[string]DataType{
+	"EmailAddress":       {"NSPrivacyCollectedDataTypeEmailAddress", "Contact Info", "Email Address"},
+	"Name":               {"NSPrivacyCollectedDataTypeName", "Contact Info", "Name"},
+	"PhoneNumber":        {"NSPrivacyCollectedDataTypePhoneNumber", "Contact Info", "Phone Number"},
+	"UserID":             {"NSPrivacyCollectedDataTypeUserID", "Identifiers", "User ID"},
+	"DeviceID":           {"NSPrivacyCollectedDataTypeDeviceID", "Identifiers", "Device ID"},
+	"PreciseLocation":    {"NSPrivacyCollectedDataTypePreciseLocation", "Location", "Precise Location"},
+	"PhotosorVideos":     {"NSPrivacyCollectedDataTypePhotosorVideos", "User Content", "Photos or Videos"},
+	"AudioData":          {"NSPrivacyCollectedDataTypeAudioData", "User Content", "Audio Data"},
+	"OtherUserContent":   {"NSPrivacyCollectedDataTypeOtherUserContent", "User Content", "Other User Content"},
+	"Contacts":           {"NSPrivacyCollectedDataTypeContacts", "Contacts", "Contacts"},
+	"Health":             {"NSPrivacyCollectedDataTypeHealth", "Health & Fitness", "Health"},
+	"Fitness":            {"NSPrivacyCollectedDataTypeFitness", "Health & Fitness", "Fitness"},
+	"PurchaseHistory":    {"NSPrivacyCollectedDataTypePurchaseHistory", "Purchases", "Purchase History"},
+	"ProductInteraction": {"NSPrivacyCollectedDataTypeProductInteraction", "Usage Data", "Product Interaction"},
+	"AdvertisingData":    {"NSPrivacyCollectedDataTypeAdvertisingData", "Usage Data", "Advertising Data"},
+	"CrashData":          {"NSPrivacyCollectedDataTypeCrashData", "Diagnostics", "Crash Data"},
+	"PerformanceData":    {"NSPrivacyCollectedDataTypePerformanceData", "Diagnostics", "Performance Data"},
+}
+
+// dataRule infers a collected data type from code, permissions, packages or
+// integrations. Patterns are Swift identifiers and call shapes, matched from
+// the start of an identifier; packages are lowercase substrings of SPM package
+// names.
+type dataRule struct {
+	dataType string
+	purpose  string
+	patterns []string
+	// sameFile, when set, only counts patterns in files that also contain one
+	// of these, e.g. a Supabase write next to the table it writes to.
+	sameFile     []string
+	permissions  []string
+	packages     []string
+	integrations []string
+	// onDevice data only counts as collected when the app also sends data off
+	// the device, since Apple defines collection as transmission.
+	onDevice bool
+	// linked data is tied to the user's identity. Account-scoped data is
+	// linked only when the app has user accounts.
+	linked        bool
+	accountScoped bool
+	tracking      bool
+}
+
+var dataRules = []dataRule{
+	{dataType: "EmailAddress", purpose: PurposeAppFunctionality, onDevice: true, accountScoped: true,
+		patterns: []string{"signIn(email:", "signUp(email:", "signInWithOTP(email:", "resetPasswordForEmail(", ".textContentType(.emailAddress)"}},
+	{dataType: "Name", purpose: PurposeAppFunctionality, onDevice: true, accountScoped: true,
+		patterns: []string{".fullName", ".textContentType(.name)", ".textContentType(.givenName)"}},
+	{dataType: "PhoneNumber", purpose: PurposeAppFunctionality, onDevice: true, accountScoped: true,
+		patterns: []string{"signIn(phone:", "signInWithOTP(phone:", ".textContentType(.telephoneNumber)"}},
+	{dataType: "UserID", purpose: PurposeAppFunctionality, linked: true,
+		patterns:     []string{"ASAuthorizationAppleIDCredential", "auth.session", "auth.currentUser", "auth.user(", "signInWithIdToken", "appUserID"},
+		integrations: []string{"revenuecat"}},
+	{dataType: "PreciseLocation", purpose: PurposeAppFunctionality, onDevice: true, accountScoped: true,
+		patterns:    []string{"CLLocationManager", "CLLocationUpdate"},
+		permissions: []string{"NSLocationWhenInUseUsageDescription", "NSLocationAlwaysAndWhenInUseUsageDescription"}},
+	{dataType: "PhotosorVideos", purpose: PurposeAppFunctionality, onDevice: true, accountScoped: true,
+		patterns:    []string{"PhotosPicker", "PHPickerViewController", "UIImagePickerController"},
+		permissions: []string{"NSPhotoLibraryUsageDescription", "NSCameraUsageDescription"}},
+	{dataType: "AudioData", purpose: PurposeAppFunctionality, onDevice: true, accountScoped: true,
+		patterns:    []string{"AVAudioRecorder", "SFSpeechRecognizer"},
+		permissions: []string{"NSMicrophoneUsageDescription"}},
+	{dataType: "Contacts", purpose: PurposeAppFunctionality, onDevice: true, accountScoped: true,
+		patterns:    []string{"CNContactStore", "CNContactPickerViewController"},
+		permissions: []string{"NSContactsUsageDescription"}},
+	{dataType: "Health", purpose: PurposeAppFunctionality, onDevice: true, accountScoped: true,
+		patterns:    []string{"HKHealthStore"},
+		permissions: []string{"NSHealthShareUsageDescription", "NSHealthUpdateUsageDescription"}},
+	{dataType: "Fitness", purpose: PurposeAppFunctionality, onDevice: true, accountScoped: true,
+		patterns:    []string{"CMPedometer", "CMMotionActivityManager"},
+		permissions: []string{"NSMotionUsageDescription"}},
+	{dataType: "OtherUserContent", purpose: PurposeAppFunctionality, accountScoped: true,
+		patterns: []string{".insert(", ".upsert(", ".update("}, sameFile: []string{`.from("`}},
+	{dataType: "PurchaseHistory", purpose: PurposeAppFunctionality, linked: true,
+		patterns:     []string{"Purchases.shared", "Purchases.configure"},
+		packages:     []string{"purchases-ios", "revenuecat"},
+		integrations: []string{"revenuecat"}},
+	{dataType: "ProductInteraction", purpose: PurposeAnalytics,
+		patterns: []string{"Analytics.logEvent", "Amplitude(", "Mixpanel.initialize", "PostHogSDK", "TelemetryDeck."},
+		packages: []string{"firebase", "amplitude", "mixpanel", "posthog", "telemetrydeck"}},
+	{dataType: "CrashData", purpose: PurposeAppFunctionality,
+		patterns: []string{"SentrySDK.start", "Crashlytics.crashlytics()", "Bugsnag.start"},
+		packages: []string{"sentry", "crashlytics", "bugsnag"}},
+	{dataType: "PerformanceData", purpose: PurposeAppFunctionality,
+		patterns: []string{"tracesSampleRate"},
+		packages: []string{"sentry"}},
+	{dataType: "DeviceID", purpose: PurposeThirdPartyAdvertising, tracking: true,
+		patterns: []string{"advertisingIdentifier", "ASIdentifierManager"},
+		packages: []string{"googlemobileads", "google-mobile-ads"}},
+	{dataType: "AdvertisingData", purpose: PurposeThirdPartyAdvertising, tracking: true,
+		patterns: []string{"GADMobileAds", "GADBannerView", "BannerView(", "InterstitialAd."},
+		packages: []string{"googlemobileads", "google-mobile-ads"}},
+}
+
+// networkPatterns show the app sends data off the device.
+var networkPatterns = []string{"URLSession", "URLRequest", "supabase.", "SupabaseClient", "Alamofire", "WebSocket"}
+
+// backendIntegrations always move app data off the device.
+var backendIntegrations = []string{"supabase"}
+
+// trackingPatterns show the app tracks users across apps and websites.
+var trackingPatterns = []string{"ATTrackingManager", "requestTrackingAuthorization"}
+
+// apiRule is an Apple "required reason" API category, detected by the Swift
+// APIs that fall under it, with the approved reason nanowave apps use it for.
+type apiRule struct {
+	category string
+	reason   string
+	// Label describes the reason in the evidence report.
+	label    string
+	patterns []string
+}
+
+var apiRules = []apiRule{
+	{"NSPrivacyAccessedAPICategoryUserDefaults", "CA92.1", "read and write the app's own defaults",
+		[]string{"UserDefaults", "@AppStorage", "NSUserDefaults"}},
+	{"NSPrivacyAccessedAPICategoryUserDefaults", "1C8F.1", "share defaults with the app group",
+		[]string{"UserDefaults(suiteName:"}},
+	{"NSPrivacyAccessedAPICategoryFileTimestamp", "C617.1", "timestamps of files inside the app container",
+		[]string{"creationDateKey", "contentModificationDateKey", "attributesOfItem(", "FileAttributeKey.creationDate", "FileAttributeKey.modificationDate", "getattrlist(", "fstat(", "lstat("}},
+	{"NSPrivacyAccessedAPICategorySystemBootTime", "35F9.1", "measure elapsed time between in-app events",
+		[]string{"systemUptime", "mach_absolute_time("}},
+	{"NSPrivacyAccessedAPICategoryDiskSpace", "E174.1", "check there is space before writing files",
+		[]string{"volumeAvailableCapacity", "systemFreeSize", "statfs(", "fstatfs("}},
+	{"NSPrivacyAccessedAPICategoryActiveKeyboards", "54BD.1", "adapt the UI to the active keyboards",
+		[]string{"activeInputModes"}},
+}
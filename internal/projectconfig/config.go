@@ -0,0 +1,362 @@
+// Package projectconfig manages project_config.json, the source of truth for a
+// generated app's Xcode project: loading and validating it, regenerating
+// project.yml and the .xcodeproj from it, editing adopted projects' project.yml
+// in place, the app's version and build number, and locking Swift packages to
+// the versions a build resolved. The MCP server's tools and the CLI share it.
+package projectconfig
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"github.com/moasq/nanowave/internal/xcodegen"
+)
+
+// Config is the source of truth for Xcode project configuration. It is stored
+// as project_config.json and project.yml is regenerated from it.
+type Config struct {
+	AppName           string            `json:"app_name"`
+	BundleID          string            `json:"bundle_id"`
+	Platform          string            `json:"platform,omitempty"`
+	Platforms         []string          `json:"platforms,omitempty"`
+	WatchProjectShape string            `json:"watch_project_shape,omitempty"`
+	DeviceFamily      string            `json:"device_family,omitempty"`
+	Permissions       []Permission      `json:"permissions,omitempty"`
+	Extensions        []ExtensionPlan   `json:"extensions,omitempty"`
+	Localizations     []string          `json:"localizations,omitempty"`
+	Entitlements      []Entitlement     `json:"entitlements,omitempty"`
+	BuildSettings     map[string]string `json:"build_settings,omitempty"`
+	Packages          []PackageDep      `json:"packages,omitempty"`
+	// InterfaceStyle locks iOS/tvOS appearance ("Light" or "Dark"); empty follows the system.
+	InterfaceStyle string `json:"interface_style,omitempty"`
+	// StoreKitConfiguration is the .storekit file used by the run scheme.
+	StoreKitConfiguration string `json:"storekit_configuration,omitempty"`
+	// Configurations are build configurations beyond Debug and Release, or
+	// values for those two, each backed by Configs/<Name>.xcconfig.
+	Configurations []BuildConfiguration `json:"configurations,omitempty"`
+	// UnitTests and UITests add the <App>Tests and <App>UITests bundles.
+	UnitTests bool `json:"unit_tests,omitempty"`
+	UITests   bool `json:"ui_tests,omitempty"`
+	// URLSchemes are custom URL schemes the app opens, e.g. for deep links.
+	URLSchemes []string `json:"url_schemes,omitempty"`
+	// MarketingVersion (MARKETING_VERSION) and BuildNumber
+	// (CURRENT_PROJECT_VERSION) apply to the app and every embedded target.
+	MarketingVersion string `json:"marketing_version,omitempty"`
+	BuildNumber      int    `json:"build_number,omitempty"`
+	// Source is "project.yml" for adopted projects: the hand-written project.yml
+	// stays the source of truth and the tools edit it in place.
+	Source string `json:"source,omitempty"`
+}
+
+// SourceProjectYML marks a config inferred from an existing project.yml.
+const SourceProjectYML = "project.yml"
+
+// Permission describes a required iOS permission.
+type Permission struct {
+	Key         string `json:"key"`
+	Description string `json:"description"`
+	Framework   string `json:"framework"`
+}
+
+// Entitlement describes a single entitlement entry.
+type Entitlement struct {
+	Key    string `json:"key"`
+	Value  any    `json:"value"`
+	Target string `json:"target,omitempty"` // empty = main app
+}
+
+// ExtensionPlan describes a secondary Xcode target.
+type ExtensionPlan struct {
+	Kind         string            `json:"kind"`
+	Name         string            `json:"name"`
+	Purpose      string            `json:"purpose"`
+	Platform     string            `json:"platform,omitempty"`
+	InfoPlist    map[string]any    `json:"info_plist,omitempty"`
+	Entitlements map[string]any    `json:"entitlements,omitempty"`
+	Settings     map[string]string `json:"settings,omitempty"`
+}
+
+// BuildConfiguration describes an Xcode build configuration and the values its
+// .xcconfig file sets.
+type BuildConfiguration struct {
+	Name           string            `json:"name"`
+	Type           string            `json:"type,omitempty"` // debug or release (default)
+	BundleIDSuffix string            `json:"bundle_id_suffix,omitempty"`
+	DisplayName    string            `json:"display_name,omitempty"`
+	AppIcon        string            `json:"app_icon,omitempty"`
+	BackendURL     string            `json:"backend_url,omitempty"`
+	Values         map[string]string `json:"values,omitempty"`
+}
+
+// PackageDep describes an SPM package dependency for the Xcode project.
+type PackageDep struct {
+	Name       string `json:"name"`
+	URL        string `json:"url,omitempty"`
+	MinVersion string `json:"min_version,omitempty"`
+	// ExactVersion or MaxVersion pin the package instead of resolving from
+	// MinVersion up to the next major version.
+	ExactVersion string `json:"exact_version,omitempty"`
+	MaxVersion   string `json:"max_version,omitempty"`
+	// Path is set instead of URL for local packages under Packages/.
+	Path     string   `json:"path,omitempty"`
+	Products []string `json:"products,omitempty"`
+	// Resolved is the version a successful build resolved, captured from
+	// Package.resolved. project.yml pins it exactly until the package is unlocked.
+	Resolved string `json:"resolved,omitempty"`
+}
+
+// Load reads project_config.json from the working directory.
+func Load(workDir string) (*Config, error) {
+	path := filepath.Join(workDir, "project_config.json")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read project_config.json: %w", err)
+	}
+	var cfg Config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse project_config.json: %w", err)
+	}
+	if cfg.InterfaceStyle == "" && !hasKey(data, "interface_style") {
+		cfg.InterfaceStyle = specInterfaceStyle(workDir)
+	}
+	return &cfg, nil
+}
+
+// hasKey reports whether the JSON object in data has the given top-level key.
+func hasKey(data []byte, key string) bool {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return false
+	}
+	_, ok := fields[key]
+	return ok
+}
+
+// specInterfaceStyle returns the interface style locked in the existing
+// project.yml, or "" if there is none. Configs written before interface_style
+// was recorded rely on it to keep the appearance project.yml was generated with.
+func specInterfaceStyle(workDir string) string {
+	spec, err := xcodegen.Load(filepath.Join(workDir, "project.yml"))
+	if err != nil {
+		return ""
+	}
+	for _, name := range spec.Targets.Keys() {
+		target, _ := spec.Targets.Get(name)
+		if target == nil || target.Settings == nil {
+			continue
+		}
+		if style, ok := target.Settings.Base.Get("INFOPLIST_KEY_UIUserInterfaceStyle"); ok {
+			if s, ok := style.(string); ok && s != "" {
+				return s
+			}
+		}
+	}
+	return ""
+}
+
+// Save writes project_config.json to the working directory.
+func Save(workDir string, cfg *Config) error {
+	data, err := json.MarshalIndent(cfg, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal config: %w", err)
+	}
+	return os.WriteFile(filepath.Join(workDir, "project_config.json"), data, 0o644)
+}
+
+// projectModel converts the config into the shared XcodeGen project model.
+// Entitlements targeting an extension are merged into that extension's entitlements.
+func projectModel(cfg *Config) *xcodegen.Project {
+	p := &xcodegen.Project{
+		AppName:               cfg.AppName,
+		BundleID:              cfg.BundleID,
+		Platform:              cfg.Platform,
+		Platforms:             cfg.Platforms,
+		WatchProjectShape:     cfg.WatchProjectShape,
+		DeviceFamily:          cfg.DeviceFamily,
+		Localizations:         cfg.Localizations,
+		BuildSettings:         cfg.BuildSettings,
+		InterfaceStyle:        cfg.InterfaceStyle,
+		StoreKitConfiguration: cfg.StoreKitConfiguration,
+		UnitTests:             cfg.UnitTests,
+		UITests:               cfg.UITests,
+		URLSchemes:            cfg.URLSchemes,
+		MarketingVersion:      cfg.MarketingVersion,
+		BuildNumber:           cfg.BuildNumber,
+	}
+	for _, perm := range cfg.Permissions {
+		p.Permissions = append(p.Permissions, xcodegen.Permission{Key: perm.Key, Description: perm.Description})
+	}
+	for _, ext := range cfg.Extensions {
+		name := ExtensionTargetName(ext, cfg.AppName)
+		entitlements := make(map[string]any)
+		for k, v := range ext.Entitlements {
+			entitlements[k] = v
+		}
+		for _, ent := range cfg.Entitlements {
+			if ent.Target == name {
+				entitlements[ent.Key] = ent.Value
+			}
+		}
+		p.Extensions = append(p.Extensions, xcodegen.Extension{
+			Kind:         ext.Kind,
+			Name:         name,
+			Platform:     ext.Platform,
+			InfoPlist:    ext.InfoPlist,
+			Entitlements: entitlements,
+			Settings:     ext.Settings,
+		})
+	}
+	for _, ent := range cfg.Entitlements {
+		if ent.Target == "" || ent.Target == cfg.AppName {
+			if p.Entitlements == nil {
+				p.Entitlements = make(map[string]any)
+			}
+			p.Entitlements[ent.Key] = ent.Value
+		}
+	}
+	for _, c := range cfg.Configurations {
+		p.Configurations = append(p.Configurations, xcodegen.BuildConfiguration{
+			Name:           c.Name,
+			Type:           c.Type,
+			BundleIDSuffix: c.BundleIDSuffix,
+			DisplayName:    c.DisplayName,
+			AppIcon:        c.AppIcon,
+			BackendURL:     c.BackendURL,
+			Values:         c.Values,
+		})
+	}
+	for _, pkg := range cfg.Packages {
+		exact := pkg.ExactVersion
+		if exact == "" && pkg.URL != "" {
+			exact = pkg.Resolved
+		}
+		p.Packages = append(p.Packages, xcodegen.Package{
+			Name:         pkg.Name,
+			URL:          pkg.URL,
+			MinVersion:   pkg.MinVersion,
+			ExactVersion: exact,
+			MaxVersion:   pkg.MaxVersion,
+			Path:         pkg.Path,
+			Products:     pkg.Products,
+		})
+	}
+	return p
+}
+
+// generateProjectYAML produces the full project.yml content from the config.
+func generateProjectYAML(cfg *Config) string {
+	return xcodegen.Generate(projectModel(cfg))
+}
+
+// writeProjectFiles writes project.yml and the build configurations' .xcconfig files.
+func writeProjectFiles(workDir string, cfg *Config) error {
+	if err := os.WriteFile(filepath.Join(workDir, "project.yml"), []byte(generateProjectYAML(cfg)), 0o644); err != nil {
+		return fmt.Errorf("failed to write project.yml: %w", err)
+	}
+	return xcodegen.WriteConfigFiles(workDir, projectModel(cfg))
+}
+
+// ExtensionTargetName returns the Xcode target name for an extension.
+func ExtensionTargetName(ext ExtensionPlan, appName string) string {
+	return xcodegen.Extension{Kind: ext.Kind, Name: ext.Name}.TargetName(appName)
+}
+
+// Validate checks that everything the config refers to exists:
+// entitlements name targets of the generated project, extension names are
+// unique and every package declares a URL or a local path. It runs before project.yml is
+// regenerated so a broken edit never reaches xcodegen.
+func Validate(cfg *Config) error {
+	seen := map[string]bool{cfg.AppName: true}
+	if cfg.UnitTests {
+		seen[xcodegen.UnitTestTargetName(cfg.AppName)] = true
+	}
+	if cfg.UITests {
+		seen[xcodegen.UITestTargetName(cfg.AppName)] = true
+	}
+	for _, ext := range cfg.Extensions {
+		name := ExtensionTargetName(ext, cfg.AppName)
+		if seen[name] {
+			return fmt.Errorf("duplicate target %s", name)
+		}
+		seen[name] = true
+	}
+
+	spec := xcodegen.Build(projectModel(cfg))
+	for _, ent := range cfg.Entitlements {
+		if ent.Target == "" {
+			continue
+		}
+		if _, ok := spec.Targets.Get(ent.Target); !ok {
+			return fmt.Errorf("entitlement %s targets %s, which is not a target of the project", ent.Key, ent.Target)
+		}
+	}
+	for _, pkg := range cfg.Packages {
+		if pkg.URL == "" && pkg.Path == "" {
+			return fmt.Errorf("package %s has no URL or path", pkg.Name)
+		}
+	}
+
+	for _, scheme := range cfg.URLSchemes {
+		if !urlSchemePattern.MatchString(scheme) {
+			return fmt.Errorf("URL scheme %q must start with a letter and contain only letters, digits, +, - and .", scheme)
+		}
+	}
+
+	if cfg.MarketingVersion != "" && !marketingVersionPattern.MatchString(cfg.MarketingVersion) {
+		return fmt.Errorf("marketing version %q must be one to three dot-separated numbers, e.g. 1.2 or 1.2.3", cfg.MarketingVersion)
+	}
+	if cfg.BuildNumber < 0 {
+		return fmt.Errorf("build number %d must be positive", cfg.BuildNumber)
+	}
+
+	configs := make(map[string]bool)
+	for _, c := range cfg.Configurations {
+		if !configNamePattern.MatchString(c.Name) {
+			return fmt.Errorf("configuration name %q must start with a letter and contain only letters and digits", c.Name)
+		}
+		if configs[c.Name] {
+			return fmt.Errorf("duplicate configuration %s", c.Name)
+		}
+		configs[c.Name] = true
+		if c.Type != "" && c.Type != "debug" && c.Type != "release" {
+			return fmt.Errorf("configuration %s has type %q, want debug or release", c.Name, c.Type)
+		}
+		if c.BundleIDSuffix != "" && !bundleIDSuffixPattern.MatchString(c.BundleIDSuffix) {
+			return fmt.Errorf("configuration %s has bundle ID suffix %q, want a form like .staging", c.Name, c.BundleIDSuffix)
+		}
+		for key := range c.Values {
+			if !xcconfigKeyPattern.MatchString(key) {
+				return fmt.Errorf("configuration %s value %q is not a valid build setting name", c.Name, key)
+			}
+		}
+	}
+	return nil
+}
+
+var (
+	configNamePattern     = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9]*$`)
+	bundleIDSuffixPattern = regexp.MustCompile(`^(\.[A-Za-z0-9-]+)+$`)
+	xcconfigKeyPattern    = regexp.MustCompile(`^[A-Z][A-Z0-9_]*$`)
+	urlSchemePattern      = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9+.-]*$`)
+	// App Store Connect accepts at most three period-separated integers.
+	marketingVersionPattern = regexp.MustCompile(`^[0-9]+(\.[0-9]+){0,2}$`)
+)
+
+// ValidURLScheme reports whether scheme can be registered as a URL scheme.
+func ValidURLScheme(scheme string) bool {
+	return urlSchemePattern.MatchString(scheme)
+}
+
+// AppGroupDependent returns the first extension that shares data with the main
+// app through its app group, or "" when none does.
+func AppGroupDependent(cfg *Config) string {
+	for _, ext := range cfg.Extensions {
+		if xcodegen.SharesAppGroup(ext.Kind) {
+			return ExtensionTargetName(ext, cfg.AppName)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,415 @@
+package projectconfig
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestGenerateProjectYAMLPairedWatchIncludesCompanionBundleIdentifier(t *testing.T) {
+	cfg := &Config{
+		AppName:           "PulseTrack",
+		BundleID:          "com.example.pulsetrack",
+		Platform:          "watchos",
+		WatchProjectShape: "paired_ios_watch",
+	}
+
+	yml := generateProjectYAML(cfg)
+
+	if !strings.Contains(yml, "WKCompanionAppBundleIdentifier: com.example.pulsetrack") {
+		t.Fatalf("paired watch YAML missing WKCompanionAppBundleIdentifier for iOS companion bundle ID:\n%s", yml)
+	}
+	if !strings.Contains(yml, "WKRunsIndependentlyOfCompanionApp: true") {
+		t.Error("paired watch YAML should preserve WKRunsIndependentlyOfCompanionApp")
+	}
+	checks := []string{
+		"PulseTrackWatchExtension:",
+		"type: watchkit2-extension",
+		"target: PulseTrackWatchExtension",
+		"NSExtensionPointIdentifier: com.apple.watchkit",
+		"WKAppBundleIdentifier: com.example.pulsetrack.watchkitapp",
+	}
+	for _, want := range checks {
+		if !strings.Contains(yml, want) {
+			t.Fatalf("paired watch YAML missing %q:\n%s", want, yml)
+		}
+	}
+}
+
+func TestGenerateProjectYAML_WithPackages(t *testing.T) {
+	cfg := &Config{
+		AppName:  "TestApp",
+		BundleID: "com.example.testapp",
+		Packages: []PackageDep{
+			{
+				Name:       "Lottie",
+				URL:        "https://github.com/airbnb/lottie-ios",
+				MinVersion: "4.0.0",
+			},
+			{
+				Name:       "SDWebImageSwiftUI",
+				URL:        "https://github.com/nicklama/SDWebImageSwiftUI",
+				MinVersion: "2.0.0",
+				Products:   []string{"SDWebImageSwiftUI"},
+			},
+		},
+	}
+
+	yml := generateProjectYAML(cfg)
+
+	// Check packages section exists
+	checks := []string{
+		"packages:",
+		"Lottie:",
+		"url: https://github.com/airbnb/lottie-ios",
+		"from: 4.0.0",
+		"SDWebImageSwiftUI:",
+		"url: https://github.com/nicklama/SDWebImageSwiftUI",
+		"from: 2.0.0",
+		// Check package dependency in target
+		"- package: Lottie",
+		"- package: SDWebImageSwiftUI",
+		// Ensure dependencies section exists
+		"dependencies:",
+	}
+	for _, want := range checks {
+		if !strings.Contains(yml, want) {
+			t.Errorf("YAML missing %q:\n%s", want, yml)
+		}
+	}
+}
+
+func TestGenerateProjectYAML_WithoutPackages(t *testing.T) {
+	cfg := &Config{
+		AppName:  "TestApp",
+		BundleID: "com.example.testapp",
+	}
+
+	yml := generateProjectYAML(cfg)
+
+	// No packages section should be present
+	if strings.Contains(yml, "packages:") {
+		t.Errorf("YAML should not contain packages section when no packages defined:\n%s", yml)
+	}
+}
+
+func TestGenerateProjectYAMLWatchOnlyOmitsCompanionBundleIdentifier(t *testing.T) {
+	cfg := &Config{
+		AppName:           "PulseTrack",
+		BundleID:          "com.example.pulsetrack",
+		Platform:          "watchos",
+		WatchProjectShape: "watch_only",
+	}
+
+	yml := generateProjectYAML(cfg)
+
+	if strings.Contains(yml, "WKCompanionAppBundleIdentifier") {
+		t.Fatal("watch_only YAML should not include WKCompanionAppBundleIdentifier")
+	}
+	if !strings.Contains(yml, "WKWatchOnly: true") {
+		t.Error("watch_only YAML should include WKWatchOnly")
+	}
+	checks := []string{
+		"PulseTrackWatch:",
+		"PulseTrackWatchExtension:",
+		"type: application.watchapp2-container",
+		"type: application.watchapp2",
+		"type: watchkit2-extension",
+		"target: PulseTrackWatch",
+		"target: PulseTrackWatchExtension",
+		"NSExtensionPointIdentifier: com.apple.watchkit",
+		"WKAppBundleIdentifier: com.example.pulsetrack.watchkitapp",
+	}
+	for _, want := range checks {
+		if !strings.Contains(yml, want) {
+			t.Fatalf("watch_only YAML missing %q:\n%s", want, yml)
+		}
+	}
+}
+
+func TestGenerateProjectYAMLKeepsNonIOSPlatforms(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  *Config
+		want []string
+	}{
+		{
+			name: "macOS",
+			cfg:  &Config{AppName: "Notes", BundleID: "com.example.notes", Platform: "macos"},
+			want: []string{"macOS: \"26.0\"", "platform: macOS", "COMBINE_HIDPI_IMAGES: \"YES\""},
+		},
+		{
+			name: "tvOS",
+			cfg:  &Config{AppName: "Flix", BundleID: "com.example.flix", Platform: "tvos", InterfaceStyle: "Dark"},
+			want: []string{"tvOS: \"26.0\"", "TARGETED_DEVICE_FAMILY: \"3\"", "INFOPLIST_KEY_UIUserInterfaceStyle: Dark"},
+		},
+		{
+			name: "visionOS",
+			cfg:  &Config{AppName: "Space", BundleID: "com.example.space", Platform: "visionos"},
+			want: []string{"visionOS: \"26.0\"", "TARGETED_DEVICE_FAMILY: \"7\""},
+		},
+		{
+			name: "multi-platform",
+			cfg: &Config{
+				AppName:   "Focus",
+				BundleID:  "com.example.focus",
+				Platform:  "ios",
+				Platforms: []string{"ios", "macos"},
+				Extensions: []ExtensionPlan{
+					{Kind: "widget", Name: "FocusMacWidget", Platform: "macos"},
+				},
+			},
+			want: []string{"FocusMac:", "PRODUCT_BUNDLE_IDENTIFIER: com.example.focus.mac", "- target: FocusMacWidget"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			yml := generateProjectYAML(tt.cfg)
+			if tt.cfg.Platforms == nil && strings.Contains(yml, "platform: iOS") {
+				t.Errorf("%s project regenerated as iOS:\n%s", tt.name, yml)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(yml, want) {
+					t.Errorf("YAML missing %q:\n%s", want, yml)
+				}
+			}
+		})
+	}
+}
+
+func TestGenerateProjectYAMLTargetedEntitlements(t *testing.T) {
+	cfg := &Config{
+		AppName:    "Trips",
+		BundleID:   "com.example.trips",
+		Extensions: []ExtensionPlan{{Kind: "widget", Name: "TripsWidget"}},
+		Entitlements: []Entitlement{
+			{Key: "com.apple.developer.applesignin", Value: []any{"Default"}},
+			{Key: "com.apple.developer.weatherkit", Value: true, Target: "TripsWidget"},
+		},
+	}
+
+	yml := generateProjectYAML(cfg)
+
+	main, widget, _ := strings.Cut(yml, "\n  TripsWidget:\n")
+	if !strings.Contains(main, "com.apple.developer.applesignin:") {
+		t.Errorf("main target missing its entitlement:\n%s", main)
+	}
+	if strings.Contains(main, "weatherkit") || !strings.Contains(widget, "com.apple.developer.weatherkit: true") {
+		t.Errorf("targeted entitlement should only be on the widget:\n%s", yml)
+	}
+}
+
+func TestGeneratesSpec(t *testing.T) {
+	dir := t.TempDir()
+	if GeneratesSpec(dir) {
+		t.Error("a project without project_config.json should be edited in place")
+	}
+
+	if err := Save(dir, &Config{AppName: "Notes", BundleID: "com.example.notes"}); err != nil {
+		t.Fatal(err)
+	}
+	if !GeneratesSpec(dir) {
+		t.Error("a nanowave-built project should regenerate project.yml from its config")
+	}
+
+	if err := Save(dir, &Config{AppName: "Notes", BundleID: "com.example.notes", Source: SourceProjectYML}); err != nil {
+		t.Fatal(err)
+	}
+	if GeneratesSpec(dir) {
+		t.Error("an adopted project should be edited in place")
+	}
+}
+
+func TestLoadLegacyConfigKeepsInterfaceStyle(t *testing.T) {
+	dir := t.TempDir()
+	legacy := `{"app_name": "Notes", "bundle_id": "com.example.notes", "platform": "ios"}`
+	if err := os.WriteFile(filepath.Join(dir, "project_config.json"), []byte(legacy), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	spec := `name: Notes
+targets:
+  Notes:
+    type: application
+    platform: iOS
+    settings:
+      base:
+        PRODUCT_BUNDLE_IDENTIFIER: com.example.notes
+        INFOPLIST_KEY_UIUserInterfaceStyle: Dark
+`
+	if err := os.WriteFile(filepath.Join(dir, "project.yml"), []byte(spec), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.InterfaceStyle != "Dark" {
+		t.Fatalf("InterfaceStyle = %q, want Dark from the existing project.yml", cfg.InterfaceStyle)
+	}
+	if err := writeProjectFiles(dir, cfg); err != nil {
+		t.Fatal(err)
+	}
+	if err := Save(dir, cfg); err != nil {
+		t.Fatal(err)
+	}
+	yml, err := os.ReadFile(filepath.Join(dir, "project.yml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(yml), "INFOPLIST_KEY_UIUserInterfaceStyle: Dark") {
+		t.Errorf("regenerated project.yml lost the Dark lock:\n%s", yml)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "project_config.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"interface_style": "Dark"`) {
+		t.Errorf("saved config should record the interface style:\n%s", data)
+	}
+
+	// A config that follows the system regenerates without a lock and stays that way.
+	cfg.InterfaceStyle = ""
+	if err := writeProjectFiles(dir, cfg); err != nil {
+		t.Fatal(err)
+	}
+	if err := Save(dir, cfg); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err = Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.InterfaceStyle != "" {
+		t.Errorf("InterfaceStyle = %q, want empty for a project that follows the system", cfg.InterfaceStyle)
+	}
+}
+
+func TestValidateConfigReferences(t *testing.T) {
+	cfg := &Config{
+		AppName:    "Notes",
+		BundleID:   "com.example.notes",
+		Platform:   "ios",
+		Extensions: []ExtensionPlan{{Kind: "widget"}},
+		Entitlements: []Entitlement{
+			{Key: "com.apple.developer.healthkit", Value: true},
+			{Key: "com.apple.developer.healthkit", Value: true, Target: "NotesWidget"},
+		},
+	}
+	if err := Validate(cfg); err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+
+	cfg.Extensions = nil
+	if err := Validate(cfg); err == nil || !strings.Contains(err.Error(), "NotesWidget") {
+		t.Errorf("Validate error = %v, want a dangling NotesWidget entitlement", err)
+	}
+
+	cfg.Entitlements = nil
+	cfg.Extensions = []ExtensionPlan{{Kind: "widget"}, {Kind: "share", Name: "NotesWidget"}}
+	if err := Validate(cfg); err == nil || !strings.Contains(err.Error(), "duplicate") {
+		t.Errorf("Validate error = %v, want a duplicate target", err)
+	}
+}
+
+func TestValidateConfigConfigurations(t *testing.T) {
+	cfg := &Config{
+		AppName:  "Notes",
+		BundleID: "com.example.notes",
+		Platform: "ios",
+		Configurations: []BuildConfiguration{
+			{Name: "Staging", BundleIDSuffix: ".staging", Values: map[string]string{"ANALYTICS_KEY": "abc"}},
+			{Name: "Release", BackendURL: "https://api.example.com"},
+		},
+	}
+	if err := Validate(cfg); err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+
+	for _, tc := range []struct {
+		config BuildConfiguration
+		want   string
+	}{
+		{BuildConfiguration{Name: "Staging"}, "duplicate"},
+		{BuildConfiguration{Name: "QA Build"}, "configuration name"},
+		{BuildConfiguration{Name: "QA", Type: "profile"}, "type"},
+		{BuildConfiguration{Name: "QA", BundleIDSuffix: "qa"}, "suffix"},
+		{BuildConfiguration{Name: "QA", Values: map[string]string{"api key": "x"}}, "build setting"},
+	} {
+		invalid := *cfg
+		invalid.Configurations = append(slices.Clone(cfg.Configurations), tc.config)
+		if err := Validate(&invalid); err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("Validate(%+v) error = %v, want %q", tc.config, err, tc.want)
+		}
+	}
+}
+
+func TestValidateConfigVersion(t *testing.T) {
+	for _, tc := range []struct {
+		version string
+		build   int
+		ok      bool
+	}{
+		{"1.0", 1, true},
+		{"2.10.3", 42, true},
+		{"", 0, true},
+		{"1.0.0.1", 1, false},
+		{"v1.2", 1, false},
+		{"1.2", -1, false},
+	} {
+		cfg := &Config{AppName: "Notes", BundleID: "com.example.notes", MarketingVersion: tc.version, BuildNumber: tc.build}
+		if err := Validate(cfg); (err == nil) != tc.ok {
+			t.Errorf("Validate(%q, %d) error = %v, want ok %v", tc.version, tc.build, err, tc.ok)
+		}
+	}
+}
+
+func TestProjectVersionDefaults(t *testing.T) {
+	dir := t.TempDir()
+	if err := Save(dir, &Config{AppName: "Notes", BundleID: "com.example.notes"}); err != nil {
+		t.Fatal(err)
+	}
+	if marketing, build, err := ProjectVersion(dir); err != nil || marketing != "1.0" || build != 1 {
+		t.Errorf("ProjectVersion() = %q, %d, %v, want 1.0, 1", marketing, build, err)
+	}
+	if err := Save(dir, &Config{AppName: "Notes", MarketingVersion: "1.3", BuildNumber: 9}); err != nil {
+		t.Fatal(err)
+	}
+	if marketing, build, _ := ProjectVersion(dir); marketing != "1.3" || build != 9 {
+		t.Errorf("ProjectVersion() = %q, %d, want 1.3, 9", marketing, build)
+	}
+}
+
+func TestSetProjectVersionAdoptedNeedsSettings(t *testing.T) {
+	dir := t.TempDir()
+	cfg := &Config{AppName: "Notes", BundleID: "com.example.notes", Source: SourceProjectYML, MarketingVersion: "1.0", BuildNumber: 3}
+	if err := Save(dir, cfg); err != nil {
+		t.Fatal(err)
+	}
+	spec := `name: Notes
+configFiles:
+  Release: Configs/Release.xcconfig
+targets:
+  Notes:
+    type: application
+    platform: iOS
+    settings:
+      base:
+        PRODUCT_BUNDLE_IDENTIFIER: com.example.notes
+`
+	if err := os.WriteFile(filepath.Join(dir, "project.yml"), []byte(spec), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := SetProjectVersion(dir, "1.1", 4); err == nil || !strings.Contains(err.Error(), "MARKETING_VERSION") {
+		t.Errorf("SetProjectVersion() error = %v, want MARKETING_VERSION not set", err)
+	}
+	if marketing, build, _ := ProjectVersion(dir); marketing != "1.0" || build != 3 {
+		t.Errorf("ProjectVersion() = %q, %d after a failed edit, want 1.0, 3 kept", marketing, build)
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, "project.yml")); string(data) != spec {
+		t.Errorf("project.yml changed after a failed edit:\n%s", data)
+	}
+}
@@ -0,0 +1,365 @@
+package projectconfig
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// lockSnapshotPath is where the last captured Package.resolved is kept,
+// relative to the project. It restores pins when the .xcodeproj is regenerated.
+const lockSnapshotPath = ".nanowave/Package.resolved"
+
+// resolvedPath returns the Package.resolved Xcode writes for the app's project.
+func resolvedPath(workDir, appName string) string {
+	return filepath.Join(workDir, appName+".xcodeproj", "project.xcworkspace", "xcshareddata", "swiftpm", "Package.resolved")
+}
+
+// resolvedPin is one package pin of a Package.resolved file. Version 1 files
+// name the URL repositoryURL; versions 2 and 3 name it location.
+type resolvedPin struct {
+	Location      string `json:"location"`
+	RepositoryURL string `json:"repositoryURL"`
+	State         struct {
+		Version  string `json:"version"`
+		Revision string `json:"revision"`
+	} `json:"state"`
+}
+
+// parsePackageResolved returns the pinned version of every package in a
+// Package.resolved file, keyed by normalized repository URL. Pins to a branch
+// or revision have no version and are left out.
+func parsePackageResolved(data []byte) (map[string]string, error) {
+	var file struct {
+		Pins   []resolvedPin `json:"pins"`
+		Object struct {
+			Pins []resolvedPin `json:"pins"`
+		} `json:"object"`
+	}
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse Package.resolved: %w", err)
+	}
+	versions := make(map[string]string)
+	for _, pin := range append(file.Pins, file.Object.Pins...) {
+		url := pin.Location
+		if url == "" {
+			url = pin.RepositoryURL
+		}
+		if url != "" && pin.State.Version != "" {
+			versions[NormalizePackageURL(url)] = pin.State.Version
+		}
+	}
+	return versions, nil
+}
+
+// NormalizePackageURL makes repository URLs comparable: SwiftPM records them
+// as written, with or without a .git suffix or trailing slash.
+func NormalizePackageURL(url string) string {
+	url = strings.ToLower(strings.TrimSuffix(url, "/"))
+	return strings.TrimSuffix(url, ".git")
+}
+
+// lockPackages sets Resolved on every unlocked remote package the resolved
+// versions pin and returns the names it locked.
+func lockPackages(cfg *Config, versions map[string]string) []string {
+	var locked []string
+	for i := range cfg.Packages {
+		pkg := &cfg.Packages[i]
+		if pkg.URL == "" || pkg.Resolved != "" {
+			continue
+		}
+		if version, ok := versions[NormalizePackageURL(pkg.URL)]; ok {
+			pkg.Resolved = version
+			locked = append(locked, pkg.Name)
+		}
+	}
+	return locked
+}
+
+// LockResolvedPackages captures the Package.resolved of a successful build:
+// the file is copied to .nanowave/Package.resolved and every remote package
+// not yet locked is pinned to its resolved version, so project.yml requests
+// exactly that version from then on. It returns the packages it locked.
+// Adopted projects and projects Xcode has not resolved yet are left alone.
+func LockResolvedPackages(workDir string) ([]string, error) {
+	if !GeneratesSpec(workDir) {
+		return nil, nil
+	}
+	cfg, err := Load(workDir)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(resolvedPath(workDir, cfg.AppName))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read Package.resolved: %w", err)
+	}
+	versions, err := parsePackageResolved(data)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := filepath.Join(workDir, lockSnapshotPath)
+	if err := os.MkdirAll(filepath.Dir(snapshot), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create directory %s: %w", filepath.Dir(snapshot), err)
+	}
+	if err := os.WriteFile(snapshot, data, 0o644); err != nil {
+		return nil, fmt.Errorf("failed to write %s: %w", lockSnapshotPath, err)
+	}
+
+	locked := lockPackages(cfg, versions)
+	if len(locked) == 0 {
+		return nil, nil
+	}
+	if err := ApplyAndRegenerate(workDir, cfg); err != nil {
+		return nil, err
+	}
+	return locked, nil
+}
+
+// RestoreResolvedPackages copies the captured Package.resolved back into the
+// .xcodeproj when regenerating left it without one, so transitive
+// dependencies resolve to the captured versions too.
+func RestoreResolvedPackages(workDir, appName string) error {
+	data, err := os.ReadFile(filepath.Join(workDir, lockSnapshotPath))
+	if err != nil {
+		return nil
+	}
+	path := resolvedPath(workDir, appName)
+	if _, err := os.Stat(path); err == nil {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create directory %s: %w", filepath.Dir(path), err)
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// PackageUpdate compares a locked package with the newest version its
+// requirement allows.
+type PackageUpdate struct {
+	Name   string
+	Locked string
+	// Available is the newest release within the requirement, or "" when none
+	// could be listed.
+	Available string
+}
+
+// Changed reports whether unlocking the package would move it to another version.
+func (u PackageUpdate) Changed() bool {
+	return u.Available != "" && u.Available != u.Locked
+}
+
+// listRemoteTags returns the tag names of a git repository. Tests replace it.
+var listRemoteTags = func(ctx context.Context, url string) ([]string, error) {
+	out, err := exec.CommandContext(ctx, "git", "ls-remote", "--tags", "--refs", url).Output()
+	if err != nil {
+		return nil, fmt.Errorf("git ls-remote %s failed: %w", url, err)
+	}
+	var tags []string
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 {
+			tags = append(tags, strings.TrimPrefix(fields[1], "refs/tags/"))
+		}
+	}
+	return tags, nil
+}
+
+// CheckPackageUpdates lists the locked packages (all, or the named ones) with
+// the newest version their requirement allows, read from the repository tags.
+func CheckPackageUpdates(ctx context.Context, workDir string, names []string) ([]PackageUpdate, error) {
+	cfg, err := Load(workDir)
+	if err != nil {
+		return nil, err
+	}
+	packages, err := lockedPackages(cfg, names)
+	if err != nil {
+		return nil, err
+	}
+	var updates []PackageUpdate
+	for _, pkg := range packages {
+		update := PackageUpdate{Name: pkg.Name, Locked: pkg.Resolved}
+		tags, err := listRemoteTags(ctx, pkg.URL)
+		if err != nil {
+			return nil, err
+		}
+		update.Available = newestAllowedVersion(pkg, tags)
+		updates = append(updates, update)
+	}
+	return updates, nil
+}
+
+// UnlockPackages clears the lock of the named packages, regenerates the
+// project, drops their pins from Package.resolved and asks Xcode to resolve
+// again. The pins are only dropped once the config is saved, and the new
+// versions are locked once resolution succeeds.
+func UnlockPackages(ctx context.Context, workDir string, names []string) ([]string, error) {
+	cfg, err := Load(workDir)
+	if err != nil {
+		return nil, err
+	}
+	packages, err := lockedPackages(cfg, names)
+	if err != nil {
+		return nil, err
+	}
+	urls := make(map[string]bool)
+	for _, pkg := range packages {
+		urls[NormalizePackageURL(pkg.URL)] = true
+		i := slices.IndexFunc(cfg.Packages, func(p PackageDep) bool { return p.Name == pkg.Name })
+		cfg.Packages[i].Resolved = ""
+	}
+	if err := ApplyAndRegenerate(workDir, cfg); err != nil {
+		return nil, err
+	}
+	if err := UnpinResolved(workDir, cfg.AppName, urls); err != nil {
+		return nil, err
+	}
+
+	resolve := exec.CommandContext(ctx, "xcodebuild", "-resolvePackageDependencies", "-project", cfg.AppName+".xcodeproj")
+	resolve.Dir = workDir
+	if output, err := resolve.CombinedOutput(); err != nil {
+		return nil, fmt.Errorf("xcodebuild -resolvePackageDependencies failed: %w\n%s", err, output)
+	}
+	return LockResolvedPackages(workDir)
+}
+
+// lockedPackages returns the locked packages of the config, or the named ones,
+// which must exist and be locked.
+func lockedPackages(cfg *Config, names []string) ([]PackageDep, error) {
+	if len(names) == 0 {
+		var locked []PackageDep
+		for _, pkg := range cfg.Packages {
+			if pkg.Resolved != "" {
+				locked = append(locked, pkg)
+			}
+		}
+		return locked, nil
+	}
+	var selected []PackageDep
+	for _, name := range names {
+		i := slices.IndexFunc(cfg.Packages, func(p PackageDep) bool { return p.Name == name })
+		if i < 0 {
+			return nil, fmt.Errorf("package %s not found", name)
+		}
+		if cfg.Packages[i].Resolved == "" {
+			return nil, fmt.Errorf("package %s is not locked", name)
+		}
+		selected = append(selected, cfg.Packages[i])
+	}
+	return selected, nil
+}
+
+// UnpinResolved drops the pins of the given repositories from both the
+// captured and the project's Package.resolved, so Xcode resolves them afresh.
+func UnpinResolved(workDir, appName string, urls map[string]bool) error {
+	for _, path := range []string{filepath.Join(workDir, lockSnapshotPath), resolvedPath(workDir, appName)} {
+		if err := dropResolvedPins(path, urls); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// dropResolvedPins removes the pins of the given repositories from a
+// Package.resolved file, keeping every other field as written.
+func dropResolvedPins(path string, urls map[string]bool) error {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	var file map[string]any
+	if err := json.Unmarshal(data, &file); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	holder := file
+	if object, ok := file["object"].(map[string]any); ok {
+		holder = object
+	}
+	pins, _ := holder["pins"].([]any)
+	holder["pins"] = slices.DeleteFunc(pins, func(pin any) bool {
+		fields, _ := pin.(map[string]any)
+		url, _ := fields["location"].(string)
+		if url == "" {
+			url, _ = fields["repositoryURL"].(string)
+		}
+		return urls[NormalizePackageURL(url)]
+	})
+	out, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s: %w", path, err)
+	}
+	return os.WriteFile(path, append(out, '\n'), 0o644)
+}
+
+// newestAllowedVersion returns the newest release tag the package's version
+// requirement admits: exactly ExactVersion, MinVersion up to MaxVersion, or
+// MinVersion up to the next major version. Pre-releases are skipped.
+func newestAllowedVersion(pkg PackageDep, tags []string) string {
+	if pkg.ExactVersion != "" {
+		return pkg.ExactVersion
+	}
+	lower, ok := parseVersion(pkg.MinVersion)
+	if !ok {
+		return ""
+	}
+	upper := [3]int{lower[0] + 1, 0, 0}
+	if pkg.MaxVersion != "" {
+		if max, ok := parseVersion(pkg.MaxVersion); ok {
+			upper = max
+		}
+	}
+
+	var best string
+	var bestVersion [3]int
+	for _, tag := range tags {
+		version, ok := parseVersion(strings.TrimPrefix(tag, "v"))
+		if !ok || compareVersions(version, lower) < 0 || compareVersions(version, upper) >= 0 {
+			continue
+		}
+		if best == "" || compareVersions(version, bestVersion) > 0 {
+			best, bestVersion = strings.TrimPrefix(tag, "v"), version
+		}
+	}
+	return best
+}
+
+// parseVersion parses a major.minor.patch release version.
+func parseVersion(s string) ([3]int, bool) {
+	var v [3]int
+	parts := strings.Split(s, ".")
+	if len(parts) != 3 {
+		return v, false
+	}
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return v, false
+		}
+		v[i] = n
+	}
+	return v, true
+}
+
+func compareVersions(a, b [3]int) int {
+	for i := range a {
+		if a[i] != b[i] {
+			return a[i] - b[i]
+		}
+	}
+	return 0
+}
@@ -0,0 +1,224 @@
+package projectconfig
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const resolvedV3 = `{
+  "originHash" : "5c2b6fa4",
+  "pins" : [
+    {
+      "identity" : "lottie-ios",
+      "kind" : "remoteSourceControl",
+      "location" : "https://github.com/airbnb/lottie-ios.git",
+      "state" : {
+        "revision" : "3c8a3b1a",
+        "version" : "4.5.2"
+      }
+    },
+    {
+      "identity" : "nuke",
+      "kind" : "remoteSourceControl",
+      "location" : "https://github.com/kean/Nuke",
+      "state" : {
+        "revision" : "0ead4435",
+        "version" : "12.8.0"
+      }
+    }
+  ],
+  "version" : 3
+}
+`
+
+const resolvedV1 = `{
+  "object": {
+    "pins": [
+      {
+        "package": "Lottie",
+        "repositoryURL": "https://github.com/airbnb/lottie-ios/",
+        "state": {
+          "branch": null,
+          "revision": "3c8a3b1a",
+          "version": "4.4.0"
+        }
+      },
+      {
+        "package": "Tools",
+        "repositoryURL": "https://github.com/example/tools",
+        "state": {
+          "branch": "main",
+          "revision": "9f1e2d3c",
+          "version": null
+        }
+      }
+    ]
+  },
+  "version": 1
+}
+`
+
+func TestParsePackageResolved(t *testing.T) {
+	v3, err := parsePackageResolved([]byte(resolvedV3))
+	if err != nil {
+		t.Fatalf("parsePackageResolved(v3) error: %v", err)
+	}
+	if v3["https://github.com/airbnb/lottie-ios"] != "4.5.2" || v3["https://github.com/kean/nuke"] != "12.8.0" {
+		t.Errorf("v3 versions = %v", v3)
+	}
+
+	v1, err := parsePackageResolved([]byte(resolvedV1))
+	if err != nil {
+		t.Fatalf("parsePackageResolved(v1) error: %v", err)
+	}
+	if len(v1) != 1 || v1["https://github.com/airbnb/lottie-ios"] != "4.4.0" {
+		t.Errorf("v1 versions = %v, want only the versioned Lottie pin", v1)
+	}
+}
+
+func TestLockedPackagesPinExactVersion(t *testing.T) {
+	cfg := &Config{
+		AppName:  "TestApp",
+		BundleID: "com.example.testapp",
+		Packages: []PackageDep{
+			{Name: "Lottie", URL: "https://github.com/airbnb/lottie-ios", MinVersion: "4.0.0"},
+			{Name: "Nuke", URL: "https://github.com/kean/Nuke", MinVersion: "12.0.0", Resolved: "12.1.0"},
+			{Name: "Core", Path: "Packages/Core"},
+		},
+	}
+	versions, err := parsePackageResolved([]byte(resolvedV3))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	locked := lockPackages(cfg, versions)
+	if len(locked) != 1 || locked[0] != "Lottie" {
+		t.Fatalf("lockPackages() = %v, want [Lottie]; Nuke is already locked", locked)
+	}
+	if cfg.Packages[1].Resolved != "12.1.0" {
+		t.Errorf("Nuke lock = %q, an existing lock must be kept", cfg.Packages[1].Resolved)
+	}
+
+	yml := generateProjectYAML(cfg)
+	for _, want := range []string{"exactVersion: 4.5.2", "exactVersion: 12.1.0", "path: Packages/Core"} {
+		if !strings.Contains(yml, want) {
+			t.Errorf("project.yml missing %q:\n%s", want, yml)
+		}
+	}
+	if strings.Contains(yml, "from: 4.0.0") {
+		t.Errorf("locked package should not resolve from its minimum version:\n%s", yml)
+	}
+}
+
+func TestCheckPackageUpdates(t *testing.T) {
+	dir := t.TempDir()
+	cfg := &Config{
+		AppName:  "TestApp",
+		BundleID: "com.example.testapp",
+		Packages: []PackageDep{
+			{Name: "Lottie", URL: "https://github.com/airbnb/lottie-ios", MinVersion: "4.0.0", Resolved: "4.4.0"},
+			{Name: "Nuke", URL: "https://github.com/kean/Nuke", MinVersion: "12.0.0", MaxVersion: "12.5.0", Resolved: "12.1.0"},
+			{Name: "Kingfisher", URL: "https://github.com/onevcat/Kingfisher", MinVersion: "8.0.0"},
+		},
+	}
+	if err := Save(dir, cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	tags := map[string][]string{
+		"https://github.com/airbnb/lottie-ios": {"4.4.0", "4.5.2", "4.6.0-beta.1", "5.0.0"},
+		"https://github.com/kean/Nuke":         {"12.1.0", "v12.4.1", "12.5.0", "13.0.0"},
+	}
+	original := listRemoteTags
+	listRemoteTags = func(ctx context.Context, url string) ([]string, error) { return tags[url], nil }
+	defer func() { listRemoteTags = original }()
+
+	updates, err := CheckPackageUpdates(context.Background(), dir, nil)
+	if err != nil {
+		t.Fatalf("CheckPackageUpdates() error: %v", err)
+	}
+	want := []PackageUpdate{
+		{Name: "Lottie", Locked: "4.4.0", Available: "4.5.2"},
+		{Name: "Nuke", Locked: "12.1.0", Available: "12.4.1"},
+	}
+	if len(updates) != len(want) {
+		t.Fatalf("updates = %+v, want %+v", updates, want)
+	}
+	for i := range want {
+		if updates[i] != want[i] {
+			t.Errorf("updates[%d] = %+v, want %+v", i, updates[i], want[i])
+		}
+	}
+
+	if _, err := CheckPackageUpdates(context.Background(), dir, []string{"Kingfisher"}); err == nil {
+		t.Error("CheckPackageUpdates(Kingfisher) should fail for an unlocked package")
+	}
+}
+
+func TestDropResolvedPins(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "Package.resolved")
+	if err := os.WriteFile(path, []byte(resolvedV3), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := dropResolvedPins(path, map[string]bool{"https://github.com/airbnb/lottie-ios": true}); err != nil {
+		t.Fatalf("dropResolvedPins() error: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	versions, err := parsePackageResolved(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := versions["https://github.com/airbnb/lottie-ios"]; ok || versions["https://github.com/kean/nuke"] != "12.8.0" {
+		t.Errorf("versions after drop = %v, want only Nuke", versions)
+	}
+	if !strings.Contains(string(data), `"originHash"`) {
+		t.Error("dropResolvedPins should keep the other fields of the file")
+	}
+}
+
+func TestUnlockPackagesKeepsPinsWhenConfigInvalid(t *testing.T) {
+	dir := t.TempDir()
+	cfg := &Config{
+		AppName:      "TestApp",
+		BundleID:     "com.example.testapp",
+		Entitlements: []Entitlement{{Key: "com.apple.security.application-groups", Value: []any{"group.com.example"}, Target: "Missing"}},
+		Packages:     []PackageDep{{Name: "Lottie", URL: "https://github.com/airbnb/lottie-ios", MinVersion: "4.0.0", Resolved: "4.5.2"}},
+	}
+	if err := Save(dir, cfg); err != nil {
+		t.Fatal(err)
+	}
+	for _, path := range []string{filepath.Join(dir, lockSnapshotPath), resolvedPath(dir, cfg.AppName)} {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(resolvedV3), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, err := UnlockPackages(context.Background(), dir, []string{"Lottie"}); err == nil {
+		t.Fatal("UnlockPackages() should fail for an invalid config")
+	}
+	for _, path := range []string{filepath.Join(dir, lockSnapshotPath), resolvedPath(dir, cfg.AppName)} {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(data) != resolvedV3 {
+			t.Errorf("%s changed although the unlock was rejected", path)
+		}
+	}
+	saved, err := Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if saved.Packages[0].Resolved != "4.5.2" {
+		t.Errorf("Resolved = %q, want the lock kept", saved.Packages[0].Resolved)
+	}
+}
@@ -0,0 +1,75 @@
+package projectconfig
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/moasq/nanowave/internal/xcodegen"
+)
+
+// ApplyAndRegenerate validates and saves the config, generates project.yml and
+// the .xcconfig files, and runs xcodegen.
+func ApplyAndRegenerate(workDir string, cfg *Config) error {
+	if err := Validate(cfg); err != nil {
+		return fmt.Errorf("invalid project configuration: %w", err)
+	}
+	if err := Save(workDir, cfg); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+	return Regenerate(workDir, cfg)
+}
+
+// Regenerate writes project.yml and the .xcconfig files from the config, runs
+// xcodegen and restores the locked package versions.
+func Regenerate(workDir string, cfg *Config) error {
+	if err := writeProjectFiles(workDir, cfg); err != nil {
+		return err
+	}
+
+	if err := RunXcodeGen(workDir); err != nil {
+		return err
+	}
+
+	return RestoreResolvedPackages(workDir, cfg.AppName)
+}
+
+// GeneratesSpec reports whether project.yml is generated from project_config.json.
+// Projects without one (a hand-written project.yml) and adopted projects, whose
+// config records project.yml as its source, are edited in place.
+func GeneratesSpec(workDir string) bool {
+	if _, err := os.Stat(filepath.Join(workDir, "project_config.json")); err != nil {
+		return false
+	}
+	cfg, err := Load(workDir)
+	return err != nil || cfg.Source != SourceProjectYML
+}
+
+// EditSpec loads project.yml, applies edit, saves the result and runs xcodegen.
+// Keys the typed spec does not model are preserved.
+func EditSpec(workDir string, edit func(*xcodegen.Spec) error) error {
+	path := filepath.Join(workDir, "project.yml")
+	spec, err := xcodegen.Load(path)
+	if err != nil {
+		return err
+	}
+	if err := edit(spec); err != nil {
+		return err
+	}
+	if err := spec.Save(path); err != nil {
+		return err
+	}
+	return RunXcodeGen(workDir)
+}
+
+// RunXcodeGen runs `xcodegen generate` in the given directory.
+func RunXcodeGen(workDir string) error {
+	cmd := exec.Command("xcodegen", "generate")
+	cmd.Dir = workDir
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("xcodegen generate failed: %w\n%s", err, string(output))
+	}
+	return nil
+}
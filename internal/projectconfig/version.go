@@ -0,0 +1,67 @@
+package projectconfig
+
+import (
+	"fmt"
+
+	"github.com/moasq/nanowave/internal/xcodegen"
+)
+
+// Versions a project starts with when project_config.json records none.
+const (
+	defaultMarketingVersion = "1.0"
+	defaultBuildNumber      = 1
+)
+
+// ProjectVersion returns the marketing version and build number recorded in
+// project_config.json, or 1.0 and 1 when none are recorded yet.
+func ProjectVersion(workDir string) (string, int, error) {
+	cfg, err := Load(workDir)
+	if err != nil {
+		return "", 0, err
+	}
+	marketing, build := cfg.MarketingVersion, cfg.BuildNumber
+	if marketing == "" {
+		marketing = defaultMarketingVersion
+	}
+	if build == 0 {
+		build = defaultBuildNumber
+	}
+	return marketing, build, nil
+}
+
+// SetProjectVersion records the marketing version and build number in
+// project_config.json and regenerates the Xcode project. Adopted projects get
+// both settings edited in place wherever project.yml sets them, before the
+// config records them; it is an error when project.yml sets either nowhere,
+// e.g. because an .xcconfig file does.
+func SetProjectVersion(workDir, marketing string, build int) error {
+	cfg, err := Load(workDir)
+	if err != nil {
+		return err
+	}
+	cfg.MarketingVersion = marketing
+	cfg.BuildNumber = build
+	if GeneratesSpec(workDir) {
+		return ApplyAndRegenerate(workDir, cfg)
+	}
+
+	if err := Validate(cfg); err != nil {
+		return fmt.Errorf("invalid project configuration: %w", err)
+	}
+	err = EditSpec(workDir, func(spec *xcodegen.Spec) error {
+		if spec.ReplaceSetting("MARKETING_VERSION", marketing) == 0 {
+			return fmt.Errorf("project.yml does not set MARKETING_VERSION; set it in the project or target settings so it can be changed")
+		}
+		if spec.ReplaceSetting("CURRENT_PROJECT_VERSION", build) == 0 {
+			return fmt.Errorf("project.yml does not set CURRENT_PROJECT_VERSION; set it in the project or target settings so it can be changed")
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if err := Save(workDir, cfg); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+	return nil
+}
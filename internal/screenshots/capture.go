@@ -20,7 +20,9 @@ type CaptureResult struct {
 	SimUDIDs    map[string]string    // device type -> UDID (kept booted for agent use)
 }
 
-// CaptureFromSimulator builds the app, boots simulator(s), and captures initial screenshots.
+// CaptureFromSimulator builds the app, boots simulator(s), and captures screenshots.
+// With a screenshots/capture_plan.json it visits every screen of the plan under a
+// clean status bar; without one it captures the launch screen.
 // Simulators are left running so the ASC agent can capture additional screens via AXe.
 func CaptureFromSimulator(ctx context.Context, projectDir string, reqs ScreenshotRequirements, progress func(step string)) (*CaptureResult, error) {
 	result := &CaptureResult{
@@ -61,6 +63,23 @@ func CaptureFromSimulator(ctx context.Context, projectDir string, reqs Screensho
 		scheme = strings.TrimSuffix(filepath.Base(xcodeProj), ".xcodeproj")
 	}
 
+	plan, err := LoadCapturePlan(projectDir)
+	if err != nil {
+		return nil, err
+	}
+	if plan != nil {
+		// The plan recaptures every screen; drop captures of screens it no longer lists.
+		stale, _ := filepath.Glob(filepath.Join(result.Dir, "*.png"))
+		for _, path := range stale {
+			_ = os.Remove(path)
+		}
+	}
+	if plan != nil && plan.Driver == DriverUITest {
+		if err := writeUITest(projectDir, cfg.AppName, plan); err != nil {
+			return nil, err
+		}
+	}
+
 	// Determine which simulators are needed
 	type simTarget struct {
 		deviceType string // e.g. "IPHONE_69", "IPAD_PRO_13"
@@ -126,32 +145,35 @@ func CaptureFromSimulator(ctx context.Context, projectDir string, reqs Screensho
 			continue
 		}
 
-		// Launch app
-		progress(fmt.Sprintf("Launching app on %s simulator...", target.label))
-		if err := launchOnSimulator(udid, cfg.BundleID); err != nil {
-			result.Errors = append(result.Errors, fmt.Sprintf("launch %s: %v", target.label, err))
-			log.Printf("[screenshots] failed to launch on %s: %v", target.label, err)
+		if plan == nil {
+			if path, err := captureLaunchScreen(udid, cfg.BundleID, target.label, result.Dir, progress); err != nil {
+				result.Errors = append(result.Errors, err.Error())
+				log.Printf("[screenshots] %v", err)
+			} else {
+				result.addCapture(path, target.label)
+			}
 			continue
 		}
 
-		// Wait for app to render
-		time.Sleep(3 * time.Second)
-
-		// Capture screenshot
-		progress(fmt.Sprintf("Capturing %s screenshot...", target.label))
-		outputPath := filepath.Join(result.Dir, fmt.Sprintf("%s_launch.png", strings.ToLower(target.label)))
-		if err := captureScreenshot(udid, outputPath); err != nil {
-			result.Errors = append(result.Errors, fmt.Sprintf("capture %s: %v", target.label, err))
-			log.Printf("[screenshots] failed to capture %s: %v", target.label, err)
-			continue
+		if err := overrideStatusBar(udid, plan.StatusBar); err != nil {
+			log.Printf("[screenshots] status bar override failed on %s: %v", target.label, err)
+		}
+		var paths []string
+		var errs []error
+		switch plan.Driver {
+		case DriverUITest:
+			progress(fmt.Sprintf("Running screenshot UI test on %s simulator...", target.label))
+			paths, errs = runUITestCapture(ctx, xcodeProj, scheme, cfg.AppName, udid, target.label, result.Dir, derivedData, plan)
+		default:
+			paths, errs = captureDeepLinks(udid, cfg.BundleID, target.label, result.Dir, plan, progress)
+		}
+		for _, err := range errs {
+			result.Errors = append(result.Errors, err.Error())
+			log.Printf("[screenshots] %v", err)
+		}
+		for _, path := range paths {
+			result.addCapture(path, target.label)
 		}
-
-		dt := detectDeviceType(outputPath)
-		result.Screenshots = append(result.Screenshots, UploadedScreenshot{
-			Filename:   filepath.Base(outputPath),
-			DeviceType: dt,
-		})
-		log.Printf("[screenshots] captured %s: %s (device type: %s)", target.label, outputPath, dt)
 	}
 
 	if len(result.Screenshots) == 0 && len(result.Errors) > 0 {
@@ -161,6 +183,124 @@ func CaptureFromSimulator(ctx context.Context, projectDir string, reqs Screensho
 	return result, nil
 }
 
+// addCapture records a captured file with its detected ASC device type.
+func (r *CaptureResult) addCapture(path, label string) {
+	dt := detectDeviceType(path)
+	r.Screenshots = append(r.Screenshots, UploadedScreenshot{
+		Filename:   filepath.Base(path),
+		DeviceType: dt,
+	})
+	log.Printf("[screenshots] captured %s: %s (device type: %s)", label, path, dt)
+}
+
+// captureLaunchScreen launches the app and captures the screen it opens to.
+func captureLaunchScreen(udid, bundleID, label, dir string, progress func(string)) (string, error) {
+	progress(fmt.Sprintf("Launching app on %s simulator...", label))
+	if err := launchOnSimulator(udid, bundleID); err != nil {
+		return "", fmt.Errorf("launch %s: %v", label, err)
+	}
+
+	// Wait for app to render
+	time.Sleep(3 * time.Second)
+
+	progress(fmt.Sprintf("Capturing %s screenshot...", label))
+	outputPath := filepath.Join(dir, fmt.Sprintf("%s_launch.png", strings.ToLower(label)))
+	if err := captureScreenshot(udid, outputPath); err != nil {
+		return "", fmt.Errorf("capture %s: %v", label, err)
+	}
+	return outputPath, nil
+}
+
+// captureDeepLinks launches the app with the plan's launch arguments and
+// captures every screen, opening its deep link first. Screens without a deep
+// link are captured after a fresh launch. A failed screen doesn't stop the rest.
+func captureDeepLinks(udid, bundleID, label, dir string, plan *CapturePlan, progress func(string)) ([]string, []error) {
+	var paths []string
+	var errs []error
+	launched := false
+	for i, screen := range plan.Screens {
+		progress(fmt.Sprintf("Capturing %s %s screen (%d/%d)...", label, screen.Name, i+1, len(plan.Screens)))
+		wait := screen.WaitSeconds
+		if screen.DeepLink == "" || !launched {
+			if err := launchOnSimulator(udid, bundleID, plan.LaunchArguments...); err != nil {
+				errs = append(errs, fmt.Errorf("launch %s: %v", label, err))
+				return paths, errs
+			}
+			launched = true
+			wait = max(wait, 3)
+		}
+		if screen.DeepLink != "" {
+			if err := openURLOnSimulator(udid, screen.DeepLink); err != nil {
+				errs = append(errs, fmt.Errorf("open %s on %s: %v", screen.DeepLink, label, err))
+				continue
+			}
+		}
+		time.Sleep(time.Duration(max(wait, 2)) * time.Second)
+
+		outputPath := filepath.Join(dir, captureName(label, i, screen))
+		if err := captureScreenshot(udid, outputPath); err != nil {
+			errs = append(errs, fmt.Errorf("capture %s %s: %v", label, screen.Name, err))
+			continue
+		}
+		paths = append(paths, outputPath)
+	}
+	return paths, errs
+}
+
+// writeUITest writes the ui_test driver's XCUITest into the app's UI test
+// target, regenerating the Xcode project when the file is new.
+func writeUITest(projectDir, appName string, plan *CapturePlan) error {
+	testDir := filepath.Join(projectDir, appName+"UITests")
+	if info, err := os.Stat(testDir); err != nil || !info.IsDir() {
+		return fmt.Errorf("the %s driver needs the %sUITests target", DriverUITest, appName)
+	}
+	path := filepath.Join(testDir, uiTestClass+".swift")
+	_, statErr := os.Stat(path)
+	if err := os.WriteFile(path, []byte(plan.UITestSource()), 0o644); err != nil {
+		return fmt.Errorf("write %s: %w", filepath.Base(path), err)
+	}
+	if statErr == nil {
+		return nil
+	}
+	cmd := exec.Command("xcodegen", "generate")
+	cmd.Dir = projectDir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("xcodegen generate: %w\n%s", err, out)
+	}
+	return nil
+}
+
+// runUITestCapture runs the generated screenshot UI test on a simulator and
+// returns the captures it wrote. Environment variables prefixed TEST_RUNNER_
+// reach the test runner without the prefix.
+func runUITestCapture(ctx context.Context, xcodeProj, scheme, appName, udid, label, dir, derivedData string, plan *CapturePlan) ([]string, []error) {
+	cmd := exec.CommandContext(ctx, "xcodebuild",
+		"-project", xcodeProj,
+		"-scheme", scheme,
+		"-destination", fmt.Sprintf("platform=iOS Simulator,id=%s", udid),
+		"-derivedDataPath", derivedData,
+		"-only-testing:"+appName+"UITests/"+uiTestClass,
+		"-quiet",
+		"test",
+	)
+	cmd.Env = append(os.Environ(),
+		"TEST_RUNNER_NANOWAVE_SCREENSHOT_DIR="+dir,
+		"TEST_RUNNER_NANOWAVE_SCREENSHOT_PREFIX="+strings.ToLower(label),
+	)
+	var errs []error
+	if out, err := cmd.CombinedOutput(); err != nil {
+		errs = append(errs, fmt.Errorf("screenshot UI test on %s: %w\n%s", label, err, out))
+	}
+	var paths []string
+	for i, screen := range plan.Screens {
+		path := filepath.Join(dir, captureName(label, i, screen))
+		if _, err := os.Stat(path); err == nil {
+			paths = append(paths, path)
+		}
+	}
+	return paths, errs
+}
+
 func findXcodeProj(projectDir string) (string, error) {
 	entries, err := os.ReadDir(projectDir)
 	if err != nil {
@@ -312,8 +452,31 @@ func installOnSimulator(udid, appPath string) error {
 	return nil
 }
 
-func launchOnSimulator(udid, bundleID string) error {
-	out, err := exec.Command("xcrun", "simctl", "launch", udid, bundleID).CombinedOutput()
+// launchOnSimulator (re)launches the app, passing args to it.
+func launchOnSimulator(udid, bundleID string, args ...string) error {
+	cmdArgs := append([]string{"simctl", "launch", "--terminate-running-process", udid, bundleID}, args...)
+	out, err := exec.Command("xcrun", cmdArgs...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%s: %s", err, out)
+	}
+	return nil
+}
+
+func openURLOnSimulator(udid, url string) error {
+	out, err := exec.Command("xcrun", "simctl", "openurl", udid, url).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%s: %s", err, out)
+	}
+	return nil
+}
+
+// overrideStatusBar sets the simulator's status bar for captures.
+func overrideStatusBar(udid string, bar StatusBar) error {
+	args := bar.args()
+	if len(args) == 0 {
+		return nil
+	}
+	out, err := exec.Command("xcrun", append([]string{"simctl", "status_bar", udid, "override"}, args...)...).CombinedOutput()
 	if err != nil {
 		return fmt.Errorf("%s: %s", err, out)
 	}
@@ -0,0 +1,101 @@
+package screenshots
+
+// DisplayType is an App Store Connect screenshot display type.
+type DisplayType struct {
+	// ID is the device type passed to asc, e.g. "IPHONE_69".
+	ID string
+	// ASC is the App Store Connect screenshotDisplayType, e.g. "APP_IPHONE_67".
+	ASC      string
+	Label    string
+	Platform string
+	// Sizes are the accepted pixel sizes; the first is the one framed
+	// screenshots are written at.
+	Sizes [][2]int
+	// Rotates accepts each size in both orientations.
+	Rotates bool
+}
+
+// displayTypes is the App Store Connect display type table. Types sharing a
+// size resolve to the first one when detected without a platform.
+var displayTypes = []DisplayType{
+	{ID: "IPHONE_69", ASC: "APP_IPHONE_67", Label: `iPhone 6.9"`, Platform: "ios", Rotates: true,
+		Sizes: [][2]int{{1320, 2868}, {1290, 2796}, {1260, 2736}}},
+	{ID: "IPHONE_65", ASC: "APP_IPHONE_65", Label: `iPhone 6.5"`, Platform: "ios", Rotates: true,
+		Sizes: [][2]int{{1284, 2778}, {1242, 2688}}},
+	{ID: "IPHONE_63", ASC: "APP_IPHONE_61", Label: `iPhone 6.3"`, Platform: "ios", Rotates: true,
+		Sizes: [][2]int{{1179, 2556}, {1206, 2622}}},
+	{ID: "IPAD_PRO_13", ASC: "APP_IPAD_PRO_3GEN_129", Label: `iPad 13"`, Platform: "ios", Rotates: true,
+		Sizes: [][2]int{{2064, 2752}, {2048, 2732}}},
+	{ID: "IPAD_PRO_11", ASC: "APP_IPAD_PRO_3GEN_11", Label: `iPad 11"`, Platform: "ios", Rotates: true,
+		Sizes: [][2]int{{1668, 2420}, {1668, 2388}, {1640, 2360}, {1488, 2266}}},
+	{ID: "DESKTOP", ASC: "APP_DESKTOP", Label: "Mac", Platform: "macos",
+		Sizes: [][2]int{{2880, 1800}, {2560, 1600}, {1440, 900}, {1280, 800}}},
+	{ID: "APPLE_TV", ASC: "APP_APPLE_TV", Label: "Apple TV", Platform: "tvos",
+		Sizes: [][2]int{{3840, 2160}, {1920, 1080}}},
+	{ID: "APPLE_VISION_PRO", ASC: "APP_APPLE_VISION_PRO", Label: "Apple Vision Pro", Platform: "visionos",
+		Sizes: [][2]int{{3840, 2160}}},
+	{ID: "WATCH_ULTRA", ASC: "APP_WATCH_ULTRA", Label: "Apple Watch Ultra", Platform: "watchos",
+		Sizes: [][2]int{{422, 514}, {410, 502}}},
+	{ID: "WATCH_SERIES_10", ASC: "APP_WATCH_SERIES_10", Label: "Apple Watch Series 10", Platform: "watchos",
+		Sizes: [][2]int{{416, 496}, {374, 446}}},
+	{ID: "WATCH_SERIES_7", ASC: "APP_WATCH_SERIES_7", Label: "Apple Watch Series 7", Platform: "watchos",
+		Sizes: [][2]int{{396, 484}, {352, 430}}},
+	{ID: "WATCH_SERIES_4", ASC: "APP_WATCH_SERIES_4", Label: "Apple Watch Series 4", Platform: "watchos",
+		Sizes: [][2]int{{368, 448}, {324, 394}}},
+	{ID: "WATCH_SERIES_3", ASC: "APP_WATCH_SERIES_3", Label: "Apple Watch Series 3", Platform: "watchos",
+		Sizes: [][2]int{{312, 390}, {272, 340}}},
+}
+
+// watchTypes are the Apple Watch display types; any one of them satisfies the
+// watchOS requirement.
+var watchTypes = []string{"WATCH_ULTRA", "WATCH_SERIES_10", "WATCH_SERIES_7", "WATCH_SERIES_4", "WATCH_SERIES_3"}
+
+// alternatives maps a required display type to the other types ASC accepts
+// in its place.
+var alternatives = map[string][]string{
+	"IPHONE_69":   {"IPHONE_65"},
+	"WATCH_ULTRA": watchTypes[1:],
+}
+
+// DisplayTypes returns the App Store Connect display type table.
+func DisplayTypes() []DisplayType {
+	return displayTypes
+}
+
+// LookupDisplayType returns the display type with the given ID.
+func LookupDisplayType(id string) (DisplayType, bool) {
+	for _, t := range displayTypes {
+		if t.ID == id {
+			return t, true
+		}
+	}
+	return DisplayType{}, false
+}
+
+// Accepts reports whether a w×h screenshot has one of the type's sizes.
+func (t DisplayType) Accepts(w, h int) bool {
+	for _, s := range t.Sizes {
+		if (w == s[0] && h == s[1]) || (t.Rotates && w == s[1] && h == s[0]) {
+			return true
+		}
+	}
+	return false
+}
+
+// deviceTypeFor returns the display type of a w×h screenshot, preferring the
+// platform's types when sizes are shared. Empty means no type accepts it.
+func deviceTypeFor(w, h int, platform string) string {
+	match := ""
+	for _, t := range displayTypes {
+		if !t.Accepts(w, h) {
+			continue
+		}
+		if t.Platform == platform {
+			return t.ID
+		}
+		if match == "" {
+			match = t.ID
+		}
+	}
+	return match
+}
@@ -0,0 +1,92 @@
+package screenshots
+
+import (
+	"image"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func writeScreenshot(t *testing.T, dir, name string, width, height int) {
+	t.Helper()
+	if err := encodePNG(filepath.Join(dir, name), image.NewRGBA(image.Rect(0, 0, width, height))); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDisplayTypesHaveASCTypes(t *testing.T) {
+	seen := map[string]bool{}
+	for _, dt := range DisplayTypes() {
+		if dt.ASC == "" || dt.Label == "" || dt.Platform == "" || len(dt.Sizes) == 0 {
+			t.Errorf("incomplete display type %+v", dt)
+		}
+		if seen[dt.ID] {
+			t.Errorf("duplicate display type %s", dt.ID)
+		}
+		seen[dt.ID] = true
+	}
+	for _, platform := range []string{"ios", "macos", "tvos", "visionos", "watchos"} {
+		for _, req := range RequirementsFor(platform, "universal").Required {
+			if _, ok := LookupDisplayType(req); !ok {
+				t.Errorf("%s requires unknown display type %s", platform, req)
+			}
+		}
+	}
+}
+
+func TestValidateScreenshotsPerPlatform(t *testing.T) {
+	tests := []struct {
+		name          string
+		platform      string
+		family        string
+		sizes         [][2]int
+		wantFulfilled []string
+		wantMissing   []string
+	}{
+		{"iphone fallback to 6.5", "ios", "iphone", [][2]int{{2688, 1242}}, []string{"IPHONE_69"}, nil},
+		{"universal without ipad", "ios", "universal", [][2]int{{1320, 2868}, {1668, 2388}}, []string{"IPHONE_69"}, []string{"IPAD_PRO_13"}},
+		{"mac 16:10", "macos", "", [][2]int{{1440, 900}}, []string{"DESKTOP"}, nil},
+		{"mac portrait", "macos", "", [][2]int{{900, 1440}}, nil, []string{"DESKTOP"}},
+		{"apple tv 1080p", "tvos", "", [][2]int{{1920, 1080}}, []string{"APPLE_TV"}, nil},
+		{"vision pro shares 4K", "visionos", "", [][2]int{{3840, 2160}}, []string{"APPLE_VISION_PRO"}, nil},
+		{"any watch size", "watchos", "", [][2]int{{396, 484}}, []string{"WATCH_ULTRA"}, nil},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for i, size := range tc.sizes {
+				writeScreenshot(t, dir, string(rune('a'+i))+".png", size[0], size[1])
+			}
+			fulfilled, missing, _ := ValidateScreenshots(dir, RequirementsFor(tc.platform, tc.family))
+			if !reflect.DeepEqual(fulfilled, tc.wantFulfilled) || !reflect.DeepEqual(missing, tc.wantMissing) {
+				t.Errorf("ValidateScreenshots() = %v, %v; want %v, %v", fulfilled, missing, tc.wantFulfilled, tc.wantMissing)
+			}
+		})
+	}
+
+	if got := deviceTypeFor(3840, 2160, "visionos"); got != "APPLE_VISION_PRO" {
+		t.Errorf("deviceTypeFor(4K, visionos) = %s, want APPLE_VISION_PRO", got)
+	}
+	if got := deviceTypeFor(3840, 2160, ""); got != "APPLE_TV" {
+		t.Errorf("deviceTypeFor(4K) = %s, want APPLE_TV", got)
+	}
+}
+
+func TestValidateScreenshotsLocaleGaps(t *testing.T) {
+	dir := t.TempDir()
+	writeScreenshot(t, dir, "01_home.png", 1320, 2868)
+	writeScreenshot(t, dir, "02_home.png", 2064, 2752)
+	writeScreenshot(t, filepath.Join(dir, "de-DE"), "01_home.png", 1320, 2868)
+	writeScreenshot(t, filepath.Join(dir, "fr"), "01_home.png", 1320, 2868)
+	writeScreenshot(t, filepath.Join(dir, "fr"), "02_home.png", 2048, 2732)
+	writeScreenshot(t, filepath.Join(dir, "drafts"), "01_home.png", 100, 100)
+
+	_, missing, localeGaps := ValidateScreenshots(dir, RequirementsFor("ios", "universal"))
+	if len(missing) > 0 {
+		t.Errorf("missing = %v, want none for the default locale", missing)
+	}
+	want := map[string][]string{"de-DE": {"IPAD_PRO_13"}}
+	if !reflect.DeepEqual(localeGaps, want) {
+		t.Errorf("localeGaps = %v, want %v", localeGaps, want)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"log"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
 )
 
@@ -14,35 +15,23 @@ import (
 type UploadedScreenshot struct {
 	Filename   string
 	DeviceType string // e.g. "IPHONE_69", auto-detected from dimensions
+	Width      int
+	Height     int
 }
 
 // ScreenshotRequirements describes what device types are needed for submission.
 type ScreenshotRequirements struct {
-	DeviceFamily string   // "iphone", "ipad", "universal"
+	Platform     string   // "ios", "macos", "tvos", "visionos", "watchos"
+	DeviceFamily string   // iOS only: "iphone", "ipad", "universal"
 	Required     []string // e.g. ["IPHONE_69"] or ["IPHONE_69", "IPAD_PRO_13"]
 }
 
-// dimensionToDevice maps width×height to ASC device type.
-// Both portrait and landscape orientations are included.
-var dimensionToDevice = map[[2]int]string{
-	// iPhone 6.9" (mandatory primary)
-	{1320, 2868}: "IPHONE_69", {2868, 1320}: "IPHONE_69",
-	{1290, 2796}: "IPHONE_69", {2796, 1290}: "IPHONE_69",
-	{1260, 2736}: "IPHONE_69", {2736, 1260}: "IPHONE_69",
-	// iPhone 6.5" (fallback if 6.9" missing)
-	{1284, 2778}: "IPHONE_65", {2778, 1284}: "IPHONE_65",
-	{1242, 2688}: "IPHONE_65", {2688, 1242}: "IPHONE_65",
-	// iPhone 6.3"
-	{1179, 2556}: "IPHONE_63", {2556, 1179}: "IPHONE_63",
-	// iPad 13" (mandatory if iPad app)
-	{2064, 2752}: "IPAD_PRO_13", {2752, 2064}: "IPAD_PRO_13",
-	{2048, 2732}: "IPAD_PRO_13", {2732, 2048}: "IPAD_PRO_13",
-}
-
 // FindScreenshotDir checks standard screenshot directories in priority order
-// and returns the first one containing PNG/JPEG files.
+// and returns the first one containing PNG/JPEG files. Screenshots framed from
+// the layout come first since they are made from the raw captures.
 func FindScreenshotDir(projectDir string) string {
 	candidates := []string{
+		filepath.Join(projectDir, filepath.FromSlash(FramedDir)),
 		filepath.Join(projectDir, "screenshots", "auto"),
 		filepath.Join(projectDir, "screenshots", "upload"),
 		filepath.Join(projectDir, "screenshots", "framed"),
@@ -58,6 +47,12 @@ func FindScreenshotDir(projectDir string) string {
 
 // ListScreenshots reads a directory and returns screenshot info with detected device types.
 func ListScreenshots(dir string) []UploadedScreenshot {
+	return listScreenshots(dir, "")
+}
+
+// listScreenshots lists a directory's screenshots, resolving sizes shared by
+// several display types to the platform's.
+func listScreenshots(dir, platform string) []UploadedScreenshot {
 	entries, err := os.ReadDir(dir)
 	if err != nil {
 		return nil
@@ -70,34 +65,42 @@ func ListScreenshots(dir string) []UploadedScreenshot {
 		if !isImageFile(e.Name()) {
 			continue
 		}
-		deviceType := detectDeviceType(filepath.Join(dir, e.Name()))
+		w, h, ok := imageSize(filepath.Join(dir, e.Name()))
+		if !ok {
+			continue
+		}
 		result = append(result, UploadedScreenshot{
 			Filename:   e.Name(),
-			DeviceType: deviceType,
+			DeviceType: deviceTypeFor(w, h, platform),
+			Width:      w,
+			Height:     h,
 		})
 	}
 	return result
 }
 
 func detectDeviceType(path string) string {
+	w, h, ok := imageSize(path)
+	if !ok {
+		return ""
+	}
+	return deviceTypeFor(w, h, "")
+}
+
+func imageSize(path string) (int, int, bool) {
 	f, err := os.Open(path)
 	if err != nil {
 		log.Printf("[screenshots] cannot open %s: %v", path, err)
-		return ""
+		return 0, 0, false
 	}
 	defer f.Close()
 
 	cfg, _, err := image.DecodeConfig(f)
 	if err != nil {
 		log.Printf("[screenshots] cannot decode %s: %v", path, err)
-		return ""
-	}
-
-	key := [2]int{cfg.Width, cfg.Height}
-	if dt, ok := dimensionToDevice[key]; ok {
-		return dt
+		return 0, 0, false
 	}
-	return ""
+	return cfg.Width, cfg.Height, true
 }
 
 func hasImages(dir string) bool {
@@ -118,46 +121,92 @@ func isImageFile(name string) bool {
 	return strings.HasSuffix(lower, ".png") || strings.HasSuffix(lower, ".jpg") || strings.HasSuffix(lower, ".jpeg")
 }
 
-// RequirementsForFamily returns the mandatory screenshot device types for a given device family.
-func RequirementsForFamily(deviceFamily string) ScreenshotRequirements {
-	switch deviceFamily {
-	case "ipad":
-		return ScreenshotRequirements{DeviceFamily: "ipad", Required: []string{"IPAD_PRO_13"}}
-	case "universal":
-		return ScreenshotRequirements{DeviceFamily: "universal", Required: []string{"IPHONE_69", "IPAD_PRO_13"}}
-	default: // "iphone"
-		return ScreenshotRequirements{DeviceFamily: "iphone", Required: []string{"IPHONE_69"}}
+// RequirementsFor returns the mandatory screenshot device types of a platform.
+// iOS apps need them per device family; the other platforms need one type.
+func RequirementsFor(platform, deviceFamily string) ScreenshotRequirements {
+	reqs := ScreenshotRequirements{Platform: platform}
+	switch platform {
+	case "macos":
+		reqs.Required = []string{"DESKTOP"}
+	case "tvos":
+		reqs.Required = []string{"APPLE_TV"}
+	case "visionos":
+		reqs.Required = []string{"APPLE_VISION_PRO"}
+	case "watchos":
+		reqs.Required = []string{"WATCH_ULTRA"}
+	default:
+		reqs.Platform = "ios"
+		switch deviceFamily {
+		case "ipad":
+			reqs.DeviceFamily = "ipad"
+			reqs.Required = []string{"IPAD_PRO_13"}
+		case "universal":
+			reqs.DeviceFamily = "universal"
+			reqs.Required = []string{"IPHONE_69", "IPAD_PRO_13"}
+		default:
+			reqs.DeviceFamily = "iphone"
+			reqs.Required = []string{"IPHONE_69"}
+		}
 	}
+	return reqs
+}
+
+// Accepted returns the display types that satisfy a required one: the type
+// itself and the alternatives ASC accepts in its place, such as the 6.5"
+// iPhone for the 6.9" one or any Apple Watch size.
+func Accepted(required string) []string {
+	return append([]string{required}, alternatives[required]...)
 }
 
-// ValidateScreenshots checks uploaded screenshots against requirements.
+// ValidateScreenshots checks the screenshots in dir against requirements.
 // Returns which required types are fulfilled and which are missing.
-// For iPhone: accepts either IPHONE_69 or IPHONE_65 to satisfy the iPhone requirement.
-func ValidateScreenshots(dir string, reqs ScreenshotRequirements) (fulfilled []string, missing []string) {
-	screenshots := ListScreenshots(dir)
-	found := map[string]bool{}
-	for _, s := range screenshots {
-		if s.DeviceType != "" {
-			found[s.DeviceType] = true
+// Locale subdirectories (e.g. framed/de-DE) are checked too; localeGaps maps
+// each locale to the required types it lacks.
+func ValidateScreenshots(dir string, reqs ScreenshotRequirements) (fulfilled []string, missing []string, localeGaps map[string][]string) {
+	fulfilled, missing = validateDir(dir, reqs)
+	entries, _ := os.ReadDir(dir)
+	for _, e := range entries {
+		if !e.IsDir() || !localePattern.MatchString(e.Name()) {
+			continue
+		}
+		if _, gaps := validateDir(filepath.Join(dir, e.Name()), reqs); len(gaps) > 0 {
+			if localeGaps == nil {
+				localeGaps = make(map[string][]string)
+			}
+			localeGaps[e.Name()] = gaps
 		}
 	}
+	return fulfilled, missing, localeGaps
+}
+
+// localePattern matches App Store locale directory names like "fr" or "zh-Hans".
+var localePattern = regexp.MustCompile(`^[a-z]{2,3}(-[A-Za-z]{2,4})*$`)
 
+func validateDir(dir string, reqs ScreenshotRequirements) (fulfilled []string, missing []string) {
+	screenshots := listScreenshots(dir, reqs.Platform)
 	for _, req := range reqs.Required {
-		switch req {
-		case "IPHONE_69":
-			// Accept IPHONE_65 as fallback for iPhone requirement
-			if found["IPHONE_69"] || found["IPHONE_65"] {
-				fulfilled = append(fulfilled, req)
-			} else {
-				missing = append(missing, req)
-			}
-		default:
-			if found[req] {
-				fulfilled = append(fulfilled, req)
-			} else {
-				missing = append(missing, req)
-			}
+		if hasAccepted(screenshots, req) {
+			fulfilled = append(fulfilled, req)
+		} else {
+			missing = append(missing, req)
 		}
 	}
 	return fulfilled, missing
 }
+
+// hasAccepted reports whether any screenshot has a size a required type
+// accepts, directly or through an alternative.
+func hasAccepted(screenshots []UploadedScreenshot, required string) bool {
+	for _, id := range Accepted(required) {
+		t, ok := LookupDisplayType(id)
+		if !ok {
+			continue
+		}
+		for _, s := range screenshots {
+			if t.Accepts(s.Width, s.Height) {
+				return true
+			}
+		}
+	}
+	return false
+}
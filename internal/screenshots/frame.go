@@ -0,0 +1,322 @@
+package screenshots
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"golang.org/x/image/draw"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+	"golang.org/x/image/vector"
+)
+
+// Proportions of a framed screenshot, as fractions of its width or height.
+const (
+	frameMargin      = 0.05  // of the height, around the caption and the device
+	frameDeviceWidth = 0.8   // of the width, the widest the device may be
+	frameBezel       = 0.028 // of the device width
+	captionSize      = 0.034 // default font size, of the height
+	captionWidth     = 0.86  // of the width, where captions wrap
+	captionMaxLines  = 3
+)
+
+// Frame composes every screen of the layout into FramedDir, once per locale,
+// at the exact App Store Connect size of the screen's device type. FramedDir is
+// replaced, so it only ever holds the layout's output. It returns the written
+// files.
+func Frame(projectDir string, layout *Layout) ([]string, error) {
+	if len(layout.Screens) == 0 {
+		return nil, fmt.Errorf("%s has no screens", LayoutFile)
+	}
+	typeface, err := opentype.Parse(gobold.TTF)
+	if err != nil {
+		return nil, err
+	}
+	framedDir := filepath.Join(projectDir, FramedDir)
+	if err := os.RemoveAll(framedDir); err != nil {
+		return nil, err
+	}
+
+	var written []string
+	for i, screen := range layout.Screens {
+		src, err := decodeImage(filepath.Join(projectDir, screen.Source))
+		if err != nil {
+			return written, err
+		}
+		width, height, err := frameSize(screen, src.Bounds())
+		if err != nil {
+			return written, err
+		}
+		name := fmt.Sprintf("%02d_%s.png", i+1, strings.TrimSuffix(filepath.Base(screen.Source), filepath.Ext(screen.Source)))
+
+		for _, locale := range layout.Locales() {
+			dir := framedDir
+			if locale != layout.DefaultLocale {
+				dir = filepath.Join(framedDir, locale)
+			}
+			img, err := layout.render(typeface, src, width, height, layout.caption(screen, locale))
+			if err != nil {
+				return written, err
+			}
+			out := filepath.Join(dir, name)
+			if err := encodePNG(out, img); err != nil {
+				return written, err
+			}
+			log.Printf("[screenshots] framed %s (%s, %dx%d) -> %s", screen.Source, locale, width, height, out)
+			written = append(written, out)
+		}
+	}
+	return written, nil
+}
+
+// frameSize returns the ASC size a screen is framed at: the device type's size
+// in the capture's orientation.
+func frameSize(screen Screen, bounds image.Rectangle) (int, int, error) {
+	w, h := bounds.Dx(), bounds.Dy()
+	deviceType := screen.DeviceType
+	if deviceType == "" {
+		deviceType = deviceTypeFor(w, h, "")
+	}
+	if t, ok := LookupDisplayType(deviceType); screen.DeviceType == "" && (!ok || t.Platform == "ios") {
+		// iPhone and iPad captures, whatever the simulator's size, are framed
+		// for the mandatory device of their family.
+		deviceType = "IPAD_PRO_13"
+		if isPhoneAspect(w, h) {
+			deviceType = "IPHONE_69"
+		}
+	}
+	t, ok := LookupDisplayType(deviceType)
+	if !ok {
+		return 0, 0, fmt.Errorf("%s: unsupported device type %q", screen.Source, deviceType)
+	}
+	size := t.Sizes[0]
+	if t.Rotates && (w > h) != (size[0] > size[1]) {
+		return size[1], size[0], nil
+	}
+	return size[0], size[1], nil
+}
+
+// isPhoneAspect reports whether a w×h capture has an iPhone's tall aspect ratio.
+func isPhoneAspect(w, h int) bool {
+	short, long := min(w, h), max(w, h)
+	return float64(short)/float64(long) < 0.6
+}
+
+// render draws one framed screenshot: the background gradient, the caption
+// and the capture, optionally inside a bezel.
+func (l *Layout) render(typeface *opentype.Font, src image.Image, width, height int, caption string) (*image.RGBA, error) {
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	from := parseHex(l.Background.From, color.RGBA{R: 10, G: 132, B: 255, A: 255})
+	to := parseHex(l.Background.To, from)
+	draw.Draw(dst, dst.Bounds(), verticalGradient{from: from, to: to, height: height}, image.Point{}, draw.Src)
+
+	margin := int(frameMargin * float64(height))
+	var lines []string
+	var face font.Face
+	captionHeight := 0
+	if strings.TrimSpace(caption) != "" {
+		var err error
+		face, lines, err = fitCaption(typeface, caption, l.Caption.Size, width, height)
+		if err != nil {
+			return nil, err
+		}
+		defer face.Close()
+		captionHeight = len(lines)*face.Metrics().Height.Ceil() + margin
+	}
+
+	// The device fills what the caption leaves, below or above it.
+	top := margin
+	if l.Caption.Position != "bottom" {
+		top += captionHeight
+	}
+	avail := image.Rect(0, top, width, height-margin)
+	if l.Caption.Position == "bottom" {
+		avail.Max.Y -= captionHeight
+	}
+	l.drawDevice(dst, src, avail)
+
+	if len(lines) > 0 {
+		captionTop := margin
+		if l.Caption.Position == "bottom" {
+			captionTop = height - captionHeight
+		}
+		ink := image.NewUniform(parseHex(l.Caption.Color, color.RGBA{R: 255, G: 255, B: 255, A: 255}))
+		d := &font.Drawer{Dst: dst, Src: ink, Face: face}
+		metrics := face.Metrics()
+		for i, line := range lines {
+			advance := d.MeasureString(line)
+			d.Dot = fixed.Point26_6{
+				X: fixed.I(width)/2 - advance/2,
+				Y: fixed.I(captionTop) + metrics.Ascent + fixed.Int26_6(i)*metrics.Height,
+			}
+			d.DrawString(line)
+		}
+	}
+	return dst, nil
+}
+
+// drawDevice scales the capture into avail, keeping its aspect ratio, with
+// rounded screen corners and an optional bezel.
+func (l *Layout) drawDevice(dst *image.RGBA, src image.Image, avail image.Rectangle) {
+	b := src.Bounds()
+	bezelScale := 1.0
+	if l.Bezel {
+		bezelScale = 1 + 2*frameBezel
+	}
+	maxW := float64(avail.Dx()) * frameDeviceWidth / bezelScale
+	maxH := float64(avail.Dy()) / bezelScale
+	s := min(maxW/float64(b.Dx()), maxH/float64(b.Dy()))
+	w, h := int(float64(b.Dx())*s), int(float64(b.Dy())*s)
+	x := avail.Min.X + (avail.Dx()-w)/2
+	y := avail.Min.Y + (avail.Dy()-h)/2
+	screen := image.Rect(x, y, x+w, y+h)
+
+	radius := 0.035 * float64(min(w, h))
+	if isPhoneAspect(b.Dx(), b.Dy()) {
+		radius = 0.11 * float64(min(w, h))
+	}
+	if l.Bezel {
+		t := frameBezel * float64(min(w, h))
+		outer := image.Rect(x-int(t), y-int(t), x+w+int(t), y+h+int(t))
+		fillRoundedRect(dst, outer, radius+t, image.NewUniform(color.RGBA{R: 17, G: 17, B: 19, A: 255}))
+	}
+
+	scaled := image.NewRGBA(screen)
+	draw.CatmullRom.Scale(scaled, screen, src, b, draw.Src, nil)
+	fillRoundedRect(dst, screen, radius, scaled)
+}
+
+// fitCaption wraps the caption to the caption width, shrinking the font until
+// it fits in captionMaxLines.
+func fitCaption(typeface *opentype.Font, caption string, size float64, width, height int) (font.Face, []string, error) {
+	if size <= 0 {
+		size = captionSize
+	}
+	px := size * float64(height)
+	for {
+		face, err := opentype.NewFace(typeface, &opentype.FaceOptions{Size: px, DPI: 72, Hinting: font.HintingNone})
+		if err != nil {
+			return nil, nil, err
+		}
+		lines := wrapText(face, caption, fixed.I(int(captionWidth*float64(width))))
+		if len(lines) <= captionMaxLines || px < 12 {
+			return face, lines, nil
+		}
+		face.Close()
+		px *= 0.9
+	}
+}
+
+// wrapText breaks text into lines no wider than maxWidth, keeping explicit
+// line breaks.
+func wrapText(face font.Face, text string, maxWidth fixed.Int26_6) []string {
+	var lines []string
+	for _, paragraph := range strings.Split(text, "\n") {
+		line := ""
+		for _, word := range strings.Fields(paragraph) {
+			candidate := word
+			if line != "" {
+				candidate = line + " " + word
+			}
+			if line != "" && font.MeasureString(face, candidate) > maxWidth {
+				lines = append(lines, line)
+				candidate = word
+			}
+			line = candidate
+		}
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// fillRoundedRect draws src into r of dst, clipped to a rounded rectangle.
+func fillRoundedRect(dst *image.RGBA, r image.Rectangle, radius float64, src image.Image) {
+	z := vector.NewRasterizer(dst.Bounds().Dx(), dst.Bounds().Dy())
+	x0, y0, x1, y1 := float32(r.Min.X), float32(r.Min.Y), float32(r.Max.X), float32(r.Max.Y)
+	rad := float32(min(radius, float64(min(r.Dx(), r.Dy()))/2))
+	k := rad * 0.4477 // 1 - the circle-approximating cubic's control distance
+	z.MoveTo(x0+rad, y0)
+	z.LineTo(x1-rad, y0)
+	z.CubeTo(x1-k, y0, x1, y0+k, x1, y0+rad)
+	z.LineTo(x1, y1-rad)
+	z.CubeTo(x1, y1-k, x1-k, y1, x1-rad, y1)
+	z.LineTo(x0+rad, y1)
+	z.CubeTo(x0+k, y1, x0, y1-k, x0, y1-rad)
+	z.LineTo(x0, y0+rad)
+	z.CubeTo(x0, y0+k, x0+k, y0, x0+rad, y0)
+	z.ClosePath()
+	z.Draw(dst, dst.Bounds(), src, dst.Bounds().Min)
+}
+
+// verticalGradient is a top-to-bottom linear gradient.
+type verticalGradient struct {
+	from, to color.RGBA
+	height   int
+}
+
+func (g verticalGradient) ColorModel() color.Model { return color.RGBAModel }
+func (g verticalGradient) Bounds() image.Rectangle { return image.Rect(-1e9, -1e9, 1e9, 1e9) }
+
+func (g verticalGradient) At(_, y int) color.Color {
+	t := min(max(float64(y)/float64(max(g.height-1, 1)), 0), 1)
+	mix := func(a, b uint8) uint8 { return uint8(float64(a) + (float64(b)-float64(a))*t + 0.5) }
+	return color.RGBA{R: mix(g.from.R, g.to.R), G: mix(g.from.G, g.to.G), B: mix(g.from.B, g.to.B), A: 255}
+}
+
+// parseHex parses a #RRGGBB or #RGB color, returning fallback when invalid.
+func parseHex(hex string, fallback color.RGBA) color.RGBA {
+	hex = strings.TrimPrefix(hex, "#")
+	if len(hex) == 3 {
+		hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+	}
+	if len(hex) != 6 {
+		return fallback
+	}
+	v, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return fallback
+	}
+	return color.RGBA{R: uint8(v >> 16), G: uint8(v >> 8), B: uint8(v), A: 255}
+}
+
+// decodeImage reads a PNG or JPEG capture.
+func decodeImage(path string) (image.Image, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	img, _, err := image.Decode(f)
+	if err != nil {
+		return nil, fmt.Errorf("decode %s: %w", filepath.Base(path), err)
+	}
+	return img, nil
+}
+
+// encodePNG writes an opaque image as a PNG without an alpha channel, which
+// App Store Connect requires of screenshots.
+func encodePNG(path string, img *image.RGBA) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := png.Encode(f, img); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,110 @@
+package screenshots
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"golang.org/x/image/draw"
+)
+
+func writeCapture(t *testing.T, projectDir, name string, width, height int) string {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(img, img.Bounds(), image.White, image.Point{}, draw.Src)
+	rel := filepath.ToSlash(filepath.Join("screenshots", "auto", name))
+	if err := encodePNG(filepath.Join(projectDir, rel), img); err != nil {
+		t.Fatal(err)
+	}
+	return rel
+}
+
+func TestFrameWritesASCSizesPerLocale(t *testing.T) {
+	projectDir := t.TempDir()
+	phone := writeCapture(t, projectDir, "iphone_launch.png", 1206, 2622) // 6.3" simulator
+	ipad := writeCapture(t, projectDir, "ipad_launch.png", 2752, 2064)
+
+	layout := DefaultLayout([]string{phone, ipad}, "#3B82F6", "#8B5CF6")
+	layout.Screens[0].Caption["en-US"] = "Track every habit, build streaks that last"
+	layout.Screens[0].Caption["de-DE"] = "Jede Gewohnheit im Blick"
+	if err := SaveLayout(projectDir, layout); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadLayout(projectDir)
+	if err != nil || loaded == nil {
+		t.Fatalf("LoadLayout() = %v, %v", loaded, err)
+	}
+	if loaded.AddScreens([]string{phone}) {
+		t.Error("AddScreens() added a screen the layout already frames")
+	}
+
+	handMade := filepath.Join(projectDir, "screenshots", "framed", "01_hand_made.png")
+	if err := os.MkdirAll(filepath.Dir(handMade), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(handMade, []byte("png"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	written, err := Frame(projectDir, loaded)
+	if err != nil {
+		t.Fatalf("Frame() error: %v", err)
+	}
+	if len(written) != 4 {
+		t.Fatalf("Frame() wrote %d files, want 2 screens × 2 locales", len(written))
+	}
+
+	want := map[string][2]int{
+		FramedDir + "/01_iphone_launch.png":       {1320, 2868},
+		FramedDir + "/de-DE/01_iphone_launch.png": {1320, 2868},
+		FramedDir + "/02_ipad_launch.png":         {2752, 2064},
+		FramedDir + "/de-DE/02_ipad_launch.png":   {2752, 2064},
+	}
+	for rel, size := range want {
+		data, err := os.ReadFile(filepath.Join(projectDir, rel))
+		if err != nil {
+			t.Errorf("missing %s: %v", rel, err)
+			continue
+		}
+		cfg, err := png.DecodeConfig(bytes.NewReader(data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if cfg.Width != size[0] || cfg.Height != size[1] {
+			t.Errorf("%s is %dx%d, want %dx%d", rel, cfg.Width, cfg.Height, size[0], size[1])
+		}
+		// The IHDR color type: 2 is RGB without alpha.
+		if colorType := data[25]; colorType != 2 {
+			t.Errorf("%s has PNG color type %d, want RGB without alpha", rel, colorType)
+		}
+	}
+
+	if _, err := os.Stat(handMade); err != nil {
+		t.Errorf("Frame() removed a hand-made screenshot: %v", err)
+	}
+	if dir := FindScreenshotDir(projectDir); dir != filepath.Join(projectDir, FramedDir) {
+		t.Errorf("FindScreenshotDir() = %s, want the framed screenshots", dir)
+	}
+	fulfilled, missing, localeGaps := ValidateScreenshots(filepath.Join(projectDir, FramedDir), RequirementsFor("ios", "universal"))
+	if len(missing) > 0 || len(localeGaps) > 0 {
+		t.Errorf("framed screenshots miss %v, per locale %v (fulfilled %v)", missing, localeGaps, fulfilled)
+	}
+}
+
+func TestParseHex(t *testing.T) {
+	fallback := color.RGBA{R: 1, G: 2, B: 3, A: 255}
+	for hex, want := range map[string]color.RGBA{
+		"#3B82F6": {R: 0x3B, G: 0x82, B: 0xF6, A: 255},
+		"0af":     {R: 0x00, G: 0xAA, B: 0xFF, A: 255},
+		"#12345":  fallback,
+		"":        fallback,
+	} {
+		if got := parseHex(hex, fallback); got != want {
+			t.Errorf("parseHex(%q) = %v, want %v", hex, got, want)
+		}
+	}
+}
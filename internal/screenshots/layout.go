@@ -0,0 +1,148 @@
+package screenshots
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// LayoutFile is the project's declarative screenshot layout, relative to the
+// project directory.
+const LayoutFile = "screenshots/layout.json"
+
+// FramedDir is where Frame writes the default locale's screenshots, relative to
+// the project directory. Other locales go to subdirectories named after them.
+// nanowave owns the directory and replaces it on every run; screenshots framed
+// by hand belong in screenshots/framed, which Frame never touches.
+const FramedDir = "screenshots/nanowave"
+
+// Layout describes how raw simulator captures become App Store screenshots.
+type Layout struct {
+	// Background is a top-to-bottom gradient of hex colors behind every screen.
+	Background Gradient     `json:"background"`
+	Caption    CaptionStyle `json:"caption"`
+	// Bezel draws each capture inside a device frame.
+	Bezel bool `json:"bezel"`
+	// DefaultLocale is the locale written to FramedDir and the caption
+	// used when a screen has none for another locale.
+	DefaultLocale string   `json:"default_locale"`
+	Screens       []Screen `json:"screens"`
+}
+
+// Gradient is a two-stop linear gradient of hex colors.
+type Gradient struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// CaptionStyle is how marketing captions are drawn.
+type CaptionStyle struct {
+	Color string `json:"color,omitempty"` // hex, white by default
+	// Position is "top" (default) or "bottom".
+	Position string `json:"position,omitempty"`
+	// Size is the font size as a fraction of the screenshot height.
+	Size float64 `json:"size,omitempty"`
+}
+
+// Screen is one framed screenshot.
+type Screen struct {
+	// Source is the raw capture, relative to the project directory.
+	Source string `json:"source"`
+	// DeviceType overrides the ASC device type detected from the capture size.
+	DeviceType string `json:"device_type,omitempty"`
+	// Caption maps a locale such as "en-US" to the screen's marketing text.
+	Caption map[string]string `json:"caption,omitempty"`
+}
+
+// DefaultLayout returns a layout framing the given captures on a from→to
+// gradient, with empty captions to fill in.
+func DefaultLayout(sources []string, from, to string) *Layout {
+	layout := &Layout{
+		Background:    Gradient{From: from, To: to},
+		Caption:       CaptionStyle{Color: "#FFFFFF", Position: "top"},
+		Bezel:         true,
+		DefaultLocale: "en-US",
+	}
+	layout.AddScreens(sources)
+	return layout
+}
+
+// AddScreens appends a screen for every source the layout doesn't frame yet
+// and reports whether any was added.
+func (l *Layout) AddScreens(sources []string) bool {
+	known := make(map[string]bool, len(l.Screens))
+	for _, s := range l.Screens {
+		known[filepath.ToSlash(s.Source)] = true
+	}
+	added := false
+	for _, src := range sources {
+		src = filepath.ToSlash(src)
+		if known[src] {
+			continue
+		}
+		known[src] = true
+		l.Screens = append(l.Screens, Screen{Source: src, Caption: map[string]string{l.DefaultLocale: ""}})
+		added = true
+	}
+	return added
+}
+
+// Locales returns the default locale followed by every other captioned locale.
+func (l *Layout) Locales() []string {
+	seen := map[string]bool{l.DefaultLocale: true}
+	var others []string
+	for _, s := range l.Screens {
+		for locale := range s.Caption {
+			if !seen[locale] {
+				seen[locale] = true
+				others = append(others, locale)
+			}
+		}
+	}
+	sort.Strings(others)
+	return append([]string{l.DefaultLocale}, others...)
+}
+
+// caption returns the screen's caption for locale, falling back to the
+// default locale's.
+func (l *Layout) caption(s Screen, locale string) string {
+	if text, ok := s.Caption[locale]; ok && text != "" {
+		return text
+	}
+	return s.Caption[l.DefaultLocale]
+}
+
+// LoadLayout reads the project's screenshots/layout.json. It returns nil and
+// no error when the project has none.
+func LoadLayout(projectDir string) (*Layout, error) {
+	data, err := os.ReadFile(filepath.Join(projectDir, LayoutFile))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var layout Layout
+	if err := json.Unmarshal(data, &layout); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", LayoutFile, err)
+	}
+	if layout.DefaultLocale == "" {
+		layout.DefaultLocale = "en-US"
+	}
+	return &layout, nil
+}
+
+// SaveLayout writes the layout to the project's screenshots/layout.json.
+func SaveLayout(projectDir string, layout *Layout) error {
+	data, err := json.MarshalIndent(layout, "", "  ")
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(projectDir, LayoutFile)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
@@ -0,0 +1,204 @@
+package screenshots
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// CapturePlanFile is the project's list of screens to capture, relative to the
+// project directory.
+const CapturePlanFile = "screenshots/capture_plan.json"
+
+// Capture drivers: how the app is moved from screen to screen.
+const (
+	// DriverDeepLink opens each screen's deep link with simctl openurl.
+	DriverDeepLink = "deep_link"
+	// DriverUITest runs a generated XCUITest that taps to each screen.
+	DriverUITest = "ui_test"
+)
+
+// SampleDataArgument is the launch argument that asks the app to seed sample
+// content. As "-NanowaveSampleData YES" it reads true from UserDefaults.
+const SampleDataArgument = "NanowaveSampleData"
+
+// uiTestClass is the generated XCUITest case of the ui_test driver.
+const uiTestClass = "NanowaveScreenshotTests"
+
+// CapturePlan lists the screens CaptureFromSimulator visits on each simulator.
+type CapturePlan struct {
+	// URLScheme is the custom scheme the app registers for deep links.
+	URLScheme string `json:"url_scheme"`
+	// Driver is DriverDeepLink (default) or DriverUITest.
+	Driver string `json:"driver,omitempty"`
+	// LaunchArguments are passed to the app on every launch.
+	LaunchArguments []string        `json:"launch_arguments,omitempty"`
+	StatusBar       StatusBar       `json:"status_bar"`
+	Screens         []CaptureScreen `json:"screens"`
+}
+
+// StatusBar is the simulator status bar shown in captures, applied with
+// simctl status_bar.
+type StatusBar struct {
+	Time         string `json:"time,omitempty"`
+	DataNetwork  string `json:"data_network,omitempty"`
+	WifiBars     int    `json:"wifi_bars,omitempty"`
+	CellularBars int    `json:"cellular_bars,omitempty"`
+	BatteryState string `json:"battery_state,omitempty"`
+	BatteryLevel int    `json:"battery_level,omitempty"`
+}
+
+// CaptureScreen is one screen to capture.
+type CaptureScreen struct {
+	// Name identifies the screen in file names, e.g. "home".
+	Name string `json:"name"`
+	// Feature is the app feature the screen shows.
+	Feature string `json:"feature,omitempty"`
+	// DeepLink opens the screen; empty captures the screen the app launches to.
+	DeepLink string `json:"deep_link,omitempty"`
+	// Tap lists accessibility identifiers the ui_test driver taps, in order,
+	// after opening the deep link.
+	Tap []string `json:"tap,omitempty"`
+	// WaitSeconds is how long the screen settles before the capture.
+	WaitSeconds int `json:"wait_seconds,omitempty"`
+}
+
+// DefaultStatusBar is the clean status bar of Apple's marketing screenshots.
+func DefaultStatusBar() StatusBar {
+	return StatusBar{
+		Time:         "9:41",
+		DataNetwork:  "wifi",
+		WifiBars:     3,
+		CellularBars: 4,
+		BatteryState: "charged",
+		BatteryLevel: 100,
+	}
+}
+
+// args returns the simctl status_bar override arguments.
+func (s StatusBar) args() []string {
+	var args []string
+	if s.Time != "" {
+		args = append(args, "--time", s.Time)
+	}
+	if s.DataNetwork != "" {
+		args = append(args, "--dataNetwork", s.DataNetwork)
+	}
+	if s.WifiBars > 0 {
+		args = append(args, "--wifiMode", "active", "--wifiBars", fmt.Sprint(s.WifiBars))
+	}
+	if s.CellularBars > 0 {
+		args = append(args, "--cellularMode", "active", "--cellularBars", fmt.Sprint(s.CellularBars))
+	}
+	if s.BatteryState != "" {
+		args = append(args, "--batteryState", s.BatteryState)
+	}
+	if s.BatteryLevel > 0 {
+		args = append(args, "--batteryLevel", fmt.Sprint(s.BatteryLevel))
+	}
+	return args
+}
+
+// captureName is the file a screen is captured to on a simulator, numbered so
+// the captures keep the plan's order.
+func captureName(label string, index int, screen CaptureScreen) string {
+	return fmt.Sprintf("%s_%02d_%s.png", strings.ToLower(label), index+1, screen.Name)
+}
+
+// LoadCapturePlan reads the project's screenshots/capture_plan.json. It
+// returns nil and no error when the project has none.
+func LoadCapturePlan(projectDir string) (*CapturePlan, error) {
+	data, err := os.ReadFile(filepath.Join(projectDir, CapturePlanFile))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var plan CapturePlan
+	if err := json.Unmarshal(data, &plan); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", CapturePlanFile, err)
+	}
+	if plan.Driver == "" {
+		plan.Driver = DriverDeepLink
+	}
+	if plan.Driver != DriverDeepLink && plan.Driver != DriverUITest {
+		return nil, fmt.Errorf("%s: unknown driver %q, want %s or %s", CapturePlanFile, plan.Driver, DriverDeepLink, DriverUITest)
+	}
+	for i, screen := range plan.Screens {
+		if screen.Name == "" {
+			return nil, fmt.Errorf("%s: screen %d has no name", CapturePlanFile, i+1)
+		}
+	}
+	return &plan, nil
+}
+
+// SaveCapturePlan writes the plan to the project's screenshots/capture_plan.json.
+func SaveCapturePlan(projectDir string, plan *CapturePlan) error {
+	data, err := json.MarshalIndent(plan, "", "  ")
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(projectDir, CapturePlanFile)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// UITestSource returns the XCUITest the ui_test driver runs. It captures every
+// screen of the plan into the directory in NANOWAVE_SCREENSHOT_DIR, naming
+// files with the NANOWAVE_SCREENSHOT_PREFIX device label.
+func (plan *CapturePlan) UITestSource() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "// Generated by nanowave from %s. Edits are overwritten on the next capture.\n", CapturePlanFile)
+	b.WriteString("import XCTest\n\n")
+	fmt.Fprintf(&b, "final class %s: XCTestCase {\n", uiTestClass)
+	b.WriteString("    override func setUp() {\n        continueAfterFailure = false\n    }\n\n")
+	b.WriteString("    func testCaptureScreens() throws {\n")
+	b.WriteString("        let env = ProcessInfo.processInfo.environment\n")
+	b.WriteString("        guard let dir = env[\"NANOWAVE_SCREENSHOT_DIR\"] else {\n")
+	b.WriteString("            throw XCTSkip(\"NANOWAVE_SCREENSHOT_DIR is not set\")\n        }\n")
+	b.WriteString("        let label = env[\"NANOWAVE_SCREENSHOT_PREFIX\"] ?? \"iphone\"\n")
+	b.WriteString("        let app = XCUIApplication()\n")
+	fmt.Fprintf(&b, "        app.launchArguments = [%s]\n", swiftStrings(plan.LaunchArguments))
+	for i, screen := range plan.Screens {
+		fmt.Fprintf(&b, "\n        // %s\n", screen.Name)
+		b.WriteString("        app.terminate()\n        app.launch()\n")
+		if screen.DeepLink != "" {
+			fmt.Fprintf(&b, "        app.open(URL(string: %s)!)\n", swiftString(screen.DeepLink))
+		}
+		for _, id := range screen.Tap {
+			fmt.Fprintf(&b, "        tap(%s, in: app)\n", swiftString(id))
+		}
+		fmt.Fprintf(&b, "        try capture(%s, to: dir, after: %d)\n",
+			swiftString(strings.TrimSuffix(captureName("\\(label)", i, screen), ".png")), max(screen.WaitSeconds, 2))
+	}
+	b.WriteString("    }\n\n")
+	b.WriteString("    private func tap(_ identifier: String, in app: XCUIApplication) {\n")
+	b.WriteString("        let element = app.descendants(matching: .any)[identifier].firstMatch\n")
+	b.WriteString("        XCTAssertTrue(element.waitForExistence(timeout: 10), \"\\(identifier) not found\")\n")
+	b.WriteString("        element.tap()\n    }\n\n")
+	b.WriteString("    private func capture(_ name: String, to dir: String, after seconds: UInt32) throws {\n")
+	b.WriteString("        sleep(seconds)\n")
+	b.WriteString("        let url = URL(fileURLWithPath: dir).appendingPathComponent(name + \".png\")\n")
+	b.WriteString("        try XCUIScreen.main.screenshot().pngRepresentation.write(to: url)\n    }\n")
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// swiftString quotes s as a Swift string literal, keeping \( interpolations.
+func swiftString(s string) string {
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	return `"` + s + `"`
+}
+
+func swiftStrings(values []string) string {
+	quoted := make([]string, len(values))
+	for i, v := range values {
+		quoted[i] = swiftString(v)
+	}
+	return strings.Join(quoted, ", ")
+}
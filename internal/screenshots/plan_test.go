@@ -0,0 +1,94 @@
+package screenshots
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestCapturePlanRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	if plan, err := LoadCapturePlan(dir); plan != nil || err != nil {
+		t.Fatalf("LoadCapturePlan without a plan = %v, %v; want nil, nil", plan, err)
+	}
+
+	want := &CapturePlan{
+		URLScheme:       "notes",
+		LaunchArguments: []string{"-" + SampleDataArgument, "YES"},
+		StatusBar:       DefaultStatusBar(),
+		Screens: []CaptureScreen{
+			{Name: "home"},
+			{Name: "editor", Feature: "Editor", DeepLink: "notes://editor"},
+		},
+	}
+	if err := SaveCapturePlan(dir, want); err != nil {
+		t.Fatalf("SaveCapturePlan: %v", err)
+	}
+	got, err := LoadCapturePlan(dir)
+	if err != nil {
+		t.Fatalf("LoadCapturePlan: %v", err)
+	}
+	if got.Driver != DriverDeepLink {
+		t.Errorf("Driver = %q, want the %s default", got.Driver, DriverDeepLink)
+	}
+	if len(got.Screens) != 2 || got.Screens[1].DeepLink != "notes://editor" {
+		t.Errorf("Screens = %+v", got.Screens)
+	}
+
+	path := filepath.Join(dir, CapturePlanFile)
+	for _, invalid := range []string{
+		`{"driver": "appium", "screens": []}`,
+		`{"screens": [{"deep_link": "notes://x"}]}`,
+	} {
+		if err := os.WriteFile(path, []byte(invalid), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := LoadCapturePlan(dir); err == nil {
+			t.Errorf("LoadCapturePlan(%s) should fail", invalid)
+		}
+	}
+}
+
+func TestStatusBarArgs(t *testing.T) {
+	args := DefaultStatusBar().args()
+	for _, want := range [][]string{
+		{"--time", "9:41"},
+		{"--wifiMode", "active", "--wifiBars", "3"},
+		{"--batteryState", "charged"},
+		{"--batteryLevel", "100"},
+	} {
+		i := slices.Index(args, want[0])
+		if i < 0 || !slices.Equal(args[i:min(i+len(want), len(args))], want) {
+			t.Errorf("args %v missing %v", args, want)
+		}
+	}
+	if args := (StatusBar{}).args(); len(args) != 0 {
+		t.Errorf("empty status bar args = %v, want none", args)
+	}
+}
+
+func TestUITestSource(t *testing.T) {
+	plan := &CapturePlan{
+		Driver:          DriverUITest,
+		LaunchArguments: []string{"-" + SampleDataArgument, "YES"},
+		Screens: []CaptureScreen{
+			{Name: "home"},
+			{Name: "settings", DeepLink: "notes://settings", Tap: []string{"themePicker"}, WaitSeconds: 4},
+		},
+	}
+	src := plan.UITestSource()
+	for _, want := range []string{
+		"final class NanowaveScreenshotTests: XCTestCase",
+		`app.launchArguments = ["-NanowaveSampleData", "YES"]`,
+		`app.open(URL(string: "notes://settings")!)`,
+		`tap("themePicker", in: app)`,
+		`try capture("\(label)_01_home", to: dir, after: 2)`,
+		`try capture("\(label)_02_settings", to: dir, after: 4)`,
+	} {
+		if !strings.Contains(src, want) {
+			t.Errorf("UI test source missing %q:\n%s", want, src)
+		}
+	}
+}
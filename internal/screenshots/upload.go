@@ -29,15 +29,38 @@ func RunUploadServer(ctx context.Context, screenshotDir string, reqs ScreenshotR
 		Device string `json:"device"`
 	}
 	type reqsPayload struct {
-		Required   []string     `json:"required"`
-		Accepted   []acceptedDim `json:"accepted"`
+		Required     []string            `json:"required"`
+		Accepted     []acceptedDim       `json:"accepted"`
+		Labels       map[string]string   `json:"labels"`
+		Dims         map[string]string   `json:"dims"`
+		Alternatives map[string][]string `json:"alternatives"`
 	}
 
-	var accepted []acceptedDim
-	for dim, dt := range dimensionToDevice {
-		accepted = append(accepted, acceptedDim{Width: dim[0], Height: dim[1], Device: dt})
+	reqsData := reqsPayload{
+		Required:     reqs.Required,
+		Labels:       make(map[string]string),
+		Dims:         make(map[string]string),
+		Alternatives: make(map[string][]string),
+	}
+	for _, req := range reqs.Required {
+		reqsData.Alternatives[req] = Accepted(req)
+	}
+	for _, t := range DisplayTypes() {
+		reqsData.Labels[t.ID] = t.Label
+		var dims []string
+		for _, size := range t.Sizes {
+			dims = append(dims, fmt.Sprintf("%dx%d", size[0], size[1]))
+			// Shared sizes resolve to the platform's type, as in validation.
+			if deviceTypeFor(size[0], size[1], reqs.Platform) != t.ID {
+				continue
+			}
+			reqsData.Accepted = append(reqsData.Accepted, acceptedDim{Width: size[0], Height: size[1], Device: t.ID})
+			if t.Rotates {
+				reqsData.Accepted = append(reqsData.Accepted, acceptedDim{Width: size[1], Height: size[0], Device: t.ID})
+			}
+		}
+		reqsData.Dims[t.ID] = strings.Join(dims, ", ")
 	}
-	reqsData := reqsPayload{Required: reqs.Required, Accepted: accepted}
 	reqsJSON, _ := json.Marshal(reqsData)
 
 	mux := http.NewServeMux()
@@ -99,7 +122,10 @@ func RunUploadServer(ctx context.Context, screenshotDir string, reqs ScreenshotR
 			out.Close()
 			src.Close()
 
-			dt := detectDeviceType(destPath)
+			dt := ""
+			if w, h, ok := imageSize(destPath); ok {
+				dt = deviceTypeFor(w, h, reqs.Platform)
+			}
 			if dt == "" {
 				dt = "UNKNOWN"
 			}
@@ -111,7 +137,7 @@ func RunUploadServer(ctx context.Context, screenshotDir string, reqs ScreenshotR
 		}
 
 		// Check fulfillment
-		fulfilled, missing := ValidateScreenshots(screenshotDir, reqs)
+		fulfilled, missing, _ := ValidateScreenshots(screenshotDir, reqs)
 
 		resp := map[string]any{
 			"ok":          true,
@@ -355,18 +381,13 @@ REQS.accepted.forEach(a => {
   deviceDims[a.w + 'x' + a.h] = a.device;
 });
 
-const deviceLabels = {
-  'IPHONE_69': 'iPhone 6.9"',
-  'IPHONE_65': 'iPhone 6.5"',
-  'IPHONE_63': 'iPhone 6.3"',
-  'IPAD_PRO_13': 'iPad 13"'
-};
+const deviceLabels = REQS.labels;
+const deviceDimDescriptions = REQS.dims;
 
-const deviceDimDescriptions = {
-  'IPHONE_69': '1320x2868, 1290x2796, or 1260x2736',
-  'IPHONE_65': '1284x2778 or 1242x2688',
-  'IPAD_PRO_13': '2064x2752 or 2048x2732'
-};
+// A requirement is covered by its own type or an accepted alternative.
+function isCovered(req) {
+  return (REQS.alternatives[req] || [req]).some(t => fulfilledTypes.has(t));
+}
 
 let selectedFiles = [];
 let fulfilledTypes = new Set();
@@ -376,9 +397,7 @@ function renderRequirements() {
   let html = '<h2>Required Screenshots</h2>';
   let fulfilledCount = 0;
   REQS.required.forEach(req => {
-    // For IPHONE_69, also accept IPHONE_65
-    let isFulfilled = fulfilledTypes.has(req);
-    if (req === 'IPHONE_69' && fulfilledTypes.has('IPHONE_65')) isFulfilled = true;
+    const isFulfilled = isCovered(req);
     if (isFulfilled) fulfilledCount++;
 
     const label = deviceLabels[req] || req;
@@ -394,11 +413,7 @@ function renderRequirements() {
   html += fulfilledCount + '/' + REQS.required.length + ' required types covered';
   if (!isComplete) {
     const missingLabels = REQS.required
-      .filter(r => {
-        if (fulfilledTypes.has(r)) return false;
-        if (r === 'IPHONE_69' && fulfilledTypes.has('IPHONE_65')) return false;
-        return true;
-      })
+      .filter(r => !isCovered(r))
       .map(r => deviceLabels[r] || r);
     if (missingLabels.length > 0) html += ' — ' + missingLabels.join(', ') + ' still needed';
   }
@@ -16,6 +16,7 @@ import (
 	"github.com/moasq/nanowave/internal/claude"
 	"github.com/moasq/nanowave/internal/config"
 	"github.com/moasq/nanowave/internal/integrations"
+	"github.com/moasq/nanowave/internal/integrations/plugin"
 	"github.com/moasq/nanowave/internal/integrations/providers"
 	"github.com/moasq/nanowave/internal/orchestration"
 	"github.com/moasq/nanowave/internal/storage"
@@ -51,6 +52,9 @@ func NewService(cfg *config.Config, opts ...ServiceOpts) (*Service, error) {
 	// Store lives at ~/.nanowave/ (global, not per-project).
 	reg := integrations.NewRegistry()
 	providers.RegisterAll(reg)
+	for _, err := range providers.RegisterPlugins(context.Background(), reg, plugin.DefaultDir()) {
+		terminal.Warning(fmt.Sprintf("Skipping integration plugin: %v", err))
+	}
 	home, _ := os.UserHomeDir()
 	storeRoot := filepath.Join(home, ".nanowave")
 	intStore := integrations.NewIntegrationStore(storeRoot)
@@ -337,6 +341,10 @@ func (s *Service) ASC(ctx context.Context, prompt string) error {
 	// Record usage
 	s.usageStore.RecordUsage(result.TotalCostUSD, result.InputTokens, result.OutputTokens, result.CacheRead, result.CacheCreated)
 	if result.SessionID != "" {
+		// Reload: the publish may have recorded a release in the meantime.
+		if latest, err := s.projectStore.Load(); err == nil && latest != nil {
+			project = latest
+		}
 		project.SessionID = result.SessionID
 		s.projectStore.Save(project)
 	}
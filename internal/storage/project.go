@@ -22,6 +22,16 @@ type TestFlightSubmission struct {
 	Error     string     `json:"error,omitempty"`
 }
 
+// Release records a build published to App Store Connect.
+type Release struct {
+	Version     string    `json:"version"` // marketing version, e.g. "1.2"
+	BuildNumber int       `json:"build_number"`
+	AppID       string    `json:"app_id,omitempty"`
+	Commit      string    `json:"commit,omitempty"`    // project HEAD when the build was published
+	Changelog   []string  `json:"changelog,omitempty"` // commit subjects since the previous release
+	CreatedAt   time.Time `json:"created_at"`
+}
+
 // Project represents a local project.
 type Project struct {
 	ID                    int32                  `json:"id"`
@@ -37,6 +47,7 @@ type Project struct {
 	ConversationSummary   string                 `json:"conversation_summary,omitempty"`
 	ASCAppID              string                 `json:"asc_app_id,omitempty"`
 	TestFlightSubmissions []TestFlightSubmission `json:"testflight_submissions,omitempty"`
+	Releases              []Release              `json:"releases,omitempty"`
 	CreatedAt             time.Time              `json:"created_at"`
 }
 
@@ -163,6 +174,28 @@ func (s *ProjectStore) UpdateConversationSummary(_ context.Context, id int32, su
 	return s.Save(p)
 }
 
+// LastRelease returns the most recent release, or nil if there is none.
+func (p *Project) LastRelease() *Release {
+	if len(p.Releases) == 0 {
+		return nil
+	}
+	return &p.Releases[len(p.Releases)-1]
+}
+
+// AddRelease appends a release to the project's history.
+func (s *ProjectStore) AddRelease(_ context.Context, release Release) error {
+	p, err := s.Load()
+	if err != nil {
+		return err
+	}
+	if p == nil {
+		return fmt.Errorf("no project to record release %s (%d) in", release.Version, release.BuildNumber)
+	}
+
+	p.Releases = append(p.Releases, release)
+	return s.Save(p)
+}
+
 // Create creates a new project.
 func (s *ProjectStore) Create(name string) (*Project, error) {
 	p := &Project{
@@ -6,9 +6,14 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"mime"
+	"mime/multipart"
 	"net/http"
+	"net/url"
 	"os"
+	"strings"
 	"time"
+	"unicode/utf8"
 )
 
 const managementAPIBase = "https://api.supabase.com"
@@ -38,8 +43,6 @@ func newClientFromEnv() (*supabaseClient, error) {
 }
 
 func (c *supabaseClient) doJSON(ctx context.Context, method, path string, body any) (json.RawMessage, error) {
-	url := managementAPIBase + path
-
 	var reqBody io.Reader
 	if body != nil {
 		data, err := json.Marshal(body)
@@ -48,33 +51,47 @@ func (c *supabaseClient) doJSON(ctx context.Context, method, path string, body a
 		}
 		reqBody = bytes.NewReader(data)
 	}
+	return c.do(ctx, method, path, "application/json", reqBody)
+}
 
-	req, err := http.NewRequestWithContext(ctx, method, url, reqBody)
+// do sends a request to the Management API and returns the raw response body.
+func (c *supabaseClient) do(ctx context.Context, method, path, contentType string, reqBody io.Reader) (json.RawMessage, error) {
+	respData, _, err := c.send(ctx, method, path, contentType, reqBody)
 	if err != nil {
 		return nil, err
 	}
+	if len(respData) == 0 {
+		return json.RawMessage("{}"), nil
+	}
+	return json.RawMessage(respData), nil
+}
+
+// send performs the request and returns the body and headers of a 2xx response.
+func (c *supabaseClient) send(ctx context.Context, method, path, contentType string, reqBody io.Reader) ([]byte, http.Header, error) {
+	req, err := http.NewRequestWithContext(ctx, method, managementAPIBase+path, reqBody)
+	if err != nil {
+		return nil, nil, err
+	}
 	req.Header.Set("Authorization", "Bearer "+c.pat)
-	req.Header.Set("Content-Type", "application/json")
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	defer resp.Body.Close()
 
 	respData, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, fmt.Errorf("read response: %w", err)
+		return nil, nil, fmt.Errorf("read response: %w", err)
 	}
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return nil, fmt.Errorf("API %s %s returned %d: %s", method, path, resp.StatusCode, string(respData))
-	}
-
-	if len(respData) == 0 {
-		return json.RawMessage("{}"), nil
+		return nil, nil, fmt.Errorf("API %s %s returned %d: %s", method, path, resp.StatusCode, string(respData))
 	}
-	return json.RawMessage(respData), nil
+	return respData, resp.Header, nil
 }
 
 // executeSQL runs a SQL query via the Management API.
@@ -177,3 +194,103 @@ func (c *supabaseClient) deleteSecrets(ctx context.Context, names []string) erro
 	_, err := c.doJSON(ctx, http.MethodDelete, path, names)
 	return err
 }
+
+// functionFile is one source file of an Edge Function bundle.
+type functionFile struct {
+	Name    string `json:"name" jsonschema:"File path relative to the function root (e.g. index.ts)"`
+	Content string `json:"content" jsonschema:"File contents"`
+}
+
+// deployFunction creates or updates an Edge Function from source files.
+// Uses the multipart deploy endpoint, which bundles the files server-side.
+func (c *supabaseClient) deployFunction(ctx context.Context, slug, entrypoint string, verifyJWT bool, files []functionFile) (json.RawMessage, error) {
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+
+	metadata, err := json.Marshal(map[string]any{
+		"entrypoint_path": entrypoint,
+		"name":            slug,
+		"verify_jwt":      verifyJWT,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("marshal function metadata: %w", err)
+	}
+	if err := w.WriteField("metadata", string(metadata)); err != nil {
+		return nil, err
+	}
+	for _, f := range files {
+		part, err := w.CreateFormFile("file", f.Name)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := io.WriteString(part, f.Content); err != nil {
+			return nil, err
+		}
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+
+	path := fmt.Sprintf("/v1/projects/%s/functions/deploy?slug=%s", c.projectRef, url.QueryEscape(slug))
+	return c.do(ctx, http.MethodPost, path, w.FormDataContentType(), &body)
+}
+
+// listFunctions returns metadata for every Edge Function in the project.
+func (c *supabaseClient) listFunctions(ctx context.Context) (json.RawMessage, error) {
+	path := fmt.Sprintf("/v1/projects/%s/functions", c.projectRef)
+	return c.doJSON(ctx, http.MethodGet, path, nil)
+}
+
+// getFunction returns metadata for one Edge Function.
+func (c *supabaseClient) getFunction(ctx context.Context, slug string) (json.RawMessage, error) {
+	path := fmt.Sprintf("/v1/projects/%s/functions/%s", c.projectRef, url.PathEscape(slug))
+	return c.doJSON(ctx, http.MethodGet, path, nil)
+}
+
+// getFunctionFiles downloads the deployed source of an Edge Function.
+// Functions deployed from source come back as multipart form data with one part per
+// file; older bundles are returned as a single body, which is kept if it is text.
+func (c *supabaseClient) getFunctionFiles(ctx context.Context, slug string) ([]functionFile, error) {
+	path := fmt.Sprintf("/v1/projects/%s/functions/%s/body", c.projectRef, url.PathEscape(slug))
+	body, header, err := c.send(ctx, http.MethodGet, path, "", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	mediaType, params, err := mime.ParseMediaType(header.Get("Content-Type"))
+	if err == nil && strings.HasPrefix(mediaType, "multipart/") {
+		r := multipart.NewReader(bytes.NewReader(body), params["boundary"])
+		var files []functionFile
+		for {
+			part, err := r.NextPart()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return nil, fmt.Errorf("parse function body: %w", err)
+			}
+			content, err := io.ReadAll(part)
+			if err != nil {
+				return nil, fmt.Errorf("parse function body: %w", err)
+			}
+			name := part.FileName()
+			if name == "" {
+				continue // metadata part
+			}
+			files = append(files, functionFile{Name: name, Content: string(content)})
+		}
+		return files, nil
+	}
+
+	if !utf8.Valid(body) {
+		return nil, fmt.Errorf("function %s is stored as a compiled bundle; its source cannot be read back — redeploy it with deploy_edge_function", slug)
+	}
+	return []functionFile{{Name: "index.ts", Content: string(body)}}, nil
+}
+
+// deleteFunction removes an Edge Function.
+func (c *supabaseClient) deleteFunction(ctx context.Context, slug string) error {
+	path := fmt.Sprintf("/v1/projects/%s/functions/%s", c.projectRef, url.PathEscape(slug))
+	_, err := c.doJSON(ctx, http.MethodDelete, path, nil)
+	return err
+}
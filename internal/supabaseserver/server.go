@@ -77,5 +77,25 @@ func Run(ctx context.Context) error {
 		Description: "Delete edge function secrets by name.",
 	}, handleDeleteSecrets)
 
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "deploy_edge_function",
+		Description: "Create or update an Edge Function from TypeScript source files. Deploys and bundles on the server — no local Supabase CLI or Docker needed. Invoked at https://<ref>.supabase.co/functions/v1/<name>.",
+	}, handleDeployEdgeFunction)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "list_edge_functions",
+		Description: "List the project's Edge Functions with slug, status, version, verify_jwt, and timestamps.",
+	}, handleListEdgeFunctions)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "get_edge_function",
+		Description: "Get an Edge Function's metadata and deployed source files. Read the current source before editing and redeploying an existing function.",
+	}, handleGetEdgeFunction)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "delete_edge_function",
+		Description: "Delete an Edge Function. The app can no longer invoke it — remove client calls first.",
+	}, handleDeleteEdgeFunction)
+
 	return server.Run(ctx, &mcp.StdioTransport{})
 }
@@ -317,3 +317,116 @@ func handleDeleteSecrets(ctx context.Context, req *mcp.CallToolRequest, input de
 	}
 	return nil, textOutput{Message: fmt.Sprintf("Secrets deleted: %s", strings.Join(input.Names, ", "))}, nil
 }
+
+// --- deploy_edge_function ---
+
+type deployEdgeFunctionInput struct {
+	Name       string         `json:"name" jsonschema:"Function slug (lowercase, hyphens, e.g. verify-transaction)"`
+	Entrypoint string         `json:"entrypoint" jsonschema:"Entrypoint file name (default: index.ts)"`
+	VerifyJWT  *bool          `json:"verify_jwt,omitempty" jsonschema:"Require a valid Supabase JWT to invoke (default: true)"`
+	Files      []functionFile `json:"files" jsonschema:"Source files of the function, including the entrypoint"`
+}
+
+func handleDeployEdgeFunction(ctx context.Context, req *mcp.CallToolRequest, input deployEdgeFunctionInput) (*mcp.CallToolResult, textOutput, error) {
+	if input.Name == "" {
+		return nil, textOutput{}, fmt.Errorf("name is required")
+	}
+	if len(input.Files) == 0 {
+		return nil, textOutput{}, fmt.Errorf("at least one file is required")
+	}
+	entrypoint := input.Entrypoint
+	if entrypoint == "" {
+		entrypoint = "index.ts"
+	}
+	hasEntrypoint := false
+	for _, f := range input.Files {
+		if f.Name == entrypoint {
+			hasEntrypoint = true
+			break
+		}
+	}
+	if !hasEntrypoint {
+		return nil, textOutput{}, fmt.Errorf("files must include the entrypoint %q", entrypoint)
+	}
+	verifyJWT := true
+	if input.VerifyJWT != nil {
+		verifyJWT = *input.VerifyJWT
+	}
+	c, err := newClientFromEnv()
+	if err != nil {
+		return nil, textOutput{}, err
+	}
+	raw, err := c.deployFunction(ctx, input.Name, entrypoint, verifyJWT, input.Files)
+	if err != nil {
+		return nil, textOutput{}, err
+	}
+	return nil, textOutput{Message: string(raw)}, nil
+}
+
+// --- list_edge_functions ---
+
+type listEdgeFunctionsInput struct{}
+
+func handleListEdgeFunctions(ctx context.Context, req *mcp.CallToolRequest, input listEdgeFunctionsInput) (*mcp.CallToolResult, textOutput, error) {
+	c, err := newClientFromEnv()
+	if err != nil {
+		return nil, textOutput{}, err
+	}
+	raw, err := c.listFunctions(ctx)
+	if err != nil {
+		return nil, textOutput{}, err
+	}
+	return nil, textOutput{Message: string(raw)}, nil
+}
+
+// --- get_edge_function ---
+
+type getEdgeFunctionInput struct {
+	Name string `json:"name" jsonschema:"Function slug"`
+}
+
+func handleGetEdgeFunction(ctx context.Context, req *mcp.CallToolRequest, input getEdgeFunctionInput) (*mcp.CallToolResult, textOutput, error) {
+	if input.Name == "" {
+		return nil, textOutput{}, fmt.Errorf("name is required")
+	}
+	c, err := newClientFromEnv()
+	if err != nil {
+		return nil, textOutput{}, err
+	}
+	meta, err := c.getFunction(ctx, input.Name)
+	if err != nil {
+		return nil, textOutput{}, err
+	}
+	files, err := c.getFunctionFiles(ctx, input.Name)
+	if err != nil {
+		return nil, textOutput{}, err
+	}
+	out, err := json.Marshal(map[string]any{
+		"function": meta,
+		"files":    files,
+	})
+	if err != nil {
+		return nil, textOutput{}, err
+	}
+	return nil, textOutput{Message: string(out)}, nil
+}
+
+// --- delete_edge_function ---
+
+type deleteEdgeFunctionInput struct {
+	Name string `json:"name" jsonschema:"Function slug to delete"`
+}
+
+func handleDeleteEdgeFunction(ctx context.Context, req *mcp.CallToolRequest, input deleteEdgeFunctionInput) (*mcp.CallToolResult, textOutput, error) {
+	if input.Name == "" {
+		return nil, textOutput{}, fmt.Errorf("name is required")
+	}
+	c, err := newClientFromEnv()
+	if err != nil {
+		return nil, textOutput{}, err
+	}
+	if err := c.deleteFunction(ctx, input.Name); err != nil {
+		return nil, textOutput{}, err
+	}
+	return nil, textOutput{Message: fmt.Sprintf("Deleted edge function %s", input.Name)}, nil
+}
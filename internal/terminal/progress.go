@@ -692,8 +692,49 @@ func friendlyToolName(toolName string, inputGetter func(key string) string) stri
 			return truncateActivity("Adding " + lang + " localization")
 		}
 		return "Adding localization"
+	case "mcp__xcodegen__add_url_scheme":
+		if scheme := inputGetter("scheme"); scheme != "" {
+			return truncateActivity("Registering " + scheme + ":// URL scheme")
+		}
+		return "Registering URL scheme"
 	case "mcp__xcodegen__set_build_setting":
 		return "Updating build settings"
+	case "mcp__xcodegen__remove_permission":
+		if key := inputGetter("key"); key != "" {
+			return truncateActivity("Removing permission: " + key)
+		}
+		return "Removing permission"
+	case "mcp__xcodegen__remove_extension":
+		if name := inputGetter("name"); name != "" {
+			return truncateActivity("Removing extension: " + name)
+		}
+		return "Removing extension"
+	case "mcp__xcodegen__remove_entitlement":
+		return "Removing entitlement"
+	case "mcp__xcodegen__remove_package":
+		if name := inputGetter("name"); name != "" {
+			return truncateActivity("Removing package: " + name)
+		}
+		return "Removing package"
+	case "mcp__xcodegen__update_package_version":
+		if name := inputGetter("name"); name != "" {
+			return truncateActivity("Updating package: " + name)
+		}
+		return "Updating package"
+	case "mcp__xcodegen__remove_localization":
+		return "Removing localization"
+	case "mcp__xcodegen__unset_build_setting":
+		return "Updating build settings"
+	case "mcp__xcodegen__add_configuration":
+		if name := inputGetter("name"); name != "" {
+			return truncateActivity("Adding configuration: " + name)
+		}
+		return "Adding configuration"
+	case "mcp__xcodegen__set_configuration_value":
+		if config := inputGetter("configuration"); config != "" {
+			return truncateActivity("Configuring " + config)
+		}
+		return "Updating configuration"
 	case "mcp__xcodegen__get_project_config":
 		return "Reading project config"
 	case "mcp__xcodegen__regenerate_project":
@@ -0,0 +1,182 @@
+package testflight
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/moasq/nanowave/internal/asc"
+)
+
+// FeedbackDir is where the feedback report is written, relative to the
+// project directory. Each pull replaces its contents.
+const FeedbackDir = "testflight/feedback"
+
+// Feedback is what testers reported for an app, optionally for one build.
+type Feedback struct {
+	// Build is the build number the feedback was limited to, or empty for all.
+	Build       string
+	Screenshots []asc.Feedback
+	Crashes     []asc.Feedback
+	// CrashLogs maps crash submission IDs to their logs. Crashes without a
+	// log (still symbolicating, or expired) are absent.
+	CrashLogs map[string]string
+	// Images maps screenshot URLs to the downloaded file, relative to
+	// FeedbackDir, once Write has saved them.
+	Images map[string]string
+}
+
+// PullFeedback fetches screenshot and crash feedback with the crash logs.
+// buildID and build may be empty to fetch feedback for every build.
+func PullFeedback(ctx context.Context, client *asc.Client, appID, buildID, build string) (*Feedback, error) {
+	shots, err := client.ListFeedbackScreenshots(ctx, appID, buildID)
+	if err != nil {
+		return nil, err
+	}
+	crashes, err := client.ListFeedbackCrashes(ctx, appID, buildID)
+	if err != nil {
+		return nil, err
+	}
+	f := &Feedback{Build: build, Screenshots: shots, Crashes: crashes, CrashLogs: map[string]string{}, Images: map[string]string{}}
+	for _, c := range crashes {
+		log, err := client.CrashLog(ctx, c.ID)
+		if err != nil && !asc.IsNotFound(err) {
+			return nil, fmt.Errorf("crash log %s: %w", c.ID, err)
+		}
+		if log != "" {
+			f.CrashLogs[c.ID] = log
+		}
+	}
+	return f, nil
+}
+
+// Write saves the report as report.md in FeedbackDir, crash logs as
+// crashes/<id>.crash and, when httpClient is non-nil, the screenshots as
+// screenshots/<id>-<n>.<ext>. Screenshots that fail to download stay linked
+// to App Store Connect. It returns the report's path.
+func (f *Feedback) Write(ctx context.Context, projectDir string, httpClient *http.Client) (string, error) {
+	dir := filepath.Join(projectDir, FeedbackDir)
+	if err := os.RemoveAll(dir); err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(filepath.Join(dir, "crashes"), 0o755); err != nil {
+		return "", err
+	}
+	for id, log := range f.CrashLogs {
+		if err := os.WriteFile(filepath.Join(dir, "crashes", id+".crash"), []byte(log), 0o644); err != nil {
+			return "", err
+		}
+	}
+	if httpClient != nil {
+		if err := os.MkdirAll(filepath.Join(dir, "screenshots"), 0o755); err != nil {
+			return "", err
+		}
+		for _, s := range f.Screenshots {
+			for i, img := range s.Screenshots {
+				name := fmt.Sprintf("%s-%d%s", s.ID, i+1, imageExt(img.URL))
+				if download(ctx, httpClient, img.URL, filepath.Join(dir, "screenshots", name)) == nil {
+					f.Images[img.URL] = "screenshots/" + name
+				}
+			}
+		}
+	}
+	reportPath := filepath.Join(dir, "report.md")
+	if err := os.WriteFile(reportPath, []byte(f.Markdown()), 0o644); err != nil {
+		return "", err
+	}
+	return reportPath, nil
+}
+
+// Markdown renders the report, newest feedback first.
+func (f *Feedback) Markdown() string {
+	var b strings.Builder
+	b.WriteString("# TestFlight feedback\n\n")
+	if f.Build != "" {
+		fmt.Fprintf(&b, "Build %s. ", f.Build)
+	}
+	fmt.Fprintf(&b, "%d screenshot submission(s), %d crash submission(s).\n", len(f.Screenshots), len(f.Crashes))
+
+	if len(f.Crashes) > 0 {
+		b.WriteString("\n## Crashes\n")
+		for _, c := range f.Crashes {
+			writeSubmission(&b, c)
+			if _, ok := f.CrashLogs[c.ID]; ok {
+				fmt.Fprintf(&b, "\nLog: [crashes/%s.crash](crashes/%s.crash)\n", c.ID, c.ID)
+			} else {
+				b.WriteString("\nNo crash log available.\n")
+			}
+		}
+	}
+	if len(f.Screenshots) > 0 {
+		b.WriteString("\n## Screenshot feedback\n")
+		for _, s := range f.Screenshots {
+			writeSubmission(&b, s)
+			for i, img := range s.Screenshots {
+				target := img.URL
+				if local, ok := f.Images[img.URL]; ok {
+					target = local
+				}
+				fmt.Fprintf(&b, "\n![Screenshot %d](%s)\n", i+1, target)
+			}
+		}
+	}
+	return b.String()
+}
+
+func writeSubmission(b *strings.Builder, s asc.Feedback) {
+	fmt.Fprintf(b, "\n### %s\n\n", s.CreatedDate.UTC().Format(time.DateTime+" UTC"))
+	tester := s.Email
+	if tester == "" {
+		tester = "anonymous"
+	}
+	fmt.Fprintf(b, "- Tester: %s\n", tester)
+	if s.DeviceModel != "" || s.OSVersion != "" {
+		fmt.Fprintf(b, "- Device: %s\n", strings.TrimSpace(s.DeviceModel+" "+s.AppPlatform+" "+s.OSVersion))
+	}
+	if comment := strings.TrimSpace(s.Comment); comment != "" {
+		fmt.Fprintf(b, "\n> %s\n", strings.ReplaceAll(comment, "\n", "\n> "))
+	}
+}
+
+// imageExt returns the extension of the image at rawURL, defaulting to .png.
+func imageExt(rawURL string) string {
+	if i := strings.IndexAny(rawURL, "?#"); i >= 0 {
+		rawURL = rawURL[:i]
+	}
+	switch ext := strings.ToLower(path.Ext(rawURL)); ext {
+	case ".png", ".jpg", ".jpeg", ".heic":
+		return ext
+	}
+	return ".png"
+}
+
+func download(ctx context.Context, httpClient *http.Client, rawURL, dest string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s: %s", rawURL, resp.Status)
+	}
+	out, err := os.Create(dest)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, resp.Body); err != nil {
+		out.Close()
+		os.Remove(dest)
+		return err
+	}
+	return out.Close()
+}
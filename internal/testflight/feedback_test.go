@@ -0,0 +1,78 @@
+package testflight
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/moasq/nanowave/internal/asc/asctest"
+)
+
+func TestFeedbackReport(t *testing.T) {
+	images := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/s1.png" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte("png"))
+	}))
+	defer images.Close()
+
+	srv := asctest.NewServer(t)
+	app := asctest.Ref{Type: "apps", ID: "app1"}
+	srv.Add(asctest.Resource{Type: "apps", ID: "app1"})
+	created := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	srv.Add(asctest.Resource{Type: "betaFeedbackScreenshotSubmissions", ID: "s1",
+		Attributes: map[string]any{"createdDate": created, "comment": "Button is cut off", "email": "ada@example.com",
+			"screenshots": []map[string]any{{"url": images.URL + "/s1.png"}, {"url": images.URL + "/expired.png"}}},
+		Relationships: map[string]asctest.Ref{"app": app}})
+	srv.Add(asctest.Resource{Type: "betaCrashLogs", ID: "log1", Attributes: map[string]any{"logText": "Exception Type: EXC_CRASH"}})
+	srv.Add(asctest.Resource{Type: "betaFeedbackCrashSubmissions", ID: "c1",
+		Attributes:    map[string]any{"createdDate": created, "comment": "Crashed on launch", "deviceModel": "iPhone16,1"},
+		Relationships: map[string]asctest.Ref{"app": app, "crashLog": {Type: "betaCrashLogs", ID: "log1"}}})
+	srv.Add(asctest.Resource{Type: "betaFeedbackCrashSubmissions", ID: "c2",
+		Attributes:    map[string]any{"createdDate": created.Add(time.Hour)},
+		Relationships: map[string]asctest.Ref{"app": app}})
+
+	ctx := context.Background()
+	feedback, err := PullFeedback(ctx, srv.APIClient(t), "app1", "", "")
+	if err != nil {
+		t.Fatalf("PullFeedback() error: %v", err)
+	}
+	if len(feedback.Crashes) != 2 || len(feedback.CrashLogs) != 1 {
+		t.Fatalf("PullFeedback() = %d crashes with %d logs; want 2 crashes, 1 log", len(feedback.Crashes), len(feedback.CrashLogs))
+	}
+
+	dir := t.TempDir()
+	reportPath, err := feedback.Write(ctx, dir, images.Client())
+	if err != nil {
+		t.Fatalf("Write() error: %v", err)
+	}
+	report, err := os.ReadFile(reportPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"1 screenshot submission(s), 2 crash submission(s)",
+		"> Crashed on launch",
+		"[crashes/c1.crash](crashes/c1.crash)",
+		"No crash log available.",
+		"![Screenshot 1](screenshots/s1-1.png)",
+		"![Screenshot 2](" + images.URL + "/expired.png)",
+	} {
+		if !strings.Contains(string(report), want) {
+			t.Errorf("report is missing %q:\n%s", want, report)
+		}
+	}
+	if log, err := os.ReadFile(filepath.Join(dir, FeedbackDir, "crashes", "c1.crash")); err != nil || string(log) != "Exception Type: EXC_CRASH" {
+		t.Errorf("crash log = %q, %v", log, err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, FeedbackDir, "screenshots", "s1-2.png")); !os.IsNotExist(err) {
+		t.Error("a failed screenshot download left a file behind")
+	}
+}
@@ -0,0 +1,182 @@
+// Package testflight manages who receives TestFlight builds and collects what
+// they report back: tester lists read from CSV files, group membership
+// changes, and a local report of feedback and crash submissions.
+package testflight
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/moasq/nanowave/internal/asc"
+)
+
+// Tester is one row of a tester CSV file.
+type Tester struct {
+	Email     string
+	FirstName string
+	LastName  string
+}
+
+// ParseTesters reads testers from CSV rows of email, first name and last
+// name. The names are optional, as is a header row naming the columns; with
+// a header the columns may come in any order. Emails are matched without
+// regard to case, and repeated emails keep their first row.
+func ParseTesters(r io.Reader) ([]Tester, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	reader.Comment = '#'
+
+	columns := map[string]int{"email": 0, "first_name": 1, "last_name": 2}
+	var testers []Tester
+	seen := map[string]bool{}
+	for row := 1; ; row++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if row == 1 && isHeader(record) {
+			columns = headerColumns(record)
+			if _, ok := columns["email"]; !ok {
+				return nil, fmt.Errorf("header has no email column")
+			}
+			continue
+		}
+		field := func(name string) string {
+			i, ok := columns[name]
+			if !ok || i >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[i])
+		}
+		t := Tester{Email: field("email"), FirstName: field("first_name"), LastName: field("last_name")}
+		if t.Email == "" && t.FirstName == "" && t.LastName == "" {
+			continue
+		}
+		if !strings.Contains(t.Email, "@") {
+			return nil, fmt.Errorf("row %d: %q is not an email address", row, t.Email)
+		}
+		key := strings.ToLower(t.Email)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		testers = append(testers, t)
+	}
+	return testers, nil
+}
+
+func isHeader(record []string) bool {
+	for _, f := range record {
+		if normalizeColumn(f) == "email" {
+			return true
+		}
+	}
+	return false
+}
+
+func headerColumns(record []string) map[string]int {
+	columns := map[string]int{}
+	for i, f := range record {
+		switch normalizeColumn(f) {
+		case "email":
+			columns["email"] = i
+		case "first_name", "firstname", "first":
+			columns["first_name"] = i
+		case "last_name", "lastname", "last":
+			columns["last_name"] = i
+		}
+	}
+	return columns
+}
+
+func normalizeColumn(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	name = strings.ReplaceAll(name, " ", "_")
+	return strings.ReplaceAll(name, "-", "_")
+}
+
+// Change is the outcome of adding or removing testers.
+type Change struct {
+	// Invited are testers that were new to the account.
+	Invited []string
+	// Added are existing testers added to the group.
+	Added []string
+	// Removed are testers removed from the group.
+	Removed []string
+	// Unknown are testers not found in the account, so not removed.
+	Unknown []string
+}
+
+// AddTesters adds testers to a group, inviting those the account does not
+// know yet.
+func AddTesters(ctx context.Context, client *asc.Client, groupID string, testers []Tester) (*Change, error) {
+	change := &Change{}
+	var existing, existingEmails []string
+	for _, t := range testers {
+		found, ok, err := client.FindBetaTester(ctx, t.Email)
+		if err != nil {
+			return change, fmt.Errorf("look up %s: %w", t.Email, err)
+		}
+		if ok {
+			existing = append(existing, found.ID)
+			existingEmails = append(existingEmails, t.Email)
+			continue
+		}
+		if _, err := client.CreateBetaTester(ctx, t.Email, t.FirstName, t.LastName, groupID); err != nil {
+			return change, fmt.Errorf("invite %s: %w", t.Email, err)
+		}
+		change.Invited = append(change.Invited, t.Email)
+	}
+	if len(existing) > 0 {
+		if err := client.AddTestersToGroup(ctx, groupID, existing...); err != nil {
+			return change, err
+		}
+		change.Added = existingEmails
+	}
+	return change, nil
+}
+
+// RemoveTesters removes testers from a group. Testers stay in the account and
+// in any other group.
+func RemoveTesters(ctx context.Context, client *asc.Client, groupID string, testers []Tester) (*Change, error) {
+	change := &Change{}
+	var ids, emails []string
+	for _, t := range testers {
+		found, ok, err := client.FindBetaTester(ctx, t.Email)
+		if err != nil {
+			return change, fmt.Errorf("look up %s: %w", t.Email, err)
+		}
+		if !ok {
+			change.Unknown = append(change.Unknown, t.Email)
+			continue
+		}
+		ids = append(ids, found.ID)
+		emails = append(emails, t.Email)
+	}
+	if len(ids) > 0 {
+		if err := client.RemoveTestersFromGroup(ctx, groupID, ids...); err != nil {
+			return change, err
+		}
+		change.Removed = emails
+	}
+	return change, nil
+}
+
+// FindGroup returns the group whose name or ID matches, ignoring case in
+// names.
+func FindGroup(groups []asc.BetaGroup, nameOrID string) (asc.BetaGroup, bool) {
+	for _, g := range groups {
+		if g.ID == nameOrID || strings.EqualFold(g.Name, nameOrID) {
+			return g, true
+		}
+	}
+	return asc.BetaGroup{}, false
+}
@@ -0,0 +1,103 @@
+package testflight
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/moasq/nanowave/internal/asc/asctest"
+)
+
+func TestParseTesters(t *testing.T) {
+	tests := []struct {
+		name string
+		csv  string
+		want []Tester
+	}{
+		{
+			name: "no header",
+			csv:  "ada@example.com,Ada,Lovelace\ngrace@example.com\n",
+			want: []Tester{{"ada@example.com", "Ada", "Lovelace"}, {"grace@example.com", "", ""}},
+		},
+		{
+			name: "header in another order",
+			csv:  "Last Name,First Name,Email\nLovelace,Ada,ada@example.com\n",
+			want: []Tester{{"ada@example.com", "Ada", "Lovelace"}},
+		},
+		{
+			name: "duplicates, blanks and comments",
+			csv:  "# beta list\nada@example.com,Ada\n\nADA@example.com,Other\n",
+			want: []Tester{{"ada@example.com", "Ada", ""}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseTesters(strings.NewReader(tt.csv))
+			if err != nil {
+				t.Fatalf("ParseTesters() error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseTesters() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := ParseTesters(strings.NewReader("Ada,Lovelace\n")); err == nil {
+		t.Error("ParseTesters() accepted a row without an email")
+	}
+	if _, err := ParseTesters(strings.NewReader("name,email_address\n")); err == nil {
+		t.Error("ParseTesters() accepted a header without an email column")
+	}
+}
+
+func TestAddAndRemoveTesters(t *testing.T) {
+	srv := asctest.NewServer(t)
+	srv.Add(asctest.Resource{Type: "betaGroups", ID: "g1", Attributes: map[string]any{"name": "Friends"}})
+	srv.Add(asctest.Resource{Type: "betaTesters", ID: "t1", Attributes: map[string]any{"email": "ada@example.com"}})
+	client := srv.APIClient(t)
+	ctx := context.Background()
+	group := asctest.Ref{Type: "betaGroups", ID: "g1"}
+
+	testers := []Tester{{Email: "ada@example.com"}, {Email: "grace@example.com", FirstName: "Grace"}}
+	change, err := AddTesters(ctx, client, "g1", testers)
+	if err != nil {
+		t.Fatalf("AddTesters() error: %v", err)
+	}
+	if !reflect.DeepEqual(change.Added, []string{"ada@example.com"}) || !reflect.DeepEqual(change.Invited, []string{"grace@example.com"}) {
+		t.Errorf("AddTesters() = %+v; want ada added and grace invited", change)
+	}
+	if members := srv.Linked(group, "betaTesters"); len(members) != 2 {
+		t.Fatalf("group has %d testers, want 2", len(members))
+	}
+
+	change, err = RemoveTesters(ctx, client, "g1", []Tester{{Email: "ada@example.com"}, {Email: "nobody@example.com"}})
+	if err != nil {
+		t.Fatalf("RemoveTesters() error: %v", err)
+	}
+	if !reflect.DeepEqual(change.Removed, []string{"ada@example.com"}) || !reflect.DeepEqual(change.Unknown, []string{"nobody@example.com"}) {
+		t.Errorf("RemoveTesters() = %+v; want ada removed and nobody unknown", change)
+	}
+	members := srv.Linked(group, "betaTesters")
+	if len(members) != 1 || members[0].Attributes["email"] != "grace@example.com" {
+		t.Errorf("group testers after removal = %+v, want only grace", members)
+	}
+}
+
+func TestChangeTestersReportsOnlyAppliedChanges(t *testing.T) {
+	srv := asctest.NewServer(t)
+	srv.Add(asctest.Resource{Type: "betaTesters", ID: "t1", Attributes: map[string]any{"email": "ada@example.com"}})
+	client := srv.APIClient(t)
+	ctx := context.Background()
+	testers := []Tester{{Email: "ada@example.com"}}
+
+	// The group is gone, so the relationship requests fail.
+	change, err := AddTesters(ctx, client, "missing", testers)
+	if err == nil || len(change.Added) != 0 {
+		t.Errorf("AddTesters() = %+v, %v; want an error and nothing added", change, err)
+	}
+	change, err = RemoveTesters(ctx, client, "missing", testers)
+	if err == nil || len(change.Removed) != 0 {
+		t.Errorf("RemoveTesters() = %+v, %v; want an error and nothing removed", change, err)
+	}
+}
@@ -0,0 +1,136 @@
+// Package versioning manages an app's marketing version and build number
+// across publishes: semver bumps, build numbers that stay above the builds
+// already uploaded to App Store Connect, and changelogs read from the
+// project's git history.
+package versioning
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// Parts of a marketing version that Bump increments.
+const (
+	Major = "major"
+	Minor = "minor"
+	Patch = "patch"
+)
+
+// Bump increments one part of a marketing version and resets the parts after
+// it. The version keeps its number of components unless a patch bump needs a
+// third one: Bump("1.0", Minor) is "1.1", Bump("1.0", Patch) is "1.0.1".
+func Bump(version, part string) (string, error) {
+	fields := strings.Split(version, ".")
+	if len(fields) > 3 {
+		return "", fmt.Errorf("version %q has more than three components", version)
+	}
+	nums := make([]int, 3)
+	for i, f := range fields {
+		n, err := strconv.Atoi(f)
+		if err != nil || n < 0 {
+			return "", fmt.Errorf("version %q is not numeric", version)
+		}
+		nums[i] = n
+	}
+
+	width := max(len(fields), 2)
+	switch part {
+	case Major:
+		nums = []int{nums[0] + 1, 0, 0}
+	case Minor:
+		nums = []int{nums[0], nums[1] + 1, 0}
+	case Patch:
+		nums[2]++
+		width = 3
+	default:
+		return "", fmt.Errorf("unknown version part %q (want major, minor or patch)", part)
+	}
+
+	parts := make([]string, width)
+	for i := range parts {
+		parts[i] = strconv.Itoa(nums[i])
+	}
+	return strings.Join(parts, "."), nil
+}
+
+// Compare compares two marketing versions numerically, treating missing
+// components as zero: Compare("1.10", "1.9") is 1 and Compare("1.0", "1.0.0")
+// is 0. Components that are not numbers count as zero.
+func Compare(a, b string) int {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := range max(len(as), len(bs)) {
+		var x, y int
+		if i < len(as) {
+			x, _ = strconv.Atoi(as[i])
+		}
+		if i < len(bs) {
+			y, _ = strconv.Atoi(bs[i])
+		}
+		if x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+// NextBuild returns the build number the next upload should use: the current
+// one, unless App Store Connect already has that build or a higher one, in
+// which case it is one above the highest uploaded build. Uploaded build
+// numbers that are not integers are ignored.
+func NextBuild(current int, uploaded []string) int {
+	highest := 0
+	for _, b := range uploaded {
+		// Builds like "12.1" sort by their leading integer.
+		n, err := strconv.Atoi(strings.SplitN(strings.TrimSpace(b), ".", 2)[0])
+		if err == nil && n > highest {
+			highest = n
+		}
+	}
+	return max(current, highest+1, 1)
+}
+
+// Changelog returns the HEAD commit of the project's git repository and the
+// subjects of the commits since the since commit, newest first. An empty since,
+// or one git no longer knows, lists the whole history.
+func Changelog(ctx context.Context, projectDir, since string) (string, []string, error) {
+	head, err := git(ctx, projectDir, "rev-parse", "HEAD")
+	if err != nil {
+		return "", nil, err
+	}
+	args := []string{"log", "--no-merges", "--format=%s"}
+	if since != "" {
+		if _, err := git(ctx, projectDir, "cat-file", "-e", since+"^{commit}"); err == nil {
+			args = append(args, since+"..HEAD")
+		}
+	}
+	out, err := git(ctx, projectDir, args...)
+	if err != nil {
+		return "", nil, err
+	}
+	var entries []string
+	for _, line := range strings.Split(out, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			entries = append(entries, line)
+		}
+	}
+	return head, entries, nil
+}
+
+func git(ctx context.Context, dir string, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("git %s: %w: %s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return strings.TrimSpace(string(out)), nil
+}
@@ -0,0 +1,113 @@
+package versioning
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestBump(t *testing.T) {
+	tests := []struct {
+		version, part, want string
+	}{
+		{"1.0", Minor, "1.1"},
+		{"1.0", Major, "2.0"},
+		{"1.0", Patch, "1.0.1"},
+		{"1.2.3", Minor, "1.3.0"},
+		{"1.2.3", Major, "2.0.0"},
+		{"1.2.3", Patch, "1.2.4"},
+		{"3", Minor, "3.1"},
+	}
+	for _, tt := range tests {
+		if got, err := Bump(tt.version, tt.part); err != nil || got != tt.want {
+			t.Errorf("Bump(%q, %s) = %q, %v, want %q", tt.version, tt.part, got, err, tt.want)
+		}
+	}
+	for _, bad := range [][2]string{{"1.x", Minor}, {"1.2.3.4", Patch}, {"1.0", "build"}} {
+		if _, err := Bump(bad[0], bad[1]); err == nil {
+			t.Errorf("Bump(%q, %s) succeeded", bad[0], bad[1])
+		}
+	}
+}
+
+func TestNextBuild(t *testing.T) {
+	tests := []struct {
+		current  int
+		uploaded []string
+		want     int
+	}{
+		{1, nil, 1},
+		{0, nil, 1},
+		{5, []string{"4", "3"}, 5},
+		{5, []string{"5"}, 6},
+		{2, []string{"9", "12", "10"}, 13},
+		{2, []string{"7.1", "beta"}, 8},
+	}
+	for _, tt := range tests {
+		if got := NextBuild(tt.current, tt.uploaded); got != tt.want {
+			t.Errorf("NextBuild(%d, %v) = %d, want %d", tt.current, tt.uploaded, got, tt.want)
+		}
+	}
+}
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"1.10", "1.9", 1},
+		{"1.0", "1.0.0", 0},
+		{"1.2", "1.2.1", -1},
+		{"2", "1.9.9", 1},
+	}
+	for _, tt := range tests {
+		if got := Compare(tt.a, tt.b); got != tt.want {
+			t.Errorf("Compare(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestChangelog(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	dir := t.TempDir()
+	run := func(args ...string) string {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(), "GIT_AUTHOR_NAME=t", "GIT_AUTHOR_EMAIL=t@example.com", "GIT_COMMITTER_NAME=t", "GIT_COMMITTER_EMAIL=t@example.com")
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+		return strings.TrimSpace(string(out))
+	}
+	commit := func(msg string) string {
+		os.WriteFile(filepath.Join(dir, "log.txt"), []byte(msg), 0o644)
+		run("add", "-A")
+		run("commit", "-q", "-m", msg)
+		return run("rev-parse", "HEAD")
+	}
+	run("init", "-q")
+	first := commit("Initial build: Habits")
+	commit("Add streak counter")
+	head := commit("Fix reminder time zone")
+
+	gotHead, entries, err := Changelog(context.Background(), dir, first)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gotHead != head || strings.Join(entries, "|") != "Fix reminder time zone|Add streak counter" {
+		t.Errorf("Changelog(since first) = %s, %v", gotHead, entries)
+	}
+	if _, entries, _ := Changelog(context.Background(), dir, "0123456789abcdef0123456789abcdef01234567"); len(entries) != 3 {
+		t.Errorf("Changelog(unknown commit) = %v, want the whole history", entries)
+	}
+	if _, _, err := Changelog(context.Background(), t.TempDir(), ""); err == nil {
+		t.Error("Changelog outside a git repository succeeded")
+	}
+}
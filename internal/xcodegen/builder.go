@@ -0,0 +1,396 @@
+package xcodegen
+
+import (
+	"fmt"
+	"strings"
+)
+
+// specBuilder captures common XcodeGen spec construction shared across all
+// platform generators (iOS, tvOS, macOS, visionOS, watchOS).
+type specBuilder struct {
+	s *Spec
+	p *Project
+}
+
+func newSpecBuilder(p *Project) *specBuilder {
+	return &specBuilder{s: &Spec{Name: p.AppName}, p: p}
+}
+
+// writePackages fills the top-level packages section.
+func (t *specBuilder) writePackages() {
+	for _, pkg := range t.p.Packages {
+		t.s.Packages.Set(pkg.Name, swiftPackage(pkg))
+	}
+}
+
+// swiftPackage returns the packages: entry for pkg, using from: unless the
+// package pins an exact version or a version range.
+func swiftPackage(pkg Package) *SwiftPackage {
+	switch {
+	case pkg.Path != "":
+		return &SwiftPackage{Path: pkg.Path}
+	case pkg.ExactVersion != "":
+		return &SwiftPackage{URL: pkg.URL, Extra: map[string]any{"exactVersion": pkg.ExactVersion}}
+	case pkg.MaxVersion != "":
+		return &SwiftPackage{URL: pkg.URL, Extra: map[string]any{"minVersion": pkg.MinVersion, "maxVersion": pkg.MaxVersion}}
+	default:
+		return &SwiftPackage{URL: pkg.URL, From: pkg.MinVersion}
+	}
+}
+
+// writeOptions fills the options section with a deployment target per platform.
+func (t *specBuilder) writeOptions(platforms ...string) {
+	t.s.Options = Options{
+		BundleIDPrefix:              t.p.bundleIDPrefix(),
+		XcodeVersion:                "16.0",
+		CreateIntermediateGroups:    true,
+		GenerateEmptyDirectories:    true,
+		UseBaseInternationalization: boolPtr(false),
+		KnownRegions:                t.p.Localizations,
+	}
+	for _, platform := range platforms {
+		t.s.Options.DeploymentTarget.Set(xcodegenPlatform(platform), deploymentTarget)
+	}
+}
+
+// addTarget appends a target to the spec.
+func (t *specBuilder) addTarget(name string, target *Target) {
+	t.s.Targets.Set(name, target)
+}
+
+// newAppTarget returns a native (non-iOS) application target with its supported destination.
+func newAppTarget(platform string) *Target {
+	return &Target{
+		Type:                  "application",
+		Platform:              xcodegenPlatform(platform),
+		SupportedDestinations: []string{xcodegenPlatform(platform)},
+	}
+}
+
+// newIOSAppTarget returns the iOS application target, constraining Xcode
+// "Supported Destinations". supportedDestinations removes Mac/Vision "Designed
+// for iPad" defaults, while destinationFilters narrows iOS devices (iPhone/iPad)
+// based on the planned family.
+func (t *specBuilder) newIOSAppTarget() *Target {
+	target := newAppTarget(PlatformIOS)
+	switch t.p.DeviceFamily {
+	case "ipad":
+		target.DestinationFilters = []DestinationFilter{{Device: "iPad"}}
+	case "universal":
+		target.DestinationFilters = []DestinationFilter{{Device: "iPhone"}, {Device: "iPad"}}
+	default: // "iphone"
+		target.DestinationFilters = []DestinationFilter{{Device: "iPhone"}}
+	}
+	return target
+}
+
+// syncedSource returns a synced-folder source entry.
+func syncedSource(path string, excludes ...string) Source {
+	return Source{Path: path, Type: "syncedFolder", Excludes: excludes}
+}
+
+// sharedSource returns the optional Shared/ source entry used by apps with extensions.
+func sharedSource() Source {
+	return Source{Path: "Shared", Type: "syncedFolder", Optional: true}
+}
+
+// watchSource returns the source entry of watch targets, which compile nothing
+// from the folder but bundle its resources.
+func watchSource(path string) Source {
+	return syncedSource(path, "**/*.swift", "*.plist", "*.entitlements")
+}
+
+// setIOSAppSettings writes the scene, launch screen and device family settings of iOS apps.
+func (t *specBuilder) setIOSAppSettings(settings *Settings) {
+	base := &settings.Base
+	base.Set("INFOPLIST_KEY_UIApplicationSceneManifest_Generation", "YES")
+	base.Set("INFOPLIST_KEY_UIApplicationSupportsIndirectInputEvents", "YES")
+	base.Set("INFOPLIST_KEY_UILaunchScreen_Generation", "YES")
+	const iPadOrientations = "UIInterfaceOrientationPortrait UIInterfaceOrientationPortraitUpsideDown UIInterfaceOrientationLandscapeLeft UIInterfaceOrientationLandscapeRight"
+	switch t.p.DeviceFamily {
+	case "ipad":
+		base.Set("TARGETED_DEVICE_FAMILY", "2")
+		base.Set("INFOPLIST_KEY_UISupportedInterfaceOrientations_iPad", iPadOrientations)
+	case "universal":
+		base.Set("TARGETED_DEVICE_FAMILY", "1,2")
+		base.Set("INFOPLIST_KEY_UISupportedInterfaceOrientations_iPhone", "UIInterfaceOrientationPortrait")
+		base.Set("INFOPLIST_KEY_UISupportedInterfaceOrientations_iPad", iPadOrientations)
+	default: // "iphone"
+		base.Set("TARGETED_DEVICE_FAMILY", "1")
+		base.Set("INFOPLIST_KEY_UISupportedInterfaceOrientations_iPhone", "UIInterfaceOrientationPortrait")
+	}
+}
+
+// commonSettings returns the settings every application target starts with.
+func commonSettings(bundleID string) *Settings {
+	settings := &Settings{}
+	base := &settings.Base
+	base.Set("SWIFT_VERSION", "6.0")
+	base.Set("PRODUCT_BUNDLE_IDENTIFIER", bundleID)
+	base.Set("CODE_SIGN_STYLE", "Automatic")
+	base.Set("CURRENT_PROJECT_VERSION", 1)
+	base.Set("MARKETING_VERSION", "1.0")
+	base.Set("GENERATE_INFOPLIST_FILE", "YES")
+	return settings
+}
+
+// writeVersion sets the project's marketing version and build number on every
+// target that carries them, so the app and its embedded targets always match.
+func (t *specBuilder) writeVersion() {
+	if t.p.MarketingVersion == "" && t.p.BuildNumber == 0 {
+		return
+	}
+	for _, name := range t.s.Targets.Keys() {
+		target, _ := t.s.Targets.Get(name)
+		if target.Settings == nil {
+			continue
+		}
+		base := &target.Settings.Base
+		if _, ok := base.Get("MARKETING_VERSION"); ok && t.p.MarketingVersion != "" {
+			base.Set("MARKETING_VERSION", t.p.MarketingVersion)
+		}
+		if _, ok := base.Get("CURRENT_PROJECT_VERSION"); ok && t.p.BuildNumber > 0 {
+			base.Set("CURRENT_PROJECT_VERSION", t.p.BuildNumber)
+		}
+	}
+}
+
+// setAssetSettings writes the app icon, accent color and preview settings.
+func setAssetSettings(settings *Settings) {
+	base := &settings.Base
+	base.Set("ASSETCATALOG_COMPILER_APPICON_NAME", "AppIcon")
+	base.Set("INFOPLIST_KEY_CFBundleIconName", "AppIcon")
+	base.Set("ASSETCATALOG_COMPILER_GLOBAL_ACCENT_COLOR_NAME", "AccentColor")
+	base.Set("ENABLE_PREVIEWS", "YES")
+	base.Set("SWIFT_EMIT_LOC_STRINGS", "YES")
+}
+
+// setConcurrencySettings writes the Swift concurrency defaults every target uses.
+func setConcurrencySettings(settings *Settings) {
+	settings.Base.Set("SWIFT_APPROACHABLE_CONCURRENCY", "YES")
+	settings.Base.Set("SWIFT_DEFAULT_ACTOR_ISOLATION", "MainActor")
+}
+
+// setCommonPostSettings writes build settings that appear after platform-specific ones.
+func setCommonPostSettings(settings *Settings) {
+	setAssetSettings(settings)
+	settings.Base.Set("LD_RUNPATH_SEARCH_PATHS", []string{"$(inherited)", "@executable_path/Frameworks"})
+	setConcurrencySettings(settings)
+}
+
+// setMacOSSettings writes the macOS settings that replace the common post settings.
+func setMacOSSettings(settings *Settings) {
+	setAssetSettings(settings)
+	settings.Base.Set("COMBINE_HIDPI_IMAGES", "YES")
+	settings.Base.Set("LD_RUNPATH_SEARCH_PATHS", []string{"$(inherited)", "@executable_path/../Frameworks"})
+	setConcurrencySettings(settings)
+}
+
+// setWatchOSSettings writes watchOS-specific build settings.
+func setWatchOSSettings(settings *Settings) {
+	setAssetSettings(settings)
+	setConcurrencySettings(settings)
+}
+
+// setAppearance locks the interface style on iOS and tvOS. macOS follows the
+// system appearance and visionOS has no light/dark concept, so neither is locked.
+func (t *specBuilder) setAppearance(settings *Settings, platform string) {
+	if t.p.InterfaceStyle == "" {
+		return
+	}
+	if platform != PlatformIOS && platform != PlatformTvOS {
+		return
+	}
+	settings.Base.Set("INFOPLIST_KEY_UIUserInterfaceStyle", t.p.InterfaceStyle)
+}
+
+// setPermissions writes permission build settings followed by extra main-target settings.
+func (t *specBuilder) setPermissions(settings *Settings) {
+	for _, perm := range t.p.Permissions {
+		settings.Base.Set("INFOPLIST_KEY_"+perm.Key, perm.Description)
+	}
+	setSettingsMap(settings, t.p.BuildSettings)
+}
+
+// setSettingsMap writes string build settings in key order.
+func setSettingsMap(settings *Settings, values map[string]string) {
+	for _, k := range sortedKeys(values) {
+		settings.Base.Set(k, values[k])
+	}
+}
+
+// entitlementsPlist returns the entitlements section for a target.
+func entitlementsPlist(sourceDir, targetName string, entitlements map[string]any) *Plist {
+	return &Plist{
+		Path:       fmt.Sprintf("%s/%s.entitlements", sourceDir, targetName),
+		Properties: entitlements,
+	}
+}
+
+// packageDependencies returns the package dependency entries of a target.
+func packageDependencies(packages []Package) []Dependency {
+	var deps []Dependency
+	for _, pkg := range packages {
+		if len(pkg.Products) == 0 || (len(pkg.Products) == 1 && pkg.Products[0] == pkg.Name) {
+			// Single product matching repo name — simple form
+			deps = append(deps, Dependency{Package: pkg.Name})
+			continue
+		}
+		// Explicit product name(s)
+		for _, product := range pkg.Products {
+			deps = append(deps, Dependency{Package: pkg.Name, Product: product})
+		}
+	}
+	return deps
+}
+
+// embedDependency returns an embedded target dependency.
+func embedDependency(target string) Dependency {
+	return Dependency{Target: target, Embed: boolPtr(true)}
+}
+
+// embedDependencies returns embedded target dependencies for the given extensions.
+func embedDependencies(appName string, extensions []Extension) []Dependency {
+	var deps []Dependency
+	for _, ext := range extensions {
+		deps = append(deps, embedDependency(ext.TargetName(appName)))
+	}
+	return deps
+}
+
+// dependencies returns the dependencies of an application target (packages + embedded extensions).
+func (t *specBuilder) dependencies(extensions []Extension) []Dependency {
+	return append(packageDependencies(t.p.Packages), embedDependencies(t.p.AppName, extensions)...)
+}
+
+// writeExtensionTargets adds extension targets. Bundle IDs and app-group
+// entitlements derive from parentBundleID.
+func (t *specBuilder) writeExtensionTargets(extensions []Extension, parentBundleID string) {
+	for _, ext := range extensions {
+		platform := ext.Platform
+		if platform == "" && t.p.isMultiPlatform() {
+			platform = PlatformIOS
+		} else if platform == "" {
+			platform = t.p.platform()
+		}
+		t.addTarget(ext.TargetName(t.p.AppName), extensionTarget(t.p.AppName, ext, xcodegenPlatform(platform), parentBundleID))
+	}
+}
+
+// extensionTarget returns the target of an extension placed on the given
+// XcodeGen platform, embedded in the app whose bundle ID is parentBundleID.
+func extensionTarget(appName string, ext Extension, platform, parentBundleID string) *Target {
+	name := ext.TargetName(appName)
+	kindForBundleID := strings.ReplaceAll(ext.Kind, "_", "")
+	if kindForBundleID == "" {
+		kindForBundleID = strings.ToLower(name)
+	}
+	sourcePath := "Targets/" + name
+
+	settings := &Settings{}
+	base := &settings.Base
+	base.Set("PRODUCT_BUNDLE_IDENTIFIER", parentBundleID+"."+kindForBundleID)
+	base.Set("CODE_SIGN_STYLE", "Automatic")
+	base.Set("SWIFT_VERSION", "6.0")
+	base.Set("GENERATE_INFOPLIST_FILE", "YES")
+	base.Set("SKIP_INSTALL", "YES")
+	base.Set("DEAD_CODE_STRIPPING", "NO")
+	base.Set("CURRENT_PROJECT_VERSION", 1)
+	base.Set("MARKETING_VERSION", "1.0")
+	setConcurrencySettings(settings)
+	setSettingsMap(settings, ext.Settings)
+
+	target := &Target{
+		Type:     targetType(ext.Kind),
+		Platform: platform,
+		Sources:  []Source{syncedSource(sourcePath), sharedSource()},
+		Settings: settings,
+	}
+	if infoPlist := mergeInfoPlistDefaults(ext.Kind, ext.InfoPlist); len(infoPlist) > 0 {
+		target.Info = &Plist{Path: sourcePath + "/Info.plist", Properties: infoPlist}
+	}
+	if entitlements := mergeEntitlementDefaults(ext.Kind, ext.Entitlements, parentBundleID); len(entitlements) > 0 {
+		target.Entitlements = entitlementsPlist(sourcePath, name, entitlements)
+	}
+	return target
+}
+
+// writeWatchAppTarget adds the watchapp2 target. sourceDir holds its resources
+// and info.plist; extra Info.plist properties describe how it pairs with iOS.
+func (t *specBuilder) writeWatchAppTarget(targetName, sourceDir, infoFile, bundleID string, info map[string]any) {
+	settings := commonSettings(bundleID)
+	setWatchOSSettings(settings)
+	t.addTarget(targetName, &Target{
+		Type:         "application.watchapp2",
+		Platform:     "watchOS",
+		Sources:      []Source{watchSource(sourceDir)},
+		Settings:     settings,
+		Entitlements: entitlementsPlist(sourceDir, targetName, nil),
+		Info:         &Plist{Path: sourceDir + "/" + infoFile, Properties: info},
+		Dependencies: []Dependency{embedDependency(WatchExtensionTargetName(t.p.AppName))},
+	})
+}
+
+// writeIntrinsicWatchExtensionTarget adds the watchkit2-extension target every watch app embeds.
+func (t *specBuilder) writeIntrinsicWatchExtensionTarget(targetName, sourcePath, extBundleID, watchAppBundleID string, includeShared bool) {
+	sources := []Source{syncedSource(sourcePath, "*.plist", "*.entitlements")}
+	if includeShared {
+		sources = append(sources, sharedSource())
+	}
+
+	settings := &Settings{}
+	base := &settings.Base
+	base.Set("PRODUCT_BUNDLE_IDENTIFIER", extBundleID)
+	base.Set("CODE_SIGN_STYLE", "Automatic")
+	base.Set("SWIFT_VERSION", "6.0")
+	base.Set("GENERATE_INFOPLIST_FILE", "YES")
+	base.Set("SKIP_INSTALL", "YES")
+	base.Set("CURRENT_PROJECT_VERSION", 1)
+	base.Set("MARKETING_VERSION", "1.0")
+	setConcurrencySettings(settings)
+
+	t.addTarget(targetName, &Target{
+		Type:         "watchkit2-extension",
+		Platform:     "watchOS",
+		Sources:      sources,
+		Settings:     settings,
+		Entitlements: entitlementsPlist(sourcePath, targetName, nil),
+		Info: &Plist{
+			Path: sourcePath + "/WatchExtension-Info.plist",
+			Properties: map[string]any{
+				"NSExtension": map[string]any{
+					"NSExtensionPointIdentifier": "com.apple.watchkit",
+					"NSExtensionAttributes": map[string]any{
+						"WKAppBundleIdentifier": watchAppBundleID,
+					},
+				},
+			},
+		},
+	})
+}
+
+// writeScheme adds a scheme that builds target plus the listed targets and runs target.
+func (t *specBuilder) writeScheme(target string, buildTargets []string, storeKit bool) {
+	scheme := &Scheme{Run: &SchemeRun{Executable: target}}
+	scheme.Build.Targets.Set(target, "all")
+	for _, name := range buildTargets {
+		scheme.Build.Targets.Set(name, "all")
+	}
+	if storeKit {
+		scheme.Run.StoreKitConfiguration = t.p.StoreKitConfiguration
+	}
+	t.s.Schemes.Set(target, scheme)
+}
+
+// targetNames returns the target names of the given extensions.
+func (t *specBuilder) targetNames(extensions []Extension) []string {
+	var names []string
+	for _, ext := range extensions {
+		names = append(names, ext.TargetName(t.p.AppName))
+	}
+	return names
+}
+
+func boolPtr(b bool) *bool {
+	return &b
+}
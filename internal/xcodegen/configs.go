@@ -0,0 +1,261 @@
+package xcodegen
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// BuildConfiguration is an Xcode build configuration backed by an .xcconfig
+// file. Debug and Release always exist; listing them here only sets their values.
+type BuildConfiguration struct {
+	Name string
+	// Type is the XcodeGen config type, "debug" or "release". Empty means release
+	// for added configurations.
+	Type string
+	// BundleIDSuffix is appended to every bundle ID, e.g. ".staging". Empty
+	// defaults to the lowercased name for added configurations.
+	BundleIDSuffix string
+	// DisplayName is the home screen name. Empty defaults to "<App> <Name>" for
+	// added configurations and the app name for Debug and Release.
+	DisplayName string
+	// AppIcon is the app icon set. Empty means AppIcon.
+	AppIcon string
+	// BackendURL is exposed to the app as the APIBaseURL Info.plist key.
+	BackendURL string
+	// Values are extra xcconfig variables, e.g. ANALYTICS_KEY.
+	Values map[string]string
+}
+
+// configFileDir holds the generated .xcconfig files, relative to the project.
+const configFileDir = "Configs"
+
+// Build setting variables defined by every generated .xcconfig.
+const (
+	bundleIDSuffixVar = "BUNDLE_ID_SUFFIX"
+	displayNameVar    = "APP_DISPLAY_NAME"
+	appIconVar        = "APP_ICON_NAME"
+	backendURLVar     = "API_BASE_URL"
+)
+
+// BackendURLInfoKey is the Info.plist key the backend URL of the active configuration is read from.
+const BackendURLInfoKey = "APIBaseURL"
+
+// IsDefaultConfiguration reports whether name is one of XcodeGen's default configurations.
+func IsDefaultConfiguration(name string) bool {
+	return name == "Debug" || name == "Release"
+}
+
+// configurations returns every build configuration with defaults filled in:
+// Debug, the added configurations in order, then Release.
+func (p *Project) configurations() []BuildConfiguration {
+	configs := []BuildConfiguration{{Name: "Debug", Type: "debug"}}
+	release := BuildConfiguration{Name: "Release", Type: "release"}
+	for _, c := range p.Configurations {
+		switch c.Name {
+		case "Debug":
+			c.Type = "debug"
+			configs[0] = c
+			continue
+		case "Release":
+			c.Type = "release"
+			release = c
+			continue
+		}
+		if c.Type == "" {
+			c.Type = "release"
+		}
+		if c.BundleIDSuffix == "" {
+			c.BundleIDSuffix = "." + strings.ToLower(c.Name)
+		}
+		if c.DisplayName == "" {
+			c.DisplayName = p.AppName + " " + c.Name
+		}
+		configs = append(configs, c)
+	}
+	configs = append(configs, release)
+	for i := range configs {
+		if configs[i].DisplayName == "" {
+			configs[i].DisplayName = p.AppName
+		}
+		if configs[i].AppIcon == "" {
+			configs[i].AppIcon = "AppIcon"
+		}
+	}
+	return configs
+}
+
+// hasBackendURL reports whether any configuration sets a backend URL.
+func (p *Project) hasBackendURL() bool {
+	for _, c := range p.Configurations {
+		if c.BackendURL != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// configFilePath returns the project-relative path of a configuration's .xcconfig.
+func configFilePath(name string) string {
+	return configFileDir + "/" + name + ".xcconfig"
+}
+
+// writeConfigurations declares the build configurations, points each at its
+// .xcconfig and routes bundle IDs, display name, icon and backend URL through
+// the xcconfig variables. It adds a scheme per added configuration. Projects
+// without configurations are left untouched.
+func (t *specBuilder) writeConfigurations() {
+	if len(t.p.Configurations) == 0 {
+		return
+	}
+	configs := t.p.configurations()
+	for _, c := range configs {
+		t.s.Configs.Set(c.Name, c.Type)
+		t.s.ConfigFiles.Set(c.Name, configFilePath(c.Name))
+	}
+
+	bundleID := t.p.BundleID
+	suffixed := bundleID + "$(" + bundleIDSuffixVar + ")"
+	for _, name := range t.s.Targets.Keys() {
+		target, _ := t.s.Targets.Get(name)
+		if target.Settings != nil {
+			if v, ok := target.Settings.Base.Get("PRODUCT_BUNDLE_IDENTIFIER"); ok {
+				target.Settings.Base.Set("PRODUCT_BUNDLE_IDENTIFIER", strings.Replace(fmt.Sprint(v), bundleID, suffixed, 1))
+			}
+		}
+		for _, plist := range []*Plist{target.Info, target.Entitlements} {
+			if plist != nil {
+				plist.Properties = suffixBundleIDs(plist.Properties, bundleID, suffixed).(map[string]any)
+			}
+		}
+
+		if !strings.HasPrefix(target.Type, "application") || target.Settings == nil {
+			continue
+		}
+		base := &target.Settings.Base
+		base.Set("INFOPLIST_KEY_CFBundleDisplayName", "$("+displayNameVar+")")
+		if _, ok := base.Get("ASSETCATALOG_COMPILER_APPICON_NAME"); ok {
+			base.Set("ASSETCATALOG_COMPILER_APPICON_NAME", "$("+appIconVar+")")
+			base.Set("INFOPLIST_KEY_CFBundleIconName", "$("+appIconVar+")")
+		}
+	}
+
+	mainName := t.s.MainTargetName()
+	main, _ := t.s.Targets.Get(mainName)
+	if t.p.hasBackendURL() && main != nil {
+		if main.Info == nil {
+			main.Info = &Plist{Path: t.p.AppName + "/Info.plist", Properties: map[string]any{}}
+		}
+		main.Info.Properties[BackendURLInfoKey] = "$(" + backendURLVar + ")"
+	}
+
+	// One scheme per added configuration, building what the main scheme builds.
+	mainScheme, ok := t.s.Schemes.Get(mainName)
+	if !ok {
+		mainScheme = &Scheme{Run: &SchemeRun{Executable: mainName}}
+		mainScheme.Build.Targets.Set(mainName, "all")
+		t.s.Schemes.Set(mainName, mainScheme)
+	}
+	for _, c := range configs {
+		if IsDefaultConfiguration(c.Name) {
+			continue
+		}
+		scheme := configurationScheme(mainName, c.Name, mainScheme)
+		t.s.Schemes.Set(mainName+" "+c.Name, scheme)
+	}
+}
+
+// configurationScheme returns a scheme whose actions all use config, building
+// the same targets as the main scheme (or just the main target when nil).
+func configurationScheme(mainName, config string, main *Scheme) *Scheme {
+	scheme := &Scheme{
+		Run:     &SchemeRun{Executable: mainName, Config: config},
+		Test:    &SchemeAction{Config: config},
+		Profile: &SchemeAction{Config: config},
+		Analyze: &SchemeAction{Config: config},
+		Archive: &SchemeAction{Config: config},
+	}
+	if main == nil {
+		scheme.Build.Targets.Set(mainName, "all")
+		return scheme
+	}
+	for _, target := range main.Build.Targets.Keys() {
+		v, _ := main.Build.Targets.Get(target)
+		scheme.Build.Targets.Set(target, v)
+	}
+	if main.Run != nil {
+		scheme.Run.StoreKitConfiguration = main.Run.StoreKitConfiguration
+	}
+	if main.Test != nil {
+		scheme.Test.GatherCoverageData = main.Test.GatherCoverageData
+		scheme.Test.Targets = main.Test.Targets
+	}
+	return scheme
+}
+
+// suffixBundleIDs inserts the bundle ID suffix variable after every occurrence
+// of bundleID in plist string values (companion IDs, app groups, ...).
+func suffixBundleIDs(v any, bundleID, suffixed string) any {
+	switch v := v.(type) {
+	case string:
+		return strings.ReplaceAll(v, bundleID, suffixed)
+	case []any:
+		out := make([]any, len(v))
+		for i, item := range v {
+			out[i] = suffixBundleIDs(item, bundleID, suffixed)
+		}
+		return out
+	case map[string]any:
+		out := make(map[string]any, len(v))
+		for k, item := range v {
+			out[k] = suffixBundleIDs(item, bundleID, suffixed)
+		}
+		return out
+	}
+	return v
+}
+
+// ConfigFiles returns the .xcconfig files of the project's build
+// configurations, keyed by project-relative path. It is empty when the project
+// only uses the default Debug and Release configurations.
+func ConfigFiles(p *Project) map[string]string {
+	if len(p.Configurations) == 0 {
+		return nil
+	}
+	files := make(map[string]string)
+	for _, c := range p.configurations() {
+		var b strings.Builder
+		fmt.Fprintf(&b, "// %s.xcconfig — %s configuration of %s, generated by nanowave.\n", c.Name, c.Name, p.AppName)
+		b.WriteString("// Change values with the xcodegen MCP tools; edits here are overwritten.\n\n")
+		fmt.Fprintf(&b, "%s = %s\n", bundleIDSuffixVar, c.BundleIDSuffix)
+		fmt.Fprintf(&b, "%s = %s\n", displayNameVar, c.DisplayName)
+		fmt.Fprintf(&b, "%s = %s\n", appIconVar, c.AppIcon)
+		fmt.Fprintf(&b, "%s = %s\n", backendURLVar, xcconfigValue(c.BackendURL))
+		for _, k := range sortedKeys(c.Values) {
+			fmt.Fprintf(&b, "%s = %s\n", k, xcconfigValue(c.Values[k]))
+		}
+		files[configFilePath(c.Name)] = b.String()
+	}
+	return files
+}
+
+// WriteConfigFiles writes the project's .xcconfig files under dir.
+func WriteConfigFiles(dir string, p *Project) error {
+	for rel, content := range ConfigFiles(p) {
+		path := filepath.Join(dir, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return fmt.Errorf("failed to create %s: %w", filepath.Dir(path), err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", rel, err)
+		}
+	}
+	return nil
+}
+
+// xcconfigValue escapes "//", which would otherwise start a comment, so URLs
+// survive: https://host becomes https:/$()/host.
+func xcconfigValue(v string) string {
+	return strings.ReplaceAll(v, "//", "/$()/")
+}
@@ -0,0 +1,492 @@
+package xcodegen
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// MainTargetName returns the app target of the spec: the target named after the
+// project, or else the first application target.
+func (s *Spec) MainTargetName() string {
+	if _, ok := s.Targets.Get(s.Name); ok {
+		return s.Name
+	}
+	for _, name := range s.Targets.Keys() {
+		target, _ := s.Targets.Get(name)
+		if strings.HasPrefix(target.Type, "application") {
+			return name
+		}
+	}
+	return ""
+}
+
+// Target returns the named target. An empty name selects the main app target.
+func (s *Spec) Target(name string) (string, *Target, error) {
+	if name == "" {
+		name = s.MainTargetName()
+	}
+	target, ok := s.Targets.Get(name)
+	if !ok || target == nil {
+		if name == "" {
+			return "", nil, fmt.Errorf("project.yml has no application target")
+		}
+		return "", nil, fmt.Errorf("target %s not found in project.yml", name)
+	}
+	return name, target, nil
+}
+
+// SetBuildSetting sets a base build setting on a target (empty = main app).
+func (s *Spec) SetBuildSetting(targetName, key string, value any) error {
+	_, target, err := s.Target(targetName)
+	if err != nil {
+		return err
+	}
+	if target.Settings == nil {
+		target.Settings = &Settings{}
+	}
+	target.Settings.Base.Set(key, value)
+	return nil
+}
+
+// AddPermission adds an Info.plist usage description to the main app target.
+func (s *Spec) AddPermission(key, description string) error {
+	return s.SetBuildSetting("", "INFOPLIST_KEY_"+key, description)
+}
+
+// SetEntitlement sets an entitlement on a target (empty = main app), creating
+// the target's entitlements file next to its first source folder if needed.
+func (s *Spec) SetEntitlement(targetName, key string, value any) error {
+	name, target, err := s.Target(targetName)
+	if err != nil {
+		return err
+	}
+	if target.Entitlements == nil {
+		dir := name
+		if len(target.Sources) > 0 {
+			dir = target.Sources[0].Path
+		}
+		target.Entitlements = entitlementsPlist(dir, name, nil)
+	}
+	if target.Entitlements.Properties == nil {
+		target.Entitlements.Properties = make(map[string]any)
+	}
+	target.Entitlements.Properties[key] = value
+	return nil
+}
+
+// AddURLScheme registers a custom URL scheme in the main app's Info.plist
+// CFBundleURLTypes, creating the info plist next to its first source folder if
+// needed. It reports false when the scheme was already registered.
+func (s *Spec) AddURLScheme(scheme string) (bool, error) {
+	name, target, err := s.Target("")
+	if err != nil {
+		return false, err
+	}
+	info := infoPlist(name, target)
+	types, _ := info.Properties["CFBundleURLTypes"].([]any)
+	for _, t := range types {
+		entry, _ := t.(map[string]any)
+		schemes, _ := entry["CFBundleURLSchemes"].([]any)
+		if slices.Contains(schemes, any(scheme)) {
+			return false, nil
+		}
+	}
+	if len(types) > 0 {
+		if entry, ok := types[0].(map[string]any); ok {
+			schemes, _ := entry["CFBundleURLSchemes"].([]any)
+			entry["CFBundleURLSchemes"] = append(schemes, scheme)
+			return true, nil
+		}
+	}
+	urlName := name
+	if target.Settings != nil {
+		if id, ok := target.Settings.Base.Get("PRODUCT_BUNDLE_IDENTIFIER"); ok {
+			urlName = fmt.Sprint(id)
+		}
+	}
+	info.Properties["CFBundleURLTypes"] = append(types, urlType(urlName, []string{scheme}))
+	return true, nil
+}
+
+// infoPlist returns the target's info plist, creating it next to its first
+// source folder if needed.
+func infoPlist(name string, target *Target) *Plist {
+	if target.Info == nil {
+		dir := name
+		if len(target.Sources) > 0 {
+			dir = target.Sources[0].Path
+		}
+		target.Info = &Plist{Path: dir + "/Info.plist"}
+	}
+	if target.Info.Properties == nil {
+		target.Info.Properties = make(map[string]any)
+	}
+	return target.Info
+}
+
+// AddKnownRegions appends languages missing from options.knownRegions.
+func (s *Spec) AddKnownRegions(languages ...string) {
+	for _, lang := range languages {
+		if !slices.Contains(s.Options.KnownRegions, lang) {
+			s.Options.KnownRegions = append(s.Options.KnownRegions, lang)
+		}
+	}
+}
+
+// AddPackage adds a Swift package and links its products into the main app
+// target. It reports false when the package is already declared.
+func (s *Spec) AddPackage(pkg Package) (bool, error) {
+	if _, ok := s.Packages.Get(pkg.Name); ok {
+		return false, nil
+	}
+	_, target, err := s.Target("")
+	if err != nil {
+		return false, err
+	}
+	s.Packages.Set(pkg.Name, swiftPackage(pkg))
+	target.Dependencies = append(packageDependencies([]Package{pkg}), target.Dependencies...)
+	return true, nil
+}
+
+// AddExtension adds an extension target embedded in the main app. The bundle
+// ID, platform and app group derive from the main target, and the extension
+// joins the main target's scheme when there is one. It returns the target name.
+func (s *Spec) AddExtension(ext Extension) (string, error) {
+	mainName, main, err := s.Target("")
+	if err != nil {
+		return "", err
+	}
+	name := ext.TargetName(s.Name)
+	if _, ok := s.Targets.Get(name); ok {
+		return "", fmt.Errorf("target %s already exists", name)
+	}
+
+	var parentBundleID string
+	if main.Settings != nil {
+		if v, ok := main.Settings.Base.Get("PRODUCT_BUNDLE_IDENTIFIER"); ok {
+			parentBundleID = fmt.Sprint(v)
+		}
+	}
+	if parentBundleID == "" {
+		return "", fmt.Errorf("target %s has no PRODUCT_BUNDLE_IDENTIFIER", mainName)
+	}
+
+	s.Targets.Set(name, extensionTarget(s.Name, ext, main.Platform, parentBundleID))
+	main.Dependencies = append(main.Dependencies, embedDependency(name))
+	hasGroups := main.Entitlements != nil && main.Entitlements.Properties[appGroupsEntitlement] != nil
+	if needsAppGroups([]Extension{ext}) && !hasGroups {
+		if err := s.SetEntitlement(mainName, appGroupsEntitlement, []any{"group." + parentBundleID}); err != nil {
+			return "", err
+		}
+	}
+	if scheme, ok := s.Schemes.Get(mainName); ok && scheme != nil {
+		scheme.Build.Targets.Set(name, "all")
+	}
+	return name, nil
+}
+
+// RemovePermission removes an Info.plist usage description from the main app
+// target, whether set as an INFOPLIST_KEY_ build setting or an info property.
+// It reports false when the permission was not declared.
+func (s *Spec) RemovePermission(key string) (bool, error) {
+	_, target, err := s.Target("")
+	if err != nil {
+		return false, err
+	}
+	removed := false
+	if target.Settings != nil {
+		removed = target.Settings.Base.Delete("INFOPLIST_KEY_" + key)
+	}
+	if target.Info != nil {
+		if _, ok := target.Info.Properties[key]; ok {
+			delete(target.Info.Properties, key)
+			removed = true
+		}
+	}
+	return removed, nil
+}
+
+// RemoveExtension removes an extension target along with every dependency on
+// it and its scheme build entries. The main app target cannot be removed.
+func (s *Spec) RemoveExtension(name string) error {
+	if name == s.MainTargetName() {
+		return fmt.Errorf("%s is the main app target and cannot be removed", name)
+	}
+	target, ok := s.Targets.Get(name)
+	if !ok || target == nil {
+		return fmt.Errorf("target %s not found in project.yml", name)
+	}
+	if strings.HasPrefix(target.Type, "application") && target.Type != "application.on-demand-install-capable" {
+		return fmt.Errorf("target %s is an application, not an extension", name)
+	}
+	s.Targets.Delete(name)
+	for _, other := range s.Targets.Keys() {
+		t, _ := s.Targets.Get(other)
+		if t == nil {
+			continue
+		}
+		t.Dependencies = slices.DeleteFunc(t.Dependencies, func(d Dependency) bool { return d.Target == name })
+	}
+	for _, schemeName := range s.Schemes.Keys() {
+		if scheme, _ := s.Schemes.Get(schemeName); scheme != nil {
+			scheme.Build.Targets.Delete(name)
+		}
+	}
+	return nil
+}
+
+// RemoveEntitlement removes an entitlement from a target (empty = main app).
+// It reports false when the target did not declare it. An app group another
+// target shares data through, and the entitlements an App Clip requires,
+// cannot be removed.
+func (s *Spec) RemoveEntitlement(targetName, key string) (bool, error) {
+	name, target, err := s.Target(targetName)
+	if err != nil {
+		return false, err
+	}
+	if target.Entitlements == nil {
+		return false, nil
+	}
+	if _, ok := target.Entitlements.Properties[key]; !ok {
+		return false, nil
+	}
+	if target.Type == "application.on-demand-install-capable" && slices.Contains(DefaultEntitlementKeys("app_clip"), key) {
+		return false, fmt.Errorf("%s is required by every app_clip extension and cannot be removed from %s", key, name)
+	}
+	if key == appGroupsEntitlement {
+		if other := s.appGroupSharer(name); other != "" && name == s.MainTargetName() {
+			return false, fmt.Errorf("the app group is required by target %s, which shares data with the app; remove the extension first", other)
+		} else if other != "" {
+			return false, fmt.Errorf("%s shares data with %s through the app group; remove the extension instead", name, other)
+		}
+	}
+	delete(target.Entitlements.Properties, key)
+	return true, nil
+}
+
+// appGroupsEntitlement is the entitlement listing a target's app groups.
+const appGroupsEntitlement = "com.apple.security.application-groups"
+
+// appGroupSharer returns the first other target that declares one of the named
+// target's app groups, or "" when none does.
+func (s *Spec) appGroupSharer(name string) string {
+	groups := s.appGroups(name)
+	for _, other := range s.Targets.Keys() {
+		if other == name {
+			continue
+		}
+		if slices.ContainsFunc(s.appGroups(other), func(g string) bool { return slices.Contains(groups, g) }) {
+			return other
+		}
+	}
+	return ""
+}
+
+// appGroups returns the app groups a target's entitlements declare.
+func (s *Spec) appGroups(name string) []string {
+	target, _ := s.Targets.Get(name)
+	if target == nil || target.Entitlements == nil {
+		return nil
+	}
+	values, _ := target.Entitlements.Properties[appGroupsEntitlement].([]any)
+	var groups []string
+	for _, v := range values {
+		groups = append(groups, fmt.Sprint(v))
+	}
+	return groups
+}
+
+// RemovePackage removes a Swift package and every target dependency on its
+// products. It reports false when the package was not declared.
+func (s *Spec) RemovePackage(name string) bool {
+	if !s.Packages.Delete(name) {
+		return false
+	}
+	for _, targetName := range s.Targets.Keys() {
+		if t, _ := s.Targets.Get(targetName); t != nil {
+			t.Dependencies = slices.DeleteFunc(t.Dependencies, func(d Dependency) bool { return d.Package == name })
+		}
+	}
+	return true
+}
+
+// packageVersionKeys are the XcodeGen version requirement keys replaced by SetPackageVersion.
+var packageVersionKeys = []string{"version", "exactVersion", "majorVersion", "minorVersion", "minVersion", "maxVersion", "branch", "revision"}
+
+// SetPackageVersion pins a remote package to "from: version" (up to the next
+// major version), replacing any other version requirement.
+func (s *Spec) SetPackageVersion(name, version string) error {
+	pkg, ok := s.Packages.Get(name)
+	if !ok || pkg == nil {
+		return fmt.Errorf("package %s not found in project.yml", name)
+	}
+	if pkg.URL == "" {
+		return fmt.Errorf("package %s is a local package and has no version", name)
+	}
+	pkg.From = version
+	for _, key := range packageVersionKeys {
+		delete(pkg.Extra, key)
+	}
+	return nil
+}
+
+// RemoveKnownRegions removes languages from options.knownRegions.
+func (s *Spec) RemoveKnownRegions(languages ...string) {
+	s.Options.KnownRegions = slices.DeleteFunc(s.Options.KnownRegions, func(lang string) bool {
+		return slices.Contains(languages, lang)
+	})
+}
+
+// UnsetBuildSetting removes a build setting from a target (empty = main app),
+// both from base and from every configuration. It reports false when the
+// setting was not set.
+func (s *Spec) UnsetBuildSetting(targetName, key string) (bool, error) {
+	_, target, err := s.Target(targetName)
+	if err != nil {
+		return false, err
+	}
+	if target.Settings == nil {
+		return false, nil
+	}
+	removed := target.Settings.Base.Delete(key)
+	for name, conf := range target.Settings.Configs {
+		if conf.Delete(key) {
+			target.Settings.Configs[name] = conf
+			removed = true
+		}
+	}
+	return removed, nil
+}
+
+// AddConfiguration adds a build configuration of the given type ("debug" or
+// "release") to a project.yml, declaring XcodeGen's default Debug and Release
+// first when the spec has no configs section, and adds a "<Main> <Name>" scheme
+// that runs and archives with it.
+func (s *Spec) AddConfiguration(name, configType string) error {
+	if s.Configs.Len() == 0 {
+		s.Configs.Set("Debug", "debug")
+		s.Configs.Set("Release", "release")
+	}
+	if _, ok := s.Configs.Get(name); ok {
+		return fmt.Errorf("configuration %s already exists", name)
+	}
+	if configType == "" {
+		configType = "release"
+	}
+	s.Configs.Set(name, configType)
+
+	mainName, _, err := s.Target("")
+	if err != nil {
+		return err
+	}
+	main, _ := s.Schemes.Get(mainName)
+	scheme := configurationScheme(mainName, name, main)
+	s.Schemes.Set(mainName+" "+name, scheme)
+	return nil
+}
+
+// SetConfigSetting sets a build setting of a target (empty = main app) for one
+// build configuration only.
+func (s *Spec) SetConfigSetting(config, targetName, key string, value any) error {
+	_, known := s.Configs.Get(config)
+	if !known && !(s.Configs.Len() == 0 && IsDefaultConfiguration(config)) {
+		return fmt.Errorf("configuration %s not found in project.yml", config)
+	}
+	_, target, err := s.Target(targetName)
+	if err != nil {
+		return err
+	}
+	if target.Settings == nil {
+		target.Settings = &Settings{}
+	}
+	if target.Settings.Configs == nil {
+		target.Settings.Configs = make(map[string]OrderedMap[any])
+	}
+	settings := target.Settings.Configs[config]
+	settings.Set(key, value)
+	target.Settings.Configs[config] = settings
+	return nil
+}
+
+// SetConfigBundleIDSuffix appends suffix to the main app's bundle ID for one
+// build configuration. Every target whose bundle ID starts with the app's gets
+// the suffix too, so extensions keep the app's ID as their prefix.
+func (s *Spec) SetConfigBundleIDSuffix(config, suffix string) error {
+	_, main, err := s.Target("")
+	if err != nil {
+		return err
+	}
+	mainID := baseSetting(main, "PRODUCT_BUNDLE_IDENTIFIER")
+	if mainID == "" {
+		return fmt.Errorf("the main target has no PRODUCT_BUNDLE_IDENTIFIER to suffix")
+	}
+	for _, name := range s.Targets.Keys() {
+		target, _ := s.Targets.Get(name)
+		id := baseSetting(target, "PRODUCT_BUNDLE_IDENTIFIER")
+		if !strings.HasPrefix(id, mainID) {
+			continue
+		}
+		if err := s.SetConfigSetting(config, name, "PRODUCT_BUNDLE_IDENTIFIER", mainID+suffix+id[len(mainID):]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SetConfigBackendURL sets the backend URL of one build configuration and
+// exposes it to the main app as the BackendURLInfoKey Info.plist key.
+func (s *Spec) SetConfigBackendURL(config, url string) error {
+	if err := s.SetConfigSetting(config, "", backendURLVar, url); err != nil {
+		return err
+	}
+	name, target, err := s.Target("")
+	if err != nil {
+		return err
+	}
+	infoPlist(name, target).Properties[BackendURLInfoKey] = "$(" + backendURLVar + ")"
+	return nil
+}
+
+// baseSetting returns a base build setting of a target as text, or "" when unset.
+func baseSetting(target *Target, key string) string {
+	if target == nil || target.Settings == nil {
+		return ""
+	}
+	v, ok := target.Settings.Base.Get(key)
+	if !ok || v == nil {
+		return ""
+	}
+	return fmt.Sprint(v)
+}
+
+// ReplaceSetting sets key to value wherever project.yml already sets it: in the
+// project's and every target's base and per-configuration settings. It returns
+// how many places were updated. Values that come from .xcconfig files are not
+// seen.
+func (s *Spec) ReplaceSetting(key string, value any) int {
+	all := []*Settings{s.Settings}
+	for _, name := range s.Targets.Keys() {
+		if target, _ := s.Targets.Get(name); target != nil {
+			all = append(all, target.Settings)
+		}
+	}
+	updated := 0
+	for _, settings := range all {
+		if settings == nil {
+			continue
+		}
+		if _, ok := settings.Base.Get(key); ok {
+			settings.Base.Set(key, value)
+			updated++
+		}
+		for config, values := range settings.Configs {
+			if _, ok := values.Get(key); ok {
+				values.Set(key, value)
+				settings.Configs[config] = values
+				updated++
+			}
+		}
+	}
+	return updated
+}
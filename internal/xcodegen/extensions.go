@@ -0,0 +1,127 @@
+package xcodegen
+
+// targetType maps an extension kind to the XcodeGen target type.
+func targetType(kind string) string {
+	switch kind {
+	case "app_clip":
+		return "app-clip"
+	case "app_intents":
+		return "extensionkit-extension"
+	}
+	return "app-extension"
+}
+
+// needsAppGroups reports whether any extension shares data with the main app.
+func needsAppGroups(extensions []Extension) bool {
+	for _, ext := range extensions {
+		if SharesAppGroup(ext.Kind) {
+			return true
+		}
+	}
+	return false
+}
+
+// SharesAppGroup reports whether an extension kind shares data with the main
+// app through an app group, which the main app's entitlements then require.
+func SharesAppGroup(kind string) bool {
+	switch kind {
+	case "widget", "live_activity", "share", "app_intents", "control_widget", "spotlight":
+		return true
+	}
+	return false
+}
+
+// DefaultEntitlementKeys returns the entitlements every extension of kind gets.
+func DefaultEntitlementKeys(kind string) []string {
+	return sortedKeys(mergeEntitlementDefaults(kind, nil, ""))
+}
+
+// mergeInfoPlistDefaults fills in known-required Info.plist keys per extension kind.
+// Plan-specified values take priority over defaults.
+func mergeInfoPlistDefaults(kind string, planValues map[string]any) map[string]any {
+	m := make(map[string]any)
+
+	switch kind {
+	case "widget":
+		m["NSExtension"] = map[string]any{
+			"NSExtensionPointIdentifier": "com.apple.widgetkit-extension",
+		}
+	case "live_activity":
+		m["NSExtension"] = map[string]any{
+			"NSExtensionPointIdentifier": "com.apple.widgetkit-extension",
+		}
+	case "control_widget":
+		m["NSExtension"] = map[string]any{
+			"NSExtensionPointIdentifier": "com.apple.widgetkit-extension",
+		}
+	case "app_intents":
+		m["EXAppExtensionAttributes"] = map[string]any{
+			"EXExtensionPointIdentifier": "com.apple.appintents-extension",
+		}
+	case "spotlight":
+		m["NSExtension"] = map[string]any{
+			"NSExtensionPointIdentifier": "com.apple.spotlight.index",
+			"NSExtensionPrincipalClass":  "$(PRODUCT_MODULE_NAME).IndexRequestHandler",
+		}
+	case "share":
+		m["NSExtension"] = map[string]any{
+			"NSExtensionPointIdentifier": "com.apple.share-services",
+			"NSExtensionPrincipalClass":  "$(PRODUCT_MODULE_NAME).ShareViewController",
+			"NSExtensionAttributes": map[string]any{
+				"NSExtensionActivationSupportsWebURLWithMaxCount": 1,
+				"NSExtensionActivationSupportsText":               true,
+			},
+		}
+	case "notification_service":
+		m["NSExtension"] = map[string]any{
+			"NSExtensionPointIdentifier": "com.apple.usernotifications.service",
+			"NSExtensionPrincipalClass":  "$(PRODUCT_MODULE_NAME).NotificationService",
+		}
+	case "notification_content":
+		m["NSExtension"] = map[string]any{
+			"NSExtensionPointIdentifier": "com.apple.usernotifications.content-extension",
+			"NSExtensionPrincipalClass":  "$(PRODUCT_MODULE_NAME).NotificationViewController",
+			"NSExtensionAttributes": map[string]any{
+				"UNNotificationExtensionCategory":                "DEFAULT",
+				"UNNotificationExtensionInitialContentSizeRatio": 1,
+				"UNNotificationExtensionDefaultContentHidden":    false,
+			},
+		}
+	case "safari":
+		m["NSExtension"] = map[string]any{
+			"NSExtensionPointIdentifier": "com.apple.Safari.web-extension",
+			"NSExtensionPrincipalClass":  "$(PRODUCT_MODULE_NAME).SafariWebExtensionHandler",
+		}
+	case "app_clip":
+		m["NSAppClip"] = map[string]any{
+			"NSAppClipRequestEphemeralUserNotification": false,
+			"NSAppClipRequestLocationConfirmation":      false,
+		}
+	}
+
+	// Plan values override defaults
+	for k, v := range planValues {
+		m[k] = v
+	}
+
+	return m
+}
+
+// mergeEntitlementDefaults fills in known-required entitlements per extension kind.
+func mergeEntitlementDefaults(kind string, planValues map[string]any, mainBundleID string) map[string]any {
+	m := make(map[string]any)
+
+	switch kind {
+	case "widget", "live_activity", "share", "app_intents", "control_widget", "spotlight":
+		m["com.apple.security.application-groups"] = []any{"group." + mainBundleID}
+	case "app_clip":
+		m["com.apple.developer.parent-application-identifiers"] = []any{"$(AppIdentifierPrefix)" + mainBundleID}
+		m["com.apple.developer.associated-domains"] = []any{"appclips:" + mainBundleID}
+	}
+
+	for k, v := range planValues {
+		m[k] = v
+	}
+
+	return m
+}
@@ -0,0 +1,144 @@
+package xcodegen
+
+import (
+	"reflect"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+// textScalars returns a copy of node whose numeric scalars are tagged as
+// strings, so they decode as the text written rather than as numbers.
+func textScalars(node *yaml.Node) *yaml.Node {
+	copied := *node
+	switch node.Kind {
+	case yaml.ScalarNode:
+		if tag := node.ShortTag(); tag == "!!int" || tag == "!!float" {
+			copied.Tag = "!!str"
+		}
+	case yaml.MappingNode, yaml.SequenceNode, yaml.DocumentNode:
+		copied.Content = make([]*yaml.Node, len(node.Content))
+		for i, child := range node.Content {
+			copied.Content[i] = textScalars(child)
+		}
+	}
+	return &copied
+}
+
+// restoreFormatting carries the formatting of a parsed file over to node, its
+// re-encoded form: comments, mapping key order, flow or block style, and the
+// text of scalars whose value did not change.
+func restoreFormatting(node, original *yaml.Node) {
+	if node.HeadComment == "" {
+		node.HeadComment = original.HeadComment
+	}
+	if node.LineComment == "" {
+		node.LineComment = original.LineComment
+	}
+	if node.FootComment == "" {
+		node.FootComment = original.FootComment
+	}
+	if node.Kind != original.Kind {
+		return
+	}
+	switch node.Kind {
+	case yaml.ScalarNode:
+		if sameScalar(node, original) {
+			node.Value, node.Tag, node.Style = original.Value, original.Tag, original.Style
+		}
+	case yaml.MappingNode:
+		node.Style = original.Style
+		restoreMapping(node, original)
+	case yaml.SequenceNode:
+		node.Style = original.Style
+		restoreSequence(node, original)
+	}
+}
+
+// restoreMapping matches entries by key and puts the keys back in the file's
+// order. Keys the file did not have stay after the key they followed.
+func restoreMapping(node, original *yaml.Node) {
+	position := map[string]int{}
+	for i := 0; i+1 < len(original.Content); i += 2 {
+		position[original.Content[i].Value] = i / 2
+	}
+	type entry struct {
+		key, value *yaml.Node
+		rank, seq  int
+	}
+	entries := make([]entry, 0, len(node.Content)/2)
+	rank, seq := -1, 0
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+		if pos, ok := position[key.Value]; ok {
+			restoreFormatting(key, original.Content[2*pos])
+			restoreFormatting(value, original.Content[2*pos+1])
+			rank, seq = pos, 0
+		} else {
+			seq++
+		}
+		entries = append(entries, entry{key, value, rank, seq})
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].rank != entries[j].rank {
+			return entries[i].rank < entries[j].rank
+		}
+		return entries[i].seq < entries[j].seq
+	})
+	for i, e := range entries {
+		node.Content[2*i], node.Content[2*i+1] = e.key, e.value
+	}
+}
+
+// restoreSequence matches items by position when the length is unchanged,
+// otherwise by their identity (scalar text, or a mapping's first entry).
+func restoreSequence(node, original *yaml.Node) {
+	if len(node.Content) == len(original.Content) {
+		for i, item := range node.Content {
+			restoreFormatting(item, original.Content[i])
+		}
+		return
+	}
+	used := make([]bool, len(original.Content))
+	for _, item := range node.Content {
+		id := identity(item)
+		if id == "" {
+			continue
+		}
+		for j, candidate := range original.Content {
+			if !used[j] && identity(candidate) == id {
+				used[j] = true
+				restoreFormatting(item, candidate)
+				break
+			}
+		}
+	}
+}
+
+func identity(node *yaml.Node) string {
+	switch node.Kind {
+	case yaml.ScalarNode:
+		return "scalar:" + node.Value
+	case yaml.MappingNode:
+		if len(node.Content) >= 2 && node.Content[1].Kind == yaml.ScalarNode {
+			return node.Content[0].Value + ":" + node.Content[1].Value
+		}
+	}
+	return ""
+}
+
+// sameScalar reports whether two scalars hold the same value: the same text,
+// or numbers and booleans that decode equal, such as 6.0 and 6.
+func sameScalar(a, b *yaml.Node) bool {
+	if a.Value == b.Value {
+		return true
+	}
+	if a.ShortTag() == "!!str" || b.ShortTag() == "!!str" {
+		return false
+	}
+	var av, bv any
+	if a.Decode(&av) != nil || b.Decode(&bv) != nil {
+		return false
+	}
+	return reflect.DeepEqual(av, bv)
+}
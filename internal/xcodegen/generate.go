@@ -0,0 +1,416 @@
+package xcodegen
+
+import "fmt"
+
+// Generate produces the full project.yml content for XcodeGen.
+func Generate(p *Project) string {
+	data, err := Build(p).Marshal()
+	if err != nil {
+		// Build only stores strings, lists and plist-compatible values, which always encode.
+		panic(fmt.Sprintf("xcodegen: %v", err))
+	}
+	return string(data)
+}
+
+// Build returns the XcodeGen spec for the project.
+// Multi-platform projects get one application target per platform; single-platform
+// projects dispatch on the platform (and, for watchOS, on the watch project shape).
+// Test targets and build configurations apply on top of whichever layout was built.
+func Build(p *Project) *Spec {
+	spec := buildLayout(p)
+	t := &specBuilder{s: spec, p: p}
+	t.writeTestTargets()
+	t.writeConfigurations()
+	t.writeVersion()
+	return spec
+}
+
+// buildLayout builds the targets and schemes of the project's platform and shape.
+func buildLayout(p *Project) *Spec {
+	if p.isMultiPlatform() {
+		return buildMultiPlatform(p)
+	}
+
+	switch p.platform() {
+	case PlatformWatchOS:
+		if p.watchShape() == WatchShapePaired {
+			return buildPaired(p)
+		}
+		return buildWatchOnly(p)
+	case PlatformTvOS:
+		return buildSimple(p, PlatformTvOS)
+	case PlatformVisionOS:
+		return buildSimple(p, PlatformVisionOS)
+	case PlatformMacOS:
+		return buildMacOS(p)
+	}
+	return buildIOS(p)
+}
+
+// buildIOS builds the iOS spec.
+func buildIOS(p *Project) *Spec {
+	t := newSpecBuilder(p)
+	appName := p.AppName
+	hasExtensions := len(p.Extensions) > 0
+
+	t.writePackages()
+	t.writeOptions(PlatformIOS)
+
+	// Main app target
+	target := t.newIOSAppTarget()
+	if len(p.Localizations) > 1 {
+		// .lproj folders are copied as resources rather than compiled as sources.
+		target.Sources = []Source{
+			syncedSource(appName, "*.lproj"),
+			{Path: appName, Type: "syncedFolder", Includes: []string{"*.lproj"}, BuildPhase: "resources"},
+		}
+	} else {
+		target.Sources = []Source{syncedSource(appName)}
+	}
+	if hasExtensions {
+		target.Sources = append(target.Sources, sharedSource())
+	}
+
+	target.Settings = commonSettings(p.BundleID)
+	t.setIOSAppSettings(target.Settings)
+	setCommonPostSettings(target.Settings)
+	t.setAppearance(target.Settings, PlatformIOS)
+	t.setPermissions(target.Settings)
+
+	// Main app entitlements — merge configured entitlements with app-group entitlements
+	entitlements := make(map[string]any)
+	for k, v := range p.Entitlements {
+		entitlements[k] = v
+	}
+	if needsAppGroups(p.Extensions) {
+		entitlements["com.apple.security.application-groups"] = []any{"group." + p.BundleID}
+	}
+	target.Entitlements = entitlementsPlist(appName, appName, entitlements)
+
+	target.Info = t.iosAppInfo(p.Extensions)
+
+	target.Dependencies = t.dependencies(p.Extensions)
+	t.addTarget(appName, target)
+	t.writeExtensionTargets(p.Extensions, p.BundleID)
+
+	if hasExtensions || p.hasMonetization() {
+		t.writeScheme(appName, t.targetNames(p.Extensions), p.hasMonetization())
+	}
+
+	return t.s
+}
+
+// iosAppInfo returns the iOS app's Info.plist for keys that can't be expressed
+// as INFOPLIST_KEY_* build settings: Live Activity support and URL schemes.
+// It returns nil when the app needs neither.
+func (t *specBuilder) iosAppInfo(extensions []Extension) *Plist {
+	properties := map[string]any{}
+	for _, ext := range extensions {
+		if ext.Kind == "live_activity" {
+			properties["NSSupportsLiveActivities"] = true
+			break
+		}
+	}
+	if len(t.p.URLSchemes) > 0 {
+		properties["CFBundleURLTypes"] = []any{urlType(t.p.BundleID, t.p.URLSchemes)}
+	}
+	if len(properties) == 0 {
+		return nil
+	}
+	return &Plist{Path: t.p.AppName + "/Info.plist", Properties: properties}
+}
+
+// urlType is a CFBundleURLTypes entry registering schemes under name.
+func urlType(name string, schemes []string) map[string]any {
+	values := make([]any, len(schemes))
+	for i, scheme := range schemes {
+		values[i] = scheme
+	}
+	return map[string]any{"CFBundleURLName": name, "CFBundleURLSchemes": values}
+}
+
+// buildSimple builds the spec for tvOS and visionOS apps, which share a
+// layout: one application target with a fixed device family and no orientations.
+func buildSimple(p *Project, platform string) *Spec {
+	t := newSpecBuilder(p)
+	appName := p.AppName
+	hasExtensions := len(p.Extensions) > 0
+
+	t.writePackages()
+	t.writeOptions(platform)
+
+	target := newAppTarget(platform)
+	target.Sources = []Source{syncedSource(appName)}
+	if hasExtensions {
+		target.Sources = append(target.Sources, sharedSource())
+	}
+
+	target.Settings = commonSettings(p.BundleID)
+	target.Settings.Base.Set("TARGETED_DEVICE_FAMILY", deviceFamilyNumber(platform))
+	setCommonPostSettings(target.Settings)
+	t.setAppearance(target.Settings, platform)
+	t.setPermissions(target.Settings)
+
+	target.Entitlements = entitlementsPlist(appName, appName, p.Entitlements)
+	target.Dependencies = t.dependencies(p.Extensions)
+	t.addTarget(appName, target)
+	t.writeExtensionTargets(p.Extensions, p.BundleID)
+
+	if hasExtensions {
+		t.writeScheme(appName, t.targetNames(p.Extensions), false)
+	}
+
+	return t.s
+}
+
+// deviceFamilyNumber returns TARGETED_DEVICE_FAMILY for tvOS (3) and visionOS (7).
+func deviceFamilyNumber(platform string) string {
+	if platform == PlatformVisionOS {
+		return "7"
+	}
+	return "3"
+}
+
+// buildMacOS builds a native macOS spec.
+func buildMacOS(p *Project) *Spec {
+	t := newSpecBuilder(p)
+	appName := p.AppName
+	hasExtensions := len(p.Extensions) > 0
+
+	t.writePackages()
+	t.writeOptions(PlatformMacOS)
+
+	target := newAppTarget(PlatformMacOS)
+	target.Sources = []Source{syncedSource(appName)}
+	if hasExtensions {
+		target.Sources = append(target.Sources, sharedSource())
+	}
+
+	// Settings — no TARGETED_DEVICE_FAMILY, no UILaunchScreen, no UIApplicationSceneManifest.
+	// macOS apps always follow the system appearance, so no interface style is locked.
+	target.Settings = commonSettings(p.BundleID)
+	setMacOSSettings(target.Settings)
+	t.setPermissions(target.Settings)
+
+	target.Entitlements = entitlementsPlist(appName, appName, p.Entitlements)
+	target.Dependencies = t.dependencies(p.Extensions)
+	t.addTarget(appName, target)
+	t.writeExtensionTargets(p.Extensions, p.BundleID)
+
+	// macOS always writes scheme (not conditional on extensions)
+	t.writeScheme(appName, t.targetNames(p.Extensions), false)
+
+	return t.s
+}
+
+// buildWatchOnly builds the spec for a standalone watchOS app.
+func buildWatchOnly(p *Project) *Spec {
+	t := newSpecBuilder(p)
+	appName := p.AppName
+	bundleID := p.BundleID
+	watchAppName := WatchAppTargetName(appName)
+	watchBundleID := bundleID + ".watchkitapp"
+	watchExtName := WatchExtensionTargetName(appName)
+	watchExtBundleID := watchBundleID + ".watchkitextension"
+	hasExtensions := len(p.Extensions) > 0
+
+	t.writePackages()
+	t.writeOptions(PlatformWatchOS)
+
+	// Watch container target
+	container := &Target{
+		Type:     "application.watchapp2-container",
+		Platform: "watchOS",
+		Sources:  []Source{watchSource(appName)},
+		Settings: commonSettings(bundleID),
+	}
+	t.setPermissions(container.Settings)
+	container.Entitlements = entitlementsPlist(appName, appName, p.Entitlements)
+
+	// Dependencies: SPM packages + watch app + optional extension targets
+	container.Dependencies = packageDependencies(p.Packages)
+	container.Dependencies = append(container.Dependencies, embedDependency(watchAppName))
+	container.Dependencies = append(container.Dependencies, embedDependencies(appName, p.Extensions)...)
+	t.addTarget(appName, container)
+
+	// Watch app target (wrapper app bundle)
+	t.writeWatchAppTarget(watchAppName, appName, "WatchApp-Info.plist", watchBundleID, map[string]any{
+		"WKWatchOnly":                       true,
+		"WKRunsIndependentlyOfCompanionApp": true,
+	})
+
+	// Intrinsic watch runtime extension target
+	t.writeIntrinsicWatchExtensionTarget(watchExtName, appName, watchExtBundleID, watchBundleID, hasExtensions)
+
+	// Extension targets (widget, control_widget and app_intents on watchOS)
+	t.writeExtensionTargets(p.Extensions, bundleID)
+
+	t.writeScheme(appName, append([]string{watchAppName, watchExtName}, t.targetNames(p.Extensions)...), false)
+
+	return t.s
+}
+
+// buildPaired builds the spec for a paired iOS+watchOS app.
+func buildPaired(p *Project) *Spec {
+	t := newSpecBuilder(p)
+	appName := p.AppName
+	bundleID := p.BundleID
+	watchAppName := WatchAppTargetName(appName)
+	watchBundleID := bundleID + ".watchkitapp"
+	watchExtName := WatchExtensionTargetName(appName)
+	watchExtBundleID := watchBundleID + ".watchkitextension"
+	hasExtensions := len(p.Extensions) > 0
+
+	t.writePackages()
+	t.writeOptions(PlatformIOS, PlatformWatchOS)
+
+	// iOS parent target (iPhone only — the companion of a watch app)
+	target := newAppTarget(PlatformIOS)
+	target.Sources = []Source{syncedSource(appName)}
+	if hasExtensions {
+		target.Sources = append(target.Sources, sharedSource())
+	}
+
+	target.Settings = commonSettings(bundleID)
+	base := &target.Settings.Base
+	base.Set("INFOPLIST_KEY_UIApplicationSceneManifest_Generation", "YES")
+	base.Set("INFOPLIST_KEY_UIApplicationSupportsIndirectInputEvents", "YES")
+	base.Set("INFOPLIST_KEY_UILaunchScreen_Generation", "YES")
+	base.Set("TARGETED_DEVICE_FAMILY", "1")
+	base.Set("INFOPLIST_KEY_UISupportedInterfaceOrientations_iPhone", "UIInterfaceOrientationPortrait")
+	setCommonPostSettings(target.Settings)
+	t.setPermissions(target.Settings)
+
+	target.Entitlements = entitlementsPlist(appName, appName, p.Entitlements)
+
+	// iOS target depends on SPM packages + watch target + extensions
+	target.Dependencies = packageDependencies(p.Packages)
+	target.Dependencies = append(target.Dependencies, embedDependency(watchAppName))
+	target.Dependencies = append(target.Dependencies, embedDependencies(appName, p.Extensions)...)
+	t.addTarget(appName, target)
+
+	// Watch target
+	t.writeWatchAppTarget(watchAppName, watchAppName, "Info.plist", watchBundleID, map[string]any{
+		"WKCompanionAppBundleIdentifier":    bundleID,
+		"WKRunsIndependentlyOfCompanionApp": true,
+	})
+
+	// Intrinsic watch runtime extension target
+	t.writeIntrinsicWatchExtensionTarget(watchExtName, watchAppName, watchExtBundleID, watchBundleID, hasExtensions)
+
+	// Watch extension targets (widget only on watchOS)
+	t.writeExtensionTargets(p.Extensions, watchBundleID)
+
+	// Scheme — run the iOS app by default
+	t.writeScheme(appName, append([]string{watchAppName, watchExtName}, t.targetNames(p.Extensions)...), false)
+
+	return t.s
+}
+
+// platformTarget describes a secondary application target of a multi-platform project.
+type platformTarget struct {
+	platform string
+	suffix   string // target name and source directory suffix
+	bundle   string // bundle ID suffix
+}
+
+// multiPlatformTargets lists the non-iOS, non-watch application targets in generation order.
+var multiPlatformTargets = []platformTarget{
+	{PlatformTvOS, "TV", "tv"},
+	{PlatformVisionOS, "Vision", "vision"},
+	{PlatformMacOS, "Mac", "mac"},
+}
+
+// buildMultiPlatform builds a spec with targets for all platforms.
+func buildMultiPlatform(p *Project) *Spec {
+	t := newSpecBuilder(p)
+	appName := p.AppName
+	bundleID := p.BundleID
+	hasWatchOS := p.hasPlatform(PlatformWatchOS)
+	watchAppName := WatchAppTargetName(appName)
+	watchExtName := WatchExtensionTargetName(appName)
+
+	t.writePackages()
+	deployment := []string{PlatformIOS}
+	for _, platform := range []string{PlatformWatchOS, PlatformTvOS, PlatformVisionOS, PlatformMacOS} {
+		if p.hasPlatform(platform) {
+			deployment = append(deployment, platform)
+		}
+	}
+	t.writeOptions(deployment...)
+
+	// iOS main target
+	target := t.newIOSAppTarget()
+	target.Sources = []Source{syncedSource(appName), sharedSource()}
+	target.Settings = commonSettings(bundleID)
+	t.setIOSAppSettings(target.Settings)
+	setCommonPostSettings(target.Settings)
+	t.setAppearance(target.Settings, PlatformIOS)
+	t.setPermissions(target.Settings)
+	target.Entitlements = entitlementsPlist(appName, appName, p.Entitlements)
+
+	// iOS dependencies: SPM packages + watch target + iOS extensions
+	iosExtensions := p.extensionsFor(PlatformIOS)
+	target.Info = t.iosAppInfo(iosExtensions)
+	target.Dependencies = packageDependencies(p.Packages)
+	if hasWatchOS {
+		target.Dependencies = append(target.Dependencies, embedDependency(watchAppName))
+	}
+	target.Dependencies = append(target.Dependencies, embedDependencies(appName, iosExtensions)...)
+	t.addTarget(appName, target)
+
+	// watchOS targets (when present)
+	if hasWatchOS {
+		watchBundleID := bundleID + ".watchkitapp"
+		watchExtBundleID := watchBundleID + ".watchkitextension"
+
+		t.writeWatchAppTarget(watchAppName, watchAppName, "Info.plist", watchBundleID, map[string]any{
+			"WKCompanionAppBundleIdentifier":    bundleID,
+			"WKRunsIndependentlyOfCompanionApp": true,
+		})
+		t.writeIntrinsicWatchExtensionTarget(watchExtName, watchAppName, watchExtBundleID, watchBundleID, true)
+	}
+
+	// tvOS, visionOS and macOS targets (when present)
+	for _, pt := range multiPlatformTargets {
+		if !p.hasPlatform(pt.platform) {
+			continue
+		}
+		targetName := appName + pt.suffix
+
+		platformApp := newAppTarget(pt.platform)
+		platformApp.Sources = []Source{syncedSource(targetName), sharedSource()}
+		platformApp.Settings = commonSettings(bundleID + "." + pt.bundle)
+		if pt.platform == PlatformMacOS {
+			setMacOSSettings(platformApp.Settings)
+		} else {
+			platformApp.Settings.Base.Set("TARGETED_DEVICE_FAMILY", deviceFamilyNumber(pt.platform))
+			setCommonPostSettings(platformApp.Settings)
+			t.setAppearance(platformApp.Settings, pt.platform)
+		}
+		platformApp.Entitlements = entitlementsPlist(targetName, targetName, nil)
+
+		// Dependencies: SPM packages + extensions for this platform
+		platformApp.Dependencies = t.dependencies(p.extensionsFor(pt.platform))
+		t.addTarget(targetName, platformApp)
+	}
+
+	// Extension targets (per-platform)
+	t.writeExtensionTargets(p.Extensions, bundleID)
+
+	// iOS scheme
+	iosBuild := t.targetNames(iosExtensions)
+	if hasWatchOS {
+		iosBuild = append([]string{watchAppName, watchExtName}, iosBuild...)
+	}
+	t.writeScheme(appName, iosBuild, false)
+
+	for _, pt := range multiPlatformTargets {
+		if !p.hasPlatform(pt.platform) {
+			continue
+		}
+		t.writeScheme(appName+pt.suffix, t.targetNames(p.extensionsFor(pt.platform)), false)
+	}
+
+	return t.s
+}
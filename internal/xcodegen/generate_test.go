@@ -0,0 +1,316 @@
+package xcodegen
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// updateGolden controls whether golden files are rewritten.
+// Run with: UPDATE_GOLDEN=1 go test ./internal/xcodegen
+var updateGolden = os.Getenv("UPDATE_GOLDEN") == "1"
+
+func assertGolden(t *testing.T, name, got string) {
+	t.Helper()
+	path := filepath.Join("testdata", name+".golden")
+	if updateGolden {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("mkdir for golden: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+			t.Fatalf("write golden %s: %v", path, err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read golden %s: %v (run with UPDATE_GOLDEN=1 to create)", path, err)
+	}
+	if got != string(want) {
+		t.Errorf("golden mismatch for %s:\n--- want ---\n%s\n--- got ---\n%s", name, string(want), got)
+	}
+}
+
+var (
+	testPackages = []Package{
+		{Name: "Kingfisher", URL: "https://github.com/onevcat/Kingfisher", MinVersion: "8.1.0", Products: []string{"Kingfisher"}},
+		{Name: "Nuke", URL: "https://github.com/kean/Nuke", MinVersion: "12.8.0", Products: []string{"Nuke", "NukeUI"}},
+	}
+	testPermissions = []Permission{
+		{Key: "NSCameraUsageDescription", Description: "Scan receipts: fast"},
+	}
+)
+
+func TestGenerateGolden(t *testing.T) {
+	tests := []struct {
+		name    string
+		project Project
+	}{
+		{
+			name:    "ios_minimal",
+			project: Project{AppName: "Notes", BundleID: "com.example.notes", InterfaceStyle: "Light"},
+		},
+		{
+			name: "ios_full",
+			project: Project{
+				AppName:       "Trips",
+				BundleID:      "com.example.trips",
+				DeviceFamily:  "universal",
+				Permissions:   testPermissions,
+				Localizations: []string{"en", "ar"},
+				Entitlements:  map[string]any{"com.apple.developer.applesignin": []any{"Default"}},
+				BuildSettings: map[string]string{"SWIFT_STRICT_CONCURRENCY": "complete"},
+				Packages:      testPackages,
+				Extensions: []Extension{
+					{Kind: "widget", Name: "TripsWidget", Settings: map[string]string{"INFOPLIST_KEY_CFBundleDisplayName": "Trips"}},
+					{Kind: "live_activity"},
+					{Kind: "share", Entitlements: map[string]any{"com.apple.developer.weatherkit": true}},
+				},
+				InterfaceStyle:        "Dark",
+				StoreKitConfiguration: "Trips/Trips.storekit",
+				URLSchemes:            []string{"trips"},
+			},
+		},
+		{
+			name:    "ios_ipad",
+			project: Project{AppName: "Sketch", BundleID: "com.example.sketch", DeviceFamily: "ipad"},
+		},
+		{
+			name: "tvos",
+			project: Project{
+				AppName:        "Flix",
+				BundleID:       "com.example.flix",
+				Platform:       PlatformTvOS,
+				Packages:       testPackages[:1],
+				Extensions:     []Extension{{Kind: "widget"}},
+				InterfaceStyle: "Dark",
+			},
+		},
+		{
+			name:    "visionos",
+			project: Project{AppName: "Space", BundleID: "com.example.space", Platform: PlatformVisionOS, Permissions: testPermissions, InterfaceStyle: "Light"},
+		},
+		{
+			name: "macos",
+			project: Project{
+				AppName:        "Ledger",
+				BundleID:       "com.example.ledger",
+				Platform:       PlatformMacOS,
+				Extensions:     []Extension{{Kind: "widget"}},
+				InterfaceStyle: "Light",
+			},
+		},
+		{
+			name: "watch_only",
+			project: Project{
+				AppName:     "Pulse",
+				BundleID:    "com.example.pulse",
+				Platform:    PlatformWatchOS,
+				Permissions: testPermissions,
+				Packages:    testPackages[:1],
+				Extensions:  []Extension{{Kind: "widget"}},
+				UnitTests:   true,
+				UITests:     true,
+			},
+		},
+		{
+			name: "watch_paired",
+			project: Project{
+				AppName:           "Pulse",
+				BundleID:          "com.example.pulse",
+				Platform:          PlatformWatchOS,
+				WatchProjectShape: WatchShapePaired,
+				Entitlements:      map[string]any{"com.apple.developer.healthkit": true},
+				Extensions:        []Extension{{Kind: "widget"}},
+			},
+		},
+		{
+			name: "ios_tests",
+			project: Project{
+				AppName:        "Notes",
+				BundleID:       "com.example.notes",
+				DeviceFamily:   "universal",
+				InterfaceStyle: "Light",
+				UnitTests:      true,
+				UITests:        true,
+			},
+		},
+		{
+			name: "ios_configs",
+			project: Project{
+				AppName:    "Shop",
+				BundleID:   "com.example.shop",
+				Extensions: []Extension{{Kind: "widget"}},
+				UnitTests:  true,
+				Configurations: []BuildConfiguration{
+					{Name: "Debug", BackendURL: "http://localhost:8080"},
+					{Name: "Staging", BackendURL: "https://staging.api.example.com", AppIcon: "AppIcon-Staging"},
+					{Name: "Beta", Type: "release", BundleIDSuffix: ".beta", DisplayName: "Shop β"},
+					{Name: "Release", BackendURL: "https://api.example.com"},
+				},
+			},
+		},
+		{
+			name: "ios_extensions",
+			project: Project{
+				AppName:  "Tasks",
+				BundleID: "com.example.tasks",
+				Extensions: []Extension{
+					{Kind: "app_intents"},
+					{Kind: "control_widget"},
+					{Kind: "spotlight"},
+					{Kind: "notification_content"},
+				},
+			},
+		},
+		{
+			name: "multi_platform",
+			project: Project{
+				AppName:      "Focus",
+				BundleID:     "com.example.focus",
+				Platform:     PlatformIOS,
+				Platforms:    []string{PlatformIOS, PlatformWatchOS, PlatformTvOS, PlatformVisionOS, PlatformMacOS},
+				DeviceFamily: "iphone",
+				Packages:     testPackages[:1],
+				Extensions: []Extension{
+					{Kind: "widget"},
+					{Kind: "widget", Name: "FocusTVTopShelf", Platform: PlatformTvOS},
+					{Kind: "widget", Name: "FocusMacWidget", Platform: PlatformMacOS},
+				},
+				InterfaceStyle: "Light",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertGolden(t, tt.name, Generate(&tt.project))
+		})
+	}
+}
+
+func TestGenerateIsDeterministic(t *testing.T) {
+	p := &Project{
+		AppName:       "Trips",
+		BundleID:      "com.example.trips",
+		Entitlements:  map[string]any{"b": true, "a": true, "c": []any{"x"}},
+		BuildSettings: map[string]string{"Z_SETTING": "1", "A_SETTING": "2"},
+		Extensions:    []Extension{{Kind: "share"}},
+	}
+	first := Generate(p)
+	for range 20 {
+		if got := Generate(p); got != first {
+			t.Fatalf("output changed between runs:\n%s\n---\n%s", first, got)
+		}
+	}
+}
+
+func TestProjectBundleIDPrefix(t *testing.T) {
+	tests := []struct {
+		project Project
+		want    string
+	}{
+		{Project{BundleID: "com.example.notes"}, "com.example"},
+		{Project{BundleID: "com.example.notes", BundleIDPrefix: "com.custom"}, "com.custom"},
+		{Project{BundleID: "notes"}, "notes"},
+	}
+	for _, tt := range tests {
+		if got := tt.project.bundleIDPrefix(); got != tt.want {
+			t.Errorf("bundleIDPrefix(%+v) = %q, want %q", tt.project, got, tt.want)
+		}
+	}
+}
+
+func TestExtensionTargetName(t *testing.T) {
+	tests := []struct {
+		ext  Extension
+		want string
+	}{
+		{Extension{Kind: "widget"}, "AppWidget"},
+		{Extension{Kind: "live_activity"}, "AppLiveactivity"},
+		{Extension{Kind: "widget", Name: "Custom"}, "Custom"},
+	}
+	for _, tt := range tests {
+		if got := tt.ext.TargetName("App"); got != tt.want {
+			t.Errorf("TargetName(%+v) = %q, want %q", tt.ext, got, tt.want)
+		}
+	}
+}
+
+func TestConfigFiles(t *testing.T) {
+	p := &Project{
+		AppName:  "Shop",
+		BundleID: "com.example.shop",
+		Configurations: []BuildConfiguration{
+			{Name: "Staging", BackendURL: "https://staging.api.example.com", Values: map[string]string{"ANALYTICS_KEY": "stg-123"}},
+		},
+	}
+	files := ConfigFiles(p)
+	if len(files) != 3 {
+		t.Fatalf("ConfigFiles returned %d files, want Debug, Staging and Release", len(files))
+	}
+	staging := files["Configs/Staging.xcconfig"]
+	for _, want := range []string{
+		"BUNDLE_ID_SUFFIX = .staging\n",
+		"APP_DISPLAY_NAME = Shop Staging\n",
+		"APP_ICON_NAME = AppIcon\n",
+		"API_BASE_URL = https:/$()/staging.api.example.com\n",
+		"ANALYTICS_KEY = stg-123\n",
+	} {
+		if !strings.Contains(staging, want) {
+			t.Errorf("Staging.xcconfig missing %q:\n%s", want, staging)
+		}
+	}
+	if release := files["Configs/Release.xcconfig"]; !strings.Contains(release, "BUNDLE_ID_SUFFIX = \n") {
+		t.Errorf("Release.xcconfig should have an empty suffix:\n%s", release)
+	}
+
+	if files := ConfigFiles(&Project{AppName: "Shop", BundleID: "com.example.shop"}); files != nil {
+		t.Errorf("ConfigFiles without configurations = %v, want nil", files)
+	}
+}
+
+func TestPackageVersionRequirements(t *testing.T) {
+	yml := Generate(&Project{
+		AppName:  "Trips",
+		BundleID: "com.example.trips",
+		Packages: []Package{
+			{Name: "Kingfisher", URL: "https://github.com/onevcat/Kingfisher", MinVersion: "8.1.0"},
+			{Name: "Nuke", URL: "https://github.com/kean/Nuke", ExactVersion: "12.8.0"},
+			{Name: "Lottie", URL: "https://github.com/airbnb/lottie-ios", MinVersion: "4.5.0", MaxVersion: "4.6.0"},
+		},
+	})
+	for _, want := range []string{
+		"Kingfisher:\n    url: https://github.com/onevcat/Kingfisher\n    from: 8.1.0\n",
+		"Nuke:\n    url: https://github.com/kean/Nuke\n    exactVersion: 12.8.0\n",
+		"Lottie:\n    url: https://github.com/airbnb/lottie-ios\n    maxVersion: 4.6.0\n    minVersion: 4.5.0\n",
+	} {
+		if !strings.Contains(yml, want) {
+			t.Errorf("project.yml missing %q:\n%s", want, yml)
+		}
+	}
+}
+
+func TestVersionAppliesToEveryTarget(t *testing.T) {
+	spec := Build(&Project{
+		AppName:          "Trips",
+		BundleID:         "com.example.trips",
+		Extensions:       []Extension{{Kind: "widget"}},
+		UnitTests:        true,
+		MarketingVersion: "1.4.0",
+		BuildNumber:      12,
+	})
+	for _, name := range []string{"Trips", "TripsWidget"} {
+		target, _ := spec.Targets.Get(name)
+		version, _ := target.Settings.Base.Get("MARKETING_VERSION")
+		build, _ := target.Settings.Base.Get("CURRENT_PROJECT_VERSION")
+		if version != "1.4.0" || build != 12 {
+			t.Errorf("%s version = %v (%v), want 1.4.0 (12)", name, version, build)
+		}
+	}
+	tests, _ := spec.Targets.Get("TripsTests")
+	if _, ok := tests.Settings.Base.Get("MARKETING_VERSION"); ok {
+		t.Error("test target got a marketing version")
+	}
+}
@@ -0,0 +1,13 @@
+package xcodegen
+
+import "sort"
+
+// sortedKeys returns the keys of m in lexical order so generated YAML is stable.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,206 @@
+// Package xcodegen models an app's Xcode project and renders it as an XcodeGen
+// project.yml. The scaffolder (orchestration) and the xcodegen MCP server both
+// build a Project and call Generate, so every platform and project shape is
+// produced by the same code.
+package xcodegen
+
+import "strings"
+
+// Platform identifiers, matching the planner's platform strings.
+const (
+	PlatformIOS      = "ios"
+	PlatformWatchOS  = "watchos"
+	PlatformTvOS     = "tvos"
+	PlatformVisionOS = "visionos"
+	PlatformMacOS    = "macos"
+)
+
+// Watch project shapes.
+const (
+	WatchShapeStandalone = "watch_only"
+	WatchShapePaired     = "paired_ios_watch"
+)
+
+// deploymentTarget is the minimum OS version used for every platform.
+const deploymentTarget = "26.0"
+
+// Project is the typed description of an app's Xcode project.
+type Project struct {
+	AppName string
+	// BundleID is the main app's bundle identifier; secondary targets derive theirs from it.
+	BundleID string
+	// BundleIDPrefix is written to options.bundleIdPrefix. Empty derives it from BundleID.
+	BundleIDPrefix string
+	// Platform is the primary platform; empty means iOS.
+	Platform string
+	// Platforms lists every platform of a multi-platform app (iOS is always the main target).
+	Platforms         []string
+	WatchProjectShape string
+	// DeviceFamily is iphone (default), ipad or universal. iOS only.
+	DeviceFamily  string
+	Permissions   []Permission
+	Extensions    []Extension
+	Localizations []string
+	// Entitlements are the main app target's entitlements.
+	Entitlements map[string]any
+	// BuildSettings are extra build settings for the main app target.
+	BuildSettings map[string]string
+	Packages      []Package
+	// InterfaceStyle locks iOS and tvOS apps to "Light" or "Dark". Empty follows the system.
+	InterfaceStyle string
+	// StoreKitConfiguration is the .storekit file the iOS run scheme uses, relative to the project.
+	StoreKitConfiguration string
+	// Configurations adds build configurations (Staging, Beta, ...) next to
+	// Debug and Release, each backed by Configs/<Name>.xcconfig.
+	Configurations []BuildConfiguration
+	// UnitTests adds the <App>Tests unit test bundle, hosted by the app.
+	UnitTests bool
+	// UITests adds the <App>UITests UI test bundle. Ignored for watchOS apps.
+	UITests bool
+	// URLSchemes are custom URL schemes the iOS app opens, e.g. for deep links.
+	URLSchemes []string
+	// MarketingVersion and BuildNumber are written to every target that carries
+	// MARKETING_VERSION and CURRENT_PROJECT_VERSION. Empty means 1.0 and build 1.
+	MarketingVersion string
+	BuildNumber      int
+}
+
+// Permission is an Info.plist usage description emitted as an INFOPLIST_KEY_* build setting.
+type Permission struct {
+	Key         string
+	Description string
+}
+
+// Extension is a secondary target (widget, share extension, app clip, ...).
+type Extension struct {
+	Kind string
+	Name string
+	// Platform places the extension in a multi-platform project; empty means iOS.
+	Platform     string
+	InfoPlist    map[string]any
+	Entitlements map[string]any
+	Settings     map[string]string
+}
+
+// Package is a Swift package dependency of the main app target.
+type Package struct {
+	// Name is the key under packages: (the repository name).
+	Name       string
+	URL        string
+	MinVersion string
+	// ExactVersion pins exactly this version instead of MinVersion.
+	ExactVersion string
+	// MaxVersion is an exclusive upper bound; with MinVersion it pins a range
+	// instead of resolving up to the next major version.
+	MaxVersion string
+	// Path is a local package directory relative to the project, set instead of URL and MinVersion.
+	Path string
+	// Products lists the products to link. Empty links a product named after the package.
+	Products []string
+}
+
+// TargetName returns the Xcode target name for the extension.
+func (e Extension) TargetName(appName string) string {
+	if e.Name != "" {
+		return e.Name
+	}
+	kindStr := e.Kind
+	if len(kindStr) > 0 {
+		kindStr = strings.ToUpper(kindStr[:1]) + kindStr[1:]
+	}
+	return appName + strings.ReplaceAll(kindStr, "_", "")
+}
+
+// WatchAppTargetName returns the watch app target name for an app.
+func WatchAppTargetName(appName string) string {
+	return appName + "Watch"
+}
+
+// WatchExtensionTargetName returns the intrinsic watch extension target name for an app.
+func WatchExtensionTargetName(appName string) string {
+	return appName + "WatchExtension"
+}
+
+func (p *Project) platform() string {
+	if p.Platform == "" {
+		return PlatformIOS
+	}
+	return p.Platform
+}
+
+func (p *Project) isMultiPlatform() bool {
+	return len(p.Platforms) > 1
+}
+
+func (p *Project) hasPlatform(platform string) bool {
+	for _, plat := range p.Platforms {
+		if plat == platform {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *Project) watchShape() string {
+	if p.WatchProjectShape == "" {
+		return WatchShapeStandalone
+	}
+	return p.WatchProjectShape
+}
+
+func (p *Project) bundleIDPrefix() string {
+	if p.BundleIDPrefix != "" {
+		return p.BundleIDPrefix
+	}
+	if i := strings.LastIndex(p.BundleID, "."); i > 0 {
+		return p.BundleID[:i]
+	}
+	return p.BundleID
+}
+
+func (p *Project) hasMonetization() bool {
+	return p.StoreKitConfiguration != ""
+}
+
+// extensionsFor returns the extensions placed on the given platform. Extensions
+// without a platform belong to iOS.
+func (p *Project) extensionsFor(platform string) []Extension {
+	var out []Extension
+	for _, ext := range p.Extensions {
+		extPlatform := ext.Platform
+		if extPlatform == "" {
+			extPlatform = PlatformIOS
+		}
+		if extPlatform == platform {
+			out = append(out, ext)
+		}
+	}
+	return out
+}
+
+// xcodegenPlatform returns the XcodeGen platform value for a platform identifier.
+func xcodegenPlatform(platform string) string {
+	switch platform {
+	case PlatformWatchOS:
+		return "watchOS"
+	case PlatformTvOS:
+		return "tvOS"
+	case PlatformVisionOS:
+		return "visionOS"
+	case PlatformMacOS:
+		return "macOS"
+	default:
+		return "iOS"
+	}
+}
+
+// PlatformFromXcodeGen returns the platform identifier for an XcodeGen platform
+// value (iOS, watchOS, ...), or "" for values nanowave does not build for.
+func PlatformFromXcodeGen(value string) string {
+	for _, platform := range []string{PlatformIOS, PlatformWatchOS, PlatformTvOS, PlatformVisionOS, PlatformMacOS} {
+		if xcodegenPlatform(platform) == value {
+			return platform
+		}
+	}
+	return ""
+}
@@ -0,0 +1,444 @@
+package xcodegen
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"reflect"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Spec is the typed form of an XcodeGen project.yml. Generate builds one from a
+// Project; Load and Parse read an existing file so it can be edited and saved
+// back. Keys the model does not know about are kept in the Extra maps, and a
+// parsed spec saves back with the file's comments, key order and scalar text
+// (6.0 stays 6.0, 007 stays 007), so a load/save round trip only changes what
+// was edited.
+type Spec struct {
+	Name     string                    `yaml:"name"`
+	Packages OrderedMap[*SwiftPackage] `yaml:"packages,omitempty"`
+	Options  Options                   `yaml:"options"`
+	// Configs maps build configuration names to their type (debug or release).
+	Configs OrderedMap[string] `yaml:"configs,omitempty"`
+	// ConfigFiles maps build configuration names to project-level .xcconfig files.
+	ConfigFiles OrderedMap[string] `yaml:"configFiles,omitempty"`
+	// Settings are project-level build settings, inherited by every target.
+	Settings *Settings           `yaml:"settings,omitempty"`
+	Targets  OrderedMap[*Target] `yaml:"targets"`
+	Schemes  OrderedMap[*Scheme] `yaml:"schemes,omitempty"`
+	Extra    map[string]any      `yaml:",inline"`
+
+	// doc is the parsed file, whose formatting Marshal keeps.
+	doc *yaml.Node
+}
+
+// SwiftPackage is an entry of the top-level packages section.
+type SwiftPackage struct {
+	URL   string         `yaml:"url,omitempty"`
+	From  string         `yaml:"from,omitempty"`
+	Path  string         `yaml:"path,omitempty"`
+	Extra map[string]any `yaml:",inline"`
+}
+
+// Options is the project-wide options section.
+type Options struct {
+	BundleIDPrefix              string             `yaml:"bundleIdPrefix,omitempty"`
+	DeploymentTarget            OrderedMap[string] `yaml:"deploymentTarget,omitempty"`
+	XcodeVersion                string             `yaml:"xcodeVersion,omitempty"`
+	CreateIntermediateGroups    bool               `yaml:"createIntermediateGroups,omitempty"`
+	GenerateEmptyDirectories    bool               `yaml:"generateEmptyDirectories,omitempty"`
+	UseBaseInternationalization *bool              `yaml:"useBaseInternationalization,omitempty"`
+	KnownRegions                []string           `yaml:"knownRegions,omitempty"`
+	Extra                       map[string]any     `yaml:",inline"`
+}
+
+// Target is one entry of the targets section.
+type Target struct {
+	Type string `yaml:"type"`
+	// Platform is the target's platform. A target built for several platforms
+	// (platform: [iOS, tvOS]) lists them in Platforms, and Platform is the first.
+	Platform              string              `yaml:"platform"`
+	Platforms             []string            `yaml:"-"`
+	SupportedDestinations []string            `yaml:"supportedDestinations,omitempty"`
+	DestinationFilters    []DestinationFilter `yaml:"destinationFilters,omitempty"`
+	Sources               []Source            `yaml:"sources,omitempty"`
+	Settings              *Settings           `yaml:"settings,omitempty"`
+	Entitlements          *Plist              `yaml:"entitlements,omitempty"`
+	Info                  *Plist              `yaml:"info,omitempty"`
+	Dependencies          []Dependency        `yaml:"dependencies,omitempty"`
+	Extra                 map[string]any      `yaml:",inline"`
+}
+
+// DestinationFilter narrows the devices an iOS target runs on.
+type DestinationFilter struct {
+	Device string `yaml:"device"`
+}
+
+// Source is a target source entry. A bare string in project.yml loads as a
+// Source with only Path set, and saves back as a bare string.
+type Source struct {
+	Path     string   `yaml:"path"`
+	Type     string   `yaml:"type,omitempty"`
+	Optional bool     `yaml:"optional,omitempty"`
+	Excludes []string `yaml:"excludes,omitempty"`
+	Includes []string `yaml:"includes,omitempty"`
+	// BuildPhase is a phase name such as "resources", or a copyFiles mapping.
+	BuildPhase any            `yaml:"buildPhase,omitempty"`
+	Extra      map[string]any `yaml:",inline"`
+
+	// bare records that the source was written as a bare path.
+	bare bool
+}
+
+// Settings holds a target's or the project's build settings. Numeric values
+// load as the text written in project.yml, so MARKETING_VERSION 1.10 reads as
+// "1.10" rather than the number 1.1.
+type Settings struct {
+	Base    OrderedMap[any]            `yaml:"base,omitempty"`
+	Configs map[string]OrderedMap[any] `yaml:"configs,omitempty"`
+	Groups  []string                   `yaml:"groups,omitempty"`
+	Extra   map[string]any             `yaml:",inline"`
+
+	// flat records that the settings were written without a base section.
+	flat bool
+}
+
+// Plist is an info or entitlements section: the generated file's path and its properties.
+type Plist struct {
+	Path       string         `yaml:"path"`
+	Properties map[string]any `yaml:"properties"`
+}
+
+// Dependency is a target dependency on another target, a package or an SDK.
+type Dependency struct {
+	Target  string         `yaml:"target,omitempty"`
+	Package string         `yaml:"package,omitempty"`
+	Product string         `yaml:"product,omitempty"`
+	SDK     string         `yaml:"sdk,omitempty"`
+	Embed   *bool          `yaml:"embed,omitempty"`
+	Extra   map[string]any `yaml:",inline"`
+}
+
+// Scheme is one entry of the schemes section.
+type Scheme struct {
+	Build   SchemeBuild    `yaml:"build"`
+	Run     *SchemeRun     `yaml:"run,omitempty"`
+	Test    *SchemeAction  `yaml:"test,omitempty"`
+	Profile *SchemeAction  `yaml:"profile,omitempty"`
+	Analyze *SchemeAction  `yaml:"analyze,omitempty"`
+	Archive *SchemeAction  `yaml:"archive,omitempty"`
+	Extra   map[string]any `yaml:",inline"`
+}
+
+// SchemeBuild lists the targets a scheme builds, each mapped to its build types ("all").
+type SchemeBuild struct {
+	Targets OrderedMap[any] `yaml:"targets"`
+	Extra   map[string]any  `yaml:",inline"`
+}
+
+// SchemeRun is a scheme's run action.
+type SchemeRun struct {
+	Executable            string         `yaml:"executable,omitempty"`
+	Config                string         `yaml:"config,omitempty"`
+	StoreKitConfiguration string         `yaml:"storeKitConfiguration,omitempty"`
+	Extra                 map[string]any `yaml:",inline"`
+}
+
+// SchemeAction is a scheme's test, profile, analyze or archive action.
+type SchemeAction struct {
+	// Config is the build configuration the action uses.
+	Config string `yaml:"config,omitempty"`
+	// GatherCoverageData and Targets apply to the test action.
+	GatherCoverageData bool           `yaml:"gatherCoverageData,omitempty"`
+	Targets            []TestTarget   `yaml:"targets,omitempty"`
+	Extra              map[string]any `yaml:",inline"`
+}
+
+// TestTarget is a test action target: a bare target name, or a mapping with
+// the name and options such as parallelizable or skippedTests.
+type TestTarget struct {
+	Name  string         `yaml:"name"`
+	Extra map[string]any `yaml:",inline"`
+
+	// mapping records that the target was written as a mapping.
+	mapping bool
+}
+
+// UnmarshalYAML accepts both the platform name and the list form of a target's platform.
+func (t *Target) UnmarshalYAML(node *yaml.Node) error {
+	type plain Target
+	var platforms []string
+	if node.Kind == yaml.MappingNode {
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			value := node.Content[i+1]
+			if node.Content[i].Value != "platform" || value.Kind != yaml.SequenceNode {
+				continue
+			}
+			if err := value.Decode(&platforms); err != nil {
+				return err
+			}
+			first := ""
+			if len(platforms) > 0 {
+				first = platforms[0]
+			}
+			copied := *node
+			copied.Content = append([]*yaml.Node(nil), node.Content...)
+			copied.Content[i+1] = &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: first}
+			node = &copied
+			break
+		}
+	}
+	if err := node.Decode((*plain)(t)); err != nil {
+		return err
+	}
+	t.Platforms = platforms
+	return nil
+}
+
+// MarshalYAML writes Platforms as the platform list when the target has several.
+func (t Target) MarshalYAML() (any, error) {
+	type plain Target
+	if len(t.Platforms) == 0 {
+		return plain(t), nil
+	}
+	var node yaml.Node
+	if err := node.Encode(plain(t)); err != nil {
+		return nil, err
+	}
+	list := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq", Style: yaml.FlowStyle}
+	for _, p := range t.Platforms {
+		list.Content = append(list.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: p})
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == "platform" {
+			node.Content[i+1] = list
+		}
+	}
+	return &node, nil
+}
+
+// UnmarshalYAML accepts both the name and the mapping form of a test target.
+func (t *TestTarget) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		*t = TestTarget{Name: node.Value}
+		return nil
+	}
+	type plain TestTarget
+	if err := node.Decode((*plain)(t)); err != nil {
+		return err
+	}
+	t.mapping = true
+	return nil
+}
+
+// MarshalYAML writes a target without options as its bare name, unless it
+// was loaded as a mapping.
+func (t TestTarget) MarshalYAML() (any, error) {
+	if !t.mapping && len(t.Extra) == 0 {
+		return t.Name, nil
+	}
+	type plain TestTarget
+	return plain(t), nil
+}
+
+// UnmarshalYAML accepts both the mapping form and the bare path form of a source.
+func (s *Source) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		*s = Source{Path: node.Value, bare: true}
+		return nil
+	}
+	type plain Source
+	return node.Decode((*plain)(s))
+}
+
+// MarshalYAML writes a source loaded as a bare path back as one while only
+// its path is set.
+func (s Source) MarshalYAML() (any, error) {
+	type plain Source
+	if s.bare && reflect.DeepEqual(s, Source{Path: s.Path, bare: true}) {
+		return s.Path, nil
+	}
+	return plain(s), nil
+}
+
+// UnmarshalYAML accepts both structured settings (base/configs/groups) and the
+// flat form, where every key is a base setting.
+func (s *Settings) UnmarshalYAML(node *yaml.Node) error {
+	structured := false
+	if node.Kind == yaml.MappingNode {
+		for i := 0; i < len(node.Content); i += 2 {
+			switch node.Content[i].Value {
+			case "base", "configs", "groups", "configFiles":
+				structured = true
+			}
+		}
+	}
+	node = textScalars(node)
+	if !structured {
+		*s = Settings{flat: true}
+		return node.Decode(&s.Base)
+	}
+	type plain Settings
+	return node.Decode((*plain)(s))
+}
+
+// MarshalYAML writes settings loaded in the flat form back flat while they
+// only have base settings.
+func (s Settings) MarshalYAML() (any, error) {
+	if s.flat && len(s.Configs) == 0 && len(s.Groups) == 0 && len(s.Extra) == 0 {
+		return s.Base, nil
+	}
+	type plain Settings
+	return plain(s), nil
+}
+
+// Parse decodes project.yml content into a Spec.
+func Parse(data []byte) (*Spec, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse project.yml: %w", err)
+	}
+	var s Spec
+	if err := doc.Decode(&s); err != nil {
+		return nil, fmt.Errorf("failed to parse project.yml: %w", err)
+	}
+	if doc.Kind == yaml.DocumentNode && len(doc.Content) == 1 {
+		s.doc = &doc
+	}
+	return &s, nil
+}
+
+// Load reads and parses the project.yml at path.
+func Load(path string) (*Spec, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read project.yml: %w", err)
+	}
+	return Parse(data)
+}
+
+// Marshal encodes the spec as project.yml content. A parsed spec keeps the
+// formatting of its file.
+func (s *Spec) Marshal() ([]byte, error) {
+	var node yaml.Node
+	if err := node.Encode(s); err != nil {
+		return nil, fmt.Errorf("failed to encode project.yml: %w", err)
+	}
+	doc := &yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{&node}}
+	if s.doc != nil {
+		doc.HeadComment = s.doc.HeadComment
+		doc.LineComment = s.doc.LineComment
+		doc.FootComment = s.doc.FootComment
+		restoreFormatting(&node, s.doc.Content[0])
+	}
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(doc); err != nil {
+		return nil, fmt.Errorf("failed to encode project.yml: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return nil, fmt.Errorf("failed to encode project.yml: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// Save writes the spec to path.
+func (s *Spec) Save(path string) error {
+	data, err := s.Marshal()
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write project.yml: %w", err)
+	}
+	return nil
+}
+
+// OrderedMap is a YAML mapping that keeps its keys in insertion order, so
+// targets, schemes and build settings render in a readable order and keep the
+// order of a loaded file.
+type OrderedMap[V any] struct {
+	keys   []string
+	values map[string]V
+}
+
+// Get returns the value stored under key.
+func (m *OrderedMap[V]) Get(key string) (V, bool) {
+	v, ok := m.values[key]
+	return v, ok
+}
+
+// Set stores value under key. New keys are appended; existing keys keep their position.
+func (m *OrderedMap[V]) Set(key string, value V) {
+	if m.values == nil {
+		m.values = make(map[string]V)
+	}
+	if _, ok := m.values[key]; !ok {
+		m.keys = append(m.keys, key)
+	}
+	m.values[key] = value
+}
+
+// Delete removes key and reports whether it was present.
+func (m *OrderedMap[V]) Delete(key string) bool {
+	if _, ok := m.values[key]; !ok {
+		return false
+	}
+	delete(m.values, key)
+	for i, k := range m.keys {
+		if k == key {
+			m.keys = append(m.keys[:i], m.keys[i+1:]...)
+			break
+		}
+	}
+	return true
+}
+
+// Keys returns the keys in order.
+func (m *OrderedMap[V]) Keys() []string {
+	return append([]string(nil), m.keys...)
+}
+
+// Len returns the number of entries.
+func (m *OrderedMap[V]) Len() int {
+	return len(m.keys)
+}
+
+// IsZero reports whether the map is empty, so omitempty fields are skipped.
+func (m OrderedMap[V]) IsZero() bool {
+	return len(m.keys) == 0
+}
+
+// MarshalYAML encodes the entries as a mapping in key order.
+func (m OrderedMap[V]) MarshalYAML() (any, error) {
+	node := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	for _, key := range m.keys {
+		var value yaml.Node
+		if err := value.Encode(m.values[key]); err != nil {
+			return nil, fmt.Errorf("%s: %w", key, err)
+		}
+		node.Content = append(node.Content,
+			&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key},
+			&value,
+		)
+	}
+	return node, nil
+}
+
+// UnmarshalYAML decodes a mapping, recording its key order.
+func (m *OrderedMap[V]) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind != yaml.MappingNode {
+		return fmt.Errorf("line %d: expected a mapping", node.Line)
+	}
+	*m = OrderedMap[V]{}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		var value V
+		if err := node.Content[i+1].Decode(&value); err != nil {
+			return err
+		}
+		m.Set(node.Content[i].Value, value)
+	}
+	return nil
+}
@@ -0,0 +1,393 @@
+package xcodegen
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// handWritten is a project.yml with keys the typed model does not cover, flat
+// settings, string sources and custom ordering.
+const handWritten = `name: Legacy
+include:
+  - base.yml
+options:
+  bundleIdPrefix: com.example
+  deploymentTarget:
+    iOS: "17.0"
+  indentWidth: 2
+targets:
+  Legacy:
+    type: application
+    platform: iOS
+    sources:
+      - Legacy
+      - path: Resources
+        buildPhase:
+          copyFiles:
+            destination: resources
+    settings:
+      PRODUCT_BUNDLE_IDENTIFIER: com.example.legacy
+      OTHER_LDFLAGS: -ObjC
+    preBuildScripts:
+      - script: swiftlint
+        name: Lint
+  LegacyTests:
+    type: bundle.unit-test
+    platform: iOS
+    sources: [LegacyTests]
+    dependencies:
+      - target: Legacy
+schemes:
+  Legacy:
+    build:
+      targets:
+        Legacy: all
+    test:
+      targets: [LegacyTests]
+`
+
+func TestSpecRoundTripPreservesUnknownKeys(t *testing.T) {
+	spec, err := Parse([]byte(handWritten))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	data, err := spec.Marshal()
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	out := string(data)
+
+	for _, want := range []string{
+		"include:\n  - base.yml",
+		"indentWidth: 2",
+		"- Legacy\n",
+		"copyFiles:",
+		"PRODUCT_BUNDLE_IDENTIFIER: com.example.legacy",
+		"OTHER_LDFLAGS: -ObjC",
+		"preBuildScripts:",
+		"script: swiftlint",
+		"test:",
+		"targets: [LegacyTests]",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("round trip lost %q:\n%s", want, out)
+		}
+	}
+
+	// Targets keep their file order.
+	if got := spec.Targets.Keys(); len(got) != 2 || got[0] != "Legacy" || got[1] != "LegacyTests" {
+		t.Errorf("Targets.Keys() = %v, want [Legacy LegacyTests]", got)
+	}
+
+	// A second round trip is stable.
+	again, err := Parse(data)
+	if err != nil {
+		t.Fatalf("Parse round-tripped: %v", err)
+	}
+	data2, err := again.Marshal()
+	if err != nil {
+		t.Fatalf("Marshal round-tripped: %v", err)
+	}
+	if string(data2) != out {
+		t.Errorf("second round trip changed output:\n%s\n---\n%s", out, data2)
+	}
+}
+
+func TestHandWrittenSpecRoundTripsUnchanged(t *testing.T) {
+	input, err := os.ReadFile(filepath.Join("testdata", "hand_written.yml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	spec, err := Parse(input)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+
+	_, main, err := spec.Target("Legacy")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if main.Platform != "iOS" || len(main.Platforms) != 2 || main.Platforms[1] != "tvOS" {
+		t.Errorf("platform = %q %v, want iOS from [iOS tvOS]", main.Platform, main.Platforms)
+	}
+	for key, want := range map[string]string{"MARKETING_VERSION": "1.10", "CURRENT_PROJECT_VERSION": "007", "IPHONEOS_DEPLOYMENT_TARGET": "17.0"} {
+		if got, _ := main.Settings.Base.Get(key); got != want {
+			t.Errorf("%s = %#v, want %q", key, got, want)
+		}
+	}
+	if got, _ := spec.Settings.Base.Get("SWIFT_VERSION"); got != "6.0" {
+		t.Errorf("project SWIFT_VERSION = %#v, want \"6.0\"", got)
+	}
+	scheme, _ := spec.Schemes.Get("Legacy")
+	if len(scheme.Test.Targets) != 2 || scheme.Test.Targets[0].Name != "LegacyTests" || scheme.Test.Targets[1].Name != "LegacyUITests" {
+		t.Errorf("test targets = %+v", scheme.Test.Targets)
+	}
+
+	got, err := spec.Marshal()
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	if string(got) != string(input) {
+		t.Errorf("unedited project.yml changed on save:\n--- want ---\n%s\n--- got ---\n%s", input, got)
+	}
+}
+
+func TestHandWrittenSpecEditKeepsFormatting(t *testing.T) {
+	input, err := os.ReadFile(filepath.Join("testdata", "hand_written.yml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	spec, err := Parse(input)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if err := spec.SetBuildSetting("Legacy", "CURRENT_PROJECT_VERSION", "8"); err != nil {
+		t.Fatal(err)
+	}
+	if err := spec.AddPermission("NSCameraUsageDescription", "Scan receipts"); err != nil {
+		t.Fatal(err)
+	}
+	got, err := spec.Marshal()
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	assertGolden(t, "hand_written_edited", string(got))
+}
+
+func TestGeneratedSpecRoundTrips(t *testing.T) {
+	p := &Project{
+		AppName:       "Trips",
+		BundleID:      "com.example.trips",
+		Localizations: []string{"en", "ar"},
+		Permissions:   testPermissions,
+		Packages:      testPackages,
+		Extensions:    []Extension{{Kind: "widget"}, {Kind: "share"}},
+	}
+	want := Generate(p)
+	spec, err := Parse([]byte(want))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	got, err := spec.Marshal()
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	if string(got) != want {
+		t.Errorf("generated project.yml did not round trip:\n--- want ---\n%s\n--- got ---\n%s", want, got)
+	}
+}
+
+func TestSpecEdits(t *testing.T) {
+	spec, err := Parse([]byte(handWritten))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+
+	if got := spec.MainTargetName(); got != "Legacy" {
+		t.Fatalf("MainTargetName() = %q, want Legacy", got)
+	}
+	if err := spec.AddPermission("NSCameraUsageDescription", "Scan: receipts"); err != nil {
+		t.Fatalf("AddPermission: %v", err)
+	}
+	if err := spec.SetBuildSetting("LegacyTests", "SWIFT_VERSION", "6.0"); err != nil {
+		t.Fatalf("SetBuildSetting: %v", err)
+	}
+	if err := spec.SetBuildSetting("Missing", "SWIFT_VERSION", "6.0"); err == nil {
+		t.Error("SetBuildSetting on a missing target should fail")
+	}
+	if err := spec.SetEntitlement("", "com.apple.developer.healthkit", true); err != nil {
+		t.Fatalf("SetEntitlement: %v", err)
+	}
+	if added, err := spec.AddURLScheme("legacy"); err != nil || !added {
+		t.Fatalf("AddURLScheme = %v, %v", added, err)
+	}
+	if added, _ := spec.AddURLScheme("legacy"); added {
+		t.Error("AddURLScheme should skip a registered scheme")
+	}
+	spec.AddKnownRegions("en", "ar", "en")
+	added, err := spec.AddPackage(Package{Name: "Nuke", URL: "https://github.com/kean/Nuke", MinVersion: "12.8.0", Products: []string{"NukeUI"}})
+	if err != nil || !added {
+		t.Fatalf("AddPackage = %v, %v", added, err)
+	}
+	if added, _ := spec.AddPackage(Package{Name: "Nuke"}); added {
+		t.Error("AddPackage should skip a declared package")
+	}
+	name, err := spec.AddExtension(Extension{Kind: "widget"})
+	if err != nil {
+		t.Fatalf("AddExtension: %v", err)
+	}
+	if name != "LegacyWidget" {
+		t.Errorf("AddExtension name = %q, want LegacyWidget", name)
+	}
+	if _, err := spec.AddExtension(Extension{Kind: "widget"}); err == nil {
+		t.Error("AddExtension should reject a duplicate target")
+	}
+
+	data, err := spec.Marshal()
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	out := string(data)
+	for _, want := range []string{
+		// Flat settings stay flat, with the new key added.
+		"    settings:\n      PRODUCT_BUNDLE_IDENTIFIER: com.example.legacy\n      OTHER_LDFLAGS: -ObjC\n      INFOPLIST_KEY_NSCameraUsageDescription: 'Scan: receipts'",
+		"path: Legacy/Legacy.entitlements",
+		"com.apple.developer.healthkit: true",
+		"CFBundleURLSchemes:\n              - legacy",
+		"knownRegions:\n    - en\n    - ar\n",
+		"Nuke:\n    url: https://github.com/kean/Nuke\n    from: 12.8.0",
+		"- package: Nuke\n        product: NukeUI",
+		"LegacyWidget:\n    type: app-extension\n    platform: iOS",
+		"PRODUCT_BUNDLE_IDENTIFIER: com.example.legacy.widget",
+		"- target: LegacyWidget\n        embed: true",
+		"- group.com.example.legacy",
+		"        LegacyWidget: all",
+		"preBuildScripts:",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("edited project.yml missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestSpecRemovals(t *testing.T) {
+	spec := Build(&Project{
+		AppName:       "Trips",
+		BundleID:      "com.example.trips",
+		Localizations: []string{"en", "ar", "fr"},
+		Permissions:   testPermissions,
+		Packages:      testPackages,
+		Extensions:    []Extension{{Kind: "widget"}},
+		BuildSettings: map[string]string{"SWIFT_STRICT_CONCURRENCY": "complete"},
+	})
+
+	key := testPermissions[0].Key
+	if removed, err := spec.RemovePermission(key); err != nil || !removed {
+		t.Fatalf("RemovePermission = %v, %v", removed, err)
+	}
+	if removed, _ := spec.RemovePermission(key); removed {
+		t.Error("RemovePermission should report a missing permission")
+	}
+
+	if err := spec.RemoveExtension("Trips"); err == nil {
+		t.Error("RemoveExtension should refuse the main app target")
+	}
+	if err := spec.RemoveExtension("TripsWidget"); err != nil {
+		t.Fatalf("RemoveExtension: %v", err)
+	}
+	if removed, err := spec.RemoveEntitlement("", "com.apple.security.application-groups"); err != nil || !removed {
+		t.Fatalf("RemoveEntitlement = %v, %v", removed, err)
+	}
+
+	pkg := testPackages[0]
+	if err := spec.SetPackageVersion(pkg.Name, "99.0.0"); err != nil {
+		t.Fatalf("SetPackageVersion: %v", err)
+	}
+	if err := spec.SetPackageVersion("Missing", "1.0.0"); err == nil {
+		t.Error("SetPackageVersion on a missing package should fail")
+	}
+	if !spec.RemovePackage(testPackages[1].Name) {
+		t.Fatal("RemovePackage reported a declared package as missing")
+	}
+
+	spec.RemoveKnownRegions("fr")
+	if removed, err := spec.UnsetBuildSetting("", "SWIFT_STRICT_CONCURRENCY"); err != nil || !removed {
+		t.Fatalf("UnsetBuildSetting = %v, %v", removed, err)
+	}
+
+	data, err := spec.Marshal()
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	out := string(data)
+	for _, gone := range []string{
+		"INFOPLIST_KEY_" + key,
+		"TripsWidget",
+		"application-groups",
+		testPackages[1].URL,
+		"- fr",
+		"SWIFT_STRICT_CONCURRENCY",
+	} {
+		if strings.Contains(out, gone) {
+			t.Errorf("project.yml still contains %q:\n%s", gone, out)
+		}
+	}
+	if !strings.Contains(out, "from: 99.0.0") {
+		t.Errorf("project.yml missing updated package version:\n%s", out)
+	}
+}
+
+func TestSpecConfigurations(t *testing.T) {
+	spec, err := Parse([]byte(handWritten))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if err := spec.SetConfigSetting("Staging", "", "API_BASE_URL", "https://staging.example.com"); err == nil {
+		t.Error("SetConfigSetting should fail for an undeclared configuration")
+	}
+	if err := spec.AddConfiguration("Staging", ""); err != nil {
+		t.Fatalf("AddConfiguration: %v", err)
+	}
+	if err := spec.AddConfiguration("Staging", "debug"); err == nil {
+		t.Error("AddConfiguration should refuse a duplicate")
+	}
+	if err := spec.SetConfigSetting("Staging", "", "API_BASE_URL", "https://staging.example.com"); err != nil {
+		t.Fatalf("SetConfigSetting: %v", err)
+	}
+
+	data, err := spec.Marshal()
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	out := string(data)
+	for _, want := range []string{
+		"configs:\n  Debug: debug\n  Release: release\n  Staging: release",
+		"Staging:\n          API_BASE_URL: https://staging.example.com",
+		"Legacy Staging:",
+		"config: Staging",
+		"OTHER_LDFLAGS: -ObjC",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("project.yml missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestReplaceSetting(t *testing.T) {
+	spec, err := Parse([]byte(`name: Notes
+settings:
+  MARKETING_VERSION: "1.0"
+targets:
+  Notes:
+    type: application
+    platform: iOS
+    settings:
+      configs:
+        Release:
+          MARKETING_VERSION: "1.0"
+  NotesWidget:
+    type: app-extension
+    platform: iOS
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := spec.ReplaceSetting("MARKETING_VERSION", "1.10"); n != 2 {
+		t.Errorf("ReplaceSetting(MARKETING_VERSION) updated %d places, want 2", n)
+	}
+	if n := spec.ReplaceSetting("CURRENT_PROJECT_VERSION", 7); n != 0 {
+		t.Errorf("ReplaceSetting(CURRENT_PROJECT_VERSION) updated %d places, want 0", n)
+	}
+	data, err := spec.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Count(string(data), `MARKETING_VERSION: "1.10"`); got != 2 {
+		t.Errorf("project.yml has %d updated versions, want 2:\n%s", got, data)
+	}
+	if strings.Contains(string(data), "CURRENT_PROJECT_VERSION") {
+		t.Errorf("ReplaceSetting added a setting that was not set:\n%s", data)
+	}
+}
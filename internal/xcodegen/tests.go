@@ -0,0 +1,84 @@
+package xcodegen
+
+// UnitTestTargetName returns the unit test bundle target name for an app. It
+// is also the directory the unit test sources live in.
+func UnitTestTargetName(appName string) string {
+	return appName + "Tests"
+}
+
+// UITestTargetName returns the UI test bundle target name for an app. It is
+// also the directory the UI test sources live in.
+func UITestTargetName(appName string) string {
+	return appName + "UITests"
+}
+
+// testHost returns the application target the test bundles run against: the
+// main app target, or the watch app for standalone watchOS projects, whose main
+// target is only a container.
+func (t *specBuilder) testHost() (string, *Target) {
+	name := t.s.MainTargetName()
+	host, _ := t.s.Targets.Get(name)
+	if host != nil && host.Type == "application.watchapp2-container" {
+		name = WatchAppTargetName(t.p.AppName)
+		host, _ = t.s.Targets.Get(name)
+	}
+	return name, host
+}
+
+// writeTestTargets adds the unit and UI test bundles next to the app target and
+// lists them in the test action of the app's scheme. XcodeGen derives TEST_HOST
+// and TEST_TARGET_NAME from the dependency on the host app.
+func (t *specBuilder) writeTestTargets() {
+	uiTests := t.p.UITests && t.p.platform() != PlatformWatchOS
+	if !t.p.UnitTests && !uiTests {
+		return
+	}
+	hostName, host := t.testHost()
+	if host == nil {
+		return
+	}
+
+	var testTargets []TestTarget
+	if t.p.UnitTests {
+		name := UnitTestTargetName(t.p.AppName)
+		t.addTarget(name, newTestTarget("bundle.unit-test", host, hostName, name, t.p.BundleID+".tests"))
+		testTargets = append(testTargets, TestTarget{Name: name})
+	}
+	if uiTests {
+		name := UITestTargetName(t.p.AppName)
+		t.addTarget(name, newTestTarget("bundle.ui-testing", host, hostName, name, t.p.BundleID+".uitests"))
+		testTargets = append(testTargets, TestTarget{Name: name})
+	}
+
+	schemeName := t.s.MainTargetName()
+	scheme, ok := t.s.Schemes.Get(schemeName)
+	if !ok {
+		t.writeScheme(schemeName, nil, false)
+		scheme, _ = t.s.Schemes.Get(schemeName)
+	}
+	scheme.Test = &SchemeAction{GatherCoverageData: true, Targets: testTargets}
+}
+
+// newTestTarget returns a test bundle target for the host app's platform whose
+// sources are the folder named after the target.
+func newTestTarget(kind string, host *Target, hostName, name, bundleID string) *Target {
+	target := &Target{
+		Type:                  kind,
+		Platform:              host.Platform,
+		SupportedDestinations: host.SupportedDestinations,
+		Sources:               []Source{syncedSource(name)},
+		Settings:              &Settings{},
+		Dependencies:          []Dependency{{Target: hostName}},
+	}
+	base := &target.Settings.Base
+	base.Set("SWIFT_VERSION", "6.0")
+	base.Set("PRODUCT_BUNDLE_IDENTIFIER", bundleID)
+	base.Set("CODE_SIGN_STYLE", "Automatic")
+	base.Set("GENERATE_INFOPLIST_FILE", "YES")
+	if host.Settings != nil {
+		if family, ok := host.Settings.Base.Get("TARGETED_DEVICE_FAMILY"); ok {
+			base.Set("TARGETED_DEVICE_FAMILY", family)
+		}
+	}
+	return target
+}
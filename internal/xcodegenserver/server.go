@@ -3,12 +3,14 @@ package xcodegenserver
 import (
 	"context"
 
+	"github.com/moasq/nanowave/internal/packageregistry"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 )
 
-// Run starts the XcodeGen MCP server over stdio.
+// Run starts the XcodeGen MCP server over stdio. add_package checks packages
+// against the given registry.
 // It blocks until the client disconnects or the context is cancelled.
-func Run(ctx context.Context) error {
+func Run(ctx context.Context, packages *packageregistry.Registry) error {
 	server := mcp.NewServer(
 		&mcp.Implementation{
 			Name:    "xcodegen",
@@ -19,7 +21,7 @@ func Run(ctx context.Context) error {
 
 	mcp.AddTool(server, &mcp.Tool{
 		Name:        "add_permission",
-		Description: "Add a permission to the Xcode project. Adds the INFOPLIST_KEY entry to project.yml and regenerates the .xcodeproj. Works for iOS and watchOS projects. Example: add_permission(key: \"NSCameraUsageDescription\", description: \"Take photos for your profile\", framework: \"AVFoundation\")",
+		Description: "Add a permission to the Xcode project. Adds the INFOPLIST_KEY entry to project.yml and regenerates the .xcodeproj. Works for every platform the project targets. Example: add_permission(key: \"NSCameraUsageDescription\", description: \"Take photos for your profile\", framework: \"AVFoundation\")",
 	}, handleAddPermission)
 
 	mcp.AddTool(server, &mcp.Tool{
@@ -37,6 +39,11 @@ func Run(ctx context.Context) error {
 		Description: "Add language support to the Xcode project. Sets knownRegions, creates .lproj directories, and configures the localization resource handling in project.yml. Regenerates .xcodeproj.",
 	}, handleAddLocalization)
 
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "add_url_scheme",
+		Description: "Register a custom URL scheme for the iOS app (CFBundleURLTypes in its Info.plist) so deep links like notes://item/42 open it. Regenerates .xcodeproj. Example: add_url_scheme(scheme: \"notes\")",
+	}, handleAddURLScheme)
+
 	mcp.AddTool(server, &mcp.Tool{
 		Name:        "set_build_setting",
 		Description: "Set an arbitrary Xcode build setting on a target. Can target the main app or any extension target. Regenerates .xcodeproj after setting.",
@@ -44,12 +51,57 @@ func Run(ctx context.Context) error {
 
 	mcp.AddTool(server, &mcp.Tool{
 		Name:        "add_package",
-		Description: "Add an SPM (Swift Package Manager) dependency to the Xcode project. Adds the package to the top-level packages section and as a dependency of the main app target, then regenerates .xcodeproj. Example: add_package(name: \"Lottie\", url: \"https://github.com/airbnb/lottie-ios\", min_version: \"4.0.0\")",
-	}, handleAddPackage)
+		Description: "Add an SPM (Swift Package Manager) dependency to the Xcode project. Adds the package to the top-level packages section and as a dependency of the main app target, then regenerates .xcodeproj. Packages in the curated registry default to its approved version, and a version outside it is refused. Pin with exact_version, or bound min_version with max_version. Example: add_package(name: \"Lottie\", url: \"https://github.com/airbnb/lottie-ios\", min_version: \"4.0.0\")",
+	}, addPackageHandler(packages))
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "remove_permission",
+		Description: "Remove a permission (Info.plist usage description) from the Xcode project and regenerate .xcodeproj. Remove the code that requests the permission as well.",
+	}, handleRemovePermission)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "remove_extension",
+		Description: "Remove an extension target from the Xcode project, along with its embed dependency and any entitlements scoped to it, then regenerate .xcodeproj. The extension's source folder is left on disk.",
+	}, handleRemoveExtension)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "remove_entitlement",
+		Description: "Remove an entitlement from a target and regenerate .xcodeproj. Fails for entitlements an extension requires, e.g. the App Group used by a widget — remove the extension first.",
+	}, handleRemoveEntitlement)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "remove_package",
+		Description: "Remove an SPM package and its product dependencies from the Xcode project, then regenerate .xcodeproj. Fails while Swift files still import one of its products.",
+	}, handleRemovePackage)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "update_package_version",
+		Description: "Change the minimum version of an SPM package (resolved up to the next major version) and regenerate .xcodeproj. Example: update_package_version(name: \"Lottie\", version: \"4.5.0\")",
+	}, handleUpdatePackageVersion)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "remove_localization",
+		Description: "Remove languages from knownRegions and regenerate .xcodeproj. English (en) cannot be removed. .lproj directories are left on disk.",
+	}, handleRemoveLocalization)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "unset_build_setting",
+		Description: "Remove a build setting previously set on a target (main app or extension) and regenerate .xcodeproj.",
+	}, handleUnsetBuildSetting)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "add_configuration",
+		Description: "Add a build configuration (e.g. Staging, Beta) next to Debug and Release. Each configuration is backed by Configs/{Name}.xcconfig with its own bundle ID suffix, display name, app icon and backend URL, and gets a \"{App} {Name}\" scheme. Regenerates .xcodeproj. Example: add_configuration(name: \"Staging\", backend_url: \"https://staging.api.example.com\")",
+	}, handleAddConfiguration)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "set_configuration_value",
+		Description: "Set a per-configuration value: bundle_id_suffix, display_name, app_icon, backend_url, or a custom xcconfig variable in UPPER_SNAKE_CASE. Works for Debug and Release too. Regenerates .xcodeproj. Example: set_configuration_value(configuration: \"Release\", key: \"backend_url\", value: \"https://api.example.com\")",
+	}, handleSetConfigurationValue)
 
 	mcp.AddTool(server, &mcp.Tool{
 		Name:        "get_project_config",
-		Description: "Get the current Xcode project configuration. Returns all targets, permissions, extensions, entitlements, localizations, packages, and build settings. Read-only — does not run xcodegen.",
+		Description: "Get the current Xcode project configuration. Returns all targets, permissions, extensions, entitlements, localizations, packages, and build settings (or the project.yml itself for projects without project_config.json). Read-only — does not run xcodegen.",
 	}, handleGetProjectConfig)
 
 	mcp.AddTool(server, &mcp.Tool{
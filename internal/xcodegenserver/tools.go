@@ -1,14 +1,22 @@
 package xcodegenserver
 
 import (
+	"cmp"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io/fs"
+	"maps"
 	"os"
-	"os/exec"
 	"path/filepath"
+	"regexp"
+	"slices"
 	"strings"
 
+	"github.com/moasq/nanowave/internal/packageregistry"
+	"github.com/moasq/nanowave/internal/projectconfig"
+	"github.com/moasq/nanowave/internal/xcodegen"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 )
 
@@ -29,7 +37,17 @@ func handleAddPermission(ctx context.Context, req *mcp.CallToolRequest, input ad
 		return nil, textOutput{}, fmt.Errorf("failed to get working directory: %w", err)
 	}
 
-	cfg, err := loadConfig(workDir)
+	if !projectconfig.GeneratesSpec(workDir) {
+		err := projectconfig.EditSpec(workDir, func(spec *xcodegen.Spec) error {
+			return spec.AddPermission(input.Key, input.Description)
+		})
+		if err != nil {
+			return nil, textOutput{}, err
+		}
+		return nil, textOutput{Message: fmt.Sprintf("Added permission %s (%s). project.yml edited and xcodegen regenerated.", input.Key, input.Framework)}, nil
+	}
+
+	cfg, err := projectconfig.Load(workDir)
 	if err != nil {
 		return nil, textOutput{}, err
 	}
@@ -41,13 +59,13 @@ func handleAddPermission(ctx context.Context, req *mcp.CallToolRequest, input ad
 		}
 	}
 
-	cfg.Permissions = append(cfg.Permissions, Permission{
+	cfg.Permissions = append(cfg.Permissions, projectconfig.Permission{
 		Key:         input.Key,
 		Description: input.Description,
 		Framework:   input.Framework,
 	})
 
-	if err := applyAndRegenerate(workDir, cfg); err != nil {
+	if err := projectconfig.ApplyAndRegenerate(workDir, cfg); err != nil {
 		return nil, textOutput{}, err
 	}
 
@@ -56,7 +74,7 @@ func handleAddPermission(ctx context.Context, req *mcp.CallToolRequest, input ad
 
 // addExtensionInput is the input for the add_extension tool.
 type addExtensionInput struct {
-	Kind    string `json:"kind" jsonschema:"Extension type: widget live_activity share notification_service safari app_clip"`
+	Kind    string `json:"kind" jsonschema:"Extension type: widget control_widget live_activity app_intents share notification_service notification_content spotlight safari app_clip"`
 	Name    string `json:"name" jsonschema:"Target name e.g. MyAppWidget. If empty a default name is generated."`
 	Purpose string `json:"purpose" jsonschema:"What this extension does e.g. Shows daily summary on home screen"`
 }
@@ -67,7 +85,23 @@ func handleAddExtension(ctx context.Context, req *mcp.CallToolRequest, input add
 		return nil, textOutput{}, fmt.Errorf("failed to get working directory: %w", err)
 	}
 
-	cfg, err := loadConfig(workDir)
+	if !projectconfig.GeneratesSpec(workDir) {
+		var name string
+		err := projectconfig.EditSpec(workDir, func(spec *xcodegen.Spec) error {
+			var err error
+			name, err = spec.AddExtension(xcodegen.Extension{Kind: input.Kind, Name: input.Name})
+			if err != nil {
+				return err
+			}
+			return scaffoldExtensionDirs(workDir, name)
+		})
+		if err != nil {
+			return nil, textOutput{}, err
+		}
+		return nil, textOutput{Message: fmt.Sprintf("Added %s extension '%s'. Created Targets/%s/ and Shared/ directories. project.yml edited and xcodegen regenerated.", input.Kind, name, name)}, nil
+	}
+
+	cfg, err := projectconfig.Load(workDir)
 	if err != nil {
 		return nil, textOutput{}, err
 	}
@@ -78,20 +112,21 @@ func handleAddExtension(ctx context.Context, req *mcp.CallToolRequest, input add
 		unsupported := map[string]bool{
 			"live_activity": true, "share": true,
 			"notification_service": true, "safari": true, "app_clip": true,
+			"spotlight": true, "notification_content": true,
 		}
 		if unsupported[input.Kind] {
-			return nil, textOutput{}, fmt.Errorf("extension kind %q is not supported on watchOS (only widget is supported)", input.Kind)
+			return nil, textOutput{}, fmt.Errorf("extension kind %q is not supported on watchOS (widget, control_widget and app_intents are supported)", input.Kind)
 		}
 	}
 
-	ext := ExtensionPlan{
+	ext := projectconfig.ExtensionPlan{
 		Kind:    input.Kind,
 		Name:    input.Name,
 		Purpose: input.Purpose,
 	}
 
 	// Generate default name if not provided
-	name := extensionTargetName(ext, cfg.AppName)
+	name := projectconfig.ExtensionTargetName(ext, cfg.AppName)
 	ext.Name = name
 
 	// Check for duplicate
@@ -103,27 +138,11 @@ func handleAddExtension(ctx context.Context, req *mcp.CallToolRequest, input add
 
 	cfg.Extensions = append(cfg.Extensions, ext)
 
-	// Scaffold directories
-	sourcePath := filepath.Join(workDir, "Targets", name)
-	sharedPath := filepath.Join(workDir, "Shared")
-	for _, dir := range []string{sourcePath, sharedPath} {
-		if err := os.MkdirAll(dir, 0o755); err != nil {
-			return nil, textOutput{}, fmt.Errorf("failed to create directory %s: %w", dir, err)
-		}
-	}
-
-	// Write placeholder files so xcodegen doesn't complain
-	placeholder := []byte("// Placeholder — replaced by generated code\nimport Foundation\n")
-	for _, dir := range []string{sourcePath, sharedPath} {
-		p := filepath.Join(dir, "Placeholder.swift")
-		if _, err := os.Stat(p); os.IsNotExist(err) {
-			if err := os.WriteFile(p, placeholder, 0o644); err != nil {
-				return nil, textOutput{}, fmt.Errorf("failed to write placeholder: %w", err)
-			}
-		}
+	if err := scaffoldExtensionDirs(workDir, name); err != nil {
+		return nil, textOutput{}, err
 	}
 
-	if err := applyAndRegenerate(workDir, cfg); err != nil {
+	if err := projectconfig.ApplyAndRegenerate(workDir, cfg); err != nil {
 		return nil, textOutput{}, err
 	}
 
@@ -143,7 +162,17 @@ func handleAddEntitlement(ctx context.Context, req *mcp.CallToolRequest, input a
 		return nil, textOutput{}, fmt.Errorf("failed to get working directory: %w", err)
 	}
 
-	cfg, err := loadConfig(workDir)
+	if !projectconfig.GeneratesSpec(workDir) {
+		err := projectconfig.EditSpec(workDir, func(spec *xcodegen.Spec) error {
+			return spec.SetEntitlement(input.Target, input.Key, input.Value)
+		})
+		if err != nil {
+			return nil, textOutput{}, err
+		}
+		return nil, textOutput{Message: fmt.Sprintf("Set entitlement %s. project.yml edited and xcodegen regenerated.", input.Key)}, nil
+	}
+
+	cfg, err := projectconfig.Load(workDir)
 	if err != nil {
 		return nil, textOutput{}, err
 	}
@@ -152,20 +181,20 @@ func handleAddEntitlement(ctx context.Context, req *mcp.CallToolRequest, input a
 	for i, e := range cfg.Entitlements {
 		if e.Key == input.Key && e.Target == input.Target {
 			cfg.Entitlements[i].Value = input.Value
-			if err := applyAndRegenerate(workDir, cfg); err != nil {
+			if err := projectconfig.ApplyAndRegenerate(workDir, cfg); err != nil {
 				return nil, textOutput{}, err
 			}
 			return nil, textOutput{Message: fmt.Sprintf("Updated entitlement %s. project.yml updated and xcodegen regenerated.", input.Key)}, nil
 		}
 	}
 
-	cfg.Entitlements = append(cfg.Entitlements, Entitlement{
+	cfg.Entitlements = append(cfg.Entitlements, projectconfig.Entitlement{
 		Key:    input.Key,
 		Value:  input.Value,
 		Target: input.Target,
 	})
 
-	if err := applyAndRegenerate(workDir, cfg); err != nil {
+	if err := projectconfig.ApplyAndRegenerate(workDir, cfg); err != nil {
 		return nil, textOutput{}, err
 	}
 
@@ -187,7 +216,33 @@ func handleAddLocalization(ctx context.Context, req *mcp.CallToolRequest, input
 		return nil, textOutput{}, fmt.Errorf("failed to get working directory: %w", err)
 	}
 
-	cfg, err := loadConfig(workDir)
+	if !projectconfig.GeneratesSpec(workDir) {
+		var regions []string
+		err := projectconfig.EditSpec(workDir, func(spec *xcodegen.Spec) error {
+			name, target, err := spec.Target("")
+			if err != nil {
+				return err
+			}
+			spec.AddKnownRegions(append([]string{"en"}, input.Languages...)...)
+			regions = spec.Options.KnownRegions
+			dir := name
+			if len(target.Sources) > 0 {
+				dir = target.Sources[0].Path
+			}
+			for _, lang := range regions {
+				if err := os.MkdirAll(filepath.Join(workDir, dir, lang+".lproj"), 0o755); err != nil {
+					return fmt.Errorf("failed to create %s.lproj: %w", lang, err)
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, textOutput{}, err
+		}
+		return nil, textOutput{Message: fmt.Sprintf("Localization set to %s. Created .lproj directories and updated knownRegions in project.yml. xcodegen regenerated.", strings.Join(regions, ", "))}, nil
+	}
+
+	cfg, err := projectconfig.Load(workDir)
 	if err != nil {
 		return nil, textOutput{}, err
 	}
@@ -226,13 +281,60 @@ func handleAddLocalization(ctx context.Context, req *mcp.CallToolRequest, input
 		}
 	}
 
-	if err := applyAndRegenerate(workDir, cfg); err != nil {
+	if err := projectconfig.ApplyAndRegenerate(workDir, cfg); err != nil {
 		return nil, textOutput{}, err
 	}
 
 	return nil, textOutput{Message: fmt.Sprintf("Localization set to %s. Created .lproj directories and updated knownRegions in project.yml. xcodegen regenerated.", strings.Join(cfg.Localizations, ", "))}, nil
 }
 
+// addURLSchemeInput is the input for the add_url_scheme tool.
+type addURLSchemeInput struct {
+	Scheme string `json:"scheme" jsonschema:"Custom URL scheme without :// e.g. notes for notes://item/42"`
+}
+
+func handleAddURLScheme(ctx context.Context, req *mcp.CallToolRequest, input addURLSchemeInput) (*mcp.CallToolResult, textOutput, error) {
+	workDir, err := os.Getwd()
+	if err != nil {
+		return nil, textOutput{}, fmt.Errorf("failed to get working directory: %w", err)
+	}
+	scheme := strings.TrimSuffix(input.Scheme, "://")
+	if !projectconfig.ValidURLScheme(scheme) {
+		return nil, textOutput{}, fmt.Errorf("URL scheme %q must start with a letter and contain only letters, digits, +, - and .", scheme)
+	}
+
+	if !projectconfig.GeneratesSpec(workDir) {
+		added := false
+		err := projectconfig.EditSpec(workDir, func(spec *xcodegen.Spec) error {
+			var err error
+			added, err = spec.AddURLScheme(scheme)
+			return err
+		})
+		if err != nil {
+			return nil, textOutput{}, err
+		}
+		if !added {
+			return nil, textOutput{Message: fmt.Sprintf("URL scheme %s is already registered", scheme)}, nil
+		}
+		return nil, textOutput{Message: fmt.Sprintf("Registered URL scheme %s:// in the app's Info.plist. project.yml edited and xcodegen regenerated.", scheme)}, nil
+	}
+
+	cfg, err := projectconfig.Load(workDir)
+	if err != nil {
+		return nil, textOutput{}, err
+	}
+	if slices.Contains(cfg.URLSchemes, scheme) {
+		return nil, textOutput{Message: fmt.Sprintf("URL scheme %s is already registered", scheme)}, nil
+	}
+	cfg.URLSchemes = append(cfg.URLSchemes, scheme)
+
+	if err := projectconfig.ApplyAndRegenerate(workDir, cfg); err != nil {
+		return nil, textOutput{}, err
+	}
+
+	return nil, textOutput{Message: fmt.Sprintf("Registered URL scheme %s:// in the app's Info.plist. project.yml updated and xcodegen regenerated. Handle incoming URLs with .onOpenURL on the root view.", scheme)}, nil
+}
+
 // setBuildSettingInput is the input for the set_build_setting tool.
 type setBuildSettingInput struct {
 	Target string `json:"target" jsonschema:"Target name. Empty or omitted means the main app target."`
@@ -246,7 +348,17 @@ func handleSetBuildSetting(ctx context.Context, req *mcp.CallToolRequest, input
 		return nil, textOutput{}, fmt.Errorf("failed to get working directory: %w", err)
 	}
 
-	cfg, err := loadConfig(workDir)
+	if !projectconfig.GeneratesSpec(workDir) {
+		err := projectconfig.EditSpec(workDir, func(spec *xcodegen.Spec) error {
+			return spec.SetBuildSetting(input.Target, input.Key, input.Value)
+		})
+		if err != nil {
+			return nil, textOutput{}, err
+		}
+		return nil, textOutput{Message: fmt.Sprintf("Set %s = %s. project.yml edited and xcodegen regenerated.", input.Key, input.Value)}, nil
+	}
+
+	cfg, err := projectconfig.Load(workDir)
 	if err != nil {
 		return nil, textOutput{}, err
 	}
@@ -274,7 +386,7 @@ func handleSetBuildSetting(ctx context.Context, req *mcp.CallToolRequest, input
 		}
 	}
 
-	if err := applyAndRegenerate(workDir, cfg); err != nil {
+	if err := projectconfig.ApplyAndRegenerate(workDir, cfg); err != nil {
 		return nil, textOutput{}, err
 	}
 
@@ -294,7 +406,15 @@ func handleGetProjectConfig(ctx context.Context, req *mcp.CallToolRequest, input
 		return nil, textOutput{}, fmt.Errorf("failed to get working directory: %w", err)
 	}
 
-	cfg, err := loadConfig(workDir)
+	if !projectconfig.GeneratesSpec(workDir) {
+		data, err := os.ReadFile(filepath.Join(workDir, "project.yml"))
+		if err != nil {
+			return nil, textOutput{}, fmt.Errorf("failed to read project.yml: %w", err)
+		}
+		return nil, textOutput{Message: "No project_config.json; project.yml is edited in place.\n\nproject.yml:\n" + string(data)}, nil
+	}
+
+	cfg, err := projectconfig.Load(workDir)
 	if err != nil {
 		return nil, textOutput{}, err
 	}
@@ -346,7 +466,25 @@ func handleGetProjectConfig(ctx context.Context, req *mcp.CallToolRequest, input
 	if len(cfg.Packages) > 0 {
 		summary.WriteString(fmt.Sprintf("Packages: %d\n", len(cfg.Packages)))
 		for _, p := range cfg.Packages {
-			summary.WriteString(fmt.Sprintf("  - %s (%s)\n", p.Name, p.URL))
+			source := p.URL
+			if p.Path != "" {
+				source = p.Path
+			}
+			if p.Resolved != "" {
+				source += ", locked at " + p.Resolved
+			}
+			summary.WriteString(fmt.Sprintf("  - %s (%s)\n", p.Name, source))
+		}
+	}
+
+	if len(cfg.URLSchemes) > 0 {
+		summary.WriteString(fmt.Sprintf("URL schemes: %s\n", strings.Join(cfg.URLSchemes, ", ")))
+	}
+
+	if len(cfg.Configurations) > 0 {
+		summary.WriteString(fmt.Sprintf("Configurations: %d\n", len(cfg.Configurations)))
+		for _, c := range cfg.Configurations {
+			summary.WriteString(fmt.Sprintf("  - %s (Configs/%s.xcconfig)\n", c.Name, c.Name))
 		}
 	}
 
@@ -358,101 +496,774 @@ func handleGetProjectConfig(ctx context.Context, req *mcp.CallToolRequest, input
 
 // addPackageInput is the input for the add_package tool.
 type addPackageInput struct {
-	Name       string   `json:"name" jsonschema:"Package name e.g. Lottie or SDWebImageSwiftUI"`
-	URL        string   `json:"url" jsonschema:"Git repository URL e.g. https://github.com/airbnb/lottie-ios"`
-	MinVersion string   `json:"min_version" jsonschema:"Minimum version e.g. 4.0.0"`
-	Products   []string `json:"products" jsonschema:"Product names to import. If omitted defaults to package name."`
+	Name         string   `json:"name" jsonschema:"Package name e.g. Lottie or SDWebImageSwiftUI"`
+	URL          string   `json:"url" jsonschema:"Git repository URL e.g. https://github.com/airbnb/lottie-ios"`
+	MinVersion   string   `json:"min_version,omitempty" jsonschema:"Minimum version e.g. 4.0.0. Resolves up to the next major version unless max_version is set."`
+	ExactVersion string   `json:"exact_version,omitempty" jsonschema:"Pin exactly this version instead of min_version e.g. 4.4.3"`
+	MaxVersion   string   `json:"max_version,omitempty" jsonschema:"Exclusive upper bound for min_version e.g. 4.6.0"`
+	Products     []string `json:"products" jsonschema:"Product names to import. If omitted defaults to package name."`
+}
+
+// addPackageHandler returns the add_package handler. Packages in the registry
+// default to the version it approves and are refused a version outside it.
+func addPackageHandler(packages *packageregistry.Registry) mcp.ToolHandlerFor[addPackageInput, textOutput] {
+	return func(ctx context.Context, req *mcp.CallToolRequest, input addPackageInput) (*mcp.CallToolResult, textOutput, error) {
+		workDir, err := os.Getwd()
+		if err != nil {
+			return nil, textOutput{}, fmt.Errorf("failed to get working directory: %w", err)
+		}
+
+		if !strings.HasPrefix(input.URL, "https://") {
+			return nil, textOutput{}, fmt.Errorf("package URL must start with https://")
+		}
+
+		requirement := packageregistry.Requirement{MinVersion: input.MinVersion, ExactVersion: input.ExactVersion, MaxVersion: input.MaxVersion}
+		if curated := packages.LookupByURL(input.URL); curated != nil {
+			if requirement == (packageregistry.Requirement{}) {
+				requirement = curated.Requirement()
+			}
+			if err := curated.Admits(requirement); err != nil {
+				return nil, textOutput{}, err
+			}
+		} else if err := requirement.Validate(); err != nil {
+			return nil, textOutput{}, err
+		}
+
+		if !projectconfig.GeneratesSpec(workDir) {
+			added := false
+			err := projectconfig.EditSpec(workDir, func(spec *xcodegen.Spec) error {
+				var err error
+				added, err = spec.AddPackage(xcodegen.Package{
+					Name:         input.Name,
+					URL:          input.URL,
+					MinVersion:   requirement.MinVersion,
+					ExactVersion: requirement.ExactVersion,
+					MaxVersion:   requirement.MaxVersion,
+					Products:     input.Products,
+				})
+				return err
+			})
+			if err != nil {
+				return nil, textOutput{}, err
+			}
+			if !added {
+				return nil, textOutput{Message: fmt.Sprintf("Package %s already exists", input.Name)}, nil
+			}
+			return nil, textOutput{Message: fmt.Sprintf("Added SPM package %s (%s, %s). project.yml edited and xcodegen regenerated. Import the package in your Swift files.", input.Name, input.URL, requirement)}, nil
+		}
+
+		cfg, err := projectconfig.Load(workDir)
+		if err != nil {
+			return nil, textOutput{}, err
+		}
+
+		// Check for duplicate
+		for _, p := range cfg.Packages {
+			if p.Name == input.Name {
+				return nil, textOutput{Message: fmt.Sprintf("Package %s already exists", input.Name)}, nil
+			}
+		}
+
+		cfg.Packages = append(cfg.Packages, projectconfig.PackageDep{
+			Name:         input.Name,
+			URL:          input.URL,
+			MinVersion:   requirement.MinVersion,
+			ExactVersion: requirement.ExactVersion,
+			MaxVersion:   requirement.MaxVersion,
+			Products:     input.Products,
+		})
+
+		if err := projectconfig.ApplyAndRegenerate(workDir, cfg); err != nil {
+			return nil, textOutput{}, err
+		}
+
+		return nil, textOutput{Message: fmt.Sprintf("Added SPM package %s (%s, %s). project.yml updated and xcodegen regenerated. Import the package in your Swift files.", input.Name, input.URL, requirement)}, nil
+	}
 }
 
-func handleAddPackage(ctx context.Context, req *mcp.CallToolRequest, input addPackageInput) (*mcp.CallToolResult, textOutput, error) {
+// regenerateProjectInput is the input for the regenerate_project tool (no inputs needed).
+type regenerateProjectInput struct{}
+
+func handleRegenerateProject(ctx context.Context, req *mcp.CallToolRequest, input regenerateProjectInput) (*mcp.CallToolResult, textOutput, error) {
 	workDir, err := os.Getwd()
 	if err != nil {
 		return nil, textOutput{}, fmt.Errorf("failed to get working directory: %w", err)
 	}
 
-	if !strings.HasPrefix(input.URL, "https://") {
-		return nil, textOutput{}, fmt.Errorf("package URL must start with https://")
+	if !projectconfig.GeneratesSpec(workDir) {
+		if err := projectconfig.RunXcodeGen(workDir); err != nil {
+			return nil, textOutput{}, err
+		}
+		return nil, textOutput{Message: "xcodegen completed successfully from the existing project.yml. .xcodeproj regenerated."}, nil
 	}
 
-	cfg, err := loadConfig(workDir)
+	// Regenerate project.yml from project_config.json (preserving entitlements, packages, etc.)
+	// before running xcodegen, matching what ApplyAndRegenerate does.
+	cfg, err := projectconfig.Load(workDir)
 	if err != nil {
 		return nil, textOutput{}, err
 	}
 
-	// Check for duplicate
-	for _, p := range cfg.Packages {
-		if p.Name == input.Name {
-			return nil, textOutput{Message: fmt.Sprintf("Package %s already exists", input.Name)}, nil
+	if err := projectconfig.Regenerate(workDir, cfg); err != nil {
+		return nil, textOutput{}, err
+	}
+
+	return nil, textOutput{Message: "project.yml regenerated from project_config.json and xcodegen completed successfully. .xcodeproj regenerated."}, nil
+}
+
+// scaffoldExtensionDirs creates Targets/{name}/ and Shared/ with placeholder
+// sources so xcodegen doesn't complain about empty folders.
+func scaffoldExtensionDirs(workDir, name string) error {
+	sourcePath := filepath.Join(workDir, "Targets", name)
+	sharedPath := filepath.Join(workDir, "Shared")
+	for _, dir := range []string{sourcePath, sharedPath} {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return fmt.Errorf("failed to create directory %s: %w", dir, err)
+		}
+	}
+
+	placeholder := []byte("// Placeholder — replaced by generated code\nimport Foundation\n")
+	for _, dir := range []string{sourcePath, sharedPath} {
+		p := filepath.Join(dir, "Placeholder.swift")
+		if _, err := os.Stat(p); os.IsNotExist(err) {
+			if err := os.WriteFile(p, placeholder, 0o644); err != nil {
+				return fmt.Errorf("failed to write placeholder: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
+// removePermissionInput is the input for the remove_permission tool.
+type removePermissionInput struct {
+	Key string `json:"key" jsonschema:"Info.plist permission key to remove e.g. NSCameraUsageDescription"`
+}
+
+func handleRemovePermission(ctx context.Context, req *mcp.CallToolRequest, input removePermissionInput) (*mcp.CallToolResult, textOutput, error) {
+	workDir, err := os.Getwd()
+	if err != nil {
+		return nil, textOutput{}, fmt.Errorf("failed to get working directory: %w", err)
+	}
+
+	if !projectconfig.GeneratesSpec(workDir) {
+		err := projectconfig.EditSpec(workDir, func(spec *xcodegen.Spec) error {
+			removed, err := spec.RemovePermission(input.Key)
+			if err == nil && !removed {
+				err = errNotFound
+			}
+			return err
+		})
+		if err == errNotFound {
+			return nil, textOutput{Message: fmt.Sprintf("Permission %s is not declared", input.Key)}, nil
+		}
+		if err != nil {
+			return nil, textOutput{}, err
+		}
+		return nil, textOutput{Message: fmt.Sprintf("Removed permission %s. project.yml edited and xcodegen regenerated.", input.Key)}, nil
+	}
+
+	cfg, err := projectconfig.Load(workDir)
+	if err != nil {
+		return nil, textOutput{}, err
+	}
+
+	before := len(cfg.Permissions)
+	cfg.Permissions = slices.DeleteFunc(cfg.Permissions, func(p projectconfig.Permission) bool { return p.Key == input.Key })
+	if len(cfg.Permissions) == before {
+		return nil, textOutput{Message: fmt.Sprintf("Permission %s is not declared", input.Key)}, nil
+	}
+
+	if err := projectconfig.ApplyAndRegenerate(workDir, cfg); err != nil {
+		return nil, textOutput{}, err
+	}
+
+	return nil, textOutput{Message: fmt.Sprintf("Removed permission %s. project.yml updated and xcodegen regenerated. Remove any code that requests it.", input.Key)}, nil
+}
+
+// removeExtensionInput is the input for the remove_extension tool.
+type removeExtensionInput struct {
+	Name string `json:"name" jsonschema:"Extension target name e.g. MyAppWidget"`
+}
+
+func handleRemoveExtension(ctx context.Context, req *mcp.CallToolRequest, input removeExtensionInput) (*mcp.CallToolResult, textOutput, error) {
+	workDir, err := os.Getwd()
+	if err != nil {
+		return nil, textOutput{}, fmt.Errorf("failed to get working directory: %w", err)
+	}
+
+	if !projectconfig.GeneratesSpec(workDir) {
+		err := projectconfig.EditSpec(workDir, func(spec *xcodegen.Spec) error {
+			return spec.RemoveExtension(input.Name)
+		})
+		if err != nil {
+			return nil, textOutput{}, err
 		}
+		return nil, textOutput{Message: fmt.Sprintf("Removed extension target %s. project.yml edited and xcodegen regenerated. Its source folder was left on disk; delete it if the code is no longer needed.", input.Name)}, nil
 	}
 
-	cfg.Packages = append(cfg.Packages, PackageDep{
-		Name:       input.Name,
-		URL:        input.URL,
-		MinVersion: input.MinVersion,
-		Products:   input.Products,
+	cfg, err := projectconfig.Load(workDir)
+	if err != nil {
+		return nil, textOutput{}, err
+	}
+
+	index := slices.IndexFunc(cfg.Extensions, func(e projectconfig.ExtensionPlan) bool {
+		return projectconfig.ExtensionTargetName(e, cfg.AppName) == input.Name
+	})
+	if index < 0 {
+		return nil, textOutput{}, fmt.Errorf("extension %s not found", input.Name)
+	}
+	cfg.Extensions = slices.Delete(cfg.Extensions, index, index+1)
+
+	// Entitlements scoped to the extension go with it.
+	var dropped []string
+	cfg.Entitlements = slices.DeleteFunc(cfg.Entitlements, func(e projectconfig.Entitlement) bool {
+		if e.Target == input.Name {
+			dropped = append(dropped, e.Key)
+			return true
+		}
+		return false
 	})
 
-	if err := applyAndRegenerate(workDir, cfg); err != nil {
+	if err := projectconfig.ApplyAndRegenerate(workDir, cfg); err != nil {
 		return nil, textOutput{}, err
 	}
 
-	return nil, textOutput{Message: fmt.Sprintf("Added SPM package %s (%s). project.yml updated and xcodegen regenerated. Import the package in your Swift files.", input.Name, input.URL)}, nil
+	msg := fmt.Sprintf("Removed extension %s. project.yml updated and xcodegen regenerated.", input.Name)
+	if len(dropped) > 0 {
+		msg += fmt.Sprintf(" Also removed its entitlements: %s.", strings.Join(dropped, ", "))
+	}
+	msg += fmt.Sprintf(" Targets/%s/ was left on disk; delete it if the code is no longer needed.", input.Name)
+	return nil, textOutput{Message: msg}, nil
 }
 
-// regenerateProjectInput is the input for the regenerate_project tool (no inputs needed).
-type regenerateProjectInput struct{}
+// removeEntitlementInput is the input for the remove_entitlement tool.
+type removeEntitlementInput struct {
+	Target string `json:"target" jsonschema:"Target name to remove the entitlement from. Empty or omitted means the main app target."`
+	Key    string `json:"key" jsonschema:"Entitlement key e.g. com.apple.developer.healthkit"`
+}
 
-func handleRegenerateProject(ctx context.Context, req *mcp.CallToolRequest, input regenerateProjectInput) (*mcp.CallToolResult, textOutput, error) {
+func handleRemoveEntitlement(ctx context.Context, req *mcp.CallToolRequest, input removeEntitlementInput) (*mcp.CallToolResult, textOutput, error) {
 	workDir, err := os.Getwd()
 	if err != nil {
 		return nil, textOutput{}, fmt.Errorf("failed to get working directory: %w", err)
 	}
 
-	// Regenerate project.yml from project_config.json (preserving entitlements, packages, etc.)
-	// before running xcodegen, matching what applyAndRegenerate does.
-	cfg, err := loadConfig(workDir)
+	if !projectconfig.GeneratesSpec(workDir) {
+		err := projectconfig.EditSpec(workDir, func(spec *xcodegen.Spec) error {
+			removed, err := spec.RemoveEntitlement(input.Target, input.Key)
+			if err == nil && !removed {
+				err = errNotFound
+			}
+			return err
+		})
+		if err == errNotFound {
+			return nil, textOutput{Message: fmt.Sprintf("Entitlement %s is not set on that target", input.Key)}, nil
+		}
+		if err != nil {
+			return nil, textOutput{}, err
+		}
+		return nil, textOutput{Message: fmt.Sprintf("Removed entitlement %s. project.yml edited and xcodegen regenerated.", input.Key)}, nil
+	}
+
+	cfg, err := projectconfig.Load(workDir)
 	if err != nil {
 		return nil, textOutput{}, err
 	}
 
-	yml := generateProjectYAML(cfg)
-	if err := os.WriteFile(filepath.Join(workDir, "project.yml"), []byte(yml), 0o644); err != nil {
-		return nil, textOutput{}, fmt.Errorf("failed to write project.yml: %w", err)
+	// Entitlements the generator adds on its own would come straight back.
+	isMain := input.Target == "" || input.Target == cfg.AppName
+	if isMain && input.Key == "com.apple.security.application-groups" {
+		if ext := projectconfig.AppGroupDependent(cfg); ext != "" {
+			return nil, textOutput{}, fmt.Errorf("the app group is required by extension %s, which shares data with the app; remove the extension first", ext)
+		}
+	}
+	for _, ext := range cfg.Extensions {
+		if projectconfig.ExtensionTargetName(ext, cfg.AppName) == input.Target && slices.Contains(xcodegen.DefaultEntitlementKeys(ext.Kind), input.Key) {
+			return nil, textOutput{}, fmt.Errorf("%s is required by every %s extension and cannot be removed from %s", input.Key, ext.Kind, input.Target)
+		}
 	}
 
-	if err := runXcodeGen(workDir); err != nil {
+	before := len(cfg.Entitlements)
+	cfg.Entitlements = slices.DeleteFunc(cfg.Entitlements, func(e projectconfig.Entitlement) bool {
+		sameTarget := e.Target == input.Target || (isMain && (e.Target == "" || e.Target == cfg.AppName))
+		return sameTarget && e.Key == input.Key
+	})
+	if len(cfg.Entitlements) == before {
+		return nil, textOutput{Message: fmt.Sprintf("Entitlement %s is not set on that target", input.Key)}, nil
+	}
+
+	if err := projectconfig.ApplyAndRegenerate(workDir, cfg); err != nil {
 		return nil, textOutput{}, err
 	}
 
-	return nil, textOutput{Message: "project.yml regenerated from project_config.json and xcodegen completed successfully. .xcodeproj regenerated."}, nil
+	return nil, textOutput{Message: fmt.Sprintf("Removed entitlement %s. project.yml updated and xcodegen regenerated.", input.Key)}, nil
+}
+
+// removePackageInput is the input for the remove_package tool.
+type removePackageInput struct {
+	Name string `json:"name" jsonschema:"Package name as passed to add_package e.g. Lottie"`
 }
 
-// applyAndRegenerate saves the config, generates project.yml, and runs xcodegen.
-func applyAndRegenerate(workDir string, cfg *ProjectConfig) error {
-	if err := saveConfig(workDir, cfg); err != nil {
-		return fmt.Errorf("failed to save config: %w", err)
+func handleRemovePackage(ctx context.Context, req *mcp.CallToolRequest, input removePackageInput) (*mcp.CallToolResult, textOutput, error) {
+	workDir, err := os.Getwd()
+	if err != nil {
+		return nil, textOutput{}, fmt.Errorf("failed to get working directory: %w", err)
 	}
 
-	yml := generateProjectYAML(cfg)
-	if err := os.WriteFile(filepath.Join(workDir, "project.yml"), []byte(yml), 0o644); err != nil {
-		return fmt.Errorf("failed to write project.yml: %w", err)
+	if !projectconfig.GeneratesSpec(workDir) {
+		err := projectconfig.EditSpec(workDir, func(spec *xcodegen.Spec) error {
+			var products []string
+			for _, targetName := range spec.Targets.Keys() {
+				target, _ := spec.Targets.Get(targetName)
+				for _, dep := range target.Dependencies {
+					if dep.Package == input.Name {
+						products = append(products, cmp.Or(dep.Product, dep.Package))
+					}
+				}
+			}
+			if err := checkNotImported(workDir, input.Name, products); err != nil {
+				return err
+			}
+			if !spec.RemovePackage(input.Name) {
+				return fmt.Errorf("package %s not found in project.yml", input.Name)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, textOutput{}, err
+		}
+		return nil, textOutput{Message: fmt.Sprintf("Removed SPM package %s. project.yml edited and xcodegen regenerated.", input.Name)}, nil
 	}
 
-	if err := runXcodeGen(workDir); err != nil {
-		return err
+	cfg, err := projectconfig.Load(workDir)
+	if err != nil {
+		return nil, textOutput{}, err
+	}
+
+	index := slices.IndexFunc(cfg.Packages, func(p projectconfig.PackageDep) bool { return p.Name == input.Name })
+	if index < 0 {
+		return nil, textOutput{}, fmt.Errorf("package %s not found", input.Name)
+	}
+	products := cfg.Packages[index].Products
+	if len(products) == 0 {
+		products = []string{input.Name}
+	}
+	if err := checkNotImported(workDir, input.Name, products); err != nil {
+		return nil, textOutput{}, err
+	}
+	cfg.Packages = slices.Delete(cfg.Packages, index, index+1)
+
+	if err := projectconfig.ApplyAndRegenerate(workDir, cfg); err != nil {
+		return nil, textOutput{}, err
+	}
+
+	return nil, textOutput{Message: fmt.Sprintf("Removed SPM package %s. project.yml updated and xcodegen regenerated.", input.Name)}, nil
+}
+
+// updatePackageVersionInput is the input for the update_package_version tool.
+type updatePackageVersionInput struct {
+	Name    string `json:"name" jsonschema:"Package name as passed to add_package e.g. Lottie"`
+	Version string `json:"version" jsonschema:"New minimum version e.g. 4.5.0. The package resolves up to the next major version."`
+}
+
+// semverPattern matches the versions SwiftPM accepts as a minimum version.
+var semverPattern = regexp.MustCompile(`^\d+\.\d+\.\d+(-[0-9A-Za-z.-]+)?$`)
+
+func handleUpdatePackageVersion(ctx context.Context, req *mcp.CallToolRequest, input updatePackageVersionInput) (*mcp.CallToolResult, textOutput, error) {
+	workDir, err := os.Getwd()
+	if err != nil {
+		return nil, textOutput{}, fmt.Errorf("failed to get working directory: %w", err)
+	}
+
+	if !semverPattern.MatchString(input.Version) {
+		return nil, textOutput{}, fmt.Errorf("version %q is not a semantic version like 4.5.0", input.Version)
+	}
+
+	if !projectconfig.GeneratesSpec(workDir) {
+		err := projectconfig.EditSpec(workDir, func(spec *xcodegen.Spec) error {
+			return spec.SetPackageVersion(input.Name, input.Version)
+		})
+		if err != nil {
+			return nil, textOutput{}, err
+		}
+		return nil, textOutput{Message: fmt.Sprintf("Package %s now resolves from %s. project.yml edited and xcodegen regenerated.", input.Name, input.Version)}, nil
+	}
+
+	cfg, err := projectconfig.Load(workDir)
+	if err != nil {
+		return nil, textOutput{}, err
+	}
+
+	index := slices.IndexFunc(cfg.Packages, func(p projectconfig.PackageDep) bool { return p.Name == input.Name })
+	if index < 0 {
+		return nil, textOutput{}, fmt.Errorf("package %s not found", input.Name)
+	}
+	if cfg.Packages[index].Path != "" {
+		return nil, textOutput{}, fmt.Errorf("package %s is a local package and has no version", input.Name)
+	}
+	previous := cfg.Packages[index].MinVersion
+	if exact := cfg.Packages[index].ExactVersion; exact != "" {
+		previous = exact
+	}
+	pinned := cfg.Packages[index].ExactVersion != "" || cfg.Packages[index].MaxVersion != "" || cfg.Packages[index].Resolved != ""
+	if previous == input.Version && !pinned {
+		return nil, textOutput{Message: fmt.Sprintf("Package %s already resolves from %s", input.Name, input.Version)}, nil
+	}
+	cfg.Packages[index].MinVersion = input.Version
+	cfg.Packages[index].ExactVersion = ""
+	cfg.Packages[index].MaxVersion = ""
+	cfg.Packages[index].Resolved = ""
+
+	if err := projectconfig.ApplyAndRegenerate(workDir, cfg); err != nil {
+		return nil, textOutput{}, err
+	}
+	// Drop the old pin only once the new range is saved, so a rejected update
+	// leaves the lock as it was.
+	if err := projectconfig.UnpinResolved(workDir, cfg.AppName, map[string]bool{projectconfig.NormalizePackageURL(cfg.Packages[index].URL): true}); err != nil {
+		return nil, textOutput{}, err
+	}
+
+	return nil, textOutput{Message: fmt.Sprintf("Package %s updated from %s to %s. project.yml updated and xcodegen regenerated. Rebuild to resolve the new version.", input.Name, previous, input.Version)}, nil
+}
+
+// removeLocalizationInput is the input for the remove_localization tool.
+type removeLocalizationInput struct {
+	Languages []string `json:"languages" jsonschema:"Language codes to remove e.g. [fr de]. English (en) cannot be removed."`
+}
+
+func handleRemoveLocalization(ctx context.Context, req *mcp.CallToolRequest, input removeLocalizationInput) (*mcp.CallToolResult, textOutput, error) {
+	workDir, err := os.Getwd()
+	if err != nil {
+		return nil, textOutput{}, fmt.Errorf("failed to get working directory: %w", err)
+	}
+
+	if slices.Contains(input.Languages, "en") {
+		return nil, textOutput{}, fmt.Errorf("en is the development language and cannot be removed")
+	}
+
+	if !projectconfig.GeneratesSpec(workDir) {
+		var regions []string
+		err := projectconfig.EditSpec(workDir, func(spec *xcodegen.Spec) error {
+			spec.RemoveKnownRegions(input.Languages...)
+			regions = spec.Options.KnownRegions
+			return nil
+		})
+		if err != nil {
+			return nil, textOutput{}, err
+		}
+		return nil, textOutput{Message: fmt.Sprintf("Localization set to %s. Updated knownRegions in project.yml and regenerated. The .lproj directories were left on disk.", strings.Join(regions, ", "))}, nil
+	}
+
+	cfg, err := projectconfig.Load(workDir)
+	if err != nil {
+		return nil, textOutput{}, err
+	}
+
+	for _, lang := range input.Languages {
+		if !slices.Contains(cfg.Localizations, lang) {
+			return nil, textOutput{}, fmt.Errorf("language %s is not configured (configured: %s)", lang, strings.Join(cfg.Localizations, ", "))
+		}
+	}
+	cfg.Localizations = slices.DeleteFunc(cfg.Localizations, func(lang string) bool {
+		return slices.Contains(input.Languages, lang)
+	})
+
+	if err := projectconfig.ApplyAndRegenerate(workDir, cfg); err != nil {
+		return nil, textOutput{}, err
+	}
+
+	return nil, textOutput{Message: fmt.Sprintf("Localization set to %s. Updated knownRegions in project.yml and regenerated. Delete the removed .lproj directories and their strings if they are no longer needed.", strings.Join(cfg.Localizations, ", "))}, nil
+}
+
+// unsetBuildSettingInput is the input for the unset_build_setting tool.
+type unsetBuildSettingInput struct {
+	Target string `json:"target" jsonschema:"Target name. Empty or omitted means the main app target."`
+	Key    string `json:"key" jsonschema:"Build setting key to remove e.g. SWIFT_STRICT_CONCURRENCY"`
+}
+
+func handleUnsetBuildSetting(ctx context.Context, req *mcp.CallToolRequest, input unsetBuildSettingInput) (*mcp.CallToolResult, textOutput, error) {
+	workDir, err := os.Getwd()
+	if err != nil {
+		return nil, textOutput{}, fmt.Errorf("failed to get working directory: %w", err)
+	}
+
+	if !projectconfig.GeneratesSpec(workDir) {
+		err := projectconfig.EditSpec(workDir, func(spec *xcodegen.Spec) error {
+			removed, err := spec.UnsetBuildSetting(input.Target, input.Key)
+			if err == nil && !removed {
+				err = errNotFound
+			}
+			return err
+		})
+		if err == errNotFound {
+			return nil, textOutput{Message: fmt.Sprintf("%s is not set on that target", input.Key)}, nil
+		}
+		if err != nil {
+			return nil, textOutput{}, err
+		}
+		return nil, textOutput{Message: fmt.Sprintf("Unset %s. project.yml edited and xcodegen regenerated.", input.Key)}, nil
+	}
+
+	cfg, err := projectconfig.Load(workDir)
+	if err != nil {
+		return nil, textOutput{}, err
+	}
+
+	var settings map[string]string
+	if input.Target == "" || input.Target == cfg.AppName {
+		settings = cfg.BuildSettings
+	} else {
+		index := slices.IndexFunc(cfg.Extensions, func(e projectconfig.ExtensionPlan) bool {
+			return projectconfig.ExtensionTargetName(e, cfg.AppName) == input.Target
+		})
+		if index < 0 {
+			return nil, textOutput{}, fmt.Errorf("target %s not found", input.Target)
+		}
+		settings = cfg.Extensions[index].Settings
+	}
+	if _, ok := settings[input.Key]; !ok {
+		return nil, textOutput{Message: fmt.Sprintf("%s is not set on that target (only settings added with set_build_setting can be unset)", input.Key)}, nil
+	}
+	delete(settings, input.Key)
+
+	if err := projectconfig.ApplyAndRegenerate(workDir, cfg); err != nil {
+		return nil, textOutput{}, err
 	}
 
+	return nil, textOutput{Message: fmt.Sprintf("Unset %s. project.yml updated and xcodegen regenerated.", input.Key)}, nil
+}
+
+// errNotFound aborts an in-place project.yml edit that has nothing to remove,
+// so the unchanged file isn't rewritten.
+var errNotFound = errors.New("not found")
+
+// checkNotImported fails when Swift sources still import one of a package's
+// products, since removing the package would break the build.
+func checkNotImported(workDir, pkg string, products []string) error {
+	var importers []string
+	err := filepath.WalkDir(workDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.IsDir() {
+			name := d.Name()
+			if path != workDir && (strings.HasPrefix(name, ".") || name == "build" || name == "DerivedData" || strings.HasSuffix(name, ".xcodeproj")) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if filepath.Ext(path) != ".swift" {
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil
+		}
+		if importsAny(string(data), products) {
+			rel, _ := filepath.Rel(workDir, path)
+			importers = append(importers, rel)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if len(importers) > 0 {
+		return fmt.Errorf("package %s is still imported by %s; remove those imports first", pkg, strings.Join(importers, ", "))
+	}
 	return nil
 }
 
-// runXcodeGen runs `xcodegen generate` in the given directory.
-func runXcodeGen(workDir string) error {
-	cmd := exec.Command("xcodegen", "generate")
-	cmd.Dir = workDir
-	output, err := cmd.CombinedOutput()
+// importsAny reports whether Swift source imports one of the modules.
+func importsAny(source string, modules []string) bool {
+	for _, line := range strings.Split(source, "\n") {
+		fields := strings.Fields(line)
+		for len(fields) > 0 && strings.HasPrefix(fields[0], "@") {
+			fields = fields[1:] // @testable, @_exported, @preconcurrency
+		}
+		if len(fields) < 2 || fields[0] != "import" {
+			continue
+		}
+		module := fields[len(fields)-1] // import struct Lottie.LottieView
+		if i := strings.Index(module, "."); i >= 0 {
+			module = module[:i]
+		}
+		if slices.Contains(modules, module) {
+			return true
+		}
+	}
+	return false
+}
+
+// addConfigurationInput is the input for the add_configuration tool.
+type addConfigurationInput struct {
+	Name           string `json:"name" jsonschema:"Configuration name e.g. Staging or Beta"`
+	Type           string `json:"type" jsonschema:"debug or release. Defaults to release (optimized, like App Store builds)."`
+	BundleIDSuffix string `json:"bundle_id_suffix" jsonschema:"Appended to every bundle ID so the build installs side by side e.g. .staging. Defaults to the lowercased name."`
+	DisplayName    string `json:"display_name" jsonschema:"Home screen name. Defaults to the app name followed by the configuration name."`
+	AppIcon        string `json:"app_icon" jsonschema:"App icon set in the asset catalog e.g. AppIcon-Staging. Defaults to AppIcon."`
+	BackendURL     string `json:"backend_url" jsonschema:"Backend base URL for this configuration e.g. https://staging.api.example.com"`
+}
+
+func handleAddConfiguration(ctx context.Context, req *mcp.CallToolRequest, input addConfigurationInput) (*mcp.CallToolResult, textOutput, error) {
+	workDir, err := os.Getwd()
+	if err != nil {
+		return nil, textOutput{}, fmt.Errorf("failed to get working directory: %w", err)
+	}
+
+	if xcodegen.IsDefaultConfiguration(input.Name) {
+		return nil, textOutput{}, fmt.Errorf("%s always exists; use set_configuration_value to change its values", input.Name)
+	}
+
+	if !projectconfig.GeneratesSpec(workDir) {
+		var scheme string
+		err := projectconfig.EditSpec(workDir, func(spec *xcodegen.Spec) error {
+			if err := spec.AddConfiguration(input.Name, input.Type); err != nil {
+				return err
+			}
+			scheme = spec.MainTargetName() + " " + input.Name
+			if input.BundleIDSuffix == "" && input.DisplayName == "" && input.AppIcon == "" && input.BackendURL == "" {
+				return nil
+			}
+			return setSpecConfigValues(spec, input.Name, map[string]string{
+				"bundle_id_suffix": input.BundleIDSuffix,
+				"display_name":     input.DisplayName,
+				"app_icon":         input.AppIcon,
+				"backend_url":      input.BackendURL,
+			})
+		})
+		if err != nil {
+			return nil, textOutput{}, err
+		}
+		msg := fmt.Sprintf("Added configuration %s with scheme %q. project.yml edited and xcodegen regenerated.", input.Name, scheme)
+		if input.BackendURL != "" {
+			msg += fmt.Sprintf(" Read the backend URL in Swift with Bundle.main.object(forInfoDictionaryKey: %q).", xcodegen.BackendURLInfoKey)
+		}
+		return nil, textOutput{Message: msg}, nil
+	}
+
+	cfg, err := projectconfig.Load(workDir)
+	if err != nil {
+		return nil, textOutput{}, err
+	}
+
+	for _, c := range cfg.Configurations {
+		if c.Name == input.Name {
+			return nil, textOutput{Message: fmt.Sprintf("Configuration %s already exists", input.Name)}, nil
+		}
+	}
+	cfg.Configurations = append(cfg.Configurations, projectconfig.BuildConfiguration{
+		Name:           input.Name,
+		Type:           input.Type,
+		BundleIDSuffix: input.BundleIDSuffix,
+		DisplayName:    input.DisplayName,
+		AppIcon:        input.AppIcon,
+		BackendURL:     input.BackendURL,
+	})
+
+	if err := projectconfig.ApplyAndRegenerate(workDir, cfg); err != nil {
+		return nil, textOutput{}, err
+	}
+
+	return nil, textOutput{Message: fmt.Sprintf("Added configuration %s backed by Configs/%s.xcconfig, with scheme \"%s %s\". project.yml updated and xcodegen regenerated. Read the backend URL in Swift with Bundle.main.object(forInfoDictionaryKey: %q).", input.Name, input.Name, cfg.AppName, input.Name, xcodegen.BackendURLInfoKey)}, nil
+}
+
+// setConfigurationValueInput is the input for the set_configuration_value tool.
+type setConfigurationValueInput struct {
+	Configuration string `json:"configuration" jsonschema:"Configuration name e.g. Debug, Staging or Release"`
+	Key           string `json:"key" jsonschema:"bundle_id_suffix, display_name, app_icon, backend_url, or an xcconfig variable in UPPER_SNAKE_CASE e.g. ANALYTICS_KEY"`
+	Value         string `json:"value" jsonschema:"Value for this configuration. Empty clears a custom variable."`
+}
+
+func handleSetConfigurationValue(ctx context.Context, req *mcp.CallToolRequest, input setConfigurationValueInput) (*mcp.CallToolResult, textOutput, error) {
+	workDir, err := os.Getwd()
 	if err != nil {
-		return fmt.Errorf("xcodegen generate failed: %w\n%s", err, string(output))
+		return nil, textOutput{}, fmt.Errorf("failed to get working directory: %w", err)
+	}
+
+	if !projectconfig.GeneratesSpec(workDir) {
+		err := projectconfig.EditSpec(workDir, func(spec *xcodegen.Spec) error {
+			return setSpecConfigValues(spec, input.Configuration, map[string]string{input.Key: input.Value})
+		})
+		if err != nil {
+			return nil, textOutput{}, err
+		}
+		return nil, textOutput{Message: fmt.Sprintf("Set %s = %s for %s. project.yml edited and xcodegen regenerated.", input.Key, input.Value, input.Configuration)}, nil
+	}
+
+	cfg, err := projectconfig.Load(workDir)
+	if err != nil {
+		return nil, textOutput{}, err
+	}
+
+	index := slices.IndexFunc(cfg.Configurations, func(c projectconfig.BuildConfiguration) bool { return c.Name == input.Configuration })
+	if index < 0 {
+		if !xcodegen.IsDefaultConfiguration(input.Configuration) {
+			return nil, textOutput{}, fmt.Errorf("configuration %s not found; add it with add_configuration", input.Configuration)
+		}
+		cfg.Configurations = append(cfg.Configurations, projectconfig.BuildConfiguration{Name: input.Configuration})
+		index = len(cfg.Configurations) - 1
+	}
+	c := &cfg.Configurations[index]
+	switch input.Key {
+	case "bundle_id_suffix":
+		c.BundleIDSuffix = input.Value
+	case "display_name":
+		c.DisplayName = input.Value
+	case "app_icon":
+		c.AppIcon = input.Value
+	case "backend_url":
+		c.BackendURL = input.Value
+	default:
+		if input.Value == "" {
+			delete(c.Values, input.Key)
+			break
+		}
+		if c.Values == nil {
+			c.Values = make(map[string]string)
+		}
+		c.Values[input.Key] = input.Value
+	}
+
+	if err := projectconfig.ApplyAndRegenerate(workDir, cfg); err != nil {
+		return nil, textOutput{}, err
+	}
+
+	return nil, textOutput{Message: fmt.Sprintf("Set %s = %s in Configs/%s.xcconfig. project.yml updated and xcodegen regenerated.", input.Key, input.Value, input.Configuration)}, nil
+}
+
+// setSpecConfigValues applies configuration values to a project.yml edited in
+// place, as per-configuration build settings. The bundle ID suffix applies to
+// every target; the other values to the main target. Empty values are skipped.
+func setSpecConfigValues(spec *xcodegen.Spec, config string, values map[string]string) error {
+	for _, key := range slices.Sorted(maps.Keys(values)) {
+		value := values[key]
+		if value == "" {
+			continue
+		}
+		settings := map[string]any{}
+		switch key {
+		case "bundle_id_suffix":
+			if err := spec.SetConfigBundleIDSuffix(config, value); err != nil {
+				return err
+			}
+		case "display_name":
+			settings["INFOPLIST_KEY_CFBundleDisplayName"] = value
+		case "app_icon":
+			settings["ASSETCATALOG_COMPILER_APPICON_NAME"] = value
+			settings["INFOPLIST_KEY_CFBundleIconName"] = value
+		case "backend_url":
+			if err := spec.SetConfigBackendURL(config, value); err != nil {
+				return err
+			}
+		default:
+			settings[key] = value
+		}
+		for _, k := range slices.Sorted(maps.Keys(settings)) {
+			if err := spec.SetConfigSetting(config, "", k, settings[k]); err != nil {
+				return err
+			}
+		}
 	}
 	return nil
 }
@@ -0,0 +1,339 @@
+package xcodegenserver
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/moasq/nanowave/internal/packageregistry"
+	"github.com/moasq/nanowave/internal/projectconfig"
+	"github.com/moasq/nanowave/internal/xcodegen"
+)
+
+func TestCheckNotImported(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"Notes/ContentView.swift":       "import SwiftUI\n@preconcurrency import NukeUI\n",
+		"Notes/Model.swift":             "import Foundation\n// import Lottie\n",
+		".build/checkouts/Lottie.swift": "import Lottie\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	err := checkNotImported(dir, "Nuke", []string{"Nuke", "NukeUI"})
+	if err == nil || !strings.Contains(err.Error(), "Notes/ContentView.swift") {
+		t.Errorf("checkNotImported(Nuke) = %v, want ContentView.swift listed", err)
+	}
+	if err := checkNotImported(dir, "Lottie", []string{"Lottie"}); err != nil {
+		t.Errorf("checkNotImported(Lottie) = %v; comments and hidden directories should be ignored", err)
+	}
+}
+
+func TestSetSpecConfigValues(t *testing.T) {
+	spec, err := xcodegen.Parse([]byte(`name: Notes
+targets:
+  Notes:
+    type: application
+    platform: iOS
+    sources: [Notes]
+    settings:
+      base:
+        PRODUCT_BUNDLE_IDENTIFIER: com.example.notes
+  NotesWidget:
+    type: app-extension
+    platform: iOS
+    sources: [Targets/NotesWidget]
+    settings:
+      base:
+        PRODUCT_BUNDLE_IDENTIFIER: com.example.notes.widget
+  Helper:
+    type: framework
+    platform: iOS
+    settings:
+      base:
+        PRODUCT_BUNDLE_IDENTIFIER: com.example.helper
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := spec.AddConfiguration("Staging", "release"); err != nil {
+		t.Fatal(err)
+	}
+	err = setSpecConfigValues(spec, "Staging", map[string]string{
+		"bundle_id_suffix": ".staging",
+		"backend_url":      "https://staging.example.com",
+	})
+	if err != nil {
+		t.Fatalf("setSpecConfigValues() error: %v", err)
+	}
+
+	setting := func(target, key string) any {
+		tgt, _ := spec.Targets.Get(target)
+		if tgt.Settings == nil {
+			return nil
+		}
+		values := tgt.Settings.Configs["Staging"]
+		v, _ := values.Get(key)
+		return v
+	}
+	for target, want := range map[string]any{
+		"Notes":       "com.example.notes.staging",
+		"NotesWidget": "com.example.notes.staging.widget",
+		"Helper":      nil,
+	} {
+		if got := setting(target, "PRODUCT_BUNDLE_IDENTIFIER"); got != want {
+			t.Errorf("%s Staging PRODUCT_BUNDLE_IDENTIFIER = %v, want %v", target, got, want)
+		}
+	}
+	if got := setting("Notes", "API_BASE_URL"); got != "https://staging.example.com" {
+		t.Errorf("Notes Staging API_BASE_URL = %v", got)
+	}
+	main, _ := spec.Targets.Get("Notes")
+	if main.Info == nil || main.Info.Properties[xcodegen.BackendURLInfoKey] != "$(API_BASE_URL)" {
+		t.Errorf("Notes Info.plist = %+v, want %s = $(API_BASE_URL)", main.Info, xcodegen.BackendURLInfoKey)
+	}
+}
+
+func TestAddPackageChecksRegistry(t *testing.T) {
+	packages, err := packageregistry.Default()
+	if err != nil {
+		t.Fatal(err)
+	}
+	kingfisher := packages.Lookup("kingfisher")
+	addPackage := addPackageHandler(packages)
+	dir := fakeXcodeGen(t)
+	if err := projectconfig.Save(dir, &projectconfig.Config{AppName: "Notes", BundleID: "com.example.notes", Platform: "ios"}); err != nil {
+		t.Fatal(err)
+	}
+
+	rejected := []struct {
+		name    string
+		input   addPackageInput
+		wantErr string
+	}{
+		{"below the registry", addPackageInput{Name: "Kingfisher", URL: kingfisher.RepoURL + ".git", MinVersion: "7.0.0"}, "outside"},
+		{"past the registry", addPackageInput{Name: "Kingfisher", URL: kingfisher.RepoURL, MinVersion: kingfisher.MinVersion, MaxVersion: "99.0.0"}, "outside"},
+		{"exact with a range", addPackageInput{Name: "Custom", URL: "https://github.com/example/custom", MinVersion: "1.0.0", ExactVersion: "1.2.0"}, "cannot be combined"},
+		{"no version", addPackageInput{Name: "Custom", URL: "https://github.com/example/custom"}, "min_version or exact_version is required"},
+	}
+	for _, tc := range rejected {
+		if _, _, err := addPackage(context.Background(), nil, tc.input); err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+			t.Errorf("%s: add_package error = %v, want %q", tc.name, err, tc.wantErr)
+		}
+	}
+
+	if _, _, err := addPackage(context.Background(), nil, addPackageInput{Name: "Kingfisher", URL: kingfisher.RepoURL}); err != nil {
+		t.Fatalf("add_package(Kingfisher) error: %v", err)
+	}
+	if _, _, err := addPackage(context.Background(), nil, addPackageInput{Name: "Custom", URL: "https://github.com/example/custom", MinVersion: "1.0.0", MaxVersion: "1.5.0"}); err != nil {
+		t.Fatalf("add_package(Custom) error: %v", err)
+	}
+	cfg, err := projectconfig.Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []projectconfig.PackageDep{
+		{Name: "Kingfisher", URL: kingfisher.RepoURL, MinVersion: kingfisher.MinVersion},
+		{Name: "Custom", URL: "https://github.com/example/custom", MinVersion: "1.0.0", MaxVersion: "1.5.0"},
+	}
+	if len(cfg.Packages) != len(want) {
+		t.Fatalf("packages = %+v, want %+v", cfg.Packages, want)
+	}
+	for i := range want {
+		if got := cfg.Packages[i]; got.Name != want[i].Name || got.URL != want[i].URL || got.MinVersion != want[i].MinVersion || got.ExactVersion != want[i].ExactVersion || got.MaxVersion != want[i].MaxVersion {
+			t.Errorf("package %d = %+v, want %+v", i, got, want[i])
+		}
+	}
+}
+
+// fakeXcodeGen changes into a new project directory and puts an xcodegen that
+// does nothing first on PATH, so handlers can regenerate the project.
+func fakeXcodeGen(t *testing.T) string {
+	t.Helper()
+	bin := t.TempDir()
+	if err := os.WriteFile(filepath.Join(bin, "xcodegen"), []byte("#!/bin/sh\nexit 0\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+	dir := t.TempDir()
+	t.Chdir(dir)
+	return dir
+}
+
+func TestRemoveToolsEditConfig(t *testing.T) {
+	dir := fakeXcodeGen(t)
+	ctx := context.Background()
+	widget := projectconfig.ExtensionPlan{Kind: "widget", Name: "Widget", Purpose: "Today's notes"}
+	widgetTarget := projectconfig.ExtensionTargetName(widget, "Notes")
+	err := projectconfig.Save(dir, &projectconfig.Config{
+		AppName:     "Notes",
+		BundleID:    "com.example.notes",
+		Platform:    "ios",
+		Permissions: []projectconfig.Permission{{Key: "NSCameraUsageDescription", Description: "Scan notes", Framework: "AVFoundation"}},
+		Extensions:  []projectconfig.ExtensionPlan{widget},
+		Entitlements: []projectconfig.Entitlement{
+			{Key: "com.apple.developer.healthkit", Value: true},
+			{Key: "com.apple.developer.siri", Value: true, Target: widgetTarget},
+		},
+		Packages:      []projectconfig.PackageDep{{Name: "Lottie", URL: "https://github.com/airbnb/lottie-ios", MinVersion: "4.0.0"}},
+		Localizations: []string{"en", "fr"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	load := func() *projectconfig.Config {
+		t.Helper()
+		cfg, err := projectconfig.Load(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return cfg
+	}
+
+	if _, _, err := handleRemoveEntitlement(ctx, nil, removeEntitlementInput{Key: "com.apple.security.application-groups"}); err == nil || !strings.Contains(err.Error(), widgetTarget) {
+		t.Errorf("removing the app group the widget shares = %v, want an error naming %s", err, widgetTarget)
+	}
+	if _, _, err := handleRemoveEntitlement(ctx, nil, removeEntitlementInput{Target: widgetTarget, Key: "com.apple.security.application-groups"}); err == nil || !strings.Contains(err.Error(), "required by every widget extension") {
+		t.Errorf("removing the widget's app group = %v, want a default entitlement error", err)
+	}
+	if _, out, err := handleRemoveEntitlement(ctx, nil, removeEntitlementInput{Key: "com.apple.developer.icloud-services"}); err != nil || !strings.Contains(out.Message, "is not set") {
+		t.Errorf("removing an undeclared entitlement = %q, %v", out.Message, err)
+	}
+	if _, _, err := handleRemoveEntitlement(ctx, nil, removeEntitlementInput{Key: "com.apple.developer.healthkit"}); err != nil {
+		t.Fatalf("remove_entitlement error: %v", err)
+	}
+	if ents := load().Entitlements; len(ents) != 1 || ents[0].Key != "com.apple.developer.siri" {
+		t.Errorf("entitlements = %+v, want only the widget's siri entitlement", ents)
+	}
+
+	if _, _, err := handleRemovePermission(ctx, nil, removePermissionInput{Key: "NSCameraUsageDescription"}); err != nil {
+		t.Fatalf("remove_permission error: %v", err)
+	}
+	if perms := load().Permissions; len(perms) != 0 {
+		t.Errorf("permissions = %+v, want none", perms)
+	}
+
+	source := filepath.Join(dir, "Notes", "Splash.swift")
+	if err := os.MkdirAll(filepath.Dir(source), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(source, []byte("import Lottie\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := handleRemovePackage(ctx, nil, removePackageInput{Name: "Lottie"}); err == nil || !strings.Contains(err.Error(), "Splash.swift") {
+		t.Errorf("removing an imported package = %v, want Splash.swift listed", err)
+	}
+	if err := os.Remove(source); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := handleRemovePackage(ctx, nil, removePackageInput{Name: "Lottie"}); err != nil {
+		t.Fatalf("remove_package error: %v", err)
+	}
+	if pkgs := load().Packages; len(pkgs) != 0 {
+		t.Errorf("packages = %+v, want none", pkgs)
+	}
+
+	if _, _, err := handleRemoveLocalization(ctx, nil, removeLocalizationInput{Languages: []string{"en"}}); err == nil {
+		t.Error("removing en should fail")
+	}
+	if _, _, err := handleRemoveLocalization(ctx, nil, removeLocalizationInput{Languages: []string{"fr"}}); err != nil {
+		t.Fatalf("remove_localization error: %v", err)
+	}
+	if langs := load().Localizations; len(langs) != 1 || langs[0] != "en" {
+		t.Errorf("localizations = %v, want [en]", langs)
+	}
+
+	if _, _, err := handleRemoveExtension(ctx, nil, removeExtensionInput{Name: widgetTarget}); err != nil {
+		t.Fatalf("remove_extension error: %v", err)
+	}
+	if cfg := load(); len(cfg.Extensions) != 0 || len(cfg.Entitlements) != 0 {
+		t.Errorf("extensions = %+v, entitlements = %+v, want both gone", cfg.Extensions, cfg.Entitlements)
+	}
+	if _, _, err := handleRemoveEntitlement(ctx, nil, removeEntitlementInput{Key: "com.apple.security.application-groups"}); err != nil {
+		t.Errorf("the app group should be removable once the widget is gone: %v", err)
+	}
+}
+
+func TestRemoveEntitlementAdoptedProject(t *testing.T) {
+	dir := fakeXcodeGen(t)
+	ctx := context.Background()
+	spec := `name: Notes
+targets:
+  Notes:
+    type: application
+    platform: iOS
+    settings:
+      base:
+        PRODUCT_BUNDLE_IDENTIFIER: com.example.notes
+    entitlements:
+      path: Notes/Notes.entitlements
+      properties:
+        com.apple.developer.healthkit: true
+        com.apple.security.application-groups:
+          - group.com.example.notes
+  NotesWidget:
+    type: app-extension
+    platform: iOS
+    entitlements:
+      path: NotesWidget/NotesWidget.entitlements
+      properties:
+        com.apple.security.application-groups:
+          - group.com.example.notes
+  NotesClip:
+    type: application.on-demand-install-capable
+    platform: iOS
+    entitlements:
+      path: NotesClip/NotesClip.entitlements
+      properties:
+        com.apple.developer.parent-application-identifiers:
+          - $(AppIdentifierPrefix)com.example.notes
+`
+	path := filepath.Join(dir, "project.yml")
+	if err := os.WriteFile(path, []byte(spec), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	rejected := []struct {
+		input   removeEntitlementInput
+		wantErr string
+	}{
+		{removeEntitlementInput{Key: "com.apple.security.application-groups"}, "required by target NotesWidget"},
+		{removeEntitlementInput{Target: "NotesWidget", Key: "com.apple.security.application-groups"}, "shares data with Notes"},
+		{removeEntitlementInput{Target: "NotesClip", Key: "com.apple.developer.parent-application-identifiers"}, "required by every app_clip extension"},
+	}
+	for _, tc := range rejected {
+		if _, _, err := handleRemoveEntitlement(ctx, nil, tc.input); err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+			t.Errorf("remove_entitlement(%+v) = %v, want %q", tc.input, err, tc.wantErr)
+		}
+	}
+	if data, err := os.ReadFile(path); err != nil || string(data) != spec {
+		t.Errorf("rejected removals should leave project.yml untouched, got:\n%s", data)
+	}
+
+	if _, _, err := handleRemoveEntitlement(ctx, nil, removeEntitlementInput{Key: "com.apple.developer.healthkit"}); err != nil {
+		t.Fatalf("remove_entitlement error: %v", err)
+	}
+	edited, err := xcodegen.Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, notes, err := edited.Target("Notes")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := notes.Entitlements.Properties["com.apple.developer.healthkit"]; ok {
+		t.Error("healthkit entitlement should be removed")
+	}
+	if _, ok := notes.Entitlements.Properties["com.apple.security.application-groups"]; !ok {
+		t.Error("the shared app group should be kept")
+	}
+}